@@ -0,0 +1,436 @@
+//go:build integration
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"taxifleet/backend/internal/cache"
+	"taxifleet/backend/internal/config"
+	"taxifleet/backend/internal/database"
+	"taxifleet/backend/internal/fuelcard"
+	"taxifleet/backend/internal/mail"
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/ocr"
+	"taxifleet/backend/internal/permissions"
+	"taxifleet/backend/internal/push"
+	"taxifleet/backend/internal/ratelimit"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/service"
+	"taxifleet/backend/internal/storage"
+	"taxifleet/backend/internal/testdb"
+	"taxifleet/backend/internal/tokens"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// The tenant isolation suite drives the API over HTTP with the tokens of two
+// tenants' owners. Postgres runs in a container and the API connects as a
+// regular role with DB_ROW_LEVEL_SECURITY, as in production:
+//
+//	go test -tags integration ./cmd/api/
+//
+// It needs Docker.
+
+const isolationPassword = "isolation-password"
+
+var testPostgres *testdb.Postgres
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	pg, err := testdb.Start(ctx, "file://../../migrations")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	testPostgres = pg
+
+	code := m.Run()
+	if err := pg.Terminate(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	os.Exit(code)
+}
+
+// testAPI is the router of newApp served over HTTP
+type testAPI struct {
+	t    *testing.T
+	url  string
+	repo *repository.Repository
+}
+
+func startAPI(t *testing.T) *testAPI {
+	t.Helper()
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("failed to load the configuration: %v", err)
+	}
+	cfg.Database = testPostgres.Config()
+	cfg.Database.RowLevelSecurity = true
+	cfg.Security.RateLimitRPS = 0
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	db, err := database.New(&cfg.Database, logger)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	repo := repository.New(db.GetDB())
+
+	systemSettingService := service.NewSystemSettingService(repo, cfg, logger)
+	if err := systemSettingService.Load(context.Background()); err != nil {
+		t.Fatalf("failed to load system settings: %v", err)
+	}
+	jwtKeys, err := tokens.Load(cfg.JWT)
+	if err != nil {
+		t.Fatalf("failed to load JWT signing keys: %v", err)
+	}
+	router, _, err := newApp(repo, systemSettingService, backends{
+		push:        push.NoopSender{},
+		mail:        mail.NoopSender{},
+		ocr:         ocr.DisabledProvider{},
+		fuelCards:   fuelcard.DisabledProvider{},
+		cache:       cache.Noop{},
+		jwtKeys:     jwtKeys,
+		sessions:    repo,
+		rateCounter: ratelimit.NewMemory(),
+		files:       storage.NewLocal(t.TempDir()),
+	}, cfg, logger)
+	if err != nil {
+		t.Fatalf("failed to set up the application: %v", err)
+	}
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return &testAPI{t: t, url: server.URL, repo: repo}
+}
+
+// apiClient calls the API with a user's token
+type apiClient struct {
+	t     *testing.T
+	url   string
+	token string
+}
+
+func (c *apiClient) do(method, path string, body any) (int, []byte) {
+	c.t.Helper()
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			c.t.Fatalf("failed to encode the body of %s %s: %v", method, path, err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, c.url+path, reader)
+	if err != nil {
+		c.t.Fatalf("failed to build %s %s: %v", method, path, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		c.t.Fatalf("%s %s failed: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.t.Fatalf("failed to read the response of %s %s: %v", method, path, err)
+	}
+	return resp.StatusCode, data
+}
+
+// create posts the record and returns its ID
+func (c *apiClient) create(path string, body any) uint {
+	c.t.Helper()
+	code, data := c.do(http.MethodPost, path, body)
+	if code != http.StatusCreated {
+		c.t.Fatalf("POST %s: expected 201, got %d: %s", path, code, data)
+	}
+	var created struct {
+		ID uint `json:"id"`
+	}
+	if err := json.Unmarshal(data, &created); err != nil || created.ID == 0 {
+		c.t.Fatalf("POST %s: unexpected response %s", path, data)
+	}
+	return created.ID
+}
+
+// get decodes the response of a GET that must succeed into out
+func (c *apiClient) get(path string, out any) {
+	c.t.Helper()
+	code, data := c.do(http.MethodGet, path, nil)
+	if code != http.StatusOK {
+		c.t.Fatalf("GET %s: expected 200, got %d: %s", path, code, data)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		c.t.Fatalf("GET %s: unexpected response %s", path, data)
+	}
+}
+
+// isolationTenant is a tenant whose owner created a taxi, report, expense
+// and deposit over the API. Their text carries the tenant's marker, so a
+// record leaking into another tenant's response shows in its body.
+type isolationTenant struct {
+	marker  string
+	owner   *repository.User
+	client  *apiClient
+	taxi    uint
+	report  uint
+	expense uint
+	deposit uint
+}
+
+func (api *testAPI) createTenant(marker string) *isolationTenant {
+	t := api.t
+	t.Helper()
+	ctx := context.Background()
+	unique := time.Now().UnixNano()
+	name := strings.ToLower(marker)
+
+	tenant := &repository.Tenant{Name: marker, Subdomain: fmt.Sprintf("%s-%d", name, unique), Settings: "{}"}
+	if err := api.repo.CreateTenant(ctx, tenant); err != nil {
+		t.Fatalf("failed to create tenant: %v", err)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(isolationPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("failed to hash the password: %v", err)
+	}
+	owner := &repository.User{
+		TenantID:     tenant.ID,
+		Email:        fmt.Sprintf("%s-%d@example.com", name, unique),
+		PasswordHash: string(hash),
+		Permission:   permissions.PermissionOwner,
+		FirstName:    "Owner",
+		LastName:     marker,
+		Phone:        fmt.Sprintf("+1%d", unique%1_000_000_000_000),
+		Active:       true,
+	}
+	if err := api.repo.CreateUser(ctx, owner); err != nil {
+		t.Fatalf("failed to create owner: %v", err)
+	}
+
+	client := api.login(owner.Email)
+	taxi := client.create("/api/v1/taxis", service.CreateTaxiRequest{LicensePlate: marker + "-1", Model: "Corolla"})
+	report := client.create("/api/v1/reports", service.CreateReportRequest{TaxiID: taxi, WeekStartDate: time.Now(), Earnings: 1000_00, Notes: marker + " report"})
+	expense := client.create("/api/v1/expenses", service.CreateExpenseRequest{ReportID: &report, Category: "fuel", Amount: 50_00, Reason: marker + " fuel", Date: "2024-05-13"})
+	deposit := client.create("/api/v1/deposits", service.CreateDepositRequest{Amount: 900_00, DepositDate: "2024-05-20", PeriodStart: "2024-05-13", PeriodEnd: "2024-05-19", TaxiID: &taxi, Notes: marker + " deposit"})
+
+	return &isolationTenant{marker: marker, owner: owner, client: client, taxi: taxi, report: report, expense: expense, deposit: deposit}
+}
+
+// login signs the user in with their password
+func (api *testAPI) login(email string) *apiClient {
+	api.t.Helper()
+	client := &apiClient{t: api.t, url: api.url}
+	code, data := client.do(http.MethodPost, "/api/v1/auth/login", service.LoginRequest{EmailOrPhone: email, Password: isolationPassword})
+	var auth service.AuthResponse
+	if code != http.StatusOK || json.Unmarshal(data, &auth) != nil {
+		api.t.Fatalf("failed to log in: %d %s", code, data)
+	}
+	client.token = auth.Token
+	return client
+}
+
+// TestTenantIsolation has the owner of one tenant try every read, change and
+// reference of the other tenant's records by ID
+func TestTenantIsolation(t *testing.T) {
+	api := startAPI(t)
+	victim := api.createTenant("VICTIM")
+	attacker := api.createTenant("ATTACKER")
+
+	model, amount := "Stolen", money.Amount(1_00)
+	attempts := []struct {
+		method string
+		path   string
+		body   any
+	}{
+		{http.MethodGet, fmt.Sprintf("/api/v1/taxis/%d", victim.taxi), nil},
+		{http.MethodPut, fmt.Sprintf("/api/v1/taxis/%d", victim.taxi), service.UpdateTaxiRequest{Model: &model}},
+		{http.MethodDelete, fmt.Sprintf("/api/v1/taxis/%d", victim.taxi), nil},
+		{http.MethodGet, fmt.Sprintf("/api/v1/taxis/%d/assignments", victim.taxi), nil},
+		{http.MethodGet, fmt.Sprintf("/api/v1/taxis/%d/ledger", victim.taxi), nil},
+		{http.MethodPost, "/api/v1/taxis", service.CreateTaxiRequest{LicensePlate: "ATTACKER-2", AssignedDriverID: &victim.owner.ID}},
+		{http.MethodGet, fmt.Sprintf("/api/v2/taxis/%d", victim.taxi), nil},
+		{http.MethodPut, fmt.Sprintf("/api/v2/taxis/%d", victim.taxi), service.UpdateTaxiRequest{Model: &model}},
+		{http.MethodDelete, fmt.Sprintf("/api/v2/taxis/%d", victim.taxi), nil},
+
+		{http.MethodGet, fmt.Sprintf("/api/v1/reports/%d", victim.report), nil},
+		{http.MethodPut, fmt.Sprintf("/api/v1/reports/%d", victim.report), service.UpdateReportRequest{Earnings: &amount}},
+		{http.MethodPost, fmt.Sprintf("/api/v1/reports/%d/submit", victim.report), nil},
+		{http.MethodPost, fmt.Sprintf("/api/v1/reports/%d/approve", victim.report), service.ApproveReportRequest{}},
+		{http.MethodPost, fmt.Sprintf("/api/v1/reports/%d/reject", victim.report), service.RejectReportRequest{Reason: "Stolen"}},
+		{http.MethodPost, fmt.Sprintf("/api/v1/reports/%d/reopen", victim.report), nil},
+		{http.MethodGet, fmt.Sprintf("/api/v1/reports/%d/receipt.pdf", victim.report), nil},
+		{http.MethodGet, fmt.Sprintf("/api/v1/reports/%d/attachments", victim.report), nil},
+		{http.MethodGet, fmt.Sprintf("/api/v1/reports/%d/adjustments", victim.report), nil},
+		{http.MethodDelete, fmt.Sprintf("/api/v1/reports/%d", victim.report), nil},
+		{http.MethodPost, "/api/v1/reports", service.CreateReportRequest{TaxiID: victim.taxi, WeekStartDate: time.Now().AddDate(0, 0, -7), Earnings: 1_00}},
+
+		{http.MethodGet, fmt.Sprintf("/api/v1/expenses/%d", victim.expense), nil},
+		{http.MethodPut, fmt.Sprintf("/api/v1/expenses/%d", victim.expense), service.UpdateExpenseRequest{Amount: &amount}},
+		{http.MethodPost, fmt.Sprintf("/api/v1/expenses/%d/approve", victim.expense), nil},
+		{http.MethodDelete, fmt.Sprintf("/api/v1/expenses/%d", victim.expense), nil},
+		{http.MethodPost, "/api/v1/expenses", service.CreateExpenseRequest{ReportID: &victim.report, Category: "fuel", Amount: 1_00, Date: "2024-05-13"}},
+		{http.MethodPost, "/api/v1/expenses", service.CreateExpenseRequest{TaxiID: &victim.taxi, Category: "fuel", Amount: 1_00, Date: "2024-05-13"}},
+
+		{http.MethodGet, fmt.Sprintf("/api/v1/deposits/%d", victim.deposit), nil},
+		{http.MethodPut, fmt.Sprintf("/api/v1/deposits/%d", victim.deposit), service.UpdateDepositRequest{Amount: 1_00}},
+		{http.MethodPost, fmt.Sprintf("/api/v1/deposits/%d/verify", victim.deposit), nil},
+		{http.MethodDelete, fmt.Sprintf("/api/v1/deposits/%d", victim.deposit), nil},
+		{http.MethodPost, "/api/v1/deposits", service.CreateDepositRequest{Amount: 1_00, DepositDate: "2024-05-20", PeriodStart: "2024-05-13", PeriodEnd: "2024-05-19", TaxiID: &victim.taxi}},
+	}
+	for _, attempt := range attempts {
+		t.Run(attempt.method+" "+attempt.path, func(t *testing.T) {
+			client := *attacker.client
+			client.t = t
+			code, data := client.do(attempt.method, attempt.path, attempt.body)
+			if code < http.StatusBadRequest || code >= http.StatusInternalServerError {
+				t.Fatalf("expected the other tenant's record to be out of reach, got %d: %s", code, data)
+			}
+			if bytes.Contains(data, []byte(victim.marker)) {
+				t.Fatalf("response leaks the other tenant's record: %s", data)
+			}
+		})
+	}
+
+	// Nothing of the victim changed or went away
+	var taxi repository.Taxi
+	victim.client.get(fmt.Sprintf("/api/v1/taxis/%d", victim.taxi), &taxi)
+	if taxi.Model != "Corolla" {
+		t.Errorf("victim's taxi changed: %+v", taxi)
+	}
+	var report repository.WeeklyReport
+	victim.client.get(fmt.Sprintf("/api/v1/reports/%d", victim.report), &report)
+	if report.Status != "draft" || report.Earnings != 1000_00 {
+		t.Errorf("victim's report changed: %+v", report)
+	}
+	var expense repository.Expense
+	victim.client.get(fmt.Sprintf("/api/v1/expenses/%d", victim.expense), &expense)
+	if expense.Amount != 50_00 {
+		t.Errorf("victim's expense changed: %+v", expense)
+	}
+	var deposit repository.BankDeposit
+	victim.client.get(fmt.Sprintf("/api/v1/deposits/%d", victim.deposit), &deposit)
+	if deposit.Amount != 900_00 || deposit.Status != "unverified" {
+		t.Errorf("victim's deposit changed: %+v", deposit)
+	}
+}
+
+// TestTenantIsolationLists checks the lists and the exports only hold the
+// caller's tenant
+func TestTenantIsolationLists(t *testing.T) {
+	api := startAPI(t)
+	victim := api.createTenant("VICTIM")
+	attacker := api.createTenant("ATTACKER")
+
+	for _, path := range []string{
+		"/api/v1/taxis",
+		"/api/v2/taxis",
+		"/api/v1/reports",
+		"/api/v1/expenses",
+		"/api/v1/deposits",
+		"/api/v1/export/reports",
+		"/api/v1/export/expenses",
+		"/api/v1/export/deposits",
+	} {
+		t.Run(path, func(t *testing.T) {
+			client := *attacker.client
+			client.t = t
+			code, data := client.do(http.MethodGet, path, nil)
+			if code != http.StatusOK || !bytes.Contains(data, []byte(attacker.marker)) {
+				t.Fatalf("expected the caller's own records, got %d: %s", code, data)
+			}
+			if bytes.Contains(data, []byte(victim.marker)) {
+				t.Fatalf("response leaks the other tenant's records: %s", data)
+			}
+		})
+	}
+}
+
+// TestTenantIsolationV2Transaction checks the v2 routes run in a transaction
+// scoped to the caller's tenant, so row-level security backs their tenant
+// filters like on v1
+func TestTenantIsolationV2Transaction(t *testing.T) {
+	api := startAPI(t)
+	tenant := api.createTenant("SCOPED")
+
+	db, err := testPostgres.Superuser()
+	if err != nil {
+		t.Fatalf("failed to connect as the superuser: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	// Record the tenant of the transaction of every taxi write
+	_, err = db.Exec(`
+		CREATE TABLE taxi_write_scopes (license_plate TEXT, tenant_id TEXT);
+		CREATE FUNCTION record_taxi_write_scope() RETURNS trigger AS $$
+		BEGIN
+			INSERT INTO taxi_write_scopes VALUES (NEW.license_plate, current_setting('app.tenant_id', true));
+			RETURN NEW;
+		END $$ LANGUAGE plpgsql SECURITY DEFINER;
+		CREATE TRIGGER record_taxi_write_scope AFTER INSERT OR UPDATE ON taxis
+			FOR EACH ROW EXECUTE FUNCTION record_taxi_write_scope()`)
+	if err != nil {
+		t.Fatalf("failed to create the trigger: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Exec(`
+			DROP TRIGGER record_taxi_write_scope ON taxis;
+			DROP FUNCTION record_taxi_write_scope();
+			DROP TABLE taxi_write_scopes`)
+	})
+
+	plate, model := "SCOPED-V2", "Prius"
+	taxi := tenant.client.create("/api/v2/taxis", service.CreateTaxiRequest{LicensePlate: plate, Model: "Corolla"})
+	if code, data := tenant.client.do(http.MethodPut, fmt.Sprintf("/api/v2/taxis/%d", taxi), service.UpdateTaxiRequest{Model: &model}); code != http.StatusOK {
+		t.Fatalf("failed to update the taxi: %d %s", code, data)
+	}
+
+	var scopes []string
+	if err := db.Select(&scopes, "SELECT COALESCE(tenant_id, '') FROM taxi_write_scopes WHERE license_plate = $1", plate); err != nil {
+		t.Fatalf("failed to read the scopes: %v", err)
+	}
+	want := fmt.Sprint(tenant.owner.TenantID)
+	if len(scopes) != 2 || scopes[0] != want || scopes[1] != want {
+		t.Fatalf("expected the create and the update scoped to tenant %s, got %q", want, scopes)
+	}
+}
+
+// TestTenantIsolationDeviceTakeover re-registers a device token of one tenant
+// under a user of another, as when a shared phone changes hands. The row of
+// the first tenant is hidden from the second's transaction, yet the token
+// must move instead of failing on its unique index.
+func TestTenantIsolationDeviceTakeover(t *testing.T) {
+	api := startAPI(t)
+	previous := api.createTenant("PREVIOUS")
+	next := api.createTenant("NEXT")
+
+	device := service.RegisterDeviceRequest{Token: fmt.Sprintf("device-%d", time.Now().UnixNano()), Platform: "android"}
+	previous.client.create("/api/v1/devices", device)
+	next.client.create("/api/v1/devices", device)
+
+	ctx := context.Background()
+	devices, err := api.repo.GetDeviceTokensByUser(ctx, next.owner.ID)
+	if err != nil || len(devices) != 1 || devices[0].TenantID != next.owner.TenantID {
+		t.Fatalf("expected the token moved to the other tenant, got %+v, %v", devices, err)
+	}
+	if devices, err := api.repo.GetDeviceTokensByUser(ctx, previous.owner.ID); err != nil || len(devices) != 0 {
+		t.Fatalf("expected the token gone from its previous user, got %+v, %v", devices, err)
+	}
+}
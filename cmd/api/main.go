@@ -2,21 +2,44 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"net/http/pprof"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"github.com/getsentry/sentry-go"
+	sentrygin "github.com/getsentry/sentry-go/gin"
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 
+	"taxifleet/backend/internal/antivirus"
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/cache"
 	"taxifleet/backend/internal/config"
 	"taxifleet/backend/internal/database"
+	"taxifleet/backend/internal/fuelcard"
 	"taxifleet/backend/internal/handlers"
+	"taxifleet/backend/internal/logging"
+	"taxifleet/backend/internal/mail"
+	"taxifleet/backend/internal/metrics"
 	"taxifleet/backend/internal/middleware"
+	"taxifleet/backend/internal/ocr"
 	"taxifleet/backend/internal/permissions"
+	"taxifleet/backend/internal/push"
+	"taxifleet/backend/internal/ratelimit"
 	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/scheduler"
 	"taxifleet/backend/internal/service"
+	"taxifleet/backend/internal/sessions"
+	"taxifleet/backend/internal/storage"
+	"taxifleet/backend/internal/tokens"
+	"taxifleet/backend/internal/tracing"
+	"taxifleet/backend/internal/validation"
 )
 
 func main() {
@@ -70,46 +93,149 @@ func main() {
 	// Initialize repository
 	repo := repository.New(db.GetDB())
 
-	// Initialize permissions from config
-	permissions.SetPermissionMasks(
-		cfg.Permissions.Admin,
-		cfg.Permissions.Owner,
-		cfg.Permissions.Manager,
-		cfg.Permissions.Mechanic,
-		cfg.Permissions.Driver,
-	)
+	// Initialize the settings admins tune at runtime; loading them applies the
+	// permission masks of the config unless an admin changed them
+	systemSettingService := service.NewSystemSettingService(repo, cfg, logger)
+	if err := systemSettingService.Load(context.Background()); err != nil {
+		logger.WithError(err).Fatal("Failed to load system settings")
+	}
 
-	// Initialize services
-	authService := service.NewAuthService(repo, cfg)
-	taxiService := service.NewTaxiService(repo)
-	reportService := service.NewReportService(repo)
-	depositService := service.NewDepositService(repo)
-	expenseService := service.NewExpenseService(repo)
-	dashboardService := service.NewDashboardService(repo)
-	adminService := service.NewAdminService(repo)
+	// Initialize push notification sender
+	var pushSender push.Sender = push.NoopSender{}
+	if cfg.Push.Enabled {
+		fcmSender, err := push.NewFCMSender(cfg.Push.FCMProjectID, cfg.Push.FCMCredentials)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to initialize FCM sender")
+		}
+		pushSender = fcmSender
+	}
 
-	// Initialize handlers
-	authHandler := handlers.NewAuthHandler(authService)
-	taxiHandler := handlers.NewTaxiHandler(taxiService)
-	reportHandler := handlers.NewReportHandler(reportService)
-	depositHandler := handlers.NewDepositHandler(depositService)
-	expenseHandler := handlers.NewExpenseHandler(expenseService)
-	dashboardHandler := handlers.NewDashboardHandler(dashboardService)
-	adminHandler := handlers.NewAdminHandler(adminService)
+	// Initialize the email sender
+	var mailSender mail.Sender = mail.NoopSender{}
+	if cfg.Mail.Enabled {
+		smtpSender, err := mail.NewSMTPSender(cfg.Mail.SMTPHost, cfg.Mail.SMTPPort, cfg.Mail.SMTPUsername, cfg.Mail.SMTPPassword, cfg.Mail.From)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to initialize SMTP sender")
+		}
+		mailSender = smtpSender
+	}
 
-	// Setup router
-	router := setupRouter(
-		authHandler,
-		taxiHandler,
-		reportHandler,
-		depositHandler,
-		expenseHandler,
-		dashboardHandler,
-		adminHandler,
-		authService,
-		cfg,
-		logger,
-	)
+	// Initialize the receipt OCR provider
+	var ocrProvider ocr.Provider = ocr.DisabledProvider{}
+	if cfg.OCR.Provider == "google_vision" {
+		ocrProvider = ocr.NewVisionProvider(cfg.OCR.GoogleVisionAPIKey)
+	}
+
+	// Initialize the fuel card provider
+	var fuelCardProvider fuelcard.Provider = fuelcard.DisabledProvider{}
+	if cfg.FuelCard.Provider == "http" {
+		fuelCardProvider = fuelcard.NewHTTPProvider(cfg.FuelCard.APIURL, cfg.FuelCard.APIKey)
+	}
+
+	// Initialize tracing
+	if cfg.Tracing.Enabled {
+		shutdownTracing, err := tracing.Init(context.Background(), cfg.Tracing)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to initialize tracing")
+		}
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := shutdownTracing(ctx); err != nil {
+				logger.WithError(err).Error("Failed to flush traces")
+			}
+		}()
+		logger.WithField("endpoint", cfg.Tracing.OTLPEndpoint).Info("Tracing enabled")
+	}
+
+	// Report the connection pool on the metrics endpoint
+	if cfg.Metrics.Enabled {
+		if err := metrics.RegisterDBStats(db.DB.DB, cfg.Database.Name); err != nil {
+			logger.WithError(err).Fatal("Failed to register database metrics")
+		}
+	}
+
+	// Initialize error reporting
+	if cfg.Sentry.Enabled() {
+		err := sentry.Init(sentry.ClientOptions{
+			Dsn:              cfg.Sentry.DSN,
+			Environment:      cfg.Sentry.Environment,
+			Release:          cfg.Sentry.Release,
+			SampleRate:       cfg.Sentry.SampleRate,
+			AttachStacktrace: true,
+		})
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to initialize error reporting")
+		}
+		defer sentry.Flush(5 * time.Second)
+		logger.Info("Error reporting enabled")
+	}
+
+	// One Redis connection pool serves the cache, sessions and rate limits
+	var redisClient *redis.Client
+	if cfg.Cache.Enabled || cfg.Session.Store == "redis" || cfg.Security.RateLimitStore == "redis" {
+		client, err := cache.Connect(cfg.Cache.RedisAddr, cfg.Cache.RedisPassword, cfg.Cache.RedisDB)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to initialize Redis")
+		}
+		defer client.Close()
+		redisClient = client
+	}
+
+	// Initialize cache
+	var appCache cache.Cache = cache.Noop{}
+	if cfg.Cache.Enabled {
+		appCache = cache.NewRedis(redisClient, cfg.Cache.TTL, logger)
+	}
+
+	jwtKeys, err := tokens.Load(cfg.JWT)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to load JWT signing keys")
+	}
+
+	// Initialize session store
+	var sessionStore repository.SessionRepo = repo
+	if cfg.Session.Store == "redis" {
+		sessionStore = sessions.NewRedis(redisClient)
+	}
+
+	// Initialize the counters of the per-user and per-API key rate limits
+	var rateCounter ratelimit.Counter = ratelimit.NewMemory()
+	if cfg.Security.RateLimitStore == "redis" {
+		rateCounter = ratelimit.NewRedis(redisClient)
+	}
+
+	// Uploaded files, such as report attachments
+	fileStore, err := storage.New(cfg.Storage.Driver, cfg.Storage)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to initialize file storage")
+	}
+
+	// Virus scanning of uploads
+	var scanner antivirus.Scanner
+	if cfg.Antivirus.Enabled() {
+		scanner, err = antivirus.New(cfg.Antivirus)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to initialize antivirus scanner")
+		}
+	}
+
+	// Initialize services, background jobs, handlers and the router
+	router, jobs, err := newApp(repo, systemSettingService, backends{
+		push:        pushSender,
+		mail:        mailSender,
+		ocr:         ocrProvider,
+		fuelCards:   fuelCardProvider,
+		cache:       appCache,
+		jwtKeys:     jwtKeys,
+		sessions:    sessionStore,
+		rateCounter: rateCounter,
+		files:       fileStore,
+		scanner:     scanner,
+	}, cfg, logger)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to set up the application")
+	}
 
 	// Create HTTP server
 	server := &http.Server{
@@ -128,6 +254,12 @@ func main() {
 		}
 	}()
 
+	// Start background jobs
+	if cfg.Scheduler.Enabled {
+		jobs.Start()
+		logger.Info("Background job scheduler started")
+	}
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -145,8 +277,263 @@ func main() {
 	} else {
 		logger.Info("Server shutdown complete")
 	}
+
+	// Stop background jobs
+	if cfg.Scheduler.Enabled {
+		jobs.Stop(ctx)
+	}
+}
+
+// backends are the external systems the services work with, picked by main
+// from the configuration
+type backends struct {
+	push        push.Sender
+	mail        mail.Sender
+	ocr         ocr.Provider
+	fuelCards   fuelcard.Provider
+	cache       cache.Cache
+	jwtKeys     *tokens.KeySet
+	sessions    repository.SessionRepo
+	rateCounter ratelimit.Counter
+	files       storage.Store
+	scanner     antivirus.Scanner
+}
+
+// newApp initializes the services, background jobs and handlers on the
+// repository and sets up the router. The jobs are registered, not started.
+func newApp(repo *repository.Repository, systemSettingService *service.SystemSettingService, b backends, cfg *config.Config, logger *logrus.Logger) (*gin.Engine, *scheduler.Scheduler, error) {
+	// Initialize services
+	notificationService := service.NewNotificationService(repo, b.push, logger)
+	authService := service.NewAuthService(repo, b.sessions, notificationService, cfg, b.jwtKeys)
+	taxiService := service.NewTaxiService(repo, b.cache)
+	policyService := service.NewPolicyService(repo, b.cache)
+	reportService := service.NewReportService(repo, b.cache, notificationService, policyService, b.files, b.scanner, cfg.Attachments)
+	depositService := service.NewDepositService(repo, b.cache)
+	budgetService := service.NewBudgetService(repo, b.cache, notificationService, logger)
+	expenseService := service.NewExpenseService(repo, b.cache, b.ocr, budgetService)
+	dashboardService := service.NewDashboardService(repo, b.cache)
+	maintenanceService := service.NewMaintenanceService(repo, notificationService)
+	inventoryService := service.NewInventoryService(repo, notificationService)
+	analyticsService := service.NewAnalyticsService(repo)
+	idempotencyService := service.NewIdempotencyService(repo)
+	tenantExportService := service.NewTenantExportService(repo, b.files, cfg.DataExport)
+	adminService := service.NewAdminService(repo, cfg, tenantExportService, b.files)
+	apiKeyService := service.NewAPIKeyService(repo)
+	oauthService := service.NewOAuthService(repo, authService, cfg.OAuth)
+	searchService := service.NewSearchService(repo)
+	ledgerService := service.NewLedgerService(repo)
+	bankAccountService := service.NewBankAccountService(repo, b.cache)
+	brandingService := service.NewBrandingService(repo)
+	exportBundleService := service.NewExportBundleService(repo, brandingService, notificationService, b.files, cfg.DataExport.TTL, cfg.Attachments.URLTTL, logger)
+	digestService := service.NewDigestService(repo, b.mail, logger)
+	onboardingService := service.NewOnboardingService(repo, b.mail, cfg)
+	demoService := service.NewDemoService(repo)
+	activityService := service.NewActivityService(repo)
+	fineService := service.NewFineService(repo)
+	tripService := service.NewTripService(repo)
+	platformEarningService := service.NewPlatformEarningService(repo)
+	fuelCardService := service.NewFuelCardService(repo, b.cache, b.fuelCards, logger)
+	geofenceService := service.NewGeofenceService(repo, notificationService)
+	fleetService := service.NewFleetService(repo)
+	syncService := service.NewSyncService(repo, reportService, expenseService)
+	planService := service.NewPlanService(repo, systemSettingService)
+	retentionService := service.NewRetentionService(repo, logger)
+	delegationService := service.NewDelegationService(repo)
+	periodService := service.NewPeriodService(repo)
+
+	// Register background jobs
+	jobs := scheduler.New(repo, logger)
+	jobDefinitions := []struct {
+		name     string
+		schedule string
+		run      scheduler.JobFunc
+	}{
+		{
+			// Alert mechanics about preventive maintenance that became due
+			name:     "maintenance_due",
+			schedule: "@every " + cfg.Maintenance.DueCheckInterval.String(),
+			run: func(ctx context.Context) error {
+				return maintenanceService.NotifyDue(ctx)
+			},
+		},
+		{
+			name:     "session_cleanup",
+			schedule: cfg.Scheduler.SessionCleanupSchedule,
+			run: func(ctx context.Context) error {
+				_, err := authService.CleanupSessions(ctx)
+				return err
+			},
+		},
+		{
+			// Email owners the summary of the week that just ended
+			name:     "weekly_digest",
+			schedule: cfg.Scheduler.WeeklyDigestSchedule,
+			run: func(ctx context.Context) error {
+				return digestService.SendWeekly(ctx)
+			},
+		},
+		{
+			name:     "idempotency_key_cleanup",
+			schedule: "@hourly",
+			run: func(ctx context.Context) error {
+				_, err := idempotencyService.Cleanup(ctx)
+				return err
+			},
+		},
+		{
+			// Remove the data tenants' retention settings no longer keep
+			name:     "data_retention",
+			schedule: cfg.Scheduler.DataRetentionSchedule,
+			run: func(ctx context.Context) error {
+				return retentionService.Purge(ctx)
+			},
+		},
+		{
+			name:     "data_export_cleanup",
+			schedule: "@hourly",
+			run: func(ctx context.Context) error {
+				if _, err := tenantExportService.Cleanup(ctx); err != nil {
+					return err
+				}
+				_, err := exportBundleService.Cleanup(ctx)
+				return err
+			},
+		},
+	}
+	for _, def := range jobDefinitions {
+		if err := jobs.Register(def.name, def.schedule, def.run); err != nil {
+			return nil, nil, fmt.Errorf("failed to register background job: %w", err)
+		}
+	}
+	if cfg.Antivirus.Enabled() {
+		// Scan the uploads whose scan failed, e.g. while the scanner was down
+		err := jobs.Register("attachment_scan", cfg.Antivirus.RetrySchedule, func(ctx context.Context) error {
+			_, err := reportService.ScanPendingAttachments(ctx)
+			return err
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to register background job: %w", err)
+		}
+	}
+	if cfg.Backup.Scheduled() {
+		// Dump the database into the file storage, keeping the latest dumps
+		backupService := service.NewBackupService(repo, database.NewPgDumper(&cfg.Database, cfg.Backup), b.files, cfg.Backup)
+		if err := jobs.Register("database_backup", cfg.Backup.Schedule, backupService.Scheduled); err != nil {
+			return nil, nil, fmt.Errorf("failed to register background job: %w", err)
+		}
+	}
+	if cfg.FuelCard.Enabled() {
+		// Book the fuel card transactions made since the last sync
+		err := jobs.Register("fuel_card_sync", cfg.Scheduler.FuelCardSyncSchedule, func(ctx context.Context) error {
+			_, err := fuelCardService.Sync(ctx)
+			return err
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to register background job: %w", err)
+		}
+	}
+
+	// Initialize handlers
+	authHandler := handlers.NewAuthHandler(authService)
+	taxiHandler := handlers.NewTaxiHandler(taxiService)
+	reportHandler := handlers.NewReportHandler(reportService, brandingService)
+	depositHandler := handlers.NewDepositHandler(depositService, brandingService)
+	expenseHandler := handlers.NewExpenseHandler(expenseService, brandingService)
+	dashboardHandler := handlers.NewDashboardHandler(dashboardService)
+	adminHandler := handlers.NewAdminHandler(adminService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	maintenanceHandler := handlers.NewMaintenanceHandler(maintenanceService)
+	inventoryHandler := handlers.NewInventoryHandler(inventoryService)
+	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService, brandingService)
+	jobHandler := handlers.NewJobHandler(jobs)
+	tenantExportHandler := handlers.NewTenantExportHandler(tenantExportService)
+	exportBundleHandler := handlers.NewExportBundleHandler(exportBundleService)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
+	oauthHandler := handlers.NewOAuthHandler(oauthService, cfg.OAuth.FrontendURL)
+	searchHandler := handlers.NewSearchHandler(searchService)
+	ledgerHandler := handlers.NewLedgerHandler(ledgerService)
+	bankAccountHandler := handlers.NewBankAccountHandler(bankAccountService)
+	digestHandler := handlers.NewDigestHandler(digestService)
+	activityHandler := handlers.NewActivityHandler(activityService)
+	fineHandler := handlers.NewFineHandler(fineService)
+	tripHandler := handlers.NewTripHandler(tripService)
+	platformEarningHandler := handlers.NewPlatformEarningHandler(platformEarningService)
+	fuelCardHandler := handlers.NewFuelCardHandler(fuelCardService)
+	geofenceHandler := handlers.NewGeofenceHandler(geofenceService)
+	fleetHandler := handlers.NewFleetHandler(fleetService)
+	syncHandler := handlers.NewSyncHandler(syncService)
+	planHandler := handlers.NewPlanHandler(planService)
+	onboardingHandler := handlers.NewOnboardingHandler(onboardingService)
+	demoHandler := handlers.NewDemoHandler(demoService)
+	systemSettingHandler := handlers.NewSystemSettingHandler(systemSettingService)
+	policyHandler := handlers.NewPolicyHandler(policyService)
+	retentionHandler := handlers.NewRetentionHandler(retentionService)
+	delegationHandler := handlers.NewDelegationHandler(delegationService)
+	periodHandler := handlers.NewPeriodHandler(periodService)
+	budgetHandler := handlers.NewBudgetHandler(budgetService)
+
+	// Register the domain validation rules used in binding tags
+	if err := validation.RegisterWithGin(); err != nil {
+		return nil, nil, fmt.Errorf("failed to register validation rules: %w", err)
+	}
+	// Clean and cap the text of every bound request before it is validated
+	validation.SanitizeWithGin(validation.Sanitizer{MaxLength: cfg.Security.TextMaxLength, EscapeHTML: cfg.Security.EscapeHTML})
+
+	// Setup router
+	return setupRouter(
+		authHandler,
+		taxiHandler,
+		reportHandler,
+		depositHandler,
+		expenseHandler,
+		dashboardHandler,
+		adminHandler,
+		notificationHandler,
+		maintenanceHandler,
+		inventoryHandler,
+		analyticsHandler,
+		jobHandler,
+		tenantExportHandler,
+		exportBundleHandler,
+		apiKeyHandler,
+		oauthHandler,
+		searchHandler,
+		ledgerHandler,
+		bankAccountHandler,
+		digestHandler,
+		activityHandler,
+		fineHandler,
+		tripHandler,
+		platformEarningHandler,
+		fuelCardHandler,
+		geofenceHandler,
+		fleetHandler,
+		syncHandler,
+		planHandler,
+		onboardingHandler,
+		demoHandler,
+		systemSettingHandler,
+		policyHandler,
+		retentionHandler,
+		delegationHandler,
+		periodHandler,
+		budgetHandler,
+		authService,
+		apiKeyService,
+		idempotencyService,
+		planService,
+		systemSettingService,
+		policyService,
+		b.rateCounter,
+		repo,
+		cfg,
+		logger,
+	), jobs, nil
 }
 
+// taxisV2Since is when the v1 taxi routes were deprecated in favor of v2
+var taxisV2Since = time.Date(2026, time.October, 15, 0, 0, 0, 0, time.UTC)
+
 func setupRouter(
 	authHandler *handlers.AuthHandler,
 	taxiHandler *handlers.TaxiHandler,
@@ -155,15 +542,62 @@ func setupRouter(
 	expenseHandler *handlers.ExpenseHandler,
 	dashboardHandler *handlers.DashboardHandler,
 	adminHandler *handlers.AdminHandler,
+	notificationHandler *handlers.NotificationHandler,
+	maintenanceHandler *handlers.MaintenanceHandler,
+	inventoryHandler *handlers.InventoryHandler,
+	analyticsHandler *handlers.AnalyticsHandler,
+	jobHandler *handlers.JobHandler,
+	tenantExportHandler *handlers.TenantExportHandler,
+	exportBundleHandler *handlers.ExportBundleHandler,
+	apiKeyHandler *handlers.APIKeyHandler,
+	oauthHandler *handlers.OAuthHandler,
+	searchHandler *handlers.SearchHandler,
+	ledgerHandler *handlers.LedgerHandler,
+	bankAccountHandler *handlers.BankAccountHandler,
+	digestHandler *handlers.DigestHandler,
+	activityHandler *handlers.ActivityHandler,
+	fineHandler *handlers.FineHandler,
+	tripHandler *handlers.TripHandler,
+	platformEarningHandler *handlers.PlatformEarningHandler,
+	fuelCardHandler *handlers.FuelCardHandler,
+	geofenceHandler *handlers.GeofenceHandler,
+	fleetHandler *handlers.FleetHandler,
+	syncHandler *handlers.SyncHandler,
+	planHandler *handlers.PlanHandler,
+	onboardingHandler *handlers.OnboardingHandler,
+	demoHandler *handlers.DemoHandler,
+	systemSettingHandler *handlers.SystemSettingHandler,
+	policyHandler *handlers.PolicyHandler,
+	retentionHandler *handlers.RetentionHandler,
+	delegationHandler *handlers.DelegationHandler,
+	periodHandler *handlers.PeriodHandler,
+	budgetHandler *handlers.BudgetHandler,
 	authService *service.AuthService,
+	apiKeyService *service.APIKeyService,
+	idempotencyService *service.IdempotencyService,
+	planService *service.PlanService,
+	systemSettingService *service.SystemSettingService,
+	policyService *service.PolicyService,
+	rateCounter ratelimit.Counter,
+	transactor repository.Transactor,
 	cfg *config.Config,
 	logger *logrus.Logger,
 ) *gin.Engine {
 	router := gin.New()
+	apierror.UseJSONFieldNames()
 
-	// Add logging middleware
+	// Trace every request; continues traces started by the caller
+	if cfg.Tracing.Enabled {
+		router.Use(otelgin.Middleware(cfg.Tracing.ServiceName))
+	}
+
+	// Tag every request with an ID returned to the client and logged with it
+	router.Use(middleware.RequestID())
+
+	// Log every request with the request's fields: request_id, route and,
+	// once authenticated, user_id and tenant_id (or api_key_id)
 	router.Use(gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
-		logger.WithFields(logrus.Fields{
+		logging.Entry(param.Request.Context(), logger).WithFields(logrus.Fields{
 			"status_code": param.StatusCode,
 			"latency":     param.Latency,
 			"client_ip":   param.ClientIP,
@@ -177,89 +611,415 @@ func setupRouter(
 	// Add recovery middleware
 	router.Use(gin.Recovery())
 
+	// Report panics (re-raised for gin's recovery) and 5xx responses
+	if cfg.Sentry.Enabled() {
+		router.Use(sentrygin.New(sentrygin.Options{Repanic: true}))
+		router.Use(middleware.ErrorReporting())
+	}
+
 	// CORS middleware
 	router.Use(middleware.CORS())
 
+	// Limit the requests per client, as tuned in the system settings
+	router.Use(middleware.RateLimit(systemSettingService))
+
+	// Cancel database work once the response can no longer be written
+	router.Use(middleware.Timeout(cfg.Server.WriteTimeout))
+
+	router.NoRoute(func(c *gin.Context) {
+		apierror.Abort(c, apierror.NotFound("Route not found"))
+	})
+
 	// Health check
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "ok"})
 	})
 
+	// Prometheus metrics: connection pool and query durations
+	if cfg.Metrics.Enabled {
+		router.GET("/metrics", gin.WrapH(metrics.Handler(cfg.Metrics.Token)))
+	}
+
+	// Public keys for verifying access tokens signed with RS256/EdDSA
+	router.GET("/.well-known/jwks.json", authHandler.JWKS)
+
+	// Runtime profiles for admins, e.g. go tool pprof with an access token.
+	// CPU profiles and traces must be shorter than the server's write timeout.
+	if cfg.Server.Pprof {
+		debug := router.Group("/debug/pprof", middleware.Auth(authService, apiKeyService, logger), adminHandler.RequireAdmin)
+		{
+			debug.GET("/", gin.WrapF(pprof.Index))
+			debug.GET("/cmdline", gin.WrapF(pprof.Cmdline))
+			debug.GET("/profile", gin.WrapF(pprof.Profile))
+			debug.GET("/symbol", gin.WrapF(pprof.Symbol))
+			debug.POST("/symbol", gin.WrapF(pprof.Symbol))
+			debug.GET("/trace", gin.WrapF(pprof.Trace))
+			// Named profiles: heap, goroutine, allocs, block, mutex, threadcreate
+			debug.GET("/:profile", gin.WrapF(pprof.Index))
+		}
+	}
+
+	// Taxi permissions, shared by v1 and v2; the tenant's policies come first
+	viewTaxis := middleware.Authorize(policyService, service.PolicyViewTaxis, permissions.PermissionViewTaxis)
+	addTaxis := middleware.Authorize(policyService, service.PolicyAddTaxis, permissions.PermissionAddTaxis)
+	editTaxis := middleware.Authorize(policyService, service.PolicyEditTaxis, permissions.PermissionEditTaxis)
+	deleteTaxis := middleware.Authorize(policyService, service.PolicyDeleteTaxis, permissions.PermissionDeleteTaxis)
+
 	// API v1 routes
 	v1 := router.Group("/api/v1")
 	{
 		// Auth routes (public)
 		auth := v1.Group("/auth")
 		{
-			// Registration removed - only admins can create users
 			auth.POST("/login", authHandler.Login)
 			auth.POST("/refresh", authHandler.Refresh)
-			auth.POST("/logout", middleware.Auth(authService, logger), authHandler.Logout)
-			auth.GET("/me", middleware.Auth(authService, logger), authHandler.Me)
-			auth.PUT("/profile", middleware.Auth(authService, logger), authHandler.UpdateProfile)
+			auth.POST("/logout", middleware.Auth(authService, apiKeyService, logger), authHandler.Logout)
+			auth.GET("/me", middleware.Auth(authService, apiKeyService, logger), authHandler.Me)
+			auth.PUT("/profile", middleware.Auth(authService, apiKeyService, logger), authHandler.UpdateProfile)
+			auth.GET("/login-history", middleware.Auth(authService, apiKeyService, logger), authHandler.LoginHistory)
+
+			// Sign in with Google, for users an admin already created
+			if cfg.OAuth.GoogleEnabled() {
+				auth.GET("/oauth/google", oauthHandler.GoogleLogin)
+				auth.GET("/oauth/google/callback", oauthHandler.GoogleCallback)
+			}
+		}
+
+		// Self-service sign-up of new tenants (public); otherwise only admins
+		// create tenants and users
+		if cfg.Onboarding.Enabled {
+			onboarding := v1.Group("/onboarding")
+			{
+				onboarding.POST("", onboardingHandler.Onboard)
+				onboarding.POST("/verify", onboardingHandler.Verify)
+				onboarding.POST("/resend", onboardingHandler.Resend)
+			}
 		}
 
 		// Protected routes
 		protected := v1.Group("")
-		protected.Use(middleware.Auth(authService, logger))
+		protected.Use(middleware.Auth(authService, apiKeyService, logger))
+		protected.Use(middleware.APIKeyRateLimit(rateCounter, planService, logger))
+		// Scope the request's queries to the tenant by row-level security
+		if cfg.Database.RowLevelSecurity {
+			protected.Use(middleware.TenantIsolation(transactor, logger, "/api/v1/fleet/status/stream"))
+		}
+		protected.Use(middleware.Fields())
 		{
+			// Create endpoints replay their response when retried with an Idempotency-Key
+			idempotent := middleware.Idempotency(idempotencyService, logger)
+
 			// Dashboard
 			dashboard := protected.Group("/dashboard")
 			{
 				dashboard.GET("/stats", dashboardHandler.GetStats)
+				dashboard.GET("/timeseries", middleware.RequirePermission(permissions.PermissionViewDeposits, permissions.PermissionViewExpenses), dashboardHandler.GetTimeSeries)
+				dashboard.GET("/mechanic", middleware.RequirePermission(permissions.PermissionViewTaxis), maintenanceHandler.GetMechanicDashboard)
 			}
 
-			// Taxis
+			// Fleet status board for a wall display
+			fleet := protected.Group("/fleet")
+			fleet.Use(middleware.RequirePermission(permissions.PermissionViewTaxis))
+			{
+				fleet.GET("/status", fleetHandler.Status)
+				fleet.GET("/status/stream", fleetHandler.StatusStream)
+			}
+
+			// Analytics (financial data, same audience as the dashboard stats)
+			analytics := protected.Group("/analytics")
+			analytics.Use(middleware.RequirePermission(permissions.PermissionViewDeposits, permissions.PermissionViewExpenses))
+			{
+				analytics.GET("/drivers", analyticsHandler.Drivers)
+				analytics.GET("/taxis", analyticsHandler.Taxis)
+				analytics.GET("/pnl", analyticsHandler.ProfitAndLoss)
+				analytics.GET("/tax", analyticsHandler.TaxReport)
+				analytics.GET("/journal", analyticsHandler.Journal)
+			}
+
+			// Search across taxis, reports and expenses
+			protected.GET("/search", searchHandler.Search)
+
+			// Taxis; the CRUD routes moved to v2
 			taxis := protected.Group("/taxis")
 			{
-				taxis.GET("", taxiHandler.List)
-				taxis.POST("", taxiHandler.Create)
-				taxis.GET("/:id", taxiHandler.Get)
-				taxis.PUT("/:id", taxiHandler.Update)
-				taxis.DELETE("/:id", taxiHandler.Delete)
+				deprecated := middleware.Deprecated(taxisV2Since, cfg.Server.V1Sunset)
+
+				taxis.GET("", deprecated, viewTaxis, taxiHandler.List)
+				taxis.GET("/count", viewTaxis, taxiHandler.Count)
+				taxis.POST("/bulk-status", editTaxis, taxiHandler.BulkStatus)
+				taxis.POST("", deprecated, addTaxis, idempotent, taxiHandler.Create)
+				taxis.GET("/:id", deprecated, viewTaxis, taxiHandler.Get)
+				taxis.PUT("/:id", deprecated, editTaxis, taxiHandler.Update)
+				taxis.DELETE("/:id", deprecated, deleteTaxis, taxiHandler.Delete)
+				taxis.GET("/:id/assignments", viewTaxis, taxiHandler.Assignments)
+				taxis.GET("/:id/ledger", middleware.RequirePermission(permissions.PermissionViewDeposits), taxiHandler.Ledger)
+				taxis.POST("/:id/positions", middleware.RequirePermission(permissions.PermissionAddReports, permissions.PermissionEditTaxis), geofenceHandler.ReportPositions)
+			}
+
+			// Operating zone; positions reported outside it alert the users managing taxis
+			geofences := protected.Group("/geofences")
+			{
+				geofences.GET("", middleware.RequirePermission(permissions.PermissionViewTaxis), geofenceHandler.List)
+				geofences.POST("", middleware.RequirePermission(permissions.PermissionEditTaxis), idempotent, geofenceHandler.Create)
+				geofences.PUT("/:id", middleware.RequirePermission(permissions.PermissionEditTaxis), geofenceHandler.Update)
+				geofences.DELETE("/:id", middleware.RequirePermission(permissions.PermissionEditTaxis), geofenceHandler.Delete)
 			}
 
 			// Reports
 			reports := protected.Group("/reports")
 			{
 				reports.GET("", reportHandler.List)
-				reports.POST("", reportHandler.Create)
+				reports.POST("", idempotent, reportHandler.Create)
+				reports.GET("/weeks", reportHandler.Weeks)
+				reports.GET("/count", reportHandler.Count)
+				reports.GET("/summary", reportHandler.Summary)
+				reports.POST("/archive", middleware.RequirePermission(permissions.PermissionEditReports), reportHandler.Archive)
 				reports.GET("/:id", reportHandler.Get)
 				reports.PUT("/:id", reportHandler.Update)
 				reports.DELETE("/:id", reportHandler.Delete)
 				reports.POST("/:id/submit", reportHandler.Submit)
 				reports.POST("/:id/approve", reportHandler.Approve)
 				reports.POST("/:id/reject", reportHandler.Reject)
+				reports.POST("/:id/reopen", reportHandler.Reopen)
+				reports.GET("/:id/receipt.pdf", reportHandler.Receipt)
+				reports.GET("/:id/attachments", reportHandler.ListAttachments)
+				reports.POST("/:id/attachments", reportHandler.UploadAttachment)
+				reports.GET("/:id/attachments/:attachmentId", reportHandler.DownloadAttachment)
+				reports.DELETE("/:id/attachments/:attachmentId", reportHandler.DeleteAttachment)
+				reports.GET("/:id/adjustments", reportHandler.ListAdjustments)
+				reports.POST("/:id/adjustments", reportHandler.AddAdjustment)
+				reports.DELETE("/:id/adjustments/:adjustmentId", reportHandler.DeleteAdjustment)
+			}
+
+			// Stored files by ID; files are report attachments
+			files := protected.Group("/files")
+			{
+				files.GET("/:id", reportHandler.DownloadFile)
+				files.GET("/:id/url", reportHandler.FileURL)
+				files.GET("/:id/thumb", reportHandler.FileThumbnail)
+			}
+
+			// Owners delegate their approval rights for a date range; the
+			// service checks the caller is an owner
+			delegations := protected.Group("/delegations")
+			{
+				delegations.GET("", delegationHandler.List)
+				delegations.POST("", delegationHandler.Create)
+				delegations.DELETE("/:id", delegationHandler.Revoke)
+			}
+
+			// Months closed for the books; the service checks the caller is an
+			// owner, and only admins reopen them
+			periods := protected.Group("/periods")
+			{
+				periods.GET("", middleware.RequirePermission(permissions.PermissionViewReports), periodHandler.List)
+				periods.POST("/close", periodHandler.Close)
+			}
+
+			// Offline sync of the driver app; items carry client IDs so retries are safe
+			protected.POST("/sync", middleware.RequirePermission(permissions.PermissionAddReports), syncHandler.Sync)
+
+			// Trips logged by the driver app, rolled up into report earnings
+			trips := protected.Group("/trips")
+			{
+				trips.GET("", middleware.RequirePermission(permissions.PermissionViewReports), tripHandler.List)
+				trips.POST("/batch", middleware.RequirePermission(permissions.PermissionAddReports), idempotent, tripHandler.LogBatch)
+				trips.GET("/weekly-earnings", middleware.RequirePermission(permissions.PermissionAddReports), tripHandler.WeeklyEarnings)
+			}
+
+			// Ride-hailing platform earnings, reconciled with reports by those reviewing them
+			platformEarnings := protected.Group("/platform-earnings")
+			platformEarnings.Use(middleware.RequirePermission(permissions.PermissionEditReports))
+			{
+				platformEarnings.POST("/import", platformEarningHandler.Import)
+				platformEarnings.POST("", idempotent, platformEarningHandler.Push)
+				platformEarnings.GET("/reconciliation", platformEarningHandler.Reconciliation)
 			}
 
 			// Deposits
 			deposits := protected.Group("/deposits")
 			{
-				deposits.GET("", depositHandler.List)
-				deposits.POST("", depositHandler.Create)
-				deposits.GET("/:id", depositHandler.Get)
-				deposits.PUT("/:id", depositHandler.Update)
-				deposits.DELETE("/:id", depositHandler.Delete)
+				viewDeposits := middleware.Authorize(policyService, service.PolicyViewDeposits, permissions.PermissionViewDeposits)
+				addDeposits := middleware.Authorize(policyService, service.PolicyAddDeposits, permissions.PermissionAddDeposits)
+				editDeposits := middleware.Authorize(policyService, service.PolicyEditDeposits, permissions.PermissionEditDeposits)
+				deleteDeposits := middleware.Authorize(policyService, service.PolicyDeleteDeposits, permissions.PermissionDeleteDeposits)
+
+				deposits.GET("", viewDeposits, depositHandler.List)
+				deposits.POST("", addDeposits, idempotent, depositHandler.Create)
+				deposits.GET("/:id", viewDeposits, depositHandler.Get)
+				deposits.PUT("/:id", editDeposits, depositHandler.Update)
+				deposits.DELETE("/:id", deleteDeposits, depositHandler.Delete)
+				deposits.POST("/:id/verify", editDeposits, depositHandler.Verify)
+			}
+
+			// Bank accounts deposits are made into
+			bankAccounts := protected.Group("/bank-accounts")
+			{
+				viewDeposits := middleware.RequirePermission(permissions.PermissionViewDeposits)
+				editDeposits := middleware.RequirePermission(permissions.PermissionEditDeposits)
+
+				bankAccounts.GET("", viewDeposits, bankAccountHandler.List)
+				bankAccounts.GET("/totals", viewDeposits, bankAccountHandler.Totals)
+				bankAccounts.POST("", editDeposits, bankAccountHandler.Create)
+				bankAccounts.GET("/:id", viewDeposits, bankAccountHandler.Get)
+				bankAccounts.PUT("/:id", editDeposits, bankAccountHandler.Update)
+				bankAccounts.DELETE("/:id", editDeposits, bankAccountHandler.Delete)
 			}
 
 			// Expenses
 			expenses := protected.Group("/expenses")
 			{
-				expenses.GET("", expenseHandler.List)
-				expenses.POST("", expenseHandler.Create)
-				expenses.GET("/:id", expenseHandler.Get)
-				expenses.PUT("/:id", expenseHandler.Update)
-				expenses.DELETE("/:id", expenseHandler.Delete)
+				viewExpenses := middleware.Authorize(policyService, service.PolicyViewExpenses, permissions.PermissionViewExpenses)
+				// Drivers add expenses to their reports and change their own;
+				// the service checks whose expense it is
+				addExpenses := middleware.Authorize(policyService, service.PolicyAddExpenses, permissions.PermissionAddExpenses, permissions.PermissionAddReports)
+				editExpenses := middleware.Authorize(policyService, service.PolicyEditExpenses, permissions.PermissionEditExpenses, permissions.PermissionAddExpenses, permissions.PermissionAddReports)
+				deleteExpenses := middleware.Authorize(policyService, service.PolicyDeleteExpenses, permissions.PermissionDeleteExpenses, permissions.PermissionAddExpenses, permissions.PermissionAddReports)
+
+				expenses.GET("", viewExpenses, expenseHandler.List)
+				expenses.GET("/summary", viewExpenses, expenseHandler.Summary)
+				expenses.POST("", addExpenses, idempotent, expenseHandler.Create)
+				expenses.POST("/import", addExpenses, expenseHandler.Import)
+				expenses.POST("/scan", addExpenses, expenseHandler.Scan)
+				expenses.POST("/bulk-delete", deleteExpenses, expenseHandler.BulkDelete)
+				// Owners only, checked by the service
+				expenses.GET("/pending", viewExpenses, expenseHandler.Pending)
+				expenses.GET("/:id", viewExpenses, expenseHandler.Get)
+				expenses.PUT("/:id", editExpenses, expenseHandler.Update)
+				expenses.POST("/:id/approve", viewExpenses, expenseHandler.Approve)
+				expenses.DELETE("/:id", deleteExpenses, expenseHandler.Delete)
+			}
+
+			// Monthly budgets per expense category, with their spending
+			budgets := protected.Group("/budgets")
+			{
+				budgets.GET("", middleware.RequirePermission(permissions.PermissionViewExpenses), budgetHandler.List)
+				budgets.POST("", middleware.RequirePermission(permissions.PermissionEditExpenses), budgetHandler.Create)
+				budgets.PUT("/:id", middleware.RequirePermission(permissions.PermissionEditExpenses), budgetHandler.Update)
+				budgets.DELETE("/:id", middleware.RequirePermission(permissions.PermissionEditExpenses), budgetHandler.Delete)
+			}
+
+			// Traffic fines; those charged to drivers show in their ledger
+			fines := protected.Group("/fines")
+			{
+				viewExpenses := middleware.RequirePermission(permissions.PermissionViewExpenses)
+				addExpenses := middleware.RequirePermission(permissions.PermissionAddExpenses)
+				editExpenses := middleware.RequirePermission(permissions.PermissionEditExpenses)
+				deleteExpenses := middleware.RequirePermission(permissions.PermissionDeleteExpenses)
+
+				fines.GET("", viewExpenses, fineHandler.List)
+				fines.POST("", addExpenses, idempotent, fineHandler.Create)
+				fines.GET("/:id", viewExpenses, fineHandler.Get)
+				fines.PUT("/:id", editExpenses, fineHandler.Update)
+				fines.POST("/:id/pay", editExpenses, fineHandler.MarkPaid)
+				fines.DELETE("/:id", deleteExpenses, fineHandler.Delete)
+			}
+
+			// Fuel cards; the synced transactions become fuel expenses of the card's taxi
+			fuelCards := protected.Group("/fuel-cards")
+			{
+				viewExpenses := middleware.RequirePermission(permissions.PermissionViewExpenses)
+				addExpenses := middleware.RequirePermission(permissions.PermissionAddExpenses)
+				editExpenses := middleware.RequirePermission(permissions.PermissionEditExpenses)
+				deleteExpenses := middleware.RequirePermission(permissions.PermissionDeleteExpenses)
+
+				fuelCards.GET("", viewExpenses, fuelCardHandler.List)
+				fuelCards.POST("", addExpenses, idempotent, fuelCardHandler.Create)
+				fuelCards.GET("/transactions", viewExpenses, fuelCardHandler.Transactions)
+				fuelCards.PUT("/:id", editExpenses, fuelCardHandler.Update)
+				fuelCards.DELETE("/:id", deleteExpenses, fuelCardHandler.Delete)
+			}
+
+			// Driver ledger (advances, fines and repayments); drivers can view their own
+			ledger := protected.Group("/ledger")
+			{
+				ledger.GET("/balances", middleware.RequirePermission(permissions.PermissionViewDeposits), ledgerHandler.ListBalances)
+				ledger.GET("/drivers/:driverId", ledgerHandler.GetDriverLedger)
+				ledger.POST("/entries", middleware.RequirePermission(permissions.PermissionAddDeposits), idempotent, ledgerHandler.RecordEntry)
+			}
+
+			// Maintenance
+			maintenance := protected.Group("/maintenance")
+			{
+				viewTaxis := middleware.RequirePermission(permissions.PermissionViewTaxis)
+				editTaxis := middleware.RequirePermission(permissions.PermissionEditTaxis)
+
+				maintenance.GET("/due", viewTaxis, maintenanceHandler.GetDue)
+				maintenance.GET("/schedules", viewTaxis, maintenanceHandler.ListSchedules)
+				maintenance.POST("/schedules", editTaxis, maintenanceHandler.CreateSchedule)
+				maintenance.GET("/schedules/:id", viewTaxis, maintenanceHandler.GetSchedule)
+				maintenance.PUT("/schedules/:id", editTaxis, maintenanceHandler.UpdateSchedule)
+				maintenance.DELETE("/schedules/:id", editTaxis, maintenanceHandler.DeleteSchedule)
+				maintenance.POST("/schedules/:id/complete", editTaxis, maintenanceHandler.CompleteSchedule)
+				maintenance.GET("/logs", viewTaxis, maintenanceHandler.ListLogs)
+				maintenance.POST("/logs", editTaxis, maintenanceHandler.CreateLog)
+			}
+
+			// Inventory (spare parts)
+			inventory := protected.Group("/inventory")
+			{
+				viewTaxis := middleware.RequirePermission(permissions.PermissionViewTaxis)
+				editTaxis := middleware.RequirePermission(permissions.PermissionEditTaxis)
+
+				inventory.GET("/parts", viewTaxis, inventoryHandler.ListParts)
+				inventory.POST("/parts", editTaxis, inventoryHandler.CreatePart)
+				inventory.GET("/parts/:id", viewTaxis, inventoryHandler.GetPart)
+				inventory.PUT("/parts/:id", editTaxis, inventoryHandler.UpdatePart)
+				inventory.DELETE("/parts/:id", editTaxis, inventoryHandler.DeletePart)
+				inventory.GET("/parts/:id/movements", viewTaxis, inventoryHandler.ListMovements)
+				inventory.POST("/parts/:id/movements", editTaxis, inventoryHandler.RecordMovement)
+				inventory.POST("/consume", editTaxis, inventoryHandler.ConsumeParts)
+				inventory.GET("/low-stock", viewTaxis, inventoryHandler.ListLowStock)
+			}
+
+			// Devices (push notifications)
+			devices := protected.Group("/devices")
+			{
+				devices.POST("", notificationHandler.RegisterDevice)
+				devices.DELETE("/:token", notificationHandler.UnregisterDevice)
+			}
+
+			// Notifications
+			notifications := protected.Group("/notifications")
+			{
+				notifications.GET("", notificationHandler.List)
+				notifications.GET("/unread-count", notificationHandler.UnreadCount)
+				notifications.POST("/:id/read", notificationHandler.MarkRead)
+				notifications.POST("/read-all", notificationHandler.MarkAllRead)
+				notifications.POST("/shift-reminders", notificationHandler.SendShiftReminders)
+			}
+
+			// Weekly digest email (financial data, same audience as the dashboard stats)
+			digest := protected.Group("/digest")
+			digest.Use(middleware.RequirePermission(permissions.PermissionViewDeposits, permissions.PermissionViewExpenses))
+			{
+				digest.GET("/preview", digestHandler.Preview)
+				digest.PUT("/subscription", digestHandler.UpdateSubscription)
 			}
 
-			// Export
+			// Activity feed (includes large expenses, same audience as the dashboard stats)
+			protected.GET("/activity", middleware.RequirePermission(permissions.PermissionViewDeposits, permissions.PermissionViewExpenses), activityHandler.Feed)
+
+			// Export, limited per user and hour and counted against the monthly
+			// export quota of the tenant's plan
 			export := protected.Group("/export")
+			export.Use(middleware.ExportRateLimit(rateCounter, systemSettingService, logger))
+			export.Use(middleware.ExportQuota(planService, logger))
 			{
 				export.GET("/reports", reportHandler.Export)
 				export.GET("/expenses", expenseHandler.Export)
 				export.GET("/deposits", depositHandler.Export)
+				export.GET("/bundle", exportBundleHandler.Create)
 			}
 
+			// Export bundles, generated in the background; polling and
+			// downloading one does not count as another export
+			protected.GET("/export/bundle/:id", exportBundleHandler.Get)
+			protected.GET("/export/bundle/:id/download", exportBundleHandler.Download)
+
+			// Schema of the tenant settings admins write
+			protected.GET("/tenant/settings/schema", adminHandler.TenantSettingsSchema)
+
 			// Admin routes (admin only)
 			admin := protected.Group("/admin")
 			admin.Use(adminHandler.RequireAdmin)
@@ -270,6 +1030,15 @@ func setupRouter(
 					tenants.GET("", adminHandler.GetAllTenants)
 					tenants.POST("", adminHandler.CreateTenant)
 					tenants.GET("/:id", adminHandler.GetTenant)
+					tenants.GET("/:id/stats", adminHandler.GetTenantStats)
+					tenants.POST("/:id/export", tenantExportHandler.Create)
+					tenants.POST("/:id/demo-data", demoHandler.Generate)
+					tenants.GET("/:id/policies", policyHandler.List)
+					tenants.POST("/:id/policies", policyHandler.Create)
+					tenants.PUT("/:id/policies/:policyId", policyHandler.Update)
+					tenants.DELETE("/:id/policies/:policyId", policyHandler.Delete)
+					tenants.GET("/:id/retention", retentionHandler.Preview)
+					tenants.DELETE("/:id/periods/:month", periodHandler.Reopen)
 					tenants.PUT("/:id", adminHandler.UpdateTenant)
 					tenants.DELETE("/:id", adminHandler.DeleteTenant)
 				}
@@ -281,12 +1050,81 @@ func setupRouter(
 					users.POST("", adminHandler.CreateUser)
 					users.GET("/tenant/:tenantId", adminHandler.GetUsersByTenant)
 					users.GET("/:id", adminHandler.GetUser)
+					users.GET("/:id/login-history", adminHandler.GetUserLoginHistory)
 					users.PUT("/:id", adminHandler.UpdateUser)
 					users.DELETE("/:id", adminHandler.DeleteUser)
 				}
+
+				// Background jobs
+				jobs := admin.Group("/jobs")
+				{
+					jobs.GET("", jobHandler.List)
+					jobs.GET("/runs", jobHandler.Runs)
+				}
+
+				// Subscription plans limiting taxis, users and exports of their tenants
+				plans := admin.Group("/plans")
+				{
+					plans.GET("", planHandler.List)
+					plans.POST("", planHandler.Create)
+					plans.GET("/:id", planHandler.Get)
+					plans.PUT("/:id", planHandler.Update)
+					plans.DELETE("/:id", planHandler.Delete)
+				}
+
+				// Runtime-tunable platform settings and their change history
+				settings := admin.Group("/settings")
+				{
+					settings.GET("", systemSettingHandler.List)
+					settings.GET("/changes", systemSettingHandler.Changes)
+					settings.PUT("/:key", systemSettingHandler.Update)
+					settings.DELETE("/:key", systemSettingHandler.Reset)
+				}
+
+				// API keys for server-to-server access
+				apiKeys := admin.Group("/api-keys")
+				{
+					apiKeys.GET("", apiKeyHandler.List)
+					apiKeys.POST("", apiKeyHandler.Create)
+					apiKeys.DELETE("/:id", apiKeyHandler.Revoke)
+				}
+
+				// Slowest database queries, from pg_stat_statements
+				admin.GET("/database/slow-queries", adminHandler.GetSlowQueries)
+
+				// Tenant data export downloads
+				admin.GET("/exports/:token", tenantExportHandler.Download)
+
+				// Announcements to the notification inbox of every user
+				admin.POST("/announcements", notificationHandler.Announce)
 			}
 		}
 	}
 
+	// API v2 routes: the v1 handlers and services, responding with the v2
+	// DTOs. Routes are added here as their resources change; the v1 routes
+	// they replace are marked deprecated.
+	v2 := router.Group("/api/v2")
+	v2.Use(middleware.APIVersion(2))
+	v2.Use(middleware.Auth(authService, apiKeyService, logger))
+	v2.Use(middleware.APIKeyRateLimit(rateCounter, planService, logger))
+	if cfg.Database.RowLevelSecurity {
+		v2.Use(middleware.TenantIsolation(transactor, logger))
+	}
+	v2.Use(middleware.Fields())
+	{
+		idempotent := middleware.Idempotency(idempotencyService, logger)
+
+		// Taxis, with the assigned driver reduced to its ID and name
+		taxis := v2.Group("/taxis")
+		{
+			taxis.GET("", viewTaxis, taxiHandler.List)
+			taxis.POST("", addTaxis, idempotent, taxiHandler.Create)
+			taxis.GET("/:id", viewTaxis, taxiHandler.Get)
+			taxis.PUT("/:id", editTaxis, taxiHandler.Update)
+			taxis.DELETE("/:id", deleteTaxis, taxiHandler.Delete)
+		}
+	}
+
 	return router
 }
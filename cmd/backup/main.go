@@ -0,0 +1,107 @@
+// Command backup dumps the database, or one tenant, into the file storage
+// and restores dumps into a staging database. The database, storage and
+// PostgreSQL client tools are configured from the environment like the API.
+//
+//	backup                 dump the whole database with pg_dump
+//	backup -tenant 5       dump one tenant's rows as a SQL script
+//	backup -list           list the stored backups, newest first
+//	backup -restore NAME   restore a backup into BACKUP_RESTORE_DATABASE_URL
+//	backup -delete NAME    remove a backup
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"taxifleet/backend/internal/config"
+	"taxifleet/backend/internal/database"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/service"
+	"taxifleet/backend/internal/storage"
+
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	tenantID := flag.Uint("tenant", 0, "dump only the tenant with this ID")
+	list := flag.Bool("list", false, "list the stored backups")
+	restore := flag.String("restore", "", "name of the backup to restore into the staging database")
+	remove := flag.String("delete", "", "name of the backup to delete")
+	flag.Parse()
+
+	// Initialize logger
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.JSONFormatter{})
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to load configuration")
+	}
+
+	fileStore, err := storage.New(cfg.Storage.Driver, cfg.Storage)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to initialize file storage")
+	}
+
+	// Initialize database
+	db, err := database.New(&cfg.Database, logger)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to initialize database")
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			logger.WithError(err).Error("Failed to close database connection")
+		}
+	}()
+
+	repo := repository.New(db.GetDB())
+	backups := service.NewBackupService(repo, database.NewPgDumper(&cfg.Database, cfg.Backup), fileStore, cfg.Backup)
+	ctx := context.Background()
+
+	switch {
+	case *list:
+		all, err := backups.List(ctx)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to list backups")
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tTENANT\tCREATED")
+		for _, backup := range all {
+			tenant := "-"
+			if backup.TenantID != 0 {
+				tenant = fmt.Sprint(backup.TenantID)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", backup.Name, tenant, backup.CreatedAt.Format(time.RFC3339))
+		}
+		w.Flush()
+
+	case *restore != "":
+		if err := backups.Restore(ctx, *restore); err != nil {
+			logger.WithError(err).WithField("backup", *restore).Fatal("Failed to restore backup")
+		}
+		logger.WithField("backup", *restore).Info("Backup restored")
+
+	case *remove != "":
+		if err := backups.Delete(ctx, *remove); err != nil {
+			logger.WithError(err).WithField("backup", *remove).Fatal("Failed to delete backup")
+		}
+		logger.WithField("backup", *remove).Info("Backup deleted")
+
+	default:
+		var backup *service.Backup
+		if *tenantID != 0 {
+			backup, err = backups.BackupTenant(ctx, *tenantID)
+		} else {
+			backup, err = backups.BackupDatabase(ctx)
+		}
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to back up")
+		}
+		logger.WithField("backup", backup.Name).Info("Backup stored")
+	}
+}
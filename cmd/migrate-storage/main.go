@@ -0,0 +1,109 @@
+// Command migrate-storage copies the stored files of every tenant from one
+// storage driver to another, e.g. before switching STORAGE_DRIVER from local
+// to s3. Both stores are configured from the environment like the API.
+//
+//	migrate-storage -from local -to s3 [-delete]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"mime"
+	"path"
+
+	"taxifleet/backend/internal/config"
+	"taxifleet/backend/internal/database"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/storage"
+
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	from := flag.String("from", "local", "storage driver to copy files from")
+	to := flag.String("to", "s3", "storage driver to copy files to")
+	deleteCopied := flag.Bool("delete", false, "delete each file from the source once copied")
+	flag.Parse()
+
+	// Initialize logger
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.JSONFormatter{})
+
+	if *from == *to {
+		logger.Fatal("Source and destination drivers must differ")
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to load configuration")
+	}
+
+	source, err := storage.New(*from, cfg.Storage)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to initialize source storage")
+	}
+	destination, err := storage.New(*to, cfg.Storage)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to initialize destination storage")
+	}
+
+	// Initialize database
+	db, err := database.New(&cfg.Database, logger)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to initialize database")
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			logger.WithError(err).Error("Failed to close database connection")
+		}
+	}()
+
+	repo := repository.New(db.GetDB())
+	ctx := context.Background()
+
+	// Files are kept under their tenant's ID, so tenants are migrated one by one
+	tenants, err := repo.GetAllTenants(ctx)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to list tenants")
+	}
+
+	copied := 0
+	for _, tenant := range tenants {
+		err := source.Walk(ctx, fmt.Sprintf("%d/", tenant.ID), func(key string) error {
+			if err := copyFile(ctx, source, destination, key); err != nil {
+				return fmt.Errorf("failed to copy %s: %w", key, err)
+			}
+			if *deleteCopied {
+				if err := source.Delete(ctx, key); err != nil {
+					return fmt.Errorf("failed to delete %s: %w", key, err)
+				}
+			}
+			copied++
+			return nil
+		})
+		if err != nil {
+			logger.WithError(err).WithField("tenant_id", tenant.ID).Fatal("Failed to migrate files")
+		}
+		logger.WithField("tenant_id", tenant.ID).Info("Migrated tenant files")
+	}
+
+	logger.WithFields(logrus.Fields{"from": *from, "to": *to, "files": copied}).Info("Storage migration complete")
+}
+
+// copyFile writes the file under the key to the destination, with the content
+// type of its extension
+func copyFile(ctx context.Context, source, destination storage.Store, key string) error {
+	file, err := source.Open(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return destination.Put(ctx, key, file, contentType)
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
@@ -41,9 +42,10 @@ func main() {
 
 	// Initialize repository
 	repo := repository.New(db.GetDB())
+	ctx := context.Background()
 
 	// Check if tenant already exists
-	tenant, err := repo.GetTenantBySubdomain("gnakpa-transport")
+	tenant, err := repo.GetTenantBySubdomain(ctx, "gnakpa-transport")
 	if err == nil {
 		// No error means tenant was found
 		logger.Info("Default tenant already exists, skipping seed")
@@ -65,7 +67,7 @@ func main() {
 		Subdomain: "gnakpa-transport",
 		Settings:  "{}", // Valid JSON for JSONB column
 	}
-	if err := repo.CreateTenant(tenant); err != nil {
+	if err := repo.CreateTenant(ctx, tenant); err != nil {
 		logger.WithError(err).Fatal("Failed to create tenant")
 	}
 	logger.Info("Created tenant: Gnakpa Transport")
@@ -107,7 +109,7 @@ func main() {
 			Active:       true,
 		}
 
-		if err := repo.CreateUser(user); err != nil {
+		if err := repo.CreateUser(ctx, user); err != nil {
 			logger.WithError(err).Warnf("Failed to create user %s (may already exist)", u.Email)
 		} else {
 			logger.Infof("Created user: %s %s (%s, permission: %d)", u.FirstName, u.LastName, permissions.GetRoleName(u.Permission), u.Permission)
@@ -139,7 +141,7 @@ func main() {
 			Status:       t.Status,
 		}
 
-		if err := repo.CreateTaxi(taxi); err != nil {
+		if err := repo.CreateTaxi(ctx, taxi); err != nil {
 			logger.WithError(err).Warnf("Failed to create taxi %s (may already exist)", t.LicensePlate)
 		} else {
 			logger.Infof("Created taxi: %s", t.LicensePlate)
@@ -0,0 +1,46 @@
+// Package antivirus scans uploaded files for malware with a ClamAV daemon or
+// an ICAP server before they can be downloaded.
+package antivirus
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"taxifleet/backend/internal/config"
+)
+
+// Verdict is the outcome of a scan
+type Verdict struct {
+	Infected bool
+	Threat   string // Name of the malware found, when the scanner reports it
+}
+
+// Scanner scans a file. An error means the file could not be scanned, not
+// that it is infected.
+type Scanner interface {
+	Scan(ctx context.Context, file io.Reader) (Verdict, error)
+}
+
+// New returns the scanner of the configured driver, "clamav" or "icap"
+func New(cfg config.AntivirusConfig) (Scanner, error) {
+	switch cfg.Driver {
+	case "clamav":
+		return NewClamAV(cfg.ClamAVAddress, cfg.Timeout), nil
+	case "icap":
+		return NewICAP(cfg.ICAPURL, cfg.Timeout)
+	default:
+		return nil, fmt.Errorf("unsupported antivirus driver %q", cfg.Driver)
+	}
+}
+
+// deadline is when a scan started now must be done, the earlier of the
+// context's deadline and the timeout
+func deadline(ctx context.Context, timeout time.Duration) time.Time {
+	d := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(d) {
+		return ctxDeadline
+	}
+	return d
+}
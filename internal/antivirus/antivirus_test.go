@@ -0,0 +1,134 @@
+package antivirus
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+// serve answers one connection of a fake scanner with reply, once handle has
+// read the request
+func serve(t *testing.T, handle func(r *bufio.Reader) string) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		io.WriteString(conn, handle(bufio.NewReader(conn)))
+	}()
+	return listener.Addr().String()
+}
+
+// clamd reads an INSTREAM request and answers for the streamed file
+func clamd(received *bytes.Buffer) func(r *bufio.Reader) string {
+	return func(r *bufio.Reader) string {
+		if command, _ := r.ReadString(0); command != "zINSTREAM\x00" {
+			return "UNKNOWN COMMAND\x00"
+		}
+		for {
+			var size uint32
+			if err := binary.Read(r, binary.BigEndian, &size); err != nil || size == 0 {
+				break
+			}
+			io.CopyN(received, r, int64(size))
+		}
+		if strings.Contains(received.String(), "EICAR") {
+			return "stream: Eicar-Test-Signature FOUND\x00"
+		}
+		return "stream: OK\x00"
+	}
+}
+
+func TestClamAVScan(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		want Verdict
+	}{
+		{"clean", strings.Repeat("photo", chunkSize/4), Verdict{}},
+		{"infected", "X5O!P%@AP[4\\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*", Verdict{Infected: true, Threat: "Eicar-Test-Signature"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received bytes.Buffer
+			scanner := NewClamAV(serve(t, clamd(&received)), 5*time.Second)
+
+			got, err := scanner.Scan(context.Background(), strings.NewReader(tt.file))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %+v, got %+v", tt.want, got)
+			}
+			if received.String() != tt.file {
+				t.Fatalf("expected clamd to receive the whole file, got %d of %d bytes", received.Len(), len(tt.file))
+			}
+		})
+	}
+}
+
+func TestClamAVScanError(t *testing.T) {
+	scanner := NewClamAV(serve(t, func(r *bufio.Reader) string {
+		return "INSTREAM size limit exceeded. ERROR\x00"
+	}), 5*time.Second)
+
+	if _, err := scanner.Scan(context.Background(), strings.NewReader("photo")); err == nil {
+		t.Fatal("expected a clamd error to fail the scan")
+	}
+}
+
+func TestICAPScan(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply string
+		want  Verdict
+	}{
+		{"clean", "ICAP/1.0 204 No Content\r\n\r\n", Verdict{}},
+		{
+			"infected",
+			"ICAP/1.0 200 OK\r\nX-Infection-Found: Type=0; Resolution=2; Threat=Eicar-Test-Signature;\r\nEncapsulated: res-hdr=0, null-body=19\r\n\r\nHTTP/1.1 403 Forbidden\r\n\r\n",
+			Verdict{Infected: true, Threat: "Eicar-Test-Signature"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var method string
+			addr := serve(t, func(r *bufio.Reader) string {
+				reader := textproto.NewReader(r)
+				method, _ = reader.ReadLine()
+				reader.ReadMIMEHeader()
+				return tt.reply
+			})
+			scanner, err := NewICAP("icap://"+addr+"/avscan", 5*time.Second)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got, err := scanner.Scan(context.Background(), strings.NewReader("photo"))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %+v, got %+v", tt.want, got)
+			}
+			if method != "RESPMOD icap://"+addr+"/avscan ICAP/1.0" {
+				t.Fatalf("unexpected request line %q", method)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package antivirus
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// chunkSize is the size of the chunks a file is streamed to clamd in
+const chunkSize = 64 << 10
+
+// ClamAV scans files with a clamd daemon, streaming them with its INSTREAM
+// command over TCP
+type ClamAV struct {
+	address string
+	timeout time.Duration
+}
+
+var _ Scanner = (*ClamAV)(nil)
+
+// NewClamAV creates a scanner for the clamd listening at address (host:port)
+func NewClamAV(address string, timeout time.Duration) *ClamAV {
+	return &ClamAV{address: address, timeout: timeout}
+}
+
+func (c *ClamAV) Scan(ctx context.Context, file io.Reader) (Verdict, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", c.address)
+	if err != nil {
+		return Verdict{}, fmt.Errorf("failed to connect to clamd: %w", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(deadline(ctx, c.timeout))
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return Verdict{}, fmt.Errorf("failed to send to clamd: %w", err)
+	}
+	// Each chunk is preceded by its length; an empty chunk ends the stream
+	chunk := make([]byte, 4+chunkSize)
+	for {
+		n, err := io.ReadFull(file, chunk[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(chunk, uint32(n))
+			if _, err := conn.Write(chunk[:4+n]); err != nil {
+				return Verdict{}, fmt.Errorf("failed to send to clamd: %w", err)
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return Verdict{}, err
+		}
+	}
+	if _, err := conn.Write([]byte{0, 0, 0, 0}); err != nil {
+		return Verdict{}, fmt.Errorf("failed to send to clamd: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil {
+		return Verdict{}, fmt.Errorf("failed to read clamd reply: %w", err)
+	}
+	return parseClamdReply(strings.TrimSuffix(reply, "\x00"))
+}
+
+// parseClamdReply reads "stream: OK", "stream: <threat> FOUND" or
+// "<message> ERROR"
+func parseClamdReply(reply string) (Verdict, error) {
+	result := strings.TrimPrefix(reply, "stream: ")
+	switch {
+	case result == "OK":
+		return Verdict{}, nil
+	case strings.HasSuffix(result, " FOUND"):
+		return Verdict{Infected: true, Threat: strings.TrimSuffix(result, " FOUND")}, nil
+	default:
+		return Verdict{}, fmt.Errorf("clamd: %s", reply)
+	}
+}
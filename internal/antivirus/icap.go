@@ -0,0 +1,109 @@
+package antivirus
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ICAP scans files with an ICAP server (RFC 3507), such as c-icap with
+// ClamAV or a commercial antivirus gateway, sending each file as the body of
+// a RESPMOD request
+type ICAP struct {
+	url     *url.URL
+	timeout time.Duration
+}
+
+var _ Scanner = (*ICAP)(nil)
+
+// NewICAP creates a scanner for the ICAP service at rawURL, e.g.
+// icap://icap:1344/avscan
+func NewICAP(rawURL string, timeout time.Duration) (*ICAP, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "icap" || u.Host == "" {
+		return nil, fmt.Errorf("invalid ICAP URL %q", rawURL)
+	}
+	if u.Port() == "" {
+		u.Host = net.JoinHostPort(u.Hostname(), "1344")
+	}
+	return &ICAP{url: u, timeout: timeout}, nil
+}
+
+func (s *ICAP) Scan(ctx context.Context, file io.Reader) (Verdict, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", s.url.Host)
+	if err != nil {
+		return Verdict{}, fmt.Errorf("failed to connect to ICAP server: %w", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(deadline(ctx, s.timeout))
+
+	// The file is the body of an HTTP response the server may modify; with
+	// Allow: 204 a clean file is answered with 204 No Content
+	httpHeader := "HTTP/1.1 200 OK\r\nContent-Type: application/octet-stream\r\n\r\n"
+	w := bufio.NewWriter(conn)
+	fmt.Fprintf(w, "RESPMOD %s ICAP/1.0\r\n", s.url)
+	fmt.Fprintf(w, "Host: %s\r\n", s.url.Host)
+	fmt.Fprintf(w, "Allow: 204\r\n")
+	fmt.Fprintf(w, "Encapsulated: res-hdr=0, res-body=%d\r\n\r\n", len(httpHeader))
+	w.WriteString(httpHeader)
+
+	chunk := make([]byte, chunkSize)
+	for {
+		n, err := io.ReadFull(file, chunk)
+		if n > 0 {
+			fmt.Fprintf(w, "%x\r\n", n)
+			w.Write(chunk[:n])
+			w.WriteString("\r\n")
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return Verdict{}, err
+		}
+	}
+	w.WriteString("0\r\n\r\n")
+	if err := w.Flush(); err != nil {
+		return Verdict{}, fmt.Errorf("failed to send to ICAP server: %w", err)
+	}
+
+	reader := textproto.NewReader(bufio.NewReader(conn))
+	status, err := reader.ReadLine()
+	if err != nil {
+		return Verdict{}, fmt.Errorf("failed to read ICAP response: %w", err)
+	}
+	header, err := reader.ReadMIMEHeader()
+	if err != nil && err != io.EOF {
+		return Verdict{}, fmt.Errorf("failed to read ICAP response: %w", err)
+	}
+
+	_, code, _ := strings.Cut(status, " ")
+	code, _, _ = strings.Cut(code, " ")
+	switch code {
+	case "204":
+		return Verdict{}, nil
+	case "200":
+		// The server replaced the file, e.g. with a block page
+		return Verdict{Infected: true, Threat: icapThreat(header)}, nil
+	default:
+		return Verdict{}, fmt.Errorf("ICAP server returned %q", status)
+	}
+}
+
+// icapThreat returns the name of the malware in the headers servers report
+// it in, e.g. X-Infection-Found: Type=0; Resolution=2; Threat=Eicar-Test;
+func icapThreat(header textproto.MIMEHeader) string {
+	for _, field := range strings.Split(header.Get("X-Infection-Found"), ";") {
+		if name, value, ok := strings.Cut(strings.TrimSpace(field), "="); ok && name == "Threat" {
+			return value
+		}
+	}
+	return header.Get("X-Virus-ID")
+}
@@ -0,0 +1,194 @@
+// Package apierror defines the error envelope every API error response uses:
+//
+//	{"error": {"code": "not_found", "message": "taxi not found", "details": ..., "request_id": "..."}}
+//
+// Codes are stable and meant for clients to branch on; messages are for humans.
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"taxifleet/backend/internal/validation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Error is an API error with its HTTP status and machine-readable code
+type Error struct {
+	Status  int
+	Code    string
+	Message string
+	Details interface{}
+
+	cause error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// WithDetails returns a copy of the error carrying additional details
+func (e *Error) WithDetails(details interface{}) *Error {
+	copied := *e
+	copied.Details = details
+	return &copied
+}
+
+func New(status int, code, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+func BadRequest(message string) *Error {
+	return New(http.StatusBadRequest, "bad_request", message)
+}
+
+func Unauthorized(message string) *Error {
+	return New(http.StatusUnauthorized, "unauthorized", message)
+}
+
+func Forbidden(message string) *Error {
+	return New(http.StatusForbidden, "forbidden", message)
+}
+
+func NotFound(message string) *Error {
+	return New(http.StatusNotFound, "not_found", message)
+}
+
+func Conflict(code, message string) *Error {
+	return New(http.StatusConflict, code, message)
+}
+
+// Internal hides err from the client; Abort attaches it to the request so it
+// is still logged
+func Internal(err error) *Error {
+	return &Error{
+		Status:  http.StatusInternalServerError,
+		Code:    "internal_error",
+		Message: "Internal server error",
+		cause:   err,
+	}
+}
+
+// FieldError describes one invalid field of a request
+type FieldError struct {
+	Field string `json:"field"`
+	Rule  string `json:"rule"`
+	Param string `json:"param,omitempty"`
+}
+
+// Validation turns a request binding error into a validation error listing
+// the invalid fields
+func Validation(err error) *Error {
+	apiErr := New(http.StatusBadRequest, "validation_failed", err.Error())
+	apiErr.cause = err
+
+	// A JSON value of the wrong type never reaches the validator
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		apiErr.Message = "Invalid value for " + typeErr.Field
+		apiErr.Details = []FieldError{{Field: typeErr.Field, Rule: "type", Param: typeErr.Type.String()}}
+		return apiErr
+	}
+
+	// Text refused by the sanitizer before validation, e.g. too long
+	var fieldErr *validation.FieldError
+	if errors.As(err, &fieldErr) {
+		apiErr.Message = fieldErr.Message
+		apiErr.Details = []FieldError{{Field: fieldErr.Field, Rule: fieldErr.Rule}}
+		return apiErr
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return apiErr
+	}
+
+	fields := make([]FieldError, 0, len(validationErrors))
+	names := make([]string, 0, len(validationErrors))
+	for _, fe := range validationErrors {
+		fields = append(fields, FieldError{Field: fe.Field(), Rule: fe.Tag(), Param: fe.Param()})
+		names = append(names, fe.Field())
+	}
+	apiErr.Message = "Invalid value for " + strings.Join(names, ", ")
+	apiErr.Details = fields
+	return apiErr
+}
+
+// CodeForStatus returns the generic code of an HTTP error status
+func CodeForStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return "bad_request"
+	case http.StatusUnauthorized:
+		return "unauthorized"
+	case http.StatusForbidden:
+		return "forbidden"
+	case http.StatusNotFound:
+		return "not_found"
+	case http.StatusConflict:
+		return "conflict"
+	case http.StatusUnprocessableEntity:
+		return "unprocessable_entity"
+	case http.StatusTooManyRequests:
+		return "rate_limited"
+	case http.StatusServiceUnavailable:
+		return "unavailable"
+	case http.StatusGatewayTimeout:
+		return "timeout"
+	}
+	if status >= http.StatusInternalServerError {
+		return "internal_error"
+	}
+	return "error"
+}
+
+type body struct {
+	Code      string      `json:"code"`
+	Message   string      `json:"message"`
+	Details   interface{} `json:"details,omitempty"`
+	RequestID string      `json:"request_id,omitempty"`
+}
+
+// Abort writes err in the error envelope and stops the handler chain
+func Abort(c *gin.Context, err *Error) {
+	if err.cause != nil {
+		c.Error(err.cause)
+	}
+	c.AbortWithStatusJSON(err.Status, gin.H{"error": body{
+		Code:      err.Code,
+		Message:   err.Message,
+		Details:   err.Details,
+		RequestID: c.GetString("requestID"),
+	}})
+}
+
+// UseJSONFieldNames makes validation errors name fields as they appear in
+// request bodies instead of by their Go struct field
+func UseJSONFieldNames() {
+	validate, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "form"} {
+			name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return field.Name
+	})
+}
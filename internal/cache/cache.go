@@ -0,0 +1,142 @@
+// Package cache provides an optional per-tenant cache for expensive reads.
+//
+// Every key lives under a tenant version number. Invalidating a tenant bumps
+// its version, so all of its entries become unreachable at once and simply
+// expire with their TTL.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"taxifleet/backend/internal/logging"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// Cache stores JSON-encoded values. Implementations never fail the caller:
+// errors are logged and treated as a cache miss.
+type Cache interface {
+	// Version returns the current version of the tenant's entries, or false
+	// when the cache is unavailable
+	Version(ctx context.Context, tenantID uint) (int64, bool)
+	Get(ctx context.Context, key string, dest interface{}) bool
+	Set(ctx context.Context, key string, value interface{})
+	Invalidate(ctx context.Context, tenantID uint)
+}
+
+// Remember returns the cached value of a tenant entry, or loads and caches it.
+// The version is read before loading so a write that invalidates the tenant
+// meanwhile cannot leave a stale value reachable.
+func Remember[T any](ctx context.Context, c Cache, tenantID uint, name string, load func() (T, error)) (T, error) {
+	version, ok := c.Version(ctx, tenantID)
+	if !ok {
+		return load()
+	}
+	key := fmt.Sprintf("tenant:%d:v%d:%s", tenantID, version, name)
+
+	var cached T
+	if c.Get(ctx, key, &cached) {
+		return cached, nil
+	}
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+	c.Set(ctx, key, value)
+	return value, nil
+}
+
+// Noop is used when caching is disabled
+type Noop struct{}
+
+func (Noop) Version(ctx context.Context, tenantID uint) (int64, bool)   { return 0, false }
+func (Noop) Get(ctx context.Context, key string, dest interface{}) bool { return false }
+func (Noop) Set(ctx context.Context, key string, value interface{})     {}
+func (Noop) Invalidate(ctx context.Context, tenantID uint)              {}
+
+// Redis is a Cache backed by a Redis server
+type Redis struct {
+	client *redis.Client
+	ttl    time.Duration
+	logger *logrus.Logger
+}
+
+// Connect connects to Redis and verifies the connection. The client is
+// shared by the cache, the session store and the rate limit counters.
+func Connect(addr, password string, db int) (*redis.Client, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       db,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+
+	return client, nil
+}
+
+// NewRedis returns a cache on the client, which stays owned by the caller
+func NewRedis(client *redis.Client, ttl time.Duration, logger *logrus.Logger) *Redis {
+	return &Redis{client: client, ttl: ttl, logger: logger}
+}
+
+func versionKey(tenantID uint) string {
+	return fmt.Sprintf("tenant:%d:version", tenantID)
+}
+
+func (r *Redis) Version(ctx context.Context, tenantID uint) (int64, bool) {
+	version, err := r.client.Get(ctx, versionKey(tenantID)).Int64()
+	if err == redis.Nil {
+		return 0, true
+	}
+	if err != nil {
+		logging.Entry(ctx, r.logger).WithError(err).WithField("tenant_id", tenantID).Warn("Cache version lookup failed")
+		return 0, false
+	}
+	return version, true
+}
+
+func (r *Redis) Get(ctx context.Context, key string, dest interface{}) bool {
+	data, err := r.client.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return false
+	}
+	if err != nil {
+		logging.Entry(ctx, r.logger).WithError(err).WithField("key", key).Warn("Cache read failed")
+		return false
+	}
+
+	if err := json.Unmarshal(data, dest); err != nil {
+		logging.Entry(ctx, r.logger).WithError(err).WithField("key", key).Warn("Cache entry could not be decoded")
+		return false
+	}
+	return true
+}
+
+func (r *Redis) Set(ctx context.Context, key string, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		logging.Entry(ctx, r.logger).WithError(err).WithField("key", key).Warn("Cache entry could not be encoded")
+		return
+	}
+
+	if err := r.client.Set(ctx, key, data, r.ttl).Err(); err != nil {
+		logging.Entry(ctx, r.logger).WithError(err).WithField("key", key).Warn("Cache write failed")
+	}
+}
+
+func (r *Redis) Invalidate(ctx context.Context, tenantID uint) {
+	if err := r.client.Incr(ctx, versionKey(tenantID)).Err(); err != nil {
+		logging.Entry(ctx, r.logger).WithError(err).WithField("tenant_id", tenantID).Error("Cache invalidation failed")
+	}
+}
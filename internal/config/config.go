@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -18,6 +19,24 @@ type Config struct {
 	Permissions PermissionsConfig `json:"permissions"`
 	Security    SecurityConfig    `json:"security"`
 	Logging     LoggingConfig     `json:"logging"`
+	Push        PushConfig        `json:"push"`
+	Maintenance MaintenanceConfig `json:"maintenance"`
+	Cache       CacheConfig       `json:"cache"`
+	Session     SessionConfig     `json:"session"`
+	Scheduler   SchedulerConfig   `json:"scheduler"`
+	Tracing     TracingConfig     `json:"tracing"`
+	Metrics     MetricsConfig     `json:"metrics"`
+	Sentry      SentryConfig      `json:"sentry"`
+	DataExport  DataExportConfig  `json:"data_export"`
+	Attachments AttachmentConfig  `json:"attachments"`
+	Storage     StorageConfig     `json:"storage"`
+	Antivirus   AntivirusConfig   `json:"antivirus"`
+	Backup      BackupConfig      `json:"backup"`
+	OAuth       OAuthConfig       `json:"oauth"`
+	OCR         OCRConfig         `json:"ocr"`
+	Mail        MailConfig        `json:"mail"`
+	FuelCard    FuelCardConfig    `json:"fuel_card"`
+	Onboarding  OnboardingConfig  `json:"onboarding"`
 }
 
 // ServerConfig holds server-related configuration
@@ -30,6 +49,11 @@ type ServerConfig struct {
 	ShutdownTimeout time.Duration `json:"shutdown_timeout"`
 	Environment     string        `json:"environment"`
 	Version         string        `json:"version"`
+	// V1Sunset is when the v1 endpoints replaced in v2 will be removed,
+	// announced in their Sunset header; zero while undecided
+	V1Sunset time.Time `json:"v1_sunset"`
+	// Pprof serves the runtime profiles to admins under /debug/pprof
+	Pprof bool `json:"pprof"`
 }
 
 // DatabaseConfig holds database-related configuration
@@ -45,6 +69,9 @@ type DatabaseConfig struct {
 	ConnMaxLifetime time.Duration `json:"conn_max_lifetime"`
 	ConnMaxIdleTime time.Duration `json:"conn_max_idle_time"`
 	MigrationPath   string        `json:"migration_path"`
+	// RowLevelSecurity runs each tenant user's request in a transaction
+	// scoped to their tenant, so the row-level security policies apply
+	RowLevelSecurity bool `json:"row_level_security"`
 }
 
 // JWTConfig holds JWT-related configuration
@@ -52,6 +79,20 @@ type JWTConfig struct {
 	Secret            string        `json:"secret"`
 	Expiration        time.Duration `json:"expiration"`
 	RefreshExpiration time.Duration `json:"refresh_expiration"`
+	// How long a user's active status, tenant and permission are trusted
+	// before access tokens are checked against the database again
+	UserCheckInterval time.Duration `json:"user_check_interval"`
+
+	// Asymmetric signing; Algorithm is HS256 (uses Secret), RS256 or EdDSA
+	Algorithm      string `json:"algorithm"`
+	PrivateKeyFile string `json:"-"`      // PEM private key for RS256/EdDSA
+	KeyID          string `json:"key_id"` // kid of the current key
+
+	// The key used before the last rotation keeps validating tokens until
+	// PreviousKeyUntil so sessions survive the rotation
+	PreviousPublicKeyFile string    `json:"-"`
+	PreviousKeyID         string    `json:"previous_key_id"`
+	PreviousKeyUntil      time.Time `json:"previous_key_until"`
 }
 
 // PermissionsConfig holds permission masks for roles
@@ -65,12 +106,17 @@ type PermissionsConfig struct {
 
 // SecurityConfig holds security-related configuration
 type SecurityConfig struct {
-	BCryptCost         int      `json:"bcrypt_cost"`
-	RateLimitRPS       int      `json:"rate_limit_rps"`
-	RateLimitBurst     int      `json:"rate_limit_burst"`
-	CORSAllowedOrigins []string `json:"cors_allowed_origins"`
-	CORSAllowedMethods []string `json:"cors_allowed_methods"`
-	CORSAllowedHeaders []string `json:"cors_allowed_headers"`
+	BCryptCost              int      `json:"bcrypt_cost"`
+	RateLimitRPS            int      `json:"rate_limit_rps"`
+	RateLimitBurst          int      `json:"rate_limit_burst"`
+	RateLimitStore          string   `json:"rate_limit_store"` // "memory" (per instance) or "redis" (shared, using the cache's Redis settings)
+	RateLimitExportsPerHour int      `json:"rate_limit_exports_per_hour"`
+	RateLimitAPIKeyPerHour  int      `json:"rate_limit_api_key_per_hour"` // For API keys of tenants without a plan
+	TextMaxLength           int      `json:"text_max_length"`             // Characters of request text fields without their own limit
+	EscapeHTML              bool     `json:"escape_html"`                 // Store request text HTML-escaped
+	CORSAllowedOrigins      []string `json:"cors_allowed_origins"`
+	CORSAllowedMethods      []string `json:"cors_allowed_methods"`
+	CORSAllowedHeaders      []string `json:"cors_allowed_headers"`
 }
 
 // LoggingConfig holds logging configuration
@@ -84,6 +130,186 @@ type LoggingConfig struct {
 	Compress   bool   `json:"compress"`
 }
 
+// PushConfig holds push notification (FCM) configuration
+type PushConfig struct {
+	Enabled        bool   `json:"enabled"`
+	FCMProjectID   string `json:"fcm_project_id"`
+	FCMCredentials string `json:"-"` // Path to the service account JSON file
+}
+
+// MaintenanceConfig holds preventive maintenance configuration
+type MaintenanceConfig struct {
+	DueCheckInterval time.Duration `json:"due_check_interval"`
+}
+
+// CacheConfig holds the optional Redis cache configuration
+type CacheConfig struct {
+	Enabled       bool          `json:"enabled"`
+	RedisAddr     string        `json:"redis_addr"`
+	RedisPassword string        `json:"-"`
+	RedisDB       int           `json:"redis_db"`
+	TTL           time.Duration `json:"ttl"`
+}
+
+// SessionConfig selects where refresh token sessions are stored:
+// "postgres" or "redis" (shared by all instances, using the cache's Redis settings)
+type SessionConfig struct {
+	Store string `json:"store"`
+}
+
+// SchedulerConfig holds background job configuration
+type SchedulerConfig struct {
+	Enabled                bool   `json:"enabled"`
+	SessionCleanupSchedule string `json:"session_cleanup_schedule"` // Cron expression
+	WeeklyDigestSchedule   string `json:"weekly_digest_schedule"`   // Cron expression
+	FuelCardSyncSchedule   string `json:"fuel_card_sync_schedule"`  // Cron expression
+	DataRetentionSchedule  string `json:"data_retention_schedule"`  // Cron expression
+}
+
+// TracingConfig holds OpenTelemetry tracing configuration
+type TracingConfig struct {
+	Enabled      bool    `json:"enabled"`
+	OTLPEndpoint string  `json:"otlp_endpoint"` // host:port of the OTLP/HTTP collector
+	OTLPInsecure bool    `json:"otlp_insecure"`
+	ServiceName  string  `json:"service_name"`
+	SampleRatio  float64 `json:"sample_ratio"` // Fraction of new traces recorded, 0 to 1
+}
+
+// MetricsConfig holds the Prometheus metrics endpoint configuration
+type MetricsConfig struct {
+	Enabled bool   `json:"enabled"`
+	Token   string `json:"-"` // Bearer token scrapers must send; none when empty
+}
+
+// SentryConfig holds error reporting configuration. Any Sentry-compatible
+// server (e.g. GlitchTip) works; reporting is off while the DSN is empty.
+type SentryConfig struct {
+	DSN         string  `json:"-"`
+	Environment string  `json:"environment"`
+	Release     string  `json:"release"`
+	SampleRate  float64 `json:"sample_rate"` // Fraction of errors reported, 0 to 1
+}
+
+// Enabled returns true if errors should be reported
+func (c *SentryConfig) Enabled() bool {
+	return c.DSN != ""
+}
+
+// DataExportConfig holds tenant data export configuration
+type DataExportConfig struct {
+	Dir string        `json:"dir"` // Where export archives are written until downloaded
+	TTL time.Duration `json:"ttl"` // How long an archive can be downloaded
+}
+
+// AttachmentConfig holds how large report attachments may be and how they
+// are served
+type AttachmentConfig struct {
+	MaxSize       int64         `json:"max_size"`       // In bytes
+	URLTTL        time.Duration `json:"url_ttl"`        // How long a presigned download URL works
+	ThumbnailSize int           `json:"thumbnail_size"` // Longer side of thumbnails, in pixels
+}
+
+// StorageConfig selects where uploaded files are kept: "local" (in Dir) or
+// "s3" (Amazon S3 or a compatible service such as MinIO)
+type StorageConfig struct {
+	Driver          string `json:"driver"`
+	Dir             string `json:"dir"`
+	S3Endpoint      string `json:"s3_endpoint"` // Defaults to AWS in the region
+	S3Region        string `json:"s3_region"`
+	S3Bucket        string `json:"s3_bucket"`
+	S3Prefix        string `json:"s3_prefix"` // Put before every object key
+	S3AccessKey     string `json:"-"`
+	S3SecretKey     string `json:"-"`
+	S3PathStyle     bool   `json:"s3_path_style"`     // Bucket in the URL path rather than the host name, as MinIO needs
+	S3TenantBuckets bool   `json:"s3_tenant_buckets"` // Each tenant's files in its own bucket, named <bucket>-<tenant ID>
+}
+
+// AntivirusConfig selects the scanner uploads are checked with before they
+// can be downloaded: "" (no scanning), "clamav" or "icap"
+type AntivirusConfig struct {
+	Driver        string        `json:"driver"`
+	ClamAVAddress string        `json:"clamav_address"` // host:port of clamd
+	ICAPURL       string        `json:"icap_url"`       // e.g. icap://icap:1344/avscan
+	Timeout       time.Duration `json:"timeout"`
+	RetrySchedule string        `json:"retry_schedule"` // Cron expression of the job scanning files a scan failed for
+}
+
+// Enabled reports whether uploads are scanned
+func (c *AntivirusConfig) Enabled() bool {
+	return c.Driver != ""
+}
+
+// BackupConfig holds the PostgreSQL client tools database backups are made
+// and restored with, and the schedule of automatic backups
+type BackupConfig struct {
+	PgDump     string `json:"pg_dump"`    // Path of pg_dump
+	PgRestore  string `json:"pg_restore"` // Path of pg_restore
+	Psql       string `json:"psql"`       // Path of psql
+	Schedule   string `json:"schedule"`   // Cron expression of the database backup job; empty disables it
+	Keep       int    `json:"keep"`       // How many scheduled database backups are kept
+	RestoreURL string `json:"-"`          // Connection string of the staging database backups are restored into
+}
+
+// Scheduled reports whether the database is backed up on a schedule
+func (c *BackupConfig) Scheduled() bool {
+	return c.Schedule != ""
+}
+
+// OAuthConfig holds the "Sign in with Google" configuration. Sign-in is off
+// while the client ID is empty.
+type OAuthConfig struct {
+	GoogleClientID     string `json:"google_client_id"`
+	GoogleClientSecret string `json:"-"`
+	GoogleRedirectURL  string `json:"google_redirect_url"` // Callback URL registered with Google
+	FrontendURL        string `json:"frontend_url"`        // Where to send the tokens after sign-in; empty returns JSON
+}
+
+// GoogleEnabled returns true if Google sign-in is configured
+func (c *OAuthConfig) GoogleEnabled() bool {
+	return c.GoogleClientID != ""
+}
+
+// OCRConfig selects the provider reading receipt photos: "" (disabled) or
+// "google_vision"
+type OCRConfig struct {
+	Provider           string `json:"provider"`
+	GoogleVisionAPIKey string `json:"-"`
+}
+
+// MailConfig holds the SMTP relay email is sent through. Email is off while
+// disabled.
+type MailConfig struct {
+	Enabled      bool   `json:"enabled"`
+	SMTPHost     string `json:"smtp_host"`
+	SMTPPort     int    `json:"smtp_port"`
+	SMTPUsername string `json:"smtp_username"`
+	SMTPPassword string `json:"-"`
+	From         string `json:"from"` // Sender address, e.g. "TaxiFleet <no-reply@example.com>"
+}
+
+// FuelCardConfig selects the provider fuel card transactions are pulled from:
+// "" (disabled) or "http", a JSON API serving GET {api_url}/transactions
+type FuelCardConfig struct {
+	Provider string `json:"provider"`
+	APIURL   string `json:"api_url"`
+	APIKey   string `json:"-"`
+}
+
+// Enabled returns true if fuel card transactions should be synced
+func (c *FuelCardConfig) Enabled() bool {
+	return c.Provider != ""
+}
+
+// OnboardingConfig controls the self-service sign-up of new tenants, which is
+// off unless enabled. With invite codes set, signing up requires one of them.
+type OnboardingConfig struct {
+	Enabled         bool          `json:"enabled"`
+	InviteCodes     []string      `json:"-"`
+	VerifyURL       string        `json:"verify_url"` // Frontend page the emailed link opens, with ?token=
+	VerificationTTL time.Duration `json:"verification_ttl"`
+	PlanID          uint          `json:"plan_id"` // Plan new tenants are put on; 0 for none
+}
+
 // Load loads configuration from environment variables and .env file
 func Load() (*Config, error) {
 	// Try to load .env file (ignore error if file doesn't exist)
@@ -99,6 +325,8 @@ func Load() (*Config, error) {
 			ShutdownTimeout: getDurationEnv("SERVER_SHUTDOWN_TIMEOUT", "30s"),
 			Environment:     getEnv("ENVIRONMENT", "development"),
 			Version:         getEnv("VERSION", "1.0.0"),
+			V1Sunset:        getTimeEnv("API_V1_SUNSET"),
+			Pprof:           getBoolEnv("PPROF_ENABLED", getEnv("ENVIRONMENT", "development") != "production"),
 		},
 		Database: DatabaseConfig{
 			Host:            getEnv("DB_HOST", "localhost"),
@@ -112,11 +340,22 @@ func Load() (*Config, error) {
 			ConnMaxLifetime: getDurationEnv("DB_CONN_MAX_LIFETIME", "5m"),
 			ConnMaxIdleTime: getDurationEnv("DB_CONN_MAX_IDLE_TIME", "5m"),
 			MigrationPath:   getEnv("DB_MIGRATION_PATH", "file://migrations"),
+
+			RowLevelSecurity: getBoolEnv("DB_ROW_LEVEL_SECURITY", false),
 		},
 		JWT: JWTConfig{
 			Secret:            getEnv("JWT_SECRET", "your-secret-key-change-in-production"),
 			Expiration:        getDurationEnv("JWT_EXPIRATION", "15m"),
 			RefreshExpiration: getDurationEnv("JWT_REFRESH_EXPIRATION", "7d"),
+			UserCheckInterval: getDurationEnv("JWT_USER_CHECK_INTERVAL", "1m"),
+
+			Algorithm:      getEnv("JWT_ALGORITHM", "HS256"),
+			PrivateKeyFile: getEnv("JWT_PRIVATE_KEY_FILE", ""),
+			KeyID:          getEnv("JWT_KEY_ID", ""),
+
+			PreviousPublicKeyFile: getEnv("JWT_PREVIOUS_PUBLIC_KEY_FILE", ""),
+			PreviousKeyID:         getEnv("JWT_PREVIOUS_KEY_ID", ""),
+			PreviousKeyUntil:      getTimeEnv("JWT_PREVIOUS_KEY_UNTIL"),
 		},
 		Permissions: PermissionsConfig{
 			Admin:    getIntEnv("JWT_ADMIN_PERMISSION_MASK", 0xFFFFFFFF),
@@ -126,12 +365,17 @@ func Load() (*Config, error) {
 			Driver:   getIntEnv("JWT_DRIVER_PERMISSION_MASK", 0x3),    // View and Add reports
 		},
 		Security: SecurityConfig{
-			BCryptCost:         getIntEnv("BCRYPT_COST", 12),
-			RateLimitRPS:       getIntEnv("RATE_LIMIT_RPS", 10),
-			RateLimitBurst:     getIntEnv("RATE_LIMIT_BURST", 20),
-			CORSAllowedOrigins: getSliceEnv("CORS_ALLOWED_ORIGINS", "*"),
-			CORSAllowedMethods: getSliceEnv("CORS_ALLOWED_METHODS", "GET,POST,PUT,DELETE,OPTIONS,PATCH"),
-			CORSAllowedHeaders: getSliceEnv("CORS_ALLOWED_HEADERS", "Origin,Content-Type,Accept,Authorization"),
+			BCryptCost:              getIntEnv("BCRYPT_COST", 12),
+			RateLimitRPS:            getIntEnv("RATE_LIMIT_RPS", 10),
+			RateLimitBurst:          getIntEnv("RATE_LIMIT_BURST", 20),
+			RateLimitStore:          getEnv("RATE_LIMIT_STORE", "memory"),
+			RateLimitExportsPerHour: getIntEnv("RATE_LIMIT_EXPORTS_PER_HOUR", 30),
+			RateLimitAPIKeyPerHour:  getIntEnv("RATE_LIMIT_API_KEY_PER_HOUR", 0),
+			TextMaxLength:           getIntEnv("TEXT_MAX_LENGTH", 2000),
+			EscapeHTML:              getBoolEnv("ESCAPE_HTML", false),
+			CORSAllowedOrigins:      getSliceEnv("CORS_ALLOWED_ORIGINS", "*"),
+			CORSAllowedMethods:      getSliceEnv("CORS_ALLOWED_METHODS", "GET,POST,PUT,DELETE,OPTIONS,PATCH"),
+			CORSAllowedHeaders:      getSliceEnv("CORS_ALLOWED_HEADERS", "Origin,Content-Type,Accept,Authorization"),
 		},
 		Logging: LoggingConfig{
 			Level:      getEnv("LOG_LEVEL", "info"),
@@ -142,6 +386,116 @@ func Load() (*Config, error) {
 			MaxAge:     getIntEnv("LOG_MAX_AGE", 28),
 			Compress:   getBoolEnv("LOG_COMPRESS", true),
 		},
+		Push: PushConfig{
+			Enabled:        getBoolEnv("PUSH_ENABLED", false),
+			FCMProjectID:   getEnv("FCM_PROJECT_ID", ""),
+			FCMCredentials: getEnv("FCM_CREDENTIALS_FILE", ""),
+		},
+		Maintenance: MaintenanceConfig{
+			DueCheckInterval: getDurationEnv("MAINTENANCE_DUE_CHECK_INTERVAL", "1h"),
+		},
+		Cache: CacheConfig{
+			Enabled:       getBoolEnv("CACHE_ENABLED", false),
+			RedisAddr:     getEnv("REDIS_ADDR", "localhost:6379"),
+			RedisPassword: getEnv("REDIS_PASSWORD", ""),
+			RedisDB:       getIntEnv("REDIS_DB", 0),
+			TTL:           getDurationEnv("CACHE_TTL", "5m"),
+		},
+		Session: SessionConfig{
+			Store: getEnv("SESSION_STORE", "postgres"),
+		},
+		Scheduler: SchedulerConfig{
+			Enabled:                getBoolEnv("SCHEDULER_ENABLED", true),
+			SessionCleanupSchedule: getEnv("JOBS_SESSION_CLEANUP_SCHEDULE", "0 3 * * *"),
+			WeeklyDigestSchedule:   getEnv("JOBS_WEEKLY_DIGEST_SCHEDULE", "0 7 * * 1"),
+			FuelCardSyncSchedule:   getEnv("JOBS_FUEL_CARD_SYNC_SCHEDULE", "@hourly"),
+			DataRetentionSchedule:  getEnv("JOBS_DATA_RETENTION_SCHEDULE", "0 4 * * *"),
+		},
+		Tracing: TracingConfig{
+			Enabled:      getBoolEnv("TRACING_ENABLED", false),
+			OTLPEndpoint: getEnv("TRACING_OTLP_ENDPOINT", "localhost:4318"),
+			OTLPInsecure: getBoolEnv("TRACING_OTLP_INSECURE", true),
+			ServiceName:  getEnv("TRACING_SERVICE_NAME", "taxifleet-backend"),
+			SampleRatio:  getFloatEnv("TRACING_SAMPLE_RATIO", 1),
+		},
+		Metrics: MetricsConfig{
+			Enabled: getBoolEnv("METRICS_ENABLED", false),
+			Token:   getEnv("METRICS_TOKEN", ""),
+		},
+		Sentry: SentryConfig{
+			DSN:         getEnv("SENTRY_DSN", ""),
+			Environment: getEnv("SENTRY_ENVIRONMENT", getEnv("ENVIRONMENT", "development")),
+			Release:     getEnv("SENTRY_RELEASE", ""),
+			SampleRate:  getFloatEnv("SENTRY_SAMPLE_RATE", 1),
+		},
+		DataExport: DataExportConfig{
+			Dir: getEnv("DATA_EXPORT_DIR", "./exports"),
+			TTL: getDurationEnv("DATA_EXPORT_TTL", "24h"),
+		},
+		Attachments: AttachmentConfig{
+			MaxSize:       int64(getIntEnv("ATTACHMENT_MAX_SIZE", 10<<20)),
+			URLTTL:        getDurationEnv("ATTACHMENT_URL_TTL", "5m"),
+			ThumbnailSize: getIntEnv("ATTACHMENT_THUMBNAIL_SIZE", 320),
+		},
+		Storage: StorageConfig{
+			Driver:          getEnv("STORAGE_DRIVER", "local"),
+			Dir:             getEnv("ATTACHMENT_DIR", "./uploads"),
+			S3Endpoint:      getEnv("S3_ENDPOINT", ""),
+			S3Region:        getEnv("S3_REGION", "us-east-1"),
+			S3Bucket:        getEnv("S3_BUCKET", ""),
+			S3Prefix:        getEnv("S3_PREFIX", ""),
+			S3AccessKey:     getEnv("S3_ACCESS_KEY", ""),
+			S3SecretKey:     getEnv("S3_SECRET_KEY", ""),
+			S3PathStyle:     getBoolEnv("S3_PATH_STYLE", false),
+			S3TenantBuckets: getBoolEnv("S3_TENANT_BUCKETS", false),
+		},
+		Antivirus: AntivirusConfig{
+			Driver:        getEnv("ANTIVIRUS_DRIVER", ""),
+			ClamAVAddress: getEnv("CLAMAV_ADDRESS", "localhost:3310"),
+			ICAPURL:       getEnv("ICAP_URL", ""),
+			Timeout:       getDurationEnv("ANTIVIRUS_TIMEOUT", "30s"),
+			RetrySchedule: getEnv("JOBS_ATTACHMENT_SCAN_SCHEDULE", "@every 5m"),
+		},
+		Backup: BackupConfig{
+			PgDump:     getEnv("PG_DUMP_PATH", "pg_dump"),
+			PgRestore:  getEnv("PG_RESTORE_PATH", "pg_restore"),
+			Psql:       getEnv("PSQL_PATH", "psql"),
+			Schedule:   getEnv("JOBS_DATABASE_BACKUP_SCHEDULE", ""),
+			Keep:       getIntEnv("BACKUP_KEEP", 7),
+			RestoreURL: getEnv("BACKUP_RESTORE_DATABASE_URL", ""),
+		},
+		OAuth: OAuthConfig{
+			GoogleClientID:     getEnv("OAUTH_GOOGLE_CLIENT_ID", ""),
+			GoogleClientSecret: getEnv("OAUTH_GOOGLE_CLIENT_SECRET", ""),
+			GoogleRedirectURL:  getEnv("OAUTH_GOOGLE_REDIRECT_URL", ""),
+			FrontendURL:        getEnv("OAUTH_FRONTEND_URL", ""),
+		},
+		OCR: OCRConfig{
+			Provider:           getEnv("OCR_PROVIDER", ""),
+			GoogleVisionAPIKey: getEnv("OCR_GOOGLE_VISION_API_KEY", ""),
+		},
+		Mail: MailConfig{
+			Enabled:      getBoolEnv("MAIL_ENABLED", false),
+			SMTPHost:     getEnv("SMTP_HOST", ""),
+			SMTPPort:     getIntEnv("SMTP_PORT", 587),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+			From:         getEnv("MAIL_FROM", ""),
+		},
+		FuelCard: FuelCardConfig{
+			Provider: getEnv("FUEL_CARD_PROVIDER", ""),
+			APIURL:   getEnv("FUEL_CARD_API_URL", ""),
+			APIKey:   getEnv("FUEL_CARD_API_KEY", ""),
+		},
+		Onboarding: OnboardingConfig{
+			Enabled: getBoolEnv("ONBOARDING_ENABLED", false),
+			InviteCodes: slices.DeleteFunc(getSliceEnv("ONBOARDING_INVITE_CODES", ""), func(code string) bool {
+				return strings.TrimSpace(code) == ""
+			}),
+			VerifyURL:       getEnv("ONBOARDING_VERIFY_URL", ""),
+			VerificationTTL: getDurationEnv("ONBOARDING_VERIFICATION_TTL", "48h"),
+			PlanID:          uint(getIntEnv("ONBOARDING_PLAN_ID", 0)),
+		},
 	}
 
 	return config, config.Validate()
@@ -163,6 +517,90 @@ func (c *Config) Validate() error {
 			return fmt.Errorf("JWT secret must be set in production")
 		}
 	}
+	switch c.JWT.Algorithm {
+	case "HS256":
+	case "RS256", "EdDSA":
+		if c.JWT.PrivateKeyFile == "" || c.JWT.KeyID == "" {
+			return fmt.Errorf("JWT private key file and key ID are required for %s", c.JWT.Algorithm)
+		}
+	default:
+		return fmt.Errorf("unsupported JWT algorithm %q", c.JWT.Algorithm)
+	}
+	if c.JWT.PreviousPublicKeyFile != "" && (c.JWT.PreviousKeyID == "" || c.JWT.PreviousKeyUntil.IsZero()) {
+		return fmt.Errorf("JWT previous key ID and JWT_PREVIOUS_KEY_UNTIL are required with a previous public key")
+	}
+	if c.Session.Store != "postgres" && c.Session.Store != "redis" {
+		return fmt.Errorf("unsupported session store %q", c.Session.Store)
+	}
+	if c.Security.RateLimitStore != "memory" && c.Security.RateLimitStore != "redis" {
+		return fmt.Errorf("unsupported rate limit store %q", c.Security.RateLimitStore)
+	}
+	if c.Security.TextMaxLength <= 0 {
+		return fmt.Errorf("text max length must be positive")
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		return fmt.Errorf("tracing sample ratio must be between 0 and 1")
+	}
+	if c.Sentry.SampleRate < 0 || c.Sentry.SampleRate > 1 {
+		return fmt.Errorf("sentry sample rate must be between 0 and 1")
+	}
+	if c.Attachments.MaxSize <= 0 {
+		return fmt.Errorf("attachment max size must be positive")
+	}
+	if c.Attachments.ThumbnailSize <= 0 {
+		return fmt.Errorf("attachment thumbnail size must be positive")
+	}
+	if c.Backup.Scheduled() && c.Backup.Keep <= 0 {
+		return fmt.Errorf("backup keep must be positive")
+	}
+	switch c.Storage.Driver {
+	case "local":
+	case "s3":
+		if c.Storage.S3Bucket == "" || c.Storage.S3AccessKey == "" || c.Storage.S3SecretKey == "" {
+			return fmt.Errorf("S3 bucket, access key and secret key are required for the s3 storage driver")
+		}
+	default:
+		return fmt.Errorf("unsupported storage driver %q", c.Storage.Driver)
+	}
+	switch c.Antivirus.Driver {
+	case "", "clamav":
+	case "icap":
+		if c.Antivirus.ICAPURL == "" {
+			return fmt.Errorf("ICAP URL is required for the icap antivirus driver")
+		}
+	default:
+		return fmt.Errorf("unsupported antivirus driver %q", c.Antivirus.Driver)
+	}
+	if c.OAuth.GoogleEnabled() && (c.OAuth.GoogleClientSecret == "" || c.OAuth.GoogleRedirectURL == "") {
+		return fmt.Errorf("Google client secret and redirect URL are required when Google sign-in is enabled")
+	}
+	if c.Push.Enabled && (c.Push.FCMProjectID == "" || c.Push.FCMCredentials == "") {
+		return fmt.Errorf("FCM project ID and credentials file are required when push is enabled")
+	}
+	if c.Mail.Enabled && (c.Mail.SMTPHost == "" || c.Mail.From == "") {
+		return fmt.Errorf("SMTP host and sender address are required when mail is enabled")
+	}
+	switch c.OCR.Provider {
+	case "":
+	case "google_vision":
+		if c.OCR.GoogleVisionAPIKey == "" {
+			return fmt.Errorf("Google Vision API key is required for the google_vision OCR provider")
+		}
+	default:
+		return fmt.Errorf("unsupported OCR provider %q", c.OCR.Provider)
+	}
+	if c.Onboarding.Enabled && (!c.Mail.Enabled || c.Onboarding.VerifyURL == "") {
+		return fmt.Errorf("mail and a verify URL are required when onboarding is enabled")
+	}
+	switch c.FuelCard.Provider {
+	case "":
+	case "http":
+		if c.FuelCard.APIURL == "" || c.FuelCard.APIKey == "" {
+			return fmt.Errorf("fuel card API URL and key are required for the http fuel card provider")
+		}
+	default:
+		return fmt.Errorf("unsupported fuel card provider %q", c.FuelCard.Provider)
+	}
 	return nil
 }
 
@@ -207,6 +645,15 @@ func getIntEnv(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getFloatEnv(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
 func getBoolEnv(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		if parsed, err := strconv.ParseBool(value); err == nil {
@@ -228,6 +675,14 @@ func getDurationEnv(key string, defaultValue string) time.Duration {
 	return time.Second * 30 // Ultimate fallback
 }
 
+// getTimeEnv parses an RFC 3339 timestamp, returning the zero time if unset or invalid
+func getTimeEnv(key string) time.Time {
+	if t, err := time.Parse(time.RFC3339, os.Getenv(key)); err == nil {
+		return t
+	}
+	return time.Time{}
+}
+
 func getSliceEnv(key string, defaultValue string) []string {
 	value := getEnv(key, defaultValue)
 	return strings.Split(value, ",")
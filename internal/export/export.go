@@ -0,0 +1,438 @@
+// Package export renders tabular documents such as financial statements to
+// XLSX, PDF and CSV files.
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"taxifleet/backend/internal/money"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/xuri/excelize/v2"
+)
+
+// Section is a titled table within a document. Cells may be strings or
+// numbers; money.Amount and float64 cells are amounts, rendered with two
+// decimals.
+type Section struct {
+	Title   string
+	Headers []string
+	Keys    []string // Column identifiers, parallel to Headers
+	Rows    [][]interface{}
+	Totals  []interface{} // Rendered in bold below the rows when set
+
+	// Summable sections list records whose amounts add up, so a totals row
+	// may be added; sections with totals among their rows leave it unset
+	Summable bool
+}
+
+// AddTotals sets the section's totals row to the sum of each column holding
+// amount cells, with label in the first column
+func (s *Section) AddTotals(label string) {
+	var totals []interface{}
+	for _, values := range s.Rows {
+		for i, value := range values {
+			if !isAmount(value) {
+				continue
+			}
+			for len(totals) <= i {
+				totals = append(totals, nil)
+			}
+			switch amount := value.(type) {
+			case money.Amount:
+				sum, _ := totals[i].(money.Amount)
+				totals[i] = sum + amount
+			case float64:
+				sum, _ := totals[i].(float64)
+				totals[i] = sum + amount
+			}
+		}
+	}
+	if len(totals) == 0 {
+		totals = []interface{}{nil}
+	}
+	if !isAmount(totals[0]) {
+		totals[0] = label
+	}
+	s.Totals = totals
+}
+
+func isAmount(value interface{}) bool {
+	switch value.(type) {
+	case money.Amount, float64:
+		return true
+	}
+	return false
+}
+
+// Branding identifies the organization a document is generated for
+type Branding struct {
+	Name     string
+	Logo     []byte // PNG, JPEG or GIF image; nil for none
+	LogoType string // png, jpg or gif
+	Footer   string // Printed at the bottom, e.g. who generated the document and when
+}
+
+// Signature is a sign-off drawn below the sections of a PDF document
+type Signature struct {
+	Image     []byte // PNG or JPEG image; nil for the caption alone
+	ImageType string // png or jpg
+	Caption   string // Printed under the signature line, e.g. who signed and when
+}
+
+// Document is a titled list of sections rendered top to bottom
+type Document struct {
+	Title     string // Omitted when empty, leaving the first section at the top
+	Subtitle  string
+	Sheet     string // XLSX sheet name, at most 31 characters
+	Sections  []Section
+	Branding  *Branding  // Header and footer; nil renders a plain document
+	Signature *Signature // PDF only
+}
+
+func formatCell(value interface{}) string {
+	switch v := value.(type) {
+	case money.Amount:
+		return v.String()
+	case float64:
+		return fmt.Sprintf("%.2f", v)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// WriteCSV writes a single section as CSV, with its headers as the first row
+func WriteCSV(w io.Writer, section Section) error {
+	cw := csv.NewWriter(w)
+	if len(section.Headers) > 0 {
+		if err := cw.Write(section.Headers); err != nil {
+			return err
+		}
+	}
+	for _, values := range section.Rows {
+		record := make([]string, len(values))
+		for i, value := range values {
+			record[i] = formatCell(value)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteXLSX writes the document as a single-sheet workbook
+func WriteXLSX(w io.Writer, doc Document) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sheet := doc.Sheet
+	if sheet == "" {
+		sheet = "Sheet1"
+	}
+	if err := f.SetSheetName("Sheet1", sheet); err != nil {
+		return err
+	}
+
+	bold, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return err
+	}
+	moneyStyle, err := f.NewStyle(&excelize.Style{NumFmt: 4}) // #,##0.00
+	if err != nil {
+		return err
+	}
+	boldMoney, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}, NumFmt: 4})
+	if err != nil {
+		return err
+	}
+
+	row := 1
+	setRow := func(values []interface{}, strong bool) error {
+		for i, value := range values {
+			cell, err := excelize.CoordinatesToCellName(i+1, row)
+			if err != nil {
+				return err
+			}
+			cellValue := value
+			if amount, ok := value.(money.Amount); ok {
+				cellValue = amount.Float()
+			}
+			if err := f.SetCellValue(sheet, cell, cellValue); err != nil {
+				return err
+			}
+			amount := isAmount(value)
+			cellStyle := 0
+			switch {
+			case strong && amount:
+				cellStyle = boldMoney
+			case strong:
+				cellStyle = bold
+			case amount:
+				cellStyle = moneyStyle
+			}
+			if cellStyle != 0 {
+				if err := f.SetCellStyle(sheet, cell, cell, cellStyle); err != nil {
+					return err
+				}
+			}
+		}
+		row++
+		return nil
+	}
+
+	if doc.Branding != nil {
+		if err := writeXLSXBranding(f, sheet, doc.Branding); err != nil {
+			return err
+		}
+		row += 2
+	}
+
+	if doc.Title != "" {
+		if err := setRow([]interface{}{doc.Title}, true); err != nil {
+			return err
+		}
+	}
+	if doc.Subtitle != "" {
+		if err := setRow([]interface{}{doc.Subtitle}, false); err != nil {
+			return err
+		}
+	}
+
+	for _, section := range doc.Sections {
+		if row > 1 {
+			row++
+		}
+		if section.Title != "" {
+			if err := setRow([]interface{}{section.Title}, true); err != nil {
+				return err
+			}
+		}
+		if len(section.Headers) > 0 {
+			headers := make([]interface{}, len(section.Headers))
+			for i, header := range section.Headers {
+				headers[i] = header
+			}
+			if err := setRow(headers, true); err != nil {
+				return err
+			}
+		}
+		for _, values := range section.Rows {
+			if err := setRow(values, false); err != nil {
+				return err
+			}
+		}
+		if section.Totals != nil {
+			if err := setRow(section.Totals, true); err != nil {
+				return err
+			}
+		}
+	}
+
+	if doc.Branding != nil && doc.Branding.Footer != "" {
+		row++
+		if err := setRow([]interface{}{doc.Branding.Footer}, false); err != nil {
+			return err
+		}
+	}
+
+	if err := f.SetColWidth(sheet, "A", "F", 22); err != nil {
+		return err
+	}
+
+	return f.Write(w)
+}
+
+// writeXLSXBranding puts the logo and name in the first row of the sheet and
+// the footer in the printed page footer
+func writeXLSXBranding(f *excelize.File, sheet string, branding *Branding) error {
+	nameCell := "A1"
+	if len(branding.Logo) > 0 {
+		// A logo that cannot be read is left out rather than failing the export
+		err := f.AddPictureFromBytes(sheet, "A1", &excelize.Picture{
+			Extension: "." + branding.LogoType,
+			File:      branding.Logo,
+			Format:    &excelize.GraphicOptions{AutoFit: true, LockAspectRatio: true, Positioning: "oneCell"},
+		})
+		if err == nil {
+			nameCell = "B1"
+			if err := f.SetRowHeight(sheet, 1, 40); err != nil {
+				return err
+			}
+		}
+	}
+
+	title, err := f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true, Size: 14},
+		Alignment: &excelize.Alignment{Vertical: "center"},
+	})
+	if err != nil {
+		return err
+	}
+	if err := f.SetCellValue(sheet, nameCell, branding.Name); err != nil {
+		return err
+	}
+	if err := f.SetCellStyle(sheet, nameCell, nameCell, title); err != nil {
+		return err
+	}
+
+	if branding.Footer == "" {
+		return nil
+	}
+	// & starts a formatting code in page footers
+	footer := strings.ReplaceAll(branding.Footer, "&", "&&")
+	return f.SetHeaderFooter(sheet, &excelize.HeaderFooterOptions{OddFooter: "&L" + footer + "&RPage &P of &N"})
+}
+
+// WritePDF writes the document as an A4 portrait PDF
+func WritePDF(w io.Writer, doc Document) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("") // Core fonts are cp1252
+	pdf.SetMargins(10, 15, 10)
+
+	pageWidth, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	contentWidth := pageWidth - left - right
+
+	if doc.Branding != nil {
+		pdf.AliasNbPages("")
+		pdf.SetFooterFunc(func() {
+			pdf.SetY(-12)
+			pdf.SetFont("Helvetica", "I", 8)
+			pdf.CellFormat(contentWidth*0.8, 6, tr(doc.Branding.Footer), "T", 0, "L", false, 0, "")
+			pdf.CellFormat(contentWidth*0.2, 6, fmt.Sprintf("Page %d/{nb}", pdf.PageNo()), "T", 0, "R", false, 0, "")
+		})
+	}
+
+	pdf.AddPage()
+
+	if doc.Branding != nil {
+		writePDFBranding(pdf, tr, contentWidth, doc.Branding)
+	}
+
+	if doc.Title != "" {
+		pdf.SetFont("Helvetica", "B", 16)
+		pdf.CellFormat(contentWidth, 10, tr(doc.Title), "", 1, "L", false, 0, "")
+	}
+	if doc.Subtitle != "" {
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(contentWidth, 6, tr(doc.Subtitle), "", 1, "L", false, 0, "")
+	}
+
+	for _, section := range doc.Sections {
+		pdf.Ln(4)
+		if section.Title != "" {
+			pdf.SetFont("Helvetica", "B", 12)
+			pdf.CellFormat(contentWidth, 8, tr(section.Title), "", 1, "L", false, 0, "")
+		}
+
+		columns := max(len(section.Headers), len(section.Totals))
+		for _, values := range section.Rows {
+			if len(values) > columns {
+				columns = len(values)
+			}
+		}
+		if columns == 0 {
+			continue
+		}
+		width := contentWidth / float64(columns)
+
+		if len(section.Headers) > 0 {
+			pdf.SetFont("Helvetica", "B", 10)
+			pdf.SetFillColor(230, 230, 230)
+			for _, header := range section.Headers {
+				pdf.CellFormat(width, 7, tr(header), "1", 0, "L", true, 0, "")
+			}
+			pdf.Ln(-1)
+		}
+
+		writeRow := func(values []interface{}) {
+			for i := 0; i < columns; i++ {
+				var value interface{}
+				if i < len(values) {
+					value = values[i]
+				}
+				align := "L"
+				if isAmount(value) {
+					align = "R"
+				}
+				pdf.CellFormat(width, 7, tr(formatCell(value)), "1", 0, align, false, 0, "")
+			}
+			pdf.Ln(-1)
+		}
+
+		pdf.SetFont("Helvetica", "", 10)
+		for _, values := range section.Rows {
+			writeRow(values)
+		}
+		if section.Totals != nil {
+			pdf.SetFont("Helvetica", "B", 10)
+			writeRow(section.Totals)
+		}
+	}
+
+	if doc.Signature != nil {
+		writePDFSignature(pdf, tr, doc.Signature)
+	}
+
+	return pdf.Output(w)
+}
+
+// writePDFSignature draws the signature image above a signature line and the
+// caption below it
+func writePDFSignature(pdf *fpdf.Fpdf, tr func(string) string, signature *Signature) {
+	const imageHeight, lineWidth = 20, 70
+	pdf.Ln(10)
+	left, _, _, _ := pdf.GetMargins()
+
+	if len(signature.Image) > 0 {
+		options := fpdf.ImageOptions{ImageType: signature.ImageType, ReadDpi: true}
+		info := pdf.RegisterImageOptionsReader("signature", options, bytes.NewReader(signature.Image))
+		if pdf.Ok() && info != nil {
+			pdf.ImageOptions("signature", left, pdf.GetY(), 0, imageHeight, true, options, 0, "")
+		} else {
+			// A signature that cannot be read leaves the line empty
+			pdf.ClearError()
+			pdf.Ln(imageHeight)
+		}
+	} else {
+		pdf.Ln(imageHeight)
+	}
+
+	y := pdf.GetY()
+	pdf.Line(left, y, left+lineWidth, y)
+	pdf.Ln(1)
+	pdf.SetFont("Helvetica", "I", 9)
+	pdf.MultiCell(lineWidth, 5, tr(signature.Caption), "", "L", false)
+}
+
+// writePDFBranding draws the logo and name above the document title
+func writePDFBranding(pdf *fpdf.Fpdf, tr func(string) string, contentWidth float64, branding *Branding) {
+	const logoHeight = 12
+	left, top, _, _ := pdf.GetMargins()
+	textLeft := left
+
+	if len(branding.Logo) > 0 {
+		options := fpdf.ImageOptions{ImageType: branding.LogoType, ReadDpi: true}
+		info := pdf.RegisterImageOptionsReader("logo", options, bytes.NewReader(branding.Logo))
+		if pdf.Ok() && info != nil {
+			pdf.ImageOptions("logo", left, top, 0, logoHeight, false, options, 0, "")
+			textLeft += info.Width()*logoHeight/info.Height() + 4
+		} else {
+			// A logo that cannot be read is left out rather than failing the export
+			pdf.ClearError()
+		}
+	}
+
+	pdf.SetXY(textLeft, top)
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(contentWidth-(textLeft-left), logoHeight, tr(branding.Name), "", 1, "L", false, 0, "")
+	pdf.Ln(4)
+}
@@ -0,0 +1,90 @@
+// Package fuelcard pulls the transactions made with the fleet's fuel cards
+// from a pluggable fuel card provider.
+package fuelcard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrDisabled is returned when no fuel card provider is configured
+var ErrDisabled = errors.New("fuel card sync is not enabled")
+
+// Transaction is a purchase made with a fuel card
+type Transaction struct {
+	ID         string    `json:"id"`          // Unique at the provider
+	CardNumber string    `json:"card_number"` // May contain spaces
+	Time       time.Time `json:"time"`
+	Amount     float64   `json:"amount"`
+	Liters     float64   `json:"liters"`
+	Station    string    `json:"station"`
+}
+
+// Provider lists fuel card transactions
+type Provider interface {
+	// Transactions returns the transactions made since the given time
+	Transactions(ctx context.Context, since time.Time) ([]Transaction, error)
+}
+
+// DisabledProvider has no transactions, used when no provider is configured
+type DisabledProvider struct{}
+
+func (DisabledProvider) Transactions(ctx context.Context, since time.Time) ([]Transaction, error) {
+	return nil, ErrDisabled
+}
+
+// HTTPProvider reads transactions from a JSON API answering
+// GET {baseURL}/transactions?since=<RFC 3339 time> with
+// {"transactions": [...]}, authenticated with a bearer API key
+type HTTPProvider struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+// NewHTTPProvider creates a provider for the API at baseURL
+func NewHTTPProvider(baseURL, apiKey string) *HTTPProvider {
+	return &HTTPProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		client:  &http.Client{Timeout: 20 * time.Second},
+	}
+}
+
+type transactionsResponse struct {
+	Transactions []Transaction `json:"transactions"`
+}
+
+func (p *HTTPProvider) Transactions(ctx context.Context, since time.Time) ([]Transaction, error) {
+	endpoint := p.baseURL + "/transactions?since=" + url.QueryEscape(since.UTC().Format(time.RFC3339))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fuel card request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("fuel card provider returned %d: %s", resp.StatusCode, body)
+	}
+
+	var result transactionsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode fuel card response: %w", err)
+	}
+	return result.Transactions, nil
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ActivityHandler struct {
+	service *service.ActivityService
+}
+
+func NewActivityHandler(service *service.ActivityService) *ActivityHandler {
+	return &ActivityHandler{service: service}
+}
+
+// Feed returns a page of the tenant's activity, newest first; the response's
+// next_cursor is passed as cursor to get the following page
+func (h *ActivityHandler) Feed(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		apierror.Abort(c, apierror.BadRequest("limit must be between 1 and 200"))
+		return
+	}
+
+	feed, err := h.service.Feed(c.Request.Context(), tenantID.(uint), c.Query("cursor"), limit)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, feed)
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"taxifleet/backend/internal/apierror"
 	"taxifleet/backend/internal/permissions"
 	"taxifleet/backend/internal/service"
 
@@ -21,14 +22,14 @@ func NewAdminHandler(service *service.AdminService) *AdminHandler {
 func (h *AdminHandler) RequireAdmin(c *gin.Context) {
 	permission, exists := c.Get("permission")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		apierror.Abort(c, apierror.Unauthorized("unauthorized"))
 		c.Abort()
 		return
 	}
 
 	perm := permission.(int)
 	if !permissions.HasPermission(perm, permissions.PermissionManageTenants) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "admin access required"})
+		apierror.Abort(c, apierror.Forbidden("admin access required"))
 		c.Abort()
 		return
 	}
@@ -40,13 +41,13 @@ func (h *AdminHandler) RequireAdmin(c *gin.Context) {
 func (h *AdminHandler) CreateTenant(c *gin.Context) {
 	var req service.CreateTenantRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Abort(c, apierror.Validation(err))
 		return
 	}
 
-	tenant, err := h.service.CreateTenant(req)
+	tenant, err := h.service.CreateTenant(c.Request.Context(), req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -54,9 +55,9 @@ func (h *AdminHandler) CreateTenant(c *gin.Context) {
 }
 
 func (h *AdminHandler) GetAllTenants(c *gin.Context) {
-	tenants, err := h.service.GetAllTenants()
+	tenants, err := h.service.GetAllTenants(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -66,35 +67,75 @@ func (h *AdminHandler) GetAllTenants(c *gin.Context) {
 func (h *AdminHandler) GetTenant(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
 		return
 	}
 
-	tenant, err := h.service.GetTenantByID(uint(id))
+	tenant, err := h.service.GetTenantByID(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "tenant not found"})
+		apierror.Abort(c, apierror.NotFound("tenant not found"))
 		return
 	}
 
 	c.JSON(http.StatusOK, tenant)
 }
 
+func (h *AdminHandler) GetTenantStats(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	stats, err := h.service.GetTenantStats(c.Request.Context(), uint(id))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetSlowQueries lists the database's queries with the highest mean
+// execution time
+func (h *AdminHandler) GetSlowQueries(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		apierror.Abort(c, apierror.BadRequest("limit must be between 1 and 100"))
+		return
+	}
+
+	queries, err := h.service.GetSlowQueries(c.Request.Context(), limit)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, queries)
+}
+
+// TenantSettingsSchema serves the JSON schema tenant settings are validated
+// against, for editors of the settings
+func (h *AdminHandler) TenantSettingsSchema(c *gin.Context) {
+	c.Data(http.StatusOK, "application/schema+json", service.TenantSettingsSchemaJSON)
+}
+
 func (h *AdminHandler) UpdateTenant(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
 		return
 	}
 
 	var req service.UpdateTenantRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Abort(c, apierror.Validation(err))
 		return
 	}
 
-	tenant, err := h.service.UpdateTenant(uint(id), req)
+	tenant, err := h.service.UpdateTenant(c.Request.Context(), uint(id), req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -104,29 +145,45 @@ func (h *AdminHandler) UpdateTenant(c *gin.Context) {
 func (h *AdminHandler) DeleteTenant(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
 		return
 	}
 
-	if err := h.service.DeleteTenant(uint(id)); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	userID, _ := c.Get("userID")
+	tenantID, _ := c.Get("tenantID")
+
+	// Without a token the deletion is only requested and has to be confirmed
+	token := c.Query("confirmation_token")
+	if token == "" {
+		deletion, err := h.service.RequestTenantDeletion(c.Request.Context(), uint(id), userID.(uint), tenantID.(uint))
+		if err != nil {
+			respondError(c, http.StatusBadRequest, err)
+			return
+		}
+		c.JSON(http.StatusAccepted, deletion)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Tenant deleted successfully"})
+	deleted, err := h.service.DeleteTenant(c.Request.Context(), uint(id), userID.(uint), token)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tenant deleted successfully", "deleted": deleted})
 }
 
 // User Management Handlers
 func (h *AdminHandler) CreateUser(c *gin.Context) {
 	var req service.CreateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Abort(c, apierror.Validation(err))
 		return
 	}
 
-	user, err := h.service.CreateUser(req)
+	user, err := h.service.CreateUser(c.Request.Context(), req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -134,9 +191,9 @@ func (h *AdminHandler) CreateUser(c *gin.Context) {
 }
 
 func (h *AdminHandler) GetAllUsers(c *gin.Context) {
-	users, err := h.service.GetAllUsers()
+	users, err := h.service.GetAllUsers(c.Request.Context(), c.Query("sort"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -146,13 +203,13 @@ func (h *AdminHandler) GetAllUsers(c *gin.Context) {
 func (h *AdminHandler) GetUsersByTenant(c *gin.Context) {
 	tenantID, err := strconv.ParseUint(c.Param("tenantId"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tenant ID"})
+		apierror.Abort(c, apierror.BadRequest("Invalid tenant ID"))
 		return
 	}
 
-	users, err := h.service.GetUsersByTenant(uint(tenantID))
+	users, err := h.service.GetUsersByTenant(c.Request.Context(), uint(tenantID), c.Query("sort"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -162,35 +219,52 @@ func (h *AdminHandler) GetUsersByTenant(c *gin.Context) {
 func (h *AdminHandler) GetUser(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
 		return
 	}
 
-	user, err := h.service.GetUserByID(uint(id))
+	user, err := h.service.GetUserByID(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		apierror.Abort(c, apierror.NotFound("user not found"))
 		return
 	}
 
 	c.JSON(http.StatusOK, user)
 }
 
+// GetUserLoginHistory lists a user's recent login attempts
+func (h *AdminHandler) GetUserLoginHistory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	history, err := h.service.GetUserLoginHistory(c.Request.Context(), uint(id))
+	if err != nil {
+		apierror.Abort(c, apierror.NotFound("user not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
 func (h *AdminHandler) UpdateUser(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
 		return
 	}
 
 	var req service.UpdateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Abort(c, apierror.Validation(err))
 		return
 	}
 
-	user, err := h.service.UpdateUser(uint(id), req)
+	user, err := h.service.UpdateUser(c.Request.Context(), uint(id), req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -200,12 +274,12 @@ func (h *AdminHandler) UpdateUser(c *gin.Context) {
 func (h *AdminHandler) DeleteUser(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
 		return
 	}
 
-	if err := h.service.DeleteUser(uint(id)); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := h.service.DeleteUser(c.Request.Context(), uint(id)); err != nil {
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/export"
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/service"
+	"taxifleet/backend/internal/tracing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type AnalyticsHandler struct {
+	service  *service.AnalyticsService
+	branding *service.BrandingService
+}
+
+func NewAnalyticsHandler(service *service.AnalyticsService, branding *service.BrandingService) *AnalyticsHandler {
+	return &AnalyticsHandler{service: service, branding: branding}
+}
+
+func (h *AnalyticsHandler) Drivers(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+
+	analytics, err := h.service.GetDriverAnalytics(c.Request.Context(), tenantID.(uint), c.Query("from"), c.Query("to"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
+
+func (h *AnalyticsHandler) Taxis(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+
+	analytics, err := h.service.GetTaxiAnalytics(c.Request.Context(), tenantID.(uint), c.Query("from"), c.Query("to"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
+
+func (h *AnalyticsHandler) ProfitAndLoss(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+
+	pnl, err := h.service.GetProfitAndLoss(c.Request.Context(), tenantID.(uint), c.Query("month"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format == "json" {
+		c.JSON(http.StatusOK, pnl)
+		return
+	}
+
+	expenses := make([][]interface{}, 0, len(pnl.Expenses))
+	for _, expense := range pnl.Expenses {
+		expenses = append(expenses, []interface{}{expense.Category, expense.Amount})
+	}
+	expenses = append(expenses, []interface{}{"Total expenses", pnl.TotalExpenses})
+
+	doc := export.Document{
+		Title:    "Profit & Loss Statement",
+		Subtitle: "Month: " + pnl.Month,
+		Sheet:    "P&L " + pnl.Month,
+		Sections: []export.Section{
+			{
+				Title: "Revenue",
+				Rows:  [][]interface{}{{"Approved earnings", pnl.Revenue}},
+			},
+			{
+				Title:   "Expenses",
+				Headers: []string{"Category", "Amount"},
+				Rows:    expenses,
+			},
+			{
+				Title: "Result",
+				Rows:  [][]interface{}{{"Net profit", pnl.NetProfit}},
+			},
+			{
+				Title: "Bank deposits",
+				Rows: [][]interface{}{
+					{fmt.Sprintf("%d deposit(s)", pnl.DepositCount), pnl.Deposits},
+					{"Undeposited cash", pnl.UndepositedCash},
+				},
+			},
+		},
+	}
+
+	userID, _ := c.Get("userID")
+	if err := h.branding.Brand(c.Request.Context(), tenantID.(uint), userID.(uint), &doc); err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeDocument(c, doc, format, "pnl-"+pnl.Month)
+}
+
+func (h *AnalyticsHandler) TaxReport(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+
+	report, err := h.service.GetTaxReport(c.Request.Context(), tenantID.(uint), c.Query("year"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format == "json" {
+		c.JSON(http.StatusOK, report)
+		return
+	}
+
+	deductible := make([][]interface{}, 0, len(report.DeductibleExpenses)+2)
+	for _, expense := range report.DeductibleExpenses {
+		deductible = append(deductible, []interface{}{expense.Category, expense.Amount})
+	}
+	deductible = append(deductible,
+		[]interface{}{"Maintenance work", report.MaintenanceCosts},
+		[]interface{}{"Total deductible", report.TotalDeductible},
+	)
+
+	vehicles := make([][]interface{}, 0, len(report.Vehicles))
+	for _, vehicle := range report.Vehicles {
+		vehicles = append(vehicles, []interface{}{vehicle.LicensePlate, vehicle.Revenue, vehicle.Expenses, vehicle.MaintenanceCosts, vehicle.Net})
+	}
+
+	doc := export.Document{
+		Title:    fmt.Sprintf("Tax Report %d", report.FiscalYear),
+		Subtitle: fmt.Sprintf("Fiscal year %s - %s", formatDateDDMMYYYY(report.PeriodStart), formatDateDDMMYYYY(report.PeriodEnd)),
+		Sheet:    fmt.Sprintf("Tax %d", report.FiscalYear),
+		Sections: []export.Section{
+			{
+				Title: "Income",
+				Rows: [][]interface{}{
+					{"Gross revenue", report.GrossRevenue},
+					{"Taxable income", report.TaxableIncome},
+				},
+			},
+			{
+				Title:   "Deductible expenses",
+				Headers: []string{"Category", "Amount"},
+				Rows:    deductible,
+			},
+			{
+				Title:    "Per vehicle",
+				Headers:  []string{"Taxi", "Revenue", "Expenses", "Maintenance", "Net"},
+				Rows:     vehicles,
+				Summable: true,
+			},
+		},
+	}
+
+	userID, _ := c.Get("userID")
+	if err := h.branding.Brand(c.Request.Context(), tenantID.(uint), userID.(uint), &doc); err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeDocument(c, doc, format, fmt.Sprintf("tax-report-%d", report.FiscalYear))
+}
+
+// Journal exports the period's journal entries for import into accounting
+// software: format=csv gives a generic journal import file (Sage, Xero...),
+// format=iif a QuickBooks Desktop general journal
+func (h *AnalyticsHandler) Journal(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+
+	journal, err := h.service.GetJournal(c.Request.Context(), tenantID.(uint), c.Query("from"), c.Query("to"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	basename := fmt.Sprintf("journal-%s-%s", journal.Period.From.Format("20060102"), journal.Period.To.Format("20060102"))
+	var buf bytes.Buffer
+	var contentType string
+
+	format := c.DefaultQuery("format", "json")
+	switch format {
+	case "json":
+		c.JSON(http.StatusOK, journal)
+		return
+	case "csv":
+		section := export.Section{Headers: []string{"Date", "Reference", "Account", "Description", "Debit", "Credit"}}
+		for _, line := range journal.Lines {
+			section.Rows = append(section.Rows, []interface{}{line.Date, line.Reference, line.Account, line.Description, journalAmount(line.Debit), journalAmount(line.Credit)})
+		}
+		if err := export.WriteCSV(&buf, section); err != nil {
+			respondError(c, http.StatusInternalServerError, err)
+			return
+		}
+		contentType = "text/csv"
+	case "iif":
+		writeIIF(&buf, journal.Lines)
+		contentType = "text/plain"
+	default:
+		apierror.Abort(c, apierror.BadRequest("Unsupported format. Use 'json', 'csv' or 'iif'"))
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.%s", basename, format))
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+// journalAmount leaves the unused side of a journal line blank
+func journalAmount(amount money.Amount) interface{} {
+	if amount == 0 {
+		return nil
+	}
+	return amount
+}
+
+// writeIIF writes the journal lines as QuickBooks IIF general journal
+// transactions: the debit line of each entry opens a transaction and its
+// credit line is the split, with credits as negative amounts
+func writeIIF(w io.Writer, lines []service.JournalLine) {
+	fmt.Fprint(w, "!TRNS\tTRNSTYPE\tDATE\tACCNT\tDOCNUM\tAMOUNT\tMEMO\n")
+	fmt.Fprint(w, "!SPL\tTRNSTYPE\tDATE\tACCNT\tDOCNUM\tAMOUNT\tMEMO\n")
+	fmt.Fprint(w, "!ENDTRNS\n")
+	for _, line := range lines {
+		kind, amount := "TRNS", line.Debit
+		if line.Credit != 0 {
+			kind, amount = "SPL", -line.Credit
+		}
+		date := line.Date
+		if t, err := time.Parse("2006-01-02", line.Date); err == nil {
+			date = t.Format("01/02/2006")
+		}
+		memo := strings.NewReplacer("\t", " ", "\n", " ").Replace(line.Description)
+		fmt.Fprintf(w, "%s\tGENERAL JOURNAL\t%s\t%s\t%s\t%s\t%s\n", kind, date, line.Account, line.Reference, amount, memo)
+		if kind == "SPL" {
+			fmt.Fprint(w, "ENDTRNS\n")
+		}
+	}
+}
+
+// formatDateDDMMYYYY formats a date as dd/mm/yyyy like the other exports
+func formatDateDDMMYYYY(t time.Time) string {
+	return fmt.Sprintf("%02d/%02d/%d", t.Day(), t.Month(), t.Year())
+}
+
+// writeDocument renders the document in the requested export format
+func writeDocument(c *gin.Context, doc export.Document, format string, basename string) {
+	var buf bytes.Buffer
+	var contentType string
+	var render func(io.Writer, export.Document) error
+
+	switch format {
+	case "xlsx":
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+		render = export.WriteXLSX
+	case "pdf":
+		contentType = "application/pdf"
+		render = export.WritePDF
+	default:
+		apierror.Abort(c, apierror.BadRequest("Unsupported format. Use 'json', 'xlsx' or 'pdf'"))
+		return
+	}
+
+	_, span := tracing.Start(c.Request.Context(), "export."+format, attribute.String("export.document", basename))
+	err := render(&buf, doc)
+	tracing.End(span, err)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.%s", basename, format))
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
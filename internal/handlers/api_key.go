@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyHandler struct {
+	service *service.APIKeyService
+}
+
+func NewAPIKeyHandler(service *service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{service: service}
+}
+
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req service.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	key, err := h.service.Create(c.Request.Context(), userID.(uint), req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, key)
+}
+
+func (h *APIKeyHandler) List(c *gin.Context) {
+	var tenantID uint64
+	if raw := c.Query("tenant_id"); raw != "" {
+		var err error
+		if tenantID, err = strconv.ParseUint(raw, 10, 32); err != nil {
+			apierror.Abort(c, apierror.BadRequest("Invalid tenant_id"))
+			return
+		}
+	}
+
+	keys, err := h.service.List(c.Request.Context(), uint(tenantID))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	key, err := h.service.Revoke(c.Request.Context(), uint(id))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, key)
+}
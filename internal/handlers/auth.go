@@ -3,6 +3,7 @@ package handlers
 import (
 	"net/http"
 
+	"taxifleet/backend/internal/apierror"
 	"taxifleet/backend/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -16,32 +17,16 @@ func NewAuthHandler(service *service.AuthService) *AuthHandler {
 	return &AuthHandler{service: service}
 }
 
-func (h *AuthHandler) Register(c *gin.Context) {
-	var req service.RegisterRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	response, err := h.service.Register(req)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	c.JSON(http.StatusCreated, response)
-}
-
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req service.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Abort(c, apierror.Validation(err))
 		return
 	}
 
-	response, err := h.service.Login(req)
+	response, err := h.service.Login(c.Request.Context(), req, loginClient(c))
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondError(c, http.StatusUnauthorized, err)
 		return
 	}
 
@@ -50,17 +35,17 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 func (h *AuthHandler) Refresh(c *gin.Context) {
 	var req struct {
-		RefreshToken string `json:"refresh_token" binding:"required"`
+		RefreshToken string `json:"refresh_token" binding:"required" sanitize:"-"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Abort(c, apierror.Validation(err))
 		return
 	}
 
-	token, err := h.service.RefreshToken(req.RefreshToken)
+	token, err := h.service.RefreshToken(c.Request.Context(), req.RefreshToken)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondError(c, http.StatusUnauthorized, err)
 		return
 	}
 
@@ -69,16 +54,16 @@ func (h *AuthHandler) Refresh(c *gin.Context) {
 
 func (h *AuthHandler) Logout(c *gin.Context) {
 	var req struct {
-		RefreshToken string `json:"refresh_token" binding:"required"`
+		RefreshToken string `json:"refresh_token" binding:"required" sanitize:"-"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Abort(c, apierror.Validation(err))
 		return
 	}
 
-	if err := h.service.Logout(req.RefreshToken); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := h.service.Logout(c.Request.Context(), req.RefreshToken); err != nil {
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -86,33 +71,71 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 }
 
 func (h *AuthHandler) Me(c *gin.Context) {
-	user, exists := c.Get("user")
+	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		apierror.Abort(c, apierror.Unauthorized("User not found"))
+		return
+	}
+
+	user, err := h.service.GetUser(c.Request.Context(), userID.(uint))
+	if err != nil {
+		respondError(c, http.StatusNotFound, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, user)
 }
 
+// LoginHistory lists the signed-in user's recent login attempts
+func (h *AuthHandler) LoginHistory(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		apierror.Abort(c, apierror.Unauthorized("User not found"))
+		return
+	}
+
+	history, err := h.service.GetLoginHistory(c.Request.Context(), userID.(uint))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// JWKS publishes the public keys access tokens are signed with
+func (h *AuthHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.service.JWKS())
+}
+
 func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		apierror.Abort(c, apierror.Unauthorized("User not found"))
 		return
 	}
 
 	var req service.UpdateProfileRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Abort(c, apierror.Validation(err))
 		return
 	}
 
-	updatedUser, err := h.service.UpdateProfile(userID.(uint), req)
+	updatedUser, err := h.service.UpdateProfile(c.Request.Context(), userID.(uint), req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, updatedUser)
 }
+
+// loginClient describes the device a login request comes from, for auditing
+func loginClient(c *gin.Context) service.LoginClient {
+	return service.LoginClient{
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		DeviceID:  c.GetHeader("X-Device-ID"),
+	}
+}
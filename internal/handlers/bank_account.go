@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BankAccountHandler struct {
+	service *service.BankAccountService
+}
+
+func NewBankAccountHandler(service *service.BankAccountService) *BankAccountHandler {
+	return &BankAccountHandler{service: service}
+}
+
+func (h *BankAccountHandler) List(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+
+	accounts, err := h.service.List(c.Request.Context(), tenantID.(uint))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, accounts)
+}
+
+// Totals returns the number and amount of deposits made into each account
+func (h *BankAccountHandler) Totals(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+
+	totals, err := h.service.DepositTotals(c.Request.Context(), tenantID.(uint))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, totals)
+}
+
+func (h *BankAccountHandler) Create(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+
+	var req service.CreateBankAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	account, err := h.service.Create(c.Request.Context(), tenantID.(uint), req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, account)
+}
+
+func (h *BankAccountHandler) Get(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	account, err := h.service.GetByID(c.Request.Context(), uint(id), tenantID.(uint))
+	if err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, account)
+}
+
+func (h *BankAccountHandler) Update(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	var req service.UpdateBankAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	account, err := h.service.Update(c.Request.Context(), uint(id), tenantID.(uint), req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, account)
+}
+
+func (h *BankAccountHandler) Delete(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), uint(id), tenantID.(uint)); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Bank account deleted successfully"})
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BudgetHandler struct {
+	service *service.BudgetService
+}
+
+func NewBudgetHandler(service *service.BudgetService) *BudgetHandler {
+	return &BudgetHandler{service: service}
+}
+
+// List returns the tenant's budgets with their spending in month=YYYY-MM,
+// by default the current month
+func (h *BudgetHandler) List(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+
+	budgets, err := h.service.List(c.Request.Context(), tenantID.(uint), c.Query("month"))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, budgets)
+}
+
+func (h *BudgetHandler) Create(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+
+	var req service.BudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	budget, err := h.service.Create(c.Request.Context(), tenantID.(uint), req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, budget)
+}
+
+// Update replaces the budget
+func (h *BudgetHandler) Update(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	var req service.BudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	budget, err := h.service.Update(c.Request.Context(), tenantID.(uint), uint(id), req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, budget)
+}
+
+func (h *BudgetHandler) Delete(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), tenantID.(uint), uint(id)); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Budget deleted successfully"})
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"net/http"
+	"taxifleet/backend/internal/apierror"
 	"taxifleet/backend/internal/permissions"
 	"taxifleet/backend/internal/service"
 
@@ -34,16 +35,28 @@ func (h *DashboardHandler) GetStats(c *gin.Context) {
 			permissions.HasPermission(userPerm, permissions.PermissionViewExpenses)
 
 		if !hasFinancialAccess {
-			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to view dashboard"})
+			apierror.Abort(c, apierror.Forbidden("You don't have permission to view dashboard"))
 			return
 		}
 	}
 
-	stats, err := h.service.GetStats(tenantID.(uint))
+	stats, err := h.service.GetStats(c.Request.Context(), tenantID.(uint))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, stats)
 }
+
+func (h *DashboardHandler) GetTimeSeries(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+
+	series, err := h.service.GetTimeSeries(c.Request.Context(), tenantID.(uint), c.Query("metric"), c.Query("interval"), c.Query("from"), c.Query("to"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DelegationHandler struct {
+	service *service.DelegationService
+}
+
+func NewDelegationHandler(service *service.DelegationService) *DelegationHandler {
+	return &DelegationHandler{service: service}
+}
+
+// List returns the tenant's delegations, revoked and past ones included
+func (h *DelegationHandler) List(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	permission, _ := c.Get("permission")
+
+	delegations, err := h.service.List(c.Request.Context(), tenantID.(uint), permission.(int))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, delegations)
+}
+
+func (h *DelegationHandler) Create(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+	permission, _ := c.Get("permission")
+
+	var req service.CreateDelegationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	delegation, err := h.service.Create(c.Request.Context(), tenantID.(uint), userID.(uint), permission.(int), req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, delegation)
+}
+
+// Revoke ends a delegation early
+func (h *DelegationHandler) Revoke(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+	permission, _ := c.Get("permission")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	if err := h.service.Revoke(c.Request.Context(), tenantID.(uint), userID.(uint), permission.(int), uint(id)); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Delegation revoked successfully"})
+}
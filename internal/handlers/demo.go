@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DemoHandler struct {
+	service *service.DemoService
+}
+
+func NewDemoHandler(service *service.DemoService) *DemoHandler {
+	return &DemoHandler{service: service}
+}
+
+func (h *DemoHandler) Generate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	var req service.DemoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	summary, err := h.service.Generate(c.Request.Context(), uint(id), req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, summary)
+}
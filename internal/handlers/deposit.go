@@ -1,37 +1,39 @@
 package handlers
 
 import (
-	"encoding/csv"
 	"fmt"
 	"math/rand"
 	"net/http"
 	"strconv"
 	"time"
 
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/export"
 	"taxifleet/backend/internal/permissions"
 	"taxifleet/backend/internal/service"
+	"taxifleet/backend/internal/tracing"
 
 	"github.com/gin-gonic/gin"
-	"github.com/xuri/excelize/v2"
 )
 
 type DepositHandler struct {
-	service *service.DepositService
+	service  *service.DepositService
+	branding *service.BrandingService
 }
 
-func NewDepositHandler(service *service.DepositService) *DepositHandler {
-	return &DepositHandler{service: service}
+func NewDepositHandler(service *service.DepositService, branding *service.BrandingService) *DepositHandler {
+	return &DepositHandler{service: service, branding: branding}
 }
 
 func (h *DepositHandler) List(c *gin.Context) {
 	tenantID, _ := c.Get("tenantID")
-	deposits, err := h.service.List(tenantID.(uint))
+	deposits, err := h.service.List(c.Request.Context(), tenantID.(uint), c.Query("sort"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, deposits)
+	c.JSON(http.StatusOK, depositList(deposits))
 }
 
 func (h *DepositHandler) Create(c *gin.Context) {
@@ -39,13 +41,13 @@ func (h *DepositHandler) Create(c *gin.Context) {
 
 	var req service.CreateDepositRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Abort(c, apierror.Validation(err))
 		return
 	}
 
-	deposit, err := h.service.Create(tenantID.(uint), req)
+	deposit, err := h.service.Create(c.Request.Context(), tenantID.(uint), req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -56,13 +58,13 @@ func (h *DepositHandler) Get(c *gin.Context) {
 	tenantID, _ := c.Get("tenantID")
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
 		return
 	}
 
-	deposit, err := h.service.GetByID(uint(id), tenantID.(uint))
+	deposit, err := h.service.GetByID(c.Request.Context(), uint(id), tenantID.(uint))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		respondError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -73,19 +75,19 @@ func (h *DepositHandler) Update(c *gin.Context) {
 	tenantID, _ := c.Get("tenantID")
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
 		return
 	}
 
 	var req service.UpdateDepositRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Abort(c, apierror.Validation(err))
 		return
 	}
 
-	deposit, err := h.service.Update(uint(id), tenantID.(uint), req)
+	deposit, err := h.service.Update(c.Request.Context(), uint(id), tenantID.(uint), req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -96,32 +98,55 @@ func (h *DepositHandler) Delete(c *gin.Context) {
 	tenantID, _ := c.Get("tenantID")
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
 		return
 	}
 
-	if err := h.service.Delete(uint(id), tenantID.(uint)); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := h.service.Delete(c.Request.Context(), uint(id), tenantID.(uint)); err != nil {
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Deposit deleted successfully"})
 }
 
+func (h *DepositHandler) Verify(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+	permission, _ := c.Get("permission")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	deposit, err := h.service.Verify(c.Request.Context(), uint(id), tenantID.(uint), userID.(uint), permission.(int))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, deposit)
+}
+
 func (h *DepositHandler) Export(c *gin.Context) {
+	ctx, span := tracing.Start(c.Request.Context(), "DepositHandler.Export")
+	defer span.End()
+
 	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
 	permission, _ := c.Get("permission")
 
 	// Check if user has permission to export (owner or manager)
 	userPerm := permission.(int)
 	if !permissions.HasPermission(userPerm, permissions.PermissionViewDeposits) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to export deposits"})
+		apierror.Abort(c, apierror.Forbidden("You don't have permission to export deposits"))
 		return
 	}
 
-	deposits, err := h.service.List(tenantID.(uint))
+	deposits, err := h.service.List(ctx, tenantID.(uint), "")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -134,90 +159,29 @@ func (h *DepositHandler) Export(c *gin.Context) {
 	rand.Seed(time.Now().UnixNano())
 	randomID := rand.Intn(1000000)
 	dateStr := time.Now().Format("20060102")
-	filename := fmt.Sprintf("deposits-%d-%s.%s", randomID, dateStr, format)
+	basename := fmt.Sprintf("deposits-%d-%s", randomID, dateStr)
+	filename := basename + "." + format
 
-	// Helper function to format date as dd/month/year
-	formatDateDDMMYYYY := func(t time.Time) string {
-		return fmt.Sprintf("%02d/%02d/%d", t.Day(), t.Month(), t.Year())
+	section := service.DepositSection(deposits)
+	if err := h.branding.ApplyTemplate(ctx, tenantID.(uint), "deposits", &section); err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
 	}
 
 	if format == "csv" {
 		c.Header("Content-Type", "text/csv")
 		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
-
-		writer := csv.NewWriter(c.Writer)
-		defer writer.Flush()
-
-		// Write header
-		writer.Write([]string{"ID", "Deposit Date", "Amount", "Bank Account", "Period Start", "Period End", "Notes", "Created At"})
-
-		// Write data
-		for _, deposit := range deposits {
-			writer.Write([]string{
-				strconv.Itoa(int(deposit.ID)),
-				formatDateDDMMYYYY(deposit.DepositDate),
-				strconv.FormatFloat(deposit.Amount, 'f', 2, 64),
-				deposit.BankAccount,
-				formatDateDDMMYYYY(deposit.PeriodStart),
-				formatDateDDMMYYYY(deposit.PeriodEnd),
-				deposit.Notes,
-				formatDateDDMMYYYY(deposit.CreatedAt),
-			})
+		if err := export.WriteCSV(c.Writer, section); err != nil {
+			respondError(c, http.StatusInternalServerError, err)
 		}
 	} else if format == "xlsx" {
-		f := excelize.NewFile()
-		defer func() {
-			if err := f.Close(); err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			}
-		}()
-
-		sheetName := "Deposits"
-		index, err := f.NewSheet(sheetName)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		f.SetActiveSheet(index)
-
-		// Write header
-		headers := []string{"ID", "Deposit Date", "Amount", "Bank Account", "Period Start", "Period End", "Notes", "Created At"}
-		for i, header := range headers {
-			cell, _ := excelize.CoordinatesToCellName(i+1, 1)
-			f.SetCellValue(sheetName, cell, header)
-		}
-
-		// Write data
-		for rowIdx, deposit := range deposits {
-			row := rowIdx + 2
-			cell1, _ := excelize.CoordinatesToCellName(1, row)
-			cell2, _ := excelize.CoordinatesToCellName(2, row)
-			cell3, _ := excelize.CoordinatesToCellName(3, row)
-			cell4, _ := excelize.CoordinatesToCellName(4, row)
-			cell5, _ := excelize.CoordinatesToCellName(5, row)
-			cell6, _ := excelize.CoordinatesToCellName(6, row)
-			cell7, _ := excelize.CoordinatesToCellName(7, row)
-			cell8, _ := excelize.CoordinatesToCellName(8, row)
-			f.SetCellValue(sheetName, cell1, deposit.ID)
-			f.SetCellValue(sheetName, cell2, formatDateDDMMYYYY(deposit.DepositDate))
-			f.SetCellValue(sheetName, cell3, deposit.Amount)
-			f.SetCellValue(sheetName, cell4, deposit.BankAccount)
-			f.SetCellValue(sheetName, cell5, formatDateDDMMYYYY(deposit.PeriodStart))
-			f.SetCellValue(sheetName, cell6, formatDateDDMMYYYY(deposit.PeriodEnd))
-			f.SetCellValue(sheetName, cell7, deposit.Notes)
-			f.SetCellValue(sheetName, cell8, formatDateDDMMYYYY(deposit.CreatedAt))
-		}
-
-		// Remove default sheet
-		f.DeleteSheet("Sheet1")
-
-		c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
-		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
-		if err := f.Write(c.Writer); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		doc := export.Document{Sheet: "Deposits", Sections: []export.Section{section}}
+		if err := h.branding.Brand(ctx, tenantID.(uint), userID.(uint), &doc); err != nil {
+			respondError(c, http.StatusInternalServerError, err)
 			return
 		}
+		writeDocument(c, doc, format, basename)
 	} else {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported format. Use 'csv' or 'xlsx'"})
+		apierror.Abort(c, apierror.BadRequest("Unsupported format. Use 'csv' or 'xlsx'"))
 	}
 }
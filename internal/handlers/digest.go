@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DigestHandler struct {
+	service *service.DigestService
+}
+
+func NewDigestHandler(service *service.DigestService) *DigestHandler {
+	return &DigestHandler{service: service}
+}
+
+type digestSubscriptionRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+// Preview returns the digest the tenant's owners would receive now:
+// format=json (default) gives the figures, format=html the rendered email
+func (h *DigestHandler) Preview(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+
+	digest, err := h.service.Build(c.Request.Context(), tenantID.(uint))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	switch c.DefaultQuery("format", "json") {
+	case "json":
+		c.JSON(http.StatusOK, digest)
+	case "html":
+		msg, err := service.RenderDigest(digest)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, err)
+			return
+		}
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(msg.HTML))
+	default:
+		apierror.Abort(c, apierror.BadRequest("Unsupported format. Use 'json' or 'html'"))
+	}
+}
+
+// UpdateSubscription opts the current user in or out of the weekly digest
+func (h *DigestHandler) UpdateSubscription(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req digestSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	if err := h.service.SetSubscription(c.Request.Context(), userID.(uint), *req.Enabled); err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"weekly_digest": *req.Enabled})
+}
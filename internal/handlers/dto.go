@@ -0,0 +1,307 @@
+package handlers
+
+import (
+	"time"
+
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// v1 responses are the stored records as they are, except lists, which use
+// the lean list items below in every version. From v2 on, handlers map
+// records to the DTOs below so the API no longer changes with the database
+// models; a v2 DTO only changes with a new API version.
+
+// apiVersion is the API version the request was made against
+func apiVersion(c *gin.Context) int {
+	if version, ok := c.Get("apiVersion"); ok {
+		return version.(int)
+	}
+	return 1
+}
+
+// versioned returns value as the request's API version represents it:
+// unchanged in v1, mapped by v2 from then on
+func versioned[T any](c *gin.Context, value T, v2 func(T) any) any {
+	if apiVersion(c) >= 2 {
+		return v2(value)
+	}
+	return value
+}
+
+// UserRefV2 identifies a user embedded in another resource
+type UserRefV2 struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+func userRefV2(user *repository.User) *UserRefV2 {
+	if user == nil {
+		return nil
+	}
+	return &UserRefV2{ID: user.ID, Name: user.FirstName + " " + user.LastName}
+}
+
+// TaxiV2 is a taxi in v2. Unlike v1 it leaves out the tenant and embeds only
+// the ID and name of the assigned driver.
+type TaxiV2 struct {
+	ID            uint                      `json:"id"`
+	LicensePlate  string                    `json:"license_plate"`
+	Model         string                    `json:"model"`
+	Year          int                       `json:"year"`
+	Color         string                    `json:"color"`
+	VIN           string                    `json:"vin"`
+	Status        string                    `json:"status"`
+	Mileage       int                       `json:"mileage"`
+	Driver        *UserRefV2                `json:"driver"`
+	EarningsSplit *repository.EarningsSplit `json:"earnings_split"`
+	Version       int                       `json:"version"`
+	CreatedAt     time.Time                 `json:"created_at"`
+	UpdatedAt     time.Time                 `json:"updated_at"`
+}
+
+func taxiV2(taxi *repository.Taxi) any {
+	return TaxiV2{
+		ID:            taxi.ID,
+		LicensePlate:  taxi.LicensePlate,
+		Model:         taxi.Model,
+		Year:          taxi.Year,
+		Color:         taxi.Color,
+		VIN:           taxi.VIN,
+		Status:        taxi.Status,
+		Mileage:       taxi.Mileage,
+		Driver:        userRefV2(taxi.AssignedDriver),
+		EarningsSplit: taxi.EarningsSplit,
+		Version:       taxi.Version,
+		CreatedAt:     taxi.CreatedAt,
+		UpdatedAt:     taxi.UpdatedAt,
+	}
+}
+
+func taxisV2(taxis []repository.Taxi) any {
+	dtos := make([]any, len(taxis))
+	for i := range taxis {
+		dtos[i] = taxiV2(&taxis[i])
+	}
+	return dtos
+}
+
+// List items keep the JSON keys of the records but leave out the tenant and
+// embed only a summary of the taxis and users they reference, which lists of
+// hundreds of records otherwise repeat in full.
+
+// TaxiSummary is a taxi embedded in a list item
+type TaxiSummary struct {
+	ID           uint   `json:"id"`
+	LicensePlate string `json:"license_plate"`
+	Model        string `json:"model"`
+}
+
+func taxiSummary(taxi *repository.Taxi) *TaxiSummary {
+	if taxi == nil || taxi.ID == 0 {
+		return nil
+	}
+	return &TaxiSummary{ID: taxi.ID, LicensePlate: taxi.LicensePlate, Model: taxi.Model}
+}
+
+// UserSummary is a user embedded in a list item
+type UserSummary struct {
+	ID        uint   `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+func userSummary(user *repository.User) *UserSummary {
+	if user == nil || user.ID == 0 {
+		return nil
+	}
+	return &UserSummary{ID: user.ID, FirstName: user.FirstName, LastName: user.LastName}
+}
+
+// TaxiListItem is a taxi in the v1 taxi list
+type TaxiListItem struct {
+	ID               uint                      `json:"id"`
+	LicensePlate     string                    `json:"license_plate"`
+	Model            string                    `json:"model"`
+	Year             int                       `json:"year"`
+	Color            string                    `json:"color"`
+	VIN              string                    `json:"vin"`
+	Status           string                    `json:"status"`
+	Mileage          int                       `json:"mileage"`
+	AssignedDriverID *uint                     `json:"assigned_driver_id"`
+	Driver           *UserSummary              `json:"driver,omitempty"`
+	EarningsSplit    *repository.EarningsSplit `json:"earnings_split"`
+	Version          int                       `json:"version"`
+	CreatedAt        time.Time                 `json:"created_at"`
+	UpdatedAt        time.Time                 `json:"updated_at"`
+}
+
+func taxiList(taxis []repository.Taxi) []TaxiListItem {
+	items := make([]TaxiListItem, len(taxis))
+	for i, taxi := range taxis {
+		items[i] = TaxiListItem{
+			ID:               taxi.ID,
+			LicensePlate:     taxi.LicensePlate,
+			Model:            taxi.Model,
+			Year:             taxi.Year,
+			Color:            taxi.Color,
+			VIN:              taxi.VIN,
+			Status:           taxi.Status,
+			Mileage:          taxi.Mileage,
+			AssignedDriverID: taxi.AssignedDriverID,
+			Driver:           userSummary(taxi.AssignedDriver),
+			EarningsSplit:    taxi.EarningsSplit,
+			Version:          taxi.Version,
+			CreatedAt:        taxi.CreatedAt,
+			UpdatedAt:        taxi.UpdatedAt,
+		}
+	}
+	return items
+}
+
+// ReportListItem is a weekly report in a list. The expenses, attachments and
+// adjustments are left to the report itself.
+type ReportListItem struct {
+	ID               uint          `json:"id"`
+	TaxiID           uint          `json:"taxi_id"`
+	DriverID         uint          `json:"driver_id"`
+	WeekStartDate    time.Time     `json:"week_start_date"`
+	Earnings         money.Amount  `json:"earnings"`
+	TotalExpenses    money.Amount  `json:"total_expenses"`
+	TotalAdjustments money.Amount  `json:"total_adjustments"`
+	Status           string        `json:"status"`
+	Notes            string        `json:"notes"`
+	SubmittedAt      *time.Time    `json:"submitted_at"`
+	ApprovedAt       *time.Time    `json:"approved_at"`
+	DriverShare      *money.Amount `json:"driver_share"`
+	OwnerShare       *money.Amount `json:"owner_share"`
+	Version          int           `json:"version"`
+	ArchivedAt       *time.Time    `json:"archived_at"`
+	CreatedAt        time.Time     `json:"created_at"`
+	UpdatedAt        time.Time     `json:"updated_at"`
+	Taxi             *TaxiSummary  `json:"taxi,omitempty"`
+	Driver           *UserSummary  `json:"driver,omitempty"`
+}
+
+func reportList(reports []repository.WeeklyReport) []ReportListItem {
+	items := make([]ReportListItem, len(reports))
+	for i := range reports {
+		report := &reports[i]
+		items[i] = ReportListItem{
+			ID:               report.ID,
+			TaxiID:           report.TaxiID,
+			DriverID:         report.DriverID,
+			WeekStartDate:    report.WeekStartDate,
+			Earnings:         report.Earnings,
+			TotalExpenses:    report.TotalExpenses,
+			TotalAdjustments: report.TotalAdjustments,
+			Status:           report.Status,
+			Notes:            report.Notes,
+			SubmittedAt:      report.SubmittedAt,
+			ApprovedAt:       report.ApprovedAt,
+			DriverShare:      report.DriverShare,
+			OwnerShare:       report.OwnerShare,
+			Version:          report.Version,
+			ArchivedAt:       report.ArchivedAt,
+			CreatedAt:        report.CreatedAt,
+			UpdatedAt:        report.UpdatedAt,
+			Taxi:             taxiSummary(&report.Taxi),
+			Driver:           userSummary(&report.Driver),
+		}
+	}
+	return items
+}
+
+// ExpenseListItem is an expense in a list
+type ExpenseListItem struct {
+	ID          uint         `json:"id"`
+	ReportID    *uint        `json:"report_id"`
+	TaxiID      *uint        `json:"taxi_id"`
+	Category    string       `json:"category"`
+	Amount      money.Amount `json:"amount"`
+	Reason      string       `json:"reason"`
+	ReceiptURL  string       `json:"receipt_url"`
+	Date        time.Time    `json:"date"`
+	CreatedByID uint         `json:"created_by_id"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+	Taxi        *TaxiSummary `json:"taxi,omitempty"`
+	CreatedBy   *UserSummary `json:"created_by,omitempty"`
+}
+
+func expenseList(expenses []repository.Expense) []ExpenseListItem {
+	items := make([]ExpenseListItem, len(expenses))
+	for i := range expenses {
+		expense := &expenses[i]
+		items[i] = ExpenseListItem{
+			ID:          expense.ID,
+			ReportID:    expense.ReportID,
+			TaxiID:      expense.TaxiID,
+			Category:    expense.Category,
+			Amount:      expense.Amount,
+			Reason:      expense.Reason,
+			ReceiptURL:  expense.ReceiptURL,
+			Date:        expense.Date,
+			CreatedByID: expense.CreatedByID,
+			CreatedAt:   expense.CreatedAt,
+			UpdatedAt:   expense.UpdatedAt,
+			Taxi:        taxiSummary(expense.Taxi),
+			CreatedBy:   userSummary(&expense.CreatedBy),
+		}
+	}
+	return items
+}
+
+// DepositListItem is a bank deposit in a list
+type DepositListItem struct {
+	ID            uint            `json:"id"`
+	TaxiID        *uint           `json:"taxi_id"`
+	Amount        money.Amount    `json:"amount"`
+	DepositDate   time.Time       `json:"deposit_date"`
+	PeriodStart   time.Time       `json:"period_start"`
+	PeriodEnd     time.Time       `json:"period_end"`
+	BankAccountID *uint           `json:"bank_account_id"`
+	BankAccount   string          `json:"bank_account"`
+	ProofURL      string          `json:"proof_url"`
+	Notes         string          `json:"notes"`
+	Status        string          `json:"status"`
+	VerifiedAt    *time.Time      `json:"verified_at"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+	Account       *AccountSummary `json:"account,omitempty"`
+}
+
+// AccountSummary is a bank account embedded in a list item
+type AccountSummary struct {
+	ID    uint   `json:"id"`
+	Label string `json:"label"`
+}
+
+func depositList(deposits []repository.BankDeposit) []DepositListItem {
+	items := make([]DepositListItem, len(deposits))
+	for i := range deposits {
+		deposit := &deposits[i]
+		items[i] = DepositListItem{
+			ID:            deposit.ID,
+			TaxiID:        deposit.TaxiID,
+			Amount:        deposit.Amount,
+			DepositDate:   deposit.DepositDate,
+			PeriodStart:   deposit.PeriodStart,
+			PeriodEnd:     deposit.PeriodEnd,
+			BankAccountID: deposit.BankAccountID,
+			BankAccount:   deposit.BankAccount,
+			ProofURL:      deposit.ProofURL,
+			Notes:         deposit.Notes,
+			Status:        deposit.Status,
+			VerifiedAt:    deposit.VerifiedAt,
+			CreatedAt:     deposit.CreatedAt,
+			UpdatedAt:     deposit.UpdatedAt,
+		}
+		if deposit.Account != nil {
+			items[i].Account = &AccountSummary{ID: deposit.Account.ID, Label: deposit.Account.Label}
+		}
+	}
+	return items
+}
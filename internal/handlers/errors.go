@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/ocr"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/service"
+	"taxifleet/backend/internal/validation"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// serviceErrors maps errors with a known meaning to their API error, in
+// order of precedence
+var serviceErrors = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{gorm.ErrRecordNotFound, http.StatusNotFound, "not_found"},
+	{repository.ErrVersionConflict, http.StatusConflict, "version_conflict"},
+	{repository.ErrInsufficientStock, http.StatusConflict, "insufficient_stock"},
+	{service.ErrLicensePlateTaken, http.StatusConflict, "license_plate_taken"},
+	{service.ErrDuplicateSKU, http.StatusConflict, "duplicate_sku"},
+	{service.ErrDuplicateReport, http.StatusConflict, "duplicate_report"},
+	{service.ErrDuplicateBudget, http.StatusConflict, "duplicate_budget"},
+	{service.ErrReportNotApproved, http.StatusConflict, "report_not_approved"},
+	{service.ErrBankAccountInUse, http.StatusConflict, "bank_account_in_use"},
+	{service.ErrFineCharged, http.StatusConflict, "fine_charged"},
+	{service.ErrExpenseApproved, http.StatusConflict, "expense_approved"},
+	{service.ErrWrongPIN, http.StatusForbidden, "wrong_pin"},
+	{service.ErrPeriodClosed, http.StatusConflict, "period_closed"},
+	{service.ErrFuelCardTaken, http.StatusConflict, "fuel_card_taken"},
+	{service.ErrPlanNameTaken, http.StatusConflict, "plan_name_taken"},
+	{service.ErrPlanInUse, http.StatusConflict, "plan_in_use"},
+	{service.ErrPlanLimitReached, http.StatusForbidden, "plan_limit_reached"},
+	{service.ErrAttachmentTooLarge, http.StatusRequestEntityTooLarge, "attachment_too_large"},
+	{service.ErrAttachmentType, http.StatusUnsupportedMediaType, "unsupported_attachment_type"},
+	{service.ErrAttachmentScanPending, http.StatusConflict, "attachment_scan_pending"},
+	{service.ErrAttachmentQuarantined, http.StatusForbidden, "attachment_quarantined"},
+	{service.ErrReceiptType, http.StatusUnsupportedMediaType, "unsupported_receipt_type"},
+	{ocr.ErrDisabled, http.StatusNotImplemented, "ocr_disabled"},
+	{service.ErrQueryStatsDisabled, http.StatusNotImplemented, "query_stats_disabled"},
+	{service.ErrExportNotFound, http.StatusNotFound, "not_found"},
+	{service.ErrBundleNotReady, http.StatusConflict, "bundle_not_ready"},
+	{service.ErrNotificationNotFound, http.StatusNotFound, "not_found"},
+	{service.ErrSettingNotFound, http.StatusNotFound, "not_found"},
+	{service.ErrInvalidConfirmationToken, http.StatusBadRequest, "invalid_confirmation_token"},
+	{service.ErrOwnTenant, http.StatusConflict, "own_tenant"},
+	{service.ErrOAuthEmailNotVerified, http.StatusUnauthorized, "email_not_verified"},
+	{service.ErrOAuthNoMatchingUser, http.StatusUnauthorized, "no_matching_user"},
+	{service.ErrEmailNotVerified, http.StatusUnauthorized, "email_not_verified"},
+	{service.ErrInvalidInviteCode, http.StatusForbidden, "invalid_invite_code"},
+	{service.ErrInvalidVerificationToken, http.StatusBadRequest, "invalid_verification_token"},
+
+	// Kinds of errors last, so the errors above keep their own codes
+	{service.ErrNotFound, http.StatusNotFound, "not_found"},
+	{service.ErrForbidden, http.StatusForbidden, "forbidden"},
+	{service.ErrConflict, http.StatusConflict, "conflict"},
+	{service.ErrValidation, http.StatusBadRequest, "validation_failed"},
+}
+
+// respondError writes an error returned by a service. Known errors get their
+// own status and code; any other error is reported with the given status.
+func respondError(c *gin.Context, status int, err error) {
+	apierror.Abort(c, toAPIError(err, status))
+}
+
+func toAPIError(err error, status int) *apierror.Error {
+	var apiErr *apierror.Error
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+
+	// Documents checked as a whole, e.g. tenant settings against their schema
+	var fieldErrs validation.FieldErrors
+	if errors.As(err, &fieldErrs) {
+		details := make([]apierror.FieldError, len(fieldErrs))
+		for i, fieldErr := range fieldErrs {
+			details[i] = apierror.FieldError{Field: fieldErr.Field, Rule: fieldErr.Rule}
+		}
+		return apierror.New(http.StatusBadRequest, "validation_failed", err.Error()).WithDetails(details)
+	}
+
+	// Domain rules checked by services, e.g. the tenant's license plate format
+	var fieldErr *validation.FieldError
+	if errors.As(err, &fieldErr) {
+		return apierror.New(http.StatusBadRequest, "validation_failed", fieldErr.Message).
+			WithDetails([]apierror.FieldError{{Field: fieldErr.Field, Rule: fieldErr.Rule}})
+	}
+
+	for _, known := range serviceErrors {
+		if errors.Is(err, known.err) {
+			return apierror.New(known.status, known.code, err.Error())
+		}
+	}
+
+	if status >= http.StatusInternalServerError {
+		return apierror.Internal(err)
+	}
+	return apierror.New(status, apierror.CodeForStatus(status), err.Error())
+}
+
+// respondBulk writes the outcome of a bulk operation, with 422 when it was
+// not applied
+func respondBulk(c *gin.Context, result *service.BulkResult) {
+	if !result.Applied {
+		c.JSON(http.StatusUnprocessableEntity, result)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -1,69 +1,206 @@
 package handlers
 
 import (
-	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
 	"net/http"
 	"strconv"
 	"time"
 
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/export"
 	"taxifleet/backend/internal/permissions"
 	"taxifleet/backend/internal/service"
+	"taxifleet/backend/internal/tracing"
 
 	"github.com/gin-gonic/gin"
-	"github.com/xuri/excelize/v2"
 )
 
 type ExpenseHandler struct {
-	service *service.ExpenseService
+	service  *service.ExpenseService
+	branding *service.BrandingService
 }
 
-func NewExpenseHandler(service *service.ExpenseService) *ExpenseHandler {
-	return &ExpenseHandler{service: service}
+func NewExpenseHandler(service *service.ExpenseService, branding *service.BrandingService) *ExpenseHandler {
+	return &ExpenseHandler{service: service, branding: branding}
 }
 
 func (h *ExpenseHandler) List(c *gin.Context) {
 	tenantID, _ := c.Get("tenantID")
-	expenses, err := h.service.List(tenantID.(uint))
+	if pageRequested(c) {
+		limit, ok := pageLimit(c)
+		if !ok {
+			return
+		}
+		page, err := h.service.Page(c.Request.Context(), tenantID.(uint), c.Query("cursor"), limit)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"expenses": expenseList(page.Expenses), "next_cursor": page.NextCursor})
+		return
+	}
+
+	expenses, err := h.service.List(c.Request.Context(), tenantID.(uint), listOptions(c))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, expenses)
+	c.JSON(http.StatusOK, expenseList(expenses))
+}
+
+// Summary returns the number and amount of the tenant's expenses per
+// ?group_by= category, taxi or month
+func (h *ExpenseHandler) Summary(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+
+	summary, err := h.service.Summary(c.Request.Context(), tenantID.(uint), c.DefaultQuery("group_by", "category"))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
 }
 
 func (h *ExpenseHandler) Create(c *gin.Context) {
 	tenantID, _ := c.Get("tenantID")
 	userID, _ := c.Get("userID")
+	permission, _ := c.Get("permission")
 
 	var req service.CreateExpenseRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Abort(c, apierror.Validation(err))
 		return
 	}
 
-	expense, err := h.service.Create(tenantID.(uint), userID.(uint), req)
+	expense, err := h.service.Create(c.Request.Context(), tenantID.(uint), userID.(uint), permission.(int), req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, expense)
 }
 
+// maxImportSize bounds the CSV file of an expense import
+const maxImportSize = 5 << 20
+
+// Import creates expenses from a CSV file sent in the 'file' form field. The
+// optional 'mapping' field is a JSON object naming the column of each field;
+// 'date_format' and 'dry_run' are form fields too.
+func (h *ExpenseHandler) Import(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+	permission, _ := c.Get("permission")
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize+multipartOverhead)
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			apierror.Abort(c, apierror.New(http.StatusRequestEntityTooLarge, "import_too_large", "The file exceeds 5 MB"))
+			return
+		}
+		apierror.Abort(c, apierror.BadRequest("A CSV file is required in the 'file' form field"))
+		return
+	}
+
+	req := service.ImportExpensesRequest{DateFormat: c.PostForm("date_format")}
+	if mapping := c.PostForm("mapping"); mapping != "" {
+		if err := json.Unmarshal([]byte(mapping), &req.Mapping); err != nil {
+			apierror.Abort(c, apierror.BadRequest("mapping must be a JSON object of field to column name"))
+			return
+		}
+	}
+	if dryRun := c.PostForm("dry_run"); dryRun != "" {
+		if req.DryRun, err = strconv.ParseBool(dryRun); err != nil {
+			apierror.Abort(c, apierror.BadRequest("dry_run must be true or false"))
+			return
+		}
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	defer file.Close()
+
+	report, err := h.service.Import(c.Request.Context(), tenantID.(uint), userID.(uint), permission.(int), file, req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	status := http.StatusCreated
+	if report.DryRun {
+		status = http.StatusOK
+	}
+	c.JSON(status, report)
+}
+
+// maxReceiptSize bounds the receipt photo sent to Scan
+const maxReceiptSize = 10 << 20
+
+// Scan reads a receipt photo sent in the 'file' form field and returns the
+// expense it describes, for the client to review and create
+func (h *ExpenseHandler) Scan(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxReceiptSize+multipartOverhead)
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			apierror.Abort(c, apierror.New(http.StatusRequestEntityTooLarge, "receipt_too_large", "The receipt photo exceeds 10 MB"))
+			return
+		}
+		apierror.Abort(c, apierror.BadRequest("A receipt photo is required in the 'file' form field"))
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	defer file.Close()
+
+	scan, err := h.service.ScanReceipt(c.Request.Context(), file)
+	if err != nil {
+		respondError(c, http.StatusBadGateway, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, scan)
+}
+
+// Pending lists the expenses awaiting an owner's approval
+func (h *ExpenseHandler) Pending(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	permission, _ := c.Get("permission")
+
+	expenses, err := h.service.Pending(c.Request.Context(), tenantID.(uint), permission.(int))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, expenses)
+}
+
 func (h *ExpenseHandler) Get(c *gin.Context) {
 	tenantID, _ := c.Get("tenantID")
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
 		return
 	}
 
-	expense, err := h.service.GetByID(uint(id), tenantID.(uint))
+	expense, err := h.service.GetByID(c.Request.Context(), uint(id), tenantID.(uint))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		respondError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -72,21 +209,42 @@ func (h *ExpenseHandler) Get(c *gin.Context) {
 
 func (h *ExpenseHandler) Update(c *gin.Context) {
 	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+	permission, _ := c.Get("permission")
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
 		return
 	}
 
 	var req service.UpdateExpenseRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	expense, err := h.service.Update(c.Request.Context(), uint(id), tenantID.(uint), userID.(uint), permission.(int), req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, expense)
+}
+
+func (h *ExpenseHandler) Approve(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+	permission, _ := c.Get("permission")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
 		return
 	}
 
-	expense, err := h.service.Update(uint(id), tenantID.(uint), req)
+	expense, err := h.service.Approve(c.Request.Context(), uint(id), tenantID.(uint), userID.(uint), permission.(int))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -95,34 +253,62 @@ func (h *ExpenseHandler) Update(c *gin.Context) {
 
 func (h *ExpenseHandler) Delete(c *gin.Context) {
 	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+	permission, _ := c.Get("permission")
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
 		return
 	}
 
-	if err := h.service.Delete(uint(id), tenantID.(uint)); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := h.service.Delete(c.Request.Context(), uint(id), tenantID.(uint), userID.(uint), permission.(int)); err != nil {
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Expense deleted successfully"})
 }
 
+// BulkDelete deletes several expenses in one transaction: all of them, or
+// none if any can't be deleted
+func (h *ExpenseHandler) BulkDelete(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+	permission, _ := c.Get("permission")
+
+	var req service.BulkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	result, err := h.service.BulkDelete(c.Request.Context(), req.IDs, tenantID.(uint), userID.(uint), permission.(int))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	respondBulk(c, result)
+}
+
 func (h *ExpenseHandler) Export(c *gin.Context) {
+	ctx, span := tracing.Start(c.Request.Context(), "ExpenseHandler.Export")
+	defer span.End()
+
 	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
 	permission, _ := c.Get("permission")
 
 	// Check if user has permission to export (owner or manager)
 	userPerm := permission.(int)
 	if !permissions.HasPermission(userPerm, permissions.PermissionViewExpenses) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to export expenses"})
+		apierror.Abort(c, apierror.Forbidden("You don't have permission to export expenses"))
 		return
 	}
 
-	expenses, err := h.service.List(tenantID.(uint))
+	expenses, err := h.service.ExportRows(ctx, tenantID.(uint))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -135,95 +321,29 @@ func (h *ExpenseHandler) Export(c *gin.Context) {
 	rand.Seed(time.Now().UnixNano())
 	randomID := rand.Intn(1000000)
 	dateStr := time.Now().Format("20060102")
-	filename := fmt.Sprintf("expenses-%d-%s.%s", randomID, dateStr, format)
+	basename := fmt.Sprintf("expenses-%d-%s", randomID, dateStr)
+	filename := basename + "." + format
 
-	// Helper function to format date as dd/month/year
-	formatDateDDMMYYYY := func(t time.Time) string {
-		return fmt.Sprintf("%02d/%02d/%d", t.Day(), t.Month(), t.Year())
+	section := service.ExpenseSection(expenses)
+	if err := h.branding.ApplyTemplate(ctx, tenantID.(uint), "expenses", &section); err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
 	}
 
 	if format == "csv" {
 		c.Header("Content-Type", "text/csv")
 		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
-
-		writer := csv.NewWriter(c.Writer)
-		defer writer.Flush()
-
-		// Write header
-		writer.Write([]string{"ID", "Date", "Category", "Amount", "Taxi", "Reason", "Created At"})
-
-		// Write data
-		for _, expense := range expenses {
-			taxiPlate := ""
-			if expense.Taxi != nil {
-				taxiPlate = expense.Taxi.LicensePlate
-			}
-			writer.Write([]string{
-				strconv.Itoa(int(expense.ID)),
-				formatDateDDMMYYYY(expense.Date),
-				expense.Category,
-				strconv.FormatFloat(expense.Amount, 'f', 2, 64),
-				taxiPlate,
-				expense.Reason,
-				formatDateDDMMYYYY(expense.CreatedAt),
-			})
+		if err := export.WriteCSV(c.Writer, section); err != nil {
+			respondError(c, http.StatusInternalServerError, err)
 		}
 	} else if format == "xlsx" {
-		f := excelize.NewFile()
-		defer func() {
-			if err := f.Close(); err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			}
-		}()
-
-		sheetName := "Expenses"
-		index, err := f.NewSheet(sheetName)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		f.SetActiveSheet(index)
-
-		// Write header
-		headers := []string{"ID", "Date", "Category", "Amount", "Taxi", "Reason", "Created At"}
-		for i, header := range headers {
-			cell, _ := excelize.CoordinatesToCellName(i+1, 1)
-			f.SetCellValue(sheetName, cell, header)
-		}
-
-		// Write data
-		for rowIdx, expense := range expenses {
-			row := rowIdx + 2
-			taxiPlate := ""
-			if expense.Taxi != nil {
-				taxiPlate = expense.Taxi.LicensePlate
-			}
-			cell1, _ := excelize.CoordinatesToCellName(1, row)
-			cell2, _ := excelize.CoordinatesToCellName(2, row)
-			cell3, _ := excelize.CoordinatesToCellName(3, row)
-			cell4, _ := excelize.CoordinatesToCellName(4, row)
-			cell5, _ := excelize.CoordinatesToCellName(5, row)
-			cell6, _ := excelize.CoordinatesToCellName(6, row)
-			cell7, _ := excelize.CoordinatesToCellName(7, row)
-			f.SetCellValue(sheetName, cell1, expense.ID)
-			f.SetCellValue(sheetName, cell2, formatDateDDMMYYYY(expense.Date))
-			f.SetCellValue(sheetName, cell3, expense.Category)
-			f.SetCellValue(sheetName, cell4, expense.Amount)
-			f.SetCellValue(sheetName, cell5, taxiPlate)
-			f.SetCellValue(sheetName, cell6, expense.Reason)
-			f.SetCellValue(sheetName, cell7, formatDateDDMMYYYY(expense.CreatedAt))
-		}
-
-		// Remove default sheet
-		f.DeleteSheet("Sheet1")
-
-		c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
-		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
-		if err := f.Write(c.Writer); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		doc := export.Document{Sheet: "Expenses", Sections: []export.Section{section}}
+		if err := h.branding.Brand(ctx, tenantID.(uint), userID.(uint), &doc); err != nil {
+			respondError(c, http.StatusInternalServerError, err)
 			return
 		}
+		writeDocument(c, doc, format, basename)
 	} else {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported format. Use 'csv' or 'xlsx'"})
+		apierror.Abort(c, apierror.BadRequest("Unsupported format. Use 'csv' or 'xlsx'"))
 	}
 }
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/permissions"
+	"taxifleet/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExportBundleHandler struct {
+	service *service.ExportBundleService
+}
+
+func NewExportBundleHandler(service *service.ExportBundleService) *ExportBundleHandler {
+	return &ExportBundleHandler{service: service}
+}
+
+// Create starts generating a bundle of the period's exports and files and
+// answers 202 with the URL to poll until it is ready
+func (h *ExportBundleHandler) Create(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+	permission, _ := c.Get("permission")
+
+	// A bundle holds reports, expenses and deposits alike
+	userPerm := permission.(int)
+	if !permissions.HasPermission(userPerm, permissions.PermissionViewReports) ||
+		!permissions.HasPermission(userPerm, permissions.PermissionViewExpenses) ||
+		!permissions.HasPermission(userPerm, permissions.PermissionViewDeposits) {
+		apierror.Abort(c, apierror.Forbidden("You don't have permission to export reports, expenses and deposits"))
+		return
+	}
+
+	bundle, err := h.service.Request(c.Request.Context(), tenantID.(uint), userID.(uint), c.Query("from"), c.Query("to"))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.Header("Location", bundle.StatusURL)
+	c.JSON(http.StatusAccepted, bundle)
+}
+
+func (h *ExportBundleHandler) Get(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	bundle, err := h.service.Get(c.Request.Context(), uint(id), tenantID.(uint), userID.(uint))
+	if err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, bundle)
+}
+
+// Download streams a ready bundle, or redirects to a presigned URL of it
+func (h *ExportBundleHandler) Download(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	download, err := h.service.Open(c.Request.Context(), uint(id), tenantID.(uint), userID.(uint))
+	if err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+	if download.URL != "" {
+		c.Redirect(http.StatusFound, download.URL)
+		return
+	}
+	defer download.File.Close()
+
+	c.DataFromReader(http.StatusOK, -1, "application/zip", download.File, map[string]string{
+		"Content-Disposition": fmt.Sprintf("attachment; filename=%s", download.FileName),
+	})
+}
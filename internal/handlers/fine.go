@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FineHandler struct {
+	service *service.FineService
+}
+
+func NewFineHandler(service *service.FineService) *FineHandler {
+	return &FineHandler{service: service}
+}
+
+// List returns the tenant's fines; status=paid or status=unpaid filters them
+func (h *FineHandler) List(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+
+	fines, err := h.service.List(c.Request.Context(), tenantID.(uint), c.Query("status"))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, fines)
+}
+
+func (h *FineHandler) Create(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+
+	var req service.CreateFineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	fine, err := h.service.Create(c.Request.Context(), tenantID.(uint), userID.(uint), req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, fine)
+}
+
+func (h *FineHandler) Get(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	fine, err := h.service.GetByID(c.Request.Context(), uint(id), tenantID.(uint))
+	if err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, fine)
+}
+
+func (h *FineHandler) Update(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	var req service.UpdateFineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	fine, err := h.service.Update(c.Request.Context(), uint(id), tenantID.(uint), userID.(uint), req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, fine)
+}
+
+// MarkPaid records that the fine was paid to the authority
+func (h *FineHandler) MarkPaid(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	fine, err := h.service.MarkPaid(c.Request.Context(), uint(id), tenantID.(uint))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, fine)
+}
+
+func (h *FineHandler) Delete(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), uint(id), tenantID.(uint)); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Fine deleted successfully"})
+}
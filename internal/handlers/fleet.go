@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"taxifleet/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fleetStatusInterval is how often the status stream checks for changes
+const fleetStatusInterval = 10 * time.Second
+
+type FleetHandler struct {
+	service *service.FleetService
+}
+
+func NewFleetHandler(service *service.FleetService) *FleetHandler {
+	return &FleetHandler{service: service}
+}
+
+func (h *FleetHandler) Status(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+
+	status, err := h.service.Status(c.Request.Context(), tenantID.(uint))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// StatusStream sends the fleet status as server-sent events: a status event
+// right away and another whenever the board changes, with keep-alive comments
+// in between. The stream lasts until the client disconnects.
+func (h *FleetHandler) StatusStream(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	ctx := c.Request.Context()
+
+	// The server's write timeout would end the stream
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+
+	ticker := time.NewTicker(fleetStatusInterval)
+	defer ticker.Stop()
+
+	var last []byte
+	for {
+		status, err := h.service.ScopedStatus(ctx, tenantID.(uint))
+		if err != nil {
+			if ctx.Err() == nil {
+				c.SSEvent("error", gin.H{"message": "Failed to load the fleet status"})
+				c.Writer.Flush()
+			}
+			return
+		}
+
+		data, _ := json.Marshal(status)
+		if !bytes.Equal(data, last) {
+			c.SSEvent("status", status)
+			last = data
+		} else {
+			c.Writer.WriteString(": keep-alive\n\n")
+		}
+		c.Writer.Flush()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
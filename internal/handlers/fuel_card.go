@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FuelCardHandler struct {
+	service *service.FuelCardService
+}
+
+func NewFuelCardHandler(service *service.FuelCardService) *FuelCardHandler {
+	return &FuelCardHandler{service: service}
+}
+
+func (h *FuelCardHandler) List(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+
+	cards, err := h.service.List(c.Request.Context(), tenantID.(uint))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, cards)
+}
+
+func (h *FuelCardHandler) Create(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+
+	var req service.CreateFuelCardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	card, err := h.service.Create(c.Request.Context(), tenantID.(uint), userID.(uint), req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, card)
+}
+
+func (h *FuelCardHandler) Update(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	var req service.UpdateFuelCardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	card, err := h.service.Update(c.Request.Context(), uint(id), tenantID.(uint), req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, card)
+}
+
+func (h *FuelCardHandler) Delete(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), uint(id), tenantID.(uint)); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Fuel card deleted successfully"})
+}
+
+// Transactions returns the latest synced transactions; flagged=true keeps
+// those needing attention
+func (h *FuelCardHandler) Transactions(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		apierror.Abort(c, apierror.BadRequest("limit must be between 1 and 200"))
+		return
+	}
+
+	transactions, err := h.service.Transactions(c.Request.Context(), tenantID.(uint), c.Query("flagged") == "true", limit)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transactions)
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GeofenceHandler struct {
+	service *service.GeofenceService
+}
+
+func NewGeofenceHandler(service *service.GeofenceService) *GeofenceHandler {
+	return &GeofenceHandler{service: service}
+}
+
+func (h *GeofenceHandler) List(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+
+	geofences, err := h.service.List(c.Request.Context(), tenantID.(uint))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, geofences)
+}
+
+func (h *GeofenceHandler) Create(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+
+	var req service.CreateGeofenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	geofence, err := h.service.Create(c.Request.Context(), tenantID.(uint), req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, geofence)
+}
+
+func (h *GeofenceHandler) Update(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	var req service.UpdateGeofenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	geofence, err := h.service.Update(c.Request.Context(), uint(id), tenantID.(uint), req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, geofence)
+}
+
+func (h *GeofenceHandler) Delete(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), uint(id), tenantID.(uint)); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Geofence deleted successfully"})
+}
+
+// ReportPositions stores a batch of positions of the taxi
+func (h *GeofenceHandler) ReportPositions(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	var req service.ReportPositionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	count, err := h.service.ReportPositions(c.Request.Context(), tenantID.(uint), uint(id), req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"created": count})
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InventoryHandler struct {
+	service *service.InventoryService
+}
+
+func NewInventoryHandler(service *service.InventoryService) *InventoryHandler {
+	return &InventoryHandler{service: service}
+}
+
+func (h *InventoryHandler) ListParts(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+
+	parts, err := h.service.ListParts(c.Request.Context(), tenantID.(uint))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, parts)
+}
+
+func (h *InventoryHandler) ListLowStock(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+
+	parts, err := h.service.ListLowStock(c.Request.Context(), tenantID.(uint))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, parts)
+}
+
+func (h *InventoryHandler) CreatePart(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+
+	var req service.CreatePartRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	part, err := h.service.CreatePart(c.Request.Context(), tenantID.(uint), userID.(uint), req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, part)
+}
+
+func (h *InventoryHandler) GetPart(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	part, err := h.service.GetPart(c.Request.Context(), uint(id), tenantID.(uint))
+	if err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, part)
+}
+
+func (h *InventoryHandler) UpdatePart(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	var req service.UpdatePartRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	part, err := h.service.UpdatePart(c.Request.Context(), uint(id), tenantID.(uint), req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, part)
+}
+
+func (h *InventoryHandler) DeletePart(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	if err := h.service.DeletePart(c.Request.Context(), uint(id), tenantID.(uint)); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Part deleted successfully"})
+}
+
+func (h *InventoryHandler) ListMovements(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	movements, err := h.service.ListMovements(c.Request.Context(), uint(id), tenantID.(uint))
+	if err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, movements)
+}
+
+func (h *InventoryHandler) RecordMovement(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	var req service.StockMovementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	part, err := h.service.RecordMovement(c.Request.Context(), uint(id), tenantID.(uint), userID.(uint), req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, part)
+}
+
+func (h *InventoryHandler) ConsumeParts(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+
+	var req service.ConsumePartsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	movements, err := h.service.ConsumeParts(c.Request.Context(), tenantID.(uint), userID.(uint), req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, movements)
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/scheduler"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JobHandler struct {
+	scheduler *scheduler.Scheduler
+}
+
+func NewJobHandler(scheduler *scheduler.Scheduler) *JobHandler {
+	return &JobHandler{scheduler: scheduler}
+}
+
+func (h *JobHandler) List(c *gin.Context) {
+	c.JSON(http.StatusOK, h.scheduler.Jobs())
+}
+
+func (h *JobHandler) Runs(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 500 {
+		apierror.Abort(c, apierror.BadRequest("limit must be between 1 and 500"))
+		return
+	}
+
+	runs, err := h.scheduler.Runs(c.Request.Context(), c.Query("job"), limit)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, runs)
+}
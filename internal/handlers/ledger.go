@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LedgerHandler struct {
+	service *service.LedgerService
+}
+
+func NewLedgerHandler(service *service.LedgerService) *LedgerHandler {
+	return &LedgerHandler{service: service}
+}
+
+func (h *LedgerHandler) ListBalances(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+
+	balances, err := h.service.ListBalances(c.Request.Context(), tenantID.(uint))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, balances)
+}
+
+func (h *LedgerHandler) GetDriverLedger(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+	permission, _ := c.Get("permission")
+	driverID, err := strconv.ParseUint(c.Param("driverId"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid driver ID"))
+		return
+	}
+
+	ledger, err := h.service.GetDriverLedger(c.Request.Context(), tenantID.(uint), userID.(uint), permission.(int), uint(driverID))
+	if err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ledger)
+}
+
+func (h *LedgerHandler) RecordEntry(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+
+	var req service.RecordLedgerEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	entry, err := h.service.RecordEntry(c.Request.Context(), tenantID.(uint), userID.(uint), req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
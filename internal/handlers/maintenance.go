@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MaintenanceHandler struct {
+	service *service.MaintenanceService
+}
+
+func NewMaintenanceHandler(service *service.MaintenanceService) *MaintenanceHandler {
+	return &MaintenanceHandler{service: service}
+}
+
+func (h *MaintenanceHandler) ListSchedules(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+
+	schedules, err := h.service.ListSchedules(c.Request.Context(), tenantID.(uint))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schedules)
+}
+
+func (h *MaintenanceHandler) CreateSchedule(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+
+	var req service.CreateScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	schedule, err := h.service.CreateSchedule(c.Request.Context(), tenantID.(uint), req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, schedule)
+}
+
+func (h *MaintenanceHandler) GetSchedule(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	schedule, err := h.service.GetSchedule(c.Request.Context(), uint(id), tenantID.(uint))
+	if err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+func (h *MaintenanceHandler) UpdateSchedule(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	var req service.UpdateScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	schedule, err := h.service.UpdateSchedule(c.Request.Context(), uint(id), tenantID.(uint), req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+func (h *MaintenanceHandler) DeleteSchedule(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	if err := h.service.DeleteSchedule(c.Request.Context(), uint(id), tenantID.(uint)); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Schedule deleted successfully"})
+}
+
+func (h *MaintenanceHandler) CompleteSchedule(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	var req service.CompleteScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	log, err := h.service.CompleteSchedule(c.Request.Context(), uint(id), tenantID.(uint), userID.(uint), req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, log)
+}
+
+func (h *MaintenanceHandler) ListLogs(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+
+	logs, err := h.service.ListLogs(c.Request.Context(), tenantID.(uint))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, logs)
+}
+
+func (h *MaintenanceHandler) CreateLog(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+
+	var req service.CreateMaintenanceLogRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	log, err := h.service.CreateLog(c.Request.Context(), tenantID.(uint), userID.(uint), req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, log)
+}
+
+func (h *MaintenanceHandler) GetDue(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+
+	due, err := h.service.GetDue(c.Request.Context(), tenantID.(uint))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, due)
+}
+
+func (h *MaintenanceHandler) GetMechanicDashboard(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+
+	dashboard, err := h.service.GetMechanicDashboard(c.Request.Context(), tenantID.(uint))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dashboard)
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/permissions"
+	"taxifleet/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	service *service.NotificationService
+}
+
+func NewNotificationHandler(service *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{service: service}
+}
+
+func (h *NotificationHandler) RegisterDevice(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+
+	var req service.RegisterDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	device, err := h.service.RegisterDevice(c.Request.Context(), tenantID.(uint), userID.(uint), req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, device)
+}
+
+func (h *NotificationHandler) UnregisterDevice(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	if err := h.service.UnregisterDevice(c.Request.Context(), userID.(uint), c.Param("token")); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Device unregistered successfully"})
+}
+
+func (h *NotificationHandler) SendShiftReminders(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	permission, _ := c.Get("permission")
+
+	// Only users managing reports (owner, manager) can remind drivers
+	if !permissions.HasPermission(permission.(int), permissions.PermissionEditReports) {
+		apierror.Abort(c, apierror.Forbidden("You don't have permission to send shift reminders"))
+		return
+	}
+
+	var req service.ShiftReminderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	count, err := h.service.SendShiftReminders(c.Request.Context(), tenantID.(uint), req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reminded": count})
+}
+
+// List returns the current user's notifications, newest first; unread=true
+// leaves out those already read
+func (h *NotificationHandler) List(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		apierror.Abort(c, apierror.BadRequest("limit must be between 1 and 200"))
+		return
+	}
+
+	notifications, err := h.service.ListNotifications(c.Request.Context(), userID.(uint), c.Query("unread") == "true", limit)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, notifications)
+}
+
+func (h *NotificationHandler) UnreadCount(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	count, err := h.service.UnreadCount(c.Request.Context(), userID.(uint))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"unread": count})
+}
+
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	if err := h.service.MarkRead(c.Request.Context(), userID.(uint), uint(id)); err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification marked as read"})
+}
+
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	count, err := h.service.MarkAllRead(c.Request.Context(), userID.(uint))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"marked": count})
+}
+
+// Announce sends an admin announcement to the inbox of every user of a
+// tenant, or of all tenants
+func (h *NotificationHandler) Announce(c *gin.Context) {
+	var req service.AnnouncementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	count, err := h.service.Announce(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"notified": count})
+}
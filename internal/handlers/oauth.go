@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// oauthStateCookie holds the state sent to the provider, so the callback can
+// check it comes from the browser that started the sign-in
+const oauthStateCookie = "oauth_state"
+
+type OAuthHandler struct {
+	service     *service.OAuthService
+	frontendURL string
+}
+
+func NewOAuthHandler(service *service.OAuthService, frontendURL string) *OAuthHandler {
+	return &OAuthHandler{service: service, frontendURL: frontendURL}
+}
+
+// GoogleLogin redirects the browser to Google's consent page
+func (h *OAuthHandler) GoogleLogin(c *gin.Context) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		apierror.Abort(c, apierror.Internal(err))
+		return
+	}
+	state := hex.EncodeToString(b)
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthStateCookie, state, 600, "/api/v1/auth/oauth", "", isHTTPS(c), true)
+	c.Redirect(http.StatusFound, h.service.GoogleAuthURL(state))
+}
+
+// GoogleCallback completes the sign-in. With a frontend URL configured the
+// browser is sent there with the tokens in the URL fragment; otherwise the
+// tokens are returned as JSON.
+func (h *OAuthHandler) GoogleCallback(c *gin.Context) {
+	state, err := c.Cookie(oauthStateCookie)
+	c.SetCookie(oauthStateCookie, "", -1, "/api/v1/auth/oauth", "", isHTTPS(c), true)
+	if err != nil || state == "" || c.Query("state") != state {
+		h.fail(c, apierror.BadRequest("Invalid OAuth state"))
+		return
+	}
+	if c.Query("error") != "" {
+		h.fail(c, apierror.Unauthorized("Sign-in was cancelled"))
+		return
+	}
+
+	response, err := h.service.GoogleLogin(c.Request.Context(), c.Query("code"), loginClient(c))
+	if err != nil {
+		h.fail(c, toAPIError(err, http.StatusUnauthorized))
+		return
+	}
+
+	if h.frontendURL == "" {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+	fragment := url.Values{"token": {response.Token}, "refresh_token": {response.RefreshToken}}
+	c.Redirect(http.StatusFound, h.frontendURL+"#"+fragment.Encode())
+}
+
+func (h *OAuthHandler) fail(c *gin.Context, apiErr *apierror.Error) {
+	if h.frontendURL == "" {
+		apierror.Abort(c, apiErr)
+		return
+	}
+	fragment := url.Values{"error": {apiErr.Code}, "error_description": {apiErr.Message}}
+	c.Redirect(http.StatusFound, h.frontendURL+"#"+fragment.Encode())
+	c.Abort()
+}
+
+func isHTTPS(c *gin.Context) bool {
+	return c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+}
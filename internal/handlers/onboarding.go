@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OnboardingHandler struct {
+	service *service.OnboardingService
+}
+
+func NewOnboardingHandler(service *service.OnboardingService) *OnboardingHandler {
+	return &OnboardingHandler{service: service}
+}
+
+func (h *OnboardingHandler) Onboard(c *gin.Context) {
+	var req service.OnboardingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	onboarding, err := h.service.Onboard(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, onboarding)
+}
+
+func (h *OnboardingHandler) Verify(c *gin.Context) {
+	var req struct {
+		Token string `json:"token" binding:"required" sanitize:"-"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	user, err := h.service.Verify(c.Request.Context(), req.Token)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *OnboardingHandler) Resend(c *gin.Context) {
+	var req struct {
+		Email string `json:"email" binding:"required,email"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	if err := h.service.ResendVerification(c.Request.Context(), req.Email); err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "If the address is waiting for verification, a new link was sent"})
+}
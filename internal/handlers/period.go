@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PeriodHandler struct {
+	service *service.PeriodService
+}
+
+func NewPeriodHandler(service *service.PeriodService) *PeriodHandler {
+	return &PeriodHandler{service: service}
+}
+
+// List returns the tenant's closed months, latest first
+func (h *PeriodHandler) List(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+
+	periods, err := h.service.List(c.Request.Context(), tenantID.(uint))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, periods)
+}
+
+func (h *PeriodHandler) Close(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+	permission, _ := c.Get("permission")
+
+	var req service.ClosePeriodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	period, err := h.service.Close(c.Request.Context(), tenantID.(uint), userID.(uint), permission.(int), req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, period)
+}
+
+// Reopen reopens a closed month of a tenant (admin only)
+func (h *PeriodHandler) Reopen(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	tenantID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	if err := h.service.Reopen(c.Request.Context(), uint(tenantID), userID.(uint), c.Param("month")); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Period reopened successfully"})
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PlatformEarningHandler struct {
+	service *service.PlatformEarningService
+}
+
+func NewPlatformEarningHandler(service *service.PlatformEarningService) *PlatformEarningHandler {
+	return &PlatformEarningHandler{service: service}
+}
+
+// Import reads a platform's earnings statement sent in the 'file' form field.
+// 'platform' is required; 'mapping', 'date_format' and 'dry_run' work as for
+// expense imports.
+func (h *PlatformEarningHandler) Import(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize+multipartOverhead)
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			apierror.Abort(c, apierror.New(http.StatusRequestEntityTooLarge, "import_too_large", "The file exceeds 5 MB"))
+			return
+		}
+		apierror.Abort(c, apierror.BadRequest("A CSV file is required in the 'file' form field"))
+		return
+	}
+
+	req := service.ImportPlatformEarningsRequest{Platform: c.PostForm("platform"), DateFormat: c.PostForm("date_format")}
+	if mapping := c.PostForm("mapping"); mapping != "" {
+		if err := json.Unmarshal([]byte(mapping), &req.Mapping); err != nil {
+			apierror.Abort(c, apierror.BadRequest("mapping must be a JSON object of field to column name"))
+			return
+		}
+	}
+	if dryRun := c.PostForm("dry_run"); dryRun != "" {
+		if req.DryRun, err = strconv.ParseBool(dryRun); err != nil {
+			apierror.Abort(c, apierror.BadRequest("dry_run must be true or false"))
+			return
+		}
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	defer file.Close()
+
+	report, err := h.service.Import(c.Request.Context(), tenantID.(uint), userID.(uint), file, req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	status := http.StatusCreated
+	if report.DryRun {
+		status = http.StatusOK
+	}
+	c.JSON(status, report)
+}
+
+// Push stores earnings an integration fetched from a platform's API
+func (h *PlatformEarningHandler) Push(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+
+	var req service.PushPlatformEarningsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	count, err := h.service.Push(c.Request.Context(), tenantID.(uint), userID.(uint), req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"saved": count})
+}
+
+// Reconciliation compares platform earnings with reported earnings per
+// driver and week between the from and to dates
+func (h *PlatformEarningHandler) Reconciliation(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+
+	reconciliation, err := h.service.Reconcile(c.Request.Context(), tenantID.(uint), c.Query("from"), c.Query("to"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reconciliation)
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PolicyHandler struct {
+	service *service.PolicyService
+}
+
+func NewPolicyHandler(service *service.PolicyService) *PolicyHandler {
+	return &PolicyHandler{service: service}
+}
+
+func (h *PolicyHandler) List(c *gin.Context) {
+	tenantID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	policies, err := h.service.List(c.Request.Context(), uint(tenantID))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policies)
+}
+
+func (h *PolicyHandler) Create(c *gin.Context) {
+	tenantID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	var req service.PolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	policy, err := h.service.Create(c.Request.Context(), uint(tenantID), req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, policy)
+}
+
+// Update replaces the policy
+func (h *PolicyHandler) Update(c *gin.Context) {
+	tenantID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+	policyID, err := strconv.ParseUint(c.Param("policyId"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid policy ID"))
+		return
+	}
+
+	var req service.PolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	policy, err := h.service.Update(c.Request.Context(), uint(tenantID), uint(policyID), req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+func (h *PolicyHandler) Delete(c *gin.Context) {
+	tenantID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+	policyID, err := strconv.ParseUint(c.Param("policyId"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid policy ID"))
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), uint(tenantID), uint(policyID)); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Policy deleted successfully"})
+}
@@ -1,26 +1,28 @@
 package handlers
 
 import (
-	"encoding/csv"
 	"fmt"
 	"math/rand"
 	"net/http"
 	"strconv"
 	"time"
 
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/export"
 	"taxifleet/backend/internal/permissions"
 	"taxifleet/backend/internal/service"
+	"taxifleet/backend/internal/tracing"
 
 	"github.com/gin-gonic/gin"
-	"github.com/xuri/excelize/v2"
 )
 
 type ReportHandler struct {
-	service *service.ReportService
+	service  *service.ReportService
+	branding *service.BrandingService
 }
 
-func NewReportHandler(service *service.ReportService) *ReportHandler {
-	return &ReportHandler{service: service}
+func NewReportHandler(service *service.ReportService, branding *service.BrandingService) *ReportHandler {
+	return &ReportHandler{service: service, branding: branding}
 }
 
 func (h *ReportHandler) List(c *gin.Context) {
@@ -28,13 +30,125 @@ func (h *ReportHandler) List(c *gin.Context) {
 	userID, _ := c.Get("userID")
 	permission, _ := c.Get("permission")
 
-	reports, err := h.service.List(tenantID.(uint), userID.(uint), permission.(int))
+	version, err := h.service.ListVersion(c.Request.Context(), tenantID.(uint))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	variant := "all"
+	if permission.(int) == permissions.PermissionDriver {
+		variant = fmt.Sprintf("driver-%d", userID.(uint))
+	}
+	archived := c.Query("archived") == "true"
+	paged := pageRequested(c)
+	switch {
+	case archived && paged:
+		apierror.Abort(c, apierror.BadRequest("cursor and limit can't be combined with archived"))
+		return
+	case archived:
+		variant += "-archived"
+	case paged:
+		variant += "-" + pageVariant(c)
+	}
+	if notModified(c, version, variant) {
+		return
+	}
+
+	if archived {
+		reports, err := h.service.ListArchived(c.Request.Context(), tenantID.(uint), userID.(uint), permission.(int), listOptions(c))
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, err)
+			return
+		}
+		c.JSON(http.StatusOK, reportList(reports))
+		return
+	}
+
+	if paged {
+		limit, ok := pageLimit(c)
+		if !ok {
+			return
+		}
+		page, err := h.service.Page(c.Request.Context(), tenantID.(uint), userID.(uint), permission.(int), c.Query("cursor"), limit)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"reports": reportList(page.Reports), "next_cursor": page.NextCursor})
+		return
+	}
+
+	reports, err := h.service.List(c.Request.Context(), tenantID.(uint), userID.(uint), permission.(int), listOptions(c))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reportList(reports))
+}
+
+// Count returns the number of reports the user can list, of the status
+// given by ?status= if any
+func (h *ReportHandler) Count(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+	permission, _ := c.Get("permission")
+
+	count, err := h.service.Count(c.Request.Context(), tenantID.(uint), userID.(uint), permission.(int), c.Query("status"))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"count": count})
+}
+
+// Summary returns the number and earnings of the reports the user can list
+// per ?group_by= status, taxi, driver or month
+func (h *ReportHandler) Summary(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+	permission, _ := c.Get("permission")
+
+	summary, err := h.service.Summary(c.Request.Context(), tenantID.(uint), userID.(uint), permission.(int), c.DefaultQuery("group_by", "status"))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// Archive archives the tenant's reviewed reports older than the given number
+// of months
+func (h *ReportHandler) Archive(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+
+	var req service.ArchiveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
 		return
 	}
 
-	c.JSON(http.StatusOK, reports)
+	archived, err := h.service.Archive(c.Request.Context(), tenantID.(uint), req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"archived": archived})
+}
+
+func (h *ReportHandler) Weeks(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+
+	weeks, err := h.service.GetWeekBoundaries(c.Request.Context(), tenantID.(uint))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, weeks)
 }
 
 func (h *ReportHandler) Create(c *gin.Context) {
@@ -43,13 +157,13 @@ func (h *ReportHandler) Create(c *gin.Context) {
 
 	var req service.CreateReportRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Abort(c, apierror.Validation(err))
 		return
 	}
 
-	report, err := h.service.Create(tenantID.(uint), userID.(uint), req)
+	report, err := h.service.Create(c.Request.Context(), tenantID.(uint), userID.(uint), req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -60,16 +174,17 @@ func (h *ReportHandler) Get(c *gin.Context) {
 	tenantID, _ := c.Get("tenantID")
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
 		return
 	}
 
-	report, err := h.service.GetByID(uint(id), tenantID.(uint))
+	report, err := h.service.GetByID(c.Request.Context(), uint(id), tenantID.(uint))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		respondError(c, http.StatusNotFound, err)
 		return
 	}
 
+	setETag(c, report.Version)
 	c.JSON(http.StatusOK, report)
 }
 
@@ -79,22 +194,29 @@ func (h *ReportHandler) Update(c *gin.Context) {
 	permission, _ := c.Get("permission")
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
 		return
 	}
 
 	var req service.UpdateReportRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Abort(c, apierror.Validation(err))
 		return
 	}
+	if req.Version == 0 {
+		if req.Version, err = ifMatchVersion(c); err != nil {
+			respondError(c, http.StatusBadRequest, err)
+			return
+		}
+	}
 
-	report, err := h.service.Update(uint(id), tenantID.(uint), userID.(uint), permission.(int), req)
+	report, err := h.service.Update(c.Request.Context(), uint(id), tenantID.(uint), userID.(uint), permission.(int), req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
+	setETag(c, report.Version)
 	c.JSON(http.StatusOK, report)
 }
 
@@ -103,13 +225,13 @@ func (h *ReportHandler) Submit(c *gin.Context) {
 	userID, _ := c.Get("userID")
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
 		return
 	}
 
-	report, err := h.service.Submit(uint(id), tenantID.(uint), userID.(uint))
+	report, err := h.service.Submit(c.Request.Context(), uint(id), tenantID.(uint), userID.(uint))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -122,13 +244,22 @@ func (h *ReportHandler) Approve(c *gin.Context) {
 	permission, _ := c.Get("permission")
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
 		return
 	}
 
-	report, err := h.service.Approve(uint(id), tenantID.(uint), approvedByID.(uint), permission.(int))
+	// The sign-off is optional, so approvals may come without a body
+	var req service.ApproveReportRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apierror.Abort(c, apierror.Validation(err))
+			return
+		}
+	}
+
+	report, err := h.service.Approve(c.Request.Context(), uint(id), tenantID.(uint), approvedByID.(uint), permission.(int), req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -141,13 +272,13 @@ func (h *ReportHandler) Delete(c *gin.Context) {
 	permission, _ := c.Get("permission")
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
 		return
 	}
 
-	err = h.service.Delete(uint(id), tenantID.(uint), userID.(uint), permission.(int))
+	err = h.service.Delete(c.Request.Context(), uint(id), tenantID.(uint), userID.(uint), permission.(int))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -156,15 +287,45 @@ func (h *ReportHandler) Delete(c *gin.Context) {
 
 func (h *ReportHandler) Reject(c *gin.Context) {
 	tenantID, _ := c.Get("tenantID")
+	rejectedByID, _ := c.Get("userID")
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
 		return
 	}
 
-	report, err := h.service.Reject(uint(id), tenantID.(uint))
+	// The reason is optional, so rejections may come without a body
+	var req service.RejectReportRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apierror.Abort(c, apierror.Validation(err))
+			return
+		}
+	}
+
+	report, err := h.service.Reject(c.Request.Context(), uint(id), tenantID.(uint), rejectedByID.(uint), req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// Reopen puts a rejected report back to draft for resubmission
+func (h *ReportHandler) Reopen(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+	permission, _ := c.Get("permission")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	report, err := h.service.Reopen(c.Request.Context(), uint(id), tenantID.(uint), userID.(uint), permission.(int))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -172,6 +333,9 @@ func (h *ReportHandler) Reject(c *gin.Context) {
 }
 
 func (h *ReportHandler) Export(c *gin.Context) {
+	ctx, span := tracing.Start(c.Request.Context(), "ReportHandler.Export")
+	defer span.End()
+
 	tenantID, _ := c.Get("tenantID")
 	userID, _ := c.Get("userID")
 	permission, _ := c.Get("permission")
@@ -179,13 +343,13 @@ func (h *ReportHandler) Export(c *gin.Context) {
 	// Check if user has permission to export (owner or manager)
 	userPerm := permission.(int)
 	if !permissions.HasPermission(userPerm, permissions.PermissionViewReports) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to export reports"})
+		apierror.Abort(c, apierror.Forbidden("You don't have permission to export reports"))
 		return
 	}
 
-	reports, err := h.service.List(tenantID.(uint), userID.(uint), userPerm)
+	reports, err := h.service.ExportRows(ctx, tenantID.(uint), userID.(uint), userPerm)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -198,93 +362,29 @@ func (h *ReportHandler) Export(c *gin.Context) {
 	rand.Seed(time.Now().UnixNano())
 	randomID := rand.Intn(1000000)
 	dateStr := time.Now().Format("20060102")
-	filename := fmt.Sprintf("reports-%d-%s.%s", randomID, dateStr, format)
+	basename := fmt.Sprintf("reports-%d-%s", randomID, dateStr)
+	filename := basename + "." + format
 
-	// Helper function to format date as dd/month/year
-	formatDateDDMMYYYY := func(t time.Time) string {
-		return fmt.Sprintf("%02d/%02d/%d", t.Day(), t.Month(), t.Year())
+	section := service.ReportSection(reports)
+	if err := h.branding.ApplyTemplate(ctx, tenantID.(uint), "reports", &section); err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
 	}
 
 	if format == "csv" {
 		c.Header("Content-Type", "text/csv")
 		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
-
-		writer := csv.NewWriter(c.Writer)
-		defer writer.Flush()
-
-		// Write header
-		writer.Write([]string{"ID", "Week Start", "Taxi", "Driver", "Earnings", "Expenses", "Status", "Notes", "Created At"})
-
-		// Write data
-		for _, report := range reports {
-			writer.Write([]string{
-				strconv.Itoa(int(report.ID)),
-				formatDateDDMMYYYY(report.WeekStartDate),
-				report.Taxi.LicensePlate,
-				report.Driver.FirstName + " " + report.Driver.LastName,
-				strconv.FormatFloat(report.Earnings, 'f', 2, 64),
-				strconv.FormatFloat(report.TotalExpenses, 'f', 2, 64),
-				report.Status,
-				report.Notes,
-				formatDateDDMMYYYY(report.CreatedAt),
-			})
+		if err := export.WriteCSV(c.Writer, section); err != nil {
+			respondError(c, http.StatusInternalServerError, err)
 		}
 	} else if format == "xlsx" {
-		f := excelize.NewFile()
-		defer func() {
-			if err := f.Close(); err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			}
-		}()
-
-		sheetName := "Reports"
-		index, err := f.NewSheet(sheetName)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		f.SetActiveSheet(index)
-
-		// Write header
-		headers := []string{"ID", "Week Start", "Taxi", "Driver", "Earnings", "Expenses", "Status", "Notes", "Created At"}
-		for i, header := range headers {
-			cell, _ := excelize.CoordinatesToCellName(i+1, 1)
-			f.SetCellValue(sheetName, cell, header)
-		}
-
-		// Write data
-		for rowIdx, report := range reports {
-			row := rowIdx + 2
-			cell1, _ := excelize.CoordinatesToCellName(1, row)
-			cell2, _ := excelize.CoordinatesToCellName(2, row)
-			cell3, _ := excelize.CoordinatesToCellName(3, row)
-			cell4, _ := excelize.CoordinatesToCellName(4, row)
-			cell5, _ := excelize.CoordinatesToCellName(5, row)
-			cell6, _ := excelize.CoordinatesToCellName(6, row)
-			cell7, _ := excelize.CoordinatesToCellName(7, row)
-			cell8, _ := excelize.CoordinatesToCellName(8, row)
-			cell9, _ := excelize.CoordinatesToCellName(9, row)
-			f.SetCellValue(sheetName, cell1, report.ID)
-			f.SetCellValue(sheetName, cell2, formatDateDDMMYYYY(report.WeekStartDate))
-			f.SetCellValue(sheetName, cell3, report.Taxi.LicensePlate)
-			f.SetCellValue(sheetName, cell4, report.Driver.FirstName+" "+report.Driver.LastName)
-			f.SetCellValue(sheetName, cell5, report.Earnings)
-			f.SetCellValue(sheetName, cell6, report.TotalExpenses)
-			f.SetCellValue(sheetName, cell7, report.Status)
-			f.SetCellValue(sheetName, cell8, report.Notes)
-			f.SetCellValue(sheetName, cell9, formatDateDDMMYYYY(report.CreatedAt))
-		}
-
-		// Remove default sheet
-		f.DeleteSheet("Sheet1")
-
-		c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
-		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
-		if err := f.Write(c.Writer); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		doc := export.Document{Sheet: "Reports", Sections: []export.Section{section}}
+		if err := h.branding.Brand(ctx, tenantID.(uint), userID.(uint), &doc); err != nil {
+			respondError(c, http.StatusInternalServerError, err)
 			return
 		}
+		writeDocument(c, doc, format, basename)
 	} else {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported format. Use 'csv' or 'xlsx'"})
+		apierror.Abort(c, apierror.BadRequest("Unsupported format. Use 'csv' or 'xlsx'"))
 	}
 }
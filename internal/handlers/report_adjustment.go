@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *ReportHandler) ListAdjustments(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	adjustments, err := h.service.ListAdjustments(c.Request.Context(), uint(id), tenantID.(uint))
+	if err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, adjustments)
+}
+
+func (h *ReportHandler) AddAdjustment(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+	permission, _ := c.Get("permission")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	var req service.AddAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	report, err := h.service.AddAdjustment(c.Request.Context(), uint(id), tenantID.(uint), userID.(uint), permission.(int), req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	setETag(c, report.Version)
+	c.JSON(http.StatusCreated, report)
+}
+
+func (h *ReportHandler) DeleteAdjustment(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	permission, _ := c.Get("permission")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+	adjustmentID, err := strconv.ParseUint(c.Param("adjustmentId"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid adjustment ID"))
+		return
+	}
+
+	report, err := h.service.DeleteAdjustment(c.Request.Context(), uint(id), uint(adjustmentID), tenantID.(uint), permission.(int))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	setETag(c, report.Version)
+	c.JSON(http.StatusOK, report)
+}
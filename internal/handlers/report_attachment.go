@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/service"
+	"taxifleet/backend/internal/thumbnail"
+
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead allows for the form encoding around an uploaded file
+const multipartOverhead = 64 << 10
+
+func (h *ReportHandler) ListAttachments(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	attachments, err := h.service.ListAttachments(c.Request.Context(), uint(id), tenantID.(uint))
+	if err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, attachments)
+}
+
+func (h *ReportHandler) UploadAttachment(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+	permission, _ := c.Get("permission")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.service.AttachmentMaxSize()+multipartOverhead)
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respondError(c, http.StatusRequestEntityTooLarge, service.ErrAttachmentTooLarge)
+			return
+		}
+		apierror.Abort(c, apierror.BadRequest("A file is required in the 'file' form field"))
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.service.AddAttachment(c.Request.Context(), uint(id), tenantID.(uint), userID.(uint), permission.(int), header.Filename, file)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, attachment)
+}
+
+func (h *ReportHandler) DownloadAttachment(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+	attachmentID, err := strconv.ParseUint(c.Param("attachmentId"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid attachment ID"))
+		return
+	}
+
+	attachment, err := h.service.OpenAttachment(c.Request.Context(), uint(id), uint(attachmentID), tenantID.(uint))
+	if err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+	h.serveAttachment(c, attachment)
+}
+
+// DownloadFile streams a file by its ID alone; files are report attachments
+func (h *ReportHandler) DownloadFile(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	attachment, err := h.service.GetAttachment(c.Request.Context(), uint(id), tenantID.(uint))
+	if err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+	h.serveAttachment(c, attachment)
+}
+
+// FileThumbnail returns a small JPEG of an image file for list views
+func (h *ReportHandler) FileThumbnail(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	attachment, err := h.service.GetAttachment(c.Request.Context(), uint(id), tenantID.(uint))
+	if err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+	thumb, err := h.service.ReadThumbnail(c.Request.Context(), attachment)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	defer thumb.Close()
+
+	// A file never changes, so clients may keep its thumbnail
+	c.Header("Cache-Control", "private, max-age=86400")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.DataFromReader(http.StatusOK, -1, thumbnail.ContentType, thumb, nil)
+}
+
+// FileURL returns a short-lived presigned URL of a file, or the route
+// streaming it when the storage cannot presign
+func (h *ReportHandler) FileURL(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	url, err := h.service.AttachmentURL(c.Request.Context(), uint(id), tenantID.(uint))
+	if err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, url)
+}
+
+func (h *ReportHandler) serveAttachment(c *gin.Context, attachment *repository.ReportAttachment) {
+	file, err := h.service.ReadAttachment(c.Request.Context(), attachment)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	defer file.Close()
+
+	// Photos are shown in the app, so serve them inline with the type detected on upload
+	c.Header("Content-Type", attachment.ContentType)
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", attachment.FileName))
+	c.Header("X-Content-Type-Options", "nosniff")
+	if seeker, ok := file.(io.ReadSeeker); ok {
+		http.ServeContent(c.Writer, c.Request, attachment.FileName, attachment.CreatedAt, seeker)
+		return
+	}
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, file, nil)
+}
+
+func (h *ReportHandler) DeleteAttachment(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+	permission, _ := c.Get("permission")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+	attachmentID, err := strconv.ParseUint(c.Param("attachmentId"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid attachment ID"))
+		return
+	}
+
+	err = h.service.DeleteAttachment(c.Request.Context(), uint(id), uint(attachmentID), tenantID.(uint), userID.(uint), permission.(int))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Attachment deleted successfully"})
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/export"
+	"taxifleet/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Receipt returns the PDF receipt of an approved report, branded with the
+// tenant's logo and name
+func (h *ReportHandler) Receipt(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+	permission, _ := c.Get("permission")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	receipt, err := h.service.Receipt(c.Request.Context(), uint(id), tenantID.(uint), userID.(uint), permission.(int))
+	if err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+	report := receipt.Report
+
+	approver := "-"
+	if report.ApprovedBy != nil {
+		approver = strings.TrimSpace(report.ApprovedBy.FirstName + " " + report.ApprovedBy.LastName)
+	}
+	approvedAt := report.ApprovedAt.UTC().Format("02/01/2006 15:04") + " UTC"
+
+	settlement := [][]interface{}{
+		{"Earnings", report.Earnings},
+		{"Expenses", report.TotalExpenses},
+		{"Adjustments", report.TotalAdjustments},
+	}
+	if report.DriverShare != nil && report.OwnerShare != nil {
+		settlement = append(settlement, []interface{}{"Driver share", *report.DriverShare}, []interface{}{"Owner share", *report.OwnerShare})
+	}
+	if report.LedgerOffset != nil {
+		settlement = append(settlement, []interface{}{"Offset against driver debt", *report.LedgerOffset})
+	}
+
+	doc := export.Document{
+		Title:    "Settlement Receipt",
+		Subtitle: "Receipt " + receipt.Number,
+		Sections: []export.Section{
+			{
+				Title: "Report",
+				Rows: [][]interface{}{
+					{"Driver", strings.TrimSpace(report.Driver.FirstName + " " + report.Driver.LastName)},
+					{"Taxi", report.Taxi.LicensePlate},
+					{"Period", formatDateDDMMYYYY(report.WeekStartDate) + " - " + formatDateDDMMYYYY(report.WeekStartDate.AddDate(0, 0, 6))},
+				},
+			},
+			{
+				Title: "Settlement",
+				Rows:  settlement,
+			},
+			{
+				Title: "Approval",
+				Rows: [][]interface{}{
+					{"Approved by", approver},
+					{"Approved at", approvedAt},
+					{"Reference", receipt.Reference},
+				},
+			},
+		},
+	}
+	if signature := receipt.Signature; signature != nil {
+		caption := "Approved by " + approver + " on " + signature.SignedAt.UTC().Format("02/01/2006 15:04") + " UTC"
+		if signature.Method == "pin" {
+			caption += ", confirmed with their PIN"
+		}
+		doc.Signature = &export.Signature{
+			Image:     signature.Image,
+			ImageType: service.SignatureImageType(signature.ContentType),
+			Caption:   caption,
+		}
+	}
+
+	if err := h.branding.BrandReceipt(c.Request.Context(), tenantID.(uint), userID.(uint), &doc); err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeDocument(c, doc, "pdf", fmt.Sprintf("receipt-%s", receipt.Number))
+}
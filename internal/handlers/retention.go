@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RetentionHandler struct {
+	service *service.RetentionService
+}
+
+func NewRetentionHandler(service *service.RetentionService) *RetentionHandler {
+	return &RetentionHandler{service: service}
+}
+
+// Preview is a dry run of the tenant's retention: the rows per table the next
+// purge would remove
+func (h *RetentionHandler) Preview(c *gin.Context) {
+	tenantID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	report, err := h.service.Preview(c.Request.Context(), uint(tenantID))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
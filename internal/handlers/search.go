@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SearchHandler struct {
+	service *service.SearchService
+}
+
+func NewSearchHandler(service *service.SearchService) *SearchHandler {
+	return &SearchHandler{service: service}
+}
+
+func (h *SearchHandler) Search(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+	permission, _ := c.Get("permission")
+
+	results, err := h.service.Search(c.Request.Context(), tenantID.(uint), userID.(uint), permission.(int), c.Query("q"))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"taxis":    taxiList(results.Taxis),
+		"reports":  reportList(results.Reports),
+		"expenses": expenseList(results.Expenses),
+	})
+}
+
+// listOptions reads the search and sort parameters of a list request
+func listOptions(c *gin.Context) service.ListOptions {
+	return service.ListOptions{Query: c.Query("q"), Sort: c.Query("sort")}
+}
+
+// pageRequested reports whether a list request asks for a page of the list,
+// by giving a cursor or a limit, rather than the whole list
+func pageRequested(c *gin.Context) bool {
+	return c.Query("cursor") != "" || c.Query("limit") != ""
+}
+
+// pageVariant tells the pages of a list apart in its ETag
+func pageVariant(c *gin.Context) string {
+	return "page-" + c.Query("cursor") + "-" + c.DefaultQuery("limit", "50")
+}
+
+// pageLimit reads the page size of a page request, aborting the request when
+// it is invalid. Pages follow the list's default order, so they can't be
+// searched or sorted.
+func pageLimit(c *gin.Context) (int, bool) {
+	if c.Query("q") != "" || c.Query("sort") != "" {
+		apierror.Abort(c, apierror.BadRequest("cursor and limit can't be combined with q or sort"))
+		return 0, false
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		apierror.Abort(c, apierror.BadRequest("limit must be between 1 and 200"))
+		return 0, false
+	}
+	return limit, true
+}
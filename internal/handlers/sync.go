@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SyncHandler struct {
+	service *service.SyncService
+}
+
+func NewSyncHandler(service *service.SyncService) *SyncHandler {
+	return &SyncHandler{service: service}
+}
+
+// Sync applies the reports and expenses the driver app created or edited
+// offline and returns the outcome of each plus the server-side changes since
+// the cursor
+func (h *SyncHandler) Sync(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+	permission, _ := c.Get("permission")
+
+	var req service.SyncRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	resp, err := h.service.Sync(c.Request.Context(), tenantID.(uint), userID.(uint), permission.(int), req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
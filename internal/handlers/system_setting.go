@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SystemSettingHandler struct {
+	service *service.SystemSettingService
+}
+
+func NewSystemSettingHandler(service *service.SystemSettingService) *SystemSettingHandler {
+	return &SystemSettingHandler{service: service}
+}
+
+func (h *SystemSettingHandler) List(c *gin.Context) {
+	settings, err := h.service.List(c.Request.Context())
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+func (h *SystemSettingHandler) Update(c *gin.Context) {
+	var req struct {
+		Value json.RawMessage `json:"value" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	setting, err := h.service.Set(c.Request.Context(), c.Param("key"), req.Value, userID.(uint))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, setting)
+}
+
+func (h *SystemSettingHandler) Reset(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	setting, err := h.service.Reset(c.Request.Context(), c.Param("key"), userID.(uint))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, setting)
+}
+
+func (h *SystemSettingHandler) Changes(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		apierror.Abort(c, apierror.BadRequest("limit must be between 1 and 200"))
+		return
+	}
+
+	changes, err := h.service.Changes(c.Request.Context(), c.Query("key"), limit)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, changes)
+}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"taxifleet/backend/internal/apierror"
 	"taxifleet/backend/internal/service"
 )
 
@@ -18,86 +19,180 @@ func NewTaxiHandler(service *service.TaxiService) *TaxiHandler {
 
 func (h *TaxiHandler) List(c *gin.Context) {
 	tenantID, _ := c.Get("tenantID")
-	taxis, err := h.service.List(tenantID.(uint))
+
+	version, err := h.service.ListVersion(c.Request.Context(), tenantID.(uint))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if notModified(c, version, "all") {
+		return
+	}
+
+	taxis, err := h.service.List(c.Request.Context(), tenantID.(uint), listOptions(c))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, taxis)
+	if apiVersion(c) >= 2 {
+		c.JSON(http.StatusOK, taxisV2(taxis))
+		return
+	}
+	c.JSON(http.StatusOK, taxiList(taxis))
+}
+
+// BulkStatus sets the status of several taxis in one transaction: all of
+// them, or none if any can't be updated
+func (h *TaxiHandler) BulkStatus(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+
+	var req service.BulkStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	result, err := h.service.BulkStatus(c.Request.Context(), req.IDs, req.Status, tenantID.(uint), userID.(uint))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	respondBulk(c, result)
+}
+
+// Count returns the number of the tenant's taxis, of the status given by
+// ?status= if any
+func (h *TaxiHandler) Count(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+
+	count, err := h.service.Count(c.Request.Context(), tenantID.(uint), c.Query("status"))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"count": count})
 }
 
 func (h *TaxiHandler) Create(c *gin.Context) {
 	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
 
 	var req service.CreateTaxiRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Abort(c, apierror.Validation(err))
 		return
 	}
 
-	taxi, err := h.service.Create(tenantID.(uint), req)
+	taxi, err := h.service.Create(c.Request.Context(), tenantID.(uint), userID.(uint), req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, taxi)
+	c.JSON(http.StatusCreated, versioned(c, taxi, taxiV2))
 }
 
 func (h *TaxiHandler) Get(c *gin.Context) {
 	tenantID, _ := c.Get("tenantID")
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
 		return
 	}
 
-	taxi, err := h.service.GetByID(uint(id), tenantID.(uint))
+	taxi, err := h.service.GetByID(c.Request.Context(), uint(id), tenantID.(uint))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		respondError(c, http.StatusNotFound, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, taxi)
+	setETag(c, taxi.Version)
+	c.JSON(http.StatusOK, versioned(c, taxi, taxiV2))
 }
 
 func (h *TaxiHandler) Update(c *gin.Context) {
 	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
 		return
 	}
 
 	var req service.UpdateTaxiRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Abort(c, apierror.Validation(err))
 		return
 	}
+	if req.Version == 0 {
+		if req.Version, err = ifMatchVersion(c); err != nil {
+			respondError(c, http.StatusBadRequest, err)
+			return
+		}
+	}
 
-	taxi, err := h.service.Update(uint(id), tenantID.(uint), req)
+	taxi, err := h.service.Update(c.Request.Context(), uint(id), tenantID.(uint), userID.(uint), req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, taxi)
+	setETag(c, taxi.Version)
+	c.JSON(http.StatusOK, versioned(c, taxi, taxiV2))
 }
 
 func (h *TaxiHandler) Delete(c *gin.Context) {
 	tenantID, _ := c.Get("tenantID")
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
 		return
 	}
 
-	if err := h.service.Delete(uint(id), tenantID.(uint)); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := h.service.Delete(c.Request.Context(), uint(id), tenantID.(uint)); err != nil {
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Taxi deleted successfully"})
 }
 
+func (h *TaxiHandler) Assignments(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	assignments, err := h.service.GetAssignments(c.Request.Context(), uint(id), tenantID.(uint))
+	if err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, assignments)
+}
+
+// Ledger returns the taxi's cash ledger: its approved earnings less expenses,
+// adjustments and deposits, with the cash in hand after each entry
+func (h *TaxiHandler) Ledger(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	ledger, err := h.service.Ledger(c.Request.Context(), uint(id), tenantID.(uint))
+	if err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ledger)
+}
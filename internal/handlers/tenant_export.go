@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TenantExportHandler struct {
+	service *service.TenantExportService
+}
+
+func NewTenantExportHandler(service *service.TenantExportService) *TenantExportHandler {
+	return &TenantExportHandler{service: service}
+}
+
+func (h *TenantExportHandler) Create(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid ID"))
+		return
+	}
+
+	result, err := h.service.Export(c.Request.Context(), uint(id))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+func (h *TenantExportHandler) Download(c *gin.Context) {
+	token := c.Param("token")
+	path, err := h.service.Open(token)
+	if err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.FileAttachment(path, fmt.Sprintf("tenant-export-%s.zip", token[:8]))
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TripHandler struct {
+	service *service.TripService
+}
+
+func NewTripHandler(service *service.TripService) *TripHandler {
+	return &TripHandler{service: service}
+}
+
+// LogBatch stores a batch of the current driver's trips
+func (h *TripHandler) LogBatch(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+
+	var req service.LogTripsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	count, err := h.service.LogTrips(c.Request.Context(), tenantID.(uint), userID.(uint), req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"created": count})
+}
+
+// List returns the trips started between the from and to dates (YYYY-MM-DD)
+func (h *TripHandler) List(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+	permission, _ := c.Get("permission")
+
+	trips, err := h.service.List(c.Request.Context(), tenantID.(uint), userID.(uint), permission.(int), c.Query("from"), c.Query("to"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, trips)
+}
+
+// WeeklyEarnings suggests the earnings of the current driver's report for a
+// taxi and the week containing week_start_date (YYYY-MM-DD, default today)
+func (h *TripHandler) WeeklyEarnings(c *gin.Context) {
+	tenantID, _ := c.Get("tenantID")
+	userID, _ := c.Get("userID")
+
+	taxiID, err := strconv.ParseUint(c.Query("taxi_id"), 10, 32)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("taxi_id is required"))
+		return
+	}
+
+	date := time.Now()
+	if value := c.Query("week_start_date"); value != "" {
+		date, err = time.Parse("2006-01-02", value)
+		if err != nil {
+			apierror.Abort(c, apierror.BadRequest("week_start_date must be a YYYY-MM-DD date"))
+			return
+		}
+	}
+
+	suggestion, err := h.service.SuggestEarnings(c.Request.Context(), tenantID.(uint), userID.(uint), uint(taxiID), date)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, suggestion)
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"taxifleet/backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setETag exposes a record version so clients can send it back in If-Match
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", `"`+strconv.Itoa(version)+`"`)
+}
+
+// ifMatchVersion reads the record version from the If-Match header, returning
+// 0 when the header is absent
+func ifMatchVersion(c *gin.Context) (int, error) {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		return 0, nil
+	}
+
+	value := strings.Trim(strings.TrimPrefix(header, "W/"), `"`)
+	version, err := strconv.Atoi(value)
+	if err != nil || version < 1 {
+		return 0, errors.New("invalid If-Match header")
+	}
+	return version, nil
+}
+
+// notModified sets the ETag and Last-Modified headers of a list response and,
+// if the client's copy is still current, responds 304 Not Modified. variant
+// tells apart lists that share a version but differ in content, such as a
+// driver's own reports.
+func notModified(c *gin.Context, version *repository.ListVersion, variant string) bool {
+	// The API versions represent the same list differently
+	if v := apiVersion(c); v > 1 {
+		variant += "-v" + strconv.Itoa(v)
+	}
+	etag := fmt.Sprintf(`W/"%d-%d-%s"`, version.Count, version.LastModified.UnixNano(), variant)
+	c.Header("ETag", etag)
+	// Lists are per tenant and user; clients must revalidate before reuse
+	c.Header("Cache-Control", "private, no-cache")
+	if !version.LastModified.IsZero() {
+		c.Header("Last-Modified", version.LastModified.UTC().Format(http.TimeFormat))
+	}
+
+	current := false
+	if match := c.GetHeader("If-None-Match"); match != "" {
+		current = etagMatches(match, etag)
+	} else if since := c.GetHeader("If-Modified-Since"); since != "" && !version.LastModified.IsZero() {
+		// Last-Modified has second precision; a change within the same second
+		// as the client's copy is caught by the ETag only
+		t, err := http.ParseTime(since)
+		current = err == nil && !version.LastModified.Truncate(time.Second).After(t)
+	}
+
+	if current {
+		c.Status(http.StatusNotModified)
+		c.Abort()
+	}
+	return current
+}
+
+// etagMatches compares an If-None-Match header with weak comparison
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
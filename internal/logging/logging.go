@@ -0,0 +1,41 @@
+// Package logging carries request-scoped log fields through a context so every
+// log entry of a request can be correlated.
+package logging
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+type fieldsKey struct{}
+
+// WithFields returns a copy of ctx carrying the given fields in addition to
+// those already attached
+func WithFields(ctx context.Context, fields logrus.Fields) context.Context {
+	merged := logrus.Fields{}
+	for k, v := range Fields(ctx) {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
+// Fields returns the fields attached to ctx
+func Fields(ctx context.Context) logrus.Fields {
+	fields, _ := ctx.Value(fieldsKey{}).(logrus.Fields)
+	return fields
+}
+
+// RequestID returns the ID of the request ctx belongs to, if any
+func RequestID(ctx context.Context) string {
+	id, _ := Fields(ctx)["request_id"].(string)
+	return id
+}
+
+// Entry returns a log entry carrying the fields attached to ctx
+func Entry(ctx context.Context, logger *logrus.Logger) *logrus.Entry {
+	return logger.WithContext(ctx).WithFields(Fields(ctx))
+}
@@ -0,0 +1,13 @@
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// RegisterDBStats collects the statistics of the connection pool on every
+// scrape, as the go_sql_* metrics labeled with the database name
+func RegisterDBStats(db *sql.DB, name string) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, name))
+}
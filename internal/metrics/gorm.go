@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"gorm.io/gorm"
+)
+
+const gormStartKey = "metrics:start"
+
+// repositoryPackage is where the queries are written; frames of other code
+// calling it are not counted
+const repositoryPackage = "taxifleet/backend/internal/repository."
+
+// QueryDuration times every database call by the repository method that
+// made it
+var QueryDuration = promauto.With(Registry).NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "db_query_duration_seconds",
+	Help:    "Duration of database calls by repository method.",
+	Buckets: []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+}, []string{"method"})
+
+// InstrumentGORM times every database call into QueryDuration
+func InstrumentGORM(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("metrics:before_create", startTimer),
+		cb.Create().After("gorm:create").Register("metrics:after_create", observeDuration),
+		cb.Query().Before("gorm:query").Register("metrics:before_query", startTimer),
+		cb.Query().After("gorm:query").Register("metrics:after_query", observeDuration),
+		cb.Update().Before("gorm:update").Register("metrics:before_update", startTimer),
+		cb.Update().After("gorm:update").Register("metrics:after_update", observeDuration),
+		cb.Delete().Before("gorm:delete").Register("metrics:before_delete", startTimer),
+		cb.Delete().After("gorm:delete").Register("metrics:after_delete", observeDuration),
+		cb.Row().Before("gorm:row").Register("metrics:before_row", startTimer),
+		cb.Row().After("gorm:row").Register("metrics:after_row", observeDuration),
+		cb.Raw().Before("gorm:raw").Register("metrics:before_raw", startTimer),
+		cb.Raw().After("gorm:raw").Register("metrics:after_raw", observeDuration),
+	)
+}
+
+func startTimer(db *gorm.DB) {
+	db.InstanceSet(gormStartKey, time.Now())
+}
+
+func observeDuration(db *gorm.DB) {
+	value, ok := db.InstanceGet(gormStartKey)
+	if !ok {
+		return
+	}
+	QueryDuration.WithLabelValues(callerMethod()).Observe(time.Since(value.(time.Time)).Seconds())
+}
+
+// callerMethod names the repository method on the stack that was called from
+// outside the repository, skipping its helpers, closures and GORM itself
+func callerMethod() string {
+	pcs := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	names := make([]string, 0, 16)
+	for {
+		frame, more := frames.Next()
+		names = append(names, frame.Function)
+		if !more {
+			break
+		}
+	}
+	return repositoryMethod(names)
+}
+
+// repositoryMethod picks the outermost repository function of the first run
+// of repository and GORM frames, innermost first, such as "GetTaxiByID" for
+// "taxifleet/backend/internal/repository.(*Repository).GetTaxiByID"
+func repositoryMethod(functions []string) string {
+	method := ""
+	for _, function := range functions {
+		switch {
+		case strings.HasPrefix(function, repositoryPackage):
+			method = function
+		case method != "" && !strings.HasPrefix(function, "gorm.io/"):
+			return trimMethod(method)
+		}
+	}
+	if method == "" {
+		return "unknown"
+	}
+	return trimMethod(method)
+}
+
+func trimMethod(function string) string {
+	name := strings.TrimPrefix(function, repositoryPackage)
+	name = strings.TrimPrefix(name, "(*Repository).")
+	// Closures are named after their function: GetTaxiByID.func1
+	if i := strings.Index(name, ".func"); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
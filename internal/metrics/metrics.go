@@ -0,0 +1,32 @@
+// Package metrics registers the API's Prometheus metrics and serves them, so
+// any Prometheus-compatible scraper can collect them from /metrics.
+package metrics
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry holds the metrics of the database instrumentation and collector
+var Registry = prometheus.NewRegistry()
+
+// Handler serves the registry. With a token, scrapers must send it as a
+// bearer token.
+func Handler(token string) http.Handler {
+	metrics := promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if token != "" {
+			got := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+		metrics.ServeHTTP(w, req)
+	})
+}
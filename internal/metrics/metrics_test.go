@@ -0,0 +1,124 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	_ "github.com/lib/pq"
+)
+
+// scrape returns the registry's metrics as served to scrapers
+func scrape(t *testing.T) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	Handler("").ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected the metrics, got %d", rec.Code)
+	}
+	return rec.Body.String()
+}
+
+func TestQueryDuration(t *testing.T) {
+	QueryDuration.WithLabelValues("TestQueryDuration").Observe(0.005)
+	QueryDuration.WithLabelValues("TestQueryDuration").Observe(2)
+
+	out := scrape(t)
+	for _, line := range []string{
+		`db_query_duration_seconds_bucket{method="TestQueryDuration",le="0.005"} 1` + "\n",
+		`db_query_duration_seconds_bucket{method="TestQueryDuration",le="+Inf"} 2` + "\n",
+		`db_query_duration_seconds_count{method="TestQueryDuration"} 2` + "\n",
+	} {
+		if !strings.Contains(out, line) {
+			t.Errorf("expected %q in the output", line)
+		}
+	}
+}
+
+func TestRegisterDBStats(t *testing.T) {
+	// The pool only connects when used, so no database is needed
+	db, err := sql.Open("postgres", "host=localhost")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(25)
+	if err := RegisterDBStats(db, "test_register"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := scrape(t)
+	for _, line := range []string{
+		`go_sql_max_open_connections{db_name="test_register"} 25` + "\n",
+		`go_sql_open_connections{db_name="test_register"} 0` + "\n",
+		`go_sql_wait_count_total{db_name="test_register"} 0` + "\n",
+	} {
+		if !strings.Contains(out, line) {
+			t.Errorf("expected %q in the output", line)
+		}
+	}
+}
+
+func TestHandlerRequiresToken(t *testing.T) {
+	handler := Handler("secret")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without the token, got %d", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain") {
+		t.Fatalf("expected the metrics with the token, got %d", rec.Code)
+	}
+}
+
+func TestRepositoryMethod(t *testing.T) {
+	tests := []struct {
+		name      string
+		functions []string
+		want      string
+	}{
+		{
+			name: "method",
+			functions: []string{
+				"gorm.io/gorm.(*processor).Execute",
+				"gorm.io/gorm.(*DB).First",
+				"taxifleet/backend/internal/repository.(*Repository).GetTaxiByID",
+				"taxifleet/backend/internal/service.(*TaxiService).Get",
+			},
+			want: "GetTaxiByID",
+		},
+		{
+			name: "helper and closure in a transaction",
+			functions: []string{
+				"gorm.io/gorm.(*DB).Updates",
+				"taxifleet/backend/internal/repository.(*Repository).updateVersioned",
+				"taxifleet/backend/internal/repository.(*Repository).UpdateTaxi.func1",
+				"gorm.io/gorm.(*DB).Transaction",
+				"taxifleet/backend/internal/repository.(*Repository).UpdateTaxi",
+				"taxifleet/backend/internal/service.(*TaxiService).Update",
+				"taxifleet/backend/internal/repository.(*Repository).InTransaction",
+			},
+			want: "UpdateTaxi",
+		},
+		{
+			name:      "outside the repository",
+			functions: []string{"gorm.io/gorm.(*DB).Exec", "main.main"},
+			want:      "unknown",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := repositoryMethod(tt.functions); got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
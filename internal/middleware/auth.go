@@ -1,90 +1,151 @@
 package middleware
 
 import (
-	"net/http"
+	"errors"
 	"strings"
 
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/logging"
 	"taxifleet/backend/internal/permissions"
-	"taxifleet/backend/internal/repository"
 	"taxifleet/backend/internal/service"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
 
-func Auth(authService *service.AuthService, logger *logrus.Logger) gin.HandlerFunc {
+// Auth authenticates requests by Bearer token or, for server-to-server
+// access, by an X-API-Key header
+func Auth(authService *service.AuthService, apiKeyService *service.APIKeyService, logger *logrus.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if key := c.GetHeader("X-API-Key"); key != "" {
+			authenticateAPIKey(c, apiKeyService, key, logger)
+			return
+		}
+
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
-			c.Abort()
+			apierror.Abort(c, apierror.Unauthorized("Authorization header required"))
 			return
 		}
 
 		// Extract token from "Bearer <token>"
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
-			c.Abort()
+			apierror.Abort(c, apierror.Unauthorized("Invalid authorization header format"))
 			return
 		}
 
 		token := parts[1]
 		if token == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token is empty"})
-			c.Abort()
+			apierror.Abort(c, apierror.Unauthorized("Token is empty"))
 			return
 		}
 
-		user, err := authService.ValidateToken(token)
+		claims, err := authService.ValidateToken(c.Request.Context(), token)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-			c.Abort()
+			apierror.Abort(c, apierror.Unauthorized(err.Error()))
 			return
 		}
 
-		logger.Infof("User authenticated: %s %s (%s)", user.FirstName, user.LastName, permissions.GetRoleName(user.Permission))
+		// Correlate every log entry of the request with the user
+		ctx := logging.WithFields(c.Request.Context(), logrus.Fields{
+			"user_id":   claims.UserID,
+			"tenant_id": claims.TenantID,
+		})
+		c.Request = c.Request.WithContext(ctx)
 
-		logger.Infof("Tenant ID: %d", user.TenantID)
+		logging.Entry(ctx, logger).Debugf("User authenticated (%s)", permissions.GetRoleName(claims.Permission))
 
-		// Store user in context
-		c.Set("user", user)
-		c.Set("userID", user.ID)
-		c.Set("tenantID", user.TenantID)
-		c.Set("permission", user.Permission)
+		// Store the caller in context
+		c.Set("userID", claims.UserID)
+		c.Set("tenantID", claims.TenantID)
+		c.Set("permission", claims.Permission)
 
 		c.Next()
 	}
 }
 
+// authenticateAPIKey lets a request through with the key's tenant and scoped,
+// read-only permissions. There is no user; userID is 0.
+func authenticateAPIKey(c *gin.Context, apiKeyService *service.APIKeyService, key string, logger *logrus.Logger) {
+	apiKey, err := apiKeyService.Authenticate(c.Request.Context(), key)
+	if err != nil {
+		apierror.Abort(c, apierror.Unauthorized(err.Error()))
+		return
+	}
+
+	if !service.APIKeyAllows(apiKey, c.Request.Method, c.FullPath()) {
+		apierror.Abort(c, apierror.Forbidden("API key scopes do not allow this request"))
+		return
+	}
+
+	ctx := logging.WithFields(c.Request.Context(), logrus.Fields{
+		"api_key_id": apiKey.ID,
+		"tenant_id":  apiKey.TenantID,
+	})
+	c.Request = c.Request.WithContext(ctx)
+
+	logging.Entry(ctx, logger).Debugf("API key authenticated: %s (%s)", apiKey.Name, apiKey.Prefix)
+
+	c.Set("apiKeyID", apiKey.ID)
+	c.Set("userID", uint(0))
+	c.Set("tenantID", apiKey.TenantID)
+	c.Set("permission", service.APIKeyPermission(apiKey))
+
+	c.Next()
+}
+
 func RequirePermission(requiredPermissions ...int) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		user, exists := c.Get("user")
+		permission, exists := c.Get("permission")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
-			c.Abort()
+			apierror.Abort(c, apierror.Unauthorized("User not authenticated"))
 			return
 		}
 
-		userObj, ok := user.(*repository.User)
+		callerPermission, ok := permission.(int)
 		if !ok {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user object"})
-			c.Abort()
+			apierror.Abort(c, apierror.Internal(errors.New("invalid permission in context")))
 			return
 		}
 
-		// Check if user has any of the required permissions
+		// Check if the caller has any of the required permissions
 		hasPermission := false
 		for _, perm := range requiredPermissions {
-			if permissions.HasPermission(userObj.Permission, perm) {
+			if permissions.HasPermission(callerPermission, perm) {
 				hasPermission = true
 				break
 			}
 		}
 
 		if !hasPermission {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
-			c.Abort()
+			apierror.Abort(c, apierror.Forbidden("Insufficient permissions"))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// Authorize lets the tenant's policies decide about the action; without a
+// policy about it the caller needs any of the fallback permissions, as with
+// RequirePermission. Must run after Auth.
+func Authorize(policies *service.PolicyService, action string, fallback ...int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		permission, _ := c.Get("permission")
+		callerPermission, ok := permission.(int)
+		if !ok {
+			apierror.Abort(c, apierror.Unauthorized("User not authenticated"))
+			return
+		}
+
+		err := policies.Authorize(c.Request.Context(), c.GetUint("tenantID"), c.GetUint("userID"), callerPermission, action, nil, fallback...)
+		switch {
+		case errors.Is(err, service.ErrPolicyDenied):
+			apierror.Abort(c, apierror.Forbidden("Insufficient permissions"))
+			return
+		case err != nil:
+			apierror.Abort(c, apierror.Internal(err))
 			return
 		}
 
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/getsentry/sentry-go"
+	sentrygin "github.com/getsentry/sentry-go/gin"
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorReporting tags the request's Sentry scope with the request ID, user and
+// tenant, and reports responses with a 5xx status. Panics are reported by
+// sentrygin, which must run before this middleware.
+func ErrorReporting() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hub := sentrygin.GetHubFromContext(c)
+		if hub == nil {
+			c.Next()
+			return
+		}
+
+		// Deferred calls run before sentrygin recovers a panic, so the panic
+		// event carries the tags as well
+		defer tagScope(hub.Scope(), c)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		if status < http.StatusInternalServerError {
+			return
+		}
+		tagScope(hub.Scope(), c)
+		hub.WithScope(func(scope *sentry.Scope) {
+			scope.SetLevel(sentry.LevelError)
+			scope.SetTag("status_code", fmt.Sprint(status))
+			if len(c.Errors) > 0 {
+				scope.SetExtra("errors", c.Errors.Errors())
+			}
+			hub.CaptureMessage(fmt.Sprintf("%d %s %s", status, c.Request.Method, c.FullPath()))
+		})
+	}
+}
+
+func tagScope(scope *sentry.Scope, c *gin.Context) {
+	scope.SetTag("request_id", c.GetString("requestID"))
+	if userID, ok := c.Get("userID"); ok {
+		scope.SetUser(sentry.User{ID: fmt.Sprint(userID)})
+		scope.SetTag("tenant_id", fmt.Sprint(c.MustGet("tenantID")))
+	}
+}
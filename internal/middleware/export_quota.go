@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/logging"
+	"taxifleet/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// ExportQuota refuses exports once the tenant used up the monthly export
+// quota of its plan and counts the successful ones. Must run after Auth.
+func ExportQuota(plans *service.PlanService, logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		tenantID := c.GetUint("tenantID")
+
+		err := plans.CheckExport(ctx, tenantID)
+		switch {
+		case errors.Is(err, service.ErrPlanLimitReached):
+			apierror.Abort(c, apierror.New(http.StatusForbidden, "plan_limit_reached", err.Error()))
+			return
+		case err != nil:
+			apierror.Abort(c, apierror.Internal(err))
+			return
+		}
+
+		c.Next()
+
+		if c.Writer.Status() < http.StatusBadRequest {
+			if err := plans.RecordExport(ctx, tenantID); err != nil {
+				logging.Entry(ctx, logger).WithError(err).Error("Failed to count export")
+			}
+		}
+	}
+}
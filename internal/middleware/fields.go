@@ -0,0 +1,186 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"taxifleet/backend/internal/apierror"
+
+	"github.com/gin-gonic/gin"
+)
+
+var fieldPathPattern = regexp.MustCompile(`^[a-z0-9_]+(\.[a-z0-9_]+)*$`)
+
+// fieldTree is a parsed list of dotted paths such as "driver.first_name"; a
+// node without children selects its whole subtree
+type fieldTree map[string]fieldTree
+
+func parseFieldTree(param string) (fieldTree, bool) {
+	tree := fieldTree{}
+	for _, path := range strings.Split(param, ",") {
+		path = strings.TrimSpace(path)
+		if !fieldPathPattern.MatchString(path) {
+			return nil, false
+		}
+		node := tree
+		for _, name := range strings.Split(path, ".") {
+			if node[name] == nil {
+				node[name] = fieldTree{}
+			}
+			node = node[name]
+		}
+	}
+	return tree, true
+}
+
+// bufferedWriter holds the response body back so it can be rewritten
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+// Fields trims JSON responses of GET requests to what the client asks for:
+// ?fields=id,license_plate,driver.first_name keeps only the listed fields,
+// and ?include=driver,taxi.driver keeps only the listed embedded objects
+// (everything else nested, such as tenant, is dropped; an empty include drops
+// them all). Both apply to every item of a list. Without them responses are
+// unchanged.
+func Fields() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		fieldsParam := c.Query("fields")
+		includeParam, hasInclude := c.GetQuery("include")
+		if c.Request.Method != http.MethodGet || (fieldsParam == "" && !hasInclude) {
+			c.Next()
+			return
+		}
+
+		var fields, include fieldTree
+		var ok bool
+		if fieldsParam != "" {
+			if fields, ok = parseFieldTree(fieldsParam); !ok {
+				apierror.Abort(c, apierror.BadRequest("Invalid fields parameter"))
+				return
+			}
+		}
+		if hasInclude {
+			include = fieldTree{}
+		}
+		if includeParam != "" {
+			if include, ok = parseFieldTree(includeParam); !ok {
+				apierror.Abort(c, apierror.BadRequest("Invalid include parameter"))
+				return
+			}
+		}
+
+		original := c.Writer
+		writer := &bufferedWriter{ResponseWriter: original}
+		c.Writer = writer
+		c.Next()
+		c.Writer = original
+
+		body := writer.body.Bytes()
+		status := writer.Status()
+		if status >= 200 && status < 300 && strings.HasPrefix(original.Header().Get("Content-Type"), "application/json") {
+			if shaped, err := shapeJSON(body, fields, include); err == nil {
+				body = shaped
+			}
+		}
+		original.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		original.Write(body)
+	}
+}
+
+func shapeJSON(body []byte, fields, include fieldTree) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if include != nil {
+		value = includeEmbeds(value, include)
+	}
+	if fields != nil {
+		value = selectFields(value, fields)
+	}
+	return json.Marshal(value)
+}
+
+// selectFields keeps the fields of each object that are in the tree
+func selectFields(value interface{}, tree fieldTree) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		for i := range v {
+			v[i] = selectFields(v[i], tree)
+		}
+		return v
+	case map[string]interface{}:
+		for key, child := range v {
+			sub, ok := tree[key]
+			switch {
+			case !ok:
+				delete(v, key)
+			case len(sub) > 0:
+				v[key] = selectFields(child, sub)
+			}
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// includeEmbeds drops embedded objects of each object that are not in the
+// tree. The objects themselves (list items) are kept.
+func includeEmbeds(value interface{}, tree fieldTree) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		for i := range v {
+			v[i] = includeEmbeds(v[i], tree)
+		}
+		return v
+	case map[string]interface{}:
+		for key, child := range v {
+			if !isEmbed(child) {
+				continue
+			}
+			sub, ok := tree[key]
+			if !ok {
+				delete(v, key)
+				continue
+			}
+			v[key] = includeEmbeds(child, sub)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// isEmbed reports whether a field holds a related object or a list of them
+func isEmbed(value interface{}) bool {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return true
+	case []interface{}:
+		if len(v) == 0 {
+			return false
+		}
+		_, ok := v[0].(map[string]interface{})
+		return ok
+	default:
+		return false
+	}
+}
@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/logging"
+	"taxifleet/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// maxIdempotencyKeyLength matches the idempotency_keys.key column
+const maxIdempotencyKeyLength = 255
+
+// responseRecorder keeps a copy of the response body while writing it
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency replays the stored response when a request is retried with the
+// same Idempotency-Key header, so flaky clients cannot create duplicates.
+// Requests without the header are processed normally. Must run after Auth.
+func Idempotency(idempotencyService *service.IdempotencyService, logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("Idempotency-Key")
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			apierror.Abort(c, apierror.BadRequest("Idempotency-Key is too long"))
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			apierror.Abort(c, apierror.BadRequest("Failed to read request body"))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := sha256.New()
+		hash.Write([]byte(c.Request.Method + " " + c.Request.URL.Path + "\n"))
+		hash.Write(body)
+		requestHash := hex.EncodeToString(hash.Sum(nil))
+
+		ctx := c.Request.Context()
+		userID := c.GetUint("userID")
+		record, replay, err := idempotencyService.Begin(ctx, userID, key, requestHash)
+		switch {
+		case errors.Is(err, service.ErrIdempotencyKeyInFlight):
+			apierror.Abort(c, apierror.Conflict("idempotency_key_in_flight", err.Error()))
+			return
+		case errors.Is(err, service.ErrIdempotencyKeyReused):
+			apierror.Abort(c, apierror.New(http.StatusUnprocessableEntity, "idempotency_key_reused", err.Error()))
+			return
+		case err != nil:
+			apierror.Abort(c, apierror.Internal(err))
+			return
+		}
+
+		if replay {
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(record.StatusCode, "application/json; charset=utf-8", record.ResponseBody)
+			c.Abort()
+			return
+		}
+
+		// The outcome is stored even if the client gave up on the request
+		storeCtx := context.WithoutCancel(ctx)
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		completed := false
+		defer func() {
+			// Server errors and panics are not final; free the key for a retry
+			if completed {
+				return
+			}
+			if err := idempotencyService.Release(storeCtx, record); err != nil {
+				logging.Entry(ctx, logger).WithError(err).Error("Failed to release idempotency key")
+			}
+		}()
+
+		c.Next()
+
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			return
+		}
+		if err := idempotencyService.Complete(storeCtx, record, status, recorder.body.Bytes()); err != nil {
+			logging.Entry(ctx, logger).WithError(err).Error("Failed to store idempotent response")
+			return
+		}
+		completed = true
+	}
+}
@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/logging"
+	"taxifleet/backend/internal/ratelimit"
+	"taxifleet/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// bucketIdleTime is how long the bucket of a client that stopped sending
+// requests is kept
+const bucketIdleTime = 10 * time.Minute
+
+// bucket is a client's token bucket
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter keeps a token bucket per client in memory, so each instance
+// limits the requests it serves
+type rateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// allow takes a token from the client's bucket, refilled at rps up to burst.
+// Without a token it returns how long until the next one.
+func (l *rateLimiter) allow(client string, limit service.RateLimit, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) > bucketIdleTime {
+		for key, b := range l.buckets {
+			if now.Sub(b.last) > bucketIdleTime {
+				delete(l.buckets, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	b, ok := l.buckets[client]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		l.buckets[client] = b
+	}
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+now.Sub(b.last).Seconds()*float64(limit.RPS))
+	b.last = now
+
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / float64(limit.RPS) * float64(time.Second))
+	}
+	b.tokens--
+	return true, 0
+}
+
+// RateLimit refuses requests of a client, by IP address, beyond the rate
+// limit of the system settings with 429 and a Retry-After header
+func RateLimit(settings *service.SystemSettingService) gin.HandlerFunc {
+	limiter := &rateLimiter{buckets: make(map[string]*bucket)}
+	return func(c *gin.Context) {
+		limit := settings.Current(c.Request.Context()).RateLimit
+		if limit.RPS <= 0 {
+			c.Next()
+			return
+		}
+
+		allowed, retryAfter := limiter.allow(c.ClientIP(), limit, time.Now())
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			apierror.Abort(c, apierror.New(http.StatusTooManyRequests, "rate_limited", "Too many requests, please slow down"))
+			return
+		}
+		c.Next()
+	}
+}
+
+// hourlyWindow is the window of the per-user and per-API key limits
+const hourlyWindow = time.Hour
+
+// ExportRateLimit refuses exports of a user, or an API key, beyond the
+// exports per hour of the system settings. Must run after Auth.
+func ExportRateLimit(counter ratelimit.Counter, settings *service.SystemSettingService, logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := settings.Current(c.Request.Context()).RateLimit.ExportsPerHour
+		limitPerHour(c, counter, "export:"+callerKey(c), limit, logger)
+	}
+}
+
+// APIKeyRateLimit refuses requests of an API key beyond the requests per hour
+// its tenant's plan allows; users are not limited here. Must run after Auth.
+func APIKeyRateLimit(counter ratelimit.Counter, plans *service.PlanService, logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		apiKeyID := c.GetUint("apiKeyID")
+		if apiKeyID == 0 {
+			c.Next()
+			return
+		}
+
+		limit, err := plans.APIKeyRateLimit(c.Request.Context(), c.GetUint("tenantID"))
+		if err != nil {
+			apierror.Abort(c, apierror.Internal(err))
+			return
+		}
+		limitPerHour(c, counter, callerKey(c), limit, logger)
+	}
+}
+
+// callerKey identifies the API key or user making the request
+func callerKey(c *gin.Context) string {
+	if apiKeyID := c.GetUint("apiKeyID"); apiKeyID != 0 {
+		return fmt.Sprintf("apikey:%d", apiKeyID)
+	}
+	return fmt.Sprintf("user:%d", c.GetUint("userID"))
+}
+
+// limitPerHour counts the request under the key and refuses it with 429 once
+// the hour's limit is used up; 0 is unlimited. X-RateLimit-Limit, -Remaining
+// and -Reset (Unix time) tell the client where it stands. Requests are let
+// through if they cannot be counted.
+func limitPerHour(c *gin.Context, counter ratelimit.Counter, key string, limit int, logger *logrus.Logger) {
+	if limit <= 0 {
+		c.Next()
+		return
+	}
+
+	ctx := c.Request.Context()
+	hits, reset, err := counter.Hit(ctx, key, hourlyWindow)
+	if err != nil {
+		logging.Entry(ctx, logger).WithError(err).Error("Failed to count request against rate limit")
+		c.Next()
+		return
+	}
+
+	c.Header("X-RateLimit-Limit", strconv.Itoa(limit))
+	c.Header("X-RateLimit-Remaining", strconv.FormatInt(max(int64(limit)-hits, 0), 10))
+	c.Header("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+	if hits > int64(limit) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(time.Until(reset).Seconds()))))
+		apierror.Abort(c, apierror.New(http.StatusTooManyRequests, "rate_limited", fmt.Sprintf("Rate limit of %d requests per hour reached", limit)))
+		return
+	}
+	c.Next()
+}
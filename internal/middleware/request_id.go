@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"taxifleet/backend/internal/logging"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// RequestIDHeader carries the ID correlating a request with its log entries
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength caps IDs supplied by clients or proxies
+const maxRequestIDLength = 128
+
+// RequestID propagates the caller's X-Request-ID, or generates one, returns
+// it in the response and attaches it to the request's log fields along with
+// the matched route, e.g. "GET /api/v1/taxis/:id"
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+
+		c.Set("requestID", requestID)
+		c.Header(RequestIDHeader, requestID)
+		fields := logrus.Fields{"request_id": requestID}
+		if route := c.FullPath(); route != "" {
+			fields["route"] = c.Request.Method + " " + route
+		}
+		c.Request = c.Request.WithContext(logging.WithFields(c.Request.Context(), fields))
+
+		c.Next()
+	}
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if r < '!' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+
+	"taxifleet/backend/internal/apierror"
+	"taxifleet/backend/internal/logging"
+	"taxifleet/backend/internal/permissions"
+	"taxifleet/backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// errRequestFailed rolls back the transaction of a request that failed
+var errRequestFailed = errors.New("request failed")
+
+// TenantIsolation runs the request in a transaction scoped to the caller's
+// tenant, so row-level security hides other tenants' rows even from a query
+// that misses its tenant_id filter. Platform admins work across tenants and
+// are not scoped. The transaction rolls back on server errors. Must run after
+// Auth.
+//
+// Server-sent event streams on the streamRoutes are left out, like in
+// Timeout: they last as long as the client stays connected and would hold a
+// pooled connection idle in the transaction. Their handlers scope each query
+// themselves. Other routes are scoped whatever the Accept header says.
+func TenantIsolation(db repository.Transactor, logger *logrus.Logger, streamRoutes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Accept") == "text/event-stream" && slices.Contains(streamRoutes, c.FullPath()) {
+			c.Next()
+			return
+		}
+
+		permission, _ := c.Get("permission")
+		if callerPermission, ok := permission.(int); !ok || permissions.HasPermission(callerPermission, permissions.PermissionManageTenants) {
+			c.Next()
+			return
+		}
+
+		ctx := repository.WithTenant(c.Request.Context(), c.GetUint("tenantID"))
+		handled := false
+		err := db.InTransaction(ctx, func(ctx context.Context) error {
+			handled = true
+			c.Request = c.Request.WithContext(ctx)
+			c.Next()
+			if c.Writer.Status() >= http.StatusInternalServerError {
+				return errRequestFailed
+			}
+			return nil
+		})
+		switch {
+		case err == nil || errors.Is(err, errRequestFailed):
+		case !handled:
+			apierror.Abort(c, apierror.Internal(err))
+		default:
+			// The response is already written
+			logging.Entry(ctx, logger).WithError(err).Error("Failed to commit request transaction")
+		}
+	}
+}
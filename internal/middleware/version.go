@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIVersion tags the requests of a route group with the API version they
+// were made against, so handlers shared between versions can respond in the
+// representation of that version
+func APIVersion(version int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("apiVersion", version)
+		c.Next()
+	}
+}
+
+// Deprecated marks v1 routes that have a v2 replacement: Deprecation carries
+// when they were deprecated, Sunset when they will be removed (left out while
+// zero) and Link the same path under /api/v2
+func Deprecated(since, sunset time.Time) gin.HandlerFunc {
+	deprecation := "@" + strconv.FormatInt(since.Unix(), 10)
+	return func(c *gin.Context) {
+		c.Header("Deprecation", deprecation)
+		if !sunset.IsZero() {
+			c.Header("Sunset", sunset.UTC().Format(http.TimeFormat))
+		}
+		if successor, ok := strings.CutPrefix(c.Request.URL.Path, "/api/v1/"); ok {
+			c.Header("Link", `</api/v2/`+successor+`>; rel="successor-version"`)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,153 @@
+// Package money holds sums of money as integer minor units (cents), so that
+// totals, splits and recalculations add up exactly instead of drifting the
+// way float64 amounts do. The API and exports still show amounts in major
+// units with two decimals, e.g. 45.50.
+package money
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Amount is a sum of money in minor units
+type Amount int64
+
+// ErrInvalid is returned for text that is not an amount with at most two
+// decimals
+var ErrInvalid = errors.New("amount must be a number with at most two decimals")
+
+// FromFloat converts major units to an amount, rounding to the nearest cent
+func FromFloat(units float64) Amount {
+	return Amount(math.Round(units * 100))
+}
+
+// Parse reads an amount in major units, such as "45.5" or "-3", exactly. More
+// than two decimals are an error.
+func Parse(s string) (Amount, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+
+	whole, fraction, _ := strings.Cut(s, ".")
+	if whole == "" && fraction == "" || len(fraction) > 2 {
+		return 0, ErrInvalid
+	}
+	units, err := parseDigits(whole)
+	if err != nil {
+		return 0, err
+	}
+	cents, err := parseDigits(fraction + "00"[len(fraction):])
+	if err != nil {
+		return 0, err
+	}
+	if units > (math.MaxInt64-cents)/100 {
+		return 0, ErrInvalid
+	}
+
+	amount := Amount(units*100 + cents)
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+func parseDigits(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(s, 10, 63)
+	if err != nil {
+		return 0, ErrInvalid
+	}
+	return int64(n), nil
+}
+
+// Float returns the amount in major units, for ratios and display only
+func (a Amount) Float() float64 {
+	return float64(a) / 100
+}
+
+// String formats the amount in major units with two decimals
+func (a Amount) String() string {
+	sign := ""
+	cents := int64(a)
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// Mul returns the amount multiplied by factor, rounded to the nearest cent
+func (a Amount) Mul(factor float64) Amount {
+	return Amount(math.Round(float64(a) * factor))
+}
+
+// Div returns the amount divided by n, rounded to the nearest cent
+func (a Amount) Div(n int) Amount {
+	return Amount(math.Round(float64(a) / float64(n)))
+}
+
+// MarshalJSON writes the amount as a number in major units
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON reads a number in major units with at most two decimals
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if bytes.ContainsAny(data, "eE\"") {
+		return ErrInvalid
+	}
+	amount, err := Parse(string(data))
+	if err != nil {
+		return err
+	}
+	*a = amount
+	return nil
+}
+
+// Scan reads a column of minor units. Aggregates such as SUM come back as
+// numeric text and are rounded to the nearest cent.
+func (a *Amount) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*a = 0
+	case int64:
+		*a = Amount(v)
+	case float64:
+		*a = Amount(math.Round(v))
+	case []byte:
+		return a.scanText(string(v))
+	case string:
+		return a.scanText(v)
+	default:
+		return fmt.Errorf("cannot scan %T into money.Amount", src)
+	}
+	return nil
+}
+
+func (a *Amount) scanText(s string) error {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		*a = Amount(n)
+		return nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("cannot scan %q into money.Amount", s)
+	}
+	*a = Amount(math.Round(f))
+	return nil
+}
+
+// Value stores the amount as minor units
+func (a Amount) Value() (driver.Value, error) {
+	return int64(a), nil
+}
@@ -0,0 +1,91 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		text string
+		want Amount
+		ok   bool
+	}{
+		{"45.5", 4550, true},
+		{"0.01", 1, true},
+		{"-3", -300, true},
+		{"19.99", 1999, true},
+		{".5", 50, true},
+		{"1.005", 0, false},
+		{"12,50", 0, false},
+		{"", 0, false},
+		{"--5", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.text)
+		if got != tt.want || (err == nil) != tt.ok {
+			t.Errorf("Parse(%q) = %v, %v, want %v, ok %v", tt.text, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestSumsDoNotDrift(t *testing.T) {
+	var total Amount
+	var floatTotal float64
+	for range 10 {
+		total += FromFloat(0.1)
+		floatTotal += 0.1
+	}
+	if total.String() != "1.00" || total != FromFloat(1) {
+		t.Fatalf("expected ten times 0.10 to be 1.00, got %s", total)
+	}
+	if floatTotal == 1 {
+		t.Fatal("float64 sums were expected to drift")
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var payload struct {
+		Amount Amount  `json:"amount"`
+		Share  *Amount `json:"share"`
+	}
+	if err := json.Unmarshal([]byte(`{"amount": 12.3, "share": null}`), &payload); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if payload.Amount != 1230 || payload.Share != nil {
+		t.Fatalf("unexpected amounts %+v", payload)
+	}
+	out, _ := json.Marshal(payload)
+	if string(out) != `{"amount":12.30,"share":null}` {
+		t.Fatalf("unexpected JSON %s", out)
+	}
+
+	for _, invalid := range []string{`{"amount": 1.005}`, `{"amount": "12"}`, `{"amount": 1e3}`} {
+		if err := json.Unmarshal([]byte(invalid), &payload); err == nil {
+			t.Errorf("expected %s to be refused", invalid)
+		}
+	}
+}
+
+func TestScanAggregates(t *testing.T) {
+	var a Amount
+	for src, want := range map[interface{}]Amount{int64(1250): 1250, "1250": 1250, "1250.4": 1250, nil: 0} {
+		if err := a.Scan(src); err != nil || a != want {
+			t.Errorf("Scan(%v) = %v, %v, want %v", src, a, err, want)
+		}
+	}
+	if err := a.Scan([]byte("-99")); err != nil || a != -99 {
+		t.Errorf("Scan([]byte) = %v, %v", a, err)
+	}
+}
+
+func TestSplitRounding(t *testing.T) {
+	earnings := Amount(10001)
+	driver := earnings.Mul(0.6)
+	if driver != 6001 || earnings-driver != 4000 {
+		t.Fatalf("unexpected split %s / %s", driver, earnings-driver)
+	}
+	if got := Amount(1000).Div(3); got != 333 {
+		t.Fatalf("Div = %v, want 333", got)
+	}
+}
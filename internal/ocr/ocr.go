@@ -0,0 +1,111 @@
+// Package ocr reads the text of receipt photos through a pluggable OCR
+// provider and extracts the figures an expense is made of.
+package ocr
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ErrDisabled is returned when no OCR provider is configured
+var ErrDisabled = errors.New("receipt scanning is not enabled")
+
+// Provider extracts the text of an image
+type Provider interface {
+	ExtractText(ctx context.Context, image []byte) (string, error)
+}
+
+// DisabledProvider refuses every image, used when OCR is not configured
+type DisabledProvider struct{}
+
+func (DisabledProvider) ExtractText(ctx context.Context, image []byte) (string, error) {
+	return "", ErrDisabled
+}
+
+const visionURL = "https://vision.googleapis.com/v1/images:annotate"
+
+// VisionProvider reads text with the Google Cloud Vision API
+type VisionProvider struct {
+	apiKey string
+	client *http.Client
+}
+
+// NewVisionProvider creates a Cloud Vision provider authenticated with an API key
+func NewVisionProvider(apiKey string) *VisionProvider {
+	return &VisionProvider{apiKey: apiKey, client: &http.Client{Timeout: 20 * time.Second}}
+}
+
+type visionRequest struct {
+	Requests []visionImageRequest `json:"requests"`
+}
+
+type visionImageRequest struct {
+	Image    visionImage     `json:"image"`
+	Features []visionFeature `json:"features"`
+}
+
+type visionImage struct {
+	Content string `json:"content"`
+}
+
+type visionFeature struct {
+	Type string `json:"type"`
+}
+
+type visionResponse struct {
+	Responses []struct {
+		FullTextAnnotation struct {
+			Text string `json:"text"`
+		} `json:"fullTextAnnotation"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	} `json:"responses"`
+}
+
+func (p *VisionProvider) ExtractText(ctx context.Context, image []byte) (string, error) {
+	payload, err := json.Marshal(visionRequest{Requests: []visionImageRequest{{
+		Image:    visionImage{Content: base64.StdEncoding.EncodeToString(image)},
+		Features: []visionFeature{{Type: "DOCUMENT_TEXT_DETECTION"}},
+	}}})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, visionURL, bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Goog-Api-Key", p.apiKey)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("cloud vision request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("cloud vision returned %d: %s", resp.StatusCode, body)
+	}
+
+	var result visionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode cloud vision response: %w", err)
+	}
+	if len(result.Responses) == 0 {
+		return "", nil
+	}
+	if result.Responses[0].Error != nil {
+		return "", fmt.Errorf("cloud vision: %s", result.Responses[0].Error.Message)
+	}
+	return result.Responses[0].FullTextAnnotation.Text, nil
+}
@@ -0,0 +1,124 @@
+package ocr
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Receipt holds what could be read from a receipt; fields that could not be
+// found are left zero
+type Receipt struct {
+	Vendor   string
+	Amount   float64
+	Date     time.Time
+	Category string // Suggested expense category
+}
+
+var (
+	// 1 234,56 or 1,234.56 or 12.50; the last two digits are the cents
+	amountPattern  = regexp.MustCompile(`(\d{1,3}(?:[ .,]\d{3})+|\d+)[.,](\d{2})\b`)
+	isoDatePattern = regexp.MustCompile(`\b(\d{4})-(\d{2})-(\d{2})\b`)
+	datePattern    = regexp.MustCompile(`\b(\d{1,2})[/.\-](\d{1,2})[/.\-](\d{4}|\d{2})\b`)
+	totalPattern   = regexp.MustCompile(`(?i)\b(total|ttc|amount due|to pay|montant|summe|gesamt|importe)\b`)
+	subtotalWords  = regexp.MustCompile(`(?i)sub\s*-?\s*total|sous\s*-?\s*total|zwischensumme`)
+)
+
+// categoryKeywords suggest an expense category from words on the receipt
+var categoryKeywords = []struct {
+	category string
+	words    []string
+}{
+	{"fuel", []string{"fuel", "diesel", "petrol", "gasoline", "unleaded", "carburant", "gazole", "sp95", "sp98", "benzin"}},
+	{"cleaning", []string{"car wash", "carwash", "lavage", "cleaning"}},
+	{"insurance", []string{"insurance", "assurance", "versicherung"}},
+	{"repair", []string{"garage", "repair", "tyre", "tire", "reparation", "réparation", "pneu"}},
+}
+
+// ParseReceipt extracts the vendor, total and date from a receipt's text.
+// Dates are read day first unless that is impossible, as on most receipts
+// outside the US.
+func ParseReceipt(text string) Receipt {
+	var receipt Receipt
+	lines := strings.Split(text, "\n")
+
+	// The vendor's name usually heads the receipt
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if strings.IndexFunc(line, isLetter) >= 0 {
+			receipt.Vendor = line
+			break
+		}
+	}
+
+	// Prefer the amount on a total line, else the largest amount printed
+	var largest float64
+	for _, line := range lines {
+		amounts := amountPattern.FindAllStringSubmatch(line, -1)
+		if len(amounts) == 0 {
+			continue
+		}
+		last := parseAmount(amounts[len(amounts)-1])
+		for _, match := range amounts {
+			if amount := parseAmount(match); amount > largest {
+				largest = amount
+			}
+		}
+		if totalPattern.MatchString(line) && !subtotalWords.MatchString(line) && last > receipt.Amount {
+			receipt.Amount = last
+		}
+	}
+	if receipt.Amount == 0 {
+		receipt.Amount = largest
+	}
+
+	receipt.Date = findDate(text)
+
+	lower := strings.ToLower(text)
+	for _, candidate := range categoryKeywords {
+		for _, word := range candidate.words {
+			if strings.Contains(lower, word) {
+				receipt.Category = candidate.category
+				return receipt
+			}
+		}
+	}
+	return receipt
+}
+
+func isLetter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r > 0x7f
+}
+
+func parseAmount(match []string) float64 {
+	whole := strings.NewReplacer(" ", "", ".", "", ",", "").Replace(match[1])
+	amount, _ := strconv.ParseFloat(whole+"."+match[2], 64)
+	return amount
+}
+
+func findDate(text string) time.Time {
+	if match := isoDatePattern.FindStringSubmatch(text); match != nil {
+		if date, err := time.Parse("2006-01-02", match[0]); err == nil {
+			return date
+		}
+	}
+
+	for _, match := range datePattern.FindAllStringSubmatch(text, -1) {
+		day, _ := strconv.Atoi(match[1])
+		month, _ := strconv.Atoi(match[2])
+		year, _ := strconv.Atoi(match[3])
+		if year < 100 {
+			year += 2000
+		}
+		if month > 12 {
+			day, month = month, day
+		}
+		date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+		// time.Date normalizes impossible dates such as 31/02; skip them
+		if date.Day() == day && int(date.Month()) == month {
+			return date
+		}
+	}
+	return time.Time{}
+}
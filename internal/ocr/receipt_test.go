@@ -0,0 +1,35 @@
+package ocr
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseReceipt(t *testing.T) {
+	text := "STATION TOTAL ACCESS\n12 rue de Paris\n14/03/2024 08:12\nGAZOLE 32,15 L\nSOUS-TOTAL 52,10\nTVA 10,42\nTOTAL TTC 62,52 EUR\nCB 62,52\n"
+
+	receipt := ParseReceipt(text)
+	if receipt.Vendor != "STATION TOTAL ACCESS" {
+		t.Errorf("expected the first line as vendor, got %q", receipt.Vendor)
+	}
+	if receipt.Amount != 62.52 {
+		t.Errorf("expected the total 62.52, got %v", receipt.Amount)
+	}
+	if !receipt.Date.Equal(time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected 2024-03-14, got %v", receipt.Date)
+	}
+	if receipt.Category != "fuel" {
+		t.Errorf("expected the fuel category, got %q", receipt.Category)
+	}
+}
+
+func TestParseReceiptFallsBackToLargestAmount(t *testing.T) {
+	receipt := ParseReceipt("Car Wash Express\n2024-05-02\nPremium wash 1,234.50\nTip 2.00\n")
+
+	if receipt.Amount != 1234.5 {
+		t.Errorf("expected 1234.50, got %v", receipt.Amount)
+	}
+	if receipt.Category != "cleaning" {
+		t.Errorf("expected the cleaning category, got %q", receipt.Category)
+	}
+}
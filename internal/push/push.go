@@ -0,0 +1,128 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// ErrInvalidToken is returned when FCM reports that a device token is no
+// longer registered, so callers can forget it
+var ErrInvalidToken = errors.New("device token is no longer valid")
+
+// Message is a push notification payload
+type Message struct {
+	Title string            `json:"title"`
+	Body  string            `json:"body"`
+	Data  map[string]string `json:"data,omitempty"`
+}
+
+// Sender delivers push notifications to a single device token
+type Sender interface {
+	Send(ctx context.Context, token string, msg Message) error
+}
+
+// NoopSender discards all messages, used when push is disabled
+type NoopSender struct{}
+
+func (NoopSender) Send(ctx context.Context, token string, msg Message) error {
+	return nil
+}
+
+const fcmScope = "https://www.googleapis.com/auth/firebase.messaging"
+
+// FCMSender sends notifications through the FCM HTTP v1 API
+type FCMSender struct {
+	projectID string
+	client    *http.Client
+}
+
+// NewFCMSender creates an FCM sender authenticated with a service account file
+func NewFCMSender(projectID, credentialsFile string) (*FCMSender, error) {
+	data, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read FCM credentials: %w", err)
+	}
+
+	creds, err := google.CredentialsFromJSON(context.Background(), data, fcmScope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse FCM credentials: %w", err)
+	}
+
+	client := oauth2.NewClient(context.Background(), creds.TokenSource)
+	client.Timeout = 10 * time.Second
+
+	return &FCMSender{projectID: projectID, client: client}, nil
+}
+
+type fcmRequest struct {
+	Message fcmMessage `json:"message"`
+}
+
+type fcmMessage struct {
+	Token        string            `json:"token"`
+	Notification fcmNotification   `json:"notification"`
+	Data         map[string]string `json:"data,omitempty"`
+}
+
+type fcmNotification struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+type fcmErrorResponse struct {
+	Error struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (s *FCMSender) Send(ctx context.Context, token string, msg Message) error {
+	payload, err := json.Marshal(fcmRequest{
+		Message: fcmMessage{
+			Token:        token,
+			Notification: fcmNotification{Title: msg.Title, Body: msg.Body},
+			Data:         msg.Data,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("https://fcm.googleapis.com/v1/projects/%s/messages:send", s.projectID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send push notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	var fcmErr fcmErrorResponse
+	_ = json.Unmarshal(body, &fcmErr)
+
+	// UNREGISTERED means the app was uninstalled or the token rotated
+	if resp.StatusCode == http.StatusNotFound || fcmErr.Error.Status == "UNREGISTERED" {
+		return ErrInvalidToken
+	}
+
+	return fmt.Errorf("fcm returned status %d: %s", resp.StatusCode, fcmErr.Error.Message)
+}
@@ -0,0 +1,63 @@
+// Package ratelimit counts requests per key in fixed windows, for the
+// per-user and per-API key rate limits. The memory store counts what one
+// instance serves; the Redis store is shared by all instances. Both are
+// selected with RATE_LIMIT_STORE.
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Counter counts hits per key in fixed windows
+type Counter interface {
+	// Hit counts a hit on the key and returns the hits in the current
+	// window, this one included, and when the window ends
+	Hit(ctx context.Context, key string, window time.Duration) (int64, time.Time, error)
+}
+
+// window is the hits on a key until it resets
+type window struct {
+	hits  int64
+	reset time.Time
+}
+
+// Memory keeps the counters in memory, so each instance limits the requests
+// it serves
+type Memory struct {
+	mu        sync.Mutex
+	windows   map[string]*window
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+var _ Counter = (*Memory)(nil)
+
+func NewMemory() *Memory {
+	return &Memory{windows: make(map[string]*window), now: time.Now}
+}
+
+func (m *Memory) Hit(ctx context.Context, key string, length time.Duration) (int64, time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	// Forget ended windows now and then, so idle keys do not pile up
+	if now.Sub(m.lastSweep) > length {
+		for k, w := range m.windows {
+			if !now.Before(w.reset) {
+				delete(m.windows, k)
+			}
+		}
+		m.lastSweep = now
+	}
+
+	w, ok := m.windows[key]
+	if !ok || !now.Before(w.reset) {
+		w = &window{reset: now.Add(length)}
+		m.windows[key] = w
+	}
+	w.hits++
+	return w.hits, w.reset, nil
+}
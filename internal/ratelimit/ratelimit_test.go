@@ -0,0 +1,30 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryCountsPerKeyAndWindow(t *testing.T) {
+	now := time.Date(2026, time.October, 15, 9, 0, 0, 0, time.UTC)
+	counter := NewMemory()
+	counter.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for want := int64(1); want <= 3; want++ {
+		hits, reset, _ := counter.Hit(ctx, "user:1", time.Hour)
+		if hits != want || !reset.Equal(now.Add(time.Hour)) {
+			t.Fatalf("hit %d: got %d hits, reset at %v", want, hits, reset)
+		}
+	}
+	if hits, _, _ := counter.Hit(ctx, "user:2", time.Hour); hits != 1 {
+		t.Fatalf("expected keys to be counted apart, got %d hits", hits)
+	}
+
+	now = now.Add(time.Hour)
+	hits, reset, _ := counter.Hit(ctx, "user:1", time.Hour)
+	if hits != 1 || !reset.Equal(now.Add(time.Hour)) {
+		t.Fatalf("expected a new window, got %d hits, reset at %v", hits, reset)
+	}
+}
@@ -0,0 +1,39 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis keeps each window's counter under its key, expiring with the window
+type Redis struct {
+	client *redis.Client
+}
+
+var _ Counter = (*Redis)(nil)
+
+// NewRedis returns a counter on the client, which stays owned by the caller
+func NewRedis(client *redis.Client) *Redis {
+	return &Redis{client: client}
+}
+
+func (r *Redis) Hit(ctx context.Context, key string, window time.Duration) (int64, time.Time, error) {
+	key = "ratelimit:" + key
+
+	pipe := r.client.TxPipeline()
+	hits := pipe.Incr(ctx, key)
+	// The first hit starts the window
+	pipe.ExpireNX(ctx, key, window)
+	ttl := pipe.PTTL(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, time.Time{}, err
+	}
+
+	remaining := ttl.Val()
+	if remaining < 0 {
+		remaining = window
+	}
+	return hits.Val(), time.Now().Add(remaining), nil
+}
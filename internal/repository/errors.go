@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// IsUniqueViolation reports whether err was caused by a unique constraint or index
+func IsUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
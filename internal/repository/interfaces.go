@@ -0,0 +1,454 @@
+package repository
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"taxifleet/backend/internal/money"
+)
+
+//go:generate mockgen -source=interfaces.go -destination=mocks/mocks.go -package=mocks
+
+// Per-domain views of the Repository. Services depend on the interfaces they
+// need so they can be unit tested against the generated mocks.
+
+// Transactor runs multi-write flows atomically; see Repository.InTransaction
+type Transactor interface {
+	InTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type UserRepo interface {
+	CreateUser(ctx context.Context, user *User) error
+	GetUserByID(ctx context.Context, id uint) (*User, error)
+	GetUserByEmail(ctx context.Context, email string) (*User, error)
+	GetUserByPhone(ctx context.Context, phone string) (*User, error)
+	GetUserByPhoneNumber(ctx context.Context, phoneNumber string) (*User, error)
+	GetUserByEmailOrPhone(ctx context.Context, emailOrPhone string) (*User, error)
+	UpdateUser(ctx context.Context, user *User) error
+	GetAllUsers(ctx context.Context) ([]User, error)
+	GetUsersByTenant(ctx context.Context, tenantID uint) ([]User, error)
+	DeleteUser(ctx context.Context, id uint) error
+}
+
+type TenantRepo interface {
+	CreateTenant(ctx context.Context, tenant *Tenant) error
+	GetTenantByID(ctx context.Context, id uint) (*Tenant, error)
+	GetTenantBySubdomain(ctx context.Context, subdomain string) (*Tenant, error)
+	GetAllTenants(ctx context.Context) ([]Tenant, error)
+	UpdateTenant(ctx context.Context, tenant *Tenant) error
+	DeleteTenant(ctx context.Context, id uint) error
+	GetTenantUsage(ctx context.Context, tenantID uint) (*TenantUsage, error)
+	CountReportsByMonth(ctx context.Context, tenantID uint, from time.Time) ([]MonthlyCount, error)
+	CountTenantData(ctx context.Context, tenantID uint) (map[string]int64, error)
+	PurgeTenant(ctx context.Context, tenantID uint) (map[string]int64, error)
+}
+
+type BackupRepo interface {
+	DumpTenant(ctx context.Context, tenantID uint, w io.Writer) error
+}
+
+type RetentionRepo interface {
+	CountExpiredData(ctx context.Context, tenantID uint, cutoffs RetentionCutoffs) (map[string]int64, error)
+	PurgeExpiredData(ctx context.Context, tenantID uint, cutoffs RetentionCutoffs) (map[string]int64, error)
+}
+
+// PlanRepo manages subscription plans and what tenants use of them
+type PlanRepo interface {
+	CreatePlan(ctx context.Context, plan *Plan) error
+	GetPlanByID(ctx context.Context, id uint) (*Plan, error)
+	GetPlans(ctx context.Context) ([]Plan, error)
+	UpdatePlan(ctx context.Context, plan *Plan) error
+	DeletePlan(ctx context.Context, id uint) error
+	PlanInUse(ctx context.Context, id uint) (bool, error)
+	GetPlanUsage(ctx context.Context, tenantID uint, month time.Time) (*PlanUsage, error)
+	RecordExport(ctx context.Context, tenantID uint, month time.Time) error
+}
+
+type SystemSettingRepo interface {
+	GetSystemSettings(ctx context.Context) ([]SystemSetting, error)
+	SaveSystemSetting(ctx context.Context, setting *SystemSetting) error
+	DeleteSystemSetting(ctx context.Context, key string) error
+	CreateSystemSettingChange(ctx context.Context, change *SystemSettingChange) error
+	GetSystemSettingChanges(ctx context.Context, key string, limit int) ([]SystemSettingChange, error)
+}
+
+type PolicyRepo interface {
+	GetPolicies(ctx context.Context, tenantID uint) ([]Policy, error)
+	GetPolicyByID(ctx context.Context, id uint) (*Policy, error)
+	CreatePolicy(ctx context.Context, policy *Policy) error
+	UpdatePolicy(ctx context.Context, policy *Policy) error
+	DeletePolicy(ctx context.Context, id uint) error
+}
+
+type TaxiRepo interface {
+	CreateTaxi(ctx context.Context, taxi *Taxi) error
+	GetTaxiByID(ctx context.Context, id uint) (*Taxi, error)
+	GetTaxisByTenant(ctx context.Context, tenantID uint) ([]Taxi, error)
+	GetTaxiListVersion(ctx context.Context, tenantID uint) (*ListVersion, error)
+	CountTaxis(ctx context.Context, tenantID uint, status string) (int64, error)
+	SearchTaxis(ctx context.Context, tenantID uint, query string, limit int) ([]Taxi, error)
+	LicensePlateExists(ctx context.Context, tenantID uint, licensePlate string, excludeID uint) (bool, error)
+	UpdateTaxi(ctx context.Context, taxi *Taxi) error
+	DeleteTaxi(ctx context.Context, id uint) error
+}
+
+// TaxiLedgerRepo loads the cash movements of a taxi
+type TaxiLedgerRepo interface {
+	GetApprovedReportsByTaxi(ctx context.Context, taxiID uint) ([]WeeklyReport, error)
+	GetTaxiCashExpenses(ctx context.Context, taxiID uint) ([]Expense, error)
+	GetDepositsByTaxi(ctx context.Context, taxiID uint) ([]BankDeposit, error)
+}
+
+type ReportRepo interface {
+	CreateReport(ctx context.Context, report *WeeklyReport) error
+	GetReportByID(ctx context.Context, id uint) (*WeeklyReport, error)
+	GetReportsByTenant(ctx context.Context, tenantID uint) ([]WeeklyReport, error)
+	GetReportsByDriver(ctx context.Context, tenantID, driverID uint) ([]WeeklyReport, error)
+	GetReportsPage(ctx context.Context, tenantID, driverID uint, after *Keyset, limit int) ([]WeeklyReport, error)
+	GetArchivedReports(ctx context.Context, tenantID, driverID uint) ([]WeeklyReport, error)
+	ArchiveReports(ctx context.Context, tenantID uint, before time.Time) (int64, error)
+	GetReportListVersion(ctx context.Context, tenantID uint) (*ListVersion, error)
+	GetReportExportRows(ctx context.Context, tenantID, driverID uint) ([]ReportExportRow, error)
+	CountReports(ctx context.Context, tenantID, driverID uint, status string) (int64, error)
+	SummarizeReports(ctx context.Context, tenantID, driverID uint, groupBy string) ([]GroupTotal, error)
+	SearchReports(ctx context.Context, tenantID, driverID uint, query string, limit int) ([]WeeklyReport, error)
+	ReportExistsForWeek(ctx context.Context, tenantID, taxiID, driverID uint, weekStartDate time.Time, excludeID uint) (bool, error)
+	UpdateReport(ctx context.Context, report *WeeklyReport) error
+	DeleteReport(ctx context.Context, id uint) error
+	RecalculateReportExpenses(ctx context.Context, reportID uint) error
+}
+
+type ReportAttachmentRepo interface {
+	CreateReportAttachment(ctx context.Context, attachment *ReportAttachment) error
+	GetReportAttachmentByID(ctx context.Context, id uint) (*ReportAttachment, error)
+	GetReportAttachments(ctx context.Context, reportID uint) ([]ReportAttachment, error)
+	GetReportAttachmentsByTenant(ctx context.Context, tenantID uint) ([]ReportAttachment, error)
+	DeleteReportAttachment(ctx context.Context, id uint) error
+	GetPendingReportAttachments(ctx context.Context, limit int) ([]ReportAttachment, error)
+	UpdateReportAttachmentScan(ctx context.Context, attachment *ReportAttachment) error
+}
+
+type ExportBundleRepo interface {
+	CreateExportBundle(ctx context.Context, bundle *ExportBundle) error
+	GetExportBundleByID(ctx context.Context, id uint) (*ExportBundle, error)
+	UpdateExportBundle(ctx context.Context, bundle *ExportBundle) error
+	DeleteExportBundle(ctx context.Context, id uint) error
+	GetExpiredExportBundles(ctx context.Context, before time.Time) ([]ExportBundle, error)
+	GetPeriodReportAttachments(ctx context.Context, tenantID uint, from, to time.Time) ([]ReportAttachment, error)
+}
+
+type ReportSignatureRepo interface {
+	CreateReportSignature(ctx context.Context, signature *ReportSignature) error
+	GetReportSignature(ctx context.Context, reportID uint) (*ReportSignature, error)
+}
+
+type DelegationRepo interface {
+	CreateDelegation(ctx context.Context, delegation *ApprovalDelegation) error
+	GetDelegationByID(ctx context.Context, id uint) (*ApprovalDelegation, error)
+	GetDelegations(ctx context.Context, tenantID uint) ([]ApprovalDelegation, error)
+	RevokeDelegation(ctx context.Context, id uint) error
+	HasActiveDelegation(ctx context.Context, tenantID, delegateID uint, day time.Time) (bool, error)
+}
+
+type ReportRejectionRepo interface {
+	CreateReportRejection(ctx context.Context, rejection *ReportRejection) error
+}
+
+type ReportAdjustmentRepo interface {
+	CreateReportAdjustment(ctx context.Context, adjustment *ReportAdjustment) error
+	GetReportAdjustmentByID(ctx context.Context, id uint) (*ReportAdjustment, error)
+	DeleteReportAdjustment(ctx context.Context, id uint) error
+	RecalculateReportAdjustments(ctx context.Context, reportID uint) error
+}
+
+type DriverLedgerRepo interface {
+	CreateLedgerEntry(ctx context.Context, entry *DriverLedgerEntry) error
+	GetLedgerEntries(ctx context.Context, driverID uint) ([]DriverLedgerEntry, error)
+	GetDriverBalance(ctx context.Context, driverID uint) (money.Amount, error)
+	GetDriverBalances(ctx context.Context, tenantID uint) ([]DriverBalance, error)
+}
+
+type TripRepo interface {
+	CreateTrips(ctx context.Context, trips []Trip) error
+	GetTrips(ctx context.Context, tenantID uint, driverID uint, from, to time.Time) ([]Trip, error)
+	SumTrips(ctx context.Context, taxiID, driverID uint, from, to time.Time) (*TripTotals, error)
+}
+
+type PlatformEarningRepo interface {
+	SavePlatformEarnings(ctx context.Context, earnings []PlatformEarning) error
+	GetPlatformEarnings(ctx context.Context, tenantID uint, from, to time.Time) ([]PlatformEarning, error)
+	GetReportsInPeriod(ctx context.Context, tenantID uint, from, to time.Time) ([]WeeklyReport, error)
+}
+
+type FuelCardRepo interface {
+	CreateFuelCard(ctx context.Context, card *FuelCard) error
+	GetFuelCardByID(ctx context.Context, id uint) (*FuelCard, error)
+	GetFuelCardsByTenant(ctx context.Context, tenantID uint) ([]FuelCard, error)
+	GetFuelCardsByNumbers(ctx context.Context, numbers []string) ([]FuelCard, error)
+	UpdateFuelCard(ctx context.Context, card *FuelCard) error
+	DeleteFuelCard(ctx context.Context, id uint) error
+	CreateFuelCardTransaction(ctx context.Context, transaction *FuelCardTransaction) error
+	GetSyncedTransactionIDs(ctx context.Context, externalIDs []string) ([]string, error)
+	GetLatestTransactionTime(ctx context.Context) (*time.Time, error)
+	GetFuelCardTransactions(ctx context.Context, tenantID uint, flagged bool, limit int) ([]FuelCardTransaction, error)
+}
+
+type GeofenceRepo interface {
+	CreateGeofence(ctx context.Context, geofence *Geofence) error
+	GetGeofenceByID(ctx context.Context, id uint) (*Geofence, error)
+	GetGeofencesByTenant(ctx context.Context, tenantID uint) ([]Geofence, error)
+	UpdateGeofence(ctx context.Context, geofence *Geofence) error
+	DeleteGeofence(ctx context.Context, id uint) error
+}
+
+type PositionRepo interface {
+	CreatePositions(ctx context.Context, positions []TaxiPosition) error
+	GetLatestPosition(ctx context.Context, taxiID uint) (*TaxiPosition, error)
+	GetLatestPositions(ctx context.Context, tenantID uint) ([]TaxiPosition, error)
+}
+
+type SyncRepo interface {
+	GetReportByClientID(ctx context.Context, tenantID uint, clientID string) (*WeeklyReport, error)
+	GetExpenseByClientID(ctx context.Context, tenantID uint, clientID string) (*Expense, error)
+	GetReportsChangedSince(ctx context.Context, driverID uint, since time.Time) ([]WeeklyReport, error)
+	GetExpensesChangedSince(ctx context.Context, userID uint, since time.Time) ([]Expense, error)
+}
+
+type FineRepo interface {
+	CreateFine(ctx context.Context, fine *Fine) error
+	GetFineByID(ctx context.Context, id uint) (*Fine, error)
+	GetFinesByTenant(ctx context.Context, tenantID uint) ([]Fine, error)
+	UpdateFine(ctx context.Context, fine *Fine) error
+	DeleteFine(ctx context.Context, id uint) error
+}
+
+type ExpenseRepo interface {
+	CreateExpense(ctx context.Context, expense *Expense) error
+	GetExpenseByID(ctx context.Context, id uint) (*Expense, error)
+	GetExpensesByTenant(ctx context.Context, tenantID uint) ([]Expense, error)
+	GetPendingExpenses(ctx context.Context, tenantID uint) ([]Expense, error)
+	GetExpensesPage(ctx context.Context, tenantID uint, after *Keyset, limit int) ([]Expense, error)
+	GetExpenseExportRows(ctx context.Context, tenantID uint) ([]ExpenseExportRow, error)
+	SummarizeExpenses(ctx context.Context, tenantID uint, groupBy string) ([]GroupTotal, error)
+	SearchExpenses(ctx context.Context, tenantID uint, query string, limit int) ([]Expense, error)
+	GetExpensesByReport(ctx context.Context, reportID uint) ([]Expense, error)
+	UpdateExpense(ctx context.Context, expense *Expense) error
+	DeleteExpense(ctx context.Context, id uint) error
+}
+
+type DepositRepo interface {
+	CreateDeposit(ctx context.Context, deposit *BankDeposit) error
+	GetDepositByID(ctx context.Context, id uint) (*BankDeposit, error)
+	GetDepositsByTenant(ctx context.Context, tenantID uint) ([]BankDeposit, error)
+	UpdateDeposit(ctx context.Context, deposit *BankDeposit) error
+	DeleteDeposit(ctx context.Context, id uint) error
+}
+
+type BudgetRepo interface {
+	CreateBudget(ctx context.Context, budget *Budget) error
+	GetBudgetByID(ctx context.Context, id uint) (*Budget, error)
+	GetBudgets(ctx context.Context, tenantID uint) ([]Budget, error)
+	UpdateBudget(ctx context.Context, budget *Budget) error
+	DeleteBudget(ctx context.Context, id uint) error
+	GetBudgetSpending(ctx context.Context, tenantID uint, from, to time.Time) (map[uint]money.Amount, error)
+}
+
+type PeriodRepo interface {
+	CreateClosedPeriod(ctx context.Context, period *ClosedPeriod) error
+	GetClosedPeriod(ctx context.Context, tenantID uint, month time.Time) (*ClosedPeriod, error)
+	GetClosedPeriods(ctx context.Context, tenantID uint) ([]ClosedPeriod, error)
+	DeleteClosedPeriod(ctx context.Context, id uint) error
+	IsPeriodClosed(ctx context.Context, tenantID uint, day time.Time) (bool, error)
+}
+
+type BankAccountRepo interface {
+	CreateBankAccount(ctx context.Context, account *BankAccount) error
+	GetBankAccountByID(ctx context.Context, id uint) (*BankAccount, error)
+	GetBankAccountsByTenant(ctx context.Context, tenantID uint) ([]BankAccount, error)
+	UpdateBankAccount(ctx context.Context, account *BankAccount) error
+	DeleteBankAccount(ctx context.Context, id uint) error
+	BankAccountInUse(ctx context.Context, id uint) (bool, error)
+	GetDepositTotalsByAccount(ctx context.Context, tenantID uint) ([]BankAccountTotal, error)
+}
+
+type SessionRepo interface {
+	CreateSession(ctx context.Context, session *Session) error
+	GetSessionByToken(ctx context.Context, token string) (*Session, error)
+	DeleteSession(ctx context.Context, token string) error
+	DeleteUserSessions(ctx context.Context, userID uint) error
+	DeleteStaleSessions(ctx context.Context, now time.Time) (int64, error)
+}
+
+type DeviceTokenRepo interface {
+	SaveDeviceToken(ctx context.Context, device *DeviceToken) error
+	GetDeviceTokensByUser(ctx context.Context, userID uint) ([]DeviceToken, error)
+	DeleteUserDeviceToken(ctx context.Context, userID uint, token string) error
+	DeleteDeviceToken(ctx context.Context, token string) error
+}
+
+type MaintenanceRepo interface {
+	CreateMaintenanceSchedule(ctx context.Context, schedule *MaintenanceSchedule) error
+	GetMaintenanceScheduleByID(ctx context.Context, id uint) (*MaintenanceSchedule, error)
+	GetMaintenanceSchedulesByTenant(ctx context.Context, tenantID uint) ([]MaintenanceSchedule, error)
+	GetAllMaintenanceSchedules(ctx context.Context) ([]MaintenanceSchedule, error)
+	UpdateMaintenanceSchedule(ctx context.Context, schedule *MaintenanceSchedule) error
+	DeleteMaintenanceSchedule(ctx context.Context, id uint) error
+	CreateMaintenanceLog(ctx context.Context, log *MaintenanceLog) error
+	GetMaintenanceLogByID(ctx context.Context, id uint) (*MaintenanceLog, error)
+	UpdateMaintenanceLog(ctx context.Context, log *MaintenanceLog) error
+	AddMaintenanceLogCost(ctx context.Context, id uint, amount money.Amount) error
+	GetMaintenanceLogsByTenant(ctx context.Context, tenantID uint) ([]MaintenanceLog, error)
+	GetMaintenanceLogsByTaxi(ctx context.Context, taxiID uint) ([]MaintenanceLog, error)
+}
+
+type InventoryRepo interface {
+	CreatePart(ctx context.Context, part *Part) error
+	GetPartByID(ctx context.Context, id uint) (*Part, error)
+	GetPartsByTenant(ctx context.Context, tenantID uint) ([]Part, error)
+	GetLowStockParts(ctx context.Context, tenantID uint) ([]Part, error)
+	UpdatePart(ctx context.Context, part *Part) error
+	DeletePart(ctx context.Context, id uint) error
+	CreateStockMovements(ctx context.Context, movements []StockMovement) ([]Part, error)
+	GetStockMovementsByPart(ctx context.Context, partID uint) ([]StockMovement, error)
+	GetStockMovementsByMaintenanceLog(ctx context.Context, logID uint) ([]StockMovement, error)
+}
+
+type AssignmentRepo interface {
+	RecordAssignment(ctx context.Context, tenantID, taxiID uint, driverID *uint, assignedByID *uint, at time.Time) error
+	GetAssignmentsByTaxi(ctx context.Context, taxiID uint) ([]Assignment, error)
+	GetAssignmentAt(ctx context.Context, taxiID uint, at time.Time) (*Assignment, error)
+}
+
+type AnalyticsRepo interface {
+	GetDriverPerformance(ctx context.Context, tenantID uint, from, to time.Time) ([]DriverPerformance, error)
+	GetDashboardTotals(ctx context.Context, tenantID uint) (*DashboardTotals, error)
+	GetTaxiProfitability(ctx context.Context, tenantID uint, from, to time.Time) ([]TaxiProfitability, error)
+	SumApprovedEarnings(ctx context.Context, tenantID uint, from, to time.Time) (money.Amount, error)
+	SumExpensesByCategory(ctx context.Context, tenantID uint, from, to time.Time) ([]CategoryTotal, error)
+	SumDeposits(ctx context.Context, tenantID uint, from, to time.Time) (money.Amount, int, error)
+	SumMaintenanceCosts(ctx context.Context, tenantID uint, from, to time.Time) (money.Amount, error)
+	GetDailyApprovedEarnings(ctx context.Context, tenantID uint, from, to time.Time) ([]DailyAmount, error)
+	GetDailyExpenses(ctx context.Context, tenantID uint, from, to time.Time) ([]DailyAmount, error)
+	GetApprovedReportsInPeriod(ctx context.Context, tenantID uint, from, to time.Time) ([]WeeklyReport, error)
+	GetExpensesInPeriod(ctx context.Context, tenantID uint, from, to time.Time) ([]Expense, error)
+	GetDepositsInPeriod(ctx context.Context, tenantID uint, from, to time.Time) ([]BankDeposit, error)
+}
+
+type JobRepo interface {
+	WithJobLock(ctx context.Context, name string, fn func() error) (bool, error)
+	CreateJobRun(ctx context.Context, run *JobRun) error
+	UpdateJobRun(ctx context.Context, run *JobRun) error
+	GetJobRuns(ctx context.Context, jobName string, limit int) ([]JobRun, error)
+}
+
+type QueryStatsRepo interface {
+	QueryStatsEnabled(ctx context.Context) (bool, error)
+	GetSlowQueries(ctx context.Context, limit int) ([]QueryStat, error)
+}
+
+type IdempotencyRepo interface {
+	CreateIdempotencyKey(ctx context.Context, record *IdempotencyKey) error
+	GetIdempotencyKey(ctx context.Context, userID uint, key string) (*IdempotencyKey, error)
+	UpdateIdempotencyKey(ctx context.Context, record *IdempotencyKey) error
+	DeleteIdempotencyKey(ctx context.Context, id uint) error
+	DeleteIdempotencyKeysBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type APIKeyRepo interface {
+	CreateAPIKey(ctx context.Context, key *APIKey) error
+	GetAPIKeyByID(ctx context.Context, id uint) (*APIKey, error)
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (*APIKey, error)
+	GetAPIKeys(ctx context.Context, tenantID uint) ([]APIKey, error)
+	UpdateAPIKey(ctx context.Context, key *APIKey) error
+	TouchAPIKey(ctx context.Context, id uint, usedAt time.Time) error
+}
+
+type OAuthIdentityRepo interface {
+	GetOAuthIdentity(ctx context.Context, provider, subject string) (*OAuthIdentity, error)
+	CreateOAuthIdentity(ctx context.Context, identity *OAuthIdentity) error
+}
+
+type LoginAuditRepo interface {
+	CreateLoginAudit(ctx context.Context, audit *LoginAudit) error
+	GetLoginAudits(ctx context.Context, userID uint, limit int) ([]LoginAudit, error)
+	HasLoginFromDevice(ctx context.Context, userID uint, fingerprint string) (known bool, anyLogin bool, err error)
+}
+
+type InboxRepo interface {
+	CreateNotification(ctx context.Context, notification *Notification) error
+	GetNotifications(ctx context.Context, userID uint, unreadOnly bool, limit int) ([]Notification, error)
+	CountUnreadNotifications(ctx context.Context, userID uint) (int64, error)
+	MarkNotificationRead(ctx context.Context, userID, id uint) (bool, error)
+	MarkAllNotificationsRead(ctx context.Context, userID uint) (int64, error)
+}
+
+type DigestRepo interface {
+	GetReportsForWeek(ctx context.Context, tenantID uint, weekStart time.Time) ([]WeeklyReport, error)
+	CountSubmittedReports(ctx context.Context, tenantID uint) (int64, error)
+	SetWeeklyDigest(ctx context.Context, userID uint, enabled bool) error
+}
+
+type AuditRepo interface {
+	CreateAuditEvent(ctx context.Context, event *AuditEvent) error
+	GetAuditEvents(ctx context.Context, tenantID uint, beforeID uint, limit int) ([]AuditEvent, error)
+}
+
+// DemoRepo bulk-inserts the synthetic data of demo tenants
+type DemoRepo interface {
+	CreateDemoUsers(ctx context.Context, users []User) error
+	CreateDemoTaxis(ctx context.Context, taxis []Taxi) error
+	CreateDemoReports(ctx context.Context, reports []WeeklyReport) error
+	CreateDemoExpenses(ctx context.Context, expenses []Expense) error
+	CreateDemoDeposits(ctx context.Context, deposits []BankDeposit) error
+}
+
+// Repository implements every domain interface
+var (
+	_ Transactor           = (*Repository)(nil)
+	_ UserRepo             = (*Repository)(nil)
+	_ TenantRepo           = (*Repository)(nil)
+	_ BackupRepo           = (*Repository)(nil)
+	_ RetentionRepo        = (*Repository)(nil)
+	_ PlanRepo             = (*Repository)(nil)
+	_ SystemSettingRepo    = (*Repository)(nil)
+	_ PolicyRepo           = (*Repository)(nil)
+	_ TaxiRepo             = (*Repository)(nil)
+	_ TaxiLedgerRepo       = (*Repository)(nil)
+	_ ReportRepo           = (*Repository)(nil)
+	_ ReportAttachmentRepo = (*Repository)(nil)
+	_ ReportAdjustmentRepo = (*Repository)(nil)
+	_ ReportRejectionRepo  = (*Repository)(nil)
+	_ ReportSignatureRepo  = (*Repository)(nil)
+	_ ExportBundleRepo     = (*Repository)(nil)
+	_ DelegationRepo       = (*Repository)(nil)
+	_ DriverLedgerRepo     = (*Repository)(nil)
+	_ FineRepo             = (*Repository)(nil)
+	_ FuelCardRepo         = (*Repository)(nil)
+	_ GeofenceRepo         = (*Repository)(nil)
+	_ PositionRepo         = (*Repository)(nil)
+	_ SyncRepo             = (*Repository)(nil)
+	_ TripRepo             = (*Repository)(nil)
+	_ PlatformEarningRepo  = (*Repository)(nil)
+	_ ExpenseRepo          = (*Repository)(nil)
+	_ DepositRepo          = (*Repository)(nil)
+	_ BankAccountRepo      = (*Repository)(nil)
+	_ PeriodRepo           = (*Repository)(nil)
+	_ BudgetRepo           = (*Repository)(nil)
+	_ SessionRepo          = (*Repository)(nil)
+	_ DeviceTokenRepo      = (*Repository)(nil)
+	_ MaintenanceRepo      = (*Repository)(nil)
+	_ InventoryRepo        = (*Repository)(nil)
+	_ AssignmentRepo       = (*Repository)(nil)
+	_ AnalyticsRepo        = (*Repository)(nil)
+	_ JobRepo              = (*Repository)(nil)
+	_ QueryStatsRepo       = (*Repository)(nil)
+	_ IdempotencyRepo      = (*Repository)(nil)
+	_ APIKeyRepo           = (*Repository)(nil)
+	_ OAuthIdentityRepo    = (*Repository)(nil)
+	_ LoginAuditRepo       = (*Repository)(nil)
+	_ DigestRepo           = (*Repository)(nil)
+	_ InboxRepo            = (*Repository)(nil)
+	_ AuditRepo            = (*Repository)(nil)
+	_ DemoRepo             = (*Repository)(nil)
+)
@@ -0,0 +1,4747 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source=interfaces.go -destination=mocks/mocks.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+	money "taxifleet/backend/internal/money"
+	repository "taxifleet/backend/internal/repository"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTransactor is a mock of Transactor interface.
+type MockTransactor struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactorMockRecorder
+	isgomock struct{}
+}
+
+// MockTransactorMockRecorder is the mock recorder for MockTransactor.
+type MockTransactorMockRecorder struct {
+	mock *MockTransactor
+}
+
+// NewMockTransactor creates a new mock instance.
+func NewMockTransactor(ctrl *gomock.Controller) *MockTransactor {
+	mock := &MockTransactor{ctrl: ctrl}
+	mock.recorder = &MockTransactorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactor) EXPECT() *MockTransactorMockRecorder {
+	return m.recorder
+}
+
+// InTransaction mocks base method.
+func (m *MockTransactor) InTransaction(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InTransaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InTransaction indicates an expected call of InTransaction.
+func (mr *MockTransactorMockRecorder) InTransaction(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InTransaction", reflect.TypeOf((*MockTransactor)(nil).InTransaction), ctx, fn)
+}
+
+// MockUserRepo is a mock of UserRepo interface.
+type MockUserRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockUserRepoMockRecorder is the mock recorder for MockUserRepo.
+type MockUserRepoMockRecorder struct {
+	mock *MockUserRepo
+}
+
+// NewMockUserRepo creates a new mock instance.
+func NewMockUserRepo(ctrl *gomock.Controller) *MockUserRepo {
+	mock := &MockUserRepo{ctrl: ctrl}
+	mock.recorder = &MockUserRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserRepo) EXPECT() *MockUserRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateUser mocks base method.
+func (m *MockUserRepo) CreateUser(ctx context.Context, user *repository.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockUserRepoMockRecorder) CreateUser(ctx, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUserRepo)(nil).CreateUser), ctx, user)
+}
+
+// DeleteUser mocks base method.
+func (m *MockUserRepo) DeleteUser(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUser", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUser indicates an expected call of DeleteUser.
+func (mr *MockUserRepoMockRecorder) DeleteUser(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockUserRepo)(nil).DeleteUser), ctx, id)
+}
+
+// GetAllUsers mocks base method.
+func (m *MockUserRepo) GetAllUsers(ctx context.Context) ([]repository.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllUsers", ctx)
+	ret0, _ := ret[0].([]repository.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllUsers indicates an expected call of GetAllUsers.
+func (mr *MockUserRepoMockRecorder) GetAllUsers(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllUsers", reflect.TypeOf((*MockUserRepo)(nil).GetAllUsers), ctx)
+}
+
+// GetUserByEmail mocks base method.
+func (m *MockUserRepo) GetUserByEmail(ctx context.Context, email string) (*repository.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByEmail", ctx, email)
+	ret0, _ := ret[0].(*repository.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByEmail indicates an expected call of GetUserByEmail.
+func (mr *MockUserRepoMockRecorder) GetUserByEmail(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockUserRepo)(nil).GetUserByEmail), ctx, email)
+}
+
+// GetUserByEmailOrPhone mocks base method.
+func (m *MockUserRepo) GetUserByEmailOrPhone(ctx context.Context, emailOrPhone string) (*repository.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByEmailOrPhone", ctx, emailOrPhone)
+	ret0, _ := ret[0].(*repository.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByEmailOrPhone indicates an expected call of GetUserByEmailOrPhone.
+func (mr *MockUserRepoMockRecorder) GetUserByEmailOrPhone(ctx, emailOrPhone any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmailOrPhone", reflect.TypeOf((*MockUserRepo)(nil).GetUserByEmailOrPhone), ctx, emailOrPhone)
+}
+
+// GetUserByID mocks base method.
+func (m *MockUserRepo) GetUserByID(ctx context.Context, id uint) (*repository.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByID", ctx, id)
+	ret0, _ := ret[0].(*repository.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByID indicates an expected call of GetUserByID.
+func (mr *MockUserRepoMockRecorder) GetUserByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockUserRepo)(nil).GetUserByID), ctx, id)
+}
+
+// GetUserByPhone mocks base method.
+func (m *MockUserRepo) GetUserByPhone(ctx context.Context, phone string) (*repository.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByPhone", ctx, phone)
+	ret0, _ := ret[0].(*repository.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByPhone indicates an expected call of GetUserByPhone.
+func (mr *MockUserRepoMockRecorder) GetUserByPhone(ctx, phone any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByPhone", reflect.TypeOf((*MockUserRepo)(nil).GetUserByPhone), ctx, phone)
+}
+
+// GetUserByPhoneNumber mocks base method.
+func (m *MockUserRepo) GetUserByPhoneNumber(ctx context.Context, phoneNumber string) (*repository.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByPhoneNumber", ctx, phoneNumber)
+	ret0, _ := ret[0].(*repository.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByPhoneNumber indicates an expected call of GetUserByPhoneNumber.
+func (mr *MockUserRepoMockRecorder) GetUserByPhoneNumber(ctx, phoneNumber any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByPhoneNumber", reflect.TypeOf((*MockUserRepo)(nil).GetUserByPhoneNumber), ctx, phoneNumber)
+}
+
+// GetUsersByTenant mocks base method.
+func (m *MockUserRepo) GetUsersByTenant(ctx context.Context, tenantID uint) ([]repository.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsersByTenant", ctx, tenantID)
+	ret0, _ := ret[0].([]repository.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsersByTenant indicates an expected call of GetUsersByTenant.
+func (mr *MockUserRepoMockRecorder) GetUsersByTenant(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersByTenant", reflect.TypeOf((*MockUserRepo)(nil).GetUsersByTenant), ctx, tenantID)
+}
+
+// UpdateUser mocks base method.
+func (m *MockUserRepo) UpdateUser(ctx context.Context, user *repository.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUser", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUser indicates an expected call of UpdateUser.
+func (mr *MockUserRepoMockRecorder) UpdateUser(ctx, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUserRepo)(nil).UpdateUser), ctx, user)
+}
+
+// MockTenantRepo is a mock of TenantRepo interface.
+type MockTenantRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockTenantRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockTenantRepoMockRecorder is the mock recorder for MockTenantRepo.
+type MockTenantRepoMockRecorder struct {
+	mock *MockTenantRepo
+}
+
+// NewMockTenantRepo creates a new mock instance.
+func NewMockTenantRepo(ctrl *gomock.Controller) *MockTenantRepo {
+	mock := &MockTenantRepo{ctrl: ctrl}
+	mock.recorder = &MockTenantRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTenantRepo) EXPECT() *MockTenantRepoMockRecorder {
+	return m.recorder
+}
+
+// CountReportsByMonth mocks base method.
+func (m *MockTenantRepo) CountReportsByMonth(ctx context.Context, tenantID uint, from time.Time) ([]repository.MonthlyCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountReportsByMonth", ctx, tenantID, from)
+	ret0, _ := ret[0].([]repository.MonthlyCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountReportsByMonth indicates an expected call of CountReportsByMonth.
+func (mr *MockTenantRepoMockRecorder) CountReportsByMonth(ctx, tenantID, from any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountReportsByMonth", reflect.TypeOf((*MockTenantRepo)(nil).CountReportsByMonth), ctx, tenantID, from)
+}
+
+// CountTenantData mocks base method.
+func (m *MockTenantRepo) CountTenantData(ctx context.Context, tenantID uint) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountTenantData", ctx, tenantID)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountTenantData indicates an expected call of CountTenantData.
+func (mr *MockTenantRepoMockRecorder) CountTenantData(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTenantData", reflect.TypeOf((*MockTenantRepo)(nil).CountTenantData), ctx, tenantID)
+}
+
+// CreateTenant mocks base method.
+func (m *MockTenantRepo) CreateTenant(ctx context.Context, tenant *repository.Tenant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTenant", ctx, tenant)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTenant indicates an expected call of CreateTenant.
+func (mr *MockTenantRepoMockRecorder) CreateTenant(ctx, tenant any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTenant", reflect.TypeOf((*MockTenantRepo)(nil).CreateTenant), ctx, tenant)
+}
+
+// DeleteTenant mocks base method.
+func (m *MockTenantRepo) DeleteTenant(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTenant", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTenant indicates an expected call of DeleteTenant.
+func (mr *MockTenantRepoMockRecorder) DeleteTenant(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTenant", reflect.TypeOf((*MockTenantRepo)(nil).DeleteTenant), ctx, id)
+}
+
+// GetAllTenants mocks base method.
+func (m *MockTenantRepo) GetAllTenants(ctx context.Context) ([]repository.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllTenants", ctx)
+	ret0, _ := ret[0].([]repository.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllTenants indicates an expected call of GetAllTenants.
+func (mr *MockTenantRepoMockRecorder) GetAllTenants(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTenants", reflect.TypeOf((*MockTenantRepo)(nil).GetAllTenants), ctx)
+}
+
+// GetTenantByID mocks base method.
+func (m *MockTenantRepo) GetTenantByID(ctx context.Context, id uint) (*repository.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTenantByID", ctx, id)
+	ret0, _ := ret[0].(*repository.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTenantByID indicates an expected call of GetTenantByID.
+func (mr *MockTenantRepoMockRecorder) GetTenantByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTenantByID", reflect.TypeOf((*MockTenantRepo)(nil).GetTenantByID), ctx, id)
+}
+
+// GetTenantBySubdomain mocks base method.
+func (m *MockTenantRepo) GetTenantBySubdomain(ctx context.Context, subdomain string) (*repository.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTenantBySubdomain", ctx, subdomain)
+	ret0, _ := ret[0].(*repository.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTenantBySubdomain indicates an expected call of GetTenantBySubdomain.
+func (mr *MockTenantRepoMockRecorder) GetTenantBySubdomain(ctx, subdomain any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTenantBySubdomain", reflect.TypeOf((*MockTenantRepo)(nil).GetTenantBySubdomain), ctx, subdomain)
+}
+
+// GetTenantUsage mocks base method.
+func (m *MockTenantRepo) GetTenantUsage(ctx context.Context, tenantID uint) (*repository.TenantUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTenantUsage", ctx, tenantID)
+	ret0, _ := ret[0].(*repository.TenantUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTenantUsage indicates an expected call of GetTenantUsage.
+func (mr *MockTenantRepoMockRecorder) GetTenantUsage(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTenantUsage", reflect.TypeOf((*MockTenantRepo)(nil).GetTenantUsage), ctx, tenantID)
+}
+
+// PurgeTenant mocks base method.
+func (m *MockTenantRepo) PurgeTenant(ctx context.Context, tenantID uint) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTenant", ctx, tenantID)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeTenant indicates an expected call of PurgeTenant.
+func (mr *MockTenantRepoMockRecorder) PurgeTenant(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTenant", reflect.TypeOf((*MockTenantRepo)(nil).PurgeTenant), ctx, tenantID)
+}
+
+// UpdateTenant mocks base method.
+func (m *MockTenantRepo) UpdateTenant(ctx context.Context, tenant *repository.Tenant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTenant", ctx, tenant)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTenant indicates an expected call of UpdateTenant.
+func (mr *MockTenantRepoMockRecorder) UpdateTenant(ctx, tenant any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTenant", reflect.TypeOf((*MockTenantRepo)(nil).UpdateTenant), ctx, tenant)
+}
+
+// MockBackupRepo is a mock of BackupRepo interface.
+type MockBackupRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockBackupRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockBackupRepoMockRecorder is the mock recorder for MockBackupRepo.
+type MockBackupRepoMockRecorder struct {
+	mock *MockBackupRepo
+}
+
+// NewMockBackupRepo creates a new mock instance.
+func NewMockBackupRepo(ctrl *gomock.Controller) *MockBackupRepo {
+	mock := &MockBackupRepo{ctrl: ctrl}
+	mock.recorder = &MockBackupRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBackupRepo) EXPECT() *MockBackupRepoMockRecorder {
+	return m.recorder
+}
+
+// DumpTenant mocks base method.
+func (m *MockBackupRepo) DumpTenant(ctx context.Context, tenantID uint, w io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DumpTenant", ctx, tenantID, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DumpTenant indicates an expected call of DumpTenant.
+func (mr *MockBackupRepoMockRecorder) DumpTenant(ctx, tenantID, w any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DumpTenant", reflect.TypeOf((*MockBackupRepo)(nil).DumpTenant), ctx, tenantID, w)
+}
+
+// MockRetentionRepo is a mock of RetentionRepo interface.
+type MockRetentionRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockRetentionRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockRetentionRepoMockRecorder is the mock recorder for MockRetentionRepo.
+type MockRetentionRepoMockRecorder struct {
+	mock *MockRetentionRepo
+}
+
+// NewMockRetentionRepo creates a new mock instance.
+func NewMockRetentionRepo(ctrl *gomock.Controller) *MockRetentionRepo {
+	mock := &MockRetentionRepo{ctrl: ctrl}
+	mock.recorder = &MockRetentionRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRetentionRepo) EXPECT() *MockRetentionRepoMockRecorder {
+	return m.recorder
+}
+
+// CountExpiredData mocks base method.
+func (m *MockRetentionRepo) CountExpiredData(ctx context.Context, tenantID uint, cutoffs repository.RetentionCutoffs) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountExpiredData", ctx, tenantID, cutoffs)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountExpiredData indicates an expected call of CountExpiredData.
+func (mr *MockRetentionRepoMockRecorder) CountExpiredData(ctx, tenantID, cutoffs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountExpiredData", reflect.TypeOf((*MockRetentionRepo)(nil).CountExpiredData), ctx, tenantID, cutoffs)
+}
+
+// PurgeExpiredData mocks base method.
+func (m *MockRetentionRepo) PurgeExpiredData(ctx context.Context, tenantID uint, cutoffs repository.RetentionCutoffs) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpiredData", ctx, tenantID, cutoffs)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpiredData indicates an expected call of PurgeExpiredData.
+func (mr *MockRetentionRepoMockRecorder) PurgeExpiredData(ctx, tenantID, cutoffs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpiredData", reflect.TypeOf((*MockRetentionRepo)(nil).PurgeExpiredData), ctx, tenantID, cutoffs)
+}
+
+// MockPlanRepo is a mock of PlanRepo interface.
+type MockPlanRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockPlanRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockPlanRepoMockRecorder is the mock recorder for MockPlanRepo.
+type MockPlanRepoMockRecorder struct {
+	mock *MockPlanRepo
+}
+
+// NewMockPlanRepo creates a new mock instance.
+func NewMockPlanRepo(ctrl *gomock.Controller) *MockPlanRepo {
+	mock := &MockPlanRepo{ctrl: ctrl}
+	mock.recorder = &MockPlanRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPlanRepo) EXPECT() *MockPlanRepoMockRecorder {
+	return m.recorder
+}
+
+// CreatePlan mocks base method.
+func (m *MockPlanRepo) CreatePlan(ctx context.Context, plan *repository.Plan) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePlan", ctx, plan)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePlan indicates an expected call of CreatePlan.
+func (mr *MockPlanRepoMockRecorder) CreatePlan(ctx, plan any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePlan", reflect.TypeOf((*MockPlanRepo)(nil).CreatePlan), ctx, plan)
+}
+
+// DeletePlan mocks base method.
+func (m *MockPlanRepo) DeletePlan(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePlan", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePlan indicates an expected call of DeletePlan.
+func (mr *MockPlanRepoMockRecorder) DeletePlan(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePlan", reflect.TypeOf((*MockPlanRepo)(nil).DeletePlan), ctx, id)
+}
+
+// GetPlanByID mocks base method.
+func (m *MockPlanRepo) GetPlanByID(ctx context.Context, id uint) (*repository.Plan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPlanByID", ctx, id)
+	ret0, _ := ret[0].(*repository.Plan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPlanByID indicates an expected call of GetPlanByID.
+func (mr *MockPlanRepoMockRecorder) GetPlanByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlanByID", reflect.TypeOf((*MockPlanRepo)(nil).GetPlanByID), ctx, id)
+}
+
+// GetPlanUsage mocks base method.
+func (m *MockPlanRepo) GetPlanUsage(ctx context.Context, tenantID uint, month time.Time) (*repository.PlanUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPlanUsage", ctx, tenantID, month)
+	ret0, _ := ret[0].(*repository.PlanUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPlanUsage indicates an expected call of GetPlanUsage.
+func (mr *MockPlanRepoMockRecorder) GetPlanUsage(ctx, tenantID, month any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlanUsage", reflect.TypeOf((*MockPlanRepo)(nil).GetPlanUsage), ctx, tenantID, month)
+}
+
+// GetPlans mocks base method.
+func (m *MockPlanRepo) GetPlans(ctx context.Context) ([]repository.Plan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPlans", ctx)
+	ret0, _ := ret[0].([]repository.Plan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPlans indicates an expected call of GetPlans.
+func (mr *MockPlanRepoMockRecorder) GetPlans(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlans", reflect.TypeOf((*MockPlanRepo)(nil).GetPlans), ctx)
+}
+
+// PlanInUse mocks base method.
+func (m *MockPlanRepo) PlanInUse(ctx context.Context, id uint) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlanInUse", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlanInUse indicates an expected call of PlanInUse.
+func (mr *MockPlanRepoMockRecorder) PlanInUse(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlanInUse", reflect.TypeOf((*MockPlanRepo)(nil).PlanInUse), ctx, id)
+}
+
+// RecordExport mocks base method.
+func (m *MockPlanRepo) RecordExport(ctx context.Context, tenantID uint, month time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordExport", ctx, tenantID, month)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordExport indicates an expected call of RecordExport.
+func (mr *MockPlanRepoMockRecorder) RecordExport(ctx, tenantID, month any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordExport", reflect.TypeOf((*MockPlanRepo)(nil).RecordExport), ctx, tenantID, month)
+}
+
+// UpdatePlan mocks base method.
+func (m *MockPlanRepo) UpdatePlan(ctx context.Context, plan *repository.Plan) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePlan", ctx, plan)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePlan indicates an expected call of UpdatePlan.
+func (mr *MockPlanRepoMockRecorder) UpdatePlan(ctx, plan any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePlan", reflect.TypeOf((*MockPlanRepo)(nil).UpdatePlan), ctx, plan)
+}
+
+// MockSystemSettingRepo is a mock of SystemSettingRepo interface.
+type MockSystemSettingRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockSystemSettingRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockSystemSettingRepoMockRecorder is the mock recorder for MockSystemSettingRepo.
+type MockSystemSettingRepoMockRecorder struct {
+	mock *MockSystemSettingRepo
+}
+
+// NewMockSystemSettingRepo creates a new mock instance.
+func NewMockSystemSettingRepo(ctrl *gomock.Controller) *MockSystemSettingRepo {
+	mock := &MockSystemSettingRepo{ctrl: ctrl}
+	mock.recorder = &MockSystemSettingRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSystemSettingRepo) EXPECT() *MockSystemSettingRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateSystemSettingChange mocks base method.
+func (m *MockSystemSettingRepo) CreateSystemSettingChange(ctx context.Context, change *repository.SystemSettingChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSystemSettingChange", ctx, change)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSystemSettingChange indicates an expected call of CreateSystemSettingChange.
+func (mr *MockSystemSettingRepoMockRecorder) CreateSystemSettingChange(ctx, change any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSystemSettingChange", reflect.TypeOf((*MockSystemSettingRepo)(nil).CreateSystemSettingChange), ctx, change)
+}
+
+// DeleteSystemSetting mocks base method.
+func (m *MockSystemSettingRepo) DeleteSystemSetting(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSystemSetting", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSystemSetting indicates an expected call of DeleteSystemSetting.
+func (mr *MockSystemSettingRepoMockRecorder) DeleteSystemSetting(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSystemSetting", reflect.TypeOf((*MockSystemSettingRepo)(nil).DeleteSystemSetting), ctx, key)
+}
+
+// GetSystemSettingChanges mocks base method.
+func (m *MockSystemSettingRepo) GetSystemSettingChanges(ctx context.Context, key string, limit int) ([]repository.SystemSettingChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSystemSettingChanges", ctx, key, limit)
+	ret0, _ := ret[0].([]repository.SystemSettingChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSystemSettingChanges indicates an expected call of GetSystemSettingChanges.
+func (mr *MockSystemSettingRepoMockRecorder) GetSystemSettingChanges(ctx, key, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSystemSettingChanges", reflect.TypeOf((*MockSystemSettingRepo)(nil).GetSystemSettingChanges), ctx, key, limit)
+}
+
+// GetSystemSettings mocks base method.
+func (m *MockSystemSettingRepo) GetSystemSettings(ctx context.Context) ([]repository.SystemSetting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSystemSettings", ctx)
+	ret0, _ := ret[0].([]repository.SystemSetting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSystemSettings indicates an expected call of GetSystemSettings.
+func (mr *MockSystemSettingRepoMockRecorder) GetSystemSettings(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSystemSettings", reflect.TypeOf((*MockSystemSettingRepo)(nil).GetSystemSettings), ctx)
+}
+
+// SaveSystemSetting mocks base method.
+func (m *MockSystemSettingRepo) SaveSystemSetting(ctx context.Context, setting *repository.SystemSetting) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSystemSetting", ctx, setting)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveSystemSetting indicates an expected call of SaveSystemSetting.
+func (mr *MockSystemSettingRepoMockRecorder) SaveSystemSetting(ctx, setting any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSystemSetting", reflect.TypeOf((*MockSystemSettingRepo)(nil).SaveSystemSetting), ctx, setting)
+}
+
+// MockPolicyRepo is a mock of PolicyRepo interface.
+type MockPolicyRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockPolicyRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockPolicyRepoMockRecorder is the mock recorder for MockPolicyRepo.
+type MockPolicyRepoMockRecorder struct {
+	mock *MockPolicyRepo
+}
+
+// NewMockPolicyRepo creates a new mock instance.
+func NewMockPolicyRepo(ctrl *gomock.Controller) *MockPolicyRepo {
+	mock := &MockPolicyRepo{ctrl: ctrl}
+	mock.recorder = &MockPolicyRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPolicyRepo) EXPECT() *MockPolicyRepoMockRecorder {
+	return m.recorder
+}
+
+// CreatePolicy mocks base method.
+func (m *MockPolicyRepo) CreatePolicy(ctx context.Context, policy *repository.Policy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePolicy", ctx, policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePolicy indicates an expected call of CreatePolicy.
+func (mr *MockPolicyRepoMockRecorder) CreatePolicy(ctx, policy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePolicy", reflect.TypeOf((*MockPolicyRepo)(nil).CreatePolicy), ctx, policy)
+}
+
+// DeletePolicy mocks base method.
+func (m *MockPolicyRepo) DeletePolicy(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePolicy", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePolicy indicates an expected call of DeletePolicy.
+func (mr *MockPolicyRepoMockRecorder) DeletePolicy(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePolicy", reflect.TypeOf((*MockPolicyRepo)(nil).DeletePolicy), ctx, id)
+}
+
+// GetPolicies mocks base method.
+func (m *MockPolicyRepo) GetPolicies(ctx context.Context, tenantID uint) ([]repository.Policy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPolicies", ctx, tenantID)
+	ret0, _ := ret[0].([]repository.Policy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPolicies indicates an expected call of GetPolicies.
+func (mr *MockPolicyRepoMockRecorder) GetPolicies(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPolicies", reflect.TypeOf((*MockPolicyRepo)(nil).GetPolicies), ctx, tenantID)
+}
+
+// GetPolicyByID mocks base method.
+func (m *MockPolicyRepo) GetPolicyByID(ctx context.Context, id uint) (*repository.Policy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPolicyByID", ctx, id)
+	ret0, _ := ret[0].(*repository.Policy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPolicyByID indicates an expected call of GetPolicyByID.
+func (mr *MockPolicyRepoMockRecorder) GetPolicyByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPolicyByID", reflect.TypeOf((*MockPolicyRepo)(nil).GetPolicyByID), ctx, id)
+}
+
+// UpdatePolicy mocks base method.
+func (m *MockPolicyRepo) UpdatePolicy(ctx context.Context, policy *repository.Policy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePolicy", ctx, policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePolicy indicates an expected call of UpdatePolicy.
+func (mr *MockPolicyRepoMockRecorder) UpdatePolicy(ctx, policy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePolicy", reflect.TypeOf((*MockPolicyRepo)(nil).UpdatePolicy), ctx, policy)
+}
+
+// MockTaxiRepo is a mock of TaxiRepo interface.
+type MockTaxiRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaxiRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockTaxiRepoMockRecorder is the mock recorder for MockTaxiRepo.
+type MockTaxiRepoMockRecorder struct {
+	mock *MockTaxiRepo
+}
+
+// NewMockTaxiRepo creates a new mock instance.
+func NewMockTaxiRepo(ctrl *gomock.Controller) *MockTaxiRepo {
+	mock := &MockTaxiRepo{ctrl: ctrl}
+	mock.recorder = &MockTaxiRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaxiRepo) EXPECT() *MockTaxiRepoMockRecorder {
+	return m.recorder
+}
+
+// CountTaxis mocks base method.
+func (m *MockTaxiRepo) CountTaxis(ctx context.Context, tenantID uint, status string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountTaxis", ctx, tenantID, status)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountTaxis indicates an expected call of CountTaxis.
+func (mr *MockTaxiRepoMockRecorder) CountTaxis(ctx, tenantID, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTaxis", reflect.TypeOf((*MockTaxiRepo)(nil).CountTaxis), ctx, tenantID, status)
+}
+
+// CreateTaxi mocks base method.
+func (m *MockTaxiRepo) CreateTaxi(ctx context.Context, taxi *repository.Taxi) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTaxi", ctx, taxi)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTaxi indicates an expected call of CreateTaxi.
+func (mr *MockTaxiRepoMockRecorder) CreateTaxi(ctx, taxi any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTaxi", reflect.TypeOf((*MockTaxiRepo)(nil).CreateTaxi), ctx, taxi)
+}
+
+// DeleteTaxi mocks base method.
+func (m *MockTaxiRepo) DeleteTaxi(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTaxi", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTaxi indicates an expected call of DeleteTaxi.
+func (mr *MockTaxiRepoMockRecorder) DeleteTaxi(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTaxi", reflect.TypeOf((*MockTaxiRepo)(nil).DeleteTaxi), ctx, id)
+}
+
+// GetTaxiByID mocks base method.
+func (m *MockTaxiRepo) GetTaxiByID(ctx context.Context, id uint) (*repository.Taxi, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaxiByID", ctx, id)
+	ret0, _ := ret[0].(*repository.Taxi)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaxiByID indicates an expected call of GetTaxiByID.
+func (mr *MockTaxiRepoMockRecorder) GetTaxiByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaxiByID", reflect.TypeOf((*MockTaxiRepo)(nil).GetTaxiByID), ctx, id)
+}
+
+// GetTaxiListVersion mocks base method.
+func (m *MockTaxiRepo) GetTaxiListVersion(ctx context.Context, tenantID uint) (*repository.ListVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaxiListVersion", ctx, tenantID)
+	ret0, _ := ret[0].(*repository.ListVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaxiListVersion indicates an expected call of GetTaxiListVersion.
+func (mr *MockTaxiRepoMockRecorder) GetTaxiListVersion(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaxiListVersion", reflect.TypeOf((*MockTaxiRepo)(nil).GetTaxiListVersion), ctx, tenantID)
+}
+
+// GetTaxisByTenant mocks base method.
+func (m *MockTaxiRepo) GetTaxisByTenant(ctx context.Context, tenantID uint) ([]repository.Taxi, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaxisByTenant", ctx, tenantID)
+	ret0, _ := ret[0].([]repository.Taxi)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaxisByTenant indicates an expected call of GetTaxisByTenant.
+func (mr *MockTaxiRepoMockRecorder) GetTaxisByTenant(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaxisByTenant", reflect.TypeOf((*MockTaxiRepo)(nil).GetTaxisByTenant), ctx, tenantID)
+}
+
+// LicensePlateExists mocks base method.
+func (m *MockTaxiRepo) LicensePlateExists(ctx context.Context, tenantID uint, licensePlate string, excludeID uint) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LicensePlateExists", ctx, tenantID, licensePlate, excludeID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LicensePlateExists indicates an expected call of LicensePlateExists.
+func (mr *MockTaxiRepoMockRecorder) LicensePlateExists(ctx, tenantID, licensePlate, excludeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LicensePlateExists", reflect.TypeOf((*MockTaxiRepo)(nil).LicensePlateExists), ctx, tenantID, licensePlate, excludeID)
+}
+
+// SearchTaxis mocks base method.
+func (m *MockTaxiRepo) SearchTaxis(ctx context.Context, tenantID uint, query string, limit int) ([]repository.Taxi, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTaxis", ctx, tenantID, query, limit)
+	ret0, _ := ret[0].([]repository.Taxi)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchTaxis indicates an expected call of SearchTaxis.
+func (mr *MockTaxiRepoMockRecorder) SearchTaxis(ctx, tenantID, query, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTaxis", reflect.TypeOf((*MockTaxiRepo)(nil).SearchTaxis), ctx, tenantID, query, limit)
+}
+
+// UpdateTaxi mocks base method.
+func (m *MockTaxiRepo) UpdateTaxi(ctx context.Context, taxi *repository.Taxi) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTaxi", ctx, taxi)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTaxi indicates an expected call of UpdateTaxi.
+func (mr *MockTaxiRepoMockRecorder) UpdateTaxi(ctx, taxi any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTaxi", reflect.TypeOf((*MockTaxiRepo)(nil).UpdateTaxi), ctx, taxi)
+}
+
+// MockTaxiLedgerRepo is a mock of TaxiLedgerRepo interface.
+type MockTaxiLedgerRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaxiLedgerRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockTaxiLedgerRepoMockRecorder is the mock recorder for MockTaxiLedgerRepo.
+type MockTaxiLedgerRepoMockRecorder struct {
+	mock *MockTaxiLedgerRepo
+}
+
+// NewMockTaxiLedgerRepo creates a new mock instance.
+func NewMockTaxiLedgerRepo(ctrl *gomock.Controller) *MockTaxiLedgerRepo {
+	mock := &MockTaxiLedgerRepo{ctrl: ctrl}
+	mock.recorder = &MockTaxiLedgerRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaxiLedgerRepo) EXPECT() *MockTaxiLedgerRepoMockRecorder {
+	return m.recorder
+}
+
+// GetApprovedReportsByTaxi mocks base method.
+func (m *MockTaxiLedgerRepo) GetApprovedReportsByTaxi(ctx context.Context, taxiID uint) ([]repository.WeeklyReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApprovedReportsByTaxi", ctx, taxiID)
+	ret0, _ := ret[0].([]repository.WeeklyReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApprovedReportsByTaxi indicates an expected call of GetApprovedReportsByTaxi.
+func (mr *MockTaxiLedgerRepoMockRecorder) GetApprovedReportsByTaxi(ctx, taxiID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApprovedReportsByTaxi", reflect.TypeOf((*MockTaxiLedgerRepo)(nil).GetApprovedReportsByTaxi), ctx, taxiID)
+}
+
+// GetDepositsByTaxi mocks base method.
+func (m *MockTaxiLedgerRepo) GetDepositsByTaxi(ctx context.Context, taxiID uint) ([]repository.BankDeposit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDepositsByTaxi", ctx, taxiID)
+	ret0, _ := ret[0].([]repository.BankDeposit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDepositsByTaxi indicates an expected call of GetDepositsByTaxi.
+func (mr *MockTaxiLedgerRepoMockRecorder) GetDepositsByTaxi(ctx, taxiID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDepositsByTaxi", reflect.TypeOf((*MockTaxiLedgerRepo)(nil).GetDepositsByTaxi), ctx, taxiID)
+}
+
+// GetTaxiCashExpenses mocks base method.
+func (m *MockTaxiLedgerRepo) GetTaxiCashExpenses(ctx context.Context, taxiID uint) ([]repository.Expense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaxiCashExpenses", ctx, taxiID)
+	ret0, _ := ret[0].([]repository.Expense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaxiCashExpenses indicates an expected call of GetTaxiCashExpenses.
+func (mr *MockTaxiLedgerRepoMockRecorder) GetTaxiCashExpenses(ctx, taxiID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaxiCashExpenses", reflect.TypeOf((*MockTaxiLedgerRepo)(nil).GetTaxiCashExpenses), ctx, taxiID)
+}
+
+// MockReportRepo is a mock of ReportRepo interface.
+type MockReportRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockReportRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockReportRepoMockRecorder is the mock recorder for MockReportRepo.
+type MockReportRepoMockRecorder struct {
+	mock *MockReportRepo
+}
+
+// NewMockReportRepo creates a new mock instance.
+func NewMockReportRepo(ctrl *gomock.Controller) *MockReportRepo {
+	mock := &MockReportRepo{ctrl: ctrl}
+	mock.recorder = &MockReportRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReportRepo) EXPECT() *MockReportRepoMockRecorder {
+	return m.recorder
+}
+
+// ArchiveReports mocks base method.
+func (m *MockReportRepo) ArchiveReports(ctx context.Context, tenantID uint, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveReports", ctx, tenantID, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ArchiveReports indicates an expected call of ArchiveReports.
+func (mr *MockReportRepoMockRecorder) ArchiveReports(ctx, tenantID, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveReports", reflect.TypeOf((*MockReportRepo)(nil).ArchiveReports), ctx, tenantID, before)
+}
+
+// CountReports mocks base method.
+func (m *MockReportRepo) CountReports(ctx context.Context, tenantID, driverID uint, status string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountReports", ctx, tenantID, driverID, status)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountReports indicates an expected call of CountReports.
+func (mr *MockReportRepoMockRecorder) CountReports(ctx, tenantID, driverID, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountReports", reflect.TypeOf((*MockReportRepo)(nil).CountReports), ctx, tenantID, driverID, status)
+}
+
+// CreateReport mocks base method.
+func (m *MockReportRepo) CreateReport(ctx context.Context, report *repository.WeeklyReport) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReport", ctx, report)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateReport indicates an expected call of CreateReport.
+func (mr *MockReportRepoMockRecorder) CreateReport(ctx, report any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReport", reflect.TypeOf((*MockReportRepo)(nil).CreateReport), ctx, report)
+}
+
+// DeleteReport mocks base method.
+func (m *MockReportRepo) DeleteReport(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReport", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReport indicates an expected call of DeleteReport.
+func (mr *MockReportRepoMockRecorder) DeleteReport(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReport", reflect.TypeOf((*MockReportRepo)(nil).DeleteReport), ctx, id)
+}
+
+// GetArchivedReports mocks base method.
+func (m *MockReportRepo) GetArchivedReports(ctx context.Context, tenantID, driverID uint) ([]repository.WeeklyReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArchivedReports", ctx, tenantID, driverID)
+	ret0, _ := ret[0].([]repository.WeeklyReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArchivedReports indicates an expected call of GetArchivedReports.
+func (mr *MockReportRepoMockRecorder) GetArchivedReports(ctx, tenantID, driverID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArchivedReports", reflect.TypeOf((*MockReportRepo)(nil).GetArchivedReports), ctx, tenantID, driverID)
+}
+
+// GetReportByID mocks base method.
+func (m *MockReportRepo) GetReportByID(ctx context.Context, id uint) (*repository.WeeklyReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportByID", ctx, id)
+	ret0, _ := ret[0].(*repository.WeeklyReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReportByID indicates an expected call of GetReportByID.
+func (mr *MockReportRepoMockRecorder) GetReportByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportByID", reflect.TypeOf((*MockReportRepo)(nil).GetReportByID), ctx, id)
+}
+
+// GetReportExportRows mocks base method.
+func (m *MockReportRepo) GetReportExportRows(ctx context.Context, tenantID, driverID uint) ([]repository.ReportExportRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportExportRows", ctx, tenantID, driverID)
+	ret0, _ := ret[0].([]repository.ReportExportRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReportExportRows indicates an expected call of GetReportExportRows.
+func (mr *MockReportRepoMockRecorder) GetReportExportRows(ctx, tenantID, driverID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportExportRows", reflect.TypeOf((*MockReportRepo)(nil).GetReportExportRows), ctx, tenantID, driverID)
+}
+
+// GetReportListVersion mocks base method.
+func (m *MockReportRepo) GetReportListVersion(ctx context.Context, tenantID uint) (*repository.ListVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportListVersion", ctx, tenantID)
+	ret0, _ := ret[0].(*repository.ListVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReportListVersion indicates an expected call of GetReportListVersion.
+func (mr *MockReportRepoMockRecorder) GetReportListVersion(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportListVersion", reflect.TypeOf((*MockReportRepo)(nil).GetReportListVersion), ctx, tenantID)
+}
+
+// GetReportsByDriver mocks base method.
+func (m *MockReportRepo) GetReportsByDriver(ctx context.Context, tenantID, driverID uint) ([]repository.WeeklyReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportsByDriver", ctx, tenantID, driverID)
+	ret0, _ := ret[0].([]repository.WeeklyReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReportsByDriver indicates an expected call of GetReportsByDriver.
+func (mr *MockReportRepoMockRecorder) GetReportsByDriver(ctx, tenantID, driverID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportsByDriver", reflect.TypeOf((*MockReportRepo)(nil).GetReportsByDriver), ctx, tenantID, driverID)
+}
+
+// GetReportsByTenant mocks base method.
+func (m *MockReportRepo) GetReportsByTenant(ctx context.Context, tenantID uint) ([]repository.WeeklyReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportsByTenant", ctx, tenantID)
+	ret0, _ := ret[0].([]repository.WeeklyReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReportsByTenant indicates an expected call of GetReportsByTenant.
+func (mr *MockReportRepoMockRecorder) GetReportsByTenant(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportsByTenant", reflect.TypeOf((*MockReportRepo)(nil).GetReportsByTenant), ctx, tenantID)
+}
+
+// GetReportsPage mocks base method.
+func (m *MockReportRepo) GetReportsPage(ctx context.Context, tenantID, driverID uint, after *repository.Keyset, limit int) ([]repository.WeeklyReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportsPage", ctx, tenantID, driverID, after, limit)
+	ret0, _ := ret[0].([]repository.WeeklyReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReportsPage indicates an expected call of GetReportsPage.
+func (mr *MockReportRepoMockRecorder) GetReportsPage(ctx, tenantID, driverID, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportsPage", reflect.TypeOf((*MockReportRepo)(nil).GetReportsPage), ctx, tenantID, driverID, after, limit)
+}
+
+// RecalculateReportExpenses mocks base method.
+func (m *MockReportRepo) RecalculateReportExpenses(ctx context.Context, reportID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecalculateReportExpenses", ctx, reportID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecalculateReportExpenses indicates an expected call of RecalculateReportExpenses.
+func (mr *MockReportRepoMockRecorder) RecalculateReportExpenses(ctx, reportID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecalculateReportExpenses", reflect.TypeOf((*MockReportRepo)(nil).RecalculateReportExpenses), ctx, reportID)
+}
+
+// ReportExistsForWeek mocks base method.
+func (m *MockReportRepo) ReportExistsForWeek(ctx context.Context, tenantID, taxiID, driverID uint, weekStartDate time.Time, excludeID uint) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportExistsForWeek", ctx, tenantID, taxiID, driverID, weekStartDate, excludeID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReportExistsForWeek indicates an expected call of ReportExistsForWeek.
+func (mr *MockReportRepoMockRecorder) ReportExistsForWeek(ctx, tenantID, taxiID, driverID, weekStartDate, excludeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportExistsForWeek", reflect.TypeOf((*MockReportRepo)(nil).ReportExistsForWeek), ctx, tenantID, taxiID, driverID, weekStartDate, excludeID)
+}
+
+// SearchReports mocks base method.
+func (m *MockReportRepo) SearchReports(ctx context.Context, tenantID, driverID uint, query string, limit int) ([]repository.WeeklyReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchReports", ctx, tenantID, driverID, query, limit)
+	ret0, _ := ret[0].([]repository.WeeklyReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchReports indicates an expected call of SearchReports.
+func (mr *MockReportRepoMockRecorder) SearchReports(ctx, tenantID, driverID, query, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchReports", reflect.TypeOf((*MockReportRepo)(nil).SearchReports), ctx, tenantID, driverID, query, limit)
+}
+
+// SummarizeReports mocks base method.
+func (m *MockReportRepo) SummarizeReports(ctx context.Context, tenantID, driverID uint, groupBy string) ([]repository.GroupTotal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SummarizeReports", ctx, tenantID, driverID, groupBy)
+	ret0, _ := ret[0].([]repository.GroupTotal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SummarizeReports indicates an expected call of SummarizeReports.
+func (mr *MockReportRepoMockRecorder) SummarizeReports(ctx, tenantID, driverID, groupBy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SummarizeReports", reflect.TypeOf((*MockReportRepo)(nil).SummarizeReports), ctx, tenantID, driverID, groupBy)
+}
+
+// UpdateReport mocks base method.
+func (m *MockReportRepo) UpdateReport(ctx context.Context, report *repository.WeeklyReport) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateReport", ctx, report)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateReport indicates an expected call of UpdateReport.
+func (mr *MockReportRepoMockRecorder) UpdateReport(ctx, report any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReport", reflect.TypeOf((*MockReportRepo)(nil).UpdateReport), ctx, report)
+}
+
+// MockReportAttachmentRepo is a mock of ReportAttachmentRepo interface.
+type MockReportAttachmentRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockReportAttachmentRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockReportAttachmentRepoMockRecorder is the mock recorder for MockReportAttachmentRepo.
+type MockReportAttachmentRepoMockRecorder struct {
+	mock *MockReportAttachmentRepo
+}
+
+// NewMockReportAttachmentRepo creates a new mock instance.
+func NewMockReportAttachmentRepo(ctrl *gomock.Controller) *MockReportAttachmentRepo {
+	mock := &MockReportAttachmentRepo{ctrl: ctrl}
+	mock.recorder = &MockReportAttachmentRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReportAttachmentRepo) EXPECT() *MockReportAttachmentRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateReportAttachment mocks base method.
+func (m *MockReportAttachmentRepo) CreateReportAttachment(ctx context.Context, attachment *repository.ReportAttachment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReportAttachment", ctx, attachment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateReportAttachment indicates an expected call of CreateReportAttachment.
+func (mr *MockReportAttachmentRepoMockRecorder) CreateReportAttachment(ctx, attachment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReportAttachment", reflect.TypeOf((*MockReportAttachmentRepo)(nil).CreateReportAttachment), ctx, attachment)
+}
+
+// DeleteReportAttachment mocks base method.
+func (m *MockReportAttachmentRepo) DeleteReportAttachment(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReportAttachment", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReportAttachment indicates an expected call of DeleteReportAttachment.
+func (mr *MockReportAttachmentRepoMockRecorder) DeleteReportAttachment(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReportAttachment", reflect.TypeOf((*MockReportAttachmentRepo)(nil).DeleteReportAttachment), ctx, id)
+}
+
+// GetPendingReportAttachments mocks base method.
+func (m *MockReportAttachmentRepo) GetPendingReportAttachments(ctx context.Context, limit int) ([]repository.ReportAttachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingReportAttachments", ctx, limit)
+	ret0, _ := ret[0].([]repository.ReportAttachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingReportAttachments indicates an expected call of GetPendingReportAttachments.
+func (mr *MockReportAttachmentRepoMockRecorder) GetPendingReportAttachments(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingReportAttachments", reflect.TypeOf((*MockReportAttachmentRepo)(nil).GetPendingReportAttachments), ctx, limit)
+}
+
+// GetReportAttachmentByID mocks base method.
+func (m *MockReportAttachmentRepo) GetReportAttachmentByID(ctx context.Context, id uint) (*repository.ReportAttachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportAttachmentByID", ctx, id)
+	ret0, _ := ret[0].(*repository.ReportAttachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReportAttachmentByID indicates an expected call of GetReportAttachmentByID.
+func (mr *MockReportAttachmentRepoMockRecorder) GetReportAttachmentByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportAttachmentByID", reflect.TypeOf((*MockReportAttachmentRepo)(nil).GetReportAttachmentByID), ctx, id)
+}
+
+// GetReportAttachments mocks base method.
+func (m *MockReportAttachmentRepo) GetReportAttachments(ctx context.Context, reportID uint) ([]repository.ReportAttachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportAttachments", ctx, reportID)
+	ret0, _ := ret[0].([]repository.ReportAttachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReportAttachments indicates an expected call of GetReportAttachments.
+func (mr *MockReportAttachmentRepoMockRecorder) GetReportAttachments(ctx, reportID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportAttachments", reflect.TypeOf((*MockReportAttachmentRepo)(nil).GetReportAttachments), ctx, reportID)
+}
+
+// GetReportAttachmentsByTenant mocks base method.
+func (m *MockReportAttachmentRepo) GetReportAttachmentsByTenant(ctx context.Context, tenantID uint) ([]repository.ReportAttachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportAttachmentsByTenant", ctx, tenantID)
+	ret0, _ := ret[0].([]repository.ReportAttachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReportAttachmentsByTenant indicates an expected call of GetReportAttachmentsByTenant.
+func (mr *MockReportAttachmentRepoMockRecorder) GetReportAttachmentsByTenant(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportAttachmentsByTenant", reflect.TypeOf((*MockReportAttachmentRepo)(nil).GetReportAttachmentsByTenant), ctx, tenantID)
+}
+
+// UpdateReportAttachmentScan mocks base method.
+func (m *MockReportAttachmentRepo) UpdateReportAttachmentScan(ctx context.Context, attachment *repository.ReportAttachment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateReportAttachmentScan", ctx, attachment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateReportAttachmentScan indicates an expected call of UpdateReportAttachmentScan.
+func (mr *MockReportAttachmentRepoMockRecorder) UpdateReportAttachmentScan(ctx, attachment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReportAttachmentScan", reflect.TypeOf((*MockReportAttachmentRepo)(nil).UpdateReportAttachmentScan), ctx, attachment)
+}
+
+// MockExportBundleRepo is a mock of ExportBundleRepo interface.
+type MockExportBundleRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportBundleRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockExportBundleRepoMockRecorder is the mock recorder for MockExportBundleRepo.
+type MockExportBundleRepoMockRecorder struct {
+	mock *MockExportBundleRepo
+}
+
+// NewMockExportBundleRepo creates a new mock instance.
+func NewMockExportBundleRepo(ctrl *gomock.Controller) *MockExportBundleRepo {
+	mock := &MockExportBundleRepo{ctrl: ctrl}
+	mock.recorder = &MockExportBundleRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportBundleRepo) EXPECT() *MockExportBundleRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateExportBundle mocks base method.
+func (m *MockExportBundleRepo) CreateExportBundle(ctx context.Context, bundle *repository.ExportBundle) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExportBundle", ctx, bundle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateExportBundle indicates an expected call of CreateExportBundle.
+func (mr *MockExportBundleRepoMockRecorder) CreateExportBundle(ctx, bundle any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExportBundle", reflect.TypeOf((*MockExportBundleRepo)(nil).CreateExportBundle), ctx, bundle)
+}
+
+// DeleteExportBundle mocks base method.
+func (m *MockExportBundleRepo) DeleteExportBundle(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExportBundle", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExportBundle indicates an expected call of DeleteExportBundle.
+func (mr *MockExportBundleRepoMockRecorder) DeleteExportBundle(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExportBundle", reflect.TypeOf((*MockExportBundleRepo)(nil).DeleteExportBundle), ctx, id)
+}
+
+// GetExpiredExportBundles mocks base method.
+func (m *MockExportBundleRepo) GetExpiredExportBundles(ctx context.Context, before time.Time) ([]repository.ExportBundle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpiredExportBundles", ctx, before)
+	ret0, _ := ret[0].([]repository.ExportBundle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpiredExportBundles indicates an expected call of GetExpiredExportBundles.
+func (mr *MockExportBundleRepoMockRecorder) GetExpiredExportBundles(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiredExportBundles", reflect.TypeOf((*MockExportBundleRepo)(nil).GetExpiredExportBundles), ctx, before)
+}
+
+// GetExportBundleByID mocks base method.
+func (m *MockExportBundleRepo) GetExportBundleByID(ctx context.Context, id uint) (*repository.ExportBundle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExportBundleByID", ctx, id)
+	ret0, _ := ret[0].(*repository.ExportBundle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExportBundleByID indicates an expected call of GetExportBundleByID.
+func (mr *MockExportBundleRepoMockRecorder) GetExportBundleByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExportBundleByID", reflect.TypeOf((*MockExportBundleRepo)(nil).GetExportBundleByID), ctx, id)
+}
+
+// GetPeriodReportAttachments mocks base method.
+func (m *MockExportBundleRepo) GetPeriodReportAttachments(ctx context.Context, tenantID uint, from, to time.Time) ([]repository.ReportAttachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPeriodReportAttachments", ctx, tenantID, from, to)
+	ret0, _ := ret[0].([]repository.ReportAttachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPeriodReportAttachments indicates an expected call of GetPeriodReportAttachments.
+func (mr *MockExportBundleRepoMockRecorder) GetPeriodReportAttachments(ctx, tenantID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPeriodReportAttachments", reflect.TypeOf((*MockExportBundleRepo)(nil).GetPeriodReportAttachments), ctx, tenantID, from, to)
+}
+
+// UpdateExportBundle mocks base method.
+func (m *MockExportBundleRepo) UpdateExportBundle(ctx context.Context, bundle *repository.ExportBundle) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateExportBundle", ctx, bundle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateExportBundle indicates an expected call of UpdateExportBundle.
+func (mr *MockExportBundleRepoMockRecorder) UpdateExportBundle(ctx, bundle any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateExportBundle", reflect.TypeOf((*MockExportBundleRepo)(nil).UpdateExportBundle), ctx, bundle)
+}
+
+// MockReportSignatureRepo is a mock of ReportSignatureRepo interface.
+type MockReportSignatureRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockReportSignatureRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockReportSignatureRepoMockRecorder is the mock recorder for MockReportSignatureRepo.
+type MockReportSignatureRepoMockRecorder struct {
+	mock *MockReportSignatureRepo
+}
+
+// NewMockReportSignatureRepo creates a new mock instance.
+func NewMockReportSignatureRepo(ctrl *gomock.Controller) *MockReportSignatureRepo {
+	mock := &MockReportSignatureRepo{ctrl: ctrl}
+	mock.recorder = &MockReportSignatureRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReportSignatureRepo) EXPECT() *MockReportSignatureRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateReportSignature mocks base method.
+func (m *MockReportSignatureRepo) CreateReportSignature(ctx context.Context, signature *repository.ReportSignature) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReportSignature", ctx, signature)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateReportSignature indicates an expected call of CreateReportSignature.
+func (mr *MockReportSignatureRepoMockRecorder) CreateReportSignature(ctx, signature any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReportSignature", reflect.TypeOf((*MockReportSignatureRepo)(nil).CreateReportSignature), ctx, signature)
+}
+
+// GetReportSignature mocks base method.
+func (m *MockReportSignatureRepo) GetReportSignature(ctx context.Context, reportID uint) (*repository.ReportSignature, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportSignature", ctx, reportID)
+	ret0, _ := ret[0].(*repository.ReportSignature)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReportSignature indicates an expected call of GetReportSignature.
+func (mr *MockReportSignatureRepoMockRecorder) GetReportSignature(ctx, reportID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportSignature", reflect.TypeOf((*MockReportSignatureRepo)(nil).GetReportSignature), ctx, reportID)
+}
+
+// MockDelegationRepo is a mock of DelegationRepo interface.
+type MockDelegationRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockDelegationRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockDelegationRepoMockRecorder is the mock recorder for MockDelegationRepo.
+type MockDelegationRepoMockRecorder struct {
+	mock *MockDelegationRepo
+}
+
+// NewMockDelegationRepo creates a new mock instance.
+func NewMockDelegationRepo(ctrl *gomock.Controller) *MockDelegationRepo {
+	mock := &MockDelegationRepo{ctrl: ctrl}
+	mock.recorder = &MockDelegationRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDelegationRepo) EXPECT() *MockDelegationRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateDelegation mocks base method.
+func (m *MockDelegationRepo) CreateDelegation(ctx context.Context, delegation *repository.ApprovalDelegation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDelegation", ctx, delegation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDelegation indicates an expected call of CreateDelegation.
+func (mr *MockDelegationRepoMockRecorder) CreateDelegation(ctx, delegation any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDelegation", reflect.TypeOf((*MockDelegationRepo)(nil).CreateDelegation), ctx, delegation)
+}
+
+// GetDelegationByID mocks base method.
+func (m *MockDelegationRepo) GetDelegationByID(ctx context.Context, id uint) (*repository.ApprovalDelegation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDelegationByID", ctx, id)
+	ret0, _ := ret[0].(*repository.ApprovalDelegation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDelegationByID indicates an expected call of GetDelegationByID.
+func (mr *MockDelegationRepoMockRecorder) GetDelegationByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDelegationByID", reflect.TypeOf((*MockDelegationRepo)(nil).GetDelegationByID), ctx, id)
+}
+
+// GetDelegations mocks base method.
+func (m *MockDelegationRepo) GetDelegations(ctx context.Context, tenantID uint) ([]repository.ApprovalDelegation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDelegations", ctx, tenantID)
+	ret0, _ := ret[0].([]repository.ApprovalDelegation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDelegations indicates an expected call of GetDelegations.
+func (mr *MockDelegationRepoMockRecorder) GetDelegations(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDelegations", reflect.TypeOf((*MockDelegationRepo)(nil).GetDelegations), ctx, tenantID)
+}
+
+// HasActiveDelegation mocks base method.
+func (m *MockDelegationRepo) HasActiveDelegation(ctx context.Context, tenantID, delegateID uint, day time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasActiveDelegation", ctx, tenantID, delegateID, day)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasActiveDelegation indicates an expected call of HasActiveDelegation.
+func (mr *MockDelegationRepoMockRecorder) HasActiveDelegation(ctx, tenantID, delegateID, day any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasActiveDelegation", reflect.TypeOf((*MockDelegationRepo)(nil).HasActiveDelegation), ctx, tenantID, delegateID, day)
+}
+
+// RevokeDelegation mocks base method.
+func (m *MockDelegationRepo) RevokeDelegation(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeDelegation", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeDelegation indicates an expected call of RevokeDelegation.
+func (mr *MockDelegationRepoMockRecorder) RevokeDelegation(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeDelegation", reflect.TypeOf((*MockDelegationRepo)(nil).RevokeDelegation), ctx, id)
+}
+
+// MockReportRejectionRepo is a mock of ReportRejectionRepo interface.
+type MockReportRejectionRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockReportRejectionRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockReportRejectionRepoMockRecorder is the mock recorder for MockReportRejectionRepo.
+type MockReportRejectionRepoMockRecorder struct {
+	mock *MockReportRejectionRepo
+}
+
+// NewMockReportRejectionRepo creates a new mock instance.
+func NewMockReportRejectionRepo(ctrl *gomock.Controller) *MockReportRejectionRepo {
+	mock := &MockReportRejectionRepo{ctrl: ctrl}
+	mock.recorder = &MockReportRejectionRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReportRejectionRepo) EXPECT() *MockReportRejectionRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateReportRejection mocks base method.
+func (m *MockReportRejectionRepo) CreateReportRejection(ctx context.Context, rejection *repository.ReportRejection) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReportRejection", ctx, rejection)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateReportRejection indicates an expected call of CreateReportRejection.
+func (mr *MockReportRejectionRepoMockRecorder) CreateReportRejection(ctx, rejection any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReportRejection", reflect.TypeOf((*MockReportRejectionRepo)(nil).CreateReportRejection), ctx, rejection)
+}
+
+// MockReportAdjustmentRepo is a mock of ReportAdjustmentRepo interface.
+type MockReportAdjustmentRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockReportAdjustmentRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockReportAdjustmentRepoMockRecorder is the mock recorder for MockReportAdjustmentRepo.
+type MockReportAdjustmentRepoMockRecorder struct {
+	mock *MockReportAdjustmentRepo
+}
+
+// NewMockReportAdjustmentRepo creates a new mock instance.
+func NewMockReportAdjustmentRepo(ctrl *gomock.Controller) *MockReportAdjustmentRepo {
+	mock := &MockReportAdjustmentRepo{ctrl: ctrl}
+	mock.recorder = &MockReportAdjustmentRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReportAdjustmentRepo) EXPECT() *MockReportAdjustmentRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateReportAdjustment mocks base method.
+func (m *MockReportAdjustmentRepo) CreateReportAdjustment(ctx context.Context, adjustment *repository.ReportAdjustment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReportAdjustment", ctx, adjustment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateReportAdjustment indicates an expected call of CreateReportAdjustment.
+func (mr *MockReportAdjustmentRepoMockRecorder) CreateReportAdjustment(ctx, adjustment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReportAdjustment", reflect.TypeOf((*MockReportAdjustmentRepo)(nil).CreateReportAdjustment), ctx, adjustment)
+}
+
+// DeleteReportAdjustment mocks base method.
+func (m *MockReportAdjustmentRepo) DeleteReportAdjustment(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReportAdjustment", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReportAdjustment indicates an expected call of DeleteReportAdjustment.
+func (mr *MockReportAdjustmentRepoMockRecorder) DeleteReportAdjustment(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReportAdjustment", reflect.TypeOf((*MockReportAdjustmentRepo)(nil).DeleteReportAdjustment), ctx, id)
+}
+
+// GetReportAdjustmentByID mocks base method.
+func (m *MockReportAdjustmentRepo) GetReportAdjustmentByID(ctx context.Context, id uint) (*repository.ReportAdjustment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportAdjustmentByID", ctx, id)
+	ret0, _ := ret[0].(*repository.ReportAdjustment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReportAdjustmentByID indicates an expected call of GetReportAdjustmentByID.
+func (mr *MockReportAdjustmentRepoMockRecorder) GetReportAdjustmentByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportAdjustmentByID", reflect.TypeOf((*MockReportAdjustmentRepo)(nil).GetReportAdjustmentByID), ctx, id)
+}
+
+// RecalculateReportAdjustments mocks base method.
+func (m *MockReportAdjustmentRepo) RecalculateReportAdjustments(ctx context.Context, reportID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecalculateReportAdjustments", ctx, reportID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecalculateReportAdjustments indicates an expected call of RecalculateReportAdjustments.
+func (mr *MockReportAdjustmentRepoMockRecorder) RecalculateReportAdjustments(ctx, reportID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecalculateReportAdjustments", reflect.TypeOf((*MockReportAdjustmentRepo)(nil).RecalculateReportAdjustments), ctx, reportID)
+}
+
+// MockDriverLedgerRepo is a mock of DriverLedgerRepo interface.
+type MockDriverLedgerRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockDriverLedgerRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockDriverLedgerRepoMockRecorder is the mock recorder for MockDriverLedgerRepo.
+type MockDriverLedgerRepoMockRecorder struct {
+	mock *MockDriverLedgerRepo
+}
+
+// NewMockDriverLedgerRepo creates a new mock instance.
+func NewMockDriverLedgerRepo(ctrl *gomock.Controller) *MockDriverLedgerRepo {
+	mock := &MockDriverLedgerRepo{ctrl: ctrl}
+	mock.recorder = &MockDriverLedgerRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDriverLedgerRepo) EXPECT() *MockDriverLedgerRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateLedgerEntry mocks base method.
+func (m *MockDriverLedgerRepo) CreateLedgerEntry(ctx context.Context, entry *repository.DriverLedgerEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLedgerEntry", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateLedgerEntry indicates an expected call of CreateLedgerEntry.
+func (mr *MockDriverLedgerRepoMockRecorder) CreateLedgerEntry(ctx, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLedgerEntry", reflect.TypeOf((*MockDriverLedgerRepo)(nil).CreateLedgerEntry), ctx, entry)
+}
+
+// GetDriverBalance mocks base method.
+func (m *MockDriverLedgerRepo) GetDriverBalance(ctx context.Context, driverID uint) (money.Amount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDriverBalance", ctx, driverID)
+	ret0, _ := ret[0].(money.Amount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDriverBalance indicates an expected call of GetDriverBalance.
+func (mr *MockDriverLedgerRepoMockRecorder) GetDriverBalance(ctx, driverID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDriverBalance", reflect.TypeOf((*MockDriverLedgerRepo)(nil).GetDriverBalance), ctx, driverID)
+}
+
+// GetDriverBalances mocks base method.
+func (m *MockDriverLedgerRepo) GetDriverBalances(ctx context.Context, tenantID uint) ([]repository.DriverBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDriverBalances", ctx, tenantID)
+	ret0, _ := ret[0].([]repository.DriverBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDriverBalances indicates an expected call of GetDriverBalances.
+func (mr *MockDriverLedgerRepoMockRecorder) GetDriverBalances(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDriverBalances", reflect.TypeOf((*MockDriverLedgerRepo)(nil).GetDriverBalances), ctx, tenantID)
+}
+
+// GetLedgerEntries mocks base method.
+func (m *MockDriverLedgerRepo) GetLedgerEntries(ctx context.Context, driverID uint) ([]repository.DriverLedgerEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLedgerEntries", ctx, driverID)
+	ret0, _ := ret[0].([]repository.DriverLedgerEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLedgerEntries indicates an expected call of GetLedgerEntries.
+func (mr *MockDriverLedgerRepoMockRecorder) GetLedgerEntries(ctx, driverID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLedgerEntries", reflect.TypeOf((*MockDriverLedgerRepo)(nil).GetLedgerEntries), ctx, driverID)
+}
+
+// MockTripRepo is a mock of TripRepo interface.
+type MockTripRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockTripRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockTripRepoMockRecorder is the mock recorder for MockTripRepo.
+type MockTripRepoMockRecorder struct {
+	mock *MockTripRepo
+}
+
+// NewMockTripRepo creates a new mock instance.
+func NewMockTripRepo(ctrl *gomock.Controller) *MockTripRepo {
+	mock := &MockTripRepo{ctrl: ctrl}
+	mock.recorder = &MockTripRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTripRepo) EXPECT() *MockTripRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateTrips mocks base method.
+func (m *MockTripRepo) CreateTrips(ctx context.Context, trips []repository.Trip) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTrips", ctx, trips)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTrips indicates an expected call of CreateTrips.
+func (mr *MockTripRepoMockRecorder) CreateTrips(ctx, trips any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTrips", reflect.TypeOf((*MockTripRepo)(nil).CreateTrips), ctx, trips)
+}
+
+// GetTrips mocks base method.
+func (m *MockTripRepo) GetTrips(ctx context.Context, tenantID, driverID uint, from, to time.Time) ([]repository.Trip, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrips", ctx, tenantID, driverID, from, to)
+	ret0, _ := ret[0].([]repository.Trip)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrips indicates an expected call of GetTrips.
+func (mr *MockTripRepoMockRecorder) GetTrips(ctx, tenantID, driverID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrips", reflect.TypeOf((*MockTripRepo)(nil).GetTrips), ctx, tenantID, driverID, from, to)
+}
+
+// SumTrips mocks base method.
+func (m *MockTripRepo) SumTrips(ctx context.Context, taxiID, driverID uint, from, to time.Time) (*repository.TripTotals, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumTrips", ctx, taxiID, driverID, from, to)
+	ret0, _ := ret[0].(*repository.TripTotals)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumTrips indicates an expected call of SumTrips.
+func (mr *MockTripRepoMockRecorder) SumTrips(ctx, taxiID, driverID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumTrips", reflect.TypeOf((*MockTripRepo)(nil).SumTrips), ctx, taxiID, driverID, from, to)
+}
+
+// MockPlatformEarningRepo is a mock of PlatformEarningRepo interface.
+type MockPlatformEarningRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockPlatformEarningRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockPlatformEarningRepoMockRecorder is the mock recorder for MockPlatformEarningRepo.
+type MockPlatformEarningRepoMockRecorder struct {
+	mock *MockPlatformEarningRepo
+}
+
+// NewMockPlatformEarningRepo creates a new mock instance.
+func NewMockPlatformEarningRepo(ctrl *gomock.Controller) *MockPlatformEarningRepo {
+	mock := &MockPlatformEarningRepo{ctrl: ctrl}
+	mock.recorder = &MockPlatformEarningRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPlatformEarningRepo) EXPECT() *MockPlatformEarningRepoMockRecorder {
+	return m.recorder
+}
+
+// GetPlatformEarnings mocks base method.
+func (m *MockPlatformEarningRepo) GetPlatformEarnings(ctx context.Context, tenantID uint, from, to time.Time) ([]repository.PlatformEarning, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPlatformEarnings", ctx, tenantID, from, to)
+	ret0, _ := ret[0].([]repository.PlatformEarning)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPlatformEarnings indicates an expected call of GetPlatformEarnings.
+func (mr *MockPlatformEarningRepoMockRecorder) GetPlatformEarnings(ctx, tenantID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlatformEarnings", reflect.TypeOf((*MockPlatformEarningRepo)(nil).GetPlatformEarnings), ctx, tenantID, from, to)
+}
+
+// GetReportsInPeriod mocks base method.
+func (m *MockPlatformEarningRepo) GetReportsInPeriod(ctx context.Context, tenantID uint, from, to time.Time) ([]repository.WeeklyReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportsInPeriod", ctx, tenantID, from, to)
+	ret0, _ := ret[0].([]repository.WeeklyReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReportsInPeriod indicates an expected call of GetReportsInPeriod.
+func (mr *MockPlatformEarningRepoMockRecorder) GetReportsInPeriod(ctx, tenantID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportsInPeriod", reflect.TypeOf((*MockPlatformEarningRepo)(nil).GetReportsInPeriod), ctx, tenantID, from, to)
+}
+
+// SavePlatformEarnings mocks base method.
+func (m *MockPlatformEarningRepo) SavePlatformEarnings(ctx context.Context, earnings []repository.PlatformEarning) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavePlatformEarnings", ctx, earnings)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SavePlatformEarnings indicates an expected call of SavePlatformEarnings.
+func (mr *MockPlatformEarningRepoMockRecorder) SavePlatformEarnings(ctx, earnings any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePlatformEarnings", reflect.TypeOf((*MockPlatformEarningRepo)(nil).SavePlatformEarnings), ctx, earnings)
+}
+
+// MockFuelCardRepo is a mock of FuelCardRepo interface.
+type MockFuelCardRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockFuelCardRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockFuelCardRepoMockRecorder is the mock recorder for MockFuelCardRepo.
+type MockFuelCardRepoMockRecorder struct {
+	mock *MockFuelCardRepo
+}
+
+// NewMockFuelCardRepo creates a new mock instance.
+func NewMockFuelCardRepo(ctrl *gomock.Controller) *MockFuelCardRepo {
+	mock := &MockFuelCardRepo{ctrl: ctrl}
+	mock.recorder = &MockFuelCardRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFuelCardRepo) EXPECT() *MockFuelCardRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateFuelCard mocks base method.
+func (m *MockFuelCardRepo) CreateFuelCard(ctx context.Context, card *repository.FuelCard) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFuelCard", ctx, card)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateFuelCard indicates an expected call of CreateFuelCard.
+func (mr *MockFuelCardRepoMockRecorder) CreateFuelCard(ctx, card any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFuelCard", reflect.TypeOf((*MockFuelCardRepo)(nil).CreateFuelCard), ctx, card)
+}
+
+// CreateFuelCardTransaction mocks base method.
+func (m *MockFuelCardRepo) CreateFuelCardTransaction(ctx context.Context, transaction *repository.FuelCardTransaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFuelCardTransaction", ctx, transaction)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateFuelCardTransaction indicates an expected call of CreateFuelCardTransaction.
+func (mr *MockFuelCardRepoMockRecorder) CreateFuelCardTransaction(ctx, transaction any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFuelCardTransaction", reflect.TypeOf((*MockFuelCardRepo)(nil).CreateFuelCardTransaction), ctx, transaction)
+}
+
+// DeleteFuelCard mocks base method.
+func (m *MockFuelCardRepo) DeleteFuelCard(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFuelCard", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFuelCard indicates an expected call of DeleteFuelCard.
+func (mr *MockFuelCardRepoMockRecorder) DeleteFuelCard(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFuelCard", reflect.TypeOf((*MockFuelCardRepo)(nil).DeleteFuelCard), ctx, id)
+}
+
+// GetFuelCardByID mocks base method.
+func (m *MockFuelCardRepo) GetFuelCardByID(ctx context.Context, id uint) (*repository.FuelCard, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFuelCardByID", ctx, id)
+	ret0, _ := ret[0].(*repository.FuelCard)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFuelCardByID indicates an expected call of GetFuelCardByID.
+func (mr *MockFuelCardRepoMockRecorder) GetFuelCardByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFuelCardByID", reflect.TypeOf((*MockFuelCardRepo)(nil).GetFuelCardByID), ctx, id)
+}
+
+// GetFuelCardTransactions mocks base method.
+func (m *MockFuelCardRepo) GetFuelCardTransactions(ctx context.Context, tenantID uint, flagged bool, limit int) ([]repository.FuelCardTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFuelCardTransactions", ctx, tenantID, flagged, limit)
+	ret0, _ := ret[0].([]repository.FuelCardTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFuelCardTransactions indicates an expected call of GetFuelCardTransactions.
+func (mr *MockFuelCardRepoMockRecorder) GetFuelCardTransactions(ctx, tenantID, flagged, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFuelCardTransactions", reflect.TypeOf((*MockFuelCardRepo)(nil).GetFuelCardTransactions), ctx, tenantID, flagged, limit)
+}
+
+// GetFuelCardsByNumbers mocks base method.
+func (m *MockFuelCardRepo) GetFuelCardsByNumbers(ctx context.Context, numbers []string) ([]repository.FuelCard, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFuelCardsByNumbers", ctx, numbers)
+	ret0, _ := ret[0].([]repository.FuelCard)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFuelCardsByNumbers indicates an expected call of GetFuelCardsByNumbers.
+func (mr *MockFuelCardRepoMockRecorder) GetFuelCardsByNumbers(ctx, numbers any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFuelCardsByNumbers", reflect.TypeOf((*MockFuelCardRepo)(nil).GetFuelCardsByNumbers), ctx, numbers)
+}
+
+// GetFuelCardsByTenant mocks base method.
+func (m *MockFuelCardRepo) GetFuelCardsByTenant(ctx context.Context, tenantID uint) ([]repository.FuelCard, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFuelCardsByTenant", ctx, tenantID)
+	ret0, _ := ret[0].([]repository.FuelCard)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFuelCardsByTenant indicates an expected call of GetFuelCardsByTenant.
+func (mr *MockFuelCardRepoMockRecorder) GetFuelCardsByTenant(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFuelCardsByTenant", reflect.TypeOf((*MockFuelCardRepo)(nil).GetFuelCardsByTenant), ctx, tenantID)
+}
+
+// GetLatestTransactionTime mocks base method.
+func (m *MockFuelCardRepo) GetLatestTransactionTime(ctx context.Context) (*time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestTransactionTime", ctx)
+	ret0, _ := ret[0].(*time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestTransactionTime indicates an expected call of GetLatestTransactionTime.
+func (mr *MockFuelCardRepoMockRecorder) GetLatestTransactionTime(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestTransactionTime", reflect.TypeOf((*MockFuelCardRepo)(nil).GetLatestTransactionTime), ctx)
+}
+
+// GetSyncedTransactionIDs mocks base method.
+func (m *MockFuelCardRepo) GetSyncedTransactionIDs(ctx context.Context, externalIDs []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSyncedTransactionIDs", ctx, externalIDs)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSyncedTransactionIDs indicates an expected call of GetSyncedTransactionIDs.
+func (mr *MockFuelCardRepoMockRecorder) GetSyncedTransactionIDs(ctx, externalIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSyncedTransactionIDs", reflect.TypeOf((*MockFuelCardRepo)(nil).GetSyncedTransactionIDs), ctx, externalIDs)
+}
+
+// UpdateFuelCard mocks base method.
+func (m *MockFuelCardRepo) UpdateFuelCard(ctx context.Context, card *repository.FuelCard) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFuelCard", ctx, card)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateFuelCard indicates an expected call of UpdateFuelCard.
+func (mr *MockFuelCardRepoMockRecorder) UpdateFuelCard(ctx, card any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFuelCard", reflect.TypeOf((*MockFuelCardRepo)(nil).UpdateFuelCard), ctx, card)
+}
+
+// MockGeofenceRepo is a mock of GeofenceRepo interface.
+type MockGeofenceRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockGeofenceRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockGeofenceRepoMockRecorder is the mock recorder for MockGeofenceRepo.
+type MockGeofenceRepoMockRecorder struct {
+	mock *MockGeofenceRepo
+}
+
+// NewMockGeofenceRepo creates a new mock instance.
+func NewMockGeofenceRepo(ctrl *gomock.Controller) *MockGeofenceRepo {
+	mock := &MockGeofenceRepo{ctrl: ctrl}
+	mock.recorder = &MockGeofenceRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGeofenceRepo) EXPECT() *MockGeofenceRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateGeofence mocks base method.
+func (m *MockGeofenceRepo) CreateGeofence(ctx context.Context, geofence *repository.Geofence) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGeofence", ctx, geofence)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateGeofence indicates an expected call of CreateGeofence.
+func (mr *MockGeofenceRepoMockRecorder) CreateGeofence(ctx, geofence any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGeofence", reflect.TypeOf((*MockGeofenceRepo)(nil).CreateGeofence), ctx, geofence)
+}
+
+// DeleteGeofence mocks base method.
+func (m *MockGeofenceRepo) DeleteGeofence(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGeofence", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGeofence indicates an expected call of DeleteGeofence.
+func (mr *MockGeofenceRepoMockRecorder) DeleteGeofence(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGeofence", reflect.TypeOf((*MockGeofenceRepo)(nil).DeleteGeofence), ctx, id)
+}
+
+// GetGeofenceByID mocks base method.
+func (m *MockGeofenceRepo) GetGeofenceByID(ctx context.Context, id uint) (*repository.Geofence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGeofenceByID", ctx, id)
+	ret0, _ := ret[0].(*repository.Geofence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGeofenceByID indicates an expected call of GetGeofenceByID.
+func (mr *MockGeofenceRepoMockRecorder) GetGeofenceByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGeofenceByID", reflect.TypeOf((*MockGeofenceRepo)(nil).GetGeofenceByID), ctx, id)
+}
+
+// GetGeofencesByTenant mocks base method.
+func (m *MockGeofenceRepo) GetGeofencesByTenant(ctx context.Context, tenantID uint) ([]repository.Geofence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGeofencesByTenant", ctx, tenantID)
+	ret0, _ := ret[0].([]repository.Geofence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGeofencesByTenant indicates an expected call of GetGeofencesByTenant.
+func (mr *MockGeofenceRepoMockRecorder) GetGeofencesByTenant(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGeofencesByTenant", reflect.TypeOf((*MockGeofenceRepo)(nil).GetGeofencesByTenant), ctx, tenantID)
+}
+
+// UpdateGeofence mocks base method.
+func (m *MockGeofenceRepo) UpdateGeofence(ctx context.Context, geofence *repository.Geofence) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateGeofence", ctx, geofence)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateGeofence indicates an expected call of UpdateGeofence.
+func (mr *MockGeofenceRepoMockRecorder) UpdateGeofence(ctx, geofence any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGeofence", reflect.TypeOf((*MockGeofenceRepo)(nil).UpdateGeofence), ctx, geofence)
+}
+
+// MockPositionRepo is a mock of PositionRepo interface.
+type MockPositionRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockPositionRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockPositionRepoMockRecorder is the mock recorder for MockPositionRepo.
+type MockPositionRepoMockRecorder struct {
+	mock *MockPositionRepo
+}
+
+// NewMockPositionRepo creates a new mock instance.
+func NewMockPositionRepo(ctrl *gomock.Controller) *MockPositionRepo {
+	mock := &MockPositionRepo{ctrl: ctrl}
+	mock.recorder = &MockPositionRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPositionRepo) EXPECT() *MockPositionRepoMockRecorder {
+	return m.recorder
+}
+
+// CreatePositions mocks base method.
+func (m *MockPositionRepo) CreatePositions(ctx context.Context, positions []repository.TaxiPosition) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePositions", ctx, positions)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePositions indicates an expected call of CreatePositions.
+func (mr *MockPositionRepoMockRecorder) CreatePositions(ctx, positions any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePositions", reflect.TypeOf((*MockPositionRepo)(nil).CreatePositions), ctx, positions)
+}
+
+// GetLatestPosition mocks base method.
+func (m *MockPositionRepo) GetLatestPosition(ctx context.Context, taxiID uint) (*repository.TaxiPosition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestPosition", ctx, taxiID)
+	ret0, _ := ret[0].(*repository.TaxiPosition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestPosition indicates an expected call of GetLatestPosition.
+func (mr *MockPositionRepoMockRecorder) GetLatestPosition(ctx, taxiID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestPosition", reflect.TypeOf((*MockPositionRepo)(nil).GetLatestPosition), ctx, taxiID)
+}
+
+// GetLatestPositions mocks base method.
+func (m *MockPositionRepo) GetLatestPositions(ctx context.Context, tenantID uint) ([]repository.TaxiPosition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestPositions", ctx, tenantID)
+	ret0, _ := ret[0].([]repository.TaxiPosition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestPositions indicates an expected call of GetLatestPositions.
+func (mr *MockPositionRepoMockRecorder) GetLatestPositions(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestPositions", reflect.TypeOf((*MockPositionRepo)(nil).GetLatestPositions), ctx, tenantID)
+}
+
+// MockSyncRepo is a mock of SyncRepo interface.
+type MockSyncRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockSyncRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockSyncRepoMockRecorder is the mock recorder for MockSyncRepo.
+type MockSyncRepoMockRecorder struct {
+	mock *MockSyncRepo
+}
+
+// NewMockSyncRepo creates a new mock instance.
+func NewMockSyncRepo(ctrl *gomock.Controller) *MockSyncRepo {
+	mock := &MockSyncRepo{ctrl: ctrl}
+	mock.recorder = &MockSyncRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSyncRepo) EXPECT() *MockSyncRepoMockRecorder {
+	return m.recorder
+}
+
+// GetExpenseByClientID mocks base method.
+func (m *MockSyncRepo) GetExpenseByClientID(ctx context.Context, tenantID uint, clientID string) (*repository.Expense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpenseByClientID", ctx, tenantID, clientID)
+	ret0, _ := ret[0].(*repository.Expense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpenseByClientID indicates an expected call of GetExpenseByClientID.
+func (mr *MockSyncRepoMockRecorder) GetExpenseByClientID(ctx, tenantID, clientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpenseByClientID", reflect.TypeOf((*MockSyncRepo)(nil).GetExpenseByClientID), ctx, tenantID, clientID)
+}
+
+// GetExpensesChangedSince mocks base method.
+func (m *MockSyncRepo) GetExpensesChangedSince(ctx context.Context, userID uint, since time.Time) ([]repository.Expense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpensesChangedSince", ctx, userID, since)
+	ret0, _ := ret[0].([]repository.Expense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpensesChangedSince indicates an expected call of GetExpensesChangedSince.
+func (mr *MockSyncRepoMockRecorder) GetExpensesChangedSince(ctx, userID, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpensesChangedSince", reflect.TypeOf((*MockSyncRepo)(nil).GetExpensesChangedSince), ctx, userID, since)
+}
+
+// GetReportByClientID mocks base method.
+func (m *MockSyncRepo) GetReportByClientID(ctx context.Context, tenantID uint, clientID string) (*repository.WeeklyReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportByClientID", ctx, tenantID, clientID)
+	ret0, _ := ret[0].(*repository.WeeklyReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReportByClientID indicates an expected call of GetReportByClientID.
+func (mr *MockSyncRepoMockRecorder) GetReportByClientID(ctx, tenantID, clientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportByClientID", reflect.TypeOf((*MockSyncRepo)(nil).GetReportByClientID), ctx, tenantID, clientID)
+}
+
+// GetReportsChangedSince mocks base method.
+func (m *MockSyncRepo) GetReportsChangedSince(ctx context.Context, driverID uint, since time.Time) ([]repository.WeeklyReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportsChangedSince", ctx, driverID, since)
+	ret0, _ := ret[0].([]repository.WeeklyReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReportsChangedSince indicates an expected call of GetReportsChangedSince.
+func (mr *MockSyncRepoMockRecorder) GetReportsChangedSince(ctx, driverID, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportsChangedSince", reflect.TypeOf((*MockSyncRepo)(nil).GetReportsChangedSince), ctx, driverID, since)
+}
+
+// MockFineRepo is a mock of FineRepo interface.
+type MockFineRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockFineRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockFineRepoMockRecorder is the mock recorder for MockFineRepo.
+type MockFineRepoMockRecorder struct {
+	mock *MockFineRepo
+}
+
+// NewMockFineRepo creates a new mock instance.
+func NewMockFineRepo(ctrl *gomock.Controller) *MockFineRepo {
+	mock := &MockFineRepo{ctrl: ctrl}
+	mock.recorder = &MockFineRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFineRepo) EXPECT() *MockFineRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateFine mocks base method.
+func (m *MockFineRepo) CreateFine(ctx context.Context, fine *repository.Fine) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFine", ctx, fine)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateFine indicates an expected call of CreateFine.
+func (mr *MockFineRepoMockRecorder) CreateFine(ctx, fine any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFine", reflect.TypeOf((*MockFineRepo)(nil).CreateFine), ctx, fine)
+}
+
+// DeleteFine mocks base method.
+func (m *MockFineRepo) DeleteFine(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFine", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFine indicates an expected call of DeleteFine.
+func (mr *MockFineRepoMockRecorder) DeleteFine(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFine", reflect.TypeOf((*MockFineRepo)(nil).DeleteFine), ctx, id)
+}
+
+// GetFineByID mocks base method.
+func (m *MockFineRepo) GetFineByID(ctx context.Context, id uint) (*repository.Fine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFineByID", ctx, id)
+	ret0, _ := ret[0].(*repository.Fine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFineByID indicates an expected call of GetFineByID.
+func (mr *MockFineRepoMockRecorder) GetFineByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFineByID", reflect.TypeOf((*MockFineRepo)(nil).GetFineByID), ctx, id)
+}
+
+// GetFinesByTenant mocks base method.
+func (m *MockFineRepo) GetFinesByTenant(ctx context.Context, tenantID uint) ([]repository.Fine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFinesByTenant", ctx, tenantID)
+	ret0, _ := ret[0].([]repository.Fine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFinesByTenant indicates an expected call of GetFinesByTenant.
+func (mr *MockFineRepoMockRecorder) GetFinesByTenant(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFinesByTenant", reflect.TypeOf((*MockFineRepo)(nil).GetFinesByTenant), ctx, tenantID)
+}
+
+// UpdateFine mocks base method.
+func (m *MockFineRepo) UpdateFine(ctx context.Context, fine *repository.Fine) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFine", ctx, fine)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateFine indicates an expected call of UpdateFine.
+func (mr *MockFineRepoMockRecorder) UpdateFine(ctx, fine any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFine", reflect.TypeOf((*MockFineRepo)(nil).UpdateFine), ctx, fine)
+}
+
+// MockExpenseRepo is a mock of ExpenseRepo interface.
+type MockExpenseRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockExpenseRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockExpenseRepoMockRecorder is the mock recorder for MockExpenseRepo.
+type MockExpenseRepoMockRecorder struct {
+	mock *MockExpenseRepo
+}
+
+// NewMockExpenseRepo creates a new mock instance.
+func NewMockExpenseRepo(ctrl *gomock.Controller) *MockExpenseRepo {
+	mock := &MockExpenseRepo{ctrl: ctrl}
+	mock.recorder = &MockExpenseRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExpenseRepo) EXPECT() *MockExpenseRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateExpense mocks base method.
+func (m *MockExpenseRepo) CreateExpense(ctx context.Context, expense *repository.Expense) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExpense", ctx, expense)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateExpense indicates an expected call of CreateExpense.
+func (mr *MockExpenseRepoMockRecorder) CreateExpense(ctx, expense any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExpense", reflect.TypeOf((*MockExpenseRepo)(nil).CreateExpense), ctx, expense)
+}
+
+// DeleteExpense mocks base method.
+func (m *MockExpenseRepo) DeleteExpense(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpense", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpense indicates an expected call of DeleteExpense.
+func (mr *MockExpenseRepoMockRecorder) DeleteExpense(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpense", reflect.TypeOf((*MockExpenseRepo)(nil).DeleteExpense), ctx, id)
+}
+
+// GetExpenseByID mocks base method.
+func (m *MockExpenseRepo) GetExpenseByID(ctx context.Context, id uint) (*repository.Expense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpenseByID", ctx, id)
+	ret0, _ := ret[0].(*repository.Expense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpenseByID indicates an expected call of GetExpenseByID.
+func (mr *MockExpenseRepoMockRecorder) GetExpenseByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpenseByID", reflect.TypeOf((*MockExpenseRepo)(nil).GetExpenseByID), ctx, id)
+}
+
+// GetExpenseExportRows mocks base method.
+func (m *MockExpenseRepo) GetExpenseExportRows(ctx context.Context, tenantID uint) ([]repository.ExpenseExportRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpenseExportRows", ctx, tenantID)
+	ret0, _ := ret[0].([]repository.ExpenseExportRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpenseExportRows indicates an expected call of GetExpenseExportRows.
+func (mr *MockExpenseRepoMockRecorder) GetExpenseExportRows(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpenseExportRows", reflect.TypeOf((*MockExpenseRepo)(nil).GetExpenseExportRows), ctx, tenantID)
+}
+
+// GetExpensesByReport mocks base method.
+func (m *MockExpenseRepo) GetExpensesByReport(ctx context.Context, reportID uint) ([]repository.Expense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpensesByReport", ctx, reportID)
+	ret0, _ := ret[0].([]repository.Expense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpensesByReport indicates an expected call of GetExpensesByReport.
+func (mr *MockExpenseRepoMockRecorder) GetExpensesByReport(ctx, reportID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpensesByReport", reflect.TypeOf((*MockExpenseRepo)(nil).GetExpensesByReport), ctx, reportID)
+}
+
+// GetExpensesByTenant mocks base method.
+func (m *MockExpenseRepo) GetExpensesByTenant(ctx context.Context, tenantID uint) ([]repository.Expense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpensesByTenant", ctx, tenantID)
+	ret0, _ := ret[0].([]repository.Expense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpensesByTenant indicates an expected call of GetExpensesByTenant.
+func (mr *MockExpenseRepoMockRecorder) GetExpensesByTenant(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpensesByTenant", reflect.TypeOf((*MockExpenseRepo)(nil).GetExpensesByTenant), ctx, tenantID)
+}
+
+// GetExpensesPage mocks base method.
+func (m *MockExpenseRepo) GetExpensesPage(ctx context.Context, tenantID uint, after *repository.Keyset, limit int) ([]repository.Expense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpensesPage", ctx, tenantID, after, limit)
+	ret0, _ := ret[0].([]repository.Expense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpensesPage indicates an expected call of GetExpensesPage.
+func (mr *MockExpenseRepoMockRecorder) GetExpensesPage(ctx, tenantID, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpensesPage", reflect.TypeOf((*MockExpenseRepo)(nil).GetExpensesPage), ctx, tenantID, after, limit)
+}
+
+// GetPendingExpenses mocks base method.
+func (m *MockExpenseRepo) GetPendingExpenses(ctx context.Context, tenantID uint) ([]repository.Expense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingExpenses", ctx, tenantID)
+	ret0, _ := ret[0].([]repository.Expense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingExpenses indicates an expected call of GetPendingExpenses.
+func (mr *MockExpenseRepoMockRecorder) GetPendingExpenses(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingExpenses", reflect.TypeOf((*MockExpenseRepo)(nil).GetPendingExpenses), ctx, tenantID)
+}
+
+// SearchExpenses mocks base method.
+func (m *MockExpenseRepo) SearchExpenses(ctx context.Context, tenantID uint, query string, limit int) ([]repository.Expense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchExpenses", ctx, tenantID, query, limit)
+	ret0, _ := ret[0].([]repository.Expense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchExpenses indicates an expected call of SearchExpenses.
+func (mr *MockExpenseRepoMockRecorder) SearchExpenses(ctx, tenantID, query, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchExpenses", reflect.TypeOf((*MockExpenseRepo)(nil).SearchExpenses), ctx, tenantID, query, limit)
+}
+
+// SummarizeExpenses mocks base method.
+func (m *MockExpenseRepo) SummarizeExpenses(ctx context.Context, tenantID uint, groupBy string) ([]repository.GroupTotal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SummarizeExpenses", ctx, tenantID, groupBy)
+	ret0, _ := ret[0].([]repository.GroupTotal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SummarizeExpenses indicates an expected call of SummarizeExpenses.
+func (mr *MockExpenseRepoMockRecorder) SummarizeExpenses(ctx, tenantID, groupBy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SummarizeExpenses", reflect.TypeOf((*MockExpenseRepo)(nil).SummarizeExpenses), ctx, tenantID, groupBy)
+}
+
+// UpdateExpense mocks base method.
+func (m *MockExpenseRepo) UpdateExpense(ctx context.Context, expense *repository.Expense) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateExpense", ctx, expense)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateExpense indicates an expected call of UpdateExpense.
+func (mr *MockExpenseRepoMockRecorder) UpdateExpense(ctx, expense any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateExpense", reflect.TypeOf((*MockExpenseRepo)(nil).UpdateExpense), ctx, expense)
+}
+
+// MockDepositRepo is a mock of DepositRepo interface.
+type MockDepositRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockDepositRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockDepositRepoMockRecorder is the mock recorder for MockDepositRepo.
+type MockDepositRepoMockRecorder struct {
+	mock *MockDepositRepo
+}
+
+// NewMockDepositRepo creates a new mock instance.
+func NewMockDepositRepo(ctrl *gomock.Controller) *MockDepositRepo {
+	mock := &MockDepositRepo{ctrl: ctrl}
+	mock.recorder = &MockDepositRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDepositRepo) EXPECT() *MockDepositRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateDeposit mocks base method.
+func (m *MockDepositRepo) CreateDeposit(ctx context.Context, deposit *repository.BankDeposit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDeposit", ctx, deposit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDeposit indicates an expected call of CreateDeposit.
+func (mr *MockDepositRepoMockRecorder) CreateDeposit(ctx, deposit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDeposit", reflect.TypeOf((*MockDepositRepo)(nil).CreateDeposit), ctx, deposit)
+}
+
+// DeleteDeposit mocks base method.
+func (m *MockDepositRepo) DeleteDeposit(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDeposit", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDeposit indicates an expected call of DeleteDeposit.
+func (mr *MockDepositRepoMockRecorder) DeleteDeposit(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeposit", reflect.TypeOf((*MockDepositRepo)(nil).DeleteDeposit), ctx, id)
+}
+
+// GetDepositByID mocks base method.
+func (m *MockDepositRepo) GetDepositByID(ctx context.Context, id uint) (*repository.BankDeposit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDepositByID", ctx, id)
+	ret0, _ := ret[0].(*repository.BankDeposit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDepositByID indicates an expected call of GetDepositByID.
+func (mr *MockDepositRepoMockRecorder) GetDepositByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDepositByID", reflect.TypeOf((*MockDepositRepo)(nil).GetDepositByID), ctx, id)
+}
+
+// GetDepositsByTenant mocks base method.
+func (m *MockDepositRepo) GetDepositsByTenant(ctx context.Context, tenantID uint) ([]repository.BankDeposit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDepositsByTenant", ctx, tenantID)
+	ret0, _ := ret[0].([]repository.BankDeposit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDepositsByTenant indicates an expected call of GetDepositsByTenant.
+func (mr *MockDepositRepoMockRecorder) GetDepositsByTenant(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDepositsByTenant", reflect.TypeOf((*MockDepositRepo)(nil).GetDepositsByTenant), ctx, tenantID)
+}
+
+// UpdateDeposit mocks base method.
+func (m *MockDepositRepo) UpdateDeposit(ctx context.Context, deposit *repository.BankDeposit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDeposit", ctx, deposit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDeposit indicates an expected call of UpdateDeposit.
+func (mr *MockDepositRepoMockRecorder) UpdateDeposit(ctx, deposit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDeposit", reflect.TypeOf((*MockDepositRepo)(nil).UpdateDeposit), ctx, deposit)
+}
+
+// MockBudgetRepo is a mock of BudgetRepo interface.
+type MockBudgetRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockBudgetRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockBudgetRepoMockRecorder is the mock recorder for MockBudgetRepo.
+type MockBudgetRepoMockRecorder struct {
+	mock *MockBudgetRepo
+}
+
+// NewMockBudgetRepo creates a new mock instance.
+func NewMockBudgetRepo(ctrl *gomock.Controller) *MockBudgetRepo {
+	mock := &MockBudgetRepo{ctrl: ctrl}
+	mock.recorder = &MockBudgetRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBudgetRepo) EXPECT() *MockBudgetRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateBudget mocks base method.
+func (m *MockBudgetRepo) CreateBudget(ctx context.Context, budget *repository.Budget) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBudget", ctx, budget)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateBudget indicates an expected call of CreateBudget.
+func (mr *MockBudgetRepoMockRecorder) CreateBudget(ctx, budget any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBudget", reflect.TypeOf((*MockBudgetRepo)(nil).CreateBudget), ctx, budget)
+}
+
+// DeleteBudget mocks base method.
+func (m *MockBudgetRepo) DeleteBudget(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBudget", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBudget indicates an expected call of DeleteBudget.
+func (mr *MockBudgetRepoMockRecorder) DeleteBudget(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBudget", reflect.TypeOf((*MockBudgetRepo)(nil).DeleteBudget), ctx, id)
+}
+
+// GetBudgetByID mocks base method.
+func (m *MockBudgetRepo) GetBudgetByID(ctx context.Context, id uint) (*repository.Budget, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBudgetByID", ctx, id)
+	ret0, _ := ret[0].(*repository.Budget)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBudgetByID indicates an expected call of GetBudgetByID.
+func (mr *MockBudgetRepoMockRecorder) GetBudgetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBudgetByID", reflect.TypeOf((*MockBudgetRepo)(nil).GetBudgetByID), ctx, id)
+}
+
+// GetBudgetSpending mocks base method.
+func (m *MockBudgetRepo) GetBudgetSpending(ctx context.Context, tenantID uint, from, to time.Time) (map[uint]money.Amount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBudgetSpending", ctx, tenantID, from, to)
+	ret0, _ := ret[0].(map[uint]money.Amount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBudgetSpending indicates an expected call of GetBudgetSpending.
+func (mr *MockBudgetRepoMockRecorder) GetBudgetSpending(ctx, tenantID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBudgetSpending", reflect.TypeOf((*MockBudgetRepo)(nil).GetBudgetSpending), ctx, tenantID, from, to)
+}
+
+// GetBudgets mocks base method.
+func (m *MockBudgetRepo) GetBudgets(ctx context.Context, tenantID uint) ([]repository.Budget, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBudgets", ctx, tenantID)
+	ret0, _ := ret[0].([]repository.Budget)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBudgets indicates an expected call of GetBudgets.
+func (mr *MockBudgetRepoMockRecorder) GetBudgets(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBudgets", reflect.TypeOf((*MockBudgetRepo)(nil).GetBudgets), ctx, tenantID)
+}
+
+// UpdateBudget mocks base method.
+func (m *MockBudgetRepo) UpdateBudget(ctx context.Context, budget *repository.Budget) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBudget", ctx, budget)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBudget indicates an expected call of UpdateBudget.
+func (mr *MockBudgetRepoMockRecorder) UpdateBudget(ctx, budget any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBudget", reflect.TypeOf((*MockBudgetRepo)(nil).UpdateBudget), ctx, budget)
+}
+
+// MockPeriodRepo is a mock of PeriodRepo interface.
+type MockPeriodRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockPeriodRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockPeriodRepoMockRecorder is the mock recorder for MockPeriodRepo.
+type MockPeriodRepoMockRecorder struct {
+	mock *MockPeriodRepo
+}
+
+// NewMockPeriodRepo creates a new mock instance.
+func NewMockPeriodRepo(ctrl *gomock.Controller) *MockPeriodRepo {
+	mock := &MockPeriodRepo{ctrl: ctrl}
+	mock.recorder = &MockPeriodRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPeriodRepo) EXPECT() *MockPeriodRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateClosedPeriod mocks base method.
+func (m *MockPeriodRepo) CreateClosedPeriod(ctx context.Context, period *repository.ClosedPeriod) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateClosedPeriod", ctx, period)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateClosedPeriod indicates an expected call of CreateClosedPeriod.
+func (mr *MockPeriodRepoMockRecorder) CreateClosedPeriod(ctx, period any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateClosedPeriod", reflect.TypeOf((*MockPeriodRepo)(nil).CreateClosedPeriod), ctx, period)
+}
+
+// DeleteClosedPeriod mocks base method.
+func (m *MockPeriodRepo) DeleteClosedPeriod(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteClosedPeriod", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteClosedPeriod indicates an expected call of DeleteClosedPeriod.
+func (mr *MockPeriodRepoMockRecorder) DeleteClosedPeriod(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteClosedPeriod", reflect.TypeOf((*MockPeriodRepo)(nil).DeleteClosedPeriod), ctx, id)
+}
+
+// GetClosedPeriod mocks base method.
+func (m *MockPeriodRepo) GetClosedPeriod(ctx context.Context, tenantID uint, month time.Time) (*repository.ClosedPeriod, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClosedPeriod", ctx, tenantID, month)
+	ret0, _ := ret[0].(*repository.ClosedPeriod)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClosedPeriod indicates an expected call of GetClosedPeriod.
+func (mr *MockPeriodRepoMockRecorder) GetClosedPeriod(ctx, tenantID, month any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClosedPeriod", reflect.TypeOf((*MockPeriodRepo)(nil).GetClosedPeriod), ctx, tenantID, month)
+}
+
+// GetClosedPeriods mocks base method.
+func (m *MockPeriodRepo) GetClosedPeriods(ctx context.Context, tenantID uint) ([]repository.ClosedPeriod, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClosedPeriods", ctx, tenantID)
+	ret0, _ := ret[0].([]repository.ClosedPeriod)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClosedPeriods indicates an expected call of GetClosedPeriods.
+func (mr *MockPeriodRepoMockRecorder) GetClosedPeriods(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClosedPeriods", reflect.TypeOf((*MockPeriodRepo)(nil).GetClosedPeriods), ctx, tenantID)
+}
+
+// IsPeriodClosed mocks base method.
+func (m *MockPeriodRepo) IsPeriodClosed(ctx context.Context, tenantID uint, day time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsPeriodClosed", ctx, tenantID, day)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsPeriodClosed indicates an expected call of IsPeriodClosed.
+func (mr *MockPeriodRepoMockRecorder) IsPeriodClosed(ctx, tenantID, day any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsPeriodClosed", reflect.TypeOf((*MockPeriodRepo)(nil).IsPeriodClosed), ctx, tenantID, day)
+}
+
+// MockBankAccountRepo is a mock of BankAccountRepo interface.
+type MockBankAccountRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockBankAccountRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockBankAccountRepoMockRecorder is the mock recorder for MockBankAccountRepo.
+type MockBankAccountRepoMockRecorder struct {
+	mock *MockBankAccountRepo
+}
+
+// NewMockBankAccountRepo creates a new mock instance.
+func NewMockBankAccountRepo(ctrl *gomock.Controller) *MockBankAccountRepo {
+	mock := &MockBankAccountRepo{ctrl: ctrl}
+	mock.recorder = &MockBankAccountRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBankAccountRepo) EXPECT() *MockBankAccountRepoMockRecorder {
+	return m.recorder
+}
+
+// BankAccountInUse mocks base method.
+func (m *MockBankAccountRepo) BankAccountInUse(ctx context.Context, id uint) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BankAccountInUse", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BankAccountInUse indicates an expected call of BankAccountInUse.
+func (mr *MockBankAccountRepoMockRecorder) BankAccountInUse(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BankAccountInUse", reflect.TypeOf((*MockBankAccountRepo)(nil).BankAccountInUse), ctx, id)
+}
+
+// CreateBankAccount mocks base method.
+func (m *MockBankAccountRepo) CreateBankAccount(ctx context.Context, account *repository.BankAccount) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBankAccount", ctx, account)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateBankAccount indicates an expected call of CreateBankAccount.
+func (mr *MockBankAccountRepoMockRecorder) CreateBankAccount(ctx, account any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBankAccount", reflect.TypeOf((*MockBankAccountRepo)(nil).CreateBankAccount), ctx, account)
+}
+
+// DeleteBankAccount mocks base method.
+func (m *MockBankAccountRepo) DeleteBankAccount(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBankAccount", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBankAccount indicates an expected call of DeleteBankAccount.
+func (mr *MockBankAccountRepoMockRecorder) DeleteBankAccount(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBankAccount", reflect.TypeOf((*MockBankAccountRepo)(nil).DeleteBankAccount), ctx, id)
+}
+
+// GetBankAccountByID mocks base method.
+func (m *MockBankAccountRepo) GetBankAccountByID(ctx context.Context, id uint) (*repository.BankAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBankAccountByID", ctx, id)
+	ret0, _ := ret[0].(*repository.BankAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBankAccountByID indicates an expected call of GetBankAccountByID.
+func (mr *MockBankAccountRepoMockRecorder) GetBankAccountByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBankAccountByID", reflect.TypeOf((*MockBankAccountRepo)(nil).GetBankAccountByID), ctx, id)
+}
+
+// GetBankAccountsByTenant mocks base method.
+func (m *MockBankAccountRepo) GetBankAccountsByTenant(ctx context.Context, tenantID uint) ([]repository.BankAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBankAccountsByTenant", ctx, tenantID)
+	ret0, _ := ret[0].([]repository.BankAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBankAccountsByTenant indicates an expected call of GetBankAccountsByTenant.
+func (mr *MockBankAccountRepoMockRecorder) GetBankAccountsByTenant(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBankAccountsByTenant", reflect.TypeOf((*MockBankAccountRepo)(nil).GetBankAccountsByTenant), ctx, tenantID)
+}
+
+// GetDepositTotalsByAccount mocks base method.
+func (m *MockBankAccountRepo) GetDepositTotalsByAccount(ctx context.Context, tenantID uint) ([]repository.BankAccountTotal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDepositTotalsByAccount", ctx, tenantID)
+	ret0, _ := ret[0].([]repository.BankAccountTotal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDepositTotalsByAccount indicates an expected call of GetDepositTotalsByAccount.
+func (mr *MockBankAccountRepoMockRecorder) GetDepositTotalsByAccount(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDepositTotalsByAccount", reflect.TypeOf((*MockBankAccountRepo)(nil).GetDepositTotalsByAccount), ctx, tenantID)
+}
+
+// UpdateBankAccount mocks base method.
+func (m *MockBankAccountRepo) UpdateBankAccount(ctx context.Context, account *repository.BankAccount) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBankAccount", ctx, account)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBankAccount indicates an expected call of UpdateBankAccount.
+func (mr *MockBankAccountRepoMockRecorder) UpdateBankAccount(ctx, account any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBankAccount", reflect.TypeOf((*MockBankAccountRepo)(nil).UpdateBankAccount), ctx, account)
+}
+
+// MockSessionRepo is a mock of SessionRepo interface.
+type MockSessionRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockSessionRepoMockRecorder is the mock recorder for MockSessionRepo.
+type MockSessionRepoMockRecorder struct {
+	mock *MockSessionRepo
+}
+
+// NewMockSessionRepo creates a new mock instance.
+func NewMockSessionRepo(ctrl *gomock.Controller) *MockSessionRepo {
+	mock := &MockSessionRepo{ctrl: ctrl}
+	mock.recorder = &MockSessionRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSessionRepo) EXPECT() *MockSessionRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateSession mocks base method.
+func (m *MockSessionRepo) CreateSession(ctx context.Context, session *repository.Session) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", ctx, session)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockSessionRepoMockRecorder) CreateSession(ctx, session any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockSessionRepo)(nil).CreateSession), ctx, session)
+}
+
+// DeleteSession mocks base method.
+func (m *MockSessionRepo) DeleteSession(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSession", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSession indicates an expected call of DeleteSession.
+func (mr *MockSessionRepoMockRecorder) DeleteSession(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSession", reflect.TypeOf((*MockSessionRepo)(nil).DeleteSession), ctx, token)
+}
+
+// DeleteStaleSessions mocks base method.
+func (m *MockSessionRepo) DeleteStaleSessions(ctx context.Context, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteStaleSessions", ctx, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteStaleSessions indicates an expected call of DeleteStaleSessions.
+func (mr *MockSessionRepoMockRecorder) DeleteStaleSessions(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStaleSessions", reflect.TypeOf((*MockSessionRepo)(nil).DeleteStaleSessions), ctx, now)
+}
+
+// DeleteUserSessions mocks base method.
+func (m *MockSessionRepo) DeleteUserSessions(ctx context.Context, userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserSessions", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserSessions indicates an expected call of DeleteUserSessions.
+func (mr *MockSessionRepoMockRecorder) DeleteUserSessions(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserSessions", reflect.TypeOf((*MockSessionRepo)(nil).DeleteUserSessions), ctx, userID)
+}
+
+// GetSessionByToken mocks base method.
+func (m *MockSessionRepo) GetSessionByToken(ctx context.Context, token string) (*repository.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSessionByToken", ctx, token)
+	ret0, _ := ret[0].(*repository.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSessionByToken indicates an expected call of GetSessionByToken.
+func (mr *MockSessionRepoMockRecorder) GetSessionByToken(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessionByToken", reflect.TypeOf((*MockSessionRepo)(nil).GetSessionByToken), ctx, token)
+}
+
+// MockDeviceTokenRepo is a mock of DeviceTokenRepo interface.
+type MockDeviceTokenRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeviceTokenRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockDeviceTokenRepoMockRecorder is the mock recorder for MockDeviceTokenRepo.
+type MockDeviceTokenRepoMockRecorder struct {
+	mock *MockDeviceTokenRepo
+}
+
+// NewMockDeviceTokenRepo creates a new mock instance.
+func NewMockDeviceTokenRepo(ctrl *gomock.Controller) *MockDeviceTokenRepo {
+	mock := &MockDeviceTokenRepo{ctrl: ctrl}
+	mock.recorder = &MockDeviceTokenRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeviceTokenRepo) EXPECT() *MockDeviceTokenRepoMockRecorder {
+	return m.recorder
+}
+
+// DeleteDeviceToken mocks base method.
+func (m *MockDeviceTokenRepo) DeleteDeviceToken(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDeviceToken", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDeviceToken indicates an expected call of DeleteDeviceToken.
+func (mr *MockDeviceTokenRepoMockRecorder) DeleteDeviceToken(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeviceToken", reflect.TypeOf((*MockDeviceTokenRepo)(nil).DeleteDeviceToken), ctx, token)
+}
+
+// DeleteUserDeviceToken mocks base method.
+func (m *MockDeviceTokenRepo) DeleteUserDeviceToken(ctx context.Context, userID uint, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserDeviceToken", ctx, userID, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserDeviceToken indicates an expected call of DeleteUserDeviceToken.
+func (mr *MockDeviceTokenRepoMockRecorder) DeleteUserDeviceToken(ctx, userID, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserDeviceToken", reflect.TypeOf((*MockDeviceTokenRepo)(nil).DeleteUserDeviceToken), ctx, userID, token)
+}
+
+// GetDeviceTokensByUser mocks base method.
+func (m *MockDeviceTokenRepo) GetDeviceTokensByUser(ctx context.Context, userID uint) ([]repository.DeviceToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeviceTokensByUser", ctx, userID)
+	ret0, _ := ret[0].([]repository.DeviceToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeviceTokensByUser indicates an expected call of GetDeviceTokensByUser.
+func (mr *MockDeviceTokenRepoMockRecorder) GetDeviceTokensByUser(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeviceTokensByUser", reflect.TypeOf((*MockDeviceTokenRepo)(nil).GetDeviceTokensByUser), ctx, userID)
+}
+
+// SaveDeviceToken mocks base method.
+func (m *MockDeviceTokenRepo) SaveDeviceToken(ctx context.Context, device *repository.DeviceToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveDeviceToken", ctx, device)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveDeviceToken indicates an expected call of SaveDeviceToken.
+func (mr *MockDeviceTokenRepoMockRecorder) SaveDeviceToken(ctx, device any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDeviceToken", reflect.TypeOf((*MockDeviceTokenRepo)(nil).SaveDeviceToken), ctx, device)
+}
+
+// MockMaintenanceRepo is a mock of MaintenanceRepo interface.
+type MockMaintenanceRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockMaintenanceRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockMaintenanceRepoMockRecorder is the mock recorder for MockMaintenanceRepo.
+type MockMaintenanceRepoMockRecorder struct {
+	mock *MockMaintenanceRepo
+}
+
+// NewMockMaintenanceRepo creates a new mock instance.
+func NewMockMaintenanceRepo(ctrl *gomock.Controller) *MockMaintenanceRepo {
+	mock := &MockMaintenanceRepo{ctrl: ctrl}
+	mock.recorder = &MockMaintenanceRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMaintenanceRepo) EXPECT() *MockMaintenanceRepoMockRecorder {
+	return m.recorder
+}
+
+// AddMaintenanceLogCost mocks base method.
+func (m *MockMaintenanceRepo) AddMaintenanceLogCost(ctx context.Context, id uint, amount money.Amount) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMaintenanceLogCost", ctx, id, amount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddMaintenanceLogCost indicates an expected call of AddMaintenanceLogCost.
+func (mr *MockMaintenanceRepoMockRecorder) AddMaintenanceLogCost(ctx, id, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMaintenanceLogCost", reflect.TypeOf((*MockMaintenanceRepo)(nil).AddMaintenanceLogCost), ctx, id, amount)
+}
+
+// CreateMaintenanceLog mocks base method.
+func (m *MockMaintenanceRepo) CreateMaintenanceLog(ctx context.Context, log *repository.MaintenanceLog) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMaintenanceLog", ctx, log)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateMaintenanceLog indicates an expected call of CreateMaintenanceLog.
+func (mr *MockMaintenanceRepoMockRecorder) CreateMaintenanceLog(ctx, log any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMaintenanceLog", reflect.TypeOf((*MockMaintenanceRepo)(nil).CreateMaintenanceLog), ctx, log)
+}
+
+// CreateMaintenanceSchedule mocks base method.
+func (m *MockMaintenanceRepo) CreateMaintenanceSchedule(ctx context.Context, schedule *repository.MaintenanceSchedule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMaintenanceSchedule", ctx, schedule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateMaintenanceSchedule indicates an expected call of CreateMaintenanceSchedule.
+func (mr *MockMaintenanceRepoMockRecorder) CreateMaintenanceSchedule(ctx, schedule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMaintenanceSchedule", reflect.TypeOf((*MockMaintenanceRepo)(nil).CreateMaintenanceSchedule), ctx, schedule)
+}
+
+// DeleteMaintenanceSchedule mocks base method.
+func (m *MockMaintenanceRepo) DeleteMaintenanceSchedule(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMaintenanceSchedule", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMaintenanceSchedule indicates an expected call of DeleteMaintenanceSchedule.
+func (mr *MockMaintenanceRepoMockRecorder) DeleteMaintenanceSchedule(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMaintenanceSchedule", reflect.TypeOf((*MockMaintenanceRepo)(nil).DeleteMaintenanceSchedule), ctx, id)
+}
+
+// GetAllMaintenanceSchedules mocks base method.
+func (m *MockMaintenanceRepo) GetAllMaintenanceSchedules(ctx context.Context) ([]repository.MaintenanceSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllMaintenanceSchedules", ctx)
+	ret0, _ := ret[0].([]repository.MaintenanceSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllMaintenanceSchedules indicates an expected call of GetAllMaintenanceSchedules.
+func (mr *MockMaintenanceRepoMockRecorder) GetAllMaintenanceSchedules(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllMaintenanceSchedules", reflect.TypeOf((*MockMaintenanceRepo)(nil).GetAllMaintenanceSchedules), ctx)
+}
+
+// GetMaintenanceLogByID mocks base method.
+func (m *MockMaintenanceRepo) GetMaintenanceLogByID(ctx context.Context, id uint) (*repository.MaintenanceLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMaintenanceLogByID", ctx, id)
+	ret0, _ := ret[0].(*repository.MaintenanceLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMaintenanceLogByID indicates an expected call of GetMaintenanceLogByID.
+func (mr *MockMaintenanceRepoMockRecorder) GetMaintenanceLogByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaintenanceLogByID", reflect.TypeOf((*MockMaintenanceRepo)(nil).GetMaintenanceLogByID), ctx, id)
+}
+
+// GetMaintenanceLogsByTaxi mocks base method.
+func (m *MockMaintenanceRepo) GetMaintenanceLogsByTaxi(ctx context.Context, taxiID uint) ([]repository.MaintenanceLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMaintenanceLogsByTaxi", ctx, taxiID)
+	ret0, _ := ret[0].([]repository.MaintenanceLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMaintenanceLogsByTaxi indicates an expected call of GetMaintenanceLogsByTaxi.
+func (mr *MockMaintenanceRepoMockRecorder) GetMaintenanceLogsByTaxi(ctx, taxiID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaintenanceLogsByTaxi", reflect.TypeOf((*MockMaintenanceRepo)(nil).GetMaintenanceLogsByTaxi), ctx, taxiID)
+}
+
+// GetMaintenanceLogsByTenant mocks base method.
+func (m *MockMaintenanceRepo) GetMaintenanceLogsByTenant(ctx context.Context, tenantID uint) ([]repository.MaintenanceLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMaintenanceLogsByTenant", ctx, tenantID)
+	ret0, _ := ret[0].([]repository.MaintenanceLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMaintenanceLogsByTenant indicates an expected call of GetMaintenanceLogsByTenant.
+func (mr *MockMaintenanceRepoMockRecorder) GetMaintenanceLogsByTenant(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaintenanceLogsByTenant", reflect.TypeOf((*MockMaintenanceRepo)(nil).GetMaintenanceLogsByTenant), ctx, tenantID)
+}
+
+// GetMaintenanceScheduleByID mocks base method.
+func (m *MockMaintenanceRepo) GetMaintenanceScheduleByID(ctx context.Context, id uint) (*repository.MaintenanceSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMaintenanceScheduleByID", ctx, id)
+	ret0, _ := ret[0].(*repository.MaintenanceSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMaintenanceScheduleByID indicates an expected call of GetMaintenanceScheduleByID.
+func (mr *MockMaintenanceRepoMockRecorder) GetMaintenanceScheduleByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaintenanceScheduleByID", reflect.TypeOf((*MockMaintenanceRepo)(nil).GetMaintenanceScheduleByID), ctx, id)
+}
+
+// GetMaintenanceSchedulesByTenant mocks base method.
+func (m *MockMaintenanceRepo) GetMaintenanceSchedulesByTenant(ctx context.Context, tenantID uint) ([]repository.MaintenanceSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMaintenanceSchedulesByTenant", ctx, tenantID)
+	ret0, _ := ret[0].([]repository.MaintenanceSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMaintenanceSchedulesByTenant indicates an expected call of GetMaintenanceSchedulesByTenant.
+func (mr *MockMaintenanceRepoMockRecorder) GetMaintenanceSchedulesByTenant(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaintenanceSchedulesByTenant", reflect.TypeOf((*MockMaintenanceRepo)(nil).GetMaintenanceSchedulesByTenant), ctx, tenantID)
+}
+
+// UpdateMaintenanceLog mocks base method.
+func (m *MockMaintenanceRepo) UpdateMaintenanceLog(ctx context.Context, log *repository.MaintenanceLog) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMaintenanceLog", ctx, log)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMaintenanceLog indicates an expected call of UpdateMaintenanceLog.
+func (mr *MockMaintenanceRepoMockRecorder) UpdateMaintenanceLog(ctx, log any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMaintenanceLog", reflect.TypeOf((*MockMaintenanceRepo)(nil).UpdateMaintenanceLog), ctx, log)
+}
+
+// UpdateMaintenanceSchedule mocks base method.
+func (m *MockMaintenanceRepo) UpdateMaintenanceSchedule(ctx context.Context, schedule *repository.MaintenanceSchedule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMaintenanceSchedule", ctx, schedule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMaintenanceSchedule indicates an expected call of UpdateMaintenanceSchedule.
+func (mr *MockMaintenanceRepoMockRecorder) UpdateMaintenanceSchedule(ctx, schedule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMaintenanceSchedule", reflect.TypeOf((*MockMaintenanceRepo)(nil).UpdateMaintenanceSchedule), ctx, schedule)
+}
+
+// MockInventoryRepo is a mock of InventoryRepo interface.
+type MockInventoryRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockInventoryRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockInventoryRepoMockRecorder is the mock recorder for MockInventoryRepo.
+type MockInventoryRepoMockRecorder struct {
+	mock *MockInventoryRepo
+}
+
+// NewMockInventoryRepo creates a new mock instance.
+func NewMockInventoryRepo(ctrl *gomock.Controller) *MockInventoryRepo {
+	mock := &MockInventoryRepo{ctrl: ctrl}
+	mock.recorder = &MockInventoryRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInventoryRepo) EXPECT() *MockInventoryRepoMockRecorder {
+	return m.recorder
+}
+
+// CreatePart mocks base method.
+func (m *MockInventoryRepo) CreatePart(ctx context.Context, part *repository.Part) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePart", ctx, part)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePart indicates an expected call of CreatePart.
+func (mr *MockInventoryRepoMockRecorder) CreatePart(ctx, part any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePart", reflect.TypeOf((*MockInventoryRepo)(nil).CreatePart), ctx, part)
+}
+
+// CreateStockMovements mocks base method.
+func (m *MockInventoryRepo) CreateStockMovements(ctx context.Context, movements []repository.StockMovement) ([]repository.Part, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStockMovements", ctx, movements)
+	ret0, _ := ret[0].([]repository.Part)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateStockMovements indicates an expected call of CreateStockMovements.
+func (mr *MockInventoryRepoMockRecorder) CreateStockMovements(ctx, movements any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStockMovements", reflect.TypeOf((*MockInventoryRepo)(nil).CreateStockMovements), ctx, movements)
+}
+
+// DeletePart mocks base method.
+func (m *MockInventoryRepo) DeletePart(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePart", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePart indicates an expected call of DeletePart.
+func (mr *MockInventoryRepoMockRecorder) DeletePart(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePart", reflect.TypeOf((*MockInventoryRepo)(nil).DeletePart), ctx, id)
+}
+
+// GetLowStockParts mocks base method.
+func (m *MockInventoryRepo) GetLowStockParts(ctx context.Context, tenantID uint) ([]repository.Part, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLowStockParts", ctx, tenantID)
+	ret0, _ := ret[0].([]repository.Part)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLowStockParts indicates an expected call of GetLowStockParts.
+func (mr *MockInventoryRepoMockRecorder) GetLowStockParts(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLowStockParts", reflect.TypeOf((*MockInventoryRepo)(nil).GetLowStockParts), ctx, tenantID)
+}
+
+// GetPartByID mocks base method.
+func (m *MockInventoryRepo) GetPartByID(ctx context.Context, id uint) (*repository.Part, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPartByID", ctx, id)
+	ret0, _ := ret[0].(*repository.Part)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPartByID indicates an expected call of GetPartByID.
+func (mr *MockInventoryRepoMockRecorder) GetPartByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPartByID", reflect.TypeOf((*MockInventoryRepo)(nil).GetPartByID), ctx, id)
+}
+
+// GetPartsByTenant mocks base method.
+func (m *MockInventoryRepo) GetPartsByTenant(ctx context.Context, tenantID uint) ([]repository.Part, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPartsByTenant", ctx, tenantID)
+	ret0, _ := ret[0].([]repository.Part)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPartsByTenant indicates an expected call of GetPartsByTenant.
+func (mr *MockInventoryRepoMockRecorder) GetPartsByTenant(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPartsByTenant", reflect.TypeOf((*MockInventoryRepo)(nil).GetPartsByTenant), ctx, tenantID)
+}
+
+// GetStockMovementsByMaintenanceLog mocks base method.
+func (m *MockInventoryRepo) GetStockMovementsByMaintenanceLog(ctx context.Context, logID uint) ([]repository.StockMovement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStockMovementsByMaintenanceLog", ctx, logID)
+	ret0, _ := ret[0].([]repository.StockMovement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStockMovementsByMaintenanceLog indicates an expected call of GetStockMovementsByMaintenanceLog.
+func (mr *MockInventoryRepoMockRecorder) GetStockMovementsByMaintenanceLog(ctx, logID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStockMovementsByMaintenanceLog", reflect.TypeOf((*MockInventoryRepo)(nil).GetStockMovementsByMaintenanceLog), ctx, logID)
+}
+
+// GetStockMovementsByPart mocks base method.
+func (m *MockInventoryRepo) GetStockMovementsByPart(ctx context.Context, partID uint) ([]repository.StockMovement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStockMovementsByPart", ctx, partID)
+	ret0, _ := ret[0].([]repository.StockMovement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStockMovementsByPart indicates an expected call of GetStockMovementsByPart.
+func (mr *MockInventoryRepoMockRecorder) GetStockMovementsByPart(ctx, partID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStockMovementsByPart", reflect.TypeOf((*MockInventoryRepo)(nil).GetStockMovementsByPart), ctx, partID)
+}
+
+// UpdatePart mocks base method.
+func (m *MockInventoryRepo) UpdatePart(ctx context.Context, part *repository.Part) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePart", ctx, part)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePart indicates an expected call of UpdatePart.
+func (mr *MockInventoryRepoMockRecorder) UpdatePart(ctx, part any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePart", reflect.TypeOf((*MockInventoryRepo)(nil).UpdatePart), ctx, part)
+}
+
+// MockAssignmentRepo is a mock of AssignmentRepo interface.
+type MockAssignmentRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockAssignmentRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockAssignmentRepoMockRecorder is the mock recorder for MockAssignmentRepo.
+type MockAssignmentRepoMockRecorder struct {
+	mock *MockAssignmentRepo
+}
+
+// NewMockAssignmentRepo creates a new mock instance.
+func NewMockAssignmentRepo(ctrl *gomock.Controller) *MockAssignmentRepo {
+	mock := &MockAssignmentRepo{ctrl: ctrl}
+	mock.recorder = &MockAssignmentRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAssignmentRepo) EXPECT() *MockAssignmentRepoMockRecorder {
+	return m.recorder
+}
+
+// GetAssignmentAt mocks base method.
+func (m *MockAssignmentRepo) GetAssignmentAt(ctx context.Context, taxiID uint, at time.Time) (*repository.Assignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAssignmentAt", ctx, taxiID, at)
+	ret0, _ := ret[0].(*repository.Assignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAssignmentAt indicates an expected call of GetAssignmentAt.
+func (mr *MockAssignmentRepoMockRecorder) GetAssignmentAt(ctx, taxiID, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAssignmentAt", reflect.TypeOf((*MockAssignmentRepo)(nil).GetAssignmentAt), ctx, taxiID, at)
+}
+
+// GetAssignmentsByTaxi mocks base method.
+func (m *MockAssignmentRepo) GetAssignmentsByTaxi(ctx context.Context, taxiID uint) ([]repository.Assignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAssignmentsByTaxi", ctx, taxiID)
+	ret0, _ := ret[0].([]repository.Assignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAssignmentsByTaxi indicates an expected call of GetAssignmentsByTaxi.
+func (mr *MockAssignmentRepoMockRecorder) GetAssignmentsByTaxi(ctx, taxiID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAssignmentsByTaxi", reflect.TypeOf((*MockAssignmentRepo)(nil).GetAssignmentsByTaxi), ctx, taxiID)
+}
+
+// RecordAssignment mocks base method.
+func (m *MockAssignmentRepo) RecordAssignment(ctx context.Context, tenantID, taxiID uint, driverID, assignedByID *uint, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordAssignment", ctx, tenantID, taxiID, driverID, assignedByID, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordAssignment indicates an expected call of RecordAssignment.
+func (mr *MockAssignmentRepoMockRecorder) RecordAssignment(ctx, tenantID, taxiID, driverID, assignedByID, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordAssignment", reflect.TypeOf((*MockAssignmentRepo)(nil).RecordAssignment), ctx, tenantID, taxiID, driverID, assignedByID, at)
+}
+
+// MockAnalyticsRepo is a mock of AnalyticsRepo interface.
+type MockAnalyticsRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockAnalyticsRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockAnalyticsRepoMockRecorder is the mock recorder for MockAnalyticsRepo.
+type MockAnalyticsRepoMockRecorder struct {
+	mock *MockAnalyticsRepo
+}
+
+// NewMockAnalyticsRepo creates a new mock instance.
+func NewMockAnalyticsRepo(ctrl *gomock.Controller) *MockAnalyticsRepo {
+	mock := &MockAnalyticsRepo{ctrl: ctrl}
+	mock.recorder = &MockAnalyticsRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAnalyticsRepo) EXPECT() *MockAnalyticsRepoMockRecorder {
+	return m.recorder
+}
+
+// GetApprovedReportsInPeriod mocks base method.
+func (m *MockAnalyticsRepo) GetApprovedReportsInPeriod(ctx context.Context, tenantID uint, from, to time.Time) ([]repository.WeeklyReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApprovedReportsInPeriod", ctx, tenantID, from, to)
+	ret0, _ := ret[0].([]repository.WeeklyReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApprovedReportsInPeriod indicates an expected call of GetApprovedReportsInPeriod.
+func (mr *MockAnalyticsRepoMockRecorder) GetApprovedReportsInPeriod(ctx, tenantID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApprovedReportsInPeriod", reflect.TypeOf((*MockAnalyticsRepo)(nil).GetApprovedReportsInPeriod), ctx, tenantID, from, to)
+}
+
+// GetDailyApprovedEarnings mocks base method.
+func (m *MockAnalyticsRepo) GetDailyApprovedEarnings(ctx context.Context, tenantID uint, from, to time.Time) ([]repository.DailyAmount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDailyApprovedEarnings", ctx, tenantID, from, to)
+	ret0, _ := ret[0].([]repository.DailyAmount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDailyApprovedEarnings indicates an expected call of GetDailyApprovedEarnings.
+func (mr *MockAnalyticsRepoMockRecorder) GetDailyApprovedEarnings(ctx, tenantID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDailyApprovedEarnings", reflect.TypeOf((*MockAnalyticsRepo)(nil).GetDailyApprovedEarnings), ctx, tenantID, from, to)
+}
+
+// GetDailyExpenses mocks base method.
+func (m *MockAnalyticsRepo) GetDailyExpenses(ctx context.Context, tenantID uint, from, to time.Time) ([]repository.DailyAmount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDailyExpenses", ctx, tenantID, from, to)
+	ret0, _ := ret[0].([]repository.DailyAmount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDailyExpenses indicates an expected call of GetDailyExpenses.
+func (mr *MockAnalyticsRepoMockRecorder) GetDailyExpenses(ctx, tenantID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDailyExpenses", reflect.TypeOf((*MockAnalyticsRepo)(nil).GetDailyExpenses), ctx, tenantID, from, to)
+}
+
+// GetDashboardTotals mocks base method.
+func (m *MockAnalyticsRepo) GetDashboardTotals(ctx context.Context, tenantID uint) (*repository.DashboardTotals, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDashboardTotals", ctx, tenantID)
+	ret0, _ := ret[0].(*repository.DashboardTotals)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDashboardTotals indicates an expected call of GetDashboardTotals.
+func (mr *MockAnalyticsRepoMockRecorder) GetDashboardTotals(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDashboardTotals", reflect.TypeOf((*MockAnalyticsRepo)(nil).GetDashboardTotals), ctx, tenantID)
+}
+
+// GetDepositsInPeriod mocks base method.
+func (m *MockAnalyticsRepo) GetDepositsInPeriod(ctx context.Context, tenantID uint, from, to time.Time) ([]repository.BankDeposit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDepositsInPeriod", ctx, tenantID, from, to)
+	ret0, _ := ret[0].([]repository.BankDeposit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDepositsInPeriod indicates an expected call of GetDepositsInPeriod.
+func (mr *MockAnalyticsRepoMockRecorder) GetDepositsInPeriod(ctx, tenantID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDepositsInPeriod", reflect.TypeOf((*MockAnalyticsRepo)(nil).GetDepositsInPeriod), ctx, tenantID, from, to)
+}
+
+// GetDriverPerformance mocks base method.
+func (m *MockAnalyticsRepo) GetDriverPerformance(ctx context.Context, tenantID uint, from, to time.Time) ([]repository.DriverPerformance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDriverPerformance", ctx, tenantID, from, to)
+	ret0, _ := ret[0].([]repository.DriverPerformance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDriverPerformance indicates an expected call of GetDriverPerformance.
+func (mr *MockAnalyticsRepoMockRecorder) GetDriverPerformance(ctx, tenantID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDriverPerformance", reflect.TypeOf((*MockAnalyticsRepo)(nil).GetDriverPerformance), ctx, tenantID, from, to)
+}
+
+// GetExpensesInPeriod mocks base method.
+func (m *MockAnalyticsRepo) GetExpensesInPeriod(ctx context.Context, tenantID uint, from, to time.Time) ([]repository.Expense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpensesInPeriod", ctx, tenantID, from, to)
+	ret0, _ := ret[0].([]repository.Expense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpensesInPeriod indicates an expected call of GetExpensesInPeriod.
+func (mr *MockAnalyticsRepoMockRecorder) GetExpensesInPeriod(ctx, tenantID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpensesInPeriod", reflect.TypeOf((*MockAnalyticsRepo)(nil).GetExpensesInPeriod), ctx, tenantID, from, to)
+}
+
+// GetTaxiProfitability mocks base method.
+func (m *MockAnalyticsRepo) GetTaxiProfitability(ctx context.Context, tenantID uint, from, to time.Time) ([]repository.TaxiProfitability, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaxiProfitability", ctx, tenantID, from, to)
+	ret0, _ := ret[0].([]repository.TaxiProfitability)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaxiProfitability indicates an expected call of GetTaxiProfitability.
+func (mr *MockAnalyticsRepoMockRecorder) GetTaxiProfitability(ctx, tenantID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaxiProfitability", reflect.TypeOf((*MockAnalyticsRepo)(nil).GetTaxiProfitability), ctx, tenantID, from, to)
+}
+
+// SumApprovedEarnings mocks base method.
+func (m *MockAnalyticsRepo) SumApprovedEarnings(ctx context.Context, tenantID uint, from, to time.Time) (money.Amount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumApprovedEarnings", ctx, tenantID, from, to)
+	ret0, _ := ret[0].(money.Amount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumApprovedEarnings indicates an expected call of SumApprovedEarnings.
+func (mr *MockAnalyticsRepoMockRecorder) SumApprovedEarnings(ctx, tenantID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumApprovedEarnings", reflect.TypeOf((*MockAnalyticsRepo)(nil).SumApprovedEarnings), ctx, tenantID, from, to)
+}
+
+// SumDeposits mocks base method.
+func (m *MockAnalyticsRepo) SumDeposits(ctx context.Context, tenantID uint, from, to time.Time) (money.Amount, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumDeposits", ctx, tenantID, from, to)
+	ret0, _ := ret[0].(money.Amount)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SumDeposits indicates an expected call of SumDeposits.
+func (mr *MockAnalyticsRepoMockRecorder) SumDeposits(ctx, tenantID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumDeposits", reflect.TypeOf((*MockAnalyticsRepo)(nil).SumDeposits), ctx, tenantID, from, to)
+}
+
+// SumExpensesByCategory mocks base method.
+func (m *MockAnalyticsRepo) SumExpensesByCategory(ctx context.Context, tenantID uint, from, to time.Time) ([]repository.CategoryTotal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumExpensesByCategory", ctx, tenantID, from, to)
+	ret0, _ := ret[0].([]repository.CategoryTotal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumExpensesByCategory indicates an expected call of SumExpensesByCategory.
+func (mr *MockAnalyticsRepoMockRecorder) SumExpensesByCategory(ctx, tenantID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumExpensesByCategory", reflect.TypeOf((*MockAnalyticsRepo)(nil).SumExpensesByCategory), ctx, tenantID, from, to)
+}
+
+// SumMaintenanceCosts mocks base method.
+func (m *MockAnalyticsRepo) SumMaintenanceCosts(ctx context.Context, tenantID uint, from, to time.Time) (money.Amount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumMaintenanceCosts", ctx, tenantID, from, to)
+	ret0, _ := ret[0].(money.Amount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumMaintenanceCosts indicates an expected call of SumMaintenanceCosts.
+func (mr *MockAnalyticsRepoMockRecorder) SumMaintenanceCosts(ctx, tenantID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumMaintenanceCosts", reflect.TypeOf((*MockAnalyticsRepo)(nil).SumMaintenanceCosts), ctx, tenantID, from, to)
+}
+
+// MockJobRepo is a mock of JobRepo interface.
+type MockJobRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockJobRepoMockRecorder is the mock recorder for MockJobRepo.
+type MockJobRepoMockRecorder struct {
+	mock *MockJobRepo
+}
+
+// NewMockJobRepo creates a new mock instance.
+func NewMockJobRepo(ctrl *gomock.Controller) *MockJobRepo {
+	mock := &MockJobRepo{ctrl: ctrl}
+	mock.recorder = &MockJobRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobRepo) EXPECT() *MockJobRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateJobRun mocks base method.
+func (m *MockJobRepo) CreateJobRun(ctx context.Context, run *repository.JobRun) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateJobRun", ctx, run)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateJobRun indicates an expected call of CreateJobRun.
+func (mr *MockJobRepoMockRecorder) CreateJobRun(ctx, run any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJobRun", reflect.TypeOf((*MockJobRepo)(nil).CreateJobRun), ctx, run)
+}
+
+// GetJobRuns mocks base method.
+func (m *MockJobRepo) GetJobRuns(ctx context.Context, jobName string, limit int) ([]repository.JobRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJobRuns", ctx, jobName, limit)
+	ret0, _ := ret[0].([]repository.JobRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJobRuns indicates an expected call of GetJobRuns.
+func (mr *MockJobRepoMockRecorder) GetJobRuns(ctx, jobName, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobRuns", reflect.TypeOf((*MockJobRepo)(nil).GetJobRuns), ctx, jobName, limit)
+}
+
+// UpdateJobRun mocks base method.
+func (m *MockJobRepo) UpdateJobRun(ctx context.Context, run *repository.JobRun) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateJobRun", ctx, run)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateJobRun indicates an expected call of UpdateJobRun.
+func (mr *MockJobRepoMockRecorder) UpdateJobRun(ctx, run any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateJobRun", reflect.TypeOf((*MockJobRepo)(nil).UpdateJobRun), ctx, run)
+}
+
+// WithJobLock mocks base method.
+func (m *MockJobRepo) WithJobLock(ctx context.Context, name string, fn func() error) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithJobLock", ctx, name, fn)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WithJobLock indicates an expected call of WithJobLock.
+func (mr *MockJobRepoMockRecorder) WithJobLock(ctx, name, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithJobLock", reflect.TypeOf((*MockJobRepo)(nil).WithJobLock), ctx, name, fn)
+}
+
+// MockQueryStatsRepo is a mock of QueryStatsRepo interface.
+type MockQueryStatsRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockQueryStatsRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockQueryStatsRepoMockRecorder is the mock recorder for MockQueryStatsRepo.
+type MockQueryStatsRepoMockRecorder struct {
+	mock *MockQueryStatsRepo
+}
+
+// NewMockQueryStatsRepo creates a new mock instance.
+func NewMockQueryStatsRepo(ctrl *gomock.Controller) *MockQueryStatsRepo {
+	mock := &MockQueryStatsRepo{ctrl: ctrl}
+	mock.recorder = &MockQueryStatsRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQueryStatsRepo) EXPECT() *MockQueryStatsRepoMockRecorder {
+	return m.recorder
+}
+
+// GetSlowQueries mocks base method.
+func (m *MockQueryStatsRepo) GetSlowQueries(ctx context.Context, limit int) ([]repository.QueryStat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSlowQueries", ctx, limit)
+	ret0, _ := ret[0].([]repository.QueryStat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSlowQueries indicates an expected call of GetSlowQueries.
+func (mr *MockQueryStatsRepoMockRecorder) GetSlowQueries(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSlowQueries", reflect.TypeOf((*MockQueryStatsRepo)(nil).GetSlowQueries), ctx, limit)
+}
+
+// QueryStatsEnabled mocks base method.
+func (m *MockQueryStatsRepo) QueryStatsEnabled(ctx context.Context) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryStatsEnabled", ctx)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryStatsEnabled indicates an expected call of QueryStatsEnabled.
+func (mr *MockQueryStatsRepoMockRecorder) QueryStatsEnabled(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryStatsEnabled", reflect.TypeOf((*MockQueryStatsRepo)(nil).QueryStatsEnabled), ctx)
+}
+
+// MockIdempotencyRepo is a mock of IdempotencyRepo interface.
+type MockIdempotencyRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockIdempotencyRepoMockRecorder is the mock recorder for MockIdempotencyRepo.
+type MockIdempotencyRepoMockRecorder struct {
+	mock *MockIdempotencyRepo
+}
+
+// NewMockIdempotencyRepo creates a new mock instance.
+func NewMockIdempotencyRepo(ctrl *gomock.Controller) *MockIdempotencyRepo {
+	mock := &MockIdempotencyRepo{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyRepo) EXPECT() *MockIdempotencyRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateIdempotencyKey mocks base method.
+func (m *MockIdempotencyRepo) CreateIdempotencyKey(ctx context.Context, record *repository.IdempotencyKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIdempotencyKey", ctx, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateIdempotencyKey indicates an expected call of CreateIdempotencyKey.
+func (mr *MockIdempotencyRepoMockRecorder) CreateIdempotencyKey(ctx, record any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIdempotencyKey", reflect.TypeOf((*MockIdempotencyRepo)(nil).CreateIdempotencyKey), ctx, record)
+}
+
+// DeleteIdempotencyKey mocks base method.
+func (m *MockIdempotencyRepo) DeleteIdempotencyKey(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIdempotencyKey", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteIdempotencyKey indicates an expected call of DeleteIdempotencyKey.
+func (mr *MockIdempotencyRepoMockRecorder) DeleteIdempotencyKey(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIdempotencyKey", reflect.TypeOf((*MockIdempotencyRepo)(nil).DeleteIdempotencyKey), ctx, id)
+}
+
+// DeleteIdempotencyKeysBefore mocks base method.
+func (m *MockIdempotencyRepo) DeleteIdempotencyKeysBefore(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIdempotencyKeysBefore", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteIdempotencyKeysBefore indicates an expected call of DeleteIdempotencyKeysBefore.
+func (mr *MockIdempotencyRepoMockRecorder) DeleteIdempotencyKeysBefore(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIdempotencyKeysBefore", reflect.TypeOf((*MockIdempotencyRepo)(nil).DeleteIdempotencyKeysBefore), ctx, before)
+}
+
+// GetIdempotencyKey mocks base method.
+func (m *MockIdempotencyRepo) GetIdempotencyKey(ctx context.Context, userID uint, key string) (*repository.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdempotencyKey", ctx, userID, key)
+	ret0, _ := ret[0].(*repository.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdempotencyKey indicates an expected call of GetIdempotencyKey.
+func (mr *MockIdempotencyRepoMockRecorder) GetIdempotencyKey(ctx, userID, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdempotencyKey", reflect.TypeOf((*MockIdempotencyRepo)(nil).GetIdempotencyKey), ctx, userID, key)
+}
+
+// UpdateIdempotencyKey mocks base method.
+func (m *MockIdempotencyRepo) UpdateIdempotencyKey(ctx context.Context, record *repository.IdempotencyKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateIdempotencyKey", ctx, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateIdempotencyKey indicates an expected call of UpdateIdempotencyKey.
+func (mr *MockIdempotencyRepoMockRecorder) UpdateIdempotencyKey(ctx, record any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIdempotencyKey", reflect.TypeOf((*MockIdempotencyRepo)(nil).UpdateIdempotencyKey), ctx, record)
+}
+
+// MockAPIKeyRepo is a mock of APIKeyRepo interface.
+type MockAPIKeyRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeyRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockAPIKeyRepoMockRecorder is the mock recorder for MockAPIKeyRepo.
+type MockAPIKeyRepoMockRecorder struct {
+	mock *MockAPIKeyRepo
+}
+
+// NewMockAPIKeyRepo creates a new mock instance.
+func NewMockAPIKeyRepo(ctrl *gomock.Controller) *MockAPIKeyRepo {
+	mock := &MockAPIKeyRepo{ctrl: ctrl}
+	mock.recorder = &MockAPIKeyRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKeyRepo) EXPECT() *MockAPIKeyRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockAPIKeyRepo) CreateAPIKey(ctx context.Context, key *repository.APIKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockAPIKeyRepoMockRecorder) CreateAPIKey(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockAPIKeyRepo)(nil).CreateAPIKey), ctx, key)
+}
+
+// GetAPIKeyByHash mocks base method.
+func (m *MockAPIKeyRepo) GetAPIKeyByHash(ctx context.Context, keyHash string) (*repository.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeyByHash", ctx, keyHash)
+	ret0, _ := ret[0].(*repository.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeyByHash indicates an expected call of GetAPIKeyByHash.
+func (mr *MockAPIKeyRepoMockRecorder) GetAPIKeyByHash(ctx, keyHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeyByHash", reflect.TypeOf((*MockAPIKeyRepo)(nil).GetAPIKeyByHash), ctx, keyHash)
+}
+
+// GetAPIKeyByID mocks base method.
+func (m *MockAPIKeyRepo) GetAPIKeyByID(ctx context.Context, id uint) (*repository.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeyByID", ctx, id)
+	ret0, _ := ret[0].(*repository.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeyByID indicates an expected call of GetAPIKeyByID.
+func (mr *MockAPIKeyRepoMockRecorder) GetAPIKeyByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeyByID", reflect.TypeOf((*MockAPIKeyRepo)(nil).GetAPIKeyByID), ctx, id)
+}
+
+// GetAPIKeys mocks base method.
+func (m *MockAPIKeyRepo) GetAPIKeys(ctx context.Context, tenantID uint) ([]repository.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeys", ctx, tenantID)
+	ret0, _ := ret[0].([]repository.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeys indicates an expected call of GetAPIKeys.
+func (mr *MockAPIKeyRepoMockRecorder) GetAPIKeys(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeys", reflect.TypeOf((*MockAPIKeyRepo)(nil).GetAPIKeys), ctx, tenantID)
+}
+
+// TouchAPIKey mocks base method.
+func (m *MockAPIKeyRepo) TouchAPIKey(ctx context.Context, id uint, usedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchAPIKey", ctx, id, usedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchAPIKey indicates an expected call of TouchAPIKey.
+func (mr *MockAPIKeyRepoMockRecorder) TouchAPIKey(ctx, id, usedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchAPIKey", reflect.TypeOf((*MockAPIKeyRepo)(nil).TouchAPIKey), ctx, id, usedAt)
+}
+
+// UpdateAPIKey mocks base method.
+func (m *MockAPIKeyRepo) UpdateAPIKey(ctx context.Context, key *repository.APIKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAPIKey", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAPIKey indicates an expected call of UpdateAPIKey.
+func (mr *MockAPIKeyRepoMockRecorder) UpdateAPIKey(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAPIKey", reflect.TypeOf((*MockAPIKeyRepo)(nil).UpdateAPIKey), ctx, key)
+}
+
+// MockOAuthIdentityRepo is a mock of OAuthIdentityRepo interface.
+type MockOAuthIdentityRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockOAuthIdentityRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockOAuthIdentityRepoMockRecorder is the mock recorder for MockOAuthIdentityRepo.
+type MockOAuthIdentityRepoMockRecorder struct {
+	mock *MockOAuthIdentityRepo
+}
+
+// NewMockOAuthIdentityRepo creates a new mock instance.
+func NewMockOAuthIdentityRepo(ctrl *gomock.Controller) *MockOAuthIdentityRepo {
+	mock := &MockOAuthIdentityRepo{ctrl: ctrl}
+	mock.recorder = &MockOAuthIdentityRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOAuthIdentityRepo) EXPECT() *MockOAuthIdentityRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateOAuthIdentity mocks base method.
+func (m *MockOAuthIdentityRepo) CreateOAuthIdentity(ctx context.Context, identity *repository.OAuthIdentity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOAuthIdentity", ctx, identity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOAuthIdentity indicates an expected call of CreateOAuthIdentity.
+func (mr *MockOAuthIdentityRepoMockRecorder) CreateOAuthIdentity(ctx, identity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOAuthIdentity", reflect.TypeOf((*MockOAuthIdentityRepo)(nil).CreateOAuthIdentity), ctx, identity)
+}
+
+// GetOAuthIdentity mocks base method.
+func (m *MockOAuthIdentityRepo) GetOAuthIdentity(ctx context.Context, provider, subject string) (*repository.OAuthIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOAuthIdentity", ctx, provider, subject)
+	ret0, _ := ret[0].(*repository.OAuthIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOAuthIdentity indicates an expected call of GetOAuthIdentity.
+func (mr *MockOAuthIdentityRepoMockRecorder) GetOAuthIdentity(ctx, provider, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuthIdentity", reflect.TypeOf((*MockOAuthIdentityRepo)(nil).GetOAuthIdentity), ctx, provider, subject)
+}
+
+// MockLoginAuditRepo is a mock of LoginAuditRepo interface.
+type MockLoginAuditRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginAuditRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockLoginAuditRepoMockRecorder is the mock recorder for MockLoginAuditRepo.
+type MockLoginAuditRepoMockRecorder struct {
+	mock *MockLoginAuditRepo
+}
+
+// NewMockLoginAuditRepo creates a new mock instance.
+func NewMockLoginAuditRepo(ctrl *gomock.Controller) *MockLoginAuditRepo {
+	mock := &MockLoginAuditRepo{ctrl: ctrl}
+	mock.recorder = &MockLoginAuditRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginAuditRepo) EXPECT() *MockLoginAuditRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateLoginAudit mocks base method.
+func (m *MockLoginAuditRepo) CreateLoginAudit(ctx context.Context, audit *repository.LoginAudit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLoginAudit", ctx, audit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateLoginAudit indicates an expected call of CreateLoginAudit.
+func (mr *MockLoginAuditRepoMockRecorder) CreateLoginAudit(ctx, audit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoginAudit", reflect.TypeOf((*MockLoginAuditRepo)(nil).CreateLoginAudit), ctx, audit)
+}
+
+// GetLoginAudits mocks base method.
+func (m *MockLoginAuditRepo) GetLoginAudits(ctx context.Context, userID uint, limit int) ([]repository.LoginAudit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoginAudits", ctx, userID, limit)
+	ret0, _ := ret[0].([]repository.LoginAudit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoginAudits indicates an expected call of GetLoginAudits.
+func (mr *MockLoginAuditRepoMockRecorder) GetLoginAudits(ctx, userID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoginAudits", reflect.TypeOf((*MockLoginAuditRepo)(nil).GetLoginAudits), ctx, userID, limit)
+}
+
+// HasLoginFromDevice mocks base method.
+func (m *MockLoginAuditRepo) HasLoginFromDevice(ctx context.Context, userID uint, fingerprint string) (bool, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasLoginFromDevice", ctx, userID, fingerprint)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// HasLoginFromDevice indicates an expected call of HasLoginFromDevice.
+func (mr *MockLoginAuditRepoMockRecorder) HasLoginFromDevice(ctx, userID, fingerprint any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasLoginFromDevice", reflect.TypeOf((*MockLoginAuditRepo)(nil).HasLoginFromDevice), ctx, userID, fingerprint)
+}
+
+// MockInboxRepo is a mock of InboxRepo interface.
+type MockInboxRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockInboxRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockInboxRepoMockRecorder is the mock recorder for MockInboxRepo.
+type MockInboxRepoMockRecorder struct {
+	mock *MockInboxRepo
+}
+
+// NewMockInboxRepo creates a new mock instance.
+func NewMockInboxRepo(ctrl *gomock.Controller) *MockInboxRepo {
+	mock := &MockInboxRepo{ctrl: ctrl}
+	mock.recorder = &MockInboxRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInboxRepo) EXPECT() *MockInboxRepoMockRecorder {
+	return m.recorder
+}
+
+// CountUnreadNotifications mocks base method.
+func (m *MockInboxRepo) CountUnreadNotifications(ctx context.Context, userID uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnreadNotifications", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnreadNotifications indicates an expected call of CountUnreadNotifications.
+func (mr *MockInboxRepoMockRecorder) CountUnreadNotifications(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnreadNotifications", reflect.TypeOf((*MockInboxRepo)(nil).CountUnreadNotifications), ctx, userID)
+}
+
+// CreateNotification mocks base method.
+func (m *MockInboxRepo) CreateNotification(ctx context.Context, notification *repository.Notification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNotification", ctx, notification)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateNotification indicates an expected call of CreateNotification.
+func (mr *MockInboxRepoMockRecorder) CreateNotification(ctx, notification any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNotification", reflect.TypeOf((*MockInboxRepo)(nil).CreateNotification), ctx, notification)
+}
+
+// GetNotifications mocks base method.
+func (m *MockInboxRepo) GetNotifications(ctx context.Context, userID uint, unreadOnly bool, limit int) ([]repository.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotifications", ctx, userID, unreadOnly, limit)
+	ret0, _ := ret[0].([]repository.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotifications indicates an expected call of GetNotifications.
+func (mr *MockInboxRepoMockRecorder) GetNotifications(ctx, userID, unreadOnly, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotifications", reflect.TypeOf((*MockInboxRepo)(nil).GetNotifications), ctx, userID, unreadOnly, limit)
+}
+
+// MarkAllNotificationsRead mocks base method.
+func (m *MockInboxRepo) MarkAllNotificationsRead(ctx context.Context, userID uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAllNotificationsRead", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkAllNotificationsRead indicates an expected call of MarkAllNotificationsRead.
+func (mr *MockInboxRepoMockRecorder) MarkAllNotificationsRead(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllNotificationsRead", reflect.TypeOf((*MockInboxRepo)(nil).MarkAllNotificationsRead), ctx, userID)
+}
+
+// MarkNotificationRead mocks base method.
+func (m *MockInboxRepo) MarkNotificationRead(ctx context.Context, userID, id uint) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotificationRead", ctx, userID, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkNotificationRead indicates an expected call of MarkNotificationRead.
+func (mr *MockInboxRepoMockRecorder) MarkNotificationRead(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationRead", reflect.TypeOf((*MockInboxRepo)(nil).MarkNotificationRead), ctx, userID, id)
+}
+
+// MockDigestRepo is a mock of DigestRepo interface.
+type MockDigestRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockDigestRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockDigestRepoMockRecorder is the mock recorder for MockDigestRepo.
+type MockDigestRepoMockRecorder struct {
+	mock *MockDigestRepo
+}
+
+// NewMockDigestRepo creates a new mock instance.
+func NewMockDigestRepo(ctrl *gomock.Controller) *MockDigestRepo {
+	mock := &MockDigestRepo{ctrl: ctrl}
+	mock.recorder = &MockDigestRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDigestRepo) EXPECT() *MockDigestRepoMockRecorder {
+	return m.recorder
+}
+
+// CountSubmittedReports mocks base method.
+func (m *MockDigestRepo) CountSubmittedReports(ctx context.Context, tenantID uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSubmittedReports", ctx, tenantID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSubmittedReports indicates an expected call of CountSubmittedReports.
+func (mr *MockDigestRepoMockRecorder) CountSubmittedReports(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSubmittedReports", reflect.TypeOf((*MockDigestRepo)(nil).CountSubmittedReports), ctx, tenantID)
+}
+
+// GetReportsForWeek mocks base method.
+func (m *MockDigestRepo) GetReportsForWeek(ctx context.Context, tenantID uint, weekStart time.Time) ([]repository.WeeklyReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportsForWeek", ctx, tenantID, weekStart)
+	ret0, _ := ret[0].([]repository.WeeklyReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReportsForWeek indicates an expected call of GetReportsForWeek.
+func (mr *MockDigestRepoMockRecorder) GetReportsForWeek(ctx, tenantID, weekStart any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportsForWeek", reflect.TypeOf((*MockDigestRepo)(nil).GetReportsForWeek), ctx, tenantID, weekStart)
+}
+
+// SetWeeklyDigest mocks base method.
+func (m *MockDigestRepo) SetWeeklyDigest(ctx context.Context, userID uint, enabled bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetWeeklyDigest", ctx, userID, enabled)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetWeeklyDigest indicates an expected call of SetWeeklyDigest.
+func (mr *MockDigestRepoMockRecorder) SetWeeklyDigest(ctx, userID, enabled any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWeeklyDigest", reflect.TypeOf((*MockDigestRepo)(nil).SetWeeklyDigest), ctx, userID, enabled)
+}
+
+// MockAuditRepo is a mock of AuditRepo interface.
+type MockAuditRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockAuditRepoMockRecorder is the mock recorder for MockAuditRepo.
+type MockAuditRepoMockRecorder struct {
+	mock *MockAuditRepo
+}
+
+// NewMockAuditRepo creates a new mock instance.
+func NewMockAuditRepo(ctrl *gomock.Controller) *MockAuditRepo {
+	mock := &MockAuditRepo{ctrl: ctrl}
+	mock.recorder = &MockAuditRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditRepo) EXPECT() *MockAuditRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateAuditEvent mocks base method.
+func (m *MockAuditRepo) CreateAuditEvent(ctx context.Context, event *repository.AuditEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuditEvent", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAuditEvent indicates an expected call of CreateAuditEvent.
+func (mr *MockAuditRepoMockRecorder) CreateAuditEvent(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuditEvent", reflect.TypeOf((*MockAuditRepo)(nil).CreateAuditEvent), ctx, event)
+}
+
+// GetAuditEvents mocks base method.
+func (m *MockAuditRepo) GetAuditEvents(ctx context.Context, tenantID, beforeID uint, limit int) ([]repository.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditEvents", ctx, tenantID, beforeID, limit)
+	ret0, _ := ret[0].([]repository.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditEvents indicates an expected call of GetAuditEvents.
+func (mr *MockAuditRepoMockRecorder) GetAuditEvents(ctx, tenantID, beforeID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditEvents", reflect.TypeOf((*MockAuditRepo)(nil).GetAuditEvents), ctx, tenantID, beforeID, limit)
+}
+
+// MockDemoRepo is a mock of DemoRepo interface.
+type MockDemoRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockDemoRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockDemoRepoMockRecorder is the mock recorder for MockDemoRepo.
+type MockDemoRepoMockRecorder struct {
+	mock *MockDemoRepo
+}
+
+// NewMockDemoRepo creates a new mock instance.
+func NewMockDemoRepo(ctrl *gomock.Controller) *MockDemoRepo {
+	mock := &MockDemoRepo{ctrl: ctrl}
+	mock.recorder = &MockDemoRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDemoRepo) EXPECT() *MockDemoRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateDemoDeposits mocks base method.
+func (m *MockDemoRepo) CreateDemoDeposits(ctx context.Context, deposits []repository.BankDeposit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDemoDeposits", ctx, deposits)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDemoDeposits indicates an expected call of CreateDemoDeposits.
+func (mr *MockDemoRepoMockRecorder) CreateDemoDeposits(ctx, deposits any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDemoDeposits", reflect.TypeOf((*MockDemoRepo)(nil).CreateDemoDeposits), ctx, deposits)
+}
+
+// CreateDemoExpenses mocks base method.
+func (m *MockDemoRepo) CreateDemoExpenses(ctx context.Context, expenses []repository.Expense) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDemoExpenses", ctx, expenses)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDemoExpenses indicates an expected call of CreateDemoExpenses.
+func (mr *MockDemoRepoMockRecorder) CreateDemoExpenses(ctx, expenses any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDemoExpenses", reflect.TypeOf((*MockDemoRepo)(nil).CreateDemoExpenses), ctx, expenses)
+}
+
+// CreateDemoReports mocks base method.
+func (m *MockDemoRepo) CreateDemoReports(ctx context.Context, reports []repository.WeeklyReport) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDemoReports", ctx, reports)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDemoReports indicates an expected call of CreateDemoReports.
+func (mr *MockDemoRepoMockRecorder) CreateDemoReports(ctx, reports any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDemoReports", reflect.TypeOf((*MockDemoRepo)(nil).CreateDemoReports), ctx, reports)
+}
+
+// CreateDemoTaxis mocks base method.
+func (m *MockDemoRepo) CreateDemoTaxis(ctx context.Context, taxis []repository.Taxi) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDemoTaxis", ctx, taxis)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDemoTaxis indicates an expected call of CreateDemoTaxis.
+func (mr *MockDemoRepoMockRecorder) CreateDemoTaxis(ctx, taxis any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDemoTaxis", reflect.TypeOf((*MockDemoRepo)(nil).CreateDemoTaxis), ctx, taxis)
+}
+
+// CreateDemoUsers mocks base method.
+func (m *MockDemoRepo) CreateDemoUsers(ctx context.Context, users []repository.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDemoUsers", ctx, users)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDemoUsers indicates an expected call of CreateDemoUsers.
+func (mr *MockDemoRepoMockRecorder) CreateDemoUsers(ctx, users any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDemoUsers", reflect.TypeOf((*MockDemoRepo)(nil).CreateDemoUsers), ctx, users)
+}
@@ -3,6 +3,8 @@ package repository
 import (
 	"time"
 
+	"taxifleet/backend/internal/money"
+
 	"gorm.io/gorm"
 )
 
@@ -13,22 +15,89 @@ type Tenant struct {
 	Subdomain string         `gorm:"uniqueIndex;not null" json:"subdomain"`
 	Logo      string         `json:"logo"`
 	Settings  string         `gorm:"type:jsonb;default:'{}'" json:"settings"` // JSON string, stored as JSONB
+	PlanID    *uint          `json:"plan_id"`                                 // Nil has no limits
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
+// Plan is a subscription plan limiting what its tenants can use; a nil limit
+// is unlimited
+type Plan struct {
+	ID                    uint      `gorm:"primaryKey" json:"id"`
+	Name                  string    `gorm:"not null;uniqueIndex" json:"name"`
+	MaxTaxis              *int      `json:"max_taxis"`
+	MaxUsers              *int      `json:"max_users"`
+	ExportQuota           *int      `json:"export_quota"`              // Exports per calendar month
+	APIKeyRequestsPerHour *int      `json:"api_key_requests_per_hour"` // Per API key of the tenant
+	CreatedAt             time.Time `json:"created_at"`
+	UpdatedAt             time.Time `json:"updated_at"`
+}
+
+// ExportCount is how many exports a tenant made in a calendar month
+type ExportCount struct {
+	TenantID uint      `gorm:"primaryKey" json:"tenant_id"`
+	Month    time.Time `gorm:"primaryKey;type:date" json:"month"` // First day of the month
+	Count    int       `gorm:"not null;default:0" json:"count"`
+}
+
+// SystemSetting overrides a platform-wide default at runtime
+type SystemSetting struct {
+	Key         string    `gorm:"primaryKey" json:"key"`
+	Value       string    `gorm:"type:jsonb;not null" json:"value"`
+	UpdatedByID *uint     `json:"updated_by_id"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// SystemSettingChange records who changed a system setting and how; a nil
+// value is the default
+type SystemSettingChange struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Key         string    `gorm:"not null" json:"key"`
+	OldValue    *string   `gorm:"type:jsonb" json:"old_value"`
+	NewValue    *string   `gorm:"type:jsonb" json:"new_value"`
+	ChangedByID *uint     `json:"changed_by_id"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	ChangedBy *User `gorm:"foreignKey:ChangedByID" json:"changed_by,omitempty"`
+}
+
+// Policy allows or denies an action to a role or user of the tenant, under
+// conditions on the action's attributes
+type Policy struct {
+	ID          uint              `gorm:"primaryKey" json:"id"`
+	TenantID    uint              `gorm:"not null;index" json:"tenant_id"`
+	Subject     string            `gorm:"not null" json:"subject"` // Role name, user:<id> or *
+	Action      string            `gorm:"not null" json:"action"`
+	Effect      string            `gorm:"not null" json:"effect"`                                // allow or deny
+	Conditions  []PolicyCondition `gorm:"type:jsonb;serializer:json;not null" json:"conditions"` // All must hold
+	Description string            `json:"description"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// PolicyCondition compares an attribute of the action, such as a report's
+// amount, with a value
+type PolicyCondition struct {
+	Attribute string  `json:"attribute"`
+	Operator  string  `json:"operator"` // lt, lte, gt, gte, eq, ne
+	Value     float64 `json:"value"`
+}
+
 // User represents a system user
 type User struct {
 	ID           uint           `gorm:"primaryKey" json:"id"`
 	TenantID     uint           `gorm:"not null;index" json:"tenant_id"`
 	Email        string         `gorm:"uniqueIndex;not null" json:"email"`
 	PasswordHash string         `gorm:"not null" json:"-"`
+	PINHash      string         `gorm:"not null;default:''" json:"-"`         // Approval PIN, bcrypt; empty until set
 	Permission   int            `gorm:"not null;default:3" json:"permission"` // Integer permission mask
 	FirstName    string         `gorm:"not null" json:"first_name"`
 	LastName     string         `gorm:"not null" json:"last_name"`
 	Phone        string         `gorm:"uniqueIndex" json:"phone"`
 	Active       bool           `gorm:"default:true" json:"active"`
+	WeeklyDigest bool           `gorm:"not null;default:true" json:"weekly_digest"` // Owners only; false opts out of the digest email
+	Unverified   bool           `gorm:"not null;default:false" json:"unverified"`   // Signed up and has not verified their email yet
 	CreatedAt    time.Time      `json:"created_at"`
 	UpdatedAt    time.Time      `json:"updated_at"`
 	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
@@ -57,8 +126,11 @@ type Taxi struct {
 	Year             int            `json:"year"`
 	Color            string         `json:"color"`
 	VIN              string         `json:"vin"`
-	Status           string         `gorm:"default:'active'" json:"status"` // active, maintenance, inactive
+	Status           string         `gorm:"default:'active'" json:"status"`    // active, maintenance, inactive
+	Mileage          int            `gorm:"not null;default:0" json:"mileage"` // Odometer reading in km
 	AssignedDriverID *uint          `json:"assigned_driver_id"`
+	EarningsSplit    *EarningsSplit `gorm:"type:jsonb;serializer:json" json:"earnings_split"` // Nil uses the tenant's rule
+	Version          int            `gorm:"not null;default:1" json:"version"`                // Bumped on every update for optimistic locking
 	CreatedAt        time.Time      `json:"created_at"`
 	UpdatedAt        time.Time      `json:"updated_at"`
 	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
@@ -67,46 +139,178 @@ type Taxi struct {
 	AssignedDriver *User  `gorm:"foreignKey:AssignedDriverID" json:"driver,omitempty"`
 }
 
+// EarningsSplit is how a week's net earnings (earnings less expenses) are
+// shared between the owner and the driver
+type EarningsSplit struct {
+	Type          string       `json:"type"`                     // percentage, daily_rental
+	DriverPercent float64      `json:"driver_percent,omitempty"` // percentage: the driver's share of net earnings
+	DailyRental   money.Amount `json:"daily_rental,omitempty"`   // daily_rental: what the driver owes the owner per day
+}
+
 // WeeklyReport represents a driver's weekly report
 type WeeklyReport struct {
-	ID            uint           `gorm:"primaryKey" json:"id"`
-	TenantID      uint           `gorm:"not null;index" json:"tenant_id"`
-	TaxiID        uint           `gorm:"not null;index" json:"taxi_id"`
-	DriverID      uint           `gorm:"not null;index" json:"driver_id"`
-	WeekStartDate time.Time      `gorm:"not null" json:"week_start_date"`
-	Earnings      float64        `gorm:"not null;default:0" json:"earnings"`
-	TotalExpenses float64        `gorm:"default:0" json:"total_expenses"`
-	Status        string         `gorm:"default:'draft'" json:"status"` // draft, submitted, approved, rejected
-	Notes         string         `gorm:"type:text" json:"notes"`
-	SubmittedAt   *time.Time     `json:"submitted_at"`
-	ApprovedAt    *time.Time     `json:"approved_at"`
-	ApprovedByID  *uint          `json:"approved_by_id"`
-	CreatedAt     time.Time      `json:"created_at"`
-	UpdatedAt     time.Time      `json:"updated_at"`
-	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+	ID               uint           `gorm:"primaryKey" json:"id"`
+	TenantID         uint           `gorm:"not null;index" json:"tenant_id"`
+	TaxiID           uint           `gorm:"not null;index" json:"taxi_id"`
+	DriverID         uint           `gorm:"not null;index" json:"driver_id"`
+	WeekStartDate    time.Time      `gorm:"not null" json:"week_start_date"`
+	Earnings         money.Amount   `gorm:"not null;default:0" json:"earnings"`
+	TotalExpenses    money.Amount   `gorm:"default:0" json:"total_expenses"`
+	TotalAdjustments money.Amount   `gorm:"not null;default:0" json:"total_adjustments"` // Bonuses less penalties
+	Status           string         `gorm:"default:'draft'" json:"status"`               // draft, submitted, approved, rejected
+	Notes            string         `gorm:"type:text" json:"notes"`
+	SubmittedAt      *time.Time     `json:"submitted_at"`
+	ApprovedAt       *time.Time     `json:"approved_at"`
+	ApprovedByID     *uint          `json:"approved_by_id"`
+	DriverShare      *money.Amount  `json:"driver_share"` // Set on approval when an earnings split applies
+	OwnerShare       *money.Amount  `json:"owner_share"`
+	LedgerOffset     *money.Amount  `json:"ledger_offset"`                     // Part of the driver share kept to repay advances and fines
+	Version          int            `gorm:"not null;default:1" json:"version"` // Bumped on every update for optimistic locking
+	ClientID         *string        `gorm:"type:uuid" json:"client_id,omitempty"`
+	ArchivedAt       *time.Time     `json:"archived_at"`                             // Archived reports are left out of the default lists
+	Resubmissions    int            `gorm:"not null;default:0" json:"resubmissions"` // Times the report went back to draft after a rejection
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
 
 	Tenant     Tenant    `gorm:"foreignKey:TenantID" json:"tenant,omitempty"`
 	Taxi       Taxi      `gorm:"foreignKey:TaxiID" json:"taxi,omitempty"`
 	Driver     User      `gorm:"foreignKey:DriverID" json:"driver,omitempty"`
 	ApprovedBy *User     `gorm:"foreignKey:ApprovedByID" json:"approved_by,omitempty"`
 	Expenses   []Expense `gorm:"foreignKey:ReportID" json:"expenses,omitempty"`
+
+	Attachments []ReportAttachment `gorm:"foreignKey:ReportID" json:"attachments,omitempty"`
+	Adjustments []ReportAdjustment `gorm:"foreignKey:ReportID" json:"adjustments,omitempty"`
+	Rejections  []ReportRejection  `gorm:"foreignKey:ReportID" json:"rejections,omitempty"`
+}
+
+// ReportRejection records a rejection of a report, which stays in the
+// report's history after it is resubmitted
+type ReportRejection struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	TenantID     uint      `gorm:"not null;index" json:"tenant_id"`
+	ReportID     uint      `gorm:"not null;index" json:"report_id"`
+	RejectedByID uint      `gorm:"not null" json:"rejected_by_id"`
+	Reason       string    `gorm:"type:text;not null;default:''" json:"reason"`
+	RejectedAt   time.Time `gorm:"not null" json:"rejected_at"`
+
+	RejectedBy User `gorm:"foreignKey:RejectedByID" json:"rejected_by,omitempty"`
 }
 
+// ReportSignature is the sign-off captured when a report was approved: the
+// approver either confirmed with their PIN or drew their signature
+type ReportSignature struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	TenantID    uint      `gorm:"not null;index" json:"tenant_id"`
+	ReportID    uint      `gorm:"not null;uniqueIndex" json:"report_id"`
+	SignedByID  uint      `gorm:"not null" json:"signed_by_id"`
+	Method      string    `gorm:"not null" json:"method"` // pin or drawn
+	Image       []byte    `json:"-"`                      // Drawn signature, PNG or JPEG
+	ContentType string    `gorm:"not null;default:''" json:"content_type,omitempty"`
+	SignedAt    time.Time `gorm:"not null" json:"signed_at"`
+}
+
+// ApprovalDelegation lets a user approve reports on an owner's behalf from
+// StartsOn through EndsOn, e.g. a manager while the owner travels
+type ApprovalDelegation struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	TenantID    uint       `gorm:"not null;index" json:"tenant_id"`
+	DelegatorID uint       `gorm:"not null" json:"delegator_id"` // The owner who granted it
+	DelegateID  uint       `gorm:"not null;index" json:"delegate_id"`
+	StartsOn    time.Time  `gorm:"type:date;not null" json:"starts_on"`
+	EndsOn      time.Time  `gorm:"type:date;not null" json:"ends_on"` // Inclusive
+	Reason      string     `gorm:"not null;default:''" json:"reason"`
+	RevokedAt   *time.Time `json:"revoked_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+
+	Delegator User `gorm:"foreignKey:DelegatorID" json:"delegator,omitempty"`
+	Delegate  User `gorm:"foreignKey:DelegateID" json:"delegate,omitempty"`
+}
+
+// ReportAdjustment is a bonus or penalty on a weekly report. It records who
+// added it; adjustments can't change once the report is approved.
+type ReportAdjustment struct {
+	ID          uint         `gorm:"primaryKey" json:"id"`
+	TenantID    uint         `gorm:"not null;index" json:"tenant_id"`
+	ReportID    uint         `gorm:"not null;index" json:"report_id"`
+	Type        string       `gorm:"not null" json:"type"`   // bonus, penalty
+	Amount      money.Amount `gorm:"not null" json:"amount"` // Always positive
+	Reason      string       `gorm:"type:text;not null" json:"reason"`
+	CreatedByID uint         `gorm:"not null" json:"created_by_id"`
+	CreatedAt   time.Time    `json:"created_at"`
+
+	CreatedBy User `gorm:"foreignKey:CreatedByID" json:"created_by,omitempty"`
+}
+
+// ReportAttachment is a photo attached to a weekly report, such as the cash
+// count or a logbook page
+type ReportAttachment struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	TenantID     uint       `gorm:"not null;index" json:"tenant_id"`
+	ReportID     uint       `gorm:"not null;index" json:"report_id"`
+	UploadedByID uint       `gorm:"not null" json:"uploaded_by_id"`
+	FileName     string     `gorm:"not null" json:"file_name"`
+	ContentType  string     `gorm:"not null" json:"content_type"`
+	Size         int64      `gorm:"not null" json:"size"`
+	StoragePath  string     `gorm:"not null" json:"-"`                               // Key of the file in storage
+	ScanStatus   string     `gorm:"not null;default:not_scanned" json:"scan_status"` // not_scanned, pending, clean or quarantined
+	ScanThreat   string     `json:"scan_threat,omitempty"`                           // Malware found in a quarantined file
+	ScannedAt    *time.Time `json:"scanned_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// Antivirus scan statuses of report attachments. Only files that are
+// not_scanned (scanning is off) or clean can be downloaded.
+const (
+	ScanNotScanned  = "not_scanned"
+	ScanPending     = "pending"
+	ScanClean       = "clean"
+	ScanQuarantined = "quarantined"
+)
+
+// ExportBundle is a ZIP archive of a period's exports and report photos,
+// generated in the background for the user who requested it
+type ExportBundle struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	TenantID      uint       `gorm:"not null;index" json:"tenant_id"`
+	RequestedByID uint       `gorm:"not null" json:"requested_by_id"`
+	PeriodStart   time.Time  `gorm:"type:date;not null" json:"period_start"`
+	PeriodEnd     time.Time  `gorm:"type:date;not null" json:"period_end"`
+	Status        string     `gorm:"not null;default:pending" json:"status"` // pending, ready or failed
+	Error         string     `json:"-"`                                      // Why generating the bundle failed, for operators
+	StoragePath   string     `json:"-"`                                      // Key of the archive in storage once ready
+	Size          int64      `gorm:"not null;default:0" json:"size"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// Export bundle statuses
+const (
+	BundlePending = "pending"
+	BundleReady   = "ready"
+	BundleFailed  = "failed"
+)
+
 // Expense represents an expense entry
 type Expense struct {
-	ID          uint           `gorm:"primaryKey" json:"id"`
-	TenantID    uint           `gorm:"not null;index" json:"tenant_id"`
-	ReportID    *uint          `gorm:"index" json:"report_id"` // Optional: can be standalone or part of report
-	TaxiID      *uint          `gorm:"index" json:"taxi_id"`
-	Category    string         `gorm:"not null" json:"category"` // fuel, maintenance, insurance, repair, cleaning, other
-	Amount      float64        `gorm:"not null" json:"amount"`
-	Reason      string         `gorm:"type:text" json:"reason"`
-	ReceiptURL  string         `json:"receipt_url"`
-	Date        time.Time      `gorm:"not null" json:"date"`
-	CreatedByID uint           `gorm:"not null" json:"created_by_id"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	ID           uint           `gorm:"primaryKey" json:"id"`
+	TenantID     uint           `gorm:"not null;index" json:"tenant_id"`
+	ReportID     *uint          `gorm:"index" json:"report_id"` // Optional: can be standalone or part of report
+	TaxiID       *uint          `gorm:"index" json:"taxi_id"`
+	Category     string         `gorm:"not null" json:"category"` // fuel, maintenance, insurance, repair, cleaning, other
+	Amount       money.Amount   `gorm:"not null" json:"amount"`
+	Reason       string         `gorm:"type:text" json:"reason"`
+	ReceiptURL   string         `json:"receipt_url"`
+	Date         time.Time      `gorm:"not null" json:"date"`
+	CreatedByID  uint           `gorm:"not null" json:"created_by_id"`
+	ClientID     *string        `gorm:"type:uuid" json:"client_id,omitempty"`      // Generated by the driver app for offline creates
+	Status       string         `gorm:"not null;default:'approved'" json:"status"` // approved, pending_approval
+	ApprovedAt   *time.Time     `json:"approved_at"`
+	ApprovedByID *uint          `json:"approved_by_id"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
 
 	Tenant    Tenant        `gorm:"foreignKey:TenantID" json:"tenant,omitempty"`
 	Report    *WeeklyReport `gorm:"foreignKey:ReportID" json:"report,omitempty"`
@@ -116,20 +320,64 @@ type Expense struct {
 
 // BankDeposit represents a bank deposit record
 type BankDeposit struct {
-	ID          uint           `gorm:"primaryKey" json:"id"`
-	TenantID    uint           `gorm:"not null;index" json:"tenant_id"`
-	Amount      float64        `gorm:"not null" json:"amount"`
-	DepositDate time.Time      `gorm:"not null" json:"deposit_date"`
-	PeriodStart time.Time      `gorm:"not null" json:"period_start"`
-	PeriodEnd   time.Time      `gorm:"not null" json:"period_end"`
-	BankAccount string         `json:"bank_account"`
-	ProofURL    string         `json:"proof_url"`
-	Notes       string         `gorm:"type:text" json:"notes"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	ID            uint           `gorm:"primaryKey" json:"id"`
+	TenantID      uint           `gorm:"not null;index" json:"tenant_id"`
+	TaxiID        *uint          `gorm:"index" json:"taxi_id"` // Optional: the taxi whose cash was banked
+	Amount        money.Amount   `gorm:"not null" json:"amount"`
+	DepositDate   time.Time      `gorm:"not null" json:"deposit_date"`
+	PeriodStart   time.Time      `gorm:"not null" json:"period_start"`
+	PeriodEnd     time.Time      `gorm:"not null" json:"period_end"`
+	BankAccountID *uint          `gorm:"index" json:"bank_account_id"`
+	BankAccount   string         `json:"bank_account"` // Free text of deposits recorded before bank accounts
+	ProofURL      string         `json:"proof_url"`
+	Notes         string         `gorm:"type:text" json:"notes"`
+	Status        string         `gorm:"not null;default:'unverified'" json:"status"` // unverified, verified
+	VerifiedAt    *time.Time     `json:"verified_at"`
+	VerifiedByID  *uint          `json:"verified_by_id"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
 
-	Tenant Tenant `gorm:"foreignKey:TenantID" json:"tenant,omitempty"`
+	Tenant  Tenant       `gorm:"foreignKey:TenantID" json:"tenant,omitempty"`
+	Account *BankAccount `gorm:"foreignKey:BankAccountID" json:"account,omitempty"`
+}
+
+// Budget caps what a tenant plans to spend on an expense category each
+// month, for the whole fleet or for one taxi
+type Budget struct {
+	ID        uint         `gorm:"primaryKey" json:"id"`
+	TenantID  uint         `gorm:"not null;index" json:"tenant_id"`
+	Category  string       `gorm:"not null" json:"category"`
+	TaxiID    *uint        `gorm:"index" json:"taxi_id"`   // Nil for the whole fleet
+	Amount    money.Amount `gorm:"not null" json:"amount"` // Per month
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+
+	Taxi *Taxi `gorm:"foreignKey:TaxiID" json:"taxi,omitempty"`
+}
+
+// ClosedPeriod is a month closed for the books: reports, expenses and
+// deposits dated within it can't change until an admin reopens it
+type ClosedPeriod struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	TenantID   uint      `gorm:"not null;index" json:"tenant_id"`
+	Month      time.Time `gorm:"type:date;not null" json:"month"` // First day of the month
+	ClosedByID uint      `gorm:"not null" json:"closed_by_id"`
+	ClosedAt   time.Time `gorm:"not null" json:"closed_at"`
+
+	ClosedBy User `gorm:"foreignKey:ClosedByID" json:"closed_by,omitempty"`
+}
+
+// BankAccount represents an account of the tenant deposits are made into
+type BankAccount struct {
+	ID            uint           `gorm:"primaryKey" json:"id"`
+	TenantID      uint           `gorm:"not null;index" json:"tenant_id"`
+	Label         string         `gorm:"not null" json:"label"`
+	BankName      string         `json:"bank_name"`
+	AccountNumber string         `json:"account_number"` // IBAN or local account number
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 // MaintenanceLog represents a maintenance record
@@ -138,7 +386,7 @@ type MaintenanceLog struct {
 	TenantID    uint           `gorm:"not null;index" json:"tenant_id"`
 	TaxiID      uint           `gorm:"not null;index" json:"taxi_id"`
 	Description string         `gorm:"type:text" json:"description"`
-	Cost        float64        `json:"cost"`
+	Cost        money.Amount   `json:"cost"`
 	Date        time.Time      `gorm:"not null" json:"date"`
 	MechanicID  *uint          `json:"mechanic_id"`
 	CreatedAt   time.Time      `json:"created_at"`
@@ -149,3 +397,308 @@ type MaintenanceLog struct {
 	Taxi     Taxi   `gorm:"foreignKey:TaxiID" json:"taxi,omitempty"`
 	Mechanic *User  `gorm:"foreignKey:MechanicID" json:"mechanic,omitempty"`
 }
+
+// DeviceToken represents a mobile device registered for push notifications
+type DeviceToken struct {
+	ID         uint           `gorm:"primaryKey" json:"id"`
+	TenantID   uint           `gorm:"not null;index" json:"tenant_id"`
+	UserID     uint           `gorm:"not null;index" json:"user_id"`
+	Token      string         `gorm:"uniqueIndex;not null" json:"token"`
+	Platform   string         `gorm:"not null" json:"platform"` // android, ios, web
+	LastSeenAt time.Time      `json:"last_seen_at"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// Notification is an entry of a user's in-app inbox
+type Notification struct {
+	ID        uint              `gorm:"primaryKey" json:"id"`
+	UserID    uint              `gorm:"not null;index" json:"user_id"`
+	Type      string            `gorm:"not null" json:"type"` // report_status, report_submitted, maintenance_due, announcement...
+	Title     string            `gorm:"not null" json:"title"`
+	Body      string            `gorm:"type:text" json:"body"`
+	Data      map[string]string `gorm:"type:jsonb;serializer:json" json:"data,omitempty"`
+	ReadAt    *time.Time        `json:"read_at"` // Nil while unread
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// AuditEvent records a notable change in a tenant, shown in its activity feed
+type AuditEvent struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	TenantID   uint      `gorm:"not null;index" json:"tenant_id"`
+	ActorID    *uint     `json:"actor_id,omitempty"`          // Nil for API keys and deleted users
+	Action     string    `gorm:"not null" json:"action"`      // report.submitted, report.approved, taxi.created, expense.created
+	EntityType string    `gorm:"not null" json:"entity_type"` // report, taxi, expense
+	EntityID   uint      `gorm:"not null" json:"entity_id"`
+	Summary    string    `gorm:"not null" json:"summary"`
+	CreatedAt  time.Time `json:"created_at"`
+
+	Actor *User `gorm:"foreignKey:ActorID" json:"actor,omitempty"`
+}
+
+// MaintenanceSchedule represents a recurring preventive maintenance task.
+// A task is due when either interval (km driven or days elapsed) is reached.
+type MaintenanceSchedule struct {
+	ID           uint           `gorm:"primaryKey" json:"id"`
+	TenantID     uint           `gorm:"not null;index" json:"tenant_id"`
+	TaxiID       uint           `gorm:"not null;index" json:"taxi_id"`
+	Task         string         `gorm:"not null" json:"task"`
+	IntervalKm   int            `gorm:"not null;default:0" json:"interval_km"`   // 0 disables the km interval
+	IntervalDays int            `gorm:"not null;default:0" json:"interval_days"` // 0 disables the days interval
+	LastDoneAt   time.Time      `gorm:"not null" json:"last_done_at"`
+	LastDoneKm   int            `gorm:"not null;default:0" json:"last_done_km"`
+	NotifiedAt   *time.Time     `json:"notified_at"` // Set once a due alert was sent, cleared on completion
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+
+	Taxi Taxi `gorm:"foreignKey:TaxiID" json:"taxi,omitempty"`
+}
+
+// Part represents a spare part kept in a tenant's garage inventory
+type Part struct {
+	ID             uint           `gorm:"primaryKey" json:"id"`
+	TenantID       uint           `gorm:"not null;index" json:"tenant_id"`
+	Name           string         `gorm:"not null" json:"name"`
+	SKU            string         `json:"sku"`
+	Unit           string         `gorm:"not null;default:'pcs'" json:"unit"` // pcs, l, kg...
+	UnitCost       money.Amount   `gorm:"not null;default:0" json:"unit_cost"`
+	QuantityOnHand float64        `gorm:"not null;default:0" json:"quantity_on_hand"`
+	ReorderLevel   float64        `gorm:"not null;default:0" json:"reorder_level"` // Low-stock threshold
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// StockMovement represents a change in the quantity on hand of a part.
+// Quantity is positive for stock received and negative for stock consumed.
+type StockMovement struct {
+	ID               uint         `gorm:"primaryKey" json:"id"`
+	TenantID         uint         `gorm:"not null;index" json:"tenant_id"`
+	PartID           uint         `gorm:"not null;index" json:"part_id"`
+	Type             string       `gorm:"not null" json:"type"` // in, out, adjustment
+	Quantity         float64      `gorm:"not null" json:"quantity"`
+	UnitCost         money.Amount `gorm:"not null;default:0" json:"unit_cost"`
+	MaintenanceLogID *uint        `gorm:"index" json:"maintenance_log_id"` // Work order the part was consumed on
+	Notes            string       `gorm:"type:text" json:"notes"`
+	CreatedByID      uint         `gorm:"not null" json:"created_by_id"`
+	CreatedAt        time.Time    `json:"created_at"`
+
+	Part      Part `gorm:"foreignKey:PartID" json:"part,omitempty"`
+	CreatedBy User `gorm:"foreignKey:CreatedByID" json:"created_by,omitempty"`
+}
+
+// DriverLedgerEntry is a movement on a driver's debt to the tenant: an advance
+// or a fine charged to the driver raises it, a repayment or an offset against
+// a report's driver share lowers it
+type DriverLedgerEntry struct {
+	ID          uint         `gorm:"primaryKey" json:"id"`
+	TenantID    uint         `gorm:"not null;index" json:"tenant_id"`
+	DriverID    uint         `gorm:"not null;index" json:"driver_id"`
+	Type        string       `gorm:"not null" json:"type"`   // advance, fine, repayment, offset
+	Amount      money.Amount `gorm:"not null" json:"amount"` // Always positive
+	ReportID    *uint        `json:"report_id"`              // Set for offsets
+	FineID      *uint        `json:"fine_id,omitempty"`      // Set for fines
+	Notes       string       `gorm:"type:text" json:"notes"`
+	CreatedByID uint         `gorm:"not null" json:"created_by_id"`
+	CreatedAt   time.Time    `json:"created_at"`
+
+	Driver    User `gorm:"foreignKey:DriverID" json:"driver,omitempty"`
+	CreatedBy User `gorm:"foreignKey:CreatedByID" json:"created_by,omitempty"`
+}
+
+// Trip is a single ride logged by the driver app
+type Trip struct {
+	ID         uint         `gorm:"primaryKey" json:"id"`
+	TenantID   uint         `gorm:"not null;index" json:"tenant_id"`
+	TaxiID     uint         `gorm:"not null;index" json:"taxi_id"`
+	DriverID   uint         `gorm:"not null;index" json:"driver_id"`
+	StartedAt  time.Time    `gorm:"not null" json:"started_at"`
+	EndedAt    time.Time    `gorm:"not null" json:"ended_at"`
+	Fare       money.Amount `gorm:"not null" json:"fare"`
+	DistanceKm float64      `gorm:"not null;default:0" json:"distance_km"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+// PlatformEarning is what a driver earned through a ride-hailing platform on
+// one day, according to the platform's statement
+type PlatformEarning struct {
+	ID          uint         `gorm:"primaryKey" json:"id"`
+	TenantID    uint         `gorm:"not null;index" json:"tenant_id"`
+	DriverID    uint         `gorm:"not null" json:"driver_id"`
+	Platform    string       `gorm:"not null" json:"platform"` // uber, bolt, yango
+	Date        time.Time    `gorm:"type:date;not null" json:"date"`
+	Amount      money.Amount `gorm:"not null" json:"amount"`
+	CreatedByID *uint        `json:"created_by_id"` // Nil for API keys
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// Fine is a traffic fine received for a taxi. The driver assigned at the time
+// of the offense is charged through their ledger when responsible for it.
+type Fine struct {
+	ID             uint         `gorm:"primaryKey" json:"id"`
+	TenantID       uint         `gorm:"not null;index" json:"tenant_id"`
+	TaxiID         uint         `gorm:"not null;index" json:"taxi_id"`
+	DriverID       *uint        `gorm:"index" json:"driver_id"` // Nil when no driver was assigned
+	OffenseAt      time.Time    `gorm:"not null" json:"offense_at"`
+	Reference      string       `json:"reference"` // Number of the notice
+	Description    string       `gorm:"type:text" json:"description"`
+	Amount         money.Amount `gorm:"not null" json:"amount"`
+	DueDate        time.Time    `gorm:"type:date;not null" json:"due_date"`
+	Responsibility string       `gorm:"not null;default:'owner'" json:"responsibility"` // driver, owner
+	PaidAt         *time.Time   `json:"paid_at"`                                        // Nil while unpaid
+	CreatedByID    uint         `gorm:"not null" json:"created_by_id"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+
+	Taxi   Taxi  `gorm:"foreignKey:TaxiID" json:"taxi,omitempty"`
+	Driver *User `gorm:"foreignKey:DriverID" json:"driver,omitempty"`
+}
+
+// FuelCard is a fuel card of the fleet. Its transactions become fuel expenses
+// of the taxi it is assigned to.
+type FuelCard struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	TenantID    uint      `gorm:"not null;index" json:"tenant_id"`
+	CardNumber  string    `gorm:"not null;uniqueIndex" json:"card_number"` // Without spaces
+	Label       string    `json:"label"`
+	TaxiID      *uint     `json:"taxi_id"` // Nil while not assigned
+	CreatedByID uint      `gorm:"not null" json:"created_by_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	Taxi *Taxi `gorm:"foreignKey:TaxiID" json:"taxi,omitempty"`
+}
+
+// FuelCardTransaction is a purchase pulled from the fuel card provider. A
+// flagged transaction needs the owner's attention.
+type FuelCardTransaction struct {
+	ID         uint         `gorm:"primaryKey" json:"id"`
+	TenantID   uint         `gorm:"not null;index" json:"tenant_id"`
+	FuelCardID *uint        `json:"fuel_card_id"`
+	ExternalID string       `gorm:"not null;uniqueIndex" json:"external_id"` // The provider's transaction ID
+	TaxiID     *uint        `json:"taxi_id"`                                 // Nil when the card was not assigned
+	ExpenseID  *uint        `json:"expense_id"`                              // The fuel expense created for it
+	OccurredAt time.Time    `gorm:"not null" json:"occurred_at"`
+	Amount     money.Amount `gorm:"not null" json:"amount"`
+	Liters     float64      `json:"liters"`
+	Station    string       `json:"station"`
+	FlagReason string       `json:"flag_reason,omitempty"` // Empty unless flagged
+	CreatedAt  time.Time    `json:"created_at"`
+
+	FuelCard *FuelCard `gorm:"foreignKey:FuelCardID" json:"fuel_card,omitempty"`
+	Taxi     *Taxi     `gorm:"foreignKey:TaxiID" json:"taxi,omitempty"`
+}
+
+// GeoPoint is a WGS 84 coordinate
+type GeoPoint struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// Geofence is an area the tenant's taxis operate in. Together, the active
+// geofences of a tenant make up its operating zone.
+type Geofence struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	TenantID  uint       `gorm:"not null;index" json:"tenant_id"`
+	Name      string     `gorm:"not null" json:"name"`
+	Polygon   []GeoPoint `gorm:"type:jsonb;serializer:json;not null" json:"polygon"` // Vertices in order, not closed
+	Active    bool       `gorm:"not null;default:true" json:"active"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// TaxiPosition is a location reported for a taxi
+type TaxiPosition struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	TenantID    uint      `gorm:"not null;index" json:"tenant_id"`
+	TaxiID      uint      `gorm:"not null;index" json:"taxi_id"`
+	DriverID    *uint     `json:"driver_id"` // Assigned at RecordedAt; nil when none was
+	Latitude    float64   `gorm:"not null" json:"latitude"`
+	Longitude   float64   `gorm:"not null" json:"longitude"`
+	RecordedAt  time.Time `gorm:"not null" json:"recorded_at"`
+	OutsideZone bool      `gorm:"not null;default:false" json:"outside_zone"`
+	Unscheduled bool      `gorm:"not null;default:false" json:"unscheduled"` // No driver was assigned
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Assignment represents a period during which a driver was assigned to a taxi.
+// The current assignment has no end date.
+type Assignment struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	TenantID     uint       `gorm:"not null;index" json:"tenant_id"`
+	TaxiID       uint       `gorm:"not null;index" json:"taxi_id"`
+	DriverID     uint       `gorm:"not null;index" json:"driver_id"`
+	StartedAt    time.Time  `gorm:"not null" json:"started_at"`
+	EndedAt      *time.Time `json:"ended_at"`
+	AssignedByID *uint      `json:"assigned_by_id"`
+	CreatedAt    time.Time  `json:"created_at"`
+
+	Driver     User  `gorm:"foreignKey:DriverID" json:"driver,omitempty"`
+	AssignedBy *User `gorm:"foreignKey:AssignedByID" json:"assigned_by,omitempty"`
+}
+
+// JobRun records one execution of a background job
+type JobRun struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	JobName    string     `gorm:"not null;index" json:"job_name"`
+	Status     string     `gorm:"not null;default:'running'" json:"status"` // running, succeeded, failed
+	Error      string     `gorm:"type:text" json:"error,omitempty"`
+	StartedAt  time.Time  `gorm:"not null" json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
+
+// IdempotencyKey stores the response of a create request so retries with the
+// same key replay it instead of creating a duplicate
+type IdempotencyKey struct {
+	ID           uint   `gorm:"primaryKey"`
+	UserID       uint   `gorm:"not null"`
+	Key          string `gorm:"not null"`
+	RequestHash  string `gorm:"not null"`
+	StatusCode   int    `gorm:"not null;default:0"` // 0 while the original request is in flight
+	ResponseBody []byte
+	CreatedAt    time.Time
+}
+
+// APIKey authenticates a third-party system against a tenant's data with
+// limited, read-only scopes
+type APIKey struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	TenantID    uint       `gorm:"not null;index" json:"tenant_id"`
+	Name        string     `gorm:"not null" json:"name"`
+	Prefix      string     `gorm:"not null" json:"prefix"` // Start of the key, to recognize it
+	KeyHash     string     `gorm:"uniqueIndex;not null" json:"-"`
+	Scopes      string     `gorm:"not null" json:"scopes"` // Comma-separated, e.g. reports:read,expenses:read
+	CreatedByID *uint      `json:"created_by_id"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	RevokedAt   *time.Time `json:"revoked_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// OAuthIdentity links a user to an account at an external sign-in provider
+type OAuthIdentity struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;index" json:"user_id"`
+	Provider  string    `gorm:"not null" json:"provider"` // google
+	Subject   string    `gorm:"not null" json:"subject"`  // The provider's stable user ID
+	Email     string    `gorm:"not null" json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LoginAudit records a successful or failed login attempt
+type LoginAudit struct {
+	ID                uint      `gorm:"primaryKey" json:"id"`
+	UserID            *uint     `gorm:"index" json:"user_id,omitempty"` // Nil when no user matched
+	Identifier        string    `gorm:"not null" json:"identifier"`     // The email or phone the attempt was made with
+	Method            string    `gorm:"not null" json:"method"`         // password, google
+	Success           bool      `gorm:"not null" json:"success"`
+	FailureReason     string    `json:"failure_reason,omitempty"`
+	IPAddress         string    `json:"ip_address"`
+	UserAgent         string    `json:"user_agent"`
+	DeviceFingerprint string    `json:"device_fingerprint"`
+	CreatedAt         time.Time `json:"created_at"`
+}
@@ -1,11 +1,26 @@
 package repository
 
 import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
+
+	"taxifleet/backend/internal/metrics"
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/tracing"
 
 	"github.com/jmoiron/sqlx"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Repository struct {
@@ -21,45 +36,117 @@ func New(db *sqlx.DB) *Repository {
 	if err != nil {
 		panic("Failed to create GORM instance: " + err.Error())
 	}
+	if err := tracing.InstrumentGORM(gormDB); err != nil {
+		panic("Failed to instrument GORM: " + err.Error())
+	}
+	if err := metrics.InstrumentGORM(gormDB); err != nil {
+		panic("Failed to instrument GORM: " + err.Error())
+	}
 	return &Repository{db: gormDB}
 }
 
+// ErrVersionConflict is returned when a versioned record was modified by
+// someone else since it was read
+var ErrVersionConflict = errors.New("record was modified by someone else")
+
+// updateVersioned saves all fields of model only if its stored version still
+// matches, and bumps the version
+func (r *Repository) updateVersioned(ctx context.Context, model interface{}, version *int) error {
+	current := *version
+	*version = current + 1
+
+	result := r.conn(ctx).Model(model).
+		Where("version = ?", current).
+		Select("*").
+		Omit(clause.Associations).
+		Updates(model)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = ErrVersionConflict
+	}
+	if result.Error != nil {
+		*version = current
+	}
+	return result.Error
+}
+
+type txKey struct{}
+
+type tenantKey struct{}
+
+// conn returns the transaction carried by ctx, or the pool when there is none
+func (r *Repository) conn(ctx context.Context) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok && tx != nil {
+		return tx
+	}
+	return r.db.WithContext(ctx)
+}
+
+// InTransaction runs fn in a database transaction that commits when fn returns
+// nil. Repository calls made with the context passed to fn join the
+// transaction; nested calls use a savepoint. In a context from WithTenant the
+// transaction is scoped to the tenant by row-level security.
+func (r *Repository) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		if tenantID, ok := ctx.Value(tenantKey{}).(uint); ok {
+			// Local to the transaction, so it never leaks to the next user of
+			// the pooled connection
+			if err := tx.Exec("SELECT set_config('app.tenant_id', ?, true)", fmt.Sprint(tenantID)).Error; err != nil {
+				return err
+			}
+		}
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// WithTenant returns a context whose transactions only see and write rows of
+// the tenant, as enforced by the row-level security policies on the tenant
+// tables. Outside a transaction queries are not scoped.
+func WithTenant(ctx context.Context, tenantID uint) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// Detach returns a context for work that outlives the transaction carried by
+// ctx, such as sending notifications in the background
+func Detach(ctx context.Context) context.Context {
+	return context.WithValue(context.WithoutCancel(ctx), txKey{}, (*gorm.DB)(nil))
+}
+
 // User methods
-func (r *Repository) CreateUser(user *User) error {
-	return r.db.Create(user).Error
+func (r *Repository) CreateUser(ctx context.Context, user *User) error {
+	return r.conn(ctx).Create(user).Error
 }
 
-func (r *Repository) GetUserByID(id uint) (*User, error) {
+func (r *Repository) GetUserByID(ctx context.Context, id uint) (*User, error) {
 	var user User
-	err := r.db.Preload("Tenant").First(&user, id).Error
+	err := r.conn(ctx).Preload("Tenant").First(&user, id).Error
 	return &user, err
 }
 
-func (r *Repository) GetUserByEmail(email string) (*User, error) {
+func (r *Repository) GetUserByEmail(ctx context.Context, email string) (*User, error) {
 	var user User
-	err := r.db.Preload("Tenant").Where("email = ?", email).First(&user).Error
+	err := r.conn(ctx).Preload("Tenant").Where("email = ?", email).First(&user).Error
 	return &user, err
 }
 
-func (r *Repository) GetUserByPhone(phone string) (*User, error) {
+func (r *Repository) GetUserByPhone(ctx context.Context, phone string) (*User, error) {
 	var user User
-	err := r.db.Preload("Tenant").Where("phone = ?", phone).First(&user).Error
+	err := r.conn(ctx).Preload("Tenant").Where("phone = ?", phone).First(&user).Error
 	return &user, err
 }
 
-func (r *Repository) GetUserByPhoneNumber(phoneNumber string) (*User, error) {
+func (r *Repository) GetUserByPhoneNumber(ctx context.Context, phoneNumber string) (*User, error) {
 	// Search for phone numbers that match the number part (without country code)
 	// Format in DB is "+XX YYYYYYYY", so we search for " YYYYYYYY"
 	var user User
-	err := r.db.Preload("Tenant").Where("phone LIKE ?", "% "+phoneNumber).First(&user).Error
+	err := r.conn(ctx).Preload("Tenant").Where("phone LIKE ?", "% "+phoneNumber).First(&user).Error
 	return &user, err
 }
 
-func (r *Repository) GetUserByEmailOrPhone(emailOrPhone string) (*User, error) {
+func (r *Repository) GetUserByEmailOrPhone(ctx context.Context, emailOrPhone string) (*User, error) {
 	var user User
 
 	// First try email
-	err := r.db.Preload("Tenant").Where("email = ?", emailOrPhone).First(&user).Error
+	err := r.conn(ctx).Preload("Tenant").Where("email = ?", emailOrPhone).First(&user).Error
 	if err == nil {
 		return &user, nil
 	}
@@ -88,188 +175,2378 @@ func (r *Repository) GetUserByEmailOrPhone(emailOrPhone string) (*User, error) {
 	// Search for phone numbers that match the number part
 	// Format in DB is "+XX YYYYYYYY", so we search for " YYYYYYYY" (space + number)
 	// Also try exact match in case full format was provided
-	err = r.db.Preload("Tenant").Where("phone LIKE ? OR phone = ?", "% "+phoneNumber, emailOrPhone).First(&user).Error
+	err = r.conn(ctx).Preload("Tenant").Where("phone LIKE ? OR phone = ?", "% "+phoneNumber, emailOrPhone).First(&user).Error
 	return &user, err
 }
 
-func (r *Repository) UpdateUser(user *User) error {
-	return r.db.Save(user).Error
+func (r *Repository) UpdateUser(ctx context.Context, user *User) error {
+	return r.conn(ctx).Save(user).Error
 }
 
-func (r *Repository) GetAllUsers() ([]User, error) {
+func (r *Repository) GetAllUsers(ctx context.Context) ([]User, error) {
 	var users []User
-	err := r.db.Preload("Tenant").Find(&users).Error
+	err := r.conn(ctx).Preload("Tenant").Find(&users).Error
 	return users, err
 }
 
-func (r *Repository) GetUsersByTenant(tenantID uint) ([]User, error) {
+func (r *Repository) GetUsersByTenant(ctx context.Context, tenantID uint) ([]User, error) {
 	var users []User
-	err := r.db.Preload("Tenant").Where("tenant_id = ?", tenantID).Find(&users).Error
+	err := r.conn(ctx).Preload("Tenant").Where("tenant_id = ?", tenantID).Find(&users).Error
 	return users, err
 }
 
-func (r *Repository) DeleteUser(id uint) error {
-	return r.db.Delete(&User{}, id).Error
+func (r *Repository) DeleteUser(ctx context.Context, id uint) error {
+	return r.conn(ctx).Delete(&User{}, id).Error
 }
 
 // Tenant methods
-func (r *Repository) CreateTenant(tenant *Tenant) error {
-	return r.db.Create(tenant).Error
+func (r *Repository) CreateTenant(ctx context.Context, tenant *Tenant) error {
+	return r.conn(ctx).Create(tenant).Error
 }
 
-func (r *Repository) GetTenantByID(id uint) (*Tenant, error) {
+func (r *Repository) GetTenantByID(ctx context.Context, id uint) (*Tenant, error) {
 	var tenant Tenant
-	err := r.db.First(&tenant, id).Error
+	err := r.conn(ctx).First(&tenant, id).Error
 	return &tenant, err
 }
 
-func (r *Repository) GetTenantBySubdomain(subdomain string) (*Tenant, error) {
+func (r *Repository) GetTenantBySubdomain(ctx context.Context, subdomain string) (*Tenant, error) {
 	var tenant Tenant
-	err := r.db.Where("subdomain = ?", subdomain).First(&tenant).Error
+	err := r.conn(ctx).Where("subdomain = ?", subdomain).First(&tenant).Error
 	return &tenant, err
 }
 
-func (r *Repository) GetAllTenants() ([]Tenant, error) {
+func (r *Repository) GetAllTenants(ctx context.Context) ([]Tenant, error) {
 	var tenants []Tenant
-	err := r.db.Find(&tenants).Error
+	err := r.conn(ctx).Find(&tenants).Error
 	return tenants, err
 }
 
-func (r *Repository) UpdateTenant(tenant *Tenant) error {
-	return r.db.Save(tenant).Error
+func (r *Repository) UpdateTenant(ctx context.Context, tenant *Tenant) error {
+	return r.conn(ctx).Save(tenant).Error
+}
+
+func (r *Repository) DeleteTenant(ctx context.Context, id uint) error {
+	return r.conn(ctx).Delete(&Tenant{}, id).Error
+}
+
+// tenantTables hold tenant-owned rows, listed children before parents
+var tenantTables = []string{
+	"audit_events", "api_keys", "policies", "stock_movements", "parts", "maintenance_schedules", "maintenance_logs", "assignments",
+	"fuel_card_transactions", "fuel_cards", "taxi_positions", "geofences", "export_counts",
+	"approval_delegations", "expenses", "report_attachments", "report_adjustments", "report_signatures", "report_rejections", "driver_ledger_entries", "fines", "trips", "platform_earnings", "weekly_reports",
+	"export_bundles", "closed_periods", "budgets", "bank_deposits", "bank_accounts", "device_tokens", "taxis",
+}
+
+// userTables hold rows owned by a user rather than directly by a tenant
+var userTables = []string{"sessions", "idempotency_keys", "oauth_identities", "login_audits", "notifications"}
+
+// CountTenantData counts every row a tenant owns per table, soft-deleted ones included
+func (r *Repository) CountTenantData(ctx context.Context, tenantID uint) (map[string]int64, error) {
+	counts := make(map[string]int64)
+	for _, table := range userTables {
+		var count int64
+		if err := r.conn(ctx).Table(table).Where("user_id IN (?)", r.tenantUserIDs(ctx, tenantID)).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		counts[table] = count
+	}
+	for _, table := range append(tenantTables, "users") {
+		var count int64
+		if err := r.conn(ctx).Table(table).Where("tenant_id = ?", tenantID).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		counts[table] = count
+	}
+	return counts, nil
+}
+
+// PurgeTenant permanently deletes the tenant and every row it owns and
+// returns how many rows were deleted per table. Run it in a transaction.
+func (r *Repository) PurgeTenant(ctx context.Context, tenantID uint) (map[string]int64, error) {
+	deleted := make(map[string]int64)
+	for _, table := range userTables {
+		result := r.conn(ctx).Exec("DELETE FROM "+table+" WHERE user_id IN (?)", r.tenantUserIDs(ctx, tenantID))
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		deleted[table] = result.RowsAffected
+	}
+	for _, table := range append(tenantTables, "users") {
+		result := r.conn(ctx).Exec("DELETE FROM "+table+" WHERE tenant_id = ?", tenantID)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		deleted[table] = result.RowsAffected
+	}
+
+	result := r.conn(ctx).Unscoped().Delete(&Tenant{}, tenantID)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return deleted, nil
+}
+
+// tenantUserIDs is a subquery selecting the IDs of all the tenant's users
+func (r *Repository) tenantUserIDs(ctx context.Context, tenantID uint) *gorm.DB {
+	return r.conn(ctx).Unscoped().Model(&User{}).Select("id").Where("tenant_id = ?", tenantID)
+}
+
+// DumpTenant writes the tenant and every row it owns, soft-deleted ones
+// included, as a SQL script of INSERT statements, parents before children.
+// It restores into a database with the same schema, such as a staging copy.
+func (r *Repository) DumpTenant(ctx context.Context, tenantID uint, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "-- Tenant %d, dumped %s\nSET standard_conforming_strings = on;\n", tenantID, time.Now().UTC().Format(time.RFC3339))
+
+	queries := []struct{ table, where string }{
+		{"tenants", "id = ?"},
+		{"users", "tenant_id = ?"},
+	}
+	for i := len(tenantTables) - 1; i >= 0; i-- {
+		queries = append(queries, struct{ table, where string }{tenantTables[i], "tenant_id = ?"})
+	}
+	for _, table := range userTables {
+		queries = append(queries, struct{ table, where string }{table, "user_id IN (SELECT id FROM users WHERE tenant_id = ?)"})
+	}
+
+	for _, query := range queries {
+		if err := r.dumpRows(ctx, bw, query.table, query.where, tenantID); err != nil {
+			return fmt.Errorf("dump %s: %w", query.table, err)
+		}
+	}
+	return bw.Flush()
+}
+
+// dumpRows writes an INSERT statement per matching row of the table, then
+// moves the table's ID sequence past the inserted IDs. Generated columns are
+// left out, as they cannot be inserted.
+func (r *Repository) dumpRows(ctx context.Context, w io.Writer, table, where string, args ...interface{}) error {
+	var names []string
+	err := r.conn(ctx).Raw(`SELECT column_name FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = ? AND is_generated = 'NEVER'
+		ORDER BY ordinal_position`, table).Scan(&names).Error
+	if err != nil {
+		return err
+	}
+
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = `"` + name + `"`
+	}
+	list := strings.Join(quoted, ", ")
+	rows, err := r.conn(ctx).Raw("SELECT "+list+" FROM "+table+" WHERE "+where, args...).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columns, err := rows.ColumnTypes()
+	if err != nil {
+		return err
+	}
+	serialID := false
+	for _, column := range columns {
+		if column.Name() == "id" {
+			serialID = column.DatabaseTypeName() == "INT4" || column.DatabaseTypeName() == "INT8"
+		}
+	}
+	insert := "INSERT INTO " + table + " (" + list + ") VALUES ("
+
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	count := 0
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return err
+		}
+		literals := make([]string, len(values))
+		for i, value := range values {
+			literals[i] = sqlLiteral(value, columns[i].DatabaseTypeName())
+		}
+		if _, err := io.WriteString(w, insert+strings.Join(literals, ", ")+");\n"); err != nil {
+			return err
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if serialID && count > 0 {
+		_, err = fmt.Fprintf(w, "SELECT setval(pg_get_serial_sequence('%[1]s', 'id'), (SELECT MAX(id) FROM %[1]s));\n", table)
+	}
+	return err
+}
+
+// sqlLiteral renders a scanned column value as a SQL literal
+func sqlLiteral(value interface{}, databaseType string) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case time.Time:
+		return quoteLiteral(v.Format("2006-01-02 15:04:05.999999999Z07:00"))
+	case []byte:
+		if databaseType == "BYTEA" {
+			return `'\x` + hex.EncodeToString(v) + `'`
+		}
+		return quoteLiteral(string(v))
+	default:
+		return quoteLiteral(fmt.Sprint(v))
+	}
+}
+
+// quoteLiteral quotes a string, assuming standard_conforming_strings
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// retentionTables hold the soft-deleted records retention removes for good.
+// Rows referencing them are deleted with them or unlinked. Taxis and users
+// are kept, since their reports and history would go with them.
+var retentionTables = []string{"expenses", "bank_deposits", "bank_accounts", "maintenance_logs", "weekly_reports"}
+
+// RetentionCutoffs are the dates before which a tenant's data expires; a nil
+// cutoff keeps the data
+type RetentionCutoffs struct {
+	Positions *time.Time // Taxi positions recorded before
+	Deleted   *time.Time // Records soft-deleted before
+}
+
+// expiredRows is a table's condition matching the rows past a cutoff
+type expiredRows struct {
+	table     string
+	condition string
+	cutoff    time.Time
+}
+
+func (c RetentionCutoffs) rows() []expiredRows {
+	var rows []expiredRows
+	if c.Positions != nil {
+		rows = append(rows, expiredRows{"taxi_positions", "recorded_at < ?", *c.Positions})
+	}
+	if c.Deleted != nil {
+		for _, table := range retentionTables {
+			rows = append(rows, expiredRows{table, "deleted_at < ?", *c.Deleted})
+		}
+	}
+	return rows
+}
+
+// CountExpiredData counts per table the tenant's rows past the cutoffs
+func (r *Repository) CountExpiredData(ctx context.Context, tenantID uint, cutoffs RetentionCutoffs) (map[string]int64, error) {
+	counts := make(map[string]int64)
+	for _, rows := range cutoffs.rows() {
+		var count int64
+		err := r.conn(ctx).Raw("SELECT COUNT(*) FROM "+rows.table+" WHERE tenant_id = ? AND "+rows.condition, tenantID, rows.cutoff).Scan(&count).Error
+		if err != nil {
+			return nil, err
+		}
+		counts[rows.table] = count
+	}
+	return counts, nil
+}
+
+// PurgeExpiredData deletes for good the tenant's rows past the cutoffs and
+// returns how many it deleted per table
+func (r *Repository) PurgeExpiredData(ctx context.Context, tenantID uint, cutoffs RetentionCutoffs) (map[string]int64, error) {
+	deleted := make(map[string]int64)
+	for _, rows := range cutoffs.rows() {
+		result := r.conn(ctx).Exec("DELETE FROM "+rows.table+" WHERE tenant_id = ? AND "+rows.condition, tenantID, rows.cutoff)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		deleted[rows.table] = result.RowsAffected
+	}
+	return deleted, nil
+}
+
+// TenantUsage counts what a tenant stores, for monitoring and billing
+type TenantUsage struct {
+	UserCount      int64
+	TaxiCount      int64
+	UploadCount    int64      // Expense receipts, deposit proofs and report attachments
+	LastActivityAt *time.Time // Latest login or change to the tenant's data
+}
+
+func (r *Repository) GetTenantUsage(ctx context.Context, tenantID uint) (*TenantUsage, error) {
+	var usage TenantUsage
+	err := r.conn(ctx).Raw(`
+		SELECT
+			(SELECT COUNT(*) FROM users WHERE tenant_id = @tenant AND deleted_at IS NULL) AS user_count,
+			(SELECT COUNT(*) FROM taxis WHERE tenant_id = @tenant AND deleted_at IS NULL) AS taxi_count,
+			(SELECT COUNT(*) FROM expenses WHERE tenant_id = @tenant AND deleted_at IS NULL AND receipt_url <> '') +
+			(SELECT COUNT(*) FROM bank_deposits WHERE tenant_id = @tenant AND deleted_at IS NULL AND proof_url <> '') +
+			(SELECT COUNT(*) FROM report_attachments WHERE tenant_id = @tenant) AS upload_count,
+			GREATEST(
+				(SELECT MAX(s.created_at) FROM sessions s JOIN users u ON u.id = s.user_id WHERE u.tenant_id = @tenant),
+				(SELECT MAX(updated_at) FROM weekly_reports WHERE tenant_id = @tenant),
+				(SELECT MAX(updated_at) FROM expenses WHERE tenant_id = @tenant),
+				(SELECT MAX(updated_at) FROM bank_deposits WHERE tenant_id = @tenant),
+				(SELECT MAX(updated_at) FROM taxis WHERE tenant_id = @tenant)
+			) AS last_activity_at`,
+		sql.Named("tenant", tenantID),
+	).Scan(&usage).Error
+	return &usage, err
+}
+
+// MonthlyCount is a number of records created in a calendar month
+type MonthlyCount struct {
+	Month time.Time
+	Count int64
+}
+
+// CountReportsByMonth counts the tenant's reports per month of their week start, from the given date on
+func (r *Repository) CountReportsByMonth(ctx context.Context, tenantID uint, from time.Time) ([]MonthlyCount, error) {
+	var counts []MonthlyCount
+	err := r.conn(ctx).Model(&WeeklyReport{}).
+		Where("tenant_id = ? AND week_start_date >= ?", tenantID, from).
+		Select("date_trunc('month', week_start_date) AS month, COUNT(*) AS count").
+		Group("month").Order("month").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// ListVersion identifies the state of a tenant's list, so clients can
+// revalidate it without the list being loaded
+type ListVersion struct {
+	Count        int64
+	LastModified time.Time // Zero for a tenant that never had any rows
+}
+
+// Keyset is where a page of a list ordered newest first by a date and then
+// ID ends; the next page starts after it
+type Keyset struct {
+	Date time.Time
+	ID   uint
+}
+
+// listVersion counts the tenant's live rows of table and finds the latest
+// change, deletions included, to them or to the related tables whose rows
+// are embedded in the list
+func (r *Repository) listVersion(ctx context.Context, tenantID uint, table string, related ...string) (*ListVersion, error) {
+	changes := make([]string, 0, len(related)+1)
+	for _, t := range append([]string{table}, related...) {
+		changes = append(changes, fmt.Sprintf("(SELECT MAX(GREATEST(updated_at, deleted_at)) FROM %s WHERE tenant_id = @tenant)", t))
+	}
+
+	var result struct {
+		Count        int64
+		LastModified *time.Time
+	}
+	err := r.conn(ctx).Raw(
+		fmt.Sprintf("SELECT (SELECT COUNT(*) FROM %s WHERE tenant_id = @tenant AND deleted_at IS NULL) AS count, GREATEST(%s) AS last_modified",
+			table, strings.Join(changes, ", ")),
+		sql.Named("tenant", tenantID),
+	).Scan(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	version := &ListVersion{Count: result.Count}
+	if result.LastModified != nil {
+		version.LastModified = *result.LastModified
+	}
+	return version, nil
+}
+
+// searchQuery turns free text into a tsquery matching every word as a prefix,
+// e.g. "AB-12 jean" becomes "ab:* & 12:* & jean:*". It returns "" when the
+// text has no words.
+func searchQuery(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsDigit(c)
+	})
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+// search applies a full-text query, given by searchQuery, to a query over
+// table, matching its own search_vector or those of the users and taxis it
+// references. Best matches come first, ties in the given order.
+func search(db *gorm.DB, table, query string, users []string, taxis []string, order string) *gorm.DB {
+	conditions := []string{table + ".search_vector @@ to_tsquery('simple', @q)"}
+	for _, column := range users {
+		conditions = append(conditions, fmt.Sprintf("%s.%s IN (SELECT id FROM users WHERE tenant_id = %s.tenant_id AND search_vector @@ to_tsquery('simple', @q))", table, column, table))
+	}
+	for _, column := range taxis {
+		conditions = append(conditions, fmt.Sprintf("%s.%s IN (SELECT id FROM taxis WHERE tenant_id = %s.tenant_id AND search_vector @@ to_tsquery('simple', @q))", table, column, table))
+	}
+	rank := "ts_rank(" + table + ".search_vector, to_tsquery('simple', ?)) DESC"
+	if order != "" {
+		rank += ", " + order
+	}
+	return db.Where("("+strings.Join(conditions, " OR ")+")", sql.Named("q", query)).
+		Clauses(clause.OrderBy{Expression: clause.Expr{SQL: rank, Vars: []interface{}{query}}})
+}
+
+// userSummary and taxiSummary preload only the columns lists show of the
+// users and taxis their records reference
+func userSummary(db *gorm.DB) *gorm.DB {
+	return db.Select("id", "tenant_id", "first_name", "last_name", "email", "phone")
+}
+
+func taxiSummary(db *gorm.DB) *gorm.DB {
+	return db.Select("id", "tenant_id", "license_plate", "model", "status")
+}
+
+// Plan methods
+func (r *Repository) CreatePlan(ctx context.Context, plan *Plan) error {
+	return r.conn(ctx).Create(plan).Error
+}
+
+func (r *Repository) GetPlanByID(ctx context.Context, id uint) (*Plan, error) {
+	var plan Plan
+	err := r.conn(ctx).First(&plan, id).Error
+	return &plan, err
+}
+
+func (r *Repository) GetPlans(ctx context.Context) ([]Plan, error) {
+	var plans []Plan
+	err := r.conn(ctx).Order("id").Find(&plans).Error
+	return plans, err
+}
+
+func (r *Repository) UpdatePlan(ctx context.Context, plan *Plan) error {
+	return r.conn(ctx).Save(plan).Error
+}
+
+func (r *Repository) DeletePlan(ctx context.Context, id uint) error {
+	return r.conn(ctx).Delete(&Plan{}, id).Error
+}
+
+// PlanInUse reports whether a tenant, deleted ones included, is on the plan
+func (r *Repository) PlanInUse(ctx context.Context, id uint) (bool, error) {
+	var count int64
+	err := r.conn(ctx).Unscoped().Model(&Tenant{}).Where("plan_id = ?", id).Limit(1).Count(&count).Error
+	return count > 0, err
+}
+
+// PlanUsage is what a tenant uses of the limits of its plan
+type PlanUsage struct {
+	TaxiCount   int64
+	UserCount   int64
+	ExportCount int64 // In the month asked for
+}
+
+func (r *Repository) GetPlanUsage(ctx context.Context, tenantID uint, month time.Time) (*PlanUsage, error) {
+	var usage PlanUsage
+	err := r.conn(ctx).Raw(`
+		SELECT
+			(SELECT COUNT(*) FROM taxis WHERE tenant_id = @tenant AND deleted_at IS NULL) AS taxi_count,
+			(SELECT COUNT(*) FROM users WHERE tenant_id = @tenant AND deleted_at IS NULL) AS user_count,
+			COALESCE((SELECT count FROM export_counts WHERE tenant_id = @tenant AND month = @month), 0) AS export_count`,
+		sql.Named("tenant", tenantID), sql.Named("month", month),
+	).Scan(&usage).Error
+	return &usage, err
+}
+
+// RecordExport counts an export of the tenant in the month
+func (r *Repository) RecordExport(ctx context.Context, tenantID uint, month time.Time) error {
+	return r.conn(ctx).Exec(`
+		INSERT INTO export_counts (tenant_id, month, count) VALUES (?, ?, 1)
+		ON CONFLICT (tenant_id, month) DO UPDATE SET count = export_counts.count + 1`,
+		tenantID, month,
+	).Error
+}
+
+// SystemSetting methods
+func (r *Repository) GetSystemSettings(ctx context.Context) ([]SystemSetting, error) {
+	var settings []SystemSetting
+	err := r.conn(ctx).Order("key").Find(&settings).Error
+	return settings, err
+}
+
+// SaveSystemSetting inserts or replaces the setting
+func (r *Repository) SaveSystemSetting(ctx context.Context, setting *SystemSetting) error {
+	return r.conn(ctx).Save(setting).Error
+}
+
+func (r *Repository) DeleteSystemSetting(ctx context.Context, key string) error {
+	return r.conn(ctx).Where("key = ?", key).Delete(&SystemSetting{}).Error
+}
+
+func (r *Repository) CreateSystemSettingChange(ctx context.Context, change *SystemSettingChange) error {
+	return r.conn(ctx).Create(change).Error
+}
+
+// GetSystemSettingChanges returns the latest changes first, those of one
+// setting unless key is empty
+func (r *Repository) GetSystemSettingChanges(ctx context.Context, key string, limit int) ([]SystemSettingChange, error) {
+	var changes []SystemSettingChange
+	query := r.conn(ctx).Preload("ChangedBy")
+	if key != "" {
+		query = query.Where("key = ?", key)
+	}
+	err := query.Order("id DESC").Limit(limit).Find(&changes).Error
+	return changes, err
+}
+
+// Policy methods
+func (r *Repository) GetPolicies(ctx context.Context, tenantID uint) ([]Policy, error) {
+	var policies []Policy
+	err := r.conn(ctx).Where("tenant_id = ?", tenantID).Order("id").Find(&policies).Error
+	return policies, err
+}
+
+func (r *Repository) GetPolicyByID(ctx context.Context, id uint) (*Policy, error) {
+	var policy Policy
+	err := r.conn(ctx).First(&policy, id).Error
+	return &policy, err
+}
+
+func (r *Repository) CreatePolicy(ctx context.Context, policy *Policy) error {
+	return r.conn(ctx).Create(policy).Error
+}
+
+func (r *Repository) UpdatePolicy(ctx context.Context, policy *Policy) error {
+	return r.conn(ctx).Save(policy).Error
 }
 
-func (r *Repository) DeleteTenant(id uint) error {
-	return r.db.Delete(&Tenant{}, id).Error
+func (r *Repository) DeletePolicy(ctx context.Context, id uint) error {
+	return r.conn(ctx).Delete(&Policy{}, id).Error
 }
 
 // Taxi methods
-func (r *Repository) CreateTaxi(taxi *Taxi) error {
-	return r.db.Create(taxi).Error
+func (r *Repository) CreateTaxi(ctx context.Context, taxi *Taxi) error {
+	return r.conn(ctx).Create(taxi).Error
 }
 
-func (r *Repository) GetTaxiByID(id uint) (*Taxi, error) {
+func (r *Repository) GetTaxiByID(ctx context.Context, id uint) (*Taxi, error) {
 	var taxi Taxi
-	err := r.db.Preload("AssignedDriver").Preload("Tenant").First(&taxi, id).Error
+	err := r.conn(ctx).Preload("AssignedDriver").Preload("Tenant").First(&taxi, id).Error
 	return &taxi, err
 }
 
-func (r *Repository) GetTaxisByTenant(tenantID uint) ([]Taxi, error) {
+func (r *Repository) GetTaxisByTenant(ctx context.Context, tenantID uint) ([]Taxi, error) {
+	var taxis []Taxi
+	err := r.conn(ctx).Preload("AssignedDriver", userSummary).Where("tenant_id = ?", tenantID).Find(&taxis).Error
+	return taxis, err
+}
+
+// GetTaxiListVersion covers the taxis and their assigned drivers
+func (r *Repository) GetTaxiListVersion(ctx context.Context, tenantID uint) (*ListVersion, error) {
+	return r.listVersion(ctx, tenantID, "taxis", "users")
+}
+
+// CountTaxis counts the tenant's taxis, only those with the status when set
+func (r *Repository) CountTaxis(ctx context.Context, tenantID uint, status string) (int64, error) {
+	var count int64
+	db := r.conn(ctx).Model(&Taxi{}).Where("tenant_id = ?", tenantID)
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+	err := db.Count(&count).Error
+	return count, err
+}
+
+// SearchTaxis finds the tenant's taxis by license plate, model or assigned
+// driver name; limit 0 returns every match
+func (r *Repository) SearchTaxis(ctx context.Context, tenantID uint, query string, limit int) ([]Taxi, error) {
 	var taxis []Taxi
-	err := r.db.Preload("AssignedDriver").Where("tenant_id = ?", tenantID).Find(&taxis).Error
+	tsquery := searchQuery(query)
+	if tsquery == "" {
+		return taxis, nil
+	}
+	db := search(r.conn(ctx).Preload("AssignedDriver", userSummary).Where("taxis.tenant_id = ?", tenantID), "taxis", tsquery, []string{"assigned_driver_id"}, nil, "")
+	if limit > 0 {
+		db = db.Limit(limit)
+	}
+	err := db.Find(&taxis).Error
 	return taxis, err
 }
 
-func (r *Repository) UpdateTaxi(taxi *Taxi) error {
-	return r.db.Save(taxi).Error
+// LicensePlateExists checks, case-insensitively, whether another live taxi of the
+// tenant already uses the plate, ignoring the taxi with excludeID (0 to ignore none)
+func (r *Repository) LicensePlateExists(ctx context.Context, tenantID uint, licensePlate string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.conn(ctx).Model(&Taxi{}).
+		Where("tenant_id = ? AND UPPER(license_plate) = UPPER(?) AND id <> ?", tenantID, licensePlate, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// UpdateTaxi saves the taxi unless it changed since it was read, in which
+// case ErrVersionConflict is returned
+func (r *Repository) UpdateTaxi(ctx context.Context, taxi *Taxi) error {
+	return r.updateVersioned(ctx, taxi, &taxi.Version)
+}
+
+func (r *Repository) DeleteTaxi(ctx context.Context, id uint) error {
+	return r.conn(ctx).Delete(&Taxi{}, id).Error
+}
+
+// Taxi ledger methods
+func (r *Repository) GetApprovedReportsByTaxi(ctx context.Context, taxiID uint) ([]WeeklyReport, error) {
+	var reports []WeeklyReport
+	err := r.conn(ctx).Where("taxi_id = ? AND status = ?", taxiID, "approved").Order("week_start_date").Find(&reports).Error
+	return reports, err
+}
+
+// GetTaxiCashExpenses returns the approved expenses paid from the taxi's
+// cash: those on its approved reports and those on the taxi without a report
+func (r *Repository) GetTaxiCashExpenses(ctx context.Context, taxiID uint) ([]Expense, error) {
+	var expenses []Expense
+	err := r.conn(ctx).
+		Where("(taxi_id = ? AND report_id IS NULL) OR report_id IN (?)", taxiID,
+			r.conn(ctx).Model(&WeeklyReport{}).Select("id").Where("taxi_id = ? AND status = ?", taxiID, "approved")).
+		Where("status = ?", "approved").
+		Order("date").Find(&expenses).Error
+	return expenses, err
 }
 
-func (r *Repository) DeleteTaxi(id uint) error {
-	return r.db.Delete(&Taxi{}, id).Error
+func (r *Repository) GetDepositsByTaxi(ctx context.Context, taxiID uint) ([]BankDeposit, error) {
+	var deposits []BankDeposit
+	err := r.conn(ctx).Where("taxi_id = ?", taxiID).Order("deposit_date").Find(&deposits).Error
+	return deposits, err
 }
 
 // WeeklyReport methods
-func (r *Repository) CreateReport(report *WeeklyReport) error {
-	return r.db.Create(report).Error
+func (r *Repository) CreateReport(ctx context.Context, report *WeeklyReport) error {
+	return r.conn(ctx).Create(report).Error
 }
 
-func (r *Repository) GetReportByID(id uint) (*WeeklyReport, error) {
+func (r *Repository) GetReportByID(ctx context.Context, id uint) (*WeeklyReport, error) {
 	var report WeeklyReport
-	err := r.db.Preload("Taxi").Preload("Driver").Preload("ApprovedBy").Preload("Expenses").First(&report, id).Error
+	err := r.conn(ctx).Preload("Taxi").Preload("Driver").Preload("ApprovedBy").Preload("Expenses").Preload("Attachments").Preload("Adjustments.CreatedBy").Preload("Rejections", func(db *gorm.DB) *gorm.DB {
+		return db.Order("rejected_at")
+	}).Preload("Rejections.RejectedBy", userSummary).First(&report, id).Error
 	return &report, err
 }
 
-func (r *Repository) GetReportsByTenant(tenantID uint) ([]WeeklyReport, error) {
+// GetReportsByTenant returns the tenant's reports that are not archived
+func (r *Repository) GetReportsByTenant(ctx context.Context, tenantID uint) ([]WeeklyReport, error) {
 	var reports []WeeklyReport
-	err := r.db.Preload("Taxi").Preload("Driver").Where("tenant_id = ?", tenantID).Order("week_start_date DESC").Find(&reports).Error
+	err := r.conn(ctx).Preload("Taxi", taxiSummary).Preload("Driver", userSummary).Where("tenant_id = ? AND archived_at IS NULL", tenantID).Order("week_start_date DESC").Find(&reports).Error
 	return reports, err
 }
 
-func (r *Repository) GetReportsByDriver(driverID uint) ([]WeeklyReport, error) {
+func (r *Repository) GetReportsByDriver(ctx context.Context, tenantID, driverID uint) ([]WeeklyReport, error) {
 	var reports []WeeklyReport
-	err := r.db.Preload("Taxi").Where("driver_id = ?", driverID).Order("week_start_date DESC").Find(&reports).Error
+	err := r.conn(ctx).Preload("Taxi", taxiSummary).Where("tenant_id = ? AND driver_id = ? AND archived_at IS NULL", tenantID, driverID).Order("week_start_date DESC").Find(&reports).Error
 	return reports, err
 }
 
-func (r *Repository) UpdateReport(report *WeeklyReport) error {
-	return r.db.Save(report).Error
+// GetArchivedReports returns the tenant's archived reports, latest week
+// first; only the driver's own when driverID is set
+func (r *Repository) GetArchivedReports(ctx context.Context, tenantID, driverID uint) ([]WeeklyReport, error) {
+	var reports []WeeklyReport
+	db := r.conn(ctx).Preload("Taxi", taxiSummary).Preload("Driver", userSummary).Where("tenant_id = ? AND archived_at IS NOT NULL", tenantID)
+	if driverID != 0 {
+		db = db.Where("driver_id = ?", driverID)
+	}
+	err := db.Order("week_start_date DESC, id DESC").Find(&reports).Error
+	return reports, err
 }
 
-func (r *Repository) DeleteReport(id uint) error {
-	return r.db.Delete(&WeeklyReport{}, id).Error
+// ArchiveReports archives the tenant's approved and rejected reports of the
+// weeks before the date and returns how many it archived
+func (r *Repository) ArchiveReports(ctx context.Context, tenantID uint, before time.Time) (int64, error) {
+	result := r.conn(ctx).Model(&WeeklyReport{}).
+		Where("tenant_id = ? AND archived_at IS NULL AND status IN ? AND week_start_date < ?",
+			tenantID, []string{"approved", "rejected"}, before).
+		Update("archived_at", time.Now())
+	return result.RowsAffected, result.Error
 }
 
-// Expense methods
-func (r *Repository) CreateExpense(expense *Expense) error {
-	return r.db.Create(expense).Error
+// GetReportsPage returns up to limit of the tenant's unarchived reports
+// after the keyset, or from the start without one, latest week first; only the
+// driver's own when driverID is set
+func (r *Repository) GetReportsPage(ctx context.Context, tenantID, driverID uint, after *Keyset, limit int) ([]WeeklyReport, error) {
+	var reports []WeeklyReport
+	db := r.conn(ctx).Preload("Taxi", taxiSummary).Preload("Driver", userSummary).Where("tenant_id = ? AND archived_at IS NULL", tenantID)
+	if driverID != 0 {
+		db = db.Where("driver_id = ?", driverID)
+	}
+	if after != nil {
+		db = db.Where("(week_start_date, id) < (?::date, ?)", after.Date.Format("2006-01-02"), after.ID)
+	}
+	err := db.Order("week_start_date DESC, id DESC").Limit(limit).Find(&reports).Error
+	return reports, err
 }
 
-func (r *Repository) GetExpenseByID(id uint) (*Expense, error) {
-	var expense Expense
-	err := r.db.Preload("Taxi").Preload("CreatedBy").Preload("Report").First(&expense, id).Error
-	return &expense, err
+// GroupTotal is the number of records of a group and the sum of their amounts
+type GroupTotal struct {
+	Key   string       `json:"key"`
+	Count int64        `json:"count"`
+	Total money.Amount `json:"total"`
 }
 
-func (r *Repository) GetExpensesByTenant(tenantID uint) ([]Expense, error) {
-	var expenses []Expense
-	err := r.db.Preload("Taxi").Preload("CreatedBy").Where("tenant_id = ?", tenantID).Order("date DESC").Find(&expenses).Error
-	return expenses, err
+// reportGroups and expenseGroups are the keys, as SQL, summaries can group
+// the records by
+var (
+	reportGroups = map[string]string{
+		"status": "status",
+		"taxi":   "taxi_id::text",
+		"driver": "driver_id::text",
+		"month":  "to_char(week_start_date, 'YYYY-MM')",
+	}
+	expenseGroups = map[string]string{
+		"category": "category",
+		"taxi":     "COALESCE(taxi_id::text, '')",
+		"month":    "to_char(date, 'YYYY-MM')",
+	}
+)
+
+// CountReports counts the tenant's reports, only those with the status when
+// set and the driver's own when driverID is set
+func (r *Repository) CountReports(ctx context.Context, tenantID, driverID uint, status string) (int64, error) {
+	var count int64
+	db := r.conn(ctx).Model(&WeeklyReport{}).Where("tenant_id = ?", tenantID)
+	if driverID != 0 {
+		db = db.Where("driver_id = ?", driverID)
+	}
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+	err := db.Count(&count).Error
+	return count, err
 }
 
-func (r *Repository) GetExpensesByReport(reportID uint) ([]Expense, error) {
-	var expenses []Expense
-	err := r.db.Preload("Taxi").Preload("CreatedBy").Where("report_id = ?", reportID).Find(&expenses).Error
-	return expenses, err
+// SummarizeReports counts the tenant's reports and sums their earnings per
+// status, taxi, driver or month; only the driver's own when driverID is set
+func (r *Repository) SummarizeReports(ctx context.Context, tenantID, driverID uint, groupBy string) ([]GroupTotal, error) {
+	key, ok := reportGroups[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("unknown report group %q", groupBy)
+	}
+	var totals []GroupTotal
+	db := r.conn(ctx).Model(&WeeklyReport{}).
+		Select(key+" AS key, COUNT(*) AS count, COALESCE(SUM(earnings), 0) AS total").
+		Where("tenant_id = ?", tenantID)
+	if driverID != 0 {
+		db = db.Where("driver_id = ?", driverID)
+	}
+	err := db.Group("1").Order("1").Scan(&totals).Error
+	return totals, err
 }
 
-func (r *Repository) UpdateExpense(expense *Expense) error {
-	return r.db.Save(expense).Error
+// ReportExportRow is a report as exports list it, with the plate of its
+// taxi and the name of its driver
+type ReportExportRow struct {
+	ID               uint
+	WeekStartDate    time.Time
+	LicensePlate     string
+	DriverFirstName  string
+	DriverLastName   string
+	Earnings         money.Amount
+	TotalExpenses    money.Amount
+	TotalAdjustments money.Amount
+	DriverShare      *money.Amount
+	OwnerShare       *money.Amount
+	LedgerOffset     *money.Amount
+	Status           string
+	Notes            string
+	SignatureMethod  string // How the approval was signed off, empty when it wasn't
+	CreatedAt        time.Time
 }
 
-func (r *Repository) DeleteExpense(id uint) error {
-	return r.db.Delete(&Expense{}, id).Error
+// GetReportExportRows returns the tenant's reports for an export in one
+// query, latest week first; only the driver's own when driverID is set
+func (r *Repository) GetReportExportRows(ctx context.Context, tenantID, driverID uint) ([]ReportExportRow, error) {
+	var rows []ReportExportRow
+	db := r.conn(ctx).Table("weekly_reports wr").
+		Select(`wr.id, wr.week_start_date, COALESCE(t.license_plate, '') AS license_plate,
+			COALESCE(u.first_name, '') AS driver_first_name, COALESCE(u.last_name, '') AS driver_last_name,
+			wr.earnings, wr.total_expenses, wr.total_adjustments, wr.driver_share, wr.owner_share,
+			wr.ledger_offset, wr.status, COALESCE(wr.notes, '') AS notes,
+			COALESCE(rs.method, '') AS signature_method, wr.created_at`).
+		Joins("LEFT JOIN taxis t ON t.id = wr.taxi_id AND t.deleted_at IS NULL").
+		Joins("LEFT JOIN users u ON u.id = wr.driver_id AND u.deleted_at IS NULL").
+		Joins("LEFT JOIN report_signatures rs ON rs.report_id = wr.id").
+		Where("wr.tenant_id = ? AND wr.deleted_at IS NULL", tenantID)
+	if driverID != 0 {
+		db = db.Where("wr.driver_id = ?", driverID)
+	}
+	err := db.Order("wr.week_start_date DESC, wr.id DESC").Scan(&rows).Error
+	return rows, err
 }
 
-// BankDeposit methods
-func (r *Repository) CreateDeposit(deposit *BankDeposit) error {
-	return r.db.Create(deposit).Error
+// GetReportListVersion covers the reports and their taxis and drivers
+func (r *Repository) GetReportListVersion(ctx context.Context, tenantID uint) (*ListVersion, error) {
+	return r.listVersion(ctx, tenantID, "weekly_reports", "taxis", "users")
 }
 
-func (r *Repository) GetDepositByID(id uint) (*BankDeposit, error) {
-	var deposit BankDeposit
-	err := r.db.First(&deposit, id).Error
-	return &deposit, err
+// SearchReports finds the tenant's unarchived reports by notes, driver name or taxi
+// license plate, only the driver's own when driverID is set; limit 0 returns
+// every match
+func (r *Repository) SearchReports(ctx context.Context, tenantID, driverID uint, query string, limit int) ([]WeeklyReport, error) {
+	var reports []WeeklyReport
+	tsquery := searchQuery(query)
+	if tsquery == "" {
+		return reports, nil
+	}
+	db := r.conn(ctx).Preload("Taxi", taxiSummary).Preload("Driver", userSummary).Where("weekly_reports.tenant_id = ? AND weekly_reports.archived_at IS NULL", tenantID)
+	if driverID != 0 {
+		db = db.Where("weekly_reports.driver_id = ?", driverID)
+	}
+	db = search(db, "weekly_reports", tsquery, []string{"driver_id"}, []string{"taxi_id"}, "week_start_date DESC")
+	if limit > 0 {
+		db = db.Limit(limit)
+	}
+	err := db.Find(&reports).Error
+	return reports, err
 }
 
-func (r *Repository) GetDepositsByTenant(tenantID uint) ([]BankDeposit, error) {
-	var deposits []BankDeposit
-	err := r.db.Where("tenant_id = ?", tenantID).Order("deposit_date DESC").Find(&deposits).Error
-	return deposits, err
+// ReportExistsForWeek checks for another live report of the same taxi and driver
+// in the given week, ignoring the report with excludeID (0 to ignore none)
+func (r *Repository) ReportExistsForWeek(ctx context.Context, tenantID, taxiID, driverID uint, weekStartDate time.Time, excludeID uint) (bool, error) {
+	var count int64
+	err := r.conn(ctx).Model(&WeeklyReport{}).
+		Where("tenant_id = ? AND taxi_id = ? AND driver_id = ? AND week_start_date = ? AND id <> ?",
+			tenantID, taxiID, driverID, weekStartDate, excludeID).
+		Count(&count).Error
+	return count > 0, err
 }
 
-func (r *Repository) UpdateDeposit(deposit *BankDeposit) error {
-	return r.db.Save(deposit).Error
+// UpdateReport saves the report unless it changed since it was read, in which
+// case ErrVersionConflict is returned
+func (r *Repository) UpdateReport(ctx context.Context, report *WeeklyReport) error {
+	return r.updateVersioned(ctx, report, &report.Version)
 }
 
-func (r *Repository) DeleteDeposit(id uint) error {
-	return r.db.Delete(&BankDeposit{}, id).Error
+func (r *Repository) DeleteReport(ctx context.Context, id uint) error {
+	return r.conn(ctx).Delete(&WeeklyReport{}, id).Error
 }
 
-// Session methods
-func (r *Repository) CreateSession(session *Session) error {
-	return r.db.Create(session).Error
+// RecalculateReportExpenses sets the report's total expenses to the sum of its
+// approved expenses in a single statement
+func (r *Repository) RecalculateReportExpenses(ctx context.Context, reportID uint) error {
+	return r.conn(ctx).Exec(`
+		UPDATE weekly_reports SET total_expenses = (
+			SELECT COALESCE(SUM(amount), 0) FROM expenses
+			WHERE report_id = ? AND deleted_at IS NULL AND status = 'approved'
+		), version = version + 1, updated_at = NOW()
+		WHERE id = ?`, reportID, reportID).Error
 }
 
-func (r *Repository) GetSessionByToken(token string) (*Session, error) {
-	var session Session
-	err := r.db.Preload("User").Where("token = ? AND expires_at > NOW()", token).First(&session).Error
-	return &session, err
+// Report attachment methods
+func (r *Repository) CreateReportAttachment(ctx context.Context, attachment *ReportAttachment) error {
+	return r.conn(ctx).Create(attachment).Error
+}
+
+func (r *Repository) GetReportAttachmentByID(ctx context.Context, id uint) (*ReportAttachment, error) {
+	var attachment ReportAttachment
+	err := r.conn(ctx).First(&attachment, id).Error
+	return &attachment, err
+}
+
+func (r *Repository) GetReportAttachments(ctx context.Context, reportID uint) ([]ReportAttachment, error) {
+	var attachments []ReportAttachment
+	err := r.conn(ctx).Where("report_id = ?", reportID).Order("created_at").Find(&attachments).Error
+	return attachments, err
+}
+
+// GetReportAttachmentsByTenant returns the attachments of all the tenant's
+// reports, archived ones included
+func (r *Repository) GetReportAttachmentsByTenant(ctx context.Context, tenantID uint) ([]ReportAttachment, error) {
+	var attachments []ReportAttachment
+	err := r.conn(ctx).Where("tenant_id = ?", tenantID).Order("report_id, created_at").Find(&attachments).Error
+	return attachments, err
+}
+
+func (r *Repository) DeleteReportAttachment(ctx context.Context, id uint) error {
+	return r.conn(ctx).Delete(&ReportAttachment{}, id).Error
+}
+
+// GetPendingReportAttachments returns the attachments of every tenant waiting
+// for an antivirus scan, oldest first
+func (r *Repository) GetPendingReportAttachments(ctx context.Context, limit int) ([]ReportAttachment, error) {
+	var attachments []ReportAttachment
+	err := r.conn(ctx).Where("scan_status = ?", ScanPending).Order("created_at").Limit(limit).Find(&attachments).Error
+	return attachments, err
+}
+
+// UpdateReportAttachmentScan saves the scan status, threat and time of an attachment
+func (r *Repository) UpdateReportAttachmentScan(ctx context.Context, attachment *ReportAttachment) error {
+	return r.conn(ctx).Model(attachment).Select("scan_status", "scan_threat", "scanned_at").Updates(attachment).Error
+}
+
+// ExportBundle methods
+func (r *Repository) CreateExportBundle(ctx context.Context, bundle *ExportBundle) error {
+	return r.conn(ctx).Create(bundle).Error
+}
+
+func (r *Repository) GetExportBundleByID(ctx context.Context, id uint) (*ExportBundle, error) {
+	var bundle ExportBundle
+	err := r.conn(ctx).First(&bundle, id).Error
+	return &bundle, err
+}
+
+func (r *Repository) UpdateExportBundle(ctx context.Context, bundle *ExportBundle) error {
+	return r.conn(ctx).Save(bundle).Error
+}
+
+func (r *Repository) DeleteExportBundle(ctx context.Context, id uint) error {
+	return r.conn(ctx).Delete(&ExportBundle{}, id).Error
+}
+
+// GetExpiredExportBundles returns the bundles of every tenant that expired
+// before the time
+func (r *Repository) GetExpiredExportBundles(ctx context.Context, before time.Time) ([]ExportBundle, error) {
+	var bundles []ExportBundle
+	err := r.conn(ctx).Where("expires_at < ?", before).Order("expires_at").Find(&bundles).Error
+	return bundles, err
+}
+
+// GetPeriodReportAttachments returns the attachments of the tenant's reports
+// for weeks starting in the period
+func (r *Repository) GetPeriodReportAttachments(ctx context.Context, tenantID uint, from, to time.Time) ([]ReportAttachment, error) {
+	var attachments []ReportAttachment
+	err := r.conn(ctx).
+		Joins("JOIN weekly_reports ON weekly_reports.id = report_attachments.report_id").
+		Where("report_attachments.tenant_id = ? AND weekly_reports.deleted_at IS NULL", tenantID).
+		Where("weekly_reports.week_start_date BETWEEN ? AND ?", from, to).
+		Order("report_attachments.report_id, report_attachments.created_at").
+		Find(&attachments).Error
+	return attachments, err
+}
+
+// Report rejection methods
+func (r *Repository) CreateReportRejection(ctx context.Context, rejection *ReportRejection) error {
+	return r.conn(ctx).Create(rejection).Error
+}
+
+// Report adjustment methods
+func (r *Repository) CreateReportAdjustment(ctx context.Context, adjustment *ReportAdjustment) error {
+	return r.conn(ctx).Create(adjustment).Error
+}
+
+func (r *Repository) GetReportAdjustmentByID(ctx context.Context, id uint) (*ReportAdjustment, error) {
+	var adjustment ReportAdjustment
+	err := r.conn(ctx).First(&adjustment, id).Error
+	return &adjustment, err
+}
+
+func (r *Repository) DeleteReportAdjustment(ctx context.Context, id uint) error {
+	return r.conn(ctx).Delete(&ReportAdjustment{}, id).Error
+}
+
+// RecalculateReportAdjustments sets the report's total adjustments to its
+// bonuses less its penalties in a single statement
+func (r *Repository) RecalculateReportAdjustments(ctx context.Context, reportID uint) error {
+	return r.conn(ctx).Exec(`
+		UPDATE weekly_reports SET total_adjustments = (
+			SELECT COALESCE(SUM(CASE WHEN type = 'bonus' THEN amount ELSE -amount END), 0)
+			FROM report_adjustments WHERE report_id = ?
+		), version = version + 1, updated_at = NOW()
+		WHERE id = ?`, reportID, reportID).Error
+}
+
+// Report signature methods
+func (r *Repository) CreateReportSignature(ctx context.Context, signature *ReportSignature) error {
+	return r.conn(ctx).Create(signature).Error
+}
+
+// GetReportSignature returns the sign-off of the report's approval
+func (r *Repository) GetReportSignature(ctx context.Context, reportID uint) (*ReportSignature, error) {
+	var signature ReportSignature
+	err := r.conn(ctx).Where("report_id = ?", reportID).First(&signature).Error
+	return &signature, err
+}
+
+// Approval delegation methods
+func (r *Repository) CreateDelegation(ctx context.Context, delegation *ApprovalDelegation) error {
+	return r.conn(ctx).Create(delegation).Error
+}
+
+func (r *Repository) GetDelegationByID(ctx context.Context, id uint) (*ApprovalDelegation, error) {
+	var delegation ApprovalDelegation
+	err := r.conn(ctx).Preload("Delegator", userSummary).Preload("Delegate", userSummary).First(&delegation, id).Error
+	return &delegation, err
+}
+
+// GetDelegations returns the tenant's delegations, revoked ones included,
+// latest start first
+func (r *Repository) GetDelegations(ctx context.Context, tenantID uint) ([]ApprovalDelegation, error) {
+	var delegations []ApprovalDelegation
+	err := r.conn(ctx).Preload("Delegator", userSummary).Preload("Delegate", userSummary).
+		Where("tenant_id = ?", tenantID).Order("starts_on DESC, id DESC").Find(&delegations).Error
+	return delegations, err
+}
+
+// RevokeDelegation ends a delegation before its end date
+func (r *Repository) RevokeDelegation(ctx context.Context, id uint) error {
+	return r.conn(ctx).Model(&ApprovalDelegation{}).Where("id = ? AND revoked_at IS NULL", id).Update("revoked_at", time.Now()).Error
+}
+
+// HasActiveDelegation checks whether the user may approve the tenant's
+// reports on the day by a delegation that was not revoked
+func (r *Repository) HasActiveDelegation(ctx context.Context, tenantID, delegateID uint, day time.Time) (bool, error) {
+	var count int64
+	date := day.Format("2006-01-02")
+	err := r.conn(ctx).Model(&ApprovalDelegation{}).
+		Where("tenant_id = ? AND delegate_id = ? AND revoked_at IS NULL AND starts_on <= ?::date AND ends_on >= ?::date", tenantID, delegateID, date, date).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Driver ledger methods
+func (r *Repository) CreateLedgerEntry(ctx context.Context, entry *DriverLedgerEntry) error {
+	return r.conn(ctx).Create(entry).Error
+}
+
+func (r *Repository) GetLedgerEntries(ctx context.Context, driverID uint) ([]DriverLedgerEntry, error) {
+	var entries []DriverLedgerEntry
+	err := r.conn(ctx).Preload("CreatedBy").Where("driver_id = ?", driverID).Order("created_at DESC").Find(&entries).Error
+	return entries, err
+}
+
+// ledgerBalance sums a driver's entries into what they owe
+const ledgerBalance = "COALESCE(SUM(CASE WHEN l.type IN ('advance', 'fine') THEN l.amount ELSE -l.amount END), 0)"
+
+func (r *Repository) GetDriverBalance(ctx context.Context, driverID uint) (money.Amount, error) {
+	var balance money.Amount
+	err := r.conn(ctx).Table("driver_ledger_entries AS l").Select(ledgerBalance).Where("l.driver_id = ?", driverID).Scan(&balance).Error
+	return balance, err
+}
+
+// DriverBalance is what a driver owes the tenant
+type DriverBalance struct {
+	DriverID  uint         `json:"driver_id"`
+	FirstName string       `json:"first_name"`
+	LastName  string       `json:"last_name"`
+	Balance   money.Amount `json:"balance"`
+}
+
+// GetDriverBalances returns the balance of every driver of the tenant with
+// ledger entries, largest debt first
+func (r *Repository) GetDriverBalances(ctx context.Context, tenantID uint) ([]DriverBalance, error) {
+	var balances []DriverBalance
+	err := r.conn(ctx).Table("driver_ledger_entries AS l").
+		Select("l.driver_id, u.first_name, u.last_name, "+ledgerBalance+" AS balance").
+		Joins("JOIN users u ON u.id = l.driver_id").
+		Where("l.tenant_id = ?", tenantID).
+		Group("l.driver_id, u.first_name, u.last_name").
+		Order("balance DESC").
+		Scan(&balances).Error
+	return balances, err
+}
+
+// Trip methods
+
+// tripBatchSize is how many trips are inserted per statement
+const tripBatchSize = 100
+
+func (r *Repository) CreateTrips(ctx context.Context, trips []Trip) error {
+	return r.conn(ctx).CreateInBatches(trips, tripBatchSize).Error
+}
+
+// GetTrips returns the tenant's trips started in [from, to), those of one
+// driver unless driverID is 0, in order
+func (r *Repository) GetTrips(ctx context.Context, tenantID uint, driverID uint, from, to time.Time) ([]Trip, error) {
+	var trips []Trip
+	query := r.conn(ctx).Where("tenant_id = ? AND started_at >= ? AND started_at < ?", tenantID, from, to)
+	if driverID != 0 {
+		query = query.Where("driver_id = ?", driverID)
+	}
+	err := query.Order("started_at, id").Find(&trips).Error
+	return trips, err
+}
+
+// TripTotals sums up a driver's trips with a taxi
+type TripTotals struct {
+	Trips      int          `json:"trips"`
+	Fare       money.Amount `json:"fare"`
+	DistanceKm float64      `json:"distance_km"`
+}
+
+// SumTrips totals the driver's trips with the taxi started in [from, to)
+func (r *Repository) SumTrips(ctx context.Context, taxiID, driverID uint, from, to time.Time) (*TripTotals, error) {
+	var totals TripTotals
+	err := r.conn(ctx).Model(&Trip{}).
+		Select("COUNT(*) AS trips, COALESCE(SUM(fare), 0) AS fare, COALESCE(SUM(distance_km), 0) AS distance_km").
+		Where("taxi_id = ? AND driver_id = ? AND started_at >= ? AND started_at < ?", taxiID, driverID, from, to).
+		Scan(&totals).Error
+	return &totals, err
+}
+
+// PlatformEarning methods
+
+// SavePlatformEarnings stores imported earnings, replacing those already
+// imported for the same driver, platform and day
+func (r *Repository) SavePlatformEarnings(ctx context.Context, earnings []PlatformEarning) error {
+	return r.conn(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "driver_id"}, {Name: "platform"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"amount", "created_by_id", "updated_at"}),
+	}).CreateInBatches(earnings, 100).Error
+}
+
+// GetPlatformEarnings returns the tenant's platform earnings dated in [from, to)
+func (r *Repository) GetPlatformEarnings(ctx context.Context, tenantID uint, from, to time.Time) ([]PlatformEarning, error) {
+	var earnings []PlatformEarning
+	err := r.conn(ctx).Where("tenant_id = ? AND date >= ? AND date < ?", tenantID, from, to).Order("date, driver_id, platform").Find(&earnings).Error
+	return earnings, err
+}
+
+// GetReportsInPeriod returns the tenant's submitted and approved reports whose
+// week starts in [from, to)
+func (r *Repository) GetReportsInPeriod(ctx context.Context, tenantID uint, from, to time.Time) ([]WeeklyReport, error) {
+	var reports []WeeklyReport
+	err := r.conn(ctx).Preload("Driver").
+		Where("tenant_id = ? AND status IN ? AND week_start_date >= ? AND week_start_date < ?", tenantID, []string{"submitted", "approved"}, from, to).
+		Order("week_start_date, driver_id").
+		Find(&reports).Error
+	return reports, err
+}
+
+// Fine methods
+func (r *Repository) CreateFine(ctx context.Context, fine *Fine) error {
+	return r.conn(ctx).Create(fine).Error
+}
+
+func (r *Repository) GetFineByID(ctx context.Context, id uint) (*Fine, error) {
+	var fine Fine
+	err := r.conn(ctx).Preload("Taxi").Preload("Driver").First(&fine, id).Error
+	return &fine, err
+}
+
+// GetFinesByTenant returns the tenant's fines, those due first
+func (r *Repository) GetFinesByTenant(ctx context.Context, tenantID uint) ([]Fine, error) {
+	var fines []Fine
+	err := r.conn(ctx).Preload("Taxi").Preload("Driver").Where("tenant_id = ?", tenantID).Order("due_date, id").Find(&fines).Error
+	return fines, err
+}
+
+func (r *Repository) UpdateFine(ctx context.Context, fine *Fine) error {
+	return r.conn(ctx).Model(fine).
+		Select("reference", "description", "amount", "due_date", "responsibility", "paid_at").
+		Updates(fine).Error
+}
+
+func (r *Repository) DeleteFine(ctx context.Context, id uint) error {
+	return r.conn(ctx).Delete(&Fine{}, id).Error
+}
+
+// FuelCard methods
+func (r *Repository) CreateFuelCard(ctx context.Context, card *FuelCard) error {
+	return r.conn(ctx).Create(card).Error
+}
+
+func (r *Repository) GetFuelCardByID(ctx context.Context, id uint) (*FuelCard, error) {
+	var card FuelCard
+	err := r.conn(ctx).Preload("Taxi").First(&card, id).Error
+	return &card, err
+}
+
+func (r *Repository) GetFuelCardsByTenant(ctx context.Context, tenantID uint) ([]FuelCard, error) {
+	var cards []FuelCard
+	err := r.conn(ctx).Preload("Taxi").Where("tenant_id = ?", tenantID).Order("id").Find(&cards).Error
+	return cards, err
+}
+
+// GetFuelCardsByNumbers returns the cards of any tenant with the given numbers
+func (r *Repository) GetFuelCardsByNumbers(ctx context.Context, numbers []string) ([]FuelCard, error) {
+	var cards []FuelCard
+	err := r.conn(ctx).Where("card_number IN ?", numbers).Find(&cards).Error
+	return cards, err
+}
+
+func (r *Repository) UpdateFuelCard(ctx context.Context, card *FuelCard) error {
+	return r.conn(ctx).Model(card).Select("label", "taxi_id").Updates(card).Error
+}
+
+func (r *Repository) DeleteFuelCard(ctx context.Context, id uint) error {
+	return r.conn(ctx).Delete(&FuelCard{}, id).Error
+}
+
+func (r *Repository) CreateFuelCardTransaction(ctx context.Context, transaction *FuelCardTransaction) error {
+	return r.conn(ctx).Create(transaction).Error
+}
+
+// GetSyncedTransactionIDs returns which of the provider's transaction IDs
+// were already stored
+func (r *Repository) GetSyncedTransactionIDs(ctx context.Context, externalIDs []string) ([]string, error) {
+	var ids []string
+	err := r.conn(ctx).Model(&FuelCardTransaction{}).Where("external_id IN ?", externalIDs).Pluck("external_id", &ids).Error
+	return ids, err
+}
+
+// GetLatestTransactionTime returns when the most recent stored fuel card
+// transaction occurred, or nil if there is none
+func (r *Repository) GetLatestTransactionTime(ctx context.Context) (*time.Time, error) {
+	var latest *time.Time
+	err := r.conn(ctx).Model(&FuelCardTransaction{}).Select("MAX(occurred_at)").Scan(&latest).Error
+	return latest, err
+}
+
+// GetFuelCardTransactions returns the tenant's latest fuel card transactions,
+// only the flagged ones if asked
+func (r *Repository) GetFuelCardTransactions(ctx context.Context, tenantID uint, flagged bool, limit int) ([]FuelCardTransaction, error) {
+	var transactions []FuelCardTransaction
+	db := r.conn(ctx).Preload("FuelCard").Preload("Taxi").Where("tenant_id = ?", tenantID)
+	if flagged {
+		db = db.Where("flag_reason <> ''")
+	}
+	err := db.Order("occurred_at DESC, id DESC").Limit(limit).Find(&transactions).Error
+	return transactions, err
+}
+
+// Geofence methods
+func (r *Repository) CreateGeofence(ctx context.Context, geofence *Geofence) error {
+	return r.conn(ctx).Create(geofence).Error
+}
+
+func (r *Repository) GetGeofenceByID(ctx context.Context, id uint) (*Geofence, error) {
+	var geofence Geofence
+	err := r.conn(ctx).First(&geofence, id).Error
+	return &geofence, err
+}
+
+func (r *Repository) GetGeofencesByTenant(ctx context.Context, tenantID uint) ([]Geofence, error) {
+	var geofences []Geofence
+	err := r.conn(ctx).Where("tenant_id = ?", tenantID).Order("id").Find(&geofences).Error
+	return geofences, err
+}
+
+func (r *Repository) UpdateGeofence(ctx context.Context, geofence *Geofence) error {
+	return r.conn(ctx).Model(geofence).Select("name", "polygon", "active").Updates(geofence).Error
+}
+
+func (r *Repository) DeleteGeofence(ctx context.Context, id uint) error {
+	return r.conn(ctx).Delete(&Geofence{}, id).Error
+}
+
+// TaxiPosition methods
+func (r *Repository) CreatePositions(ctx context.Context, positions []TaxiPosition) error {
+	return r.conn(ctx).CreateInBatches(positions, 100).Error
+}
+
+// GetLatestPosition returns the most recently recorded position of the taxi
+func (r *Repository) GetLatestPosition(ctx context.Context, taxiID uint) (*TaxiPosition, error) {
+	var position TaxiPosition
+	err := r.conn(ctx).Where("taxi_id = ?", taxiID).Order("recorded_at DESC, id DESC").First(&position).Error
+	return &position, err
+}
+
+// GetLatestPositions returns the most recently recorded position of each of
+// the tenant's taxis that reported one
+func (r *Repository) GetLatestPositions(ctx context.Context, tenantID uint) ([]TaxiPosition, error) {
+	var positions []TaxiPosition
+	err := r.conn(ctx).Raw(`
+		SELECT DISTINCT ON (taxi_id) * FROM taxi_positions
+		WHERE tenant_id = ?
+		ORDER BY taxi_id, recorded_at DESC, id DESC`, tenantID).Scan(&positions).Error
+	return positions, err
+}
+
+// Expense methods
+func (r *Repository) CreateExpense(ctx context.Context, expense *Expense) error {
+	return r.conn(ctx).Create(expense).Error
+}
+
+func (r *Repository) GetExpenseByID(ctx context.Context, id uint) (*Expense, error) {
+	var expense Expense
+	err := r.conn(ctx).Preload("Taxi").Preload("CreatedBy").Preload("Report").First(&expense, id).Error
+	return &expense, err
+}
+
+// SearchExpenses finds the tenant's expenses by reason, category, creator
+// name or taxi license plate; limit 0 returns every match
+func (r *Repository) SearchExpenses(ctx context.Context, tenantID uint, query string, limit int) ([]Expense, error) {
+	var expenses []Expense
+	tsquery := searchQuery(query)
+	if tsquery == "" {
+		return expenses, nil
+	}
+	db := search(r.conn(ctx).Preload("Taxi", taxiSummary).Preload("CreatedBy", userSummary).Where("expenses.tenant_id = ?", tenantID), "expenses", tsquery, []string{"created_by_id"}, []string{"taxi_id"}, "date DESC")
+	if limit > 0 {
+		db = db.Limit(limit)
+	}
+	err := db.Find(&expenses).Error
+	return expenses, err
+}
+
+func (r *Repository) GetExpensesByTenant(ctx context.Context, tenantID uint) ([]Expense, error) {
+	var expenses []Expense
+	err := r.conn(ctx).Preload("Taxi", taxiSummary).Preload("CreatedBy", userSummary).Where("tenant_id = ?", tenantID).Order("date DESC").Find(&expenses).Error
+	return expenses, err
+}
+
+// GetPendingExpenses returns the tenant's expenses awaiting an owner's
+// approval, oldest first
+func (r *Repository) GetPendingExpenses(ctx context.Context, tenantID uint) ([]Expense, error) {
+	var expenses []Expense
+	err := r.conn(ctx).Preload("Taxi", taxiSummary).Preload("CreatedBy", userSummary).
+		Where("tenant_id = ? AND status = ?", tenantID, "pending_approval").
+		Order("created_at, id").Find(&expenses).Error
+	return expenses, err
+}
+
+// GetExpensesPage returns up to limit of the tenant's expenses after the
+// keyset, or from the start without one, newest first
+func (r *Repository) GetExpensesPage(ctx context.Context, tenantID uint, after *Keyset, limit int) ([]Expense, error) {
+	var expenses []Expense
+	db := r.conn(ctx).Preload("Taxi", taxiSummary).Preload("CreatedBy", userSummary).Where("tenant_id = ?", tenantID)
+	if after != nil {
+		db = db.Where("(date, id) < (?::date, ?)", after.Date.Format("2006-01-02"), after.ID)
+	}
+	err := db.Order("date DESC, id DESC").Limit(limit).Find(&expenses).Error
+	return expenses, err
+}
+
+// SummarizeExpenses counts the tenant's approved expenses and sums their
+// amounts per category, taxi or month
+func (r *Repository) SummarizeExpenses(ctx context.Context, tenantID uint, groupBy string) ([]GroupTotal, error) {
+	key, ok := expenseGroups[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("unknown expense group %q", groupBy)
+	}
+	var totals []GroupTotal
+	err := r.conn(ctx).Model(&Expense{}).
+		Select(key+" AS key, COUNT(*) AS count, COALESCE(SUM(amount), 0) AS total").
+		Where("tenant_id = ? AND status = ?", tenantID, "approved").
+		Group("1").Order("1").
+		Scan(&totals).Error
+	return totals, err
+}
+
+// ExpenseExportRow is an expense as exports list it, with the plate of its
+// taxi
+type ExpenseExportRow struct {
+	ID           uint
+	Date         time.Time
+	Category     string
+	Amount       money.Amount
+	LicensePlate string
+	Reason       string
+	ReceiptURL   string
+	CreatedAt    time.Time
+}
+
+// GetExpenseExportRows returns the tenant's expenses for an export in one
+// query, newest first
+func (r *Repository) GetExpenseExportRows(ctx context.Context, tenantID uint) ([]ExpenseExportRow, error) {
+	var rows []ExpenseExportRow
+	err := r.conn(ctx).Table("expenses e").
+		Select("e.id, e.date, e.category, e.amount, COALESCE(t.license_plate, '') AS license_plate, COALESCE(e.reason, '') AS reason, COALESCE(e.receipt_url, '') AS receipt_url, e.created_at").
+		Joins("LEFT JOIN taxis t ON t.id = e.taxi_id AND t.deleted_at IS NULL").
+		Where("e.tenant_id = ? AND e.deleted_at IS NULL", tenantID).
+		Order("e.date DESC, e.id DESC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *Repository) GetExpensesByReport(ctx context.Context, reportID uint) ([]Expense, error) {
+	var expenses []Expense
+	err := r.conn(ctx).Preload("Taxi", taxiSummary).Preload("CreatedBy", userSummary).Where("report_id = ?", reportID).Find(&expenses).Error
+	return expenses, err
+}
+
+func (r *Repository) UpdateExpense(ctx context.Context, expense *Expense) error {
+	return r.conn(ctx).Save(expense).Error
+}
+
+func (r *Repository) DeleteExpense(ctx context.Context, id uint) error {
+	return r.conn(ctx).Delete(&Expense{}, id).Error
+}
+
+// Sync methods
+
+// GetReportByClientID returns the tenant's report created with the client ID,
+// deleted or not
+func (r *Repository) GetReportByClientID(ctx context.Context, tenantID uint, clientID string) (*WeeklyReport, error) {
+	var report WeeklyReport
+	err := r.conn(ctx).Unscoped().Where("tenant_id = ? AND client_id = ?", tenantID, clientID).First(&report).Error
+	return &report, err
+}
+
+// GetExpenseByClientID returns the tenant's expense created with the client
+// ID, deleted or not
+func (r *Repository) GetExpenseByClientID(ctx context.Context, tenantID uint, clientID string) (*Expense, error) {
+	var expense Expense
+	err := r.conn(ctx).Unscoped().Where("tenant_id = ? AND client_id = ?", tenantID, clientID).First(&expense).Error
+	return &expense, err
+}
+
+// GetReportsChangedSince returns the driver's reports updated or deleted
+// after the given time, deleted ones included
+func (r *Repository) GetReportsChangedSince(ctx context.Context, driverID uint, since time.Time) ([]WeeklyReport, error) {
+	var reports []WeeklyReport
+	err := r.conn(ctx).Unscoped().Preload("Taxi").
+		Where("driver_id = ? AND (updated_at > ? OR deleted_at > ?)", driverID, since, since).
+		Order("updated_at, id").Find(&reports).Error
+	return reports, err
+}
+
+// GetExpensesChangedSince returns the expenses the user created that were
+// updated or deleted after the given time, deleted ones included
+func (r *Repository) GetExpensesChangedSince(ctx context.Context, userID uint, since time.Time) ([]Expense, error) {
+	var expenses []Expense
+	err := r.conn(ctx).Unscoped().
+		Where("created_by_id = ? AND (updated_at > ? OR deleted_at > ?)", userID, since, since).
+		Order("updated_at, id").Find(&expenses).Error
+	return expenses, err
+}
+
+// BankDeposit methods
+func (r *Repository) CreateDeposit(ctx context.Context, deposit *BankDeposit) error {
+	return r.conn(ctx).Create(deposit).Error
+}
+
+func (r *Repository) GetDepositByID(ctx context.Context, id uint) (*BankDeposit, error) {
+	var deposit BankDeposit
+	err := r.conn(ctx).Preload("Account").First(&deposit, id).Error
+	return &deposit, err
+}
+
+func (r *Repository) GetDepositsByTenant(ctx context.Context, tenantID uint) ([]BankDeposit, error) {
+	var deposits []BankDeposit
+	err := r.conn(ctx).Preload("Account").Where("tenant_id = ?", tenantID).Order("deposit_date DESC").Find(&deposits).Error
+	return deposits, err
+}
+
+// UpdateDeposit saves the deposit; its preloaded account is left untouched
+func (r *Repository) UpdateDeposit(ctx context.Context, deposit *BankDeposit) error {
+	return r.conn(ctx).Omit(clause.Associations).Save(deposit).Error
+}
+
+func (r *Repository) DeleteDeposit(ctx context.Context, id uint) error {
+	return r.conn(ctx).Delete(&BankDeposit{}, id).Error
+}
+
+// Budget methods
+func (r *Repository) CreateBudget(ctx context.Context, budget *Budget) error {
+	return r.conn(ctx).Create(budget).Error
+}
+
+func (r *Repository) GetBudgetByID(ctx context.Context, id uint) (*Budget, error) {
+	var budget Budget
+	err := r.conn(ctx).Preload("Taxi", taxiSummary).First(&budget, id).Error
+	return &budget, err
+}
+
+func (r *Repository) GetBudgets(ctx context.Context, tenantID uint) ([]Budget, error) {
+	var budgets []Budget
+	err := r.conn(ctx).Preload("Taxi", taxiSummary).Where("tenant_id = ?", tenantID).Order("category, taxi_id NULLS FIRST").Find(&budgets).Error
+	return budgets, err
+}
+
+func (r *Repository) UpdateBudget(ctx context.Context, budget *Budget) error {
+	return r.conn(ctx).Model(budget).Select("category", "taxi_id", "amount").Updates(budget).Error
+}
+
+func (r *Repository) DeleteBudget(ctx context.Context, id uint) error {
+	return r.conn(ctx).Delete(&Budget{}, id).Error
+}
+
+// GetBudgetSpending sums, per budget of the tenant, the approved expenses
+// dated from from up to to that count against it: those of its category and,
+// for a taxi's budget, of that taxi
+func (r *Repository) GetBudgetSpending(ctx context.Context, tenantID uint, from, to time.Time) (map[uint]money.Amount, error) {
+	var rows []struct {
+		BudgetID uint
+		Spent    money.Amount
+	}
+	err := r.conn(ctx).Raw(`
+		SELECT b.id AS budget_id, COALESCE(SUM(e.amount), 0) AS spent
+		FROM budgets b
+		LEFT JOIN expenses e ON e.tenant_id = b.tenant_id AND e.category = b.category
+			AND (b.taxi_id IS NULL OR e.taxi_id = b.taxi_id)
+			AND e.date >= ? AND e.date < ? AND e.deleted_at IS NULL AND e.status = 'approved'
+		WHERE b.tenant_id = ?
+		GROUP BY b.id`, from, to, tenantID).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	spending := make(map[uint]money.Amount, len(rows))
+	for _, row := range rows {
+		spending[row.BudgetID] = row.Spent
+	}
+	return spending, nil
+}
+
+// ClosedPeriod methods
+func (r *Repository) CreateClosedPeriod(ctx context.Context, period *ClosedPeriod) error {
+	return r.conn(ctx).Create(period).Error
+}
+
+// GetClosedPeriod returns the tenant's closed period of the month starting on month
+func (r *Repository) GetClosedPeriod(ctx context.Context, tenantID uint, month time.Time) (*ClosedPeriod, error) {
+	var period ClosedPeriod
+	err := r.conn(ctx).Where("tenant_id = ? AND month = ?::date", tenantID, month.Format("2006-01-02")).First(&period).Error
+	return &period, err
+}
+
+func (r *Repository) GetClosedPeriods(ctx context.Context, tenantID uint) ([]ClosedPeriod, error) {
+	var periods []ClosedPeriod
+	err := r.conn(ctx).Preload("ClosedBy", userSummary).Where("tenant_id = ?", tenantID).Order("month DESC").Find(&periods).Error
+	return periods, err
+}
+
+func (r *Repository) DeleteClosedPeriod(ctx context.Context, id uint) error {
+	return r.conn(ctx).Delete(&ClosedPeriod{}, id).Error
+}
+
+// IsPeriodClosed reports whether the month of day is closed for the tenant
+func (r *Repository) IsPeriodClosed(ctx context.Context, tenantID uint, day time.Time) (bool, error) {
+	var count int64
+	err := r.conn(ctx).Model(&ClosedPeriod{}).
+		Where("tenant_id = ? AND month = date_trunc('month', ?::date)::date", tenantID, day.Format("2006-01-02")).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// BankAccount methods
+func (r *Repository) CreateBankAccount(ctx context.Context, account *BankAccount) error {
+	return r.conn(ctx).Create(account).Error
+}
+
+func (r *Repository) GetBankAccountByID(ctx context.Context, id uint) (*BankAccount, error) {
+	var account BankAccount
+	err := r.conn(ctx).First(&account, id).Error
+	return &account, err
+}
+
+func (r *Repository) GetBankAccountsByTenant(ctx context.Context, tenantID uint) ([]BankAccount, error) {
+	var accounts []BankAccount
+	err := r.conn(ctx).Where("tenant_id = ?", tenantID).Order("label").Find(&accounts).Error
+	return accounts, err
+}
+
+func (r *Repository) UpdateBankAccount(ctx context.Context, account *BankAccount) error {
+	return r.conn(ctx).Model(account).Select("label", "bank_name", "account_number").Updates(account).Error
+}
+
+func (r *Repository) DeleteBankAccount(ctx context.Context, id uint) error {
+	return r.conn(ctx).Delete(&BankAccount{}, id).Error
+}
+
+// BankAccountInUse reports whether any deposit was made into the account
+func (r *Repository) BankAccountInUse(ctx context.Context, id uint) (bool, error) {
+	var count int64
+	err := r.conn(ctx).Model(&BankDeposit{}).Where("bank_account_id = ?", id).Limit(1).Count(&count).Error
+	return count > 0, err
+}
+
+// BankAccountTotal is the number and amount of deposits made into an account;
+// BankAccountID is nil for deposits not linked to an account
+type BankAccountTotal struct {
+	BankAccountID *uint        `json:"bank_account_id"`
+	Count         int          `json:"count"`
+	Total         money.Amount `json:"total"`
+}
+
+func (r *Repository) GetDepositTotalsByAccount(ctx context.Context, tenantID uint) ([]BankAccountTotal, error) {
+	var totals []BankAccountTotal
+	err := r.conn(ctx).Model(&BankDeposit{}).
+		Where("tenant_id = ?", tenantID).
+		Select("bank_account_id, COUNT(*) AS count, COALESCE(SUM(amount), 0) AS total").
+		Group("bank_account_id").Order("bank_account_id").
+		Scan(&totals).Error
+	return totals, err
+}
+
+// Session methods
+func (r *Repository) CreateSession(ctx context.Context, session *Session) error {
+	return r.conn(ctx).Create(session).Error
+}
+
+func (r *Repository) GetSessionByToken(ctx context.Context, token string) (*Session, error) {
+	var session Session
+	err := r.conn(ctx).Preload("User").Where("token = ? AND expires_at > NOW()", token).First(&session).Error
+	return &session, err
+}
+
+func (r *Repository) DeleteSession(ctx context.Context, token string) error {
+	return r.conn(ctx).Where("token = ?", token).Delete(&Session{}).Error
+}
+
+func (r *Repository) DeleteUserSessions(ctx context.Context, userID uint) error {
+	return r.conn(ctx).Where("user_id = ?", userID).Delete(&Session{}).Error
+}
+
+// DeleteStaleSessions permanently removes expired and logged out sessions
+func (r *Repository) DeleteStaleSessions(ctx context.Context, now time.Time) (int64, error) {
+	result := r.conn(ctx).Unscoped().Where("expires_at < ? OR deleted_at IS NOT NULL", now).Delete(&Session{})
+	return result.RowsAffected, result.Error
+}
+
+// DeviceToken methods
+func (r *Repository) SaveDeviceToken(ctx context.Context, device *DeviceToken) error {
+	// A token moves with the device, so re-registering it under another user
+	// (e.g. after logout/login) takes it over instead of failing on the unique index.
+	// The token may belong to another tenant, whose row the tenant transaction
+	// of the request cannot see, so this runs outside of it.
+	db := r.conn(Detach(ctx))
+	var existing DeviceToken
+	err := db.Unscoped().Where("token = ?", device.Token).First(&existing).Error
+	if err == nil {
+		device.ID = existing.ID
+		device.CreatedAt = existing.CreatedAt
+		return db.Unscoped().Model(&existing).Updates(map[string]interface{}{
+			"tenant_id":    device.TenantID,
+			"user_id":      device.UserID,
+			"platform":     device.Platform,
+			"last_seen_at": device.LastSeenAt,
+			"deleted_at":   nil,
+		}).Error
+	}
+	if err != gorm.ErrRecordNotFound {
+		return err
+	}
+	return db.Create(device).Error
+}
+
+func (r *Repository) GetDeviceTokensByUser(ctx context.Context, userID uint) ([]DeviceToken, error) {
+	var devices []DeviceToken
+	err := r.conn(ctx).Where("user_id = ?", userID).Find(&devices).Error
+	return devices, err
+}
+
+func (r *Repository) DeleteUserDeviceToken(ctx context.Context, userID uint, token string) error {
+	return r.conn(ctx).Where("user_id = ? AND token = ?", userID, token).Delete(&DeviceToken{}).Error
+}
+
+func (r *Repository) DeleteDeviceToken(ctx context.Context, token string) error {
+	return r.conn(ctx).Where("token = ?", token).Delete(&DeviceToken{}).Error
+}
+
+// Notification methods
+func (r *Repository) CreateNotification(ctx context.Context, notification *Notification) error {
+	return r.conn(ctx).Create(notification).Error
+}
+
+// GetNotifications returns the user's latest notifications, newest first
+func (r *Repository) GetNotifications(ctx context.Context, userID uint, unreadOnly bool, limit int) ([]Notification, error) {
+	var notifications []Notification
+	query := r.conn(ctx).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	err := query.Order("created_at DESC, id DESC").Limit(limit).Find(&notifications).Error
+	return notifications, err
+}
+
+func (r *Repository) CountUnreadNotifications(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := r.conn(ctx).Model(&Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
+
+// MarkNotificationRead marks one of the user's notifications read and reports
+// whether the user has it. Reading it again keeps the first read time.
+func (r *Repository) MarkNotificationRead(ctx context.Context, userID, id uint) (bool, error) {
+	result := r.conn(ctx).Model(&Notification{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("read_at", gorm.Expr("COALESCE(read_at, ?)", time.Now()))
+	return result.RowsAffected > 0, result.Error
+}
+
+// MarkAllNotificationsRead marks every unread notification of the user read
+// and returns how many there were
+func (r *Repository) MarkAllNotificationsRead(ctx context.Context, userID uint) (int64, error) {
+	result := r.conn(ctx).Model(&Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now())
+	return result.RowsAffected, result.Error
+}
+
+// AuditEvent methods
+func (r *Repository) CreateAuditEvent(ctx context.Context, event *AuditEvent) error {
+	return r.conn(ctx).Create(event).Error
+}
+
+// GetAuditEvents returns the tenant's latest events older than beforeID (all
+// events when it is 0), newest first
+func (r *Repository) GetAuditEvents(ctx context.Context, tenantID uint, beforeID uint, limit int) ([]AuditEvent, error) {
+	var events []AuditEvent
+	query := r.conn(ctx).Preload("Actor").Where("tenant_id = ?", tenantID)
+	if beforeID > 0 {
+		query = query.Where("id < ?", beforeID)
+	}
+	err := query.Order("id DESC").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+// MaintenanceSchedule methods
+func (r *Repository) CreateMaintenanceSchedule(ctx context.Context, schedule *MaintenanceSchedule) error {
+	return r.conn(ctx).Create(schedule).Error
+}
+
+func (r *Repository) GetMaintenanceScheduleByID(ctx context.Context, id uint) (*MaintenanceSchedule, error) {
+	var schedule MaintenanceSchedule
+	err := r.conn(ctx).Preload("Taxi").First(&schedule, id).Error
+	return &schedule, err
+}
+
+func (r *Repository) GetMaintenanceSchedulesByTenant(ctx context.Context, tenantID uint) ([]MaintenanceSchedule, error) {
+	var schedules []MaintenanceSchedule
+	err := r.conn(ctx).Preload("Taxi").Where("tenant_id = ?", tenantID).Order("taxi_id, id").Find(&schedules).Error
+	return schedules, err
+}
+
+func (r *Repository) GetAllMaintenanceSchedules(ctx context.Context) ([]MaintenanceSchedule, error) {
+	var schedules []MaintenanceSchedule
+	err := r.conn(ctx).Preload("Taxi").Find(&schedules).Error
+	return schedules, err
+}
+
+func (r *Repository) UpdateMaintenanceSchedule(ctx context.Context, schedule *MaintenanceSchedule) error {
+	return r.conn(ctx).Save(schedule).Error
+}
+
+func (r *Repository) DeleteMaintenanceSchedule(ctx context.Context, id uint) error {
+	return r.conn(ctx).Delete(&MaintenanceSchedule{}, id).Error
+}
+
+// MaintenanceLog methods
+func (r *Repository) CreateMaintenanceLog(ctx context.Context, log *MaintenanceLog) error {
+	return r.conn(ctx).Create(log).Error
+}
+
+func (r *Repository) GetMaintenanceLogByID(ctx context.Context, id uint) (*MaintenanceLog, error) {
+	var log MaintenanceLog
+	err := r.conn(ctx).Preload("Taxi").Preload("Mechanic").First(&log, id).Error
+	return &log, err
+}
+
+func (r *Repository) UpdateMaintenanceLog(ctx context.Context, log *MaintenanceLog) error {
+	return r.conn(ctx).Save(log).Error
+}
+
+// AddMaintenanceLogCost adds to the log's cost in the database, so concurrent
+// additions never overwrite each other
+func (r *Repository) AddMaintenanceLogCost(ctx context.Context, id uint, amount money.Amount) error {
+	return r.conn(ctx).Model(&MaintenanceLog{}).Where("id = ?", id).Update("cost", gorm.Expr("cost + ?", amount)).Error
+}
+
+func (r *Repository) GetMaintenanceLogsByTenant(ctx context.Context, tenantID uint) ([]MaintenanceLog, error) {
+	var logs []MaintenanceLog
+	err := r.conn(ctx).Preload("Taxi").Preload("Mechanic").Where("tenant_id = ?", tenantID).Order("date DESC").Find(&logs).Error
+	return logs, err
+}
+
+func (r *Repository) GetMaintenanceLogsByTaxi(ctx context.Context, taxiID uint) ([]MaintenanceLog, error) {
+	var logs []MaintenanceLog
+	err := r.conn(ctx).Preload("Mechanic").Where("taxi_id = ?", taxiID).Order("date DESC").Find(&logs).Error
+	return logs, err
+}
+
+// Part methods
+func (r *Repository) CreatePart(ctx context.Context, part *Part) error {
+	return r.conn(ctx).Create(part).Error
+}
+
+func (r *Repository) GetPartByID(ctx context.Context, id uint) (*Part, error) {
+	var part Part
+	err := r.conn(ctx).First(&part, id).Error
+	return &part, err
+}
+
+func (r *Repository) GetPartsByTenant(ctx context.Context, tenantID uint) ([]Part, error) {
+	var parts []Part
+	err := r.conn(ctx).Where("tenant_id = ?", tenantID).Order("name").Find(&parts).Error
+	return parts, err
+}
+
+func (r *Repository) GetLowStockParts(ctx context.Context, tenantID uint) ([]Part, error) {
+	var parts []Part
+	err := r.conn(ctx).Where("tenant_id = ? AND quantity_on_hand <= reorder_level", tenantID).Order("name").Find(&parts).Error
+	return parts, err
+}
+
+// UpdatePart saves the part details. The quantity on hand is left untouched,
+// it only changes through stock movements.
+func (r *Repository) UpdatePart(ctx context.Context, part *Part) error {
+	return r.conn(ctx).Model(part).Select("name", "sku", "unit", "unit_cost", "reorder_level").Updates(part).Error
+}
+
+func (r *Repository) DeletePart(ctx context.Context, id uint) error {
+	return r.conn(ctx).Delete(&Part{}, id).Error
+}
+
+// ErrInsufficientStock is returned when a movement would take a part below zero
+var ErrInsufficientStock = errors.New("insufficient stock")
+
+// CreateStockMovements applies the movements to their parts atomically, locking
+// each part row so concurrent consumption cannot oversell, and returns the
+// updated parts in the same order
+func (r *Repository) CreateStockMovements(ctx context.Context, movements []StockMovement) ([]Part, error) {
+	parts := make([]Part, len(movements))
+	err := r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range movements {
+			movement := &movements[i]
+
+			var part Part
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&part, movement.PartID).Error; err != nil {
+				return err
+			}
+
+			part.QuantityOnHand += movement.Quantity
+			if part.QuantityOnHand < 0 {
+				return ErrInsufficientStock
+			}
+
+			if err := tx.Model(&part).Update("quantity_on_hand", part.QuantityOnHand).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(movement).Error; err != nil {
+				return err
+			}
+			parts[i] = part
+		}
+		return nil
+	})
+	return parts, err
+}
+
+func (r *Repository) GetStockMovementsByPart(ctx context.Context, partID uint) ([]StockMovement, error) {
+	var movements []StockMovement
+	err := r.conn(ctx).Preload("CreatedBy").Where("part_id = ?", partID).Order("created_at DESC").Find(&movements).Error
+	return movements, err
+}
+
+func (r *Repository) GetStockMovementsByMaintenanceLog(ctx context.Context, logID uint) ([]StockMovement, error) {
+	var movements []StockMovement
+	err := r.conn(ctx).Preload("Part").Where("maintenance_log_id = ?", logID).Order("created_at").Find(&movements).Error
+	return movements, err
+}
+
+// Assignment methods
+
+// RecordAssignment closes the taxi's open assignment and, if driverID is set,
+// opens a new one starting at the same instant
+func (r *Repository) RecordAssignment(ctx context.Context, tenantID, taxiID uint, driverID *uint, assignedByID *uint, at time.Time) error {
+	return r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&Assignment{}).
+			Where("taxi_id = ? AND ended_at IS NULL", taxiID).
+			Update("ended_at", at).Error; err != nil {
+			return err
+		}
+
+		if driverID == nil {
+			return nil
+		}
+
+		return tx.Create(&Assignment{
+			TenantID:     tenantID,
+			TaxiID:       taxiID,
+			DriverID:     *driverID,
+			StartedAt:    at,
+			AssignedByID: assignedByID,
+		}).Error
+	})
+}
+
+func (r *Repository) GetAssignmentsByTaxi(ctx context.Context, taxiID uint) ([]Assignment, error) {
+	var assignments []Assignment
+	err := r.conn(ctx).Preload("Driver").Preload("AssignedBy").Where("taxi_id = ?", taxiID).Order("started_at DESC").Find(&assignments).Error
+	return assignments, err
+}
+
+// GetAssignmentAt returns the assignment of the taxi that was active at the given time
+func (r *Repository) GetAssignmentAt(ctx context.Context, taxiID uint, at time.Time) (*Assignment, error) {
+	var assignment Assignment
+	err := r.conn(ctx).Preload("Driver").
+		Where("taxi_id = ? AND started_at <= ? AND (ended_at IS NULL OR ended_at > ?)", taxiID, at, at).
+		Order("started_at DESC").
+		First(&assignment).Error
+	return &assignment, err
+}
+
+// Analytics methods
+
+// DriverPerformance holds per-driver report aggregates over a period
+type DriverPerformance struct {
+	DriverID       uint
+	FirstName      string
+	LastName       string
+	ReportCount    int
+	ApprovedWeeks  int
+	TotalEarnings  money.Amount
+	SubmittedCount int
+	OnTimeCount    int
+	ReviewedCount  int
+	RejectedCount  int
+	TotalExpenses  money.Amount
+}
+
+// GetDriverPerformance aggregates the reports of every driver of the tenant whose
+// week starts within [from, to]. A report counts as on time when it was
+// submitted by the end of the day after its week ended.
+func (r *Repository) GetDriverPerformance(ctx context.Context, tenantID uint, from, to time.Time) ([]DriverPerformance, error) {
+	var rows []DriverPerformance
+	err := r.conn(ctx).Raw(`
+		SELECT u.id AS driver_id, u.first_name, u.last_name,
+			rp.report_count, rp.approved_weeks, rp.total_earnings,
+			rp.submitted_count, rp.on_time_count, rp.reviewed_count, rp.rejected_count,
+			COALESCE(ex.total_expenses, 0) AS total_expenses
+		FROM users u
+		JOIN (
+			SELECT driver_id,
+				COUNT(*) AS report_count,
+				COUNT(DISTINCT week_start_date) FILTER (WHERE status = 'approved') AS approved_weeks,
+				COALESCE(SUM(earnings) FILTER (WHERE status = 'approved'), 0) AS total_earnings,
+				COUNT(*) FILTER (WHERE submitted_at IS NOT NULL) AS submitted_count,
+				COUNT(*) FILTER (WHERE submitted_at < week_start_date + INTERVAL '8 days') AS on_time_count,
+				COUNT(*) FILTER (WHERE status IN ('approved', 'rejected')) AS reviewed_count,
+				COUNT(*) FILTER (WHERE status = 'rejected') AS rejected_count
+			FROM weekly_reports
+			WHERE tenant_id = ? AND deleted_at IS NULL AND week_start_date BETWEEN ? AND ?
+			GROUP BY driver_id
+		) rp ON rp.driver_id = u.id
+		LEFT JOIN (
+			SELECT wr.driver_id, SUM(e.amount) AS total_expenses
+			FROM expenses e
+			JOIN weekly_reports wr ON wr.id = e.report_id AND wr.deleted_at IS NULL
+			WHERE e.tenant_id = ? AND e.deleted_at IS NULL AND e.status = 'approved' AND wr.week_start_date BETWEEN ? AND ?
+			GROUP BY wr.driver_id
+		) ex ON ex.driver_id = u.id
+		WHERE u.tenant_id = ?
+		ORDER BY rp.total_earnings DESC, u.id`,
+		tenantID, from, to, tenantID, from, to, tenantID,
+	).Scan(&rows).Error
+	return rows, err
+}
+
+// TaxiProfitability holds per-taxi income and cost aggregates over a period
+type TaxiProfitability struct {
+	TaxiID           uint
+	LicensePlate     string
+	Model            string
+	Status           string
+	Earnings         money.Amount
+	Expenses         money.Amount
+	MaintenanceCosts money.Amount
+	DowntimeDays     int
+}
+
+// GetTaxiProfitability aggregates approved report earnings, expenses and
+// maintenance costs of every taxi of the tenant within [from, to]. Downtime
+// days are the days of the period the taxi had no driver assigned.
+func (r *Repository) GetTaxiProfitability(ctx context.Context, tenantID uint, from, to time.Time) ([]TaxiProfitability, error) {
+	var rows []TaxiProfitability
+	err := r.conn(ctx).Raw(`
+		SELECT t.id AS taxi_id, t.license_plate, t.model, t.status,
+			COALESCE(rp.earnings, 0) AS earnings,
+			COALESCE(ex.expenses, 0) AS expenses,
+			COALESCE(ml.maintenance_costs, 0) AS maintenance_costs,
+			COALESCE(dt.downtime_days, 0) AS downtime_days
+		FROM taxis t
+		LEFT JOIN (
+			SELECT taxi_id, SUM(earnings) AS earnings
+			FROM weekly_reports
+			WHERE tenant_id = ? AND deleted_at IS NULL AND status = 'approved' AND week_start_date BETWEEN ? AND ?
+			GROUP BY taxi_id
+		) rp ON rp.taxi_id = t.id
+		LEFT JOIN (
+			SELECT COALESCE(e.taxi_id, wr.taxi_id) AS taxi_id, SUM(e.amount) AS expenses
+			FROM expenses e
+			LEFT JOIN weekly_reports wr ON wr.id = e.report_id
+			WHERE e.tenant_id = ? AND e.deleted_at IS NULL AND e.status = 'approved' AND e.date BETWEEN ? AND ?
+			GROUP BY COALESCE(e.taxi_id, wr.taxi_id)
+		) ex ON ex.taxi_id = t.id
+		LEFT JOIN (
+			SELECT taxi_id, SUM(cost) AS maintenance_costs
+			FROM maintenance_logs
+			WHERE tenant_id = ? AND deleted_at IS NULL AND date BETWEEN ? AND ?
+			GROUP BY taxi_id
+		) ml ON ml.taxi_id = t.id
+		LEFT JOIN LATERAL (
+			SELECT COUNT(*) AS downtime_days
+			FROM generate_series(GREATEST(?::date, t.created_at::date), LEAST(?::date, CURRENT_DATE), INTERVAL '1 day') AS d(day)
+			WHERE NOT EXISTS (
+				SELECT 1 FROM assignments a
+				WHERE a.taxi_id = t.id
+					AND a.started_at < d.day + INTERVAL '1 day'
+					AND (a.ended_at IS NULL OR a.ended_at > d.day)
+			)
+		) dt ON true
+		WHERE t.tenant_id = ? AND t.deleted_at IS NULL
+		ORDER BY t.license_plate`,
+		tenantID, from, to,
+		tenantID, from, to,
+		tenantID, from, to,
+		from, to,
+		tenantID,
+	).Scan(&rows).Error
+	return rows, err
+}
+
+// DashboardTotals are the tenant-wide counts and sums of the dashboard
+type DashboardTotals struct {
+	TotalTaxis              int
+	ActiveDrivers           int // Drivers assigned to an active taxi
+	PendingReports          int // Drafts
+	TotalRevenue            money.Amount
+	TotalAdjustments        money.Amount
+	TotalExpenses           money.Amount
+	UnverifiedDeposits      int
+	UnverifiedDepositAmount money.Amount
+}
+
+// GetDashboardTotals computes the dashboard totals of the tenant in the
+// database rather than by loading its records. Revenue and adjustments are
+// those of approved reports.
+func (r *Repository) GetDashboardTotals(ctx context.Context, tenantID uint) (*DashboardTotals, error) {
+	var totals DashboardTotals
+	err := r.conn(ctx).Raw(`
+		SELECT tx.total_taxis, tx.active_drivers,
+			rp.pending_reports, rp.total_revenue, rp.total_adjustments,
+			ex.total_expenses,
+			dp.unverified_deposits, dp.unverified_deposit_amount
+		FROM (
+			SELECT COUNT(*) AS total_taxis,
+				COUNT(DISTINCT assigned_driver_id) FILTER (WHERE status = 'active') AS active_drivers
+			FROM taxis WHERE tenant_id = @tenant AND deleted_at IS NULL
+		) tx, (
+			SELECT COUNT(*) FILTER (WHERE status = 'draft') AS pending_reports,
+				COALESCE(SUM(earnings) FILTER (WHERE status = 'approved'), 0) AS total_revenue,
+				COALESCE(SUM(total_adjustments) FILTER (WHERE status = 'approved'), 0) AS total_adjustments
+			FROM weekly_reports WHERE tenant_id = @tenant AND deleted_at IS NULL
+		) rp, (
+			SELECT COALESCE(SUM(amount), 0) AS total_expenses
+			FROM expenses WHERE tenant_id = @tenant AND deleted_at IS NULL AND status = 'approved'
+		) ex, (
+			SELECT COUNT(*) AS unverified_deposits, COALESCE(SUM(amount), 0) AS unverified_deposit_amount
+			FROM bank_deposits WHERE tenant_id = @tenant AND deleted_at IS NULL AND status <> 'verified'
+		) dp`,
+		sql.Named("tenant", tenantID),
+	).Scan(&totals).Error
+	return &totals, err
+}
+
+// CategoryTotal is the sum of expenses of a category
+type CategoryTotal struct {
+	Category string
+	Total    money.Amount
+}
+
+// SumApprovedEarnings totals the earnings of approved reports whose week starts within [from, to)
+func (r *Repository) SumApprovedEarnings(ctx context.Context, tenantID uint, from, to time.Time) (money.Amount, error) {
+	var total money.Amount
+	err := r.conn(ctx).Model(&WeeklyReport{}).
+		Where("tenant_id = ? AND status = ? AND week_start_date >= ? AND week_start_date < ?", tenantID, "approved", from, to).
+		Select("COALESCE(SUM(earnings), 0)").Scan(&total).Error
+	return total, err
+}
+
+// SumExpensesByCategory totals the approved expenses dated within [from, to) per category
+func (r *Repository) SumExpensesByCategory(ctx context.Context, tenantID uint, from, to time.Time) ([]CategoryTotal, error) {
+	var totals []CategoryTotal
+	err := r.conn(ctx).Model(&Expense{}).
+		Where("tenant_id = ? AND status = ? AND date >= ? AND date < ?", tenantID, "approved", from, to).
+		Select("category, SUM(amount) AS total").
+		Group("category").Order("category").
+		Scan(&totals).Error
+	return totals, err
+}
+
+// SumDeposits totals the deposits made within [from, to) and counts them
+func (r *Repository) SumDeposits(ctx context.Context, tenantID uint, from, to time.Time) (money.Amount, int, error) {
+	var result struct {
+		Total money.Amount
+		Count int
+	}
+	err := r.conn(ctx).Model(&BankDeposit{}).
+		Where("tenant_id = ? AND deposit_date >= ? AND deposit_date < ?", tenantID, from, to).
+		Select("COALESCE(SUM(amount), 0) AS total, COUNT(*) AS count").
+		Scan(&result).Error
+	return result.Total, result.Count, err
+}
+
+// SumMaintenanceCosts totals the cost of maintenance logs dated within [from, to)
+func (r *Repository) SumMaintenanceCosts(ctx context.Context, tenantID uint, from, to time.Time) (money.Amount, error) {
+	var total money.Amount
+	err := r.conn(ctx).Model(&MaintenanceLog{}).
+		Where("tenant_id = ? AND date >= ? AND date < ?", tenantID, from, to).
+		Select("COALESCE(SUM(cost), 0)").Scan(&total).Error
+	return total, err
+}
+
+// DailyAmount is the sum of amounts falling on a calendar day
+type DailyAmount struct {
+	Day    time.Time
+	Amount money.Amount
+}
+
+// GetDailyApprovedEarnings sums approved report earnings per week start date within [from, to)
+func (r *Repository) GetDailyApprovedEarnings(ctx context.Context, tenantID uint, from, to time.Time) ([]DailyAmount, error) {
+	var amounts []DailyAmount
+	err := r.conn(ctx).Model(&WeeklyReport{}).
+		Where("tenant_id = ? AND status = ? AND week_start_date >= ? AND week_start_date < ?", tenantID, "approved", from, to).
+		Select("week_start_date::date AS day, SUM(earnings) AS amount").
+		Group("day").Order("day").
+		Scan(&amounts).Error
+	return amounts, err
+}
+
+// GetDailyExpenses sums approved expenses per date within [from, to)
+func (r *Repository) GetDailyExpenses(ctx context.Context, tenantID uint, from, to time.Time) ([]DailyAmount, error) {
+	var amounts []DailyAmount
+	err := r.conn(ctx).Model(&Expense{}).
+		Where("tenant_id = ? AND status = ? AND date >= ? AND date < ?", tenantID, "approved", from, to).
+		Select("date::date AS day, SUM(amount) AS amount").
+		Group("day").Order("day").
+		Scan(&amounts).Error
+	return amounts, err
+}
+
+// GetApprovedReportsInPeriod returns the approved reports whose week starts within [from, to)
+func (r *Repository) GetApprovedReportsInPeriod(ctx context.Context, tenantID uint, from, to time.Time) ([]WeeklyReport, error) {
+	var reports []WeeklyReport
+	err := r.conn(ctx).Preload("Taxi").
+		Where("tenant_id = ? AND status = ? AND week_start_date >= ? AND week_start_date < ?", tenantID, "approved", from, to).
+		Order("week_start_date, id").Find(&reports).Error
+	return reports, err
+}
+
+// GetExpensesInPeriod returns the approved expenses dated within [from, to)
+func (r *Repository) GetExpensesInPeriod(ctx context.Context, tenantID uint, from, to time.Time) ([]Expense, error) {
+	var expenses []Expense
+	err := r.conn(ctx).
+		Where("tenant_id = ? AND status = ? AND date >= ? AND date < ?", tenantID, "approved", from, to).
+		Order("date, id").Find(&expenses).Error
+	return expenses, err
+}
+
+// GetDepositsInPeriod returns the deposits made within [from, to)
+func (r *Repository) GetDepositsInPeriod(ctx context.Context, tenantID uint, from, to time.Time) ([]BankDeposit, error) {
+	var deposits []BankDeposit
+	err := r.conn(ctx).Preload("Account").
+		Where("tenant_id = ? AND deposit_date >= ? AND deposit_date < ?", tenantID, from, to).
+		Order("deposit_date, id").Find(&deposits).Error
+	return deposits, err
+}
+
+// IdempotencyKey methods
+func (r *Repository) CreateIdempotencyKey(ctx context.Context, record *IdempotencyKey) error {
+	return r.conn(ctx).Create(record).Error
+}
+
+func (r *Repository) GetIdempotencyKey(ctx context.Context, userID uint, key string) (*IdempotencyKey, error) {
+	var record IdempotencyKey
+	err := r.conn(ctx).Where("user_id = ? AND key = ?", userID, key).First(&record).Error
+	return &record, err
+}
+
+func (r *Repository) UpdateIdempotencyKey(ctx context.Context, record *IdempotencyKey) error {
+	return r.conn(ctx).Save(record).Error
+}
+
+func (r *Repository) DeleteIdempotencyKey(ctx context.Context, id uint) error {
+	return r.conn(ctx).Delete(&IdempotencyKey{}, id).Error
+}
+
+// DeleteIdempotencyKeysBefore removes keys created before the given time
+func (r *Repository) DeleteIdempotencyKeysBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.conn(ctx).Where("created_at < ?", before).Delete(&IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
+
+// Query statistics methods
+
+// QueryStat holds the execution statistics pg_stat_statements collected for
+// a query, with its constants replaced by placeholders
+type QueryStat struct {
+	Query       string  `json:"query"`
+	Calls       int64   `json:"calls"`
+	TotalTimeMs float64 `json:"total_time_ms"`
+	MeanTimeMs  float64 `json:"mean_time_ms"`
+	MaxTimeMs   float64 `json:"max_time_ms"`
+	Rows        int64   `json:"rows"`
+}
+
+// QueryStatsEnabled reports whether the pg_stat_statements extension is
+// installed in the database
+func (r *Repository) QueryStatsEnabled(ctx context.Context) (bool, error) {
+	var enabled bool
+	err := r.conn(ctx).Raw("SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'pg_stat_statements')").Scan(&enabled).Error
+	return enabled, err
+}
+
+// GetSlowQueries returns the limit queries of this database with the highest
+// mean execution time since the statistics were last reset
+func (r *Repository) GetSlowQueries(ctx context.Context, limit int) ([]QueryStat, error) {
+	var stats []QueryStat
+	err := r.conn(ctx).Raw(`
+		SELECT query, calls, total_exec_time AS total_time_ms, mean_exec_time AS mean_time_ms,
+			max_exec_time AS max_time_ms, rows
+		FROM pg_stat_statements
+		WHERE dbid = (SELECT oid FROM pg_database WHERE datname = current_database())
+		ORDER BY mean_exec_time DESC
+		LIMIT ?`, limit,
+	).Scan(&stats).Error
+	return stats, err
+}
+
+// Job methods
+
+// WithJobLock runs fn while holding a Postgres advisory lock named after the
+// job, so a job never runs concurrently across API instances. It reports
+// false without calling fn when another instance holds the lock.
+func (r *Repository) WithJobLock(ctx context.Context, name string, fn func() error) (bool, error) {
+	acquired := false
+	// Advisory locks belong to a session, so lock and unlock on one connection
+	err := r.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		if err := conn.Raw("SELECT pg_try_advisory_lock(hashtext(?))", name).Scan(&acquired).Error; err != nil {
+			return err
+		}
+		if !acquired {
+			return nil
+		}
+		defer conn.Exec("SELECT pg_advisory_unlock(hashtext(?))", name)
+
+		return fn()
+	})
+	return acquired, err
+}
+
+func (r *Repository) CreateJobRun(ctx context.Context, run *JobRun) error {
+	return r.conn(ctx).Create(run).Error
+}
+
+func (r *Repository) UpdateJobRun(ctx context.Context, run *JobRun) error {
+	return r.conn(ctx).Save(run).Error
+}
+
+// GetJobRuns returns the latest runs, optionally of a single job
+func (r *Repository) GetJobRuns(ctx context.Context, jobName string, limit int) ([]JobRun, error) {
+	var runs []JobRun
+	query := r.conn(ctx).Order("started_at DESC").Limit(limit)
+	if jobName != "" {
+		query = query.Where("job_name = ?", jobName)
+	}
+	err := query.Find(&runs).Error
+	return runs, err
+}
+
+// APIKey methods
+func (r *Repository) CreateAPIKey(ctx context.Context, key *APIKey) error {
+	return r.conn(ctx).Create(key).Error
+}
+
+func (r *Repository) GetAPIKeyByID(ctx context.Context, id uint) (*APIKey, error) {
+	var key APIKey
+	err := r.conn(ctx).First(&key, id).Error
+	return &key, err
+}
+
+func (r *Repository) GetAPIKeyByHash(ctx context.Context, keyHash string) (*APIKey, error) {
+	var key APIKey
+	err := r.conn(ctx).Where("key_hash = ?", keyHash).First(&key).Error
+	return &key, err
+}
+
+// GetAPIKeys lists keys newest first, optionally of a single tenant
+func (r *Repository) GetAPIKeys(ctx context.Context, tenantID uint) ([]APIKey, error) {
+	var keys []APIKey
+	query := r.conn(ctx).Order("created_at DESC")
+	if tenantID != 0 {
+		query = query.Where("tenant_id = ?", tenantID)
+	}
+	err := query.Find(&keys).Error
+	return keys, err
+}
+
+func (r *Repository) UpdateAPIKey(ctx context.Context, key *APIKey) error {
+	return r.conn(ctx).Save(key).Error
+}
+
+// TouchAPIKey records when a key was last used
+func (r *Repository) TouchAPIKey(ctx context.Context, id uint, usedAt time.Time) error {
+	return r.conn(ctx).Model(&APIKey{}).Where("id = ?", id).UpdateColumn("last_used_at", usedAt).Error
+}
+
+// OAuthIdentity methods
+func (r *Repository) GetOAuthIdentity(ctx context.Context, provider, subject string) (*OAuthIdentity, error) {
+	var identity OAuthIdentity
+	err := r.conn(ctx).Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error
+	return &identity, err
+}
+
+func (r *Repository) CreateOAuthIdentity(ctx context.Context, identity *OAuthIdentity) error {
+	return r.conn(ctx).Create(identity).Error
+}
+
+// LoginAudit methods
+func (r *Repository) CreateLoginAudit(ctx context.Context, audit *LoginAudit) error {
+	return r.conn(ctx).Create(audit).Error
+}
+
+// GetLoginAudits returns the user's most recent login attempts, newest first
+func (r *Repository) GetLoginAudits(ctx context.Context, userID uint, limit int) ([]LoginAudit, error) {
+	var audits []LoginAudit
+	err := r.conn(ctx).Where("user_id = ?", userID).Order("created_at DESC").Limit(limit).Find(&audits).Error
+	return audits, err
+}
+
+// HasLoginFromDevice reports whether the user signed in successfully from the
+// device before, and whether they ever signed in successfully at all
+func (r *Repository) HasLoginFromDevice(ctx context.Context, userID uint, fingerprint string) (bool, bool, error) {
+	var result struct {
+		Known bool
+		Any   bool
+	}
+	err := r.conn(ctx).Model(&LoginAudit{}).
+		Select("COALESCE(BOOL_OR(device_fingerprint = ?), false) AS known, COUNT(*) > 0 AS any", fingerprint).
+		Where("user_id = ? AND success", userID).
+		Scan(&result).Error
+	return result.Known, result.Any, err
+}
+
+// Digest methods
+
+// GetReportsForWeek returns the reports of any status for the week
+func (r *Repository) GetReportsForWeek(ctx context.Context, tenantID uint, weekStart time.Time) ([]WeeklyReport, error) {
+	var reports []WeeklyReport
+	err := r.conn(ctx).Where("tenant_id = ? AND week_start_date = ?", tenantID, weekStart).Find(&reports).Error
+	return reports, err
+}
+
+// CountSubmittedReports counts the reports awaiting review
+func (r *Repository) CountSubmittedReports(ctx context.Context, tenantID uint) (int64, error) {
+	var count int64
+	err := r.conn(ctx).Model(&WeeklyReport{}).Where("tenant_id = ? AND status = ?", tenantID, "submitted").Count(&count).Error
+	return count, err
+}
+
+// SetWeeklyDigest opts the user in or out of the weekly digest email
+func (r *Repository) SetWeeklyDigest(ctx context.Context, userID uint, enabled bool) error {
+	return r.conn(ctx).Model(&User{}).Where("id = ?", userID).UpdateColumn("weekly_digest", enabled).Error
+}
+
+// Demo data methods
+
+// demoBatchSize is how many generated records are inserted per statement
+const demoBatchSize = 200
+
+// CreateDemoUsers inserts generated users and sets their IDs
+func (r *Repository) CreateDemoUsers(ctx context.Context, users []User) error {
+	return r.conn(ctx).Omit(clause.Associations).CreateInBatches(users, demoBatchSize).Error
+}
+
+func (r *Repository) CreateDemoTaxis(ctx context.Context, taxis []Taxi) error {
+	return r.conn(ctx).Omit(clause.Associations).CreateInBatches(taxis, demoBatchSize).Error
+}
+
+func (r *Repository) CreateDemoReports(ctx context.Context, reports []WeeklyReport) error {
+	return r.conn(ctx).Omit(clause.Associations).CreateInBatches(reports, demoBatchSize).Error
 }
 
-func (r *Repository) DeleteSession(token string) error {
-	return r.db.Where("token = ?", token).Delete(&Session{}).Error
+func (r *Repository) CreateDemoExpenses(ctx context.Context, expenses []Expense) error {
+	return r.conn(ctx).Omit(clause.Associations).CreateInBatches(expenses, demoBatchSize).Error
 }
 
-func (r *Repository) DeleteUserSessions(userID uint) error {
-	return r.db.Where("user_id = ?", userID).Delete(&Session{}).Error
+func (r *Repository) CreateDemoDeposits(ctx context.Context, deposits []BankDeposit) error {
+	return r.conn(ctx).Omit(clause.Associations).CreateInBatches(deposits, demoBatchSize).Error
 }
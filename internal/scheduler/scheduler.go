@@ -0,0 +1,194 @@
+// Package scheduler runs periodic background jobs on cron schedules.
+//
+// Each run holds a database advisory lock so that only one API instance
+// executes a job at a time, recovers from panics and is recorded in the
+// job_runs table.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"taxifleet/backend/internal/logging"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/tracing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+)
+
+// JobFunc is the work done by a job. The context is cancelled when the
+// scheduler stops.
+type JobFunc func(ctx context.Context) error
+
+// JobInfo describes a registered job
+type JobInfo struct {
+	Name     string    `json:"name"`
+	Schedule string    `json:"schedule"`
+	Running  bool      `json:"running"`
+	NextRun  time.Time `json:"next_run"`
+}
+
+type job struct {
+	name     string
+	schedule string
+	run      JobFunc
+	entryID  cron.EntryID
+	running  atomic.Bool // Set while the job runs in this process
+}
+
+type Scheduler struct {
+	cron   *cron.Cron
+	repo   repository.JobRepo
+	logger *logrus.Logger
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu   sync.Mutex
+	jobs []*job
+}
+
+func New(repo repository.JobRepo, logger *logrus.Logger) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		cron:   cron.New(),
+		repo:   repo,
+		logger: logger,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Register adds a job. The schedule is a standard 5-field cron expression or
+// a descriptor such as "@hourly" or "@every 30m".
+func (s *Scheduler) Register(name, schedule string, run JobFunc) error {
+	j := &job{name: name, schedule: schedule, run: run}
+
+	id, err := s.cron.AddFunc(schedule, func() { s.execute(j) })
+	if err != nil {
+		return fmt.Errorf("invalid schedule %q for job %s: %w", schedule, name, err)
+	}
+	j.entryID = id
+
+	s.mu.Lock()
+	s.jobs = append(s.jobs, j)
+	s.mu.Unlock()
+	return nil
+}
+
+// Start runs the scheduler in the background
+func (s *Scheduler) Start() {
+	s.cron.Start()
+}
+
+// Stop cancels running jobs and waits for them to return or ctx to expire
+func (s *Scheduler) Stop(ctx context.Context) {
+	s.cancel()
+	select {
+	case <-s.cron.Stop().Done():
+	case <-ctx.Done():
+		s.logger.Warn("Scheduler stopped before all jobs finished")
+	}
+}
+
+// Jobs lists the registered jobs
+func (s *Scheduler) Jobs() []JobInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	infos := make([]JobInfo, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		infos = append(infos, JobInfo{
+			Name:     j.name,
+			Schedule: j.schedule,
+			Running:  j.running.Load(),
+			NextRun:  s.cron.Entry(j.entryID).Next,
+		})
+	}
+	return infos
+}
+
+// Runs returns the latest runs, optionally of a single job
+func (s *Scheduler) Runs(ctx context.Context, jobName string, limit int) ([]repository.JobRun, error) {
+	return s.repo.GetJobRuns(ctx, jobName, limit)
+}
+
+// execute runs a job once unless it is still running here or on another instance
+func (s *Scheduler) execute(j *job) {
+	// Everything the job logs carries its name
+	ctx := logging.WithFields(s.ctx, logrus.Fields{"job": j.name})
+	if !j.running.CompareAndSwap(false, true) {
+		logging.Entry(ctx, s.logger).Warn("Skipping job run, previous run still in progress")
+		return
+	}
+	defer j.running.Store(false)
+
+	acquired, err := s.repo.WithJobLock(ctx, j.name, func() error {
+		return s.record(ctx, j)
+	})
+	if err != nil {
+		logging.Entry(ctx, s.logger).WithError(err).Error("Job run failed")
+		return
+	}
+	if !acquired {
+		logging.Entry(ctx, s.logger).Debug("Job is running on another instance")
+	}
+}
+
+// record runs the job and stores the outcome in the run history
+func (s *Scheduler) record(ctx context.Context, j *job) error {
+	run := &repository.JobRun{
+		JobName:   j.name,
+		Status:    "running",
+		StartedAt: time.Now(),
+	}
+	if err := s.repo.CreateJobRun(ctx, run); err != nil {
+		return err
+	}
+	ctx = logging.WithFields(ctx, logrus.Fields{"job_run_id": run.ID})
+
+	jobErr := s.safeRun(ctx, j)
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.Status = "succeeded"
+	if jobErr != nil {
+		run.Status = "failed"
+		run.Error = jobErr.Error()
+	}
+	// Still record the outcome of a run interrupted by shutdown
+	if err := s.repo.UpdateJobRun(context.WithoutCancel(ctx), run); err != nil {
+		logging.Entry(ctx, s.logger).WithError(err).Error("Failed to record job run")
+	}
+
+	entry := logging.Entry(ctx, s.logger).WithField("duration", finishedAt.Sub(run.StartedAt))
+	if jobErr != nil {
+		entry.WithError(jobErr).Error("Job failed")
+	} else {
+		entry.Info("Job succeeded")
+	}
+	return nil
+}
+
+// safeRun turns a panicking job into a failed run instead of crashing the API
+func (s *Scheduler) safeRun(ctx context.Context, j *job) (err error) {
+	ctx, span := tracing.Start(ctx, "job."+j.name)
+	defer func() {
+		if r := recover(); r != nil {
+			logging.Entry(ctx, s.logger).WithField("stack", string(debug.Stack())).Error("Job panicked")
+			err = fmt.Errorf("panic: %v", r)
+
+			hub := sentry.CurrentHub().Clone()
+			hub.Scope().SetTag("job", j.name)
+			hub.Recover(r)
+		}
+		tracing.End(span, err)
+	}()
+
+	return j.run(ctx)
+}
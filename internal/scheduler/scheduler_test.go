@@ -0,0 +1,52 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"taxifleet/backend/internal/logging"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+
+	"github.com/sirupsen/logrus"
+	"go.uber.org/mock/gomock"
+)
+
+func newSchedulerMock(t *testing.T) (*Scheduler, *mocks.MockJobRepo) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockJobRepo(ctrl)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return New(repo, logger), repo
+}
+
+func TestExecuteLogsWithJobFields(t *testing.T) {
+	s, repo := newSchedulerMock(t)
+	repo.EXPECT().WithJobLock(gomock.Any(), "session_cleanup", gomock.Any()).DoAndReturn(func(ctx context.Context, name string, fn func() error) (bool, error) {
+		return true, fn()
+	})
+	repo.EXPECT().CreateJobRun(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, run *repository.JobRun) error {
+		run.ID = 7
+		return nil
+	})
+	var recorded *repository.JobRun
+	repo.EXPECT().UpdateJobRun(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, run *repository.JobRun) error {
+		recorded = run
+		return nil
+	})
+
+	var fields logrus.Fields
+	s.execute(&job{name: "session_cleanup", run: func(ctx context.Context) error {
+		fields = logging.Fields(ctx)
+		return errors.New("boom")
+	}})
+
+	if fields["job"] != "session_cleanup" || fields["job_run_id"] != uint(7) {
+		t.Fatalf("expected the job's context to carry its log fields, got %v", fields)
+	}
+	if recorded == nil || recorded.Status != "failed" || recorded.Error != "boom" {
+		t.Fatalf("expected the failed run to be recorded, got %+v", recorded)
+	}
+}
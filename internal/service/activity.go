@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/validation"
+)
+
+// Audited actions shown in the activity feed
+const (
+	ActionReportSubmitted = "report.submitted"
+	ActionReportApproved  = "report.approved"
+	ActionTaxiCreated     = "taxi.created"
+	ActionExpenseCreated  = "expense.created"
+	ActionExpenseApproved = "expense.approved"
+
+	ActionDelegationGranted = "delegation.granted"
+	ActionDelegationRevoked = "delegation.revoked"
+	ActionPeriodClosed      = "period.closed"
+	ActionPeriodReopened    = "period.reopened"
+)
+
+// largeExpenseAmount is the amount from which a new expense is notable
+// enough for the activity feed
+const largeExpenseAmount money.Amount = 500_00
+
+// auditEvent builds an audit log entry; userID 0 (an API key) has no actor
+func auditEvent(tenantID, userID uint, action, entityType string, entityID uint, summary string) *repository.AuditEvent {
+	event := &repository.AuditEvent{
+		TenantID:   tenantID,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Summary:    summary,
+	}
+	if userID != 0 {
+		event.ActorID = &userID
+	}
+	return event
+}
+
+// ActivityFeed is one page of the tenant's activity feed, newest first
+type ActivityFeed struct {
+	Events []repository.AuditEvent `json:"events"`
+	// NextCursor fetches the following page; empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type ActivityService struct {
+	repo repository.AuditRepo
+}
+
+func NewActivityService(repo repository.AuditRepo) *ActivityService {
+	return &ActivityService{repo: repo}
+}
+
+// Feed returns up to limit events of the tenant older than the cursor, or the
+// latest events without one
+func (s *ActivityService) Feed(ctx context.Context, tenantID uint, cursor string, limit int) (*ActivityFeed, error) {
+	beforeID, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	// One extra event tells whether another page follows
+	events, err := s.repo.GetAuditEvents(ctx, tenantID, beforeID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	feed := &ActivityFeed{Events: events}
+	if len(events) > limit {
+		feed.Events = events[:limit]
+		feed.NextCursor = encodeCursor(feed.Events[limit-1].ID)
+	}
+	return feed, nil
+}
+
+// encodeCursor makes the opaque cursor of the page after the event with id
+func encodeCursor(id uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(uint64(id), 10)))
+}
+
+func decodeCursor(cursor string) (uint, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	invalid := &validation.FieldError{Field: "cursor", Rule: "cursor", Message: "cursor is invalid"}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, invalid
+	}
+	id, err := strconv.ParseUint(string(raw), 10, 32)
+	if err != nil || id == 0 {
+		return 0, invalid
+	}
+	return uint(id), nil
+}
+
+// reportSummary describes a report for the activity feed, e.g.
+// "Weekly report of 04/03/2024 for AB-123 submitted"
+func reportSummary(report *repository.WeeklyReport, status string) string {
+	return fmt.Sprintf("Weekly report of %s for %s %s", report.WeekStartDate.Format("02/01/2006"), report.Taxi.LicensePlate, status)
+}
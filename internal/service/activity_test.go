@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+	"taxifleet/backend/internal/validation"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestActivityFeedPagesWithCursor(t *testing.T) {
+	repo := mocks.NewMockAuditRepo(gomock.NewController(t))
+	svc := NewActivityService(repo)
+
+	repo.EXPECT().GetAuditEvents(gomock.Any(), uint(1), uint(0), 3).Return([]repository.AuditEvent{{ID: 9}, {ID: 7}, {ID: 4}}, nil)
+	page, err := svc.Feed(context.Background(), 1, "", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Events) != 2 || page.NextCursor == "" {
+		t.Fatalf("expected 2 events and a next cursor, got %+v", page)
+	}
+
+	repo.EXPECT().GetAuditEvents(gomock.Any(), uint(1), uint(7), 3).Return([]repository.AuditEvent{{ID: 4}}, nil)
+	page, err = svc.Feed(context.Background(), 1, page.NextCursor, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Events) != 1 || page.NextCursor != "" {
+		t.Fatalf("expected the last page, got %+v", page)
+	}
+}
+
+func TestActivityFeedRejectsInvalidCursor(t *testing.T) {
+	svc := NewActivityService(mocks.NewMockAuditRepo(gomock.NewController(t)))
+
+	var fieldErr *validation.FieldError
+	if _, err := svc.Feed(context.Background(), 1, "not a cursor", 20); !errors.As(err, &fieldErr) || fieldErr.Field != "cursor" {
+		t.Fatalf("expected a cursor field error, got %v", err)
+	}
+}
@@ -1,42 +1,66 @@
 package service
 
 import (
+	"cmp"
+	"context"
 	"errors"
+	"fmt"
+	"time"
+
+	"taxifleet/backend/internal/config"
 	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/storage"
+	"taxifleet/backend/internal/validation"
 
+	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
+// AdminRepository is the data access AdminService depends on
+type AdminRepository interface {
+	repository.Transactor
+	repository.UserRepo
+	repository.TenantRepo
+	repository.PlanRepo
+	repository.LoginAuditRepo
+	repository.QueryStatsRepo
+}
+
 type AdminService struct {
-	repo *repository.Repository
+	repo    AdminRepository
+	cfg     *config.Config
+	exports *TenantExportService
+	files   storage.Store
 }
 
-func NewAdminService(repo *repository.Repository) *AdminService {
-	return &AdminService{repo: repo}
+func NewAdminService(repo AdminRepository, cfg *config.Config, exports *TenantExportService, files storage.Store) *AdminService {
+	return &AdminService{repo: repo, cfg: cfg, exports: exports, files: files}
 }
 
 // Tenant Management
 type CreateTenantRequest struct {
 	Name      string `json:"name" binding:"required"`
 	Subdomain string `json:"subdomain" binding:"required"`
-	Logo      string `json:"logo"`
-	Settings  string `json:"settings"`
+	Logo      string `json:"logo" binding:"max=1400000" sanitize:"-"` // URL or data URI of up to 1 MB
+	Settings  string `json:"settings" binding:"max=65536" sanitize:"-"`
+	PlanID    *uint  `json:"plan_id"` // Nil has no limits
 }
 
 type UpdateTenantRequest struct {
 	Name      string `json:"name"`
 	Subdomain string `json:"subdomain"`
-	Logo      string `json:"logo"`
-	Settings  string `json:"settings"`
+	Logo      string `json:"logo" binding:"max=1400000" sanitize:"-"` // URL or data URI of up to 1 MB
+	Settings  string `json:"settings" binding:"max=65536" sanitize:"-"`
+	PlanID    *uint  `json:"plan_id"` // 0 removes the plan
 }
 
-func (s *AdminService) CreateTenant(req CreateTenantRequest) (*repository.Tenant, error) {
+func (s *AdminService) CreateTenant(ctx context.Context, req CreateTenantRequest) (*repository.Tenant, error) {
 	// Check if subdomain already exists
-	_, err := s.repo.GetTenantBySubdomain(req.Subdomain)
+	_, err := s.repo.GetTenantBySubdomain(ctx, req.Subdomain)
 	if err == nil {
 		// Tenant found, subdomain already exists
-		return nil, errors.New("subdomain already exists")
+		return nil, conflict("subdomain already exists")
 	}
 	// If error is not "record not found", it's a real error that should be returned
 	if err != gorm.ErrRecordNotFound {
@@ -48,33 +72,42 @@ func (s *AdminService) CreateTenant(req CreateTenantRequest) (*repository.Tenant
 	if settings == "" {
 		settings = "{}"
 	}
+	if err := checkTenantSettings(settings); err != nil {
+		return nil, err
+	}
+	if req.PlanID != nil {
+		if _, err := s.repo.GetPlanByID(ctx, *req.PlanID); err != nil {
+			return nil, notFound("plan not found")
+		}
+	}
 
 	tenant := &repository.Tenant{
 		Name:      req.Name,
 		Subdomain: req.Subdomain,
 		Logo:      req.Logo,
 		Settings:  settings,
+		PlanID:    req.PlanID,
 	}
 
-	if err := s.repo.CreateTenant(tenant); err != nil {
+	if err := s.repo.CreateTenant(ctx, tenant); err != nil {
 		return nil, err
 	}
 
-	return s.repo.GetTenantByID(tenant.ID)
+	return s.repo.GetTenantByID(ctx, tenant.ID)
 }
 
-func (s *AdminService) GetAllTenants() ([]repository.Tenant, error) {
-	return s.repo.GetAllTenants()
+func (s *AdminService) GetAllTenants(ctx context.Context) ([]repository.Tenant, error) {
+	return s.repo.GetAllTenants(ctx)
 }
 
-func (s *AdminService) GetTenantByID(id uint) (*repository.Tenant, error) {
-	return s.repo.GetTenantByID(id)
+func (s *AdminService) GetTenantByID(ctx context.Context, id uint) (*repository.Tenant, error) {
+	return s.repo.GetTenantByID(ctx, id)
 }
 
-func (s *AdminService) UpdateTenant(id uint, req UpdateTenantRequest) (*repository.Tenant, error) {
-	tenant, err := s.repo.GetTenantByID(id)
+func (s *AdminService) UpdateTenant(ctx context.Context, id uint, req UpdateTenantRequest) (*repository.Tenant, error) {
+	tenant, err := s.repo.GetTenantByID(ctx, id)
 	if err != nil {
-		return nil, errors.New("tenant not found")
+		return nil, notFound("tenant not found")
 	}
 
 	if req.Name != "" {
@@ -83,10 +116,10 @@ func (s *AdminService) UpdateTenant(id uint, req UpdateTenantRequest) (*reposito
 	if req.Subdomain != "" {
 		// Check if subdomain is being changed and if new one exists
 		if tenant.Subdomain != req.Subdomain {
-			_, err := s.repo.GetTenantBySubdomain(req.Subdomain)
+			_, err := s.repo.GetTenantBySubdomain(ctx, req.Subdomain)
 			if err == nil {
 				// Tenant found, subdomain already exists
-				return nil, errors.New("subdomain already exists")
+				return nil, conflict("subdomain already exists")
 			}
 			// If error is not "record not found", it's a real error that should be returned
 			if err != gorm.ErrRecordNotFound {
@@ -100,55 +133,244 @@ func (s *AdminService) UpdateTenant(id uint, req UpdateTenantRequest) (*reposito
 		tenant.Logo = req.Logo
 	}
 	if req.Settings != "" {
+		if err := checkTenantSettings(req.Settings); err != nil {
+			return nil, err
+		}
 		tenant.Settings = req.Settings
 	}
+	if req.PlanID != nil {
+		// A plan lowering a limit below what the tenant has only stops it from adding more
+		if *req.PlanID == 0 {
+			tenant.PlanID = nil
+		} else if _, err := s.repo.GetPlanByID(ctx, *req.PlanID); err != nil {
+			return nil, notFound("plan not found")
+		} else {
+			tenant.PlanID = req.PlanID
+		}
+	}
+
+	if err := s.repo.UpdateTenant(ctx, tenant); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetTenantByID(ctx, tenant.ID)
+}
+
+// tenantDeletionTTL is how long a tenant deletion can be confirmed
+const tenantDeletionTTL = 15 * time.Minute
+
+var (
+	// ErrInvalidConfirmationToken is returned when a tenant deletion is
+	// confirmed with a missing, expired or foreign token
+	ErrInvalidConfirmationToken = errors.New("invalid or expired confirmation token")
+	// ErrOwnTenant is returned when an admin tries to delete their own tenant
+	ErrOwnTenant = errors.New("cannot delete your own tenant")
+)
+
+// TenantDeletion is the first step of deleting a tenant: what would be
+// deleted and the token to confirm it with
+type TenantDeletion struct {
+	TenantID          uint             `json:"tenant_id"`
+	Records           map[string]int64 `json:"records"`
+	ConfirmationToken string           `json:"confirmation_token"`
+	ExpiresAt         time.Time        `json:"expires_at"`
+}
+
+// RequestTenantDeletion counts the tenant's data and issues a short-lived
+// token that only the requesting admin can use to confirm the deletion
+func (s *AdminService) RequestTenantDeletion(ctx context.Context, id uint, adminID uint, adminTenantID uint) (*TenantDeletion, error) {
+	if id == adminTenantID {
+		return nil, ErrOwnTenant
+	}
+	if _, err := s.repo.GetTenantByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	records, err := s.repo.CountTenantData(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(tenantDeletionTTL)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"purpose":   "tenant_deletion",
+		"tenant_id": id,
+		"user_id":   adminID,
+		"exp":       expiresAt.Unix(),
+	})
+	signed, err := token.SignedString([]byte(s.cfg.JWT.Secret))
+	if err != nil {
+		return nil, err
+	}
+
+	return &TenantDeletion{
+		TenantID:          id,
+		Records:           records,
+		ConfirmationToken: signed,
+		ExpiresAt:         expiresAt,
+	}, nil
+}
+
+// DeleteTenant permanently deletes the tenant with all its data in one
+// transaction, then removes its export archives and report attachments. It
+// returns the number of deleted rows per table.
+func (s *AdminService) DeleteTenant(ctx context.Context, id uint, adminID uint, confirmationToken string) (map[string]int64, error) {
+	if !s.validDeletionToken(confirmationToken, id, adminID) {
+		return nil, ErrInvalidConfirmationToken
+	}
+
+	var deleted map[string]int64
+	err := s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		deleted, err = s.repo.PurgeTenant(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.exports.PurgeTenant(id); err != nil {
+		return nil, fmt.Errorf("tenant deleted but its export archives were not removed: %w", err)
+	}
+	if err := s.files.DeletePrefix(ctx, fmt.Sprintf("%d/", id)); err != nil {
+		return nil, fmt.Errorf("tenant deleted but its report attachments were not removed: %w", err)
+	}
+	return deleted, nil
+}
+
+func (s *AdminService) validDeletionToken(tokenString string, tenantID uint, adminID uint) bool {
+	if tokenString == "" {
+		return false
+	}
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		return []byte(s.cfg.JWT.Secret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil || !token.Valid {
+		return false
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return false
+	}
+	purpose, _ := claims["purpose"].(string)
+	claimedTenant, _ := claims["tenant_id"].(float64)
+	claimedUser, _ := claims["user_id"].(float64)
+	return purpose == "tenant_deletion" && uint(claimedTenant) == tenantID && uint(claimedUser) == adminID
+}
+
+// statsMonths is how many months of report counts tenant stats cover
+const statsMonths = 12
+
+// MonthlyReports is the number of reports of a month, formatted as YYYY-MM
+type MonthlyReports struct {
+	Month string `json:"month"`
+	Count int64  `json:"count"`
+}
+
+// TenantStats summarizes a tenant's usage for monitoring and billing
+type TenantStats struct {
+	TenantID        uint             `json:"tenant_id"`
+	Users           int64            `json:"users"`
+	Taxis           int64            `json:"taxis"`
+	ReportsPerMonth []MonthlyReports `json:"reports_per_month"` // Last 12 months, oldest first
+	Uploads         int64            `json:"uploads"`           // Receipts, deposit proofs and report attachments
+	LastActivityAt  *time.Time       `json:"last_activity_at"`
+}
+
+func (s *AdminService) GetTenantStats(ctx context.Context, id uint) (*TenantStats, error) {
+	if _, err := s.repo.GetTenantByID(ctx, id); err != nil {
+		return nil, err
+	}
 
-	if err := s.repo.UpdateTenant(tenant); err != nil {
+	usage, err := s.repo.GetTenantUsage(ctx, id)
+	if err != nil {
 		return nil, err
 	}
 
-	return s.repo.GetTenantByID(tenant.ID)
+	now := time.Now().UTC()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 1-statsMonths, 0)
+	counts, err := s.repo.CountReportsByMonth(ctx, id, from)
+	if err != nil {
+		return nil, err
+	}
+
+	// Months without reports are listed with a zero count
+	byMonth := make(map[string]int64, len(counts))
+	for _, c := range counts {
+		byMonth[c.Month.Format("2006-01")] = c.Count
+	}
+	months := make([]MonthlyReports, 0, statsMonths)
+	for i := 0; i < statsMonths; i++ {
+		month := from.AddDate(0, i, 0).Format("2006-01")
+		months = append(months, MonthlyReports{Month: month, Count: byMonth[month]})
+	}
+
+	return &TenantStats{
+		TenantID:        id,
+		Users:           usage.UserCount,
+		Taxis:           usage.TaxiCount,
+		ReportsPerMonth: months,
+		Uploads:         usage.UploadCount,
+		LastActivityAt:  usage.LastActivityAt,
+	}, nil
 }
 
-func (s *AdminService) DeleteTenant(id uint) error {
-	return s.repo.DeleteTenant(id)
+// ErrQueryStatsDisabled is returned for query statistics when the database
+// doesn't collect them
+var ErrQueryStatsDisabled = errors.New("query statistics need the pg_stat_statements extension")
+
+// GetSlowQueries returns the limit queries with the highest mean execution
+// time, to check the indexes serve the hot queries
+func (s *AdminService) GetSlowQueries(ctx context.Context, limit int) ([]repository.QueryStat, error) {
+	enabled, err := s.repo.QueryStatsEnabled(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return nil, ErrQueryStatsDisabled
+	}
+	return s.repo.GetSlowQueries(ctx, limit)
 }
 
 // User Management
 type CreateUserRequest struct {
 	TenantID   uint   `json:"tenant_id" binding:"required"`
 	Email      string `json:"email" binding:"required,email"`
-	Password   string `json:"password" binding:"required,min=6"`
+	Password   string `json:"password" binding:"required,min=6" sanitize:"-"`
 	Permission int    `json:"permission" binding:"required"`
 	FirstName  string `json:"first_name" binding:"required"`
 	LastName   string `json:"last_name" binding:"required"`
-	Phone      string `json:"phone" binding:"required"`
+	Phone      string `json:"phone" binding:"required,phone"`
 	Active     bool   `json:"active"`
 }
 
 type UpdateUserRequest struct {
 	TenantID   uint   `json:"tenant_id"`
 	Email      string `json:"email"`
-	Password   string `json:"password"`
+	Password   string `json:"password" sanitize:"-"`
 	Permission int    `json:"permission"`
 	FirstName  string `json:"first_name"`
 	LastName   string `json:"last_name"`
-	Phone      string `json:"phone" binding:"required"`
+	Phone      string `json:"phone" binding:"required,phone"`
 	Active     *bool  `json:"active"`
 }
 
-func (s *AdminService) CreateUser(req CreateUserRequest) (*repository.User, error) {
+func (s *AdminService) CreateUser(ctx context.Context, req CreateUserRequest) (*repository.User, error) {
 	// Verify tenant exists
-	_, err := s.repo.GetTenantByID(req.TenantID)
+	tenant, err := s.repo.GetTenantByID(ctx, req.TenantID)
 	if err != nil {
-		return nil, errors.New("tenant not found")
+		return nil, notFound("tenant not found")
+	}
+	if err := checkPlanLimit(ctx, s.repo, tenant, userLimit); err != nil {
+		return nil, err
 	}
 
 	// Check if email already exists
-	_, err = s.repo.GetUserByEmail(req.Email)
+	_, err = s.repo.GetUserByEmail(ctx, req.Email)
 	if err == nil {
 		// User found, email already exists
-		return nil, errors.New("email already exists")
+		return nil, conflict("email already exists")
 	}
 	// If error is not "record not found", it's a real error that should be returned
 	if err != gorm.ErrRecordNotFound {
@@ -157,10 +379,11 @@ func (s *AdminService) CreateUser(req CreateUserRequest) (*repository.User, erro
 	// err == gorm.ErrRecordNotFound means user doesn't exist, which is what we want
 
 	// Check if phone number already exists (phone must be unique globally)
-	_, err = s.repo.GetUserByPhone(req.Phone)
+	req.Phone, _ = validation.NormalizePhone(req.Phone)
+	_, err = s.repo.GetUserByPhone(ctx, req.Phone)
 	if err == nil {
 		// User found, phone already exists
-		return nil, errors.New("phone number already exists")
+		return nil, conflict("phone number already exists")
 	}
 	// If error is not "record not found", it's a real error that should be returned
 	if err != gorm.ErrRecordNotFound {
@@ -190,36 +413,69 @@ func (s *AdminService) CreateUser(req CreateUserRequest) (*repository.User, erro
 		Active:       active,
 	}
 
-	if err := s.repo.CreateUser(user); err != nil {
+	if err := s.repo.CreateUser(ctx, user); err != nil {
 		return nil, err
 	}
 
-	return s.repo.GetUserByID(user.ID)
+	return s.repo.GetUserByID(ctx, user.ID)
+}
+
+// userSortKeys are the fields user lists can be sorted by
+var userSortKeys = sortKeys[repository.User]{
+	"id":         func(a, b repository.User) int { return cmp.Compare(a.ID, b.ID) },
+	"email":      func(a, b repository.User) int { return cmp.Compare(a.Email, b.Email) },
+	"first_name": func(a, b repository.User) int { return cmp.Compare(a.FirstName, b.FirstName) },
+	"last_name":  func(a, b repository.User) int { return cmp.Compare(a.LastName, b.LastName) },
+	"permission": func(a, b repository.User) int { return cmp.Compare(a.Permission, b.Permission) },
+	"tenant_id":  func(a, b repository.User) int { return cmp.Compare(a.TenantID, b.TenantID) },
+	"created_at": func(a, b repository.User) int { return a.CreatedAt.Compare(b.CreatedAt) },
+}
+
+func (s *AdminService) GetAllUsers(ctx context.Context, sort string) ([]repository.User, error) {
+	users, err := s.repo.GetAllUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sortList(users, sort, userSortKeys)
 }
 
-func (s *AdminService) GetAllUsers() ([]repository.User, error) {
-	return s.repo.GetAllUsers()
+func (s *AdminService) GetUsersByTenant(ctx context.Context, tenantID uint, sort string) ([]repository.User, error) {
+	users, err := s.repo.GetUsersByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return sortList(users, sort, userSortKeys)
 }
 
-func (s *AdminService) GetUsersByTenant(tenantID uint) ([]repository.User, error) {
-	return s.repo.GetUsersByTenant(tenantID)
+func (s *AdminService) GetUserByID(ctx context.Context, id uint) (*repository.User, error) {
+	return s.repo.GetUserByID(ctx, id)
 }
 
-func (s *AdminService) GetUserByID(id uint) (*repository.User, error) {
-	return s.repo.GetUserByID(id)
+// GetUserLoginHistory returns the user's most recent login attempts
+func (s *AdminService) GetUserLoginHistory(ctx context.Context, id uint) ([]repository.LoginAudit, error) {
+	if _, err := s.repo.GetUserByID(ctx, id); err != nil {
+		return nil, notFound("user not found")
+	}
+	return s.repo.GetLoginAudits(ctx, id, loginHistoryLimit)
 }
 
-func (s *AdminService) UpdateUser(id uint, req UpdateUserRequest) (*repository.User, error) {
-	user, err := s.repo.GetUserByID(id)
+func (s *AdminService) UpdateUser(ctx context.Context, id uint, req UpdateUserRequest) (*repository.User, error) {
+	user, err := s.repo.GetUserByID(ctx, id)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, notFound("user not found")
 	}
 
 	if req.TenantID != 0 {
 		// Verify tenant exists
-		_, err := s.repo.GetTenantByID(req.TenantID)
+		tenant, err := s.repo.GetTenantByID(ctx, req.TenantID)
 		if err != nil {
-			return nil, errors.New("tenant not found")
+			return nil, notFound("tenant not found")
+		}
+		if req.TenantID != user.TenantID {
+			// Moving the user adds one to the tenant
+			if err := checkPlanLimit(ctx, s.repo, tenant, userLimit); err != nil {
+				return nil, err
+			}
 		}
 		user.TenantID = req.TenantID
 	}
@@ -227,10 +483,10 @@ func (s *AdminService) UpdateUser(id uint, req UpdateUserRequest) (*repository.U
 	if req.Email != "" {
 		// Check if email is being changed and if new one exists
 		if user.Email != req.Email {
-			_, err = s.repo.GetUserByEmail(req.Email)
+			_, err = s.repo.GetUserByEmail(ctx, req.Email)
 			if err == nil {
 				// User found, email already exists
-				return nil, errors.New("email already exists")
+				return nil, conflict("email already exists")
 			}
 			// If error is not "record not found", it's a real error that should be returned
 			if err != gorm.ErrRecordNotFound {
@@ -242,11 +498,12 @@ func (s *AdminService) UpdateUser(id uint, req UpdateUserRequest) (*repository.U
 	}
 
 	// Check if phone number is being changed and if new one exists
+	req.Phone, _ = validation.NormalizePhone(req.Phone)
 	if req.Phone != "" && user.Phone != req.Phone {
-		_, err = s.repo.GetUserByPhone(req.Phone)
+		_, err = s.repo.GetUserByPhone(ctx, req.Phone)
 		if err == nil {
 			// User found, phone already exists
-			return nil, errors.New("phone number already exists")
+			return nil, conflict("phone number already exists")
 		}
 		// If error is not "record not found", it's a real error that should be returned
 		if err != gorm.ErrRecordNotFound {
@@ -284,15 +541,15 @@ func (s *AdminService) UpdateUser(id uint, req UpdateUserRequest) (*repository.U
 		user.Active = *req.Active
 	}
 
-	if err := s.repo.UpdateUser(user); err != nil {
+	if err := s.repo.UpdateUser(ctx, user); err != nil {
 		return nil, err
 	}
 
-	return s.repo.GetUserByID(user.ID)
+	return s.repo.GetUserByID(ctx, user.ID)
 }
 
-func (s *AdminService) DeleteUser(id uint) error {
-	return s.repo.DeleteUser(id)
+func (s *AdminService) DeleteUser(ctx context.Context, id uint) error {
+	return s.repo.DeleteUser(ctx, id)
 }
 
 // Helper function to hash password
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"taxifleet/backend/internal/config"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+	"taxifleet/backend/internal/storage"
+
+	"go.uber.org/mock/gomock"
+)
+
+type adminRepoMock struct {
+	*mocks.MockTransactor
+	*mocks.MockUserRepo
+	*mocks.MockTenantRepo
+	*mocks.MockPlanRepo
+	*mocks.MockLoginAuditRepo
+	*mocks.MockQueryStatsRepo
+}
+
+func newAdminServiceMock(t *testing.T) (*AdminService, adminRepoMock) {
+	ctrl := gomock.NewController(t)
+	repo := adminRepoMock{
+		MockTransactor:     mocks.NewMockTransactor(ctrl),
+		MockUserRepo:       mocks.NewMockUserRepo(ctrl),
+		MockTenantRepo:     mocks.NewMockTenantRepo(ctrl),
+		MockPlanRepo:       mocks.NewMockPlanRepo(ctrl),
+		MockLoginAuditRepo: mocks.NewMockLoginAuditRepo(ctrl),
+		MockQueryStatsRepo: mocks.NewMockQueryStatsRepo(ctrl),
+	}
+	cfg := &config.Config{JWT: config.JWTConfig{Secret: "test-secret"}}
+	exports := NewTenantExportService(nil, nil, config.DataExportConfig{Dir: t.TempDir()})
+	return NewAdminService(repo, cfg, exports, storage.NewLocal(t.TempDir())), repo
+}
+
+func (r adminRepoMock) expectDeletionRequest() {
+	r.MockTenantRepo.EXPECT().GetTenantByID(gomock.Any(), uint(3)).Return(&repository.Tenant{ID: 3}, nil)
+	r.MockTenantRepo.EXPECT().CountTenantData(gomock.Any(), uint(3)).Return(map[string]int64{"taxis": 2}, nil)
+}
+
+func TestDeleteTenantWithConfirmationToken(t *testing.T) {
+	svc, repo := newAdminServiceMock(t)
+	repo.expectDeletionRequest()
+
+	deletion, err := svc.RequestTenantDeletion(context.Background(), 3, 1, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	repo.MockTransactor.EXPECT().InTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	})
+	repo.MockTenantRepo.EXPECT().PurgeTenant(gomock.Any(), uint(3)).Return(map[string]int64{"taxis": 2}, nil)
+
+	deleted, err := svc.DeleteTenant(context.Background(), 3, 1, deletion.ConfirmationToken)
+	if err != nil || deleted["taxis"] != 2 {
+		t.Fatalf("expected the tenant to be purged, got %v, %v", deleted, err)
+	}
+}
+
+func TestDeleteTenantRejectsForeignToken(t *testing.T) {
+	svc, repo := newAdminServiceMock(t)
+	repo.expectDeletionRequest()
+
+	deletion, err := svc.RequestTenantDeletion(context.Background(), 3, 1, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Another admin, another tenant or no token at all cannot confirm it
+	for _, attempt := range []struct {
+		tenantID, adminID uint
+		token             string
+	}{
+		{3, 2, deletion.ConfirmationToken},
+		{4, 1, deletion.ConfirmationToken},
+		{3, 1, ""},
+	} {
+		if _, err := svc.DeleteTenant(context.Background(), attempt.tenantID, attempt.adminID, attempt.token); !errors.Is(err, ErrInvalidConfirmationToken) {
+			t.Errorf("expected ErrInvalidConfirmationToken for %+v, got %v", attempt, err)
+		}
+	}
+}
+
+func TestRequestTenantDeletionRejectsOwnTenant(t *testing.T) {
+	svc, _ := newAdminServiceMock(t)
+
+	if _, err := svc.RequestTenantDeletion(context.Background(), 1, 1, 1); !errors.Is(err, ErrOwnTenant) {
+		t.Fatalf("expected ErrOwnTenant, got %v", err)
+	}
+}
+
+func TestCreateUserEnforcesPlanLimit(t *testing.T) {
+	svc, repo := newAdminServiceMock(t)
+	planID, maxUsers := uint(2), 3
+	repo.MockTenantRepo.EXPECT().GetTenantByID(gomock.Any(), uint(3)).Return(&repository.Tenant{ID: 3, PlanID: &planID}, nil)
+	repo.MockPlanRepo.EXPECT().GetPlanByID(gomock.Any(), planID).Return(&repository.Plan{ID: 2, Name: "Starter", MaxUsers: &maxUsers}, nil)
+	repo.MockPlanRepo.EXPECT().GetPlanUsage(gomock.Any(), uint(3), gomock.Any()).Return(&repository.PlanUsage{UserCount: 3}, nil)
+
+	_, err := svc.CreateUser(context.Background(), CreateUserRequest{TenantID: 3, Email: "new@example.com", Password: "secret1", Phone: "+22890000000"})
+	if !errors.Is(err, ErrPlanLimitReached) {
+		t.Fatalf("expected ErrPlanLimitReached, got %v", err)
+	}
+}
+
+func TestSlowQueriesNeedQueryStats(t *testing.T) {
+	svc, repo := newAdminServiceMock(t)
+
+	repo.MockQueryStatsRepo.EXPECT().QueryStatsEnabled(gomock.Any()).Return(false, nil)
+	if _, err := svc.GetSlowQueries(context.Background(), 20); !errors.Is(err, ErrQueryStatsDisabled) {
+		t.Fatalf("expected ErrQueryStatsDisabled, got %v", err)
+	}
+
+	repo.MockQueryStatsRepo.EXPECT().QueryStatsEnabled(gomock.Any()).Return(true, nil)
+	repo.MockQueryStatsRepo.EXPECT().GetSlowQueries(gomock.Any(), 20).Return([]repository.QueryStat{{Query: "SELECT 1", Calls: 3}}, nil)
+	queries, err := svc.GetSlowQueries(context.Background(), 20)
+	if err != nil || len(queries) != 1 {
+		t.Fatalf("expected one query, got %v, %v", queries, err)
+	}
+}
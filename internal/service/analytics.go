@@ -0,0 +1,346 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/tracing"
+)
+
+// AnalyticsRepository is the data access AnalyticsService depends on
+type AnalyticsRepository interface {
+	repository.AnalyticsRepo
+	repository.TenantRepo
+}
+
+type AnalyticsService struct {
+	repo AnalyticsRepository
+}
+
+func NewAnalyticsService(repo AnalyticsRepository) *AnalyticsService {
+	return &AnalyticsService{repo: repo}
+}
+
+// AnalyticsPeriod bounds analytics queries; both dates are inclusive
+type AnalyticsPeriod struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+type DriverAnalytics struct {
+	DriverID       uint         `json:"driver_id"`
+	DriverName     string       `json:"driver_name"`
+	ReportCount    int          `json:"report_count"`
+	TotalEarnings  money.Amount `json:"total_earnings"`
+	AveragePerWeek money.Amount `json:"average_per_week"`
+	OnTimeRate     float64      `json:"on_time_rate"`   // Share of submitted reports sent by the day after the week ended
+	RejectionRate  float64      `json:"rejection_rate"` // Share of reviewed reports that were rejected
+	ExpensesCaused money.Amount `json:"expenses_caused"`
+}
+
+type DriverAnalyticsResponse struct {
+	Period  AnalyticsPeriod   `json:"period"`
+	Drivers []DriverAnalytics `json:"drivers"`
+}
+
+type TaxiAnalytics struct {
+	TaxiID           uint         `json:"taxi_id"`
+	LicensePlate     string       `json:"license_plate"`
+	Model            string       `json:"model"`
+	Status           string       `json:"status"`
+	Earnings         money.Amount `json:"earnings"`
+	Expenses         money.Amount `json:"expenses"`
+	MaintenanceCosts money.Amount `json:"maintenance_costs"`
+	NetProfit        money.Amount `json:"net_profit"`
+	DowntimeDays     int          `json:"downtime_days"` // Days without an assigned driver
+}
+
+type TaxiAnalyticsResponse struct {
+	Period AnalyticsPeriod `json:"period"`
+	Taxis  []TaxiAnalytics `json:"taxis"`
+}
+
+// parsePeriod reads the from/to query dates, defaulting to the last 12 weeks
+func parsePeriod(from, to string) (AnalyticsPeriod, error) {
+	var period AnalyticsPeriod
+
+	now := time.Now()
+	period.To = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if to != "" {
+		date, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			return period, invalid("invalid to date format")
+		}
+		period.To = date
+	}
+
+	period.From = period.To.AddDate(0, 0, -12*7)
+	if from != "" {
+		date, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			return period, invalid("invalid from date format")
+		}
+		period.From = date
+	}
+
+	if period.From.After(period.To) {
+		return period, invalid("from must be before to")
+	}
+
+	return period, nil
+}
+
+func ratio(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total)
+}
+
+func (s *AnalyticsService) GetDriverAnalytics(ctx context.Context, tenantID uint, from, to string) (_ *DriverAnalyticsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "AnalyticsService.GetDriverAnalytics")
+	defer func() { tracing.End(span, err) }()
+
+	period, err := parsePeriod(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.repo.GetDriverPerformance(ctx, tenantID, period.From, period.To)
+	if err != nil {
+		return nil, err
+	}
+
+	drivers := make([]DriverAnalytics, 0, len(rows))
+	for _, row := range rows {
+		var average money.Amount
+		if row.ApprovedWeeks > 0 {
+			average = row.TotalEarnings.Div(row.ApprovedWeeks)
+		}
+
+		drivers = append(drivers, DriverAnalytics{
+			DriverID:       row.DriverID,
+			DriverName:     row.FirstName + " " + row.LastName,
+			ReportCount:    row.ReportCount,
+			TotalEarnings:  row.TotalEarnings,
+			AveragePerWeek: average,
+			OnTimeRate:     ratio(row.OnTimeCount, row.SubmittedCount),
+			RejectionRate:  ratio(row.RejectedCount, row.ReviewedCount),
+			ExpensesCaused: row.TotalExpenses,
+		})
+	}
+
+	return &DriverAnalyticsResponse{Period: period, Drivers: drivers}, nil
+}
+
+// GetTaxiAnalytics returns the profitability of every taxi of the tenant,
+// least profitable first
+func (s *AnalyticsService) GetTaxiAnalytics(ctx context.Context, tenantID uint, from, to string) (_ *TaxiAnalyticsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "AnalyticsService.GetTaxiAnalytics")
+	defer func() { tracing.End(span, err) }()
+
+	period, err := parsePeriod(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.repo.GetTaxiProfitability(ctx, tenantID, period.From, period.To)
+	if err != nil {
+		return nil, err
+	}
+
+	taxis := make([]TaxiAnalytics, 0, len(rows))
+	for _, row := range rows {
+		taxis = append(taxis, TaxiAnalytics{
+			TaxiID:           row.TaxiID,
+			LicensePlate:     row.LicensePlate,
+			Model:            row.Model,
+			Status:           row.Status,
+			Earnings:         row.Earnings,
+			Expenses:         row.Expenses,
+			MaintenanceCosts: row.MaintenanceCosts,
+			NetProfit:        row.Earnings - row.Expenses - row.MaintenanceCosts,
+			DowntimeDays:     row.DowntimeDays,
+		})
+	}
+
+	sort.SliceStable(taxis, func(i, j int) bool {
+		return taxis[i].NetProfit < taxis[j].NetProfit
+	})
+
+	return &TaxiAnalyticsResponse{Period: period, Taxis: taxis}, nil
+}
+
+type CategoryAmount struct {
+	Category string       `json:"category"`
+	Amount   money.Amount `json:"amount"`
+}
+
+// ProfitAndLoss is the monthly P&L statement of a tenant. Deposits are cash
+// moved to the bank and do not enter the net profit.
+type ProfitAndLoss struct {
+	Month           string           `json:"month"`
+	Revenue         money.Amount     `json:"revenue"` // Earnings of approved reports whose week starts in the month
+	Expenses        []CategoryAmount `json:"expenses"`
+	TotalExpenses   money.Amount     `json:"total_expenses"`
+	NetProfit       money.Amount     `json:"net_profit"`
+	Deposits        money.Amount     `json:"deposits"`
+	DepositCount    int              `json:"deposit_count"`
+	UndepositedCash money.Amount     `json:"undeposited_cash"` // Net profit minus deposits; negative when earlier cash was deposited
+}
+
+// GetProfitAndLoss builds the P&L statement for a month given as YYYY-MM,
+// defaulting to the current month
+func (s *AnalyticsService) GetProfitAndLoss(ctx context.Context, tenantID uint, month string) (_ *ProfitAndLoss, err error) {
+	ctx, span := tracing.Start(ctx, "AnalyticsService.GetProfitAndLoss")
+	defer func() { tracing.End(span, err) }()
+
+	start := time.Now()
+	if month != "" {
+		parsed, err := time.Parse("2006-01", month)
+		if err != nil {
+			return nil, invalid("invalid month format, expected YYYY-MM")
+		}
+		start = parsed
+	}
+	start = time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	revenue, err := s.repo.SumApprovedEarnings(ctx, tenantID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.repo.SumExpensesByCategory(ctx, tenantID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	deposits, depositCount, err := s.repo.SumDeposits(ctx, tenantID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	pnl := &ProfitAndLoss{
+		Month:        start.Format("2006-01"),
+		Revenue:      revenue,
+		Expenses:     make([]CategoryAmount, 0, len(totals)),
+		Deposits:     deposits,
+		DepositCount: depositCount,
+	}
+	for _, total := range totals {
+		pnl.Expenses = append(pnl.Expenses, CategoryAmount{Category: total.Category, Amount: total.Total})
+		pnl.TotalExpenses += total.Total
+	}
+	pnl.NetProfit = pnl.Revenue - pnl.TotalExpenses
+	pnl.UndepositedCash = pnl.NetProfit - pnl.Deposits
+
+	return pnl, nil
+}
+
+type VehicleTaxTotals struct {
+	TaxiID           uint         `json:"taxi_id"`
+	LicensePlate     string       `json:"license_plate"`
+	Revenue          money.Amount `json:"revenue"`
+	Expenses         money.Amount `json:"expenses"`
+	MaintenanceCosts money.Amount `json:"maintenance_costs"`
+	Net              money.Amount `json:"net"`
+}
+
+// TaxReport holds the annual figures needed for the tax declaration. Every
+// expense category and maintenance log is treated as deductible.
+type TaxReport struct {
+	FiscalYear         int                `json:"fiscal_year"`
+	PeriodStart        time.Time          `json:"period_start"`
+	PeriodEnd          time.Time          `json:"period_end"` // Inclusive
+	GrossRevenue       money.Amount       `json:"gross_revenue"`
+	DeductibleExpenses []CategoryAmount   `json:"deductible_expenses"`
+	MaintenanceCosts   money.Amount       `json:"maintenance_costs"`
+	TotalDeductible    money.Amount       `json:"total_deductible"`
+	TaxableIncome      money.Amount       `json:"taxable_income"`
+	Vehicles           []VehicleTaxTotals `json:"vehicles"`
+}
+
+// GetTaxReport builds the tax report for the fiscal year starting in the given
+// calendar year, using the tenant's fiscal_year_start setting. It defaults to
+// the last fully closed fiscal year.
+func (s *AnalyticsService) GetTaxReport(ctx context.Context, tenantID uint, year string) (_ *TaxReport, err error) {
+	ctx, span := tracing.Start(ctx, "AnalyticsService.GetTaxReport")
+	defer func() { tracing.End(span, err) }()
+
+	tenant, err := s.repo.GetTenantByID(ctx, tenantID)
+	if err != nil {
+		return nil, notFound("tenant not found")
+	}
+	settings := parseTenantSettings(tenant.Settings)
+
+	var fiscalYear int
+	if year != "" {
+		fiscalYear, err = strconv.Atoi(year)
+		if err != nil || fiscalYear < 1900 {
+			return nil, invalid("invalid year")
+		}
+	} else {
+		now := time.Now()
+		fiscalYear = now.Year() - 1
+		if _, end := settings.FiscalYear(fiscalYear); end.After(now) {
+			fiscalYear--
+		}
+	}
+
+	start, end := settings.FiscalYear(fiscalYear)
+	lastDay := end.AddDate(0, 0, -1)
+
+	revenue, err := s.repo.SumApprovedEarnings(ctx, tenantID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.repo.SumExpensesByCategory(ctx, tenantID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	maintenance, err := s.repo.SumMaintenanceCosts(ctx, tenantID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	taxis, err := s.repo.GetTaxiProfitability(ctx, tenantID, start, lastDay)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &TaxReport{
+		FiscalYear:         fiscalYear,
+		PeriodStart:        start,
+		PeriodEnd:          lastDay,
+		GrossRevenue:       revenue,
+		DeductibleExpenses: make([]CategoryAmount, 0, len(totals)),
+		MaintenanceCosts:   maintenance,
+		TotalDeductible:    maintenance,
+		Vehicles:           make([]VehicleTaxTotals, 0, len(taxis)),
+	}
+	for _, total := range totals {
+		report.DeductibleExpenses = append(report.DeductibleExpenses, CategoryAmount{Category: total.Category, Amount: total.Total})
+		report.TotalDeductible += total.Total
+	}
+	report.TaxableIncome = report.GrossRevenue - report.TotalDeductible
+
+	for _, taxi := range taxis {
+		report.Vehicles = append(report.Vehicles, VehicleTaxTotals{
+			TaxiID:           taxi.TaxiID,
+			LicensePlate:     taxi.LicensePlate,
+			Revenue:          taxi.Earnings,
+			Expenses:         taxi.Expenses,
+			MaintenanceCosts: taxi.MaintenanceCosts,
+			Net:              taxi.Earnings - taxi.Expenses - taxi.MaintenanceCosts,
+		})
+	}
+
+	return report, nil
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetProfitAndLoss(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := analyticsRepoMock{MockAnalyticsRepo: mocks.NewMockAnalyticsRepo(ctrl), MockTenantRepo: mocks.NewMockTenantRepo(ctrl)}
+	svc := NewAnalyticsService(repo)
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	repo.MockAnalyticsRepo.EXPECT().SumApprovedEarnings(gomock.Any(), uint(1), start, end).Return(money.Amount(5000_00), nil)
+	repo.MockAnalyticsRepo.EXPECT().SumExpensesByCategory(gomock.Any(), uint(1), start, end).Return([]repository.CategoryTotal{
+		{Category: "fuel", Total: 1200_00},
+		{Category: "repairs", Total: 300_00},
+	}, nil)
+	repo.MockAnalyticsRepo.EXPECT().SumDeposits(gomock.Any(), uint(1), start, end).Return(money.Amount(2000_00), 2, nil)
+
+	pnl, err := svc.GetProfitAndLoss(context.Background(), 1, "2026-03")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pnl.Month != "2026-03" || pnl.TotalExpenses != 1500_00 || pnl.NetProfit != 3500_00 {
+		t.Fatalf("unexpected statement %+v", pnl)
+	}
+	if pnl.Deposits != 2000_00 || pnl.DepositCount != 2 || pnl.UndepositedCash != 1500_00 {
+		t.Fatalf("expected 1500.00 of the net profit undeposited, got %+v", pnl)
+	}
+}
+
+func TestGetProfitAndLossRejectsInvalidMonth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	svc := NewAnalyticsService(analyticsRepoMock{MockAnalyticsRepo: mocks.NewMockAnalyticsRepo(ctrl), MockTenantRepo: mocks.NewMockTenantRepo(ctrl)})
+	if _, err := svc.GetProfitAndLoss(context.Background(), 1, "March"); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected an invalid month to be rejected, got %v", err)
+	}
+}
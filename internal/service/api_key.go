@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"taxifleet/backend/internal/permissions"
+	"taxifleet/backend/internal/repository"
+)
+
+// apiKeyPrefix marks API keys so they are easy to recognize, e.g. in leaked
+// secret scans
+const apiKeyPrefix = "tfk_"
+
+// apiKeyTouchInterval limits how often the last use of a key is written
+const apiKeyTouchInterval = time.Minute
+
+// ErrInvalidAPIKey is returned for an unknown, revoked or expired API key
+var ErrInvalidAPIKey = errors.New("invalid API key")
+
+// APIKeyScope grants read access to one kind of data
+type APIKeyScope struct {
+	Permission int
+	Paths      []string // Route prefixes the scope opens for GET requests
+}
+
+// APIKeyScopes are the scopes a key can be given. Keys are read-only.
+var APIKeyScopes = map[string]APIKeyScope{
+	"reports:read": {
+		Permission: permissions.PermissionViewReports,
+		Paths:      []string{"/api/v1/reports", "/api/v1/export/reports"},
+	},
+	"expenses:read": {
+		Permission: permissions.PermissionViewExpenses,
+		Paths:      []string{"/api/v1/expenses", "/api/v1/export/expenses"},
+	},
+}
+
+type APIKeyService struct {
+	repo repository.APIKeyRepo
+}
+
+func NewAPIKeyService(repo repository.APIKeyRepo) *APIKeyService {
+	return &APIKeyService{repo: repo}
+}
+
+type CreateAPIKeyRequest struct {
+	TenantID  uint       `json:"tenant_id" binding:"required"`
+	Name      string     `json:"name" binding:"required"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// CreatedAPIKey is returned once on creation; Key is never shown again
+type CreatedAPIKey struct {
+	*repository.APIKey
+	Key string `json:"key"`
+}
+
+func (s *APIKeyService) Create(ctx context.Context, createdByID uint, req CreateAPIKeyRequest) (*CreatedAPIKey, error) {
+	scopes, err := normalizeScopes(req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, invalid("expires_at must be in the future")
+	}
+
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	key := apiKeyPrefix + hex.EncodeToString(secret)
+
+	apiKey := &repository.APIKey{
+		TenantID:    req.TenantID,
+		Name:        req.Name,
+		Prefix:      key[:len(apiKeyPrefix)+8],
+		KeyHash:     hashAPIKey(key),
+		Scopes:      strings.Join(scopes, ","),
+		CreatedByID: &createdByID,
+		ExpiresAt:   req.ExpiresAt,
+	}
+	if err := s.repo.CreateAPIKey(ctx, apiKey); err != nil {
+		return nil, err
+	}
+
+	return &CreatedAPIKey{APIKey: apiKey, Key: key}, nil
+}
+
+// List returns the keys of a tenant, or of all tenants when tenantID is 0
+func (s *APIKeyService) List(ctx context.Context, tenantID uint) ([]repository.APIKey, error) {
+	return s.repo.GetAPIKeys(ctx, tenantID)
+}
+
+// Revoke disables a key for good
+func (s *APIKeyService) Revoke(ctx context.Context, id uint) (*repository.APIKey, error) {
+	apiKey, err := s.repo.GetAPIKeyByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if apiKey.RevokedAt == nil {
+		now := time.Now()
+		apiKey.RevokedAt = &now
+		if err := s.repo.UpdateAPIKey(ctx, apiKey); err != nil {
+			return nil, err
+		}
+	}
+	return apiKey, nil
+}
+
+// Authenticate returns the active key matching the given secret
+func (s *APIKeyService) Authenticate(ctx context.Context, key string) (*repository.APIKey, error) {
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+	apiKey, err := s.repo.GetAPIKeyByHash(ctx, hashAPIKey(key))
+	if err != nil {
+		return nil, ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	if apiKey.RevokedAt != nil || (apiKey.ExpiresAt != nil && now.After(*apiKey.ExpiresAt)) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > apiKeyTouchInterval {
+		if err := s.repo.TouchAPIKey(ctx, apiKey.ID, now); err != nil {
+			return nil, err
+		}
+	}
+	return apiKey, nil
+}
+
+// APIKeyPermission returns the permission mask granted by a key's scopes
+func APIKeyPermission(apiKey *repository.APIKey) int {
+	permission := 0
+	for _, name := range strings.Split(apiKey.Scopes, ",") {
+		permission |= APIKeyScopes[name].Permission
+	}
+	return permission
+}
+
+// APIKeyAllows reports whether a key's scopes open the given route for the method
+func APIKeyAllows(apiKey *repository.APIKey, method, route string) bool {
+	if method != "GET" && method != "HEAD" {
+		return false
+	}
+	for _, name := range strings.Split(apiKey.Scopes, ",") {
+		for _, prefix := range APIKeyScopes[name].Paths {
+			if route == prefix || strings.HasPrefix(route, prefix+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func normalizeScopes(scopes []string) ([]string, error) {
+	seen := make(map[string]bool, len(scopes))
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if _, ok := APIKeyScopes[scope]; !ok {
+			return nil, fmt.Errorf("unknown scope %q", scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			normalized = append(normalized, scope)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
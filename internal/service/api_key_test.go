@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"taxifleet/backend/internal/permissions"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestAPIKeyCreateStoresOnlyHash(t *testing.T) {
+	repo := mocks.NewMockAPIKeyRepo(gomock.NewController(t))
+	svc := NewAPIKeyService(repo)
+
+	var stored *repository.APIKey
+	repo.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, key *repository.APIKey) error {
+		stored = key
+		return nil
+	})
+
+	created, err := svc.Create(context.Background(), 1, CreateAPIKeyRequest{TenantID: 3, Name: "Accounting", Scopes: []string{"Reports:Read", "expenses:read", "reports:read"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored.KeyHash != hashAPIKey(created.Key) || stored.KeyHash == created.Key {
+		t.Fatal("expected only the hash of the key to be stored")
+	}
+	if stored.Scopes != "expenses:read,reports:read" {
+		t.Fatalf("expected normalized scopes, got %q", stored.Scopes)
+	}
+}
+
+func TestAPIKeyCreateRejectsUnknownScope(t *testing.T) {
+	svc := NewAPIKeyService(mocks.NewMockAPIKeyRepo(gomock.NewController(t)))
+
+	if _, err := svc.Create(context.Background(), 1, CreateAPIKeyRequest{TenantID: 3, Name: "Accounting", Scopes: []string{"taxis:write"}}); err == nil {
+		t.Fatal("expected an error for an unknown scope")
+	}
+}
+
+func TestAPIKeyAuthenticateRejectsRevokedKey(t *testing.T) {
+	repo := mocks.NewMockAPIKeyRepo(gomock.NewController(t))
+	svc := NewAPIKeyService(repo)
+
+	revokedAt := time.Now().Add(-time.Hour)
+	repo.EXPECT().GetAPIKeyByHash(gomock.Any(), hashAPIKey("tfk_abc")).Return(&repository.APIKey{ID: 1, RevokedAt: &revokedAt}, nil)
+
+	if _, err := svc.Authenticate(context.Background(), "tfk_abc"); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Fatalf("expected ErrInvalidAPIKey, got %v", err)
+	}
+}
+
+func TestAPIKeyScopes(t *testing.T) {
+	key := &repository.APIKey{Scopes: "reports:read"}
+
+	if APIKeyPermission(key) != permissions.PermissionViewReports {
+		t.Fatalf("expected view reports permission, got %d", APIKeyPermission(key))
+	}
+	tests := []struct {
+		method, route string
+		allowed       bool
+	}{
+		{"GET", "/api/v1/reports", true},
+		{"GET", "/api/v1/reports/:id", true},
+		{"GET", "/api/v1/export/reports", true},
+		{"POST", "/api/v1/reports", false},
+		{"GET", "/api/v1/expenses", false},
+		{"GET", "/api/v1/reportsx", false},
+		{"GET", "/api/v1/admin/api-keys", false},
+	}
+	for _, tt := range tests {
+		if got := APIKeyAllows(key, tt.method, tt.route); got != tt.allowed {
+			t.Errorf("APIKeyAllows(%s %s) = %v, want %v", tt.method, tt.route, got, tt.allowed)
+		}
+	}
+}
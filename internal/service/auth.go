@@ -1,39 +1,69 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"sync"
 	"time"
 
 	"taxifleet/backend/internal/config"
-	"taxifleet/backend/internal/permissions"
 	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/tokens"
+	"taxifleet/backend/internal/validation"
 
 	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
+// AuthRepository is the data access AuthService depends on
+type AuthRepository interface {
+	repository.UserRepo
+	repository.TenantRepo
+	repository.LoginAuditRepo
+}
+
 type AuthService struct {
-	repo *repository.Repository
-	cfg  *config.Config
+	repo          AuthRepository
+	sessions      repository.SessionRepo
+	notifications *NotificationService
+	cfg           *config.Config
+	keys          *tokens.KeySet
+
+	// Recently verified users, so access tokens only hit the database once
+	// per JWT.UserCheckInterval
+	checkedMu sync.Mutex
+	checked   map[uint]checkedUser
+}
+
+// checkedUser is the state of a user as last read from the database
+type checkedUser struct {
+	tenantID   uint
+	permission int
+	checkedAt  time.Time
 }
 
-func NewAuthService(repo *repository.Repository, cfg *config.Config) *AuthService {
-	return &AuthService{repo: repo, cfg: cfg}
+func NewAuthService(repo AuthRepository, sessions repository.SessionRepo, notifications *NotificationService, cfg *config.Config, keys *tokens.KeySet) *AuthService {
+	return &AuthService{
+		repo:          repo,
+		sessions:      sessions,
+		notifications: notifications,
+		cfg:           cfg,
+		keys:          keys,
+		checked:       make(map[uint]checkedUser),
+	}
 }
 
-type RegisterRequest struct {
-	Email     string `json:"email" binding:"required,email"`
-	Password  string `json:"password" binding:"required,min=6"`
-	FirstName string `json:"first_name" binding:"required"`
-	LastName  string `json:"last_name" binding:"required"`
-	Phone     string `json:"phone" binding:"required"`
-	Role      string `json:"role"`
+// TokenClaims identifies the caller of a validated access token
+type TokenClaims struct {
+	UserID     uint
+	TenantID   uint
+	Permission int
 }
 
 type LoginRequest struct {
 	EmailOrPhone string `json:"email_or_phone" binding:"required"`
-	Password     string `json:"password" binding:"required"`
+	Password     string `json:"password" binding:"required" sanitize:"-"`
 }
 
 type AuthResponse struct {
@@ -42,122 +72,59 @@ type AuthResponse struct {
 	User         *repository.User `json:"user"`
 }
 
-func (s *AuthService) Register(req RegisterRequest) (*AuthResponse, error) {
-	// Check if user exists by email
-	_, err := s.repo.GetUserByEmail(req.Email)
-	if err == nil {
-		// User found, email already exists
-		return nil, errors.New("user with this email already exists")
-	}
-	// If error is not "record not found", it's a real error that should be returned
-	if err != gorm.ErrRecordNotFound {
-		return nil, err
-	}
-	// err == gorm.ErrRecordNotFound means user doesn't exist, which is what we want
-
-	// Check if phone number already exists (phone must be unique globally)
-	_, err = s.repo.GetUserByPhone(req.Phone)
-	if err == nil {
-		// User found, phone already exists
-		return nil, errors.New("phone number already exists")
-	}
-	// If error is not "record not found", it's a real error that should be returned
-	if err != gorm.ErrRecordNotFound {
-		return nil, err
-	}
-	// err == gorm.ErrRecordNotFound means phone doesn't exist, which is what we want
-
-	// Hash password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
-	if err != nil {
-		return nil, err
-	}
-
-	// Create default tenant for new user (simplified - in production, handle tenant creation separately)
-	tenant := &repository.Tenant{
-		Name:      req.FirstName + " " + req.LastName,
-		Subdomain: generateSubdomain(req.Email),
-		Settings:  "{}", // Valid JSON for JSONB column
-	}
-	if err := s.repo.CreateTenant(tenant); err != nil {
-		return nil, err
-	}
-
-	// Set default permission (owner if not specified)
-	userPermission := permissions.PermissionOwner
-	if req.Role != "" {
-		userPermission = permissions.GetPermissionForRole(req.Role)
-	}
-
-	// Create user
-	user := &repository.User{
-		TenantID:     tenant.ID,
-		Email:        req.Email,
-		PasswordHash: string(hashedPassword),
-		Permission:   userPermission,
-		FirstName:    req.FirstName,
-		LastName:     req.LastName,
-		Phone:        req.Phone,
-		Active:       true,
+// Login signs a user in with their password. Every attempt is audited.
+func (s *AuthService) Login(ctx context.Context, req LoginRequest, client LoginClient) (*AuthResponse, error) {
+	// Get user by email or phone; phones are stored in E.164 form
+	if phone, ok := validation.NormalizePhone(req.EmailOrPhone); ok {
+		req.EmailOrPhone = phone
 	}
-
-	if err := s.repo.CreateUser(user); err != nil {
-		return nil, err
-	}
-
-	// Generate tokens
-	token, refreshToken, err := s.generateTokens(user)
-	if err != nil {
-		return nil, err
-	}
-
-	// Create session
-	session := &repository.Session{
-		UserID:    user.ID,
-		Token:     refreshToken,
-		ExpiresAt: time.Now().Add(s.cfg.JWT.RefreshExpiration),
-	}
-	if err := s.repo.CreateSession(session); err != nil {
-		return nil, err
-	}
-
-	return &AuthResponse{
-		Token:        token,
-		RefreshToken: refreshToken,
-		User:         user,
-	}, nil
-}
-
-func (s *AuthService) Login(req LoginRequest) (*AuthResponse, error) {
-	// Get user by email or phone
-	user, err := s.repo.GetUserByEmailOrPhone(req.EmailOrPhone)
+	user, err := s.repo.GetUserByEmailOrPhone(ctx, req.EmailOrPhone)
 	if err != nil {
+		if err := s.recordFailedLogin(ctx, nil, req.EmailOrPhone, loginMethodPassword, client, "unknown user"); err != nil {
+			return nil, err
+		}
 		return nil, errors.New("invalid credentials")
 	}
 
 	// Check password
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		if err := s.recordFailedLogin(ctx, &user.ID, req.EmailOrPhone, loginMethodPassword, client, "wrong password"); err != nil {
+			return nil, err
+		}
 		return nil, errors.New("invalid credentials")
 	}
 
 	// Check if user is active
 	if !user.Active {
+		if err := s.recordFailedLogin(ctx, &user.ID, req.EmailOrPhone, loginMethodPassword, client, "inactive user"); err != nil {
+			return nil, err
+		}
 		return nil, errors.New("user account is inactive")
 	}
+	if user.Unverified {
+		if err := s.recordFailedLogin(ctx, &user.ID, req.EmailOrPhone, loginMethodPassword, client, "unverified email"); err != nil {
+			return nil, err
+		}
+		return nil, ErrEmailNotVerified
+	}
 
-	// Generate tokens
-	token, refreshToken, err := s.generateTokens(user)
+	return s.completeLogin(ctx, user, req.EmailOrPhone, loginMethodPassword, client)
+}
+
+// startSession issues an access and a refresh token for the user and stores
+// the session of the refresh token
+func (s *AuthService) startSession(ctx context.Context, user *repository.User) (*AuthResponse, error) {
+	token, refreshToken, err := s.generateTokens(ctx, user)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create session
 	session := &repository.Session{
 		UserID:    user.ID,
 		Token:     refreshToken,
 		ExpiresAt: time.Now().Add(s.cfg.JWT.RefreshExpiration),
 	}
-	if err := s.repo.CreateSession(session); err != nil {
+	if err := s.sessions.CreateSession(ctx, session); err != nil {
 		return nil, err
 	}
 
@@ -168,21 +135,21 @@ func (s *AuthService) Login(req LoginRequest) (*AuthResponse, error) {
 	}, nil
 }
 
-func (s *AuthService) RefreshToken(refreshToken string) (string, error) {
+func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (string, error) {
 	// Get session
-	session, err := s.repo.GetSessionByToken(refreshToken)
+	session, err := s.sessions.GetSessionByToken(ctx, refreshToken)
 	if err != nil {
 		return "", errors.New("invalid refresh token")
 	}
 
 	// Get user
-	user, err := s.repo.GetUserByID(session.UserID)
+	user, err := s.repo.GetUserByID(ctx, session.UserID)
 	if err != nil {
-		return "", errors.New("user not found")
+		return "", notFound("user not found")
 	}
 
 	// Generate new access token
-	token, _, err := s.generateTokens(user)
+	token, _, err := s.generateTokens(ctx, user)
 	if err != nil {
 		return "", err
 	}
@@ -190,18 +157,22 @@ func (s *AuthService) RefreshToken(refreshToken string) (string, error) {
 	return token, nil
 }
 
-func (s *AuthService) Logout(refreshToken string) error {
-	return s.repo.DeleteSession(refreshToken)
+func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
+	return s.sessions.DeleteSession(ctx, refreshToken)
 }
 
-func (s *AuthService) ValidateToken(tokenString string) (*repository.User, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		return []byte(s.cfg.JWT.Secret), nil
-	})
+// CleanupSessions permanently removes expired and logged out sessions and
+// returns how many were removed
+func (s *AuthService) CleanupSessions(ctx context.Context) (int64, error) {
+	return s.sessions.DeleteStaleSessions(ctx, time.Now())
+}
 
+// ValidateToken verifies an access token and returns its claims. The user is
+// looked up at most once per JWT.UserCheckInterval; a token is rejected once
+// its user is deactivated or has moved tenant or permission, so the client
+// refreshes it.
+func (s *AuthService) ValidateToken(ctx context.Context, tokenString string) (*TokenClaims, error) {
+	token, err := s.keys.Parse(tokenString)
 	if err != nil {
 		// Check if token is expired
 		if errors.Is(err, jwt.ErrTokenExpired) {
@@ -224,30 +195,92 @@ func (s *AuthService) ValidateToken(tokenString string) (*repository.User, error
 		return nil, errors.New("invalid user ID in token")
 	}
 
-	user, err := s.repo.GetUserByID(uint(userID))
+	current, err := s.checkUser(ctx, uint(userID))
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, err
+	}
+
+	tenantID, hasTenant := claims["tenant_id"].(float64)
+	permission, hasPermission := claims["permission"].(float64)
+	if !hasTenant || !hasPermission {
+		// Tokens issued before tenant_id was embedded
+		return &TokenClaims{UserID: uint(userID), TenantID: current.tenantID, Permission: current.permission}, nil
+	}
+	if uint(tenantID) != current.tenantID || int(permission) != current.permission {
+		return nil, errors.New("token is outdated, please refresh")
+	}
+
+	return &TokenClaims{UserID: uint(userID), TenantID: uint(tenantID), Permission: int(permission)}, nil
+}
+
+// checkUser returns the user's tenant and permission, reading them from the
+// database only if they were not checked within the interval
+func (s *AuthService) checkUser(ctx context.Context, userID uint) (checkedUser, error) {
+	s.checkedMu.Lock()
+	cached, ok := s.checked[userID]
+	s.checkedMu.Unlock()
+	if ok && time.Since(cached.checkedAt) < s.cfg.JWT.UserCheckInterval {
+		return cached, nil
+	}
+
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		s.forgetUser(userID)
+		return checkedUser{}, notFound("user not found")
 	}
 
 	// Check if user is still active
 	if !user.Active {
-		return nil, errors.New("user account is inactive")
+		s.forgetUser(userID)
+		return checkedUser{}, errors.New("user account is inactive")
+	}
+
+	current := checkedUser{tenantID: user.TenantID, permission: user.Permission, checkedAt: time.Now()}
+	s.checkedMu.Lock()
+	// Drop stale entries now and then so users who left don't pile up
+	if len(s.checked) >= 10000 {
+		for id, c := range s.checked {
+			if time.Since(c.checkedAt) >= s.cfg.JWT.UserCheckInterval {
+				delete(s.checked, id)
+			}
+		}
 	}
+	s.checked[userID] = current
+	s.checkedMu.Unlock()
+	return current, nil
+}
+
+func (s *AuthService) forgetUser(userID uint) {
+	s.checkedMu.Lock()
+	delete(s.checked, userID)
+	s.checkedMu.Unlock()
+}
 
+// GetUser returns the signed-in user
+func (s *AuthService) GetUser(ctx context.Context, userID uint) (*repository.User, error) {
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, notFound("user not found")
+	}
 	return user, nil
 }
 
-func (s *AuthService) generateTokens(user *repository.User) (string, string, error) {
+// JWKS returns the public keys other services can verify access tokens with
+func (s *AuthService) JWKS() tokens.JWKS {
+	return s.keys.JWKS()
+}
+
+func (s *AuthService) generateTokens(ctx context.Context, user *repository.User) (string, string, error) {
 	// Access token
 	accessClaims := jwt.MapClaims{
 		"user_id":    user.ID,
+		"tenant_id":  user.TenantID,
 		"email":      user.Email,
 		"permission": user.Permission,
 		"iat":        time.Now().Unix(),
 		"exp":        time.Now().Add(s.cfg.JWT.Expiration).Unix(),
 	}
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
-	accessTokenString, err := accessToken.SignedString([]byte(s.cfg.JWT.Secret))
+	accessTokenString, err := s.keys.Sign(accessClaims)
 	if err != nil {
 		return "", "", err
 	}
@@ -255,12 +288,12 @@ func (s *AuthService) generateTokens(user *repository.User) (string, string, err
 	// Refresh token (longer expiration)
 	refreshClaims := jwt.MapClaims{
 		"user_id":    user.ID,
+		"tenant_id":  user.TenantID,
 		"permission": user.Permission,
 		"iat":        time.Now().Unix(),
 		"exp":        time.Now().Add(s.cfg.JWT.RefreshExpiration).Unix(),
 	}
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
-	refreshTokenString, err := refreshToken.SignedString([]byte(s.cfg.JWT.Secret))
+	refreshTokenString, err := s.keys.Sign(refreshClaims)
 	if err != nil {
 		return "", "", err
 	}
@@ -272,16 +305,17 @@ type UpdateProfileRequest struct {
 	FirstName       string `json:"first_name"`
 	LastName        string `json:"last_name"`
 	Email           string `json:"email" binding:"omitempty,email"`
-	Phone           string `json:"phone"`
-	CurrentPassword string `json:"current_password"` // Required when updating password
-	NewPassword     string `json:"new_password"`     // Optional, only if changing password
+	Phone           string `json:"phone" binding:"omitempty,phone"`
+	CurrentPassword string `json:"current_password" sanitize:"-"` // Required when updating password
+	NewPassword     string `json:"new_password" sanitize:"-"`     // Optional, only if changing password
+	ApprovalPIN     string `json:"approval_pin" sanitize:"-"`     // Optional, signs off report approvals; requires the current password
 }
 
-func (s *AuthService) UpdateProfile(userID uint, req UpdateProfileRequest) (*repository.User, error) {
+func (s *AuthService) UpdateProfile(ctx context.Context, userID uint, req UpdateProfileRequest) (*repository.User, error) {
 	// Get current user
-	user, err := s.repo.GetUserByID(userID)
+	user, err := s.repo.GetUserByID(ctx, userID)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, notFound("user not found")
 	}
 
 	// Update first name
@@ -297,10 +331,10 @@ func (s *AuthService) UpdateProfile(userID uint, req UpdateProfileRequest) (*rep
 	// Update email (with uniqueness check)
 	if req.Email != "" && req.Email != user.Email {
 		// Check if email already exists
-		_, err := s.repo.GetUserByEmail(req.Email)
+		_, err := s.repo.GetUserByEmail(ctx, req.Email)
 		if err == nil {
 			// User found, email already exists
-			return nil, errors.New("email already exists")
+			return nil, conflict("email already exists")
 		}
 		// If error is not "record not found", it's a real error
 		if err != gorm.ErrRecordNotFound {
@@ -311,12 +345,13 @@ func (s *AuthService) UpdateProfile(userID uint, req UpdateProfileRequest) (*rep
 	}
 
 	// Update phone (with uniqueness check)
+	req.Phone, _ = validation.NormalizePhone(req.Phone)
 	if req.Phone != "" && req.Phone != user.Phone {
 		// Check if phone already exists
-		_, err := s.repo.GetUserByPhone(req.Phone)
+		_, err := s.repo.GetUserByPhone(ctx, req.Phone)
 		if err == nil {
 			// User found, phone already exists
-			return nil, errors.New("phone number already exists")
+			return nil, conflict("phone number already exists")
 		}
 		// If error is not "record not found", it's a real error
 		if err != gorm.ErrRecordNotFound {
@@ -326,11 +361,30 @@ func (s *AuthService) UpdateProfile(userID uint, req UpdateProfileRequest) (*rep
 		user.Phone = req.Phone
 	}
 
+	// Set the approval PIN, which stands in for the password when signing off
+	// an approval, so it takes the password to set it
+	if req.ApprovalPIN != "" {
+		if req.CurrentPassword == "" {
+			return nil, invalid("current password is required to set the approval PIN")
+		}
+		if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.CurrentPassword)); err != nil {
+			return nil, errors.New("current password is incorrect")
+		}
+		if err := checkApprovalPIN(req.ApprovalPIN); err != nil {
+			return nil, err
+		}
+		hashedPIN, err := bcrypt.GenerateFromPassword([]byte(req.ApprovalPIN), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, errors.New("failed to hash approval PIN")
+		}
+		user.PINHash = string(hashedPIN)
+	}
+
 	// Update password (if provided)
 	if req.NewPassword != "" {
 		// Require current password for security
 		if req.CurrentPassword == "" {
-			return nil, errors.New("current password is required to change password")
+			return nil, invalid("current password is required to change password")
 		}
 
 		// Verify current password
@@ -341,7 +395,7 @@ func (s *AuthService) UpdateProfile(userID uint, req UpdateProfileRequest) (*rep
 
 		// Validate new password
 		if len(req.NewPassword) < 6 {
-			return nil, errors.New("new password must be at least 6 characters")
+			return nil, invalid("new password must be at least 6 characters")
 		}
 
 		// Hash new password
@@ -353,16 +407,10 @@ func (s *AuthService) UpdateProfile(userID uint, req UpdateProfileRequest) (*rep
 	}
 
 	// Save updated user
-	if err := s.repo.UpdateUser(user); err != nil {
+	if err := s.repo.UpdateUser(ctx, user); err != nil {
 		return nil, errors.New("failed to update profile")
 	}
 
 	// Return updated user
-	return s.repo.GetUserByID(userID)
-}
-
-func generateSubdomain(email string) string {
-	// Simple subdomain generation from email
-	// In production, ensure uniqueness
-	return email[:len(email)-len("@example.com")]
+	return s.repo.GetUserByID(ctx, userID)
 }
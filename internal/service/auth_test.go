@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"taxifleet/backend/internal/config"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+	"taxifleet/backend/internal/tokens"
+
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type authRepoMock struct {
+	*mocks.MockUserRepo
+	*mocks.MockTenantRepo
+	*mocks.MockSessionRepo
+	*mocks.MockLoginAuditRepo
+}
+
+func newAuthServiceMock(t *testing.T, checkInterval time.Duration) (*AuthService, authRepoMock) {
+	ctrl := gomock.NewController(t)
+	repo := authRepoMock{
+		MockUserRepo:       mocks.NewMockUserRepo(ctrl),
+		MockTenantRepo:     mocks.NewMockTenantRepo(ctrl),
+		MockSessionRepo:    mocks.NewMockSessionRepo(ctrl),
+		MockLoginAuditRepo: mocks.NewMockLoginAuditRepo(ctrl),
+	}
+	cfg := &config.Config{JWT: config.JWTConfig{Secret: "test-secret", Expiration: time.Hour, RefreshExpiration: time.Hour, UserCheckInterval: checkInterval}}
+	keys, err := tokens.Load(cfg.JWT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewAuthService(repo, repo, nil, cfg, keys), repo
+}
+
+func TestValidateTokenChecksUserOncePerInterval(t *testing.T) {
+	svc, repo := newAuthServiceMock(t, time.Minute)
+	user := &repository.User{ID: 4, TenantID: 2, Permission: 8, Active: true}
+
+	token, _, err := svc.generateTokens(context.Background(), user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo.MockUserRepo.EXPECT().GetUserByID(gomock.Any(), uint(4)).Return(user, nil).Times(1)
+	for i := 0; i < 3; i++ {
+		claims, err := svc.ValidateToken(context.Background(), token)
+		if err != nil {
+			t.Fatalf("validate: %v", err)
+		}
+		if claims.UserID != 4 || claims.TenantID != 2 || claims.Permission != 8 {
+			t.Errorf("unexpected claims %+v", claims)
+		}
+	}
+}
+
+func TestValidateTokenRejectsChangedUser(t *testing.T) {
+	svc, repo := newAuthServiceMock(t, 0)
+	user := &repository.User{ID: 4, TenantID: 2, Permission: 8, Active: true}
+
+	token, _, err := svc.generateTokens(context.Background(), user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo.MockUserRepo.EXPECT().GetUserByID(gomock.Any(), uint(4)).Return(&repository.User{ID: 4, TenantID: 2, Permission: 1, Active: true}, nil)
+	if _, err := svc.ValidateToken(context.Background(), token); err == nil || err.Error() != "token is outdated, please refresh" {
+		t.Errorf("expected outdated token error, got %v", err)
+	}
+
+	repo.MockUserRepo.EXPECT().GetUserByID(gomock.Any(), uint(4)).Return(&repository.User{ID: 4, TenantID: 2, Permission: 8, Active: false}, nil)
+	if _, err := svc.ValidateToken(context.Background(), token); err == nil || err.Error() != "user account is inactive" {
+		t.Errorf("expected inactive user error, got %v", err)
+	}
+}
+
+func TestLoginAuditsFailedAttempt(t *testing.T) {
+	svc, repo := newAuthServiceMock(t, time.Minute)
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &repository.User{ID: 4, TenantID: 2, Email: "driver@example.com", PasswordHash: string(hash), Active: true}
+	client := LoginClient{IPAddress: "203.0.113.7", UserAgent: "TaxiFleet/1.0"}
+
+	repo.MockUserRepo.EXPECT().GetUserByEmailOrPhone(gomock.Any(), "driver@example.com").Return(user, nil)
+	repo.MockLoginAuditRepo.EXPECT().CreateLoginAudit(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, audit *repository.LoginAudit) error {
+		if audit.UserID == nil || *audit.UserID != 4 || audit.Success || audit.FailureReason != "wrong password" {
+			t.Errorf("unexpected audit %+v", audit)
+		}
+		if audit.IPAddress != "203.0.113.7" || audit.DeviceFingerprint != client.Fingerprint() {
+			t.Errorf("audit does not describe the client: %+v", audit)
+		}
+		return nil
+	})
+
+	_, err = svc.Login(context.Background(), LoginRequest{EmailOrPhone: "driver@example.com", Password: "wrong"}, client)
+	if err == nil || err.Error() != "invalid credentials" {
+		t.Fatalf("expected invalid credentials, got %v", err)
+	}
+}
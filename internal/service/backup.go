@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"taxifleet/backend/internal/config"
+	"taxifleet/backend/internal/database"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/storage"
+)
+
+var (
+	// ErrBackupNotFound is returned for an unknown backup name
+	ErrBackupNotFound = errors.New("backup not found")
+	// ErrRestoreDisabled is returned when no staging database is configured
+	ErrRestoreDisabled = errors.New("restoring backups requires BACKUP_RESTORE_DATABASE_URL")
+)
+
+// backupPrefix is where backups are kept in the file storage
+const backupPrefix = "backups/"
+
+// backupTimeFormat is the time in backup names, sortable as text
+const backupTimeFormat = "20060102T150405Z"
+
+// backupNamePattern matches database-<time>.dump and tenant-<ID>-<time>.sql
+var backupNamePattern = regexp.MustCompile(`^(?:database|tenant-(\d+))-(\d{8}T\d{6}Z)\.(dump|sql)$`)
+
+// BackupRepository is the data access BackupService depends on
+type BackupRepository interface {
+	repository.BackupRepo
+	repository.TenantRepo
+}
+
+// BackupService dumps the whole database or a single tenant into the file
+// storage and restores the dumps into a staging database
+type BackupService struct {
+	repo   BackupRepository
+	dumper database.Dumper
+	files  storage.Store
+	cfg    config.BackupConfig
+	now    func() time.Time
+}
+
+func NewBackupService(repo BackupRepository, dumper database.Dumper, files storage.Store, cfg config.BackupConfig) *BackupService {
+	return &BackupService{repo: repo, dumper: dumper, files: files, cfg: cfg, now: time.Now}
+}
+
+// Backup is a dump in the file storage
+type Backup struct {
+	Name      string    `json:"name"`
+	TenantID  uint      `json:"tenant_id,omitempty"` // Zero for the whole database
+	Format    string    `json:"format"`              // custom (pg_dump) or sql
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// parseBackup reads a backup's name
+func parseBackup(name string) (*Backup, bool) {
+	match := backupNamePattern.FindStringSubmatch(name)
+	if match == nil {
+		return nil, false
+	}
+	createdAt, err := time.Parse(backupTimeFormat, match[2])
+	if err != nil {
+		return nil, false
+	}
+	backup := &Backup{Name: name, Format: database.FormatCustom, CreatedAt: createdAt}
+	if match[1] != "" {
+		tenantID, err := strconv.ParseUint(match[1], 10, 32)
+		if err != nil {
+			return nil, false
+		}
+		backup.TenantID = uint(tenantID)
+		backup.Format = database.FormatSQL
+	}
+	return backup, true
+}
+
+// BackupDatabase dumps the whole database with pg_dump
+func (s *BackupService) BackupDatabase(ctx context.Context) (*Backup, error) {
+	backup := &Backup{Format: database.FormatCustom, CreatedAt: s.now().UTC().Truncate(time.Second)}
+	backup.Name = "database-" + backup.CreatedAt.Format(backupTimeFormat) + ".dump"
+	if err := s.store(ctx, backup, s.dumper.Dump); err != nil {
+		return nil, err
+	}
+	return backup, nil
+}
+
+// BackupTenant dumps the tenant's rows as a SQL script
+func (s *BackupService) BackupTenant(ctx context.Context, tenantID uint) (*Backup, error) {
+	if _, err := s.repo.GetTenantByID(ctx, tenantID); err != nil {
+		return nil, err
+	}
+	backup := &Backup{TenantID: tenantID, Format: database.FormatSQL, CreatedAt: s.now().UTC().Truncate(time.Second)}
+	backup.Name = fmt.Sprintf("tenant-%d-%s.sql", tenantID, backup.CreatedAt.Format(backupTimeFormat))
+	err := s.store(ctx, backup, func(ctx context.Context, w io.Writer) error {
+		return s.repo.DumpTenant(ctx, tenantID, w)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return backup, nil
+}
+
+// store writes the dump to a temporary file first, so a failed dump never
+// reaches the storage, and then stores it
+func (s *BackupService) store(ctx context.Context, backup *Backup, dump func(context.Context, io.Writer) error) error {
+	tmp, err := os.CreateTemp("", "backup-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if err := dump(ctx, tmp); err != nil {
+		return err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	return s.files.Put(ctx, backupPrefix+backup.Name, tmp, "application/octet-stream")
+}
+
+// List returns the stored backups, newest first
+func (s *BackupService) List(ctx context.Context) ([]Backup, error) {
+	var backups []Backup
+	err := s.files.Walk(ctx, backupPrefix, func(key string) error {
+		if backup, ok := parseBackup(path.Base(key)); ok {
+			backups = append(backups, *backup)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(backups, func(i, j int) bool {
+		return backups[i].CreatedAt.After(backups[j].CreatedAt)
+	})
+	return backups, nil
+}
+
+// Restore loads a backup into the staging database. A database backup
+// replaces the objects it holds; a tenant backup inserts the tenant's rows
+// into a database with the same schema, so the tenant must not exist there.
+func (s *BackupService) Restore(ctx context.Context, name string) error {
+	if s.cfg.RestoreURL == "" {
+		return ErrRestoreDisabled
+	}
+	backup, ok := parseBackup(name)
+	if !ok {
+		return ErrBackupNotFound
+	}
+	file, err := s.files.Open(ctx, backupPrefix+backup.Name)
+	if errors.Is(err, storage.ErrNotExist) {
+		return ErrBackupNotFound
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return s.dumper.Restore(ctx, s.cfg.RestoreURL, backup.Format, file)
+}
+
+// Delete removes a backup
+func (s *BackupService) Delete(ctx context.Context, name string) error {
+	if _, ok := parseBackup(name); !ok {
+		return ErrBackupNotFound
+	}
+	return s.files.Delete(ctx, backupPrefix+name)
+}
+
+// Scheduled backs up the database and removes the oldest database backups
+// beyond the configured number to keep. Tenant backups are kept until deleted.
+func (s *BackupService) Scheduled(ctx context.Context) error {
+	if _, err := s.BackupDatabase(ctx); err != nil {
+		return err
+	}
+	backups, err := s.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	kept := 0
+	for _, backup := range backups {
+		if backup.TenantID != 0 {
+			continue
+		}
+		if kept++; kept <= s.cfg.Keep {
+			continue
+		}
+		if err := s.files.Delete(ctx, backupPrefix+backup.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"taxifleet/backend/internal/config"
+	"taxifleet/backend/internal/database"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+	"taxifleet/backend/internal/storage"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+type backupRepoMock struct {
+	*mocks.MockBackupRepo
+	*mocks.MockTenantRepo
+}
+
+// fakeDumper dumps fixed content and records what it restored
+type fakeDumper struct {
+	dump     string
+	target   string
+	format   string
+	restored string
+}
+
+func (d *fakeDumper) Dump(ctx context.Context, w io.Writer) error {
+	_, err := io.WriteString(w, d.dump)
+	return err
+}
+
+func (d *fakeDumper) Restore(ctx context.Context, target string, format string, backup io.Reader) error {
+	content, err := io.ReadAll(backup)
+	d.target, d.format, d.restored = target, format, string(content)
+	return err
+}
+
+func newBackupServiceMock(t *testing.T, cfg config.BackupConfig) (*BackupService, backupRepoMock, *fakeDumper) {
+	ctrl := gomock.NewController(t)
+	repo := backupRepoMock{MockBackupRepo: mocks.NewMockBackupRepo(ctrl), MockTenantRepo: mocks.NewMockTenantRepo(ctrl)}
+	dumper := &fakeDumper{dump: "PGDMP"}
+	return NewBackupService(repo, dumper, storage.NewLocal(t.TempDir()), cfg), repo, dumper
+}
+
+func TestBackupTenantStoresScriptAndRestoresIt(t *testing.T) {
+	svc, repo, dumper := newBackupServiceMock(t, config.BackupConfig{RestoreURL: "postgres://staging/taxifleet"})
+	svc.now = func() time.Time { return time.Date(2026, 10, 15, 3, 0, 0, 0, time.UTC) }
+	ctx := context.Background()
+
+	repo.MockTenantRepo.EXPECT().GetTenantByID(gomock.Any(), uint(5)).Return(&repository.Tenant{ID: 5}, nil)
+	repo.MockBackupRepo.EXPECT().DumpTenant(gomock.Any(), uint(5), gomock.Any()).DoAndReturn(func(ctx context.Context, tenantID uint, w io.Writer) error {
+		_, err := io.WriteString(w, "INSERT INTO tenants (id) VALUES (5);\n")
+		return err
+	})
+
+	backup, err := svc.BackupTenant(ctx, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if backup.Name != "tenant-5-20261015T030000Z.sql" || backup.Format != database.FormatSQL {
+		t.Fatalf("unexpected backup %+v", backup)
+	}
+
+	backups, err := svc.List(ctx)
+	if err != nil || len(backups) != 1 || backups[0].TenantID != 5 || !backups[0].CreatedAt.Equal(svc.now()) {
+		t.Fatalf("expected the backup to be listed, got %+v, %v", backups, err)
+	}
+
+	if err := svc.Restore(ctx, backup.Name); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dumper.target != "postgres://staging/taxifleet" || dumper.format != database.FormatSQL || !strings.HasPrefix(dumper.restored, "INSERT INTO tenants") {
+		t.Fatalf("expected the script to be restored into staging, got %+v", dumper)
+	}
+}
+
+func TestBackupTenantRejectsUnknownTenant(t *testing.T) {
+	svc, repo, _ := newBackupServiceMock(t, config.BackupConfig{})
+	repo.MockTenantRepo.EXPECT().GetTenantByID(gomock.Any(), uint(5)).Return(nil, gorm.ErrRecordNotFound)
+
+	if _, err := svc.BackupTenant(context.Background(), 5); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("expected the unknown tenant to be rejected, got %v", err)
+	}
+	if backups, _ := svc.List(context.Background()); len(backups) != 0 {
+		t.Fatalf("expected nothing stored, got %+v", backups)
+	}
+}
+
+func TestBackupRestoreRequiresStagingDatabase(t *testing.T) {
+	svc, _, _ := newBackupServiceMock(t, config.BackupConfig{})
+	if err := svc.Restore(context.Background(), "database-20261015T030000Z.dump"); !errors.Is(err, ErrRestoreDisabled) {
+		t.Fatalf("expected restoring to be disabled, got %v", err)
+	}
+
+	svc.cfg.RestoreURL = "postgres://staging/taxifleet"
+	for _, name := range []string{"database-20261015T030000Z.dump", "../etc/passwd", "tenant-x-20261015T030000Z.sql"} {
+		if err := svc.Restore(context.Background(), name); !errors.Is(err, ErrBackupNotFound) {
+			t.Errorf("expected %q to be not found, got %v", name, err)
+		}
+	}
+}
+
+func TestBackupScheduledKeepsLatestDatabaseBackups(t *testing.T) {
+	svc, _, _ := newBackupServiceMock(t, config.BackupConfig{Keep: 2})
+	ctx := context.Background()
+	if err := svc.files.Put(ctx, "backups/tenant-5-20261001T030000Z.sql", strings.NewReader(""), "application/octet-stream"); err != nil {
+		t.Fatalf("failed to store tenant backup: %v", err)
+	}
+
+	day := time.Date(2026, 10, 12, 3, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		svc.now = func() time.Time { return day.AddDate(0, 0, i) }
+		if err := svc.Scheduled(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	backups, err := svc.List(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var names []string
+	for _, backup := range backups {
+		names = append(names, backup.Name)
+	}
+	want := "database-20261014T030000Z.dump,database-20261013T030000Z.dump,tenant-5-20261001T030000Z.sql"
+	if strings.Join(names, ",") != want {
+		t.Fatalf("expected %s, got %s", want, strings.Join(names, ","))
+	}
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"taxifleet/backend/internal/cache"
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/validation"
+)
+
+// ErrBankAccountInUse is returned when deleting an account deposits were made into
+var ErrBankAccountInUse = errors.New("deposits were made into this bank account")
+
+type BankAccountService struct {
+	repo  repository.BankAccountRepo
+	cache cache.Cache
+}
+
+func NewBankAccountService(repo repository.BankAccountRepo, cache cache.Cache) *BankAccountService {
+	return &BankAccountService{repo: repo, cache: cache}
+}
+
+type CreateBankAccountRequest struct {
+	Label         string `json:"label" binding:"required"`
+	BankName      string `json:"bank_name"`
+	AccountNumber string `json:"account_number"`
+}
+
+type UpdateBankAccountRequest struct {
+	Label         string `json:"label"`
+	BankName      string `json:"bank_name"`
+	AccountNumber string `json:"account_number"`
+}
+
+// BankAccountDeposits is the number and amount of deposits made into an
+// account
+type BankAccountDeposits struct {
+	Account *repository.BankAccount `json:"account"` // Nil for deposits not linked to an account
+	Count   int                     `json:"count"`
+	Total   money.Amount            `json:"total"`
+}
+
+func (s *BankAccountService) Create(ctx context.Context, tenantID uint, req CreateBankAccountRequest) (*repository.BankAccount, error) {
+	account := &repository.BankAccount{
+		TenantID:      tenantID,
+		Label:         strings.TrimSpace(req.Label),
+		BankName:      req.BankName,
+		AccountNumber: normalizeAccountNumber(req.AccountNumber),
+	}
+	if account.Label == "" {
+		return nil, &validation.FieldError{Field: "label", Rule: "required", Message: "label is required"}
+	}
+
+	if err := s.repo.CreateBankAccount(ctx, account); err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+func (s *BankAccountService) GetByID(ctx context.Context, id uint, tenantID uint) (*repository.BankAccount, error) {
+	account, err := s.repo.GetBankAccountByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if account.TenantID != tenantID {
+		return nil, notFound("bank account not found")
+	}
+
+	return account, nil
+}
+
+func (s *BankAccountService) List(ctx context.Context, tenantID uint) ([]repository.BankAccount, error) {
+	return s.repo.GetBankAccountsByTenant(ctx, tenantID)
+}
+
+func (s *BankAccountService) Update(ctx context.Context, id uint, tenantID uint, req UpdateBankAccountRequest) (*repository.BankAccount, error) {
+	account, err := s.GetByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if label := strings.TrimSpace(req.Label); label != "" {
+		account.Label = label
+	}
+	if req.BankName != "" {
+		account.BankName = req.BankName
+	}
+	if req.AccountNumber != "" {
+		account.AccountNumber = normalizeAccountNumber(req.AccountNumber)
+	}
+
+	if err := s.repo.UpdateBankAccount(ctx, account); err != nil {
+		return nil, err
+	}
+
+	// Cached deposit lists embed the account
+	s.cache.Invalidate(ctx, tenantID)
+
+	return s.repo.GetBankAccountByID(ctx, account.ID)
+}
+
+// Delete removes an account no deposit was made into
+func (s *BankAccountService) Delete(ctx context.Context, id uint, tenantID uint) error {
+	if _, err := s.GetByID(ctx, id, tenantID); err != nil {
+		return err
+	}
+
+	inUse, err := s.repo.BankAccountInUse(ctx, id)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return ErrBankAccountInUse
+	}
+
+	return s.repo.DeleteBankAccount(ctx, id)
+}
+
+// DepositTotals returns the deposits made into each of the tenant's accounts,
+// followed by those not linked to an account if there are any
+func (s *BankAccountService) DepositTotals(ctx context.Context, tenantID uint) ([]BankAccountDeposits, error) {
+	accounts, err := s.repo.GetBankAccountsByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	totals, err := s.repo.GetDepositTotalsByAccount(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	byAccount := make(map[uint]repository.BankAccountTotal, len(totals))
+	var unlinked *repository.BankAccountTotal
+	for i, total := range totals {
+		if total.BankAccountID == nil {
+			unlinked = &totals[i]
+			continue
+		}
+		byAccount[*total.BankAccountID] = total
+	}
+
+	result := make([]BankAccountDeposits, 0, len(accounts)+1)
+	for i := range accounts {
+		total := byAccount[accounts[i].ID]
+		result = append(result, BankAccountDeposits{Account: &accounts[i], Count: total.Count, Total: total.Total})
+	}
+	if unlinked != nil {
+		result = append(result, BankAccountDeposits{Count: unlinked.Count, Total: unlinked.Total})
+	}
+	return result, nil
+}
+
+// normalizeAccountNumber drops the spaces IBANs are usually written with
+func normalizeAccountNumber(number string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(number), " ", ""))
+}
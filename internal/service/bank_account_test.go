@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"taxifleet/backend/internal/cache"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+
+	"go.uber.org/mock/gomock"
+)
+
+func newBankAccountServiceMock(t *testing.T) (*BankAccountService, *mocks.MockBankAccountRepo) {
+	repo := mocks.NewMockBankAccountRepo(gomock.NewController(t))
+	return NewBankAccountService(repo, cache.Noop{}), repo
+}
+
+func TestBankAccountDeleteRefusesAccountInUse(t *testing.T) {
+	svc, repo := newBankAccountServiceMock(t)
+	repo.EXPECT().GetBankAccountByID(gomock.Any(), uint(3)).Return(&repository.BankAccount{ID: 3, TenantID: 1}, nil)
+	repo.EXPECT().BankAccountInUse(gomock.Any(), uint(3)).Return(true, nil)
+
+	if err := svc.Delete(context.Background(), 3, 1); !errors.Is(err, ErrBankAccountInUse) {
+		t.Fatalf("expected ErrBankAccountInUse, got %v", err)
+	}
+}
+
+func TestBankAccountDepositTotals(t *testing.T) {
+	svc, repo := newBankAccountServiceMock(t)
+	accountID := uint(3)
+	repo.EXPECT().GetBankAccountsByTenant(gomock.Any(), uint(1)).Return([]repository.BankAccount{
+		{ID: 2, TenantID: 1, Label: "Savings"},
+		{ID: 3, TenantID: 1, Label: "Main"},
+	}, nil)
+	repo.EXPECT().GetDepositTotalsByAccount(gomock.Any(), uint(1)).Return([]repository.BankAccountTotal{
+		{BankAccountID: &accountID, Count: 2, Total: 900_00},
+		{Count: 1, Total: 150_00},
+	}, nil)
+
+	totals, err := svc.DepositTotals(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(totals) != 3 {
+		t.Fatalf("expected two accounts and the unlinked deposits, got %+v", totals)
+	}
+	if totals[0].Count != 0 || totals[1].Total != 900_00 || totals[2].Account != nil || totals[2].Total != 150_00 {
+		t.Fatalf("unexpected totals %+v", totals)
+	}
+}
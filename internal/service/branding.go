@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"taxifleet/backend/internal/export"
+	"taxifleet/backend/internal/repository"
+)
+
+// maxLogoSize bounds the logo downloaded into each branded export
+const maxLogoSize = 1 << 20
+
+// logoTypes maps the image types both XLSX and PDF support to their extension
+var logoTypes = map[string]string{
+	"image/png":  "png",
+	"image/jpeg": "jpg",
+	"image/gif":  "gif",
+}
+
+// BrandingRepository is the data access BrandingService depends on
+type BrandingRepository interface {
+	repository.TenantRepo
+	repository.UserRepo
+}
+
+// BrandingService applies the tenant's export settings to generated documents
+type BrandingService struct {
+	repo   BrandingRepository
+	client *http.Client
+}
+
+func NewBrandingService(repo BrandingRepository) *BrandingService {
+	return &BrandingService{repo: repo, client: &http.Client{Timeout: 5 * time.Second}}
+}
+
+// Brand adds the tenant's header and footer and the totals rows to a document
+// generated by the user, as enabled in the tenant settings
+func (s *BrandingService) Brand(ctx context.Context, tenantID, userID uint, doc *export.Document) error {
+	tenant, err := s.repo.GetTenantByID(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	settings := parseTenantSettings(tenant.Settings).Exports
+
+	if settings.Totals {
+		for i := range doc.Sections {
+			if doc.Sections[i].Summable && len(doc.Sections[i].Rows) > 0 {
+				doc.Sections[i].AddTotals("Total")
+			}
+		}
+	}
+
+	if settings.Branding {
+		doc.Branding = s.branding(ctx, tenant, userID, settings.Footer)
+	}
+	return nil
+}
+
+// BrandReceipt adds the tenant's header and footer to a receipt, which
+// carries them whether or not the tenant brands its exports
+func (s *BrandingService) BrandReceipt(ctx context.Context, tenantID, userID uint, doc *export.Document) error {
+	tenant, err := s.repo.GetTenantByID(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	doc.Branding = s.branding(ctx, tenant, userID, parseTenantSettings(tenant.Settings).Exports.Footer)
+	return nil
+}
+
+// branding is the tenant's logo and name, and a footer telling who generated
+// the document and when
+func (s *BrandingService) branding(ctx context.Context, tenant *repository.Tenant, userID uint, extraFooter string) *export.Branding {
+	footer := "Generated on " + time.Now().UTC().Format("02/01/2006 15:04") + " UTC"
+	if user, err := s.repo.GetUserByID(ctx, userID); err == nil {
+		footer += " by " + strings.TrimSpace(user.FirstName+" "+user.LastName)
+	}
+	footer += " · " + tenant.Name
+	if extraFooter != "" {
+		footer += " · " + extraFooter
+	}
+
+	branding := &export.Branding{Name: tenant.Name, Footer: footer}
+	// A logo that cannot be loaded leaves the name on its own rather than
+	// failing the export
+	if logo, err := s.loadLogo(ctx, tenant.Logo); err == nil {
+		branding.Logo = logo
+		branding.LogoType = logoTypes[http.DetectContentType(logo)]
+	}
+	return branding
+}
+
+// loadLogo reads the tenant logo from a data URI or an http(s) URL
+func (s *BrandingService) loadLogo(ctx context.Context, logo string) ([]byte, error) {
+	var data []byte
+	switch {
+	case logo == "":
+		return nil, nil
+	case strings.HasPrefix(logo, "data:"):
+		_, encoded, ok := strings.Cut(logo, ";base64,")
+		if !ok {
+			return nil, errors.New("logo data URI is not base64 encoded")
+		}
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, err
+		}
+		data = decoded
+	case strings.HasPrefix(logo, "https://"), strings.HasPrefix(logo, "http://"):
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, logo, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("logo download failed with status %d", resp.StatusCode)
+		}
+		data, err = io.ReadAll(io.LimitReader(resp.Body, maxLogoSize+1))
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported logo location %q", logo)
+	}
+
+	if len(data) > maxLogoSize {
+		return nil, fmt.Errorf("logo is larger than %d bytes", maxLogoSize)
+	}
+	if _, ok := logoTypes[http.DetectContentType(data)]; !ok {
+		return nil, errors.New("logo is not a PNG, JPEG or GIF image")
+	}
+	return data, nil
+}
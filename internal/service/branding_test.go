@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"taxifleet/backend/internal/export"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+	"taxifleet/backend/internal/validation"
+
+	"go.uber.org/mock/gomock"
+)
+
+type brandingRepoMock struct {
+	*mocks.MockTenantRepo
+	*mocks.MockUserRepo
+}
+
+func TestBrandAddsHeaderFooterAndTotals(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := brandingRepoMock{MockTenantRepo: mocks.NewMockTenantRepo(ctrl), MockUserRepo: mocks.NewMockUserRepo(ctrl)}
+	svc := NewBrandingService(repo)
+
+	repo.MockTenantRepo.EXPECT().GetTenantByID(gomock.Any(), uint(1)).Return(&repository.Tenant{
+		ID:       1,
+		Name:     "City Cabs",
+		Settings: `{"exports": {"branding": true, "totals": true, "footer": "Reg. 12345"}}`,
+	}, nil)
+	repo.MockUserRepo.EXPECT().GetUserByID(gomock.Any(), uint(4)).Return(&repository.User{ID: 4, FirstName: "Ana", LastName: "Silva"}, nil)
+
+	doc := export.Document{Sections: []export.Section{
+		{
+			Headers:  []string{"ID", "Category", "Amount"},
+			Rows:     [][]interface{}{{uint(1), "fuel", 40.5}, {uint(2), "repair", 120.0}},
+			Summable: true,
+		},
+		{Rows: [][]interface{}{{"Net profit", 99.0}}},
+	}}
+	if err := svc.Brand(context.Background(), 1, 4, &doc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if doc.Branding == nil || doc.Branding.Name != "City Cabs" || doc.Branding.Logo != nil {
+		t.Fatalf("expected a name-only branding, got %+v", doc.Branding)
+	}
+	if !strings.Contains(doc.Branding.Footer, "by Ana Silva") || !strings.HasSuffix(doc.Branding.Footer, "Reg. 12345") {
+		t.Fatalf("unexpected footer %q", doc.Branding.Footer)
+	}
+	totals := doc.Sections[0].Totals
+	if len(totals) != 3 || totals[0] != "Total" || totals[1] != nil || totals[2] != 160.5 {
+		t.Fatalf("unexpected totals row %v", totals)
+	}
+	if doc.Sections[1].Totals != nil {
+		t.Fatalf("expected no totals on a section that is not summable")
+	}
+}
+
+func TestApplyTemplateSelectsAndLabelsColumns(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := brandingRepoMock{MockTenantRepo: mocks.NewMockTenantRepo(ctrl), MockUserRepo: mocks.NewMockUserRepo(ctrl)}
+	svc := NewBrandingService(repo)
+
+	repo.MockTenantRepo.EXPECT().GetTenantByID(gomock.Any(), uint(1)).Return(&repository.Tenant{
+		ID:       1,
+		Settings: `{"exports": {"templates": {"expenses": {"language": "de", "columns": [{"key": "amount", "label": "Summe"}, {"key": "date"}]}}}}`,
+	}, nil)
+
+	section := export.Section{
+		Keys:    []string{"id", "date", "category", "amount"},
+		Headers: []string{"ID", "Date", "Category", "Amount"},
+		Rows:    [][]interface{}{{uint(1), "01/02/2026", "fuel", 40.5}},
+	}
+	if err := svc.ApplyTemplate(context.Background(), 1, "expenses", &section); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Join(section.Headers, ",") != "Summe,Datum" || strings.Join(section.Keys, ",") != "amount,date" {
+		t.Fatalf("unexpected columns %v %v", section.Keys, section.Headers)
+	}
+	if len(section.Rows[0]) != 2 || section.Rows[0][0] != 40.5 || section.Rows[0][1] != "01/02/2026" {
+		t.Fatalf("unexpected row %v", section.Rows[0])
+	}
+}
+
+func TestCheckTenantSettingsRejectsUnknownExportColumn(t *testing.T) {
+	err := checkTenantSettings(`{"exports": {"templates": {"reports": {"columns": [{"key": "mileage"}]}}}}`)
+	var fieldErr *validation.FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Field != "exports.templates.reports.columns[0].key" || fieldErr.Rule != "enum" {
+		t.Fatalf("expected an exports.templates.reports.columns[0].key field error, got %v", err)
+	}
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"taxifleet/backend/internal/cache"
+	"taxifleet/backend/internal/logging"
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/validation"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrDuplicateBudget is returned when the category already has a budget for
+// the taxi, or for the whole fleet
+var ErrDuplicateBudget = errors.New("a budget for this category and taxi already exists")
+
+// budgetThresholds are the shares of a budget whose crossing is notified,
+// highest first
+var budgetThresholds = []float64{1, 0.8}
+
+// BudgetRepository is the data access BudgetService depends on
+type BudgetRepository interface {
+	repository.BudgetRepo
+	repository.TaxiRepo
+}
+
+// BudgetService manages monthly budgets per expense category and alerts the
+// users managing expenses when spending crosses 80% and 100% of one
+type BudgetService struct {
+	repo          BudgetRepository
+	cache         cache.Cache
+	notifications *NotificationService
+	logger        *logrus.Logger
+}
+
+func NewBudgetService(repo BudgetRepository, cache cache.Cache, notifications *NotificationService, logger *logrus.Logger) *BudgetService {
+	return &BudgetService{repo: repo, cache: cache, notifications: notifications, logger: logger}
+}
+
+// BudgetRequest creates or replaces a budget; without a taxi the budget is
+// for the whole fleet
+type BudgetRequest struct {
+	Category string       `json:"category" binding:"required"`
+	TaxiID   *uint        `json:"taxi_id"`
+	Amount   money.Amount `json:"amount" binding:"required,amount"`
+}
+
+// BudgetStatus is a budget with what was spent against it in a month
+type BudgetStatus struct {
+	repository.Budget
+	Month   string       `json:"month"` // YYYY-MM
+	Spent   money.Amount `json:"spent"`
+	Percent float64      `json:"percent"` // Share of the budget spent, e.g. 85.5
+}
+
+// List returns the tenant's budgets with what was spent against them in the
+// month, YYYY-MM, or the current month
+func (s *BudgetService) List(ctx context.Context, tenantID uint, month string) ([]BudgetStatus, error) {
+	start := startOfMonth(time.Now())
+	if month != "" {
+		var err error
+		if start, err = parseMonth(month); err != nil {
+			return nil, err
+		}
+	}
+	return budgetStatuses(ctx, s.repo, tenantID, start)
+}
+
+func (s *BudgetService) Create(ctx context.Context, tenantID uint, req BudgetRequest) (*repository.Budget, error) {
+	budget := &repository.Budget{TenantID: tenantID}
+	if err := s.applyBudgetRequest(ctx, budget, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateBudget(ctx, budget); err != nil {
+		if repository.IsUniqueViolation(err) {
+			return nil, ErrDuplicateBudget
+		}
+		return nil, err
+	}
+
+	s.cache.Invalidate(ctx, tenantID)
+	return s.repo.GetBudgetByID(ctx, budget.ID)
+}
+
+// Update replaces a budget of the tenant
+func (s *BudgetService) Update(ctx context.Context, tenantID, id uint, req BudgetRequest) (*repository.Budget, error) {
+	budget, err := s.getBudget(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyBudgetRequest(ctx, budget, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateBudget(ctx, budget); err != nil {
+		if repository.IsUniqueViolation(err) {
+			return nil, ErrDuplicateBudget
+		}
+		return nil, err
+	}
+
+	s.cache.Invalidate(ctx, tenantID)
+	return s.repo.GetBudgetByID(ctx, budget.ID)
+}
+
+func (s *BudgetService) Delete(ctx context.Context, tenantID, id uint) error {
+	if _, err := s.getBudget(ctx, tenantID, id); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteBudget(ctx, id); err != nil {
+		return err
+	}
+
+	s.cache.Invalidate(ctx, tenantID)
+	return nil
+}
+
+// Spending returns what was spent against each budget of the tenant in the
+// month of date; AlertCrossed compares it with the spending after a change
+func (s *BudgetService) Spending(ctx context.Context, tenantID uint, date time.Time) (map[uint]money.Amount, error) {
+	start := startOfMonth(date)
+	return s.repo.GetBudgetSpending(ctx, tenantID, start, start.AddDate(0, 1, 0))
+}
+
+// AlertCrossed notifies the budgets of the month of date whose spending
+// crossed a threshold since before was taken. A change crossing both
+// thresholds at once is notified once, for the highest. Failures are only
+// logged since the expense itself was saved.
+func (s *BudgetService) AlertCrossed(ctx context.Context, tenantID uint, date time.Time, before map[uint]money.Amount) {
+	if err := s.alertCrossed(ctx, tenantID, date, before); err != nil {
+		logging.Entry(ctx, s.logger).WithError(err).WithField("tenant_id", tenantID).Error("Failed to check budget thresholds")
+	}
+}
+
+func (s *BudgetService) alertCrossed(ctx context.Context, tenantID uint, date time.Time, before map[uint]money.Amount) error {
+	after, err := s.Spending(ctx, tenantID, date)
+	if err != nil || len(after) == 0 {
+		return err
+	}
+	budgets, err := s.repo.GetBudgets(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+
+	for i := range budgets {
+		budget := &budgets[i]
+		for _, threshold := range budgetThresholds {
+			limit := budget.Amount.Mul(threshold)
+			if before[budget.ID] < limit && after[budget.ID] >= limit {
+				s.notifications.NotifyBudgetThreshold(ctx, budget, startOfMonth(date), int(threshold*100), after[budget.ID])
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func (s *BudgetService) getBudget(ctx context.Context, tenantID, id uint) (*repository.Budget, error) {
+	budget, err := s.repo.GetBudgetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if budget.TenantID != tenantID {
+		return nil, notFound("budget not found")
+	}
+	return budget, nil
+}
+
+func (s *BudgetService) applyBudgetRequest(ctx context.Context, budget *repository.Budget, req BudgetRequest) error {
+	if !slices.Contains(expenseCategories, req.Category) {
+		return &validation.FieldError{Field: "category", Rule: "oneof", Message: fmt.Sprintf("unknown category %q", req.Category)}
+	}
+	if req.TaxiID != nil {
+		taxi, err := s.repo.GetTaxiByID(ctx, *req.TaxiID)
+		if err != nil || taxi.TenantID != budget.TenantID {
+			return notFound("taxi not found")
+		}
+	}
+
+	budget.Category = req.Category
+	budget.TaxiID = req.TaxiID
+	budget.Amount = req.Amount
+	return nil
+}
+
+// budgetStatuses returns the tenant's budgets with their spending in the
+// month starting on month
+func budgetStatuses(ctx context.Context, repo repository.BudgetRepo, tenantID uint, month time.Time) ([]BudgetStatus, error) {
+	budgets, err := repo.GetBudgets(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	spending, err := repo.GetBudgetSpending(ctx, tenantID, month, month.AddDate(0, 1, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]BudgetStatus, len(budgets))
+	for i, budget := range budgets {
+		spent := spending[budget.ID]
+		statuses[i] = BudgetStatus{
+			Budget:  budget,
+			Month:   month.Format("2006-01"),
+			Spent:   spent,
+			Percent: math.Round(spent.Float()/budget.Amount.Float()*1000) / 10,
+		}
+	}
+	return statuses, nil
+}
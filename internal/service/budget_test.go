@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"taxifleet/backend/internal/cache"
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/push"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+	"taxifleet/backend/internal/validation"
+
+	"github.com/sirupsen/logrus"
+	"go.uber.org/mock/gomock"
+)
+
+type budgetRepoMock struct {
+	*mocks.MockBudgetRepo
+	*mocks.MockTaxiRepo
+}
+
+func newBudgetServiceMock(t *testing.T) (*BudgetService, budgetRepoMock, notificationRepoMock) {
+	ctrl := gomock.NewController(t)
+	repo := budgetRepoMock{
+		MockBudgetRepo: mocks.NewMockBudgetRepo(ctrl),
+		MockTaxiRepo:   mocks.NewMockTaxiRepo(ctrl),
+	}
+	notificationRepo := notificationRepoMock{
+		MockDeviceTokenRepo: mocks.NewMockDeviceTokenRepo(ctrl),
+		MockInboxRepo:       mocks.NewMockInboxRepo(ctrl),
+		MockUserRepo:        mocks.NewMockUserRepo(ctrl),
+		MockTaxiRepo:        mocks.NewMockTaxiRepo(ctrl),
+	}
+	notifications := NewNotificationService(notificationRepo, push.NoopSender{}, logrus.New())
+	return NewBudgetService(repo, cache.Noop{}, notifications, logrus.New()), repo, notificationRepo
+}
+
+func TestCreateBudgetValidates(t *testing.T) {
+	svc, repo, _ := newBudgetServiceMock(t)
+	taxiID := uint(8)
+	repo.MockTaxiRepo.EXPECT().GetTaxiByID(gomock.Any(), taxiID).Return(&repository.Taxi{ID: 8, TenantID: 2}, nil)
+
+	var fieldErr *validation.FieldError
+	if _, err := svc.Create(context.Background(), 1, BudgetRequest{Category: "snacks", Amount: 100_00}); !errors.As(err, &fieldErr) {
+		t.Fatalf("expected a category validation error, got %v", err)
+	}
+	if _, err := svc.Create(context.Background(), 1, BudgetRequest{Category: "fuel", TaxiID: &taxiID, Amount: 100_00}); err == nil {
+		t.Fatal("expected another tenant's taxi to be refused")
+	}
+}
+
+func TestBudgetAlertsOnceForTheHighestThresholdCrossed(t *testing.T) {
+	svc, repo, notificationRepo := newBudgetServiceMock(t)
+	repo.MockBudgetRepo.EXPECT().GetBudgetSpending(gomock.Any(), uint(1), time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)).
+		Return(map[uint]money.Amount{1: 520_00, 2: 60_00}, nil)
+	repo.MockBudgetRepo.EXPECT().GetBudgets(gomock.Any(), uint(1)).Return([]repository.Budget{
+		{ID: 1, TenantID: 1, Category: "fuel", Amount: 500_00},
+		{ID: 2, TenantID: 1, Category: "repair", Amount: 100_00},
+	}, nil)
+	// Only the fuel budget crossed a threshold: from 70% to 104%
+	notificationRepo.MockUserRepo.EXPECT().GetUsersByTenant(gomock.Any(), uint(1)).Return(nil, nil).Times(1)
+
+	svc.AlertCrossed(context.Background(), 1, time.Date(2024, 5, 14, 0, 0, 0, 0, time.UTC), map[uint]money.Amount{1: 350_00, 2: 50_00})
+}
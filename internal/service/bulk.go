@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/validation"
+)
+
+// maxBulkItems is the most records one bulk request may name
+const maxBulkItems = 100
+
+// BulkRequest names the records of a bulk operation
+type BulkRequest struct {
+	IDs []uint `json:"ids" binding:"required"`
+}
+
+// BulkResult is the outcome of a bulk operation. It is applied only when
+// every item succeeded; otherwise nothing changed and the failed items say
+// why.
+type BulkResult struct {
+	Applied bool             `json:"applied"`
+	Items   []BulkItemResult `json:"items"`
+}
+
+// BulkItemResult is the outcome of one item of a bulk operation
+type BulkItemResult struct {
+	ID    uint   `json:"id"`
+	Error string `json:"error,omitempty"`
+}
+
+// errBulkFailed rolls back a bulk operation an item of which failed
+var errBulkFailed = errors.New("bulk operation failed")
+
+// runBulk applies fn to every ID in one transaction, which it rolls back if
+// any of them fails. Each item runs in a savepoint of its own, so a failed
+// statement doesn't keep the rest from being tried.
+func runBulk(ctx context.Context, db repository.Transactor, ids []uint, fn func(ctx context.Context, id uint) error) (*BulkResult, error) {
+	if len(ids) == 0 || len(ids) > maxBulkItems {
+		return nil, &validation.FieldError{Field: "ids", Rule: "bulk_size", Message: fmt.Sprintf("ids must name 1 to %d records", maxBulkItems)}
+	}
+	seen := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return nil, &validation.FieldError{Field: "ids", Rule: "unique", Message: fmt.Sprintf("id %d is named twice", id)}
+		}
+		seen[id] = true
+	}
+
+	result := &BulkResult{Items: make([]BulkItemResult, len(ids))}
+	err := db.InTransaction(ctx, func(ctx context.Context) error {
+		failed := false
+		for i, id := range ids {
+			result.Items[i].ID = id
+			if err := db.InTransaction(ctx, func(ctx context.Context) error { return fn(ctx, id) }); err != nil {
+				result.Items[i].Error = err.Error()
+				failed = true
+			}
+		}
+		if failed {
+			return errBulkFailed
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBulkFailed) {
+		return nil, err
+	}
+	result.Applied = err == nil
+	return result, nil
+}
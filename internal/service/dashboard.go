@@ -1,85 +1,235 @@
 package service
 
 import (
+	"context"
+	"fmt"
+	"time"
+
+	"taxifleet/backend/internal/cache"
+	"taxifleet/backend/internal/money"
 	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/tracing"
 )
 
+// DashboardRepository is the data access DashboardService depends on
+type DashboardRepository interface {
+	repository.TaxiRepo
+	repository.ReportRepo
+	repository.ExpenseRepo
+	repository.DepositRepo
+	repository.TenantRepo
+	repository.AnalyticsRepo
+	repository.BudgetRepo
+}
+
 type DashboardService struct {
-	repo *repository.Repository
+	repo  DashboardRepository
+	cache cache.Cache
 }
 
-func NewDashboardService(repo *repository.Repository) *DashboardService {
-	return &DashboardService{repo: repo}
+func NewDashboardService(repo DashboardRepository, cache cache.Cache) *DashboardService {
+	return &DashboardService{repo: repo, cache: cache}
 }
 
 type DashboardStats struct {
-	TotalTaxis     int     `json:"total_taxis"`
-	ActiveDrivers  int     `json:"active_drivers"`
-	PendingReports int     `json:"pending_reports"`
-	TotalRevenue   float64 `json:"total_revenue"`
-	TotalExpenses  float64 `json:"total_expenses"`
-	NetRevenue     float64 `json:"net_revenue"`
+	TotalTaxis       int          `json:"total_taxis"`
+	ActiveDrivers    int          `json:"active_drivers"`
+	PendingReports   int          `json:"pending_reports"`
+	TotalRevenue     money.Amount `json:"total_revenue"`
+	TotalExpenses    money.Amount `json:"total_expenses"`
+	TotalAdjustments money.Amount `json:"total_adjustments"` // Bonuses less penalties paid to drivers
+	NetRevenue       money.Amount `json:"net_revenue"`
+
+	// Deposits no owner has checked against their proof yet
+	UnverifiedDeposits      int          `json:"unverified_deposits"`
+	UnverifiedDepositAmount money.Amount `json:"unverified_deposit_amount"`
+
+	// Budget vs actual spending of the current month
+	Budgets []BudgetStatus `json:"budgets"`
+}
+
+func (s *DashboardService) GetStats(ctx context.Context, tenantID uint) (_ *DashboardStats, err error) {
+	ctx, span := tracing.Start(ctx, "DashboardService.GetStats")
+	defer func() { tracing.End(span, err) }()
+
+	return cache.Remember(ctx, s.cache, tenantID, "dashboard:stats", func() (*DashboardStats, error) {
+		return s.loadStats(ctx, tenantID)
+	})
 }
 
-func (s *DashboardService) GetStats(tenantID uint) (*DashboardStats, error) {
-	// Get all taxis for tenant
-	taxis, err := s.repo.GetTaxisByTenant(tenantID)
+func (s *DashboardService) loadStats(ctx context.Context, tenantID uint) (*DashboardStats, error) {
+	totals, err := s.repo.GetDashboardTotals(ctx, tenantID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Count total taxis
-	totalTaxis := len(taxis)
+	// Calculate net revenue (total revenue - total expenses - bonuses + penalties)
+	netRevenue := totals.TotalRevenue - totals.TotalExpenses - totals.TotalAdjustments
 
-	// Count active drivers (taxis with assigned drivers and status active)
-	activeDriversMap := make(map[uint]bool)
-	for _, taxi := range taxis {
-		if taxi.Status == "active" && taxi.AssignedDriverID != nil {
-			activeDriversMap[*taxi.AssignedDriverID] = true
-		}
+	budgets, err := budgetStatuses(ctx, s.repo, tenantID, startOfMonth(time.Now()))
+	if err != nil {
+		return nil, err
 	}
-	activeDrivers := len(activeDriversMap)
 
-	// Get all reports for tenant
-	reports, err := s.repo.GetReportsByTenant(tenantID)
+	return &DashboardStats{
+		TotalTaxis:       totals.TotalTaxis,
+		ActiveDrivers:    totals.ActiveDrivers,
+		PendingReports:   totals.PendingReports,
+		TotalRevenue:     totals.TotalRevenue,
+		TotalExpenses:    totals.TotalExpenses,
+		TotalAdjustments: totals.TotalAdjustments,
+		NetRevenue:       netRevenue,
+
+		UnverifiedDeposits:      totals.UnverifiedDeposits,
+		UnverifiedDepositAmount: totals.UnverifiedDepositAmount,
+
+		Budgets: budgets,
+	}, nil
+}
+
+type TimeSeriesPoint struct {
+	Bucket string       `json:"bucket"` // First day of the bucket, YYYY-MM-DD
+	Value  money.Amount `json:"value"`
+}
+
+type TimeSeries struct {
+	Metric   string            `json:"metric"`
+	Interval string            `json:"interval"`
+	From     string            `json:"from"`
+	To       string            `json:"to"`
+	Points   []TimeSeriesPoint `json:"points"`
+}
+
+// bucketStart returns the first day of the interval bucket containing the date.
+// Weekly buckets follow the tenant's reporting week.
+func bucketStart(date time.Time, interval string, weekStart time.Weekday) time.Time {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	switch interval {
+	case "week":
+		return startOfWeek(day, weekStart)
+	case "month":
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+func nextBucket(start time.Time, interval string) time.Time {
+	switch interval {
+	case "week":
+		return start.AddDate(0, 0, 7)
+	case "month":
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// GetTimeSeries returns revenue, expenses or net bucketed per day, week or
+// month. Empty buckets are included with a zero value so charts have no gaps.
+func (s *DashboardService) GetTimeSeries(ctx context.Context, tenantID uint, metric, interval, from, to string) (_ *TimeSeries, err error) {
+	ctx, span := tracing.Start(ctx, "DashboardService.GetTimeSeries")
+	defer func() { tracing.End(span, err) }()
+
+	key := fmt.Sprintf("dashboard:timeseries:%s:%s:%s:%s", metric, interval, from, to)
+	if to == "" {
+		// Open-ended series move with the current date
+		key += ":" + time.Now().Format("2006-01-02")
+	}
+	return cache.Remember(ctx, s.cache, tenantID, key, func() (*TimeSeries, error) {
+		return s.loadTimeSeries(ctx, tenantID, metric, interval, from, to)
+	})
+}
+
+func (s *DashboardService) loadTimeSeries(ctx context.Context, tenantID uint, metric, interval, from, to string) (*TimeSeries, error) {
+	if metric == "" {
+		metric = "revenue"
+	}
+	if metric != "revenue" && metric != "expenses" && metric != "net" {
+		return nil, invalid("metric must be revenue, expenses or net")
+	}
+	if interval == "" {
+		interval = "week"
+	}
+	if interval != "day" && interval != "week" && interval != "month" {
+		return nil, invalid("interval must be day, week or month")
+	}
+
+	tenant, err := s.repo.GetTenantByID(ctx, tenantID)
 	if err != nil {
-		return nil, err
+		return nil, notFound("tenant not found")
 	}
+	weekStart := parseTenantSettings(tenant.Settings).WeekStart()
 
-	// Count pending reports (draft status)
-	pendingReports := 0
-	totalRevenue := 0.0
-	for _, report := range reports {
-		if report.Status == "draft" {
-			pendingReports++
-		}
-		// Sum earnings from approved reports only
-		if report.Status == "approved" {
-			totalRevenue += report.Earnings
+	end := time.Now()
+	if to != "" {
+		end, err = time.Parse("2006-01-02", to)
+		if err != nil {
+			return nil, invalid("invalid to date format")
 		}
 	}
+	last := bucketStart(end, interval, weekStart)
 
-	// Get all expenses for tenant
-	expenses, err := s.repo.GetExpensesByTenant(tenantID)
-	if err != nil {
-		return nil, err
+	// Default to 30 days, 12 weeks or 12 months depending on the interval
+	var first time.Time
+	if from != "" {
+		start, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			return nil, invalid("invalid from date format")
+		}
+		first = bucketStart(start, interval, weekStart)
+	} else {
+		switch interval {
+		case "week":
+			first = last.AddDate(0, 0, -11*7)
+		case "month":
+			first = last.AddDate(0, -11, 0)
+		default:
+			first = last.AddDate(0, 0, -29)
+		}
 	}
+	if first.After(last) {
+		return nil, invalid("from must be before to")
+	}
+	until := nextBucket(last, interval)
 
-	// Sum total expenses
-	totalExpenses := 0.0
-	for _, expense := range expenses {
-		totalExpenses += expense.Amount
+	values := make(map[time.Time]money.Amount)
+	if metric == "revenue" || metric == "net" {
+		earnings, err := s.repo.GetDailyApprovedEarnings(ctx, tenantID, first, until)
+		if err != nil {
+			return nil, err
+		}
+		for _, amount := range earnings {
+			values[bucketStart(amount.Day, interval, weekStart)] += amount.Amount
+		}
+	}
+	if metric == "expenses" || metric == "net" {
+		expenses, err := s.repo.GetDailyExpenses(ctx, tenantID, first, until)
+		if err != nil {
+			return nil, err
+		}
+		sign := money.Amount(1)
+		if metric == "net" {
+			sign = -1
+		}
+		for _, amount := range expenses {
+			values[bucketStart(amount.Day, interval, weekStart)] += sign * amount.Amount
+		}
 	}
 
-	// Calculate net revenue (total revenue - total expenses)
-	netRevenue := totalRevenue - totalExpenses
+	series := &TimeSeries{
+		Metric:   metric,
+		Interval: interval,
+		From:     first.Format("2006-01-02"),
+		To:       until.AddDate(0, 0, -1).Format("2006-01-02"),
+		Points:   []TimeSeriesPoint{},
+	}
+	for bucket := first; bucket.Before(until); bucket = nextBucket(bucket, interval) {
+		series.Points = append(series.Points, TimeSeriesPoint{
+			Bucket: bucket.Format("2006-01-02"),
+			Value:  values[bucket],
+		})
+	}
 
-	return &DashboardStats{
-		TotalTaxis:     totalTaxis,
-		ActiveDrivers:  activeDrivers,
-		PendingReports: pendingReports,
-		TotalRevenue:   totalRevenue,
-		TotalExpenses:  totalExpenses,
-		NetRevenue:     netRevenue,
-	}, nil
+	return series, nil
 }
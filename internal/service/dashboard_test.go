@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"taxifleet/backend/internal/cache"
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+
+	"go.uber.org/mock/gomock"
+)
+
+type dashboardRepoMock struct {
+	*mocks.MockTaxiRepo
+	*mocks.MockReportRepo
+	*mocks.MockExpenseRepo
+	*mocks.MockDepositRepo
+	*mocks.MockTenantRepo
+	*mocks.MockAnalyticsRepo
+	*mocks.MockBudgetRepo
+}
+
+func TestDashboardStatsFromTotals(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := dashboardRepoMock{
+		MockTaxiRepo:      mocks.NewMockTaxiRepo(ctrl),
+		MockReportRepo:    mocks.NewMockReportRepo(ctrl),
+		MockExpenseRepo:   mocks.NewMockExpenseRepo(ctrl),
+		MockDepositRepo:   mocks.NewMockDepositRepo(ctrl),
+		MockTenantRepo:    mocks.NewMockTenantRepo(ctrl),
+		MockAnalyticsRepo: mocks.NewMockAnalyticsRepo(ctrl),
+		MockBudgetRepo:    mocks.NewMockBudgetRepo(ctrl),
+	}
+	svc := NewDashboardService(repo, cache.Noop{})
+
+	// The stats come from one aggregate query, not from the tenant's records
+	repo.MockAnalyticsRepo.EXPECT().GetDashboardTotals(gomock.Any(), uint(1)).Return(&repository.DashboardTotals{
+		TotalTaxis: 3, ActiveDrivers: 2, PendingReports: 1,
+		TotalRevenue: 1000_00, TotalAdjustments: 50_00, TotalExpenses: 200_00,
+		UnverifiedDeposits: 1, UnverifiedDepositAmount: 300_00,
+	}, nil)
+	repo.MockBudgetRepo.EXPECT().GetBudgets(gomock.Any(), uint(1)).Return([]repository.Budget{{ID: 4, TenantID: 1, Category: "fuel", Amount: 400_00}}, nil)
+	repo.MockBudgetRepo.EXPECT().GetBudgetSpending(gomock.Any(), uint(1), gomock.Any(), gomock.Any()).Return(map[uint]money.Amount{4: 342_00}, nil)
+
+	stats, err := svc.GetStats(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.TotalTaxis != 3 || stats.ActiveDrivers != 2 || stats.UnverifiedDepositAmount != 300_00 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if stats.NetRevenue != 750_00 {
+		t.Errorf("expected a net revenue of 750.00, got %s", stats.NetRevenue)
+	}
+	if len(stats.Budgets) != 1 || stats.Budgets[0].Spent != 342_00 || stats.Budgets[0].Percent != 85.5 {
+		t.Errorf("unexpected budgets %+v", stats.Budgets)
+	}
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"taxifleet/backend/internal/permissions"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/validation"
+)
+
+// maxDelegationDays bounds how long a delegation may last
+const maxDelegationDays = 90
+
+// ErrNotDelegator is returned when someone other than an owner manages
+// approval delegations
+var ErrNotDelegator = forbidden("only owners can delegate their approval rights")
+
+// DelegationRepository is the data access DelegationService depends on
+type DelegationRepository interface {
+	repository.Transactor
+	repository.DelegationRepo
+	repository.UserRepo
+	repository.AuditRepo
+}
+
+// DelegationService lets owners grant other users their approval rights for
+// a date range, e.g. a manager while they travel. Grants and revocations are
+// audited.
+type DelegationService struct {
+	repo DelegationRepository
+}
+
+func NewDelegationService(repo DelegationRepository) *DelegationService {
+	return &DelegationService{repo: repo}
+}
+
+type CreateDelegationRequest struct {
+	DelegateID uint   `json:"delegate_id" binding:"required"`
+	StartsOn   string `json:"starts_on" binding:"required"` // YYYY-MM-DD
+	EndsOn     string `json:"ends_on" binding:"required"`   // YYYY-MM-DD, inclusive
+	Reason     string `json:"reason"`
+}
+
+func (s *DelegationService) List(ctx context.Context, tenantID uint, permission int) ([]repository.ApprovalDelegation, error) {
+	if !hasOwnerRights(permission) {
+		return nil, ErrNotDelegator
+	}
+	return s.repo.GetDelegations(ctx, tenantID)
+}
+
+// Create grants the delegate the owner's approval rights from the start date
+// through the end date
+func (s *DelegationService) Create(ctx context.Context, tenantID, userID uint, permission int, req CreateDelegationRequest) (*repository.ApprovalDelegation, error) {
+	if !hasOwnerRights(permission) {
+		return nil, ErrNotDelegator
+	}
+
+	startsOn, err := time.Parse("2006-01-02", req.StartsOn)
+	if err != nil {
+		return nil, &validation.FieldError{Field: "starts_on", Rule: "date", Message: "starts_on must be a date (YYYY-MM-DD)"}
+	}
+	endsOn, err := time.Parse("2006-01-02", req.EndsOn)
+	if err != nil {
+		return nil, &validation.FieldError{Field: "ends_on", Rule: "date", Message: "ends_on must be a date (YYYY-MM-DD)"}
+	}
+	switch {
+	case endsOn.Before(startsOn):
+		return nil, &validation.FieldError{Field: "ends_on", Rule: "gtefield", Message: "ends_on must not be before starts_on"}
+	case endsOn.Before(today()):
+		return nil, &validation.FieldError{Field: "ends_on", Rule: "future", Message: "ends_on must not be in the past"}
+	case endsOn.Sub(startsOn) >= maxDelegationDays*24*time.Hour:
+		return nil, &validation.FieldError{Field: "ends_on", Rule: "max", Message: fmt.Sprintf("a delegation lasts at most %d days", maxDelegationDays)}
+	}
+
+	delegate, err := s.repo.GetUserByID(ctx, req.DelegateID)
+	if err != nil || delegate.TenantID != tenantID {
+		return nil, notFound("user not found")
+	}
+	if delegate.ID == userID || !delegate.Active || !permissions.HasPermission(delegate.Permission, permissions.PermissionEditReports) {
+		return nil, &validation.FieldError{Field: "delegate_id", Rule: "delegate", Message: "approval rights can only be delegated to another active user who can edit reports"}
+	}
+
+	delegation := &repository.ApprovalDelegation{
+		TenantID:    tenantID,
+		DelegatorID: userID,
+		DelegateID:  delegate.ID,
+		StartsOn:    startsOn,
+		EndsOn:      endsOn,
+		Reason:      req.Reason,
+	}
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateDelegation(ctx, delegation); err != nil {
+			return err
+		}
+		return s.repo.CreateAuditEvent(ctx, auditEvent(tenantID, userID, ActionDelegationGranted, "delegation", delegation.ID, delegationSummary(delegation, delegate, "granted")))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetDelegationByID(ctx, delegation.ID)
+}
+
+// Revoke ends a delegation of the tenant before its end date
+func (s *DelegationService) Revoke(ctx context.Context, tenantID, userID uint, permission int, id uint) error {
+	if !hasOwnerRights(permission) {
+		return ErrNotDelegator
+	}
+	delegation, err := s.repo.GetDelegationByID(ctx, id)
+	if err != nil || delegation.TenantID != tenantID {
+		return notFound("delegation not found")
+	}
+	if delegation.RevokedAt != nil {
+		return nil
+	}
+
+	return s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.RevokeDelegation(ctx, id); err != nil {
+			return err
+		}
+		return s.repo.CreateAuditEvent(ctx, auditEvent(tenantID, userID, ActionDelegationRevoked, "delegation", id, delegationSummary(delegation, &delegation.Delegate, "revoked")))
+	})
+}
+
+// hasOwnerRights tells owners and admins, whose masks hold every owner
+// permission, apart from users who share only some of them
+func hasOwnerRights(permission int) bool {
+	return permission&permissions.PermissionOwner == permissions.PermissionOwner
+}
+
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// delegationSummary describes a delegation for the activity feed, e.g.
+// "Approval rights delegated to Jane Doe from 03/06/2024 to 14/06/2024 granted"
+func delegationSummary(delegation *repository.ApprovalDelegation, delegate *repository.User, status string) string {
+	name := strings.TrimSpace(delegate.FirstName + " " + delegate.LastName)
+	return fmt.Sprintf("Approval rights delegated to %s from %s to %s %s", name,
+		delegation.StartsOn.Format("02/01/2006"), delegation.EndsOn.Format("02/01/2006"), status)
+}
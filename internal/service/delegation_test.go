@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"taxifleet/backend/internal/permissions"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+	"taxifleet/backend/internal/validation"
+
+	"go.uber.org/mock/gomock"
+)
+
+type delegationRepoMock struct {
+	*mocks.MockTransactor
+	*mocks.MockDelegationRepo
+	*mocks.MockUserRepo
+	*mocks.MockAuditRepo
+}
+
+func newDelegationServiceMock(t *testing.T) (*DelegationService, delegationRepoMock) {
+	ctrl := gomock.NewController(t)
+	repo := delegationRepoMock{
+		MockTransactor:     mocks.NewMockTransactor(ctrl),
+		MockDelegationRepo: mocks.NewMockDelegationRepo(ctrl),
+		MockUserRepo:       mocks.NewMockUserRepo(ctrl),
+		MockAuditRepo:      mocks.NewMockAuditRepo(ctrl),
+	}
+	repo.MockTransactor.EXPECT().InTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}).AnyTimes()
+	return NewDelegationService(repo), repo
+}
+
+func TestCreateDelegation(t *testing.T) {
+	svc, repo := newDelegationServiceMock(t)
+	repo.MockUserRepo.EXPECT().GetUserByID(gomock.Any(), uint(5)).Return(&repository.User{ID: 5, TenantID: 1, Active: true, Permission: permissions.PermissionManager}, nil).AnyTimes()
+	repo.MockUserRepo.EXPECT().GetUserByID(gomock.Any(), uint(4)).Return(&repository.User{ID: 4, TenantID: 1, Active: true, Permission: permissions.PermissionDriver}, nil)
+
+	start := time.Now().Format("2006-01-02")
+	end := time.Now().AddDate(0, 0, 14).Format("2006-01-02")
+	if _, err := svc.Create(context.Background(), 1, 5, permissions.PermissionManager, CreateDelegationRequest{DelegateID: 5, StartsOn: start, EndsOn: end}); !errors.Is(err, ErrNotDelegator) {
+		t.Fatalf("expected ErrNotDelegator for a manager, got %v", err)
+	}
+
+	invalid := []CreateDelegationRequest{
+		{DelegateID: 5, StartsOn: "14/06/2024", EndsOn: end},
+		{DelegateID: 5, StartsOn: end, EndsOn: start},
+		{DelegateID: 5, StartsOn: "2020-01-01", EndsOn: "2020-01-10"},
+		{DelegateID: 5, StartsOn: start, EndsOn: time.Now().AddDate(0, 0, 120).Format("2006-01-02")},
+		{DelegateID: 4, StartsOn: start, EndsOn: end},
+	}
+	for _, req := range invalid {
+		var fieldErr *validation.FieldError
+		if _, err := svc.Create(context.Background(), 1, 9, permissions.PermissionOwner, req); !errors.As(err, &fieldErr) {
+			t.Errorf("expected a validation error for %+v, got %v", req, err)
+		}
+	}
+
+	repo.MockDelegationRepo.EXPECT().CreateDelegation(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, delegation *repository.ApprovalDelegation) error {
+		if delegation.DelegatorID != 9 || delegation.DelegateID != 5 || delegation.TenantID != 1 {
+			t.Errorf("unexpected delegation %+v", delegation)
+		}
+		delegation.ID = 2
+		return nil
+	})
+	repo.MockAuditRepo.EXPECT().CreateAuditEvent(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, event *repository.AuditEvent) error {
+		if event.Action != ActionDelegationGranted || event.EntityID != 2 {
+			t.Errorf("unexpected audit event %+v", event)
+		}
+		return nil
+	})
+	repo.MockDelegationRepo.EXPECT().GetDelegationByID(gomock.Any(), uint(2)).Return(&repository.ApprovalDelegation{ID: 2, TenantID: 1}, nil)
+	if _, err := svc.Create(context.Background(), 1, 9, permissions.PermissionOwner, CreateDelegationRequest{DelegateID: 5, StartsOn: start, EndsOn: end}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRevokeDelegation(t *testing.T) {
+	svc, repo := newDelegationServiceMock(t)
+	repo.MockDelegationRepo.EXPECT().GetDelegationByID(gomock.Any(), uint(2)).Return(&repository.ApprovalDelegation{ID: 2, TenantID: 1, DelegateID: 5}, nil).Times(2)
+
+	if err := svc.Revoke(context.Background(), 2, 9, permissions.PermissionOwner, 2); err == nil {
+		t.Fatal("expected another tenant's delegation to be refused")
+	}
+	repo.MockDelegationRepo.EXPECT().RevokeDelegation(gomock.Any(), uint(2)).Return(nil)
+	repo.MockAuditRepo.EXPECT().CreateAuditEvent(gomock.Any(), gomock.Any()).Return(nil)
+	if err := svc.Revoke(context.Background(), 1, 9, permissions.PermissionOwner, 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,345 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/permissions"
+	"taxifleet/backend/internal/repository"
+)
+
+// DemoRepository is the data access DemoService depends on
+type DemoRepository interface {
+	repository.Transactor
+	repository.TenantRepo
+	repository.TaxiRepo
+	repository.DemoRepo
+}
+
+// DemoService fills a tenant with synthetic drivers, taxis and months of
+// reports, expenses and deposits for sales demos and load tests
+type DemoService struct {
+	repo DemoRepository
+}
+
+func NewDemoService(repo DemoRepository) *DemoService {
+	return &DemoService{repo: repo}
+}
+
+// DemoRequest sizes the generated data. The same seed generates the same
+// figures, so a demo can be rebuilt the same way on another tenant.
+type DemoRequest struct {
+	Taxis    int    `json:"taxis" binding:"omitempty,min=1,max=200"`         // One driver each; defaults to 8
+	Weeks    int    `json:"weeks" binding:"omitempty,min=1,max=104"`         // Defaults to 26
+	Seed     int64  `json:"seed"`                                            // Random unless set
+	Password string `json:"password" binding:"omitempty,min=6" sanitize:"-"` // Of the drivers; defaults to demo1234
+}
+
+// DemoSummary counts what was generated
+type DemoSummary struct {
+	TenantID       uint     `json:"tenant_id"`
+	Seed           int64    `json:"seed"`
+	Drivers        int      `json:"drivers"`
+	Taxis          int      `json:"taxis"`
+	Reports        int      `json:"reports"`
+	Expenses       int      `json:"expenses"`
+	Deposits       int      `json:"deposits"`
+	DriverEmails   []string `json:"driver_emails"`
+	DriverPassword string   `json:"driver_password"`
+}
+
+var (
+	demoFirstNames = []string{"Kouadio", "Awa", "Yao", "Fatou", "Moussa", "Aya", "Ibrahim", "Mariam", "Koffi", "Aminata", "Seydou", "Adjoua"}
+	demoLastNames  = []string{"Kone", "Traore", "Ouattara", "Diallo", "Bamba", "Coulibaly", "Kouassi", "Toure", "Yao", "Sangare"}
+	demoModels     = []string{"Toyota Corolla", "Toyota Yaris", "Hyundai Elantra", "Kia Rio", "Suzuki Dzire", "Renault Logan", "Peugeot 301"}
+	demoColors     = []string{"Orange", "Yellow", "White", "Silver"}
+)
+
+// demoPlates make plates in the national formats of validation.platePatterns;
+// other countries get the French format, which passes the generic check
+var demoPlates = map[string]func(rng *rand.Rand) string{
+	"CI": func(rng *rand.Rand) string {
+		return fmt.Sprintf("%d %s %02d", 1000+rng.Intn(9000), demoLetters(rng, 2), 1+rng.Intn(99))
+	},
+	"SN": func(rng *rand.Rand) string {
+		return fmt.Sprintf("DK-%04d-%s", rng.Intn(10000), demoLetters(rng, 2))
+	},
+	"NG": func(rng *rand.Rand) string {
+		return fmt.Sprintf("%s-%03d%s", demoLetters(rng, 3), rng.Intn(1000), demoLetters(rng, 2))
+	},
+	"DE": func(rng *rand.Rand) string {
+		return fmt.Sprintf("B-%s %d", demoLetters(rng, 2), 1+rng.Intn(9999))
+	},
+	"GB": func(rng *rand.Rand) string {
+		return fmt.Sprintf("%s%02d %s", demoLetters(rng, 2), rng.Intn(100), demoLetters(rng, 3))
+	},
+	"FR": func(rng *rand.Rand) string {
+		return fmt.Sprintf("%s-%03d-%s", demoLetters(rng, 2), rng.Intn(1000), demoLetters(rng, 2))
+	},
+}
+
+func demoLetters(rng *rand.Rand, n int) string {
+	const letters = "ABCDEFGHJKLMNPRSTUVWXYZ"
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = letters[rng.Intn(len(letters))]
+	}
+	return string(b)
+}
+
+// demoAmount is a random amount in [min, max) major units
+func demoAmount(rng *rand.Rand, min, max float64) money.Amount {
+	return money.FromFloat(min + rng.Float64()*(max-min))
+}
+
+// Generate adds the demo data to the tenant in one transaction: a driver and
+// a taxi per requested taxi and, for each of the past weeks, a report per
+// taxi with its expenses and the banked takings. The last complete week is
+// submitted and waits for review; older weeks are approved, a few rejected.
+// Plan limits don't apply, so admins can size demos past the tenant's plan.
+func (s *DemoService) Generate(ctx context.Context, tenantID uint, req DemoRequest) (*DemoSummary, error) {
+	tenant, err := s.repo.GetTenantByID(ctx, tenantID)
+	if err != nil {
+		return nil, notFound("tenant not found")
+	}
+	if req.Taxis == 0 {
+		req.Taxis = 8
+	}
+	if req.Weeks == 0 {
+		req.Weeks = 26
+	}
+	if req.Seed == 0 {
+		req.Seed = time.Now().UnixNano()
+	}
+	if req.Password == "" {
+		req.Password = "demo1234"
+	}
+	rng := rand.New(rand.NewSource(req.Seed))
+
+	hashedPassword, err := hashPassword(req.Password)
+	if err != nil {
+		return nil, errors.New("failed to hash password")
+	}
+	// Tags the logins so the generator can run more than once per tenant
+	tag := strings.ToLower(demoLetters(rng, 4))
+
+	drivers := make([]repository.User, req.Taxis)
+	for i := range drivers {
+		drivers[i] = repository.User{
+			TenantID:     tenant.ID,
+			Email:        fmt.Sprintf("driver%d.%s@%s.demo.taxifleet.app", i+1, tag, tenant.Subdomain),
+			PasswordHash: hashedPassword,
+			Permission:   permissions.PermissionDriver,
+			FirstName:    demoFirstNames[rng.Intn(len(demoFirstNames))],
+			LastName:     demoLastNames[rng.Intn(len(demoLastNames))],
+			Phone:        fmt.Sprintf("+22501%08d", rng.Intn(100000000)),
+			Active:       true,
+		}
+	}
+
+	taxis, err := s.demoTaxis(ctx, tenant, rng, req.Taxis)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &DemoSummary{
+		TenantID:       tenant.ID,
+		Seed:           req.Seed,
+		Drivers:        len(drivers),
+		Taxis:          len(taxis),
+		DriverEmails:   make([]string, len(drivers)),
+		DriverPassword: req.Password,
+	}
+	for i, driver := range drivers {
+		summary.DriverEmails[i] = driver.Email
+	}
+
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateDemoUsers(ctx, drivers); err != nil {
+			return err
+		}
+		for i := range taxis {
+			taxis[i].AssignedDriverID = &drivers[i].ID
+		}
+		if err := s.repo.CreateDemoTaxis(ctx, taxis); err != nil {
+			return err
+		}
+
+		currentWeek := startOfWeek(time.Now(), parseTenantSettings(tenant.Settings).WeekStart())
+		reports := make([]repository.WeeklyReport, 0, len(taxis)*req.Weeks)
+		expenses := make([][]repository.Expense, 0, cap(reports))
+		for i, taxi := range taxis {
+			// Each driver has their own typical week
+			base := demoAmount(rng, 650, 1100)
+			for week := req.Weeks; week >= 1; week-- {
+				start := currentWeek.AddDate(0, 0, -7*week)
+				report, reportExpenses := demoWeek(rng, tenant.ID, taxi.ID, drivers[i].ID, start, base, week == 1)
+				reports = append(reports, report)
+				expenses = append(expenses, reportExpenses)
+			}
+		}
+		if err := s.repo.CreateDemoReports(ctx, reports); err != nil {
+			return err
+		}
+
+		var allExpenses []repository.Expense
+		var deposits []repository.BankDeposit
+		for i, report := range reports {
+			for _, expense := range expenses[i] {
+				expense.ReportID = &reports[i].ID
+				allExpenses = append(allExpenses, expense)
+			}
+			if report.Status == "approved" {
+				deposits = append(deposits, demoDeposit(rng, report, currentWeek))
+			}
+		}
+		if err := s.repo.CreateDemoExpenses(ctx, allExpenses); err != nil {
+			return err
+		}
+		if len(deposits) > 0 {
+			if err := s.repo.CreateDemoDeposits(ctx, deposits); err != nil {
+				return err
+			}
+		}
+
+		summary.Reports, summary.Expenses, summary.Deposits = len(reports), len(allExpenses), len(deposits)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// demoTaxis makes taxis with plates in the tenant's format that none of its
+// taxis have yet
+func (s *DemoService) demoTaxis(ctx context.Context, tenant *repository.Tenant, rng *rand.Rand, count int) ([]repository.Taxi, error) {
+	existing, err := s.repo.GetTaxisByTenant(ctx, tenant.ID)
+	if err != nil {
+		return nil, err
+	}
+	taken := make(map[string]bool, len(existing)+count)
+	for _, taxi := range existing {
+		taken[strings.ToUpper(taxi.LicensePlate)] = true
+	}
+
+	plate, ok := demoPlates[strings.ToUpper(parseTenantSettings(tenant.Settings).Country)]
+	if !ok {
+		plate = demoPlates["FR"]
+	}
+
+	taxis := make([]repository.Taxi, 0, count)
+	for len(taxis) < count {
+		licensePlate, err := normalizePlate(tenant, plate(rng))
+		if err != nil {
+			return nil, err
+		}
+		if taken[licensePlate] {
+			continue
+		}
+		taken[licensePlate] = true
+
+		taxis = append(taxis, repository.Taxi{
+			TenantID:     tenant.ID,
+			LicensePlate: licensePlate,
+			Model:        demoModels[rng.Intn(len(demoModels))],
+			Year:         2015 + rng.Intn(10),
+			Color:        demoColors[rng.Intn(len(demoColors))],
+			Status:       "active",
+			Mileage:      40000 + rng.Intn(160000),
+		})
+	}
+	return taxis, nil
+}
+
+// demoWeek makes a report of the week starting at start with its expenses:
+// fuel every week, cleaning most weeks and now and then a service or repair
+func demoWeek(rng *rand.Rand, tenantID, taxiID, driverID uint, start time.Time, base money.Amount, lastWeek bool) (repository.WeeklyReport, []repository.Expense) {
+	earnings := base.Mul(0.8 + rng.Float64()*0.4)
+	expenses := []repository.Expense{demoExpense(rng, "fuel", earnings.Float()*0.18, earnings.Float()*0.28, start)}
+	if rng.Float64() < 0.6 {
+		expenses = append(expenses, demoExpense(rng, "cleaning", 8, 25, start))
+	}
+	if rng.Float64() < 0.12 {
+		expenses = append(expenses, demoExpense(rng, "maintenance", 60, 180, start))
+	}
+	if rng.Float64() < 0.05 {
+		expenses = append(expenses, demoExpense(rng, "repair", 120, 450, start))
+	}
+
+	var total money.Amount
+	for i := range expenses {
+		expenses[i].TenantID = tenantID
+		expenses[i].TaxiID = &taxiID
+		expenses[i].CreatedByID = driverID
+		total += expenses[i].Amount
+	}
+
+	submittedAt := start.AddDate(0, 0, 7).Add(18 * time.Hour)
+	report := repository.WeeklyReport{
+		TenantID:      tenantID,
+		TaxiID:        taxiID,
+		DriverID:      driverID,
+		WeekStartDate: start,
+		Earnings:      earnings,
+		TotalExpenses: total,
+		Status:        "submitted",
+		SubmittedAt:   &submittedAt,
+		CreatedAt:     submittedAt,
+		UpdatedAt:     submittedAt,
+	}
+	if lastWeek {
+		return report, expenses
+	}
+
+	reviewedAt := submittedAt.Add(time.Duration(12+rng.Intn(48)) * time.Hour)
+	report.UpdatedAt = reviewedAt
+	if rng.Float64() < 0.04 {
+		report.Status = "rejected"
+		report.Notes = "Earnings do not match the trip log, please check and resubmit"
+	} else {
+		report.Status = "approved"
+		report.ApprovedAt = &reviewedAt
+	}
+	return report, expenses
+}
+
+func demoExpense(rng *rand.Rand, category string, min, max float64, weekStart time.Time) repository.Expense {
+	date := weekStart.AddDate(0, 0, rng.Intn(7)).Add(time.Duration(7+rng.Intn(14)) * time.Hour)
+	return repository.Expense{
+		Category:  category,
+		Amount:    demoAmount(rng, min, max),
+		Date:      date,
+		CreatedAt: date,
+		UpdatedAt: date,
+	}
+}
+
+// demoDeposit banks the net takings of an approved report a few days after
+// the week; deposits of the last weeks are not verified yet
+func demoDeposit(rng *rand.Rand, report repository.WeeklyReport, currentWeek time.Time) repository.BankDeposit {
+	periodEnd := report.WeekStartDate.AddDate(0, 0, 6)
+	depositDate := periodEnd.AddDate(0, 0, 1+rng.Intn(3))
+	deposit := repository.BankDeposit{
+		TenantID:    report.TenantID,
+		TaxiID:      &report.TaxiID,
+		Amount:      report.Earnings - report.TotalExpenses,
+		DepositDate: depositDate,
+		PeriodStart: report.WeekStartDate,
+		PeriodEnd:   periodEnd,
+		BankAccount: "Demo operating account",
+		Status:      "unverified",
+		CreatedAt:   depositDate,
+		UpdatedAt:   depositDate,
+	}
+	if report.WeekStartDate.Before(currentWeek.AddDate(0, 0, -21)) {
+		verifiedAt := depositDate.Add(24 * time.Hour)
+		deposit.Status, deposit.VerifiedAt, deposit.UpdatedAt = "verified", &verifiedAt, verifiedAt
+	}
+	return deposit
+}
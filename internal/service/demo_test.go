@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+	"taxifleet/backend/internal/validation"
+
+	"go.uber.org/mock/gomock"
+)
+
+type demoRepoMock struct {
+	*mocks.MockTransactor
+	*mocks.MockTenantRepo
+	*mocks.MockTaxiRepo
+	*mocks.MockDemoRepo
+}
+
+// demoData is what a demo run inserted
+type demoData struct {
+	taxis    []repository.Taxi
+	reports  []repository.WeeklyReport
+	expenses []repository.Expense
+	deposits []repository.BankDeposit
+}
+
+func generateDemo(t *testing.T, req DemoRequest) (*DemoSummary, *demoData) {
+	ctrl := gomock.NewController(t)
+	repo := demoRepoMock{
+		MockTransactor: mocks.NewMockTransactor(ctrl),
+		MockTenantRepo: mocks.NewMockTenantRepo(ctrl),
+		MockTaxiRepo:   mocks.NewMockTaxiRepo(ctrl),
+		MockDemoRepo:   mocks.NewMockDemoRepo(ctrl),
+	}
+	repo.MockTenantRepo.EXPECT().GetTenantByID(gomock.Any(), uint(1)).Return(&repository.Tenant{ID: 1, Subdomain: "city-cabs", Settings: `{"country":"CI"}`}, nil)
+	repo.MockTaxiRepo.EXPECT().GetTaxisByTenant(gomock.Any(), uint(1)).Return(nil, nil)
+	repo.MockTransactor.EXPECT().InTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	})
+
+	data := &demoData{}
+	var nextID uint
+	repo.MockDemoRepo.EXPECT().CreateDemoUsers(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, users []repository.User) error {
+		for i := range users {
+			nextID++
+			users[i].ID = nextID
+		}
+		return nil
+	})
+	repo.MockDemoRepo.EXPECT().CreateDemoTaxis(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, taxis []repository.Taxi) error {
+		for i := range taxis {
+			nextID++
+			taxis[i].ID = nextID
+		}
+		data.taxis = taxis
+		return nil
+	})
+	repo.MockDemoRepo.EXPECT().CreateDemoReports(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, reports []repository.WeeklyReport) error {
+		for i := range reports {
+			nextID++
+			reports[i].ID = nextID
+		}
+		data.reports = reports
+		return nil
+	})
+	repo.MockDemoRepo.EXPECT().CreateDemoExpenses(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, expenses []repository.Expense) error {
+		data.expenses = expenses
+		return nil
+	})
+	repo.MockDemoRepo.EXPECT().CreateDemoDeposits(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, deposits []repository.BankDeposit) error {
+		data.deposits = deposits
+		return nil
+	})
+
+	summary, err := NewDemoService(repo).Generate(context.Background(), 1, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return summary, data
+}
+
+func TestGenerateDemoData(t *testing.T) {
+	summary, data := generateDemo(t, DemoRequest{Taxis: 3, Weeks: 6, Seed: 42})
+
+	if summary.Drivers != 3 || summary.Taxis != 3 || summary.Reports != 18 || len(summary.DriverEmails) != 3 {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+	for _, taxi := range data.taxis {
+		if err := validation.CheckLicensePlate("CI", taxi.LicensePlate); err != nil || taxi.AssignedDriverID == nil {
+			t.Fatalf("expected an assigned taxi with an Ivorian plate, got %+v", taxi)
+		}
+	}
+
+	// The last complete week of each taxi waits for review, older weeks are
+	// reviewed and only approved weeks are banked
+	reviewed, approved := 0, 0
+	for i, report := range data.reports {
+		lastWeek := i%6 == 5
+		if lastWeek != (report.Status == "submitted") {
+			t.Fatalf("unexpected status %q of report %d", report.Status, i)
+		}
+		if report.Status != "submitted" {
+			reviewed++
+		}
+		if report.Status == "approved" {
+			approved++
+		}
+	}
+	if reviewed != 15 || len(data.deposits) != approved {
+		t.Fatalf("expected a deposit per approved report, got %d deposits for %d approved", len(data.deposits), approved)
+	}
+
+	totals := map[uint]money.Amount{}
+	for _, expense := range data.expenses {
+		if expense.ReportID == nil {
+			t.Fatalf("expected every expense on a report, got %+v", expense)
+		}
+		totals[*expense.ReportID] += expense.Amount
+	}
+	for _, report := range data.reports {
+		if totals[report.ID] != report.TotalExpenses {
+			t.Fatalf("expected report %d to total %s, got %s", report.ID, totals[report.ID], report.TotalExpenses)
+		}
+	}
+}
+
+func TestGenerateDemoDataIsReproducible(t *testing.T) {
+	_, first := generateDemo(t, DemoRequest{Taxis: 2, Weeks: 3, Seed: 7})
+	_, second := generateDemo(t, DemoRequest{Taxis: 2, Weeks: 3, Seed: 7})
+
+	for i := range first.reports {
+		if first.reports[i].Earnings != second.reports[i].Earnings || first.taxis[i%2].LicensePlate != second.taxis[i%2].LicensePlate {
+			t.Fatal("expected the same seed to generate the same data")
+		}
+	}
+}
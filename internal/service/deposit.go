@@ -1,94 +1,174 @@
 package service
 
 import (
-	"errors"
+	"cmp"
+	"context"
 	"time"
+
+	"taxifleet/backend/internal/cache"
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/permissions"
 	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/validation"
 )
 
+// DepositRepository is the data access DepositService depends on
+type DepositRepository interface {
+	repository.DepositRepo
+	repository.TaxiRepo
+	repository.BankAccountRepo
+	repository.PeriodRepo
+}
+
 type DepositService struct {
-	repo *repository.Repository
+	repo  DepositRepository
+	cache cache.Cache
 }
 
-func NewDepositService(repo *repository.Repository) *DepositService {
-	return &DepositService{repo: repo}
+func NewDepositService(repo DepositRepository, cache cache.Cache) *DepositService {
+	return &DepositService{repo: repo, cache: cache}
 }
 
 type CreateDepositRequest struct {
-	Amount      float64 `json:"amount" binding:"required"`
-	DepositDate string  `json:"deposit_date" binding:"required"`
-	PeriodStart string  `json:"period_start" binding:"required"`
-	PeriodEnd   string  `json:"period_end" binding:"required"`
-	BankAccount string  `json:"bank_account"`
-	ProofURL    string  `json:"proof_url"`
-	Notes       string  `json:"notes"`
+	Amount        money.Amount `json:"amount" binding:"required,amount"`
+	DepositDate   string       `json:"deposit_date" binding:"required"`
+	PeriodStart   string       `json:"period_start" binding:"required"`
+	PeriodEnd     string       `json:"period_end" binding:"required"`
+	BankAccountID *uint        `json:"bank_account_id"`
+	ProofURL      string       `json:"proof_url" sanitize:"-"`
+	Notes         string       `json:"notes"`
+	TaxiID        *uint        `json:"taxi_id"` // Taxi whose cash was deposited, for its cash ledger
 }
 
 type UpdateDepositRequest struct {
-	Amount      float64 `json:"amount"`
-	DepositDate string  `json:"deposit_date"`
-	PeriodStart string  `json:"period_start"`
-	PeriodEnd   string  `json:"period_end"`
-	BankAccount string  `json:"bank_account"`
-	ProofURL    string  `json:"proof_url"`
-	Notes       string  `json:"notes"`
+	Amount        money.Amount `json:"amount" binding:"omitempty,amount"`
+	DepositDate   string       `json:"deposit_date"`
+	PeriodStart   string       `json:"period_start"`
+	PeriodEnd     string       `json:"period_end"`
+	BankAccountID *uint        `json:"bank_account_id"`
+	ProofURL      string       `json:"proof_url" sanitize:"-"`
+	Notes         string       `json:"notes"`
+	TaxiID        *uint        `json:"taxi_id"`
+}
+
+// ensureTenantTaxi verifies the deposit's taxi belongs to the tenant
+func (s *DepositService) ensureTenantTaxi(ctx context.Context, tenantID uint, taxiID *uint) error {
+	if taxiID == nil {
+		return nil
+	}
+	taxi, err := s.repo.GetTaxiByID(ctx, *taxiID)
+	if err != nil || taxi.TenantID != tenantID {
+		return notFound("taxi not found")
+	}
+	return nil
 }
 
-func (s *DepositService) Create(tenantID uint, req CreateDepositRequest) (*repository.BankDeposit, error) {
+// ensureTenantBankAccount verifies the deposit's account belongs to the tenant
+func (s *DepositService) ensureTenantBankAccount(ctx context.Context, tenantID uint, accountID *uint) error {
+	if accountID == nil {
+		return nil
+	}
+	account, err := s.repo.GetBankAccountByID(ctx, *accountID)
+	if err != nil || account.TenantID != tenantID {
+		return notFound("bank account not found")
+	}
+	return nil
+}
+
+func (s *DepositService) Create(ctx context.Context, tenantID uint, req CreateDepositRequest) (*repository.BankDeposit, error) {
+	if err := s.ensureTenantTaxi(ctx, tenantID, req.TaxiID); err != nil {
+		return nil, err
+	}
+	if err := s.ensureTenantBankAccount(ctx, tenantID, req.BankAccountID); err != nil {
+		return nil, err
+	}
+
 	depositDate, _ := time.Parse("2006-01-02", req.DepositDate)
 	periodStart, _ := time.Parse("2006-01-02", req.PeriodStart)
 	periodEnd, _ := time.Parse("2006-01-02", req.PeriodEnd)
 
+	if err := ensurePeriodOpen(ctx, s.repo, tenantID, depositDate); err != nil {
+		return nil, err
+	}
+
 	deposit := &repository.BankDeposit{
-		TenantID:    tenantID,
-		Amount:      req.Amount,
-		DepositDate: depositDate,
-		PeriodStart: periodStart,
-		PeriodEnd:   periodEnd,
-		BankAccount: req.BankAccount,
-		ProofURL:    req.ProofURL,
-		Notes:       req.Notes,
+		TenantID:      tenantID,
+		Amount:        req.Amount,
+		DepositDate:   depositDate,
+		PeriodStart:   periodStart,
+		PeriodEnd:     periodEnd,
+		BankAccountID: req.BankAccountID,
+		ProofURL:      req.ProofURL,
+		Notes:         req.Notes,
+		TaxiID:        req.TaxiID,
 	}
 
-	if err := s.repo.CreateDeposit(deposit); err != nil {
+	if err := s.repo.CreateDeposit(ctx, deposit); err != nil {
 		return nil, err
 	}
 
-	return s.repo.GetDepositByID(deposit.ID)
+	s.cache.Invalidate(ctx, tenantID)
+
+	return s.repo.GetDepositByID(ctx, deposit.ID)
 }
 
-func (s *DepositService) GetByID(id uint, tenantID uint) (*repository.BankDeposit, error) {
-	deposit, err := s.repo.GetDepositByID(id)
+func (s *DepositService) GetByID(ctx context.Context, id uint, tenantID uint) (*repository.BankDeposit, error) {
+	deposit, err := s.repo.GetDepositByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	if deposit.TenantID != tenantID {
-		return nil, errors.New("deposit not found")
+		return nil, notFound("deposit not found")
 	}
 
 	return deposit, nil
 }
 
-func (s *DepositService) List(tenantID uint) ([]repository.BankDeposit, error) {
-	return s.repo.GetDepositsByTenant(tenantID)
+// depositSortKeys are the fields the deposit list can be sorted by
+var depositSortKeys = sortKeys[repository.BankDeposit]{
+	"id":           func(a, b repository.BankDeposit) int { return cmp.Compare(a.ID, b.ID) },
+	"deposit_date": func(a, b repository.BankDeposit) int { return a.DepositDate.Compare(b.DepositDate) },
+	"amount":       func(a, b repository.BankDeposit) int { return cmp.Compare(a.Amount, b.Amount) },
+	"status":       func(a, b repository.BankDeposit) int { return cmp.Compare(a.Status, b.Status) },
+	"created_at":   func(a, b repository.BankDeposit) int { return a.CreatedAt.Compare(b.CreatedAt) },
+}
+
+// List returns the tenant's deposits, newest first unless the sort parameter
+// says otherwise (see sortList)
+func (s *DepositService) List(ctx context.Context, tenantID uint, sort string) ([]repository.BankDeposit, error) {
+	deposits, err := cache.Remember(ctx, s.cache, tenantID, "deposits", func() ([]repository.BankDeposit, error) {
+		return s.repo.GetDepositsByTenant(ctx, tenantID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sortList(deposits, sort, depositSortKeys)
 }
 
-func (s *DepositService) Update(id uint, tenantID uint, req UpdateDepositRequest) (*repository.BankDeposit, error) {
-	deposit, err := s.repo.GetDepositByID(id)
+func (s *DepositService) Update(ctx context.Context, id uint, tenantID uint, req UpdateDepositRequest) (*repository.BankDeposit, error) {
+	deposit, err := s.repo.GetDepositByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	if deposit.TenantID != tenantID {
-		return nil, errors.New("deposit not found")
+		return nil, notFound("deposit not found")
 	}
 
+	if err := ensurePeriodOpen(ctx, s.repo, tenantID, deposit.DepositDate); err != nil {
+		return nil, err
+	}
+
+	before := *deposit
 	if req.Amount != 0 {
 		deposit.Amount = req.Amount
 	}
 	if req.DepositDate != "" {
 		depositDate, _ := time.Parse("2006-01-02", req.DepositDate)
+		if err := ensurePeriodOpen(ctx, s.repo, tenantID, depositDate); err != nil {
+			return nil, err
+		}
 		deposit.DepositDate = depositDate
 	}
 	if req.PeriodStart != "" {
@@ -99,8 +179,11 @@ func (s *DepositService) Update(id uint, tenantID uint, req UpdateDepositRequest
 		periodEnd, _ := time.Parse("2006-01-02", req.PeriodEnd)
 		deposit.PeriodEnd = periodEnd
 	}
-	if req.BankAccount != "" {
-		deposit.BankAccount = req.BankAccount
+	if req.BankAccountID != nil {
+		if err := s.ensureTenantBankAccount(ctx, tenantID, req.BankAccountID); err != nil {
+			return nil, err
+		}
+		deposit.BankAccountID = req.BankAccountID
 	}
 	if req.ProofURL != "" {
 		deposit.ProofURL = req.ProofURL
@@ -108,24 +191,95 @@ func (s *DepositService) Update(id uint, tenantID uint, req UpdateDepositRequest
 	if req.Notes != "" {
 		deposit.Notes = req.Notes
 	}
+	if req.TaxiID != nil {
+		if err := s.ensureTenantTaxi(ctx, tenantID, req.TaxiID); err != nil {
+			return nil, err
+		}
+		deposit.TaxiID = req.TaxiID
+	}
 
-	if err := s.repo.UpdateDeposit(deposit); err != nil {
+	// A verified deposit must be checked again once what was verified changes
+	if deposit.Amount != before.Amount || !deposit.DepositDate.Equal(before.DepositDate) ||
+		!deposit.PeriodStart.Equal(before.PeriodStart) || !deposit.PeriodEnd.Equal(before.PeriodEnd) ||
+		!sameID(deposit.BankAccountID, before.BankAccountID) || deposit.ProofURL != before.ProofURL {
+		deposit.Status = "unverified"
+		deposit.VerifiedAt = nil
+		deposit.VerifiedByID = nil
+	}
+
+	if err := s.repo.UpdateDeposit(ctx, deposit); err != nil {
 		return nil, err
 	}
 
-	return s.repo.GetDepositByID(deposit.ID)
+	s.cache.Invalidate(ctx, tenantID)
+
+	return s.repo.GetDepositByID(ctx, deposit.ID)
 }
 
-func (s *DepositService) Delete(id uint, tenantID uint) error {
-	deposit, err := s.repo.GetDepositByID(id)
+// Verify marks a deposit as checked against its proof. Only owners and
+// admins can verify deposits, and only those with a proof attached.
+func (s *DepositService) Verify(ctx context.Context, id uint, tenantID uint, userID uint, permission int) (*repository.BankDeposit, error) {
+	// Every owner bit is required; the permissions helpers would let a manager
+	// through on a bit shared with owners. Admins have all bits set.
+	if permission&permissions.PermissionOwner != permissions.PermissionOwner {
+		return nil, forbidden("only owner or admin can verify deposits")
+	}
+
+	deposit, err := s.GetByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensurePeriodOpen(ctx, s.repo, tenantID, deposit.DepositDate); err != nil {
+		return nil, err
+	}
+	if deposit.Status == "verified" {
+		return nil, conflict("deposit already verified")
+	}
+	if deposit.ProofURL == "" {
+		return nil, &validation.FieldError{Field: "proof_url", Rule: "required", Message: "a proof must be attached before the deposit can be verified"}
+	}
+
+	now := time.Now()
+	deposit.Status = "verified"
+	deposit.VerifiedAt = &now
+	deposit.VerifiedByID = &userID
+
+	if err := s.repo.UpdateDeposit(ctx, deposit); err != nil {
+		return nil, err
+	}
+
+	s.cache.Invalidate(ctx, tenantID)
+
+	return s.repo.GetDepositByID(ctx, deposit.ID)
+}
+
+func (s *DepositService) Delete(ctx context.Context, id uint, tenantID uint) error {
+	deposit, err := s.repo.GetDepositByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
 	if deposit.TenantID != tenantID {
-		return errors.New("deposit not found")
+		return notFound("deposit not found")
+	}
+
+	if err := ensurePeriodOpen(ctx, s.repo, tenantID, deposit.DepositDate); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteDeposit(ctx, id); err != nil {
+		return err
 	}
 
-	return s.repo.DeleteDeposit(id)
+	s.cache.Invalidate(ctx, tenantID)
+
+	return nil
 }
 
+// sameID compares two optional references
+func sameID(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
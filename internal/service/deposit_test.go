@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"taxifleet/backend/internal/cache"
+	"taxifleet/backend/internal/permissions"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+	"taxifleet/backend/internal/validation"
+
+	"go.uber.org/mock/gomock"
+)
+
+type depositRepoMock struct {
+	*mocks.MockDepositRepo
+	*mocks.MockTaxiRepo
+	*mocks.MockBankAccountRepo
+	*mocks.MockPeriodRepo
+}
+
+func newDepositServiceMock(t *testing.T) (*DepositService, depositRepoMock) {
+	ctrl := gomock.NewController(t)
+	repo := depositRepoMock{
+		MockDepositRepo:     mocks.NewMockDepositRepo(ctrl),
+		MockTaxiRepo:        mocks.NewMockTaxiRepo(ctrl),
+		MockBankAccountRepo: mocks.NewMockBankAccountRepo(ctrl),
+		MockPeriodRepo:      mocks.NewMockPeriodRepo(ctrl),
+	}
+	repo.MockPeriodRepo.EXPECT().IsPeriodClosed(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+	return NewDepositService(repo, cache.Noop{}), repo
+}
+
+func TestDepositVerifyRequiresProof(t *testing.T) {
+	svc, repo := newDepositServiceMock(t)
+	repo.MockDepositRepo.EXPECT().GetDepositByID(gomock.Any(), uint(5)).Return(&repository.BankDeposit{ID: 5, TenantID: 1, Status: "unverified"}, nil)
+
+	_, err := svc.Verify(context.Background(), 5, 1, 2, permissions.PermissionOwner)
+	var fieldErr *validation.FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Field != "proof_url" {
+		t.Fatalf("expected a proof_url validation error, got %v", err)
+	}
+}
+
+func TestDepositVerifyOnlyByOwner(t *testing.T) {
+	svc, _ := newDepositServiceMock(t)
+
+	if _, err := svc.Verify(context.Background(), 5, 1, 2, permissions.PermissionManager); err == nil {
+		t.Fatal("expected a manager not to be allowed to verify deposits")
+	}
+}
+
+func TestDepositUpdateResetsVerification(t *testing.T) {
+	svc, repo := newDepositServiceMock(t)
+	verifiedBy := uint(2)
+	deposit := &repository.BankDeposit{ID: 5, TenantID: 1, Amount: 100_00, ProofURL: "https://example.com/slip.jpg", Status: "verified", VerifiedByID: &verifiedBy}
+	repo.MockDepositRepo.EXPECT().GetDepositByID(gomock.Any(), uint(5)).Return(deposit, nil).Times(2)
+	repo.MockDepositRepo.EXPECT().UpdateDeposit(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, d *repository.BankDeposit) error {
+		if d.Status != "unverified" || d.VerifiedByID != nil {
+			t.Fatalf("expected the verification to be reset, got %+v", d)
+		}
+		return nil
+	})
+
+	if _, err := svc.Update(context.Background(), 5, 1, UpdateDepositRequest{Amount: 120_00}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"text/template"
+	"time"
+
+	"taxifleet/backend/internal/logging"
+	"taxifleet/backend/internal/mail"
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/permissions"
+	"taxifleet/backend/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DigestRepository is the data access DigestService depends on
+type DigestRepository interface {
+	repository.DigestRepo
+	repository.TenantRepo
+	repository.UserRepo
+	repository.TaxiRepo
+	repository.AnalyticsRepo
+	repository.MaintenanceRepo
+}
+
+// DigestService builds the weekly digest of a tenant and emails it to the
+// owners who did not opt out
+type DigestService struct {
+	repo   DigestRepository
+	mailer mail.Sender
+	logger *logrus.Logger
+}
+
+func NewDigestService(repo DigestRepository, mailer mail.Sender, logger *logrus.Logger) *DigestService {
+	return &DigestService{repo: repo, mailer: mailer, logger: logger}
+}
+
+// WeeklyDigest summarizes the tenant's last complete reporting week
+type WeeklyDigest struct {
+	Tenant         string           `json:"tenant"`
+	Period         AnalyticsPeriod  `json:"period"`
+	Revenue        money.Amount     `json:"revenue"` // Earnings of the week's approved reports
+	Expenses       money.Amount     `json:"expenses"`
+	Net            money.Amount     `json:"net"`
+	AwaitingReview int64            `json:"awaiting_review"` // Submitted reports of any week
+	MissingReports []MissingReport  `json:"missing_reports"`
+	MaintenanceDue []MaintenanceDue `json:"maintenance_due"`
+}
+
+// MissingReport is an assigned taxi whose driver has not submitted the week's
+// report
+type MissingReport struct {
+	TaxiID       uint   `json:"taxi_id"`
+	LicensePlate string `json:"license_plate"`
+	DriverID     uint   `json:"driver_id"`
+	DriverName   string `json:"driver_name"`
+	Status       string `json:"status"` // missing, or draft when started but not submitted
+}
+
+// Build returns the digest of the tenant's last complete week
+func (s *DigestService) Build(ctx context.Context, tenantID uint) (*WeeklyDigest, error) {
+	tenant, err := s.repo.GetTenantByID(ctx, tenantID)
+	if err != nil {
+		return nil, notFound("tenant not found")
+	}
+	return s.build(ctx, tenant, time.Now())
+}
+
+func (s *DigestService) build(ctx context.Context, tenant *repository.Tenant, now time.Time) (*WeeklyDigest, error) {
+	settings := parseTenantSettings(tenant.Settings)
+	to := startOfWeek(now, settings.WeekStart())
+	from := to.AddDate(0, 0, -7)
+
+	revenue, err := s.repo.SumApprovedEarnings(ctx, tenant.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.repo.SumExpensesByCategory(ctx, tenant.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	var expenses money.Amount
+	for _, category := range categories {
+		expenses += category.Total
+	}
+
+	awaiting, err := s.repo.CountSubmittedReports(ctx, tenant.ID)
+	if err != nil {
+		return nil, err
+	}
+	missing, err := s.missingReports(ctx, tenant.ID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules, err := s.repo.GetMaintenanceSchedulesByTenant(ctx, tenant.ID)
+	if err != nil {
+		return nil, err
+	}
+	due := []MaintenanceDue{}
+	for _, schedule := range schedules {
+		if item, ok := checkDue(schedule, now); ok {
+			due = append(due, item)
+		}
+	}
+
+	return &WeeklyDigest{
+		Tenant:         tenant.Name,
+		Period:         AnalyticsPeriod{From: from, To: to.AddDate(0, 0, -1)},
+		Revenue:        revenue,
+		Expenses:       expenses,
+		Net:            revenue - expenses,
+		AwaitingReview: awaiting,
+		MissingReports: missing,
+		MaintenanceDue: due,
+	}, nil
+}
+
+// missingReports lists the active taxis with an assigned driver and no
+// submitted report for the week
+func (s *DigestService) missingReports(ctx context.Context, tenantID uint, weekStart time.Time) ([]MissingReport, error) {
+	taxis, err := s.repo.GetTaxisByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	reports, err := s.repo.GetReportsForWeek(ctx, tenantID, weekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct{ taxiID, driverID uint }
+	statuses := make(map[key]string, len(reports))
+	for _, report := range reports {
+		statuses[key{report.TaxiID, report.DriverID}] = report.Status
+	}
+
+	missing := []MissingReport{}
+	for _, taxi := range taxis {
+		if taxi.Status != "active" || taxi.AssignedDriverID == nil {
+			continue
+		}
+		status, ok := statuses[key{taxi.ID, *taxi.AssignedDriverID}]
+		if ok && status != "draft" {
+			continue
+		}
+		item := MissingReport{TaxiID: taxi.ID, LicensePlate: taxi.LicensePlate, DriverID: *taxi.AssignedDriverID, Status: "missing"}
+		if ok {
+			item.Status = status
+		}
+		if taxi.AssignedDriver != nil {
+			item.DriverName = taxi.AssignedDriver.FirstName + " " + taxi.AssignedDriver.LastName
+		}
+		missing = append(missing, item)
+	}
+	return missing, nil
+}
+
+// SetSubscription opts the user in or out of the weekly digest
+func (s *DigestService) SetSubscription(ctx context.Context, userID uint, enabled bool) error {
+	return s.repo.SetWeeklyDigest(ctx, userID, enabled)
+}
+
+// SendWeekly emails every tenant's digest to its active owners who kept the
+// digest on. A failed email is logged and does not stop the others.
+func (s *DigestService) SendWeekly(ctx context.Context) error {
+	tenants, err := s.repo.GetAllTenants(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var errs []error
+	for i := range tenants {
+		tenant := &tenants[i]
+		users, err := s.repo.GetUsersByTenant(ctx, tenant.ID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		var recipients []repository.User
+		for _, user := range users {
+			if user.Active && user.WeeklyDigest && user.Email != "" && isOwner(user.Permission) {
+				recipients = append(recipients, user)
+			}
+		}
+		if len(recipients) == 0 {
+			continue
+		}
+
+		digest, err := s.build(ctx, tenant, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("digest of tenant %d: %w", tenant.ID, err))
+			continue
+		}
+		msg, err := RenderDigest(digest)
+		if err != nil {
+			return err
+		}
+
+		for _, user := range recipients {
+			msg.To = user.Email
+			sendCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+			err := s.mailer.Send(sendCtx, msg)
+			cancel()
+			if err != nil {
+				logging.Entry(ctx, s.logger).WithError(err).WithFields(logrus.Fields{
+					"tenant_id": tenant.ID,
+					"user_id":   user.ID,
+				}).Warn("Failed to send weekly digest")
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// isOwner tells owners apart from platform admins, whose mask includes the
+// owner's
+func isOwner(permission int) bool {
+	return permission&permissions.PermissionOwner == permissions.PermissionOwner &&
+		permission&permissions.PermissionManageTenants == 0
+}
+
+var digestFuncs = template.FuncMap{
+	"date":  func(t time.Time) string { return t.Format("02/01/2006") },
+	"money": money.Amount.String,
+}
+
+var digestText = template.Must(template.New("digest").Funcs(digestFuncs).Parse(`Weekly digest for {{.Tenant}}, {{date .Period.From}} - {{date .Period.To}}
+
+Revenue:  {{money .Revenue}}
+Expenses: {{money .Expenses}}
+Net:      {{money .Net}}
+
+Reports awaiting review: {{.AwaitingReview}}
+{{if .MissingReports}}
+Reports not submitted:
+{{range .MissingReports}}- {{.LicensePlate}}, {{.DriverName}} ({{.Status}})
+{{end}}{{end}}{{if .MaintenanceDue}}
+Maintenance due:
+{{range .MaintenanceDue}}- {{.Schedule.Taxi.LicensePlate}}: {{.Schedule.Task}}
+{{end}}{{end}}`))
+
+var digestHTML = htmltemplate.Must(htmltemplate.New("digest").Funcs(htmltemplate.FuncMap(digestFuncs)).Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: Helvetica, Arial, sans-serif; color: #222;">
+<h2>Weekly digest for {{.Tenant}}</h2>
+<p>{{date .Period.From}} - {{date .Period.To}}</p>
+<table cellpadding="4">
+<tr><td>Revenue</td><td align="right">{{money .Revenue}}</td></tr>
+<tr><td>Expenses</td><td align="right">{{money .Expenses}}</td></tr>
+<tr><td><strong>Net</strong></td><td align="right"><strong>{{money .Net}}</strong></td></tr>
+</table>
+<p>Reports awaiting review: <strong>{{.AwaitingReview}}</strong></p>
+{{if .MissingReports}}<h3>Reports not submitted</h3>
+<ul>{{range .MissingReports}}<li>{{.LicensePlate}}, {{.DriverName}} ({{.Status}})</li>{{end}}</ul>
+{{end}}{{if .MaintenanceDue}}<h3>Maintenance due</h3>
+<ul>{{range .MaintenanceDue}}<li>{{.Schedule.Taxi.LicensePlate}}: {{.Schedule.Task}}</li>{{end}}</ul>
+{{end}}</body>
+</html>
+`))
+
+// RenderDigest renders the digest as an email without a recipient
+func RenderDigest(digest *WeeklyDigest) (mail.Message, error) {
+	var text, html bytes.Buffer
+	if err := digestText.Execute(&text, digest); err != nil {
+		return mail.Message{}, err
+	}
+	if err := digestHTML.Execute(&html, digest); err != nil {
+		return mail.Message{}, err
+	}
+	return mail.Message{
+		Subject: fmt.Sprintf("%s weekly digest, %s", digest.Tenant, digest.Period.From.Format("02/01/2006")),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
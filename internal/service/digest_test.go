@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"taxifleet/backend/internal/mail"
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+
+	"go.uber.org/mock/gomock"
+)
+
+type digestRepoMock struct {
+	*mocks.MockDigestRepo
+	*mocks.MockTenantRepo
+	*mocks.MockUserRepo
+	*mocks.MockTaxiRepo
+	*mocks.MockAnalyticsRepo
+	*mocks.MockMaintenanceRepo
+}
+
+func TestDigestListsMissingReports(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := digestRepoMock{
+		MockDigestRepo:      mocks.NewMockDigestRepo(ctrl),
+		MockTenantRepo:      mocks.NewMockTenantRepo(ctrl),
+		MockUserRepo:        mocks.NewMockUserRepo(ctrl),
+		MockTaxiRepo:        mocks.NewMockTaxiRepo(ctrl),
+		MockAnalyticsRepo:   mocks.NewMockAnalyticsRepo(ctrl),
+		MockMaintenanceRepo: mocks.NewMockMaintenanceRepo(ctrl),
+	}
+	svc := NewDigestService(repo, mail.NoopSender{}, nil)
+
+	// Wednesday; the last complete week ran Monday 2 to Sunday 8 March
+	now := time.Date(2026, 3, 11, 9, 0, 0, 0, time.UTC)
+	from := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	tenant := &repository.Tenant{ID: 1, Name: "City Cabs"}
+	driver1, driver2, driver3 := uint(11), uint(12), uint(13)
+
+	repo.MockAnalyticsRepo.EXPECT().SumApprovedEarnings(gomock.Any(), uint(1), from, to).Return(money.Amount(900_00), nil)
+	repo.MockAnalyticsRepo.EXPECT().SumExpensesByCategory(gomock.Any(), uint(1), from, to).Return([]repository.CategoryTotal{
+		{Category: "fuel", Total: 120_00},
+		{Category: "repair", Total: 80_50},
+	}, nil)
+	repo.MockDigestRepo.EXPECT().CountSubmittedReports(gomock.Any(), uint(1)).Return(int64(2), nil)
+	repo.MockTaxiRepo.EXPECT().GetTaxisByTenant(gomock.Any(), uint(1)).Return([]repository.Taxi{
+		{ID: 1, LicensePlate: "AB-123", Status: "active", AssignedDriverID: &driver1},
+		{ID: 2, LicensePlate: "CD-456", Status: "active", AssignedDriverID: &driver2, AssignedDriver: &repository.User{FirstName: "Ana", LastName: "Silva"}},
+		{ID: 3, LicensePlate: "EF-789", Status: "active", AssignedDriverID: &driver3},
+		{ID: 4, LicensePlate: "GH-012", Status: "maintenance", AssignedDriverID: &driver1},
+		{ID: 5, LicensePlate: "IJ-345", Status: "active"},
+	}, nil)
+	repo.MockDigestRepo.EXPECT().GetReportsForWeek(gomock.Any(), uint(1), from).Return([]repository.WeeklyReport{
+		{TaxiID: 1, DriverID: driver1, Status: "submitted"},
+		{TaxiID: 3, DriverID: driver3, Status: "draft"},
+	}, nil)
+	repo.MockMaintenanceRepo.EXPECT().GetMaintenanceSchedulesByTenant(gomock.Any(), uint(1)).Return(nil, nil)
+
+	digest, err := svc.build(context.Background(), tenant, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if digest.Revenue != 900_00 || digest.Expenses != 200_50 || digest.Net != 699_50 || digest.AwaitingReview != 2 {
+		t.Fatalf("unexpected figures %+v", digest)
+	}
+	if len(digest.MissingReports) != 2 ||
+		digest.MissingReports[0].LicensePlate != "CD-456" || digest.MissingReports[0].Status != "missing" ||
+		digest.MissingReports[1].LicensePlate != "EF-789" || digest.MissingReports[1].Status != "draft" {
+		t.Fatalf("unexpected missing reports %+v", digest.MissingReports)
+	}
+
+	msg, err := RenderDigest(digest)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(msg.Text, "CD-456, Ana Silva (missing)") || !strings.Contains(msg.HTML, "699.50") {
+		t.Fatalf("unexpected email:\n%s", msg.Text)
+	}
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/validation"
+)
+
+const (
+	splitPercentage  = "percentage"
+	splitDailyRental = "daily_rental"
+)
+
+// checkEarningsSplit validates a split rule; nil means no rule
+func checkEarningsSplit(split *repository.EarningsSplit) error {
+	if split == nil {
+		return nil
+	}
+	invalid := func(message string) error {
+		return &validation.FieldError{Field: "earnings_split", Rule: "earnings_split", Message: message}
+	}
+	switch split.Type {
+	case splitPercentage:
+		if split.DriverPercent < 0 || split.DriverPercent > 100 {
+			return invalid("earnings_split.driver_percent must be between 0 and 100")
+		}
+	case splitDailyRental:
+		if split.DailyRental < 0 {
+			return invalid("earnings_split.daily_rental must not be negative")
+		}
+	default:
+		return invalid("earnings_split.type must be percentage or daily_rental")
+	}
+	return nil
+}
+
+// earningsSplitFor returns the rule for a report's taxi: its own, or else the
+// tenant's. It is nil when neither has one.
+func earningsSplitFor(taxi *repository.Taxi, settings TenantSettings) *repository.EarningsSplit {
+	if taxi != nil && taxi.EarningsSplit != nil {
+		return taxi.EarningsSplit
+	}
+	return settings.EarningsSplit
+}
+
+// splitEarnings divides a week's net earnings. With a daily rental the owner
+// gets the rent for all seven days of the week and the driver keeps the rest,
+// which can be negative in a bad week. Adjustments (bonuses less penalties)
+// go to the driver at the owner's expense.
+func splitEarnings(split *repository.EarningsSplit, earnings, expenses, adjustments money.Amount) (driver, owner money.Amount) {
+	net := earnings - expenses
+	switch split.Type {
+	case splitDailyRental:
+		owner = split.DailyRental * 7
+		driver = net - owner
+	default:
+		driver = net.Mul(split.DriverPercent / 100)
+		owner = net - driver
+	}
+	return driver + adjustments, owner - adjustments
+}
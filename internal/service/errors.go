@@ -0,0 +1,51 @@
+package service
+
+import "errors"
+
+// Kinds of service errors. Errors of a kind keep their own message and match
+// it with errors.Is, so handlers can map them to an HTTP status without
+// knowing each error.
+var (
+	// ErrNotFound is the kind of errors for records that do not exist or
+	// belong to another tenant; both look the same to the caller
+	ErrNotFound = errors.New("not found")
+	// ErrForbidden is the kind of errors for callers who may see a record
+	// but not change it
+	ErrForbidden = errors.New("forbidden")
+	// ErrConflict is the kind of errors for changes the record's current
+	// state does not allow, e.g. editing an approved report
+	ErrConflict = errors.New("conflict")
+	// ErrValidation is the kind of errors for request values a service
+	// refuses, e.g. a malformed date
+	ErrValidation = errors.New("validation failed")
+)
+
+// kindError is an error of one of the kinds above with its own message
+type kindError struct {
+	kind    error
+	message string
+}
+
+func (e *kindError) Error() string {
+	return e.message
+}
+
+func (e *kindError) Is(target error) bool {
+	return target == e.kind
+}
+
+func notFound(message string) error {
+	return &kindError{kind: ErrNotFound, message: message}
+}
+
+func forbidden(message string) error {
+	return &kindError{kind: ErrForbidden, message: message}
+}
+
+func conflict(message string) error {
+	return &kindError{kind: ErrConflict, message: message}
+}
+
+func invalid(message string) error {
+	return &kindError{kind: ErrValidation, message: message}
+}
@@ -1,38 +1,78 @@
 package service
 
 import (
+	"cmp"
+	"context"
 	"errors"
+	"fmt"
 	"time"
+
+	"taxifleet/backend/internal/cache"
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/ocr"
+	"taxifleet/backend/internal/permissions"
 	"taxifleet/backend/internal/repository"
 )
 
+var (
+	// ErrNotExpenseCreator is returned when a user without the edit or delete
+	// expenses permission changes an expense someone else created
+	ErrNotExpenseCreator = forbidden("you can only change expenses you created")
+	// ErrExpenseApproved is returned when a user without the edit or delete
+	// expenses permission changes an expense of an approved report
+	ErrExpenseApproved = errors.New("expense is on an approved report")
+	// ErrNotExpenseApprover is returned when someone other than an owner
+	// reviews expenses awaiting approval
+	ErrNotExpenseApprover = forbidden("only owner or admin can approve expenses")
+)
+
+// ExpenseRepository is the data access ExpenseService depends on
+type ExpenseRepository interface {
+	repository.ExpenseRepo
+	repository.ReportRepo
+	repository.TaxiRepo
+	repository.TenantRepo
+	repository.Transactor
+	repository.PeriodRepo
+	repository.AuditRepo
+}
+
 type ExpenseService struct {
-	repo *repository.Repository
+	repo    ExpenseRepository
+	cache   cache.Cache
+	ocr     ocr.Provider
+	budgets *BudgetService
 }
 
-func NewExpenseService(repo *repository.Repository) *ExpenseService {
-	return &ExpenseService{repo: repo}
+func NewExpenseService(repo ExpenseRepository, cache cache.Cache, ocr ocr.Provider, budgets *BudgetService) *ExpenseService {
+	return &ExpenseService{repo: repo, cache: cache, ocr: ocr, budgets: budgets}
 }
 
 type CreateExpenseRequest struct {
-	ReportID   *uint   `json:"report_id"`
-	TaxiID     *uint   `json:"taxi_id"`
-	Category   string  `json:"category" binding:"required"`
-	Amount     float64 `json:"amount" binding:"required"`
-	Reason     string  `json:"reason"`
-	ReceiptURL string  `json:"receipt_url"`
-	Date       string  `json:"date" binding:"required"`
+	ReportID   *uint        `json:"report_id"`
+	TaxiID     *uint        `json:"taxi_id"`
+	Category   string       `json:"category" binding:"required"`
+	Amount     money.Amount `json:"amount" binding:"required,amount"`
+	Reason     string       `json:"reason"`
+	ReceiptURL string       `json:"receipt_url" sanitize:"-"`
+	Date       string       `json:"date" binding:"required"`
+	ClientID   string       `json:"client_id" binding:"omitempty,uuid"` // Set by the driver app for offline creates
 }
 
+// UpdateExpenseRequest changes the fields that are set; an empty reason or
+// receipt URL clears it
 type UpdateExpenseRequest struct {
-	Category   string  `json:"category"`
-	Amount     float64 `json:"amount"`
-	Reason     string  `json:"reason"`
-	ReceiptURL string  `json:"receipt_url"`
-	Date       string  `json:"date"`
+	Category   string        `json:"category"`
+	Amount     *money.Amount `json:"amount" binding:"omitempty,amount"`
+	Reason     *string       `json:"reason"`
+	ReceiptURL *string       `json:"receipt_url" sanitize:"-"`
+	Date       string        `json:"date"`
 }
 
-func (s *ExpenseService) Create(tenantID uint, createdByID uint, req CreateExpenseRequest) (*repository.Expense, error) {
+// Create records an expense. Above the tenant's approval threshold, an
+// expense recorded by anyone but an owner waits for approval and does not
+// count yet.
+func (s *ExpenseService) Create(ctx context.Context, tenantID uint, createdByID uint, permission int, req CreateExpenseRequest) (*repository.Expense, error) {
 	expense := &repository.Expense{
 		TenantID:    tenantID,
 		ReportID:    req.ReportID,
@@ -43,137 +83,356 @@ func (s *ExpenseService) Create(tenantID uint, createdByID uint, req CreateExpen
 		ReceiptURL:  req.ReceiptURL,
 		CreatedByID: createdByID,
 	}
+	if req.ClientID != "" {
+		expense.ClientID = &req.ClientID
+	}
+
+	if req.ReportID != nil {
+		report, err := s.repo.GetReportByID(ctx, *req.ReportID)
+		if err != nil || report.TenantID != tenantID {
+			return nil, notFound("report not found")
+		}
+	}
+	if req.TaxiID != nil {
+		taxi, err := s.repo.GetTaxiByID(ctx, *req.TaxiID)
+		if err != nil || taxi.TenantID != tenantID {
+			return nil, notFound("taxi not found")
+		}
+	}
 
 	// Parse date
 	if req.Date != "" {
 		date, err := time.Parse("2006-01-02", req.Date)
 		if err != nil {
-			return nil, errors.New("invalid date format")
+			return nil, invalid("invalid date format")
 		}
 		expense.Date = date
 	} else {
 		expense.Date = time.Now()
 	}
-
-	if err := s.repo.CreateExpense(expense); err != nil {
+	if err := ensurePeriodOpen(ctx, s.repo, tenantID, expense.Date); err != nil {
+		return nil, err
+	}
+	threshold, err := s.approvalThreshold(ctx, tenantID, permission)
+	if err != nil {
+		return nil, err
+	}
+	expense.Status = expenseStatus(expense.Amount, threshold)
+	spending, err := s.budgets.Spending(ctx, tenantID, expense.Date)
+	if err != nil {
 		return nil, err
 	}
 
-	// If expense is part of a report, update report total
-	if req.ReportID != nil {
-		report, _ := s.repo.GetReportByID(*req.ReportID)
-		if report != nil && report.TenantID == tenantID {
-			expenses, _ := s.repo.GetExpensesByReport(report.ID)
-			total := 0.0
-			for _, exp := range expenses {
-				total += exp.Amount
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateExpense(ctx, expense); err != nil {
+			return err
+		}
+		if expense.Amount >= largeExpenseAmount {
+			summary := fmt.Sprintf("Expense of %s (%s)", expense.Amount, expense.Category)
+			if err := s.repo.CreateAuditEvent(ctx, auditEvent(tenantID, createdByID, ActionExpenseCreated, "expense", expense.ID, summary)); err != nil {
+				return err
 			}
-			report.TotalExpenses = total
-			s.repo.UpdateReport(report)
 		}
+		return s.recalculateReport(ctx, expense.ReportID)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return s.repo.GetExpenseByID(expense.ID)
+	s.cache.Invalidate(ctx, tenantID)
+	s.budgets.AlertCrossed(ctx, tenantID, expense.Date, spending)
+
+	return s.repo.GetExpenseByID(ctx, expense.ID)
 }
 
-func (s *ExpenseService) GetByID(id uint, tenantID uint) (*repository.Expense, error) {
-	expense, err := s.repo.GetExpenseByID(id)
+func (s *ExpenseService) GetByID(ctx context.Context, id uint, tenantID uint) (*repository.Expense, error) {
+	expense, err := s.repo.GetExpenseByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	if expense.TenantID != tenantID {
-		return nil, errors.New("expense not found")
+		return nil, notFound("expense not found")
 	}
 
 	return expense, nil
 }
 
-func (s *ExpenseService) List(tenantID uint) ([]repository.Expense, error) {
-	return s.repo.GetExpensesByTenant(tenantID)
+// expenseSortKeys are the fields the expense list can be sorted by
+var expenseSortKeys = sortKeys[repository.Expense]{
+	"id":         func(a, b repository.Expense) int { return cmp.Compare(a.ID, b.ID) },
+	"date":       func(a, b repository.Expense) int { return a.Date.Compare(b.Date) },
+	"amount":     func(a, b repository.Expense) int { return cmp.Compare(a.Amount, b.Amount) },
+	"category":   func(a, b repository.Expense) int { return cmp.Compare(a.Category, b.Category) },
+	"created_at": func(a, b repository.Expense) int { return a.CreatedAt.Compare(b.CreatedAt) },
 }
 
-func (s *ExpenseService) Update(id uint, tenantID uint, req UpdateExpenseRequest) (*repository.Expense, error) {
-	expense, err := s.repo.GetExpenseByID(id)
+// List returns the tenant's expenses, those matching opts.Query if set,
+// newest first unless opts.Sort says otherwise
+func (s *ExpenseService) List(ctx context.Context, tenantID uint, opts ListOptions) ([]repository.Expense, error) {
+	var expenses []repository.Expense
+	var err error
+	if opts.Query != "" {
+		expenses, err = s.repo.SearchExpenses(ctx, tenantID, opts.Query, 0)
+	} else {
+		expenses, err = cache.Remember(ctx, s.cache, tenantID, "expenses", func() ([]repository.Expense, error) {
+			return s.repo.GetExpensesByTenant(ctx, tenantID)
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return sortList(expenses, opts.Sort, expenseSortKeys)
+}
+
+// Summary counts the tenant's expenses and sums their amounts per category,
+// taxi or month
+func (s *ExpenseService) Summary(ctx context.Context, tenantID uint, groupBy string) ([]repository.GroupTotal, error) {
+	if err := checkGroupBy(groupBy, expenseSummaryGroups); err != nil {
+		return nil, err
+	}
+	return s.repo.SummarizeExpenses(ctx, tenantID, groupBy)
+}
+
+// ExportRows returns the tenant's expenses to export, newest first
+func (s *ExpenseService) ExportRows(ctx context.Context, tenantID uint) ([]repository.ExpenseExportRow, error) {
+	return s.repo.GetExpenseExportRows(ctx, tenantID)
+}
+
+// ExpensePage is a page of the expense list
+type ExpensePage struct {
+	Expenses   []repository.Expense
+	NextCursor string // Fetches the following page; empty on the last page
+}
+
+// Page returns up to limit expenses after the cursor, or the latest without
+// one, newest first, reading one page from the database
+func (s *ExpenseService) Page(ctx context.Context, tenantID uint, cursor string, limit int) (*ExpensePage, error) {
+	after, err := decodeKeyset(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	// One extra expense tells whether another page follows
+	expenses, err := s.repo.GetExpensesPage(ctx, tenantID, after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &ExpensePage{Expenses: expenses}
+	if len(expenses) > limit {
+		page.Expenses = expenses[:limit]
+		last := page.Expenses[limit-1]
+		page.NextCursor = encodeKeyset(last.Date, last.ID)
+	}
+	return page, nil
+}
+
+// checkExpenseChange lets users with the given permission change any of the
+// tenant's expenses; others only change the ones they created while the
+// report they are on is not approved
+func checkExpenseChange(expense *repository.Expense, userID uint, permission int, required int) error {
+	if permissions.HasPermission(permission, required) {
+		return nil
+	}
+	if expense.CreatedByID != userID {
+		return ErrNotExpenseCreator
+	}
+	if expense.Report != nil && expense.Report.Status == "approved" {
+		return ErrExpenseApproved
+	}
+	return nil
+}
+
+func (s *ExpenseService) Update(ctx context.Context, id uint, tenantID uint, userID uint, permission int, req UpdateExpenseRequest) (*repository.Expense, error) {
+	expense, err := s.repo.GetExpenseByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	if expense.TenantID != tenantID {
-		return nil, errors.New("expense not found")
+		return nil, notFound("expense not found")
+	}
+	if err := checkExpenseChange(expense, userID, permission, permissions.PermissionEditExpenses); err != nil {
+		return nil, err
+	}
+	if err := ensurePeriodOpen(ctx, s.repo, tenantID, expense.Date); err != nil {
+		return nil, err
 	}
 
 	if req.Category != "" {
 		expense.Category = req.Category
 	}
-	if req.Amount != 0 {
-		expense.Amount = req.Amount
+	if req.Amount != nil && *req.Amount != expense.Amount {
+		threshold, err := s.approvalThreshold(ctx, tenantID, permission)
+		if err != nil {
+			return nil, err
+		}
+		expense.Amount = *req.Amount
+		if expense.Status = expenseStatus(expense.Amount, threshold); expense.Status != "approved" {
+			expense.ApprovedAt = nil
+			expense.ApprovedByID = nil
+		}
 	}
-	if req.Reason != "" {
-		expense.Reason = req.Reason
+	if req.Reason != nil {
+		expense.Reason = *req.Reason
 	}
-	if req.ReceiptURL != "" {
-		expense.ReceiptURL = req.ReceiptURL
+	if req.ReceiptURL != nil {
+		expense.ReceiptURL = *req.ReceiptURL
 	}
 	if req.Date != "" {
 		date, err := time.Parse("2006-01-02", req.Date)
 		if err != nil {
-			return nil, errors.New("invalid date format")
+			return nil, invalid("invalid date format")
+		}
+		if err := ensurePeriodOpen(ctx, s.repo, tenantID, date); err != nil {
+			return nil, err
 		}
 		expense.Date = date
 	}
 
-	if err := s.repo.UpdateExpense(expense); err != nil {
+	spending, err := s.budgets.Spending(ctx, tenantID, expense.Date)
+	if err != nil {
 		return nil, err
 	}
 
-	// Update report total if expense is part of a report
-	if expense.ReportID != nil {
-		report, _ := s.repo.GetReportByID(*expense.ReportID)
-		if report != nil {
-			expenses, _ := s.repo.GetExpensesByReport(report.ID)
-			total := 0.0
-			for _, exp := range expenses {
-				total += exp.Amount
-			}
-			report.TotalExpenses = total
-			s.repo.UpdateReport(report)
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdateExpense(ctx, expense); err != nil {
+			return err
 		}
+		return s.recalculateReport(ctx, expense.ReportID)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return s.repo.GetExpenseByID(expense.ID)
+	s.cache.Invalidate(ctx, tenantID)
+	s.budgets.AlertCrossed(ctx, tenantID, expense.Date, spending)
+
+	return s.repo.GetExpenseByID(ctx, expense.ID)
 }
 
-func (s *ExpenseService) Delete(id uint, tenantID uint) error {
-	expense, err := s.repo.GetExpenseByID(id)
+func (s *ExpenseService) Delete(ctx context.Context, id uint, tenantID uint, userID uint, permission int) error {
+	expense, err := s.repo.GetExpenseByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
 	if expense.TenantID != tenantID {
-		return errors.New("expense not found")
+		return notFound("expense not found")
 	}
-
-	reportID := expense.ReportID
-
-	if err := s.repo.DeleteExpense(id); err != nil {
+	if err := checkExpenseChange(expense, userID, permission, permissions.PermissionDeleteExpenses); err != nil {
+		return err
+	}
+	if err := ensurePeriodOpen(ctx, s.repo, tenantID, expense.Date); err != nil {
 		return err
 	}
 
-	// Update report total if expense was part of a report
-	if reportID != nil {
-		report, _ := s.repo.GetReportByID(*reportID)
-		if report != nil {
-			expenses, _ := s.repo.GetExpensesByReport(report.ID)
-			total := 0.0
-			for _, exp := range expenses {
-				total += exp.Amount
-			}
-			report.TotalExpenses = total
-			s.repo.UpdateReport(report)
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteExpense(ctx, id); err != nil {
+			return err
 		}
+		return s.recalculateReport(ctx, expense.ReportID)
+	})
+	if err != nil {
+		return err
 	}
 
+	s.cache.Invalidate(ctx, tenantID)
+
 	return nil
 }
 
+// Pending returns the tenant's expenses awaiting an owner's approval, oldest
+// first
+func (s *ExpenseService) Pending(ctx context.Context, tenantID uint, permission int) ([]repository.Expense, error) {
+	if !hasOwnerRights(permission) {
+		return nil, ErrNotExpenseApprover
+	}
+	return s.repo.GetPendingExpenses(ctx, tenantID)
+}
+
+// Approve lets an expense awaiting approval count towards its report, the
+// budgets and the tenant's totals
+func (s *ExpenseService) Approve(ctx context.Context, id uint, tenantID uint, userID uint, permission int) (*repository.Expense, error) {
+	if !hasOwnerRights(permission) {
+		return nil, ErrNotExpenseApprover
+	}
+
+	expense, err := s.GetByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if expense.Status != "pending_approval" {
+		return nil, conflict("expense is not awaiting approval")
+	}
+	if err := ensurePeriodOpen(ctx, s.repo, tenantID, expense.Date); err != nil {
+		return nil, err
+	}
+	spending, err := s.budgets.Spending(ctx, tenantID, expense.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	expense.Status = "approved"
+	expense.ApprovedAt = &now
+	expense.ApprovedByID = &userID
+
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdateExpense(ctx, expense); err != nil {
+			return err
+		}
+		summary := fmt.Sprintf("Expense of %s (%s)", expense.Amount, expense.Category)
+		if err := s.repo.CreateAuditEvent(ctx, auditEvent(tenantID, userID, ActionExpenseApproved, "expense", expense.ID, summary)); err != nil {
+			return err
+		}
+		return s.recalculateReport(ctx, expense.ReportID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.Invalidate(ctx, tenantID)
+	s.budgets.AlertCrossed(ctx, tenantID, expense.Date, spending)
+
+	return s.repo.GetExpenseByID(ctx, expense.ID)
+}
+
+// approvalThreshold returns the amount above which the user's expenses wait
+// for approval; zero when they don't, as for owners
+func (s *ExpenseService) approvalThreshold(ctx context.Context, tenantID uint, permission int) (money.Amount, error) {
+	if hasOwnerRights(permission) {
+		return 0, nil
+	}
+	tenant, err := s.repo.GetTenantByID(ctx, tenantID)
+	if err != nil {
+		return 0, err
+	}
+	return parseTenantSettings(tenant.Settings).ExpenseApprovalThreshold, nil
+}
+
+// expenseStatus returns the status of an expense of the amount under the
+// approval threshold
+func expenseStatus(amount, threshold money.Amount) string {
+	if threshold > 0 && amount > threshold {
+		return "pending_approval"
+	}
+	return "approved"
+}
+
+// BulkDelete deletes the expenses, all or none: each must be one the user
+// could delete on its own
+func (s *ExpenseService) BulkDelete(ctx context.Context, ids []uint, tenantID uint, userID uint, permission int) (*BulkResult, error) {
+	return runBulk(ctx, s.repo, ids, func(ctx context.Context, id uint) error {
+		return s.Delete(ctx, id, tenantID, userID, permission)
+	})
+}
+
+// recalculateReport refreshes the total expenses of the expense's report, if any
+func (s *ExpenseService) recalculateReport(ctx context.Context, reportID *uint) error {
+	if reportID == nil {
+		return nil
+	}
+	return s.repo.RecalculateReportExpenses(ctx, *reportID)
+}
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/validation"
+)
+
+// maxImportRows bounds the number of expenses a single import may hold
+const maxImportRows = 10000
+
+// expenseCategories are the categories imported expenses may have
+var expenseCategories = []string{"fuel", "maintenance", "insurance", "repair", "cleaning", "other"}
+
+// importDateFormats are the date formats an import may declare
+var importDateFormats = map[string]string{
+	"YYYY-MM-DD": "2006-01-02",
+	"DD/MM/YYYY": "02/01/2006",
+	"MM/DD/YYYY": "01/02/2006",
+	"DD.MM.YYYY": "02.01.2006",
+}
+
+// importFields are the expense fields a CSV column can be mapped to
+var importFields = []struct {
+	name     string
+	required bool
+}{
+	{"date", true},
+	{"amount", true},
+	{"category", true},
+	{"reason", false},
+	{"taxi", false}, // License plate of the taxi
+	{"receipt_url", false},
+}
+
+type ImportExpensesRequest struct {
+	// Mapping names the CSV column of each field; unmapped fields are read from
+	// the column of the same name
+	Mapping    map[string]string `json:"mapping"`
+	DateFormat string            `json:"date_format"` // One of importDateFormats, defaults to YYYY-MM-DD
+	DryRun     bool              `json:"dry_run"`
+}
+
+// ImportRowError explains why a row was rejected. Row counts the header as
+// row 1, like spreadsheets do.
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ExpenseImportReport tells which rows of an import were accepted. In a dry
+// run nothing is saved.
+type ExpenseImportReport struct {
+	DryRun   bool             `json:"dry_run"`
+	Rows     int              `json:"rows"`
+	Accepted int              `json:"accepted"`
+	Rejected int              `json:"rejected"`
+	Errors   []ImportRowError `json:"errors"`
+}
+
+// Import creates an expense for each valid row of a CSV file. Rows that fail
+// validation are reported and skipped; the others are saved together, unless
+// req.DryRun is set. Rows above the approval threshold wait for approval as
+// created ones do.
+func (s *ExpenseService) Import(ctx context.Context, tenantID uint, createdByID uint, permission int, file io.Reader, req ImportExpensesRequest) (*ExpenseImportReport, error) {
+	layout := "2006-01-02"
+	if req.DateFormat != "" {
+		var ok bool
+		if layout, ok = importDateFormats[strings.ToUpper(req.DateFormat)]; !ok {
+			return nil, &validation.FieldError{Field: "date_format", Rule: "oneof", Message: "date_format must be YYYY-MM-DD, DD/MM/YYYY, MM/DD/YYYY or DD.MM.YYYY"}
+		}
+	}
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, invalid("the file is not a CSV file with a header row")
+	}
+
+	columns, err := mapImportColumns(header, req.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	taxis, err := s.repo.GetTaxisByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	taxiByPlate := make(map[string]uint, len(taxis))
+	for _, taxi := range taxis {
+		taxiByPlate[validation.NormalizeLicensePlate(taxi.LicensePlate)] = taxi.ID
+	}
+
+	periods, err := s.repo.GetClosedPeriods(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	closed := make(map[string]bool, len(periods))
+	for _, period := range periods {
+		closed[period.Month.Format("2006-01")] = true
+	}
+	threshold, err := s.approvalThreshold(ctx, tenantID, permission)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ExpenseImportReport{DryRun: req.DryRun, Errors: []ImportRowError{}}
+	var expenses []*repository.Expense
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if report.Rows++; report.Rows > maxImportRows {
+			return nil, fmt.Errorf("an import holds at most %d rows", maxImportRows)
+		}
+		if err != nil {
+			report.Errors = append(report.Errors, ImportRowError{Row: row, Message: "malformed CSV row"})
+			continue
+		}
+
+		value := func(field string) string {
+			if i, ok := columns[field]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		expense, rowErr := parseImportRow(value, layout, taxiByPlate)
+		if rowErr != nil {
+			rowErr.Row = row
+			report.Errors = append(report.Errors, *rowErr)
+			continue
+		}
+		if closed[expense.Date.Format("2006-01")] {
+			report.Errors = append(report.Errors, ImportRowError{Row: row, Field: "date", Message: fmt.Sprintf("%s is in a closed period", expense.Date.Format("2006-01-02"))})
+			continue
+		}
+		expense.TenantID = tenantID
+		expense.CreatedByID = createdByID
+		expense.Status = expenseStatus(expense.Amount, threshold)
+		expenses = append(expenses, expense)
+	}
+
+	report.Accepted = len(expenses)
+	report.Rejected = report.Rows - report.Accepted
+	if req.DryRun || len(expenses) == 0 {
+		return report, nil
+	}
+
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		for _, expense := range expenses {
+			if err := s.repo.CreateExpense(ctx, expense); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.Invalidate(ctx, tenantID)
+
+	return report, nil
+}
+
+// mapImportColumns finds the column index of each field in the header row.
+// Column names are matched case-insensitively.
+func mapImportColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	index := headerIndex(header)
+	columns := make(map[string]int, len(importFields))
+	for _, field := range importFields {
+		column, mapped := mapping[field.name]
+		if !mapped {
+			column = field.name
+		}
+		if i, ok := index[strings.ToLower(strings.TrimSpace(column))]; ok {
+			columns[field.name] = i
+		} else if field.required || mapped {
+			return nil, &validation.FieldError{Field: "mapping", Rule: "column", Message: fmt.Sprintf("column %q for %s not found in the file", column, field.name)}
+		}
+	}
+	return columns, nil
+}
+
+// headerIndex maps the lowercased column names of a header row to their
+// index; a repeated name keeps its first column
+func headerIndex(header []string) map[string]int {
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		// Excel starts UTF-8 CSV files with a byte order mark
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\uFEFF")))
+		if _, seen := index[name]; !seen {
+			index[name] = i
+		}
+	}
+	return index
+}
+
+// parseImportRow builds the expense of a row, or explains why it is rejected
+func parseImportRow(value func(string) string, layout string, taxiByPlate map[string]uint) (*repository.Expense, *ImportRowError) {
+	date, err := time.Parse(layout, value("date"))
+	if err != nil {
+		return nil, &ImportRowError{Field: "date", Message: fmt.Sprintf("invalid date %q", value("date"))}
+	}
+
+	amount, err := parseImportAmount(value("amount"))
+	if err != nil || amount <= 0 {
+		return nil, &ImportRowError{Field: "amount", Message: fmt.Sprintf("invalid amount %q", value("amount"))}
+	}
+
+	category := strings.ToLower(value("category"))
+	if !slices.Contains(expenseCategories, category) {
+		return nil, &ImportRowError{Field: "category", Message: fmt.Sprintf("unknown category %q, expected one of %s", value("category"), strings.Join(expenseCategories, ", "))}
+	}
+
+	expense := &repository.Expense{
+		Category:   category,
+		Amount:     amount,
+		Reason:     value("reason"),
+		ReceiptURL: value("receipt_url"),
+		Date:       date,
+	}
+
+	if plate := value("taxi"); plate != "" {
+		taxiID, ok := taxiByPlate[validation.NormalizeLicensePlate(plate)]
+		if !ok {
+			return nil, &ImportRowError{Field: "taxi", Message: fmt.Sprintf("no taxi with license plate %q", plate)}
+		}
+		expense.TaxiID = &taxiID
+	}
+
+	return expense, nil
+}
+
+// parseImportAmount reads an amount written with a decimal point or, as many
+// spreadsheets export it, a decimal comma
+func parseImportAmount(raw string) (money.Amount, error) {
+	raw = strings.ReplaceAll(raw, " ", "")
+	if strings.Contains(raw, ".") {
+		raw = strings.ReplaceAll(raw, ",", "")
+	} else {
+		raw = strings.Replace(raw, ",", ".", 1)
+	}
+	return money.Parse(raw)
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/ocr"
+	"taxifleet/backend/internal/tracing"
+)
+
+// ErrReceiptType is returned for receipts that are not a supported image
+var ErrReceiptType = errors.New("receipt must be a JPEG, PNG or WebP image")
+
+// ReceiptScan is an expense pre-filled from a receipt photo for the client to
+// review before creating it. Fields that could not be read are left empty.
+type ReceiptScan struct {
+	Expense CreateExpenseRequest `json:"expense"`
+	Vendor  string               `json:"vendor"`
+	Text    string               `json:"text"` // Text read from the receipt
+}
+
+// ScanReceipt reads the amount, date and vendor of a receipt photo with the
+// configured OCR provider. Nothing is saved.
+func (s *ExpenseService) ScanReceipt(ctx context.Context, image io.Reader) (_ *ReceiptScan, err error) {
+	ctx, span := tracing.Start(ctx, "ExpenseService.ScanReceipt")
+	defer func() { tracing.End(span, err) }()
+
+	data, err := io.ReadAll(image)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := attachmentExtensions[http.DetectContentType(data)]; !ok {
+		return nil, ErrReceiptType
+	}
+
+	text, err := s.ocr.ExtractText(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := ocr.ParseReceipt(text)
+	scan := &ReceiptScan{
+		Expense: CreateExpenseRequest{
+			Category: receipt.Category,
+			Amount:   money.FromFloat(receipt.Amount),
+			Reason:   receipt.Vendor,
+		},
+		Vendor: receipt.Vendor,
+		Text:   text,
+	}
+	if !receipt.Date.IsZero() {
+		scan.Expense.Date = receipt.Date.Format("2006-01-02")
+	}
+	return scan, nil
+}
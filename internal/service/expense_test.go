@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"taxifleet/backend/internal/cache"
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/ocr"
+	"taxifleet/backend/internal/permissions"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+	"taxifleet/backend/internal/validation"
+
+	"github.com/sirupsen/logrus"
+	"go.uber.org/mock/gomock"
+)
+
+type expenseRepoMock struct {
+	*mocks.MockExpenseRepo
+	*mocks.MockReportRepo
+	*mocks.MockTaxiRepo
+	*mocks.MockTenantRepo
+	*mocks.MockTransactor
+	*mocks.MockPeriodRepo
+	*mocks.MockAuditRepo
+	*mocks.MockBudgetRepo
+}
+
+func newExpenseServiceMock(t *testing.T) (*ExpenseService, expenseRepoMock) {
+	ctrl := gomock.NewController(t)
+	repo := expenseRepoMock{
+		MockExpenseRepo: mocks.NewMockExpenseRepo(ctrl),
+		MockReportRepo:  mocks.NewMockReportRepo(ctrl),
+		MockTaxiRepo:    mocks.NewMockTaxiRepo(ctrl),
+		MockTenantRepo:  mocks.NewMockTenantRepo(ctrl),
+		MockTransactor:  mocks.NewMockTransactor(ctrl),
+		MockPeriodRepo:  mocks.NewMockPeriodRepo(ctrl),
+		MockAuditRepo:   mocks.NewMockAuditRepo(ctrl),
+		MockBudgetRepo:  mocks.NewMockBudgetRepo(ctrl),
+	}
+	repo.MockBudgetRepo.EXPECT().GetBudgetSpending(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(map[uint]money.Amount{}, nil).AnyTimes()
+	repo.MockPeriodRepo.EXPECT().IsPeriodClosed(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+	repo.MockPeriodRepo.EXPECT().GetClosedPeriods(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	budgets := NewBudgetService(repo, cache.Noop{}, nil, logrus.New())
+	return NewExpenseService(repo, cache.Noop{}, ocr.DisabledProvider{}, budgets), repo
+}
+
+const expenseImportCSV = "Date,Total,Type,Plate\n" +
+	"04/03/2024,\"45,50\",Fuel,ab-123-cd\n" +
+	"05/03/2024,-3,fuel,\n" +
+	"06/03/2024,12,snacks,\n" +
+	"07/03/2024,80,repair,ZZ-999-ZZ\n"
+
+func TestExpenseImportDryRunReportsRejectedRows(t *testing.T) {
+	svc, repo := newExpenseServiceMock(t)
+	repo.MockTaxiRepo.EXPECT().GetTaxisByTenant(gomock.Any(), uint(1)).Return([]repository.Taxi{{ID: 5, LicensePlate: "AB-123-CD"}}, nil)
+
+	req := ImportExpensesRequest{
+		Mapping:    map[string]string{"amount": "Total", "category": "type", "taxi": "Plate"},
+		DateFormat: "DD/MM/YYYY",
+		DryRun:     true,
+	}
+	report, err := svc.Import(context.Background(), 1, 2, permissions.PermissionOwner, strings.NewReader(expenseImportCSV), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Rows != 4 || report.Accepted != 1 || report.Rejected != 3 {
+		t.Fatalf("expected 1 of 4 rows accepted, got %+v", report)
+	}
+	fields := []string{}
+	for _, rowErr := range report.Errors {
+		fields = append(fields, rowErr.Field)
+	}
+	if strings.Join(fields, ",") != "amount,category,taxi" || report.Errors[0].Row != 3 {
+		t.Fatalf("unexpected row errors %+v", report.Errors)
+	}
+}
+
+func TestExpenseImportRequiresMappedColumns(t *testing.T) {
+	svc, _ := newExpenseServiceMock(t)
+
+	_, err := svc.Import(context.Background(), 1, 2, permissions.PermissionOwner, strings.NewReader(expenseImportCSV), ImportExpensesRequest{DryRun: true})
+	var fieldErr *validation.FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Field != "mapping" {
+		t.Fatalf("expected a mapping error for the missing amount column, got %v", err)
+	}
+}
+
+func TestDriverCannotChangeOthersExpenses(t *testing.T) {
+	svc, repo := newExpenseServiceMock(t)
+	repo.MockExpenseRepo.EXPECT().GetExpenseByID(gomock.Any(), uint(7)).Return(&repository.Expense{ID: 7, TenantID: 1, CreatedByID: 3}, nil).Times(2)
+
+	amount := money.Amount(20_00)
+	if _, err := svc.Update(context.Background(), 7, 1, 4, permissions.PermissionDriver, UpdateExpenseRequest{Amount: &amount}); !errors.Is(err, ErrNotExpenseCreator) {
+		t.Fatalf("expected ErrNotExpenseCreator, got %v", err)
+	}
+	if err := svc.Delete(context.Background(), 7, 1, 4, permissions.PermissionDriver); !errors.Is(err, ErrNotExpenseCreator) {
+		t.Fatalf("expected ErrNotExpenseCreator, got %v", err)
+	}
+}
+
+func TestDriverCannotChangeApprovedExpenses(t *testing.T) {
+	svc, repo := newExpenseServiceMock(t)
+	reportID := uint(9)
+	expense := &repository.Expense{ID: 7, TenantID: 1, CreatedByID: 4, ReportID: &reportID, Report: &repository.WeeklyReport{ID: 9, Status: "approved"}}
+	repo.MockExpenseRepo.EXPECT().GetExpenseByID(gomock.Any(), uint(7)).Return(expense, nil)
+
+	if err := svc.Delete(context.Background(), 7, 1, 4, permissions.PermissionDriver); !errors.Is(err, ErrExpenseApproved) {
+		t.Fatalf("expected ErrExpenseApproved, got %v", err)
+	}
+}
+
+func TestDeleteOthersExpenseWithPermission(t *testing.T) {
+	svc, repo := newExpenseServiceMock(t)
+	repo.MockExpenseRepo.EXPECT().GetExpenseByID(gomock.Any(), uint(7)).Return(&repository.Expense{ID: 7, TenantID: 1, CreatedByID: 3}, nil)
+	repo.MockTransactor.EXPECT().InTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	})
+	repo.MockExpenseRepo.EXPECT().DeleteExpense(gomock.Any(), uint(7)).Return(nil)
+
+	if err := svc.Delete(context.Background(), 7, 1, 2, permissions.PermissionDeleteExpenses); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestExpenseCreateRefusesOtherTenantsTaxi(t *testing.T) {
+	svc, repo := newExpenseServiceMock(t)
+	taxiID := uint(5)
+	repo.MockTaxiRepo.EXPECT().GetTaxiByID(gomock.Any(), taxiID).Return(&repository.Taxi{ID: 5, TenantID: 2}, nil)
+
+	_, err := svc.Create(context.Background(), 1, 3, permissions.PermissionDriver, CreateExpenseRequest{TaxiID: &taxiID, Category: "fuel", Amount: 10_00, Date: "2024-05-13"})
+	if err == nil || err.Error() != "taxi not found" {
+		t.Fatalf("expected taxi not found, got %v", err)
+	}
+}
+
+func TestExpensePageRejectsInvalidCursor(t *testing.T) {
+	svc, _ := newExpenseServiceMock(t)
+
+	for _, cursor := range []string{"not a cursor", encodeCursor(7)} {
+		var fieldErr *validation.FieldError
+		if _, err := svc.Page(context.Background(), 1, cursor, 20); !errors.As(err, &fieldErr) || fieldErr.Field != "cursor" {
+			t.Errorf("expected a cursor field error for %q, got %v", cursor, err)
+		}
+	}
+}
+
+func TestExpenseBulkDeleteIsAllOrNothing(t *testing.T) {
+	svc, repo := newExpenseServiceMock(t)
+	rolledBack := false
+	repo.MockTransactor.EXPECT().InTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		err := fn(ctx)
+		if errors.Is(err, errBulkFailed) {
+			rolledBack = true
+		}
+		return err
+	}).AnyTimes()
+	repo.MockExpenseRepo.EXPECT().GetExpenseByID(gomock.Any(), uint(7)).Return(&repository.Expense{ID: 7, TenantID: 1, CreatedByID: 3}, nil)
+	repo.MockExpenseRepo.EXPECT().DeleteExpense(gomock.Any(), uint(7)).Return(nil)
+	repo.MockExpenseRepo.EXPECT().GetExpenseByID(gomock.Any(), uint(8)).Return(&repository.Expense{ID: 8, TenantID: 2, CreatedByID: 3}, nil)
+
+	result, err := svc.BulkDelete(context.Background(), []uint{7, 8}, 1, 2, permissions.PermissionDeleteExpenses)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Applied || !rolledBack {
+		t.Fatal("expected the deletion to be rolled back")
+	}
+	if result.Items[0].Error != "" || result.Items[1].Error != "expense not found" {
+		t.Fatalf("unexpected item results %+v", result.Items)
+	}
+
+	var fieldErr *validation.FieldError
+	if _, err := svc.BulkDelete(context.Background(), []uint{7, 7}, 1, 2, permissions.PermissionDeleteExpenses); !errors.As(err, &fieldErr) {
+		t.Fatalf("expected a field error for repeated ids, got %v", err)
+	}
+}
+
+func TestExpenseAboveThresholdAwaitsApproval(t *testing.T) {
+	svc, repo := newExpenseServiceMock(t)
+	repo.MockTenantRepo.EXPECT().GetTenantByID(gomock.Any(), uint(1)).Return(&repository.Tenant{ID: 1, Settings: `{"expense_approval_threshold": 300}`}, nil)
+	repo.MockTransactor.EXPECT().InTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}).Times(2)
+	repo.MockExpenseRepo.EXPECT().CreateExpense(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, expense *repository.Expense) error {
+		if expense.Status != "pending_approval" {
+			t.Errorf("expected the expense to await approval, got %q", expense.Status)
+		}
+		expense.ID = 7
+		return nil
+	})
+	pending := &repository.Expense{ID: 7, TenantID: 1, CreatedByID: 3, Category: "repair", Amount: 400_00, Status: "pending_approval"}
+	repo.MockExpenseRepo.EXPECT().GetExpenseByID(gomock.Any(), uint(7)).Return(pending, nil).Times(3)
+
+	if _, err := svc.Create(context.Background(), 1, 3, permissions.PermissionDriver, CreateExpenseRequest{Category: "repair", Amount: 400_00, Date: "2024-05-13"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := svc.Approve(context.Background(), 7, 1, 3, permissions.PermissionManager); !errors.Is(err, ErrNotExpenseApprover) {
+		t.Fatalf("expected ErrNotExpenseApprover for a manager, got %v", err)
+	}
+	repo.MockExpenseRepo.EXPECT().UpdateExpense(gomock.Any(), gomock.Any()).Return(nil)
+	repo.MockAuditRepo.EXPECT().CreateAuditEvent(gomock.Any(), gomock.Any()).Return(nil)
+	if _, err := svc.Approve(context.Background(), 7, 1, 2, permissions.PermissionOwner); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pending.Status != "approved" || pending.ApprovedByID == nil || *pending.ApprovedByID != 2 {
+		t.Fatalf("expected the owner's approval to be recorded, got %+v", pending)
+	}
+}
@@ -0,0 +1,116 @@
+//go:build integration
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	"taxifleet/backend/internal/cache"
+	"taxifleet/backend/internal/permissions"
+)
+
+// The export and dashboard benchmarks compare the purpose-built queries with
+// loading the lists they used to be built from, for a tenant with 50,000
+// reports and expenses:
+//
+//	go test -tags integration -run '^$' -bench 'Export|Dashboard' ./internal/service/
+
+// seedBenchmarkTenant gives a new tenant 100 taxis with 500 weekly reports
+// and 500 expenses each
+func seedBenchmarkTenant(b *testing.B, s *isolationServices) *isolationTenant {
+	b.Helper()
+	tenant := createIsolationTenant(b, s, "benchmark")
+	tenantID, ownerID := tenant.tenant.ID, tenant.owner.ID
+
+	statements := []string{
+		`INSERT INTO taxis (tenant_id, license_plate, model, assigned_driver_id)
+			SELECT $1, 'BM-' || n, 'Corolla', $2 FROM generate_series(1, 100) n`,
+		`INSERT INTO weekly_reports (tenant_id, taxi_id, driver_id, week_start_date, earnings, status)
+			SELECT $1, t.id, $2, DATE '2000-01-03' + 7 * w, 1000, 'approved'
+			FROM taxis t, generate_series(0, 499) w WHERE t.tenant_id = $1 AND t.license_plate LIKE 'BM-%'`,
+		`INSERT INTO expenses (tenant_id, taxi_id, category, amount, date, created_by_id)
+			SELECT $1, t.id, 'fuel', 50, DATE '2000-01-03' + w, $2
+			FROM taxis t, generate_series(0, 499) w WHERE t.tenant_id = $1 AND t.license_plate LIKE 'BM-%'`,
+	}
+	for _, statement := range statements {
+		if _, err := s.db.Exec(statement, tenantID, ownerID); err != nil {
+			b.Fatalf("failed to seed: %v", err)
+		}
+	}
+	return tenant
+}
+
+func BenchmarkReportExport(b *testing.B) {
+	s := openIsolationDB(b)
+	tenant := seedBenchmarkTenant(b, s)
+	ctx := context.Background()
+
+	b.Run("List", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := s.reports.List(ctx, tenant.tenant.ID, tenant.owner.ID, permissions.PermissionOwner, ListOptions{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("ExportRows", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := s.reports.ExportRows(ctx, tenant.tenant.ID, tenant.owner.ID, permissions.PermissionOwner); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkExpenseExport(b *testing.B) {
+	s := openIsolationDB(b)
+	tenant := seedBenchmarkTenant(b, s)
+	ctx := context.Background()
+
+	b.Run("List", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := s.expenses.List(ctx, tenant.tenant.ID, ListOptions{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("ExportRows", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := s.expenses.ExportRows(ctx, tenant.tenant.ID); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkDashboardStats(b *testing.B) {
+	s := openIsolationDB(b)
+	tenant := seedBenchmarkTenant(b, s)
+	dashboard := NewDashboardService(s.repo, cache.Noop{})
+	ctx := context.Background()
+
+	// What the stats were computed from before
+	b.Run("Lists", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := s.repo.GetTaxisByTenant(ctx, tenant.tenant.ID); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := s.repo.GetReportsByTenant(ctx, tenant.tenant.ID); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := s.repo.GetExpensesByTenant(ctx, tenant.tenant.ID); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := s.repo.GetDepositsByTenant(ctx, tenant.tenant.ID); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Totals", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := dashboard.GetStats(ctx, tenant.tenant.ID); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
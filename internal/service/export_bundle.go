@@ -0,0 +1,373 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"time"
+
+	"taxifleet/backend/internal/export"
+	"taxifleet/backend/internal/logging"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/storage"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrBundleNotReady is returned when downloading a bundle still being generated
+var ErrBundleNotReady = errors.New("export bundle is not ready yet")
+
+// ExportBundleRepository is the data access ExportBundleService depends on
+type ExportBundleRepository interface {
+	repository.ExportBundleRepo
+	repository.ReportRepo
+	repository.ExpenseRepo
+	repository.DepositRepo
+}
+
+// ExportBundleService generates ZIP archives of a period's report, expense
+// and deposit exports together with the report photos, in the background,
+// and serves them until they expire
+type ExportBundleService struct {
+	repo          ExportBundleRepository
+	branding      *BrandingService
+	notifications *NotificationService
+	files         storage.Store
+	ttl           time.Duration // How long a bundle can be downloaded
+	urlTTL        time.Duration // How long a presigned download URL is valid
+	logger        *logrus.Logger
+}
+
+func NewExportBundleService(repo ExportBundleRepository, branding *BrandingService, notifications *NotificationService, files storage.Store, ttl, urlTTL time.Duration, logger *logrus.Logger) *ExportBundleService {
+	return &ExportBundleService{repo: repo, branding: branding, notifications: notifications, files: files, ttl: ttl, urlTTL: urlTTL, logger: logger}
+}
+
+// ExportBundleStatus is a bundle with the URLs to poll and download it
+type ExportBundleStatus struct {
+	*repository.ExportBundle
+	StatusURL   string `json:"status_url"`
+	DownloadURL string `json:"download_url,omitempty"` // Set once ready
+}
+
+func bundleStatus(bundle *repository.ExportBundle) *ExportBundleStatus {
+	status := &ExportBundleStatus{
+		ExportBundle: bundle,
+		StatusURL:    fmt.Sprintf("/api/v1/export/bundle/%d", bundle.ID),
+	}
+	if bundle.Status == repository.BundleReady {
+		status.DownloadURL = bundleDownloadURL(bundle.ID)
+	}
+	return status
+}
+
+func bundleDownloadURL(id uint) string {
+	return fmt.Sprintf("/api/v1/export/bundle/%d/download", id)
+}
+
+// Request starts generating a bundle for the period, by default the last 12
+// weeks, and returns it while still pending. The user is notified once it is
+// ready.
+func (s *ExportBundleService) Request(ctx context.Context, tenantID, userID uint, from, to string) (*ExportBundleStatus, error) {
+	period, err := parsePeriod(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pending bundles expire too, so one lost to a restart is cleaned up
+	expiresAt := time.Now().Add(s.ttl)
+	bundle := &repository.ExportBundle{
+		TenantID:      tenantID,
+		RequestedByID: userID,
+		PeriodStart:   period.From,
+		PeriodEnd:     period.To,
+		Status:        repository.BundlePending,
+		ExpiresAt:     &expiresAt,
+	}
+	if err := s.repo.CreateExportBundle(ctx, bundle); err != nil {
+		return nil, err
+	}
+
+	generated := *bundle
+	go s.Generate(repository.Detach(ctx), &generated)
+	return bundleStatus(bundle), nil
+}
+
+// Get returns a bundle of the user's
+func (s *ExportBundleService) Get(ctx context.Context, id, tenantID, userID uint) (*ExportBundleStatus, error) {
+	bundle, err := s.bundle(ctx, id, tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return bundleStatus(bundle), nil
+}
+
+// BundleDownload is either the archive of a bundle or, when the store
+// presigns URLs, a URL downloading it directly
+type BundleDownload struct {
+	FileName string
+	File     io.ReadCloser
+	URL      string
+}
+
+// Open returns the download of a ready bundle of the user's
+func (s *ExportBundleService) Open(ctx context.Context, id, tenantID, userID uint) (*BundleDownload, error) {
+	bundle, err := s.bundle(ctx, id, tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if bundle.Status != repository.BundleReady {
+		return nil, ErrBundleNotReady
+	}
+
+	download := &BundleDownload{
+		FileName: fmt.Sprintf("export-%s-%s.zip", bundle.PeriodStart.Format("20060102"), bundle.PeriodEnd.Format("20060102")),
+	}
+	if presigner, ok := s.files.(storage.Presigner); ok {
+		download.URL, err = presigner.PresignGet(ctx, bundle.StoragePath, s.urlTTL, storage.Download{
+			FileName:    download.FileName,
+			ContentType: "application/zip",
+		})
+		if err != nil {
+			return nil, err
+		}
+		return download, nil
+	}
+	download.File, err = s.files.Open(ctx, bundle.StoragePath)
+	if errors.Is(err, storage.ErrNotExist) {
+		return nil, ErrExportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return download, nil
+}
+
+// bundle returns an unexpired bundle the user requested
+func (s *ExportBundleService) bundle(ctx context.Context, id, tenantID, userID uint) (*repository.ExportBundle, error) {
+	bundle, err := s.repo.GetExportBundleByID(ctx, id)
+	if err != nil {
+		return nil, ErrExportNotFound
+	}
+	if bundle.TenantID != tenantID || bundle.RequestedByID != userID {
+		return nil, ErrExportNotFound
+	}
+	if bundle.ExpiresAt != nil && time.Now().After(*bundle.ExpiresAt) {
+		return nil, ErrExportNotFound
+	}
+	return bundle, nil
+}
+
+// Generate writes the bundle's archive to storage, records whether that
+// succeeded and notifies the user who requested it
+func (s *ExportBundleService) Generate(ctx context.Context, bundle *repository.ExportBundle) error {
+	key := fmt.Sprintf("%d/bundles/%d.zip", bundle.TenantID, bundle.ID)
+	size, err := s.write(ctx, bundle, key)
+
+	now := time.Now()
+	bundle.CompletedAt = &now
+	if err != nil {
+		logging.Entry(ctx, s.logger).WithError(err).WithField("bundle_id", bundle.ID).Error("Failed to generate export bundle")
+		bundle.Status = repository.BundleFailed
+		bundle.Error = err.Error()
+	} else {
+		expiresAt := now.Add(s.ttl)
+		bundle.Status = repository.BundleReady
+		bundle.StoragePath = key
+		bundle.Size = size
+		bundle.ExpiresAt = &expiresAt
+	}
+	if updateErr := s.repo.UpdateExportBundle(ctx, bundle); updateErr != nil {
+		return errors.Join(err, updateErr)
+	}
+	s.notifications.NotifyExportBundle(ctx, bundle)
+	return err
+}
+
+// write builds the archive in a temporary file, as the photos can be large,
+// and stores it under the key. It returns the archive's size.
+func (s *ExportBundleService) write(ctx context.Context, bundle *repository.ExportBundle, key string) (int64, error) {
+	tmp, err := os.CreateTemp("", "export-bundle-*.zip")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	zw := zip.NewWriter(tmp)
+	if err := s.writeEntries(ctx, zw, bundle); err != nil {
+		return 0, err
+	}
+	if err := zw.Close(); err != nil {
+		return 0, err
+	}
+
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	if err := s.files.Put(ctx, key, tmp, "application/zip"); err != nil {
+		return 0, err
+	}
+	return size, nil
+}
+
+// writeEntries adds the CSV and XLSX exports of the reports for weeks
+// starting in the period and of the expenses and deposits dated in it, the
+// downloadable photos of those reports, and files.csv listing every file.
+// Receipts and deposit proofs are links to other services, so files.csv
+// lists their URLs rather than the archive fetching them.
+func (s *ExportBundleService) writeEntries(ctx context.Context, zw *zip.Writer, bundle *repository.ExportBundle) error {
+	inPeriod := func(t time.Time) bool {
+		return !t.Before(bundle.PeriodStart) && t.Before(bundle.PeriodEnd.AddDate(0, 0, 1))
+	}
+
+	reports, err := s.repo.GetReportExportRows(ctx, bundle.TenantID, 0)
+	if err != nil {
+		return err
+	}
+	var periodReports []repository.ReportExportRow
+	for _, report := range reports {
+		if inPeriod(report.WeekStartDate) {
+			periodReports = append(periodReports, report)
+		}
+	}
+
+	expenses, err := s.repo.GetExpenseExportRows(ctx, bundle.TenantID)
+	if err != nil {
+		return err
+	}
+	var periodExpenses []repository.ExpenseExportRow
+	for _, expense := range expenses {
+		if inPeriod(expense.Date) {
+			periodExpenses = append(periodExpenses, expense)
+		}
+	}
+
+	deposits, err := s.repo.GetDepositsByTenant(ctx, bundle.TenantID)
+	if err != nil {
+		return err
+	}
+	var periodDeposits []repository.BankDeposit
+	for _, deposit := range deposits {
+		if inPeriod(deposit.DepositDate) {
+			periodDeposits = append(periodDeposits, deposit)
+		}
+	}
+
+	sheets := []struct {
+		name    string
+		sheet   string
+		section export.Section
+	}{
+		{"reports", "Reports", ReportSection(periodReports)},
+		{"expenses", "Expenses", ExpenseSection(periodExpenses)},
+		{"deposits", "Deposits", DepositSection(periodDeposits)},
+	}
+	for _, sheet := range sheets {
+		section := sheet.section
+		if err := s.branding.ApplyTemplate(ctx, bundle.TenantID, sheet.name, &section); err != nil {
+			return err
+		}
+		w, err := zw.Create(sheet.name + ".csv")
+		if err != nil {
+			return err
+		}
+		if err := export.WriteCSV(w, section); err != nil {
+			return fmt.Errorf("write %s.csv: %w", sheet.name, err)
+		}
+
+		doc := export.Document{Sheet: sheet.sheet, Sections: []export.Section{section}}
+		if err := s.branding.Brand(ctx, bundle.TenantID, bundle.RequestedByID, &doc); err != nil {
+			return err
+		}
+		w, err = zw.Create(sheet.name + ".xlsx")
+		if err != nil {
+			return err
+		}
+		if err := export.WriteXLSX(w, doc); err != nil {
+			return fmt.Errorf("write %s.xlsx: %w", sheet.name, err)
+		}
+	}
+
+	files := export.Section{Headers: []string{"record", "record_id", "file", "url", "note"}}
+	attachments, err := s.repo.GetPeriodReportAttachments(ctx, bundle.TenantID, bundle.PeriodStart, bundle.PeriodEnd)
+	if err != nil {
+		return err
+	}
+	for i := range attachments {
+		attachment := &attachments[i]
+		if err := downloadable(attachment); err != nil {
+			files.Rows = append(files.Rows, []interface{}{"report", attachment.ReportID, "", "", err.Error()})
+			continue
+		}
+		name := path.Join("photos", strconv.FormatUint(uint64(attachment.ReportID), 10), fmt.Sprintf("%d-%s", attachment.ID, attachment.FileName))
+		if err := s.addFile(ctx, zw, name, attachment.StoragePath); err != nil {
+			return fmt.Errorf("attachment %d: %w", attachment.ID, err)
+		}
+		files.Rows = append(files.Rows, []interface{}{"report", attachment.ReportID, name, "", ""})
+	}
+	for _, expense := range periodExpenses {
+		if expense.ReceiptURL != "" {
+			files.Rows = append(files.Rows, []interface{}{"expense", expense.ID, "", expense.ReceiptURL, ""})
+		}
+	}
+	for _, deposit := range periodDeposits {
+		if deposit.ProofURL != "" {
+			files.Rows = append(files.Rows, []interface{}{"deposit", deposit.ID, "", deposit.ProofURL, ""})
+		}
+	}
+
+	w, err := zw.Create("files.csv")
+	if err != nil {
+		return err
+	}
+	return export.WriteCSV(w, files)
+}
+
+// addFile copies a stored file into the archive
+func (s *ExportBundleService) addFile(ctx context.Context, zw *zip.Writer, name, key string) error {
+	file, err := s.files.Open(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// Photos are already compressed
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store, Modified: time.Now()})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, file)
+	return err
+}
+
+// Cleanup removes expired bundles and their archives and returns how many
+// were removed
+func (s *ExportBundleService) Cleanup(ctx context.Context) (int, error) {
+	bundles, err := s.repo.GetExpiredExportBundles(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, bundle := range bundles {
+		if bundle.StoragePath != "" {
+			if err := s.files.Delete(ctx, bundle.StoragePath); err != nil {
+				return removed, err
+			}
+		}
+		if err := s.repo.DeleteExportBundle(ctx, bundle.ID); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
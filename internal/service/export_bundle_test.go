@@ -0,0 +1,201 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"taxifleet/backend/internal/push"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+	"taxifleet/backend/internal/storage"
+
+	"github.com/sirupsen/logrus"
+	"go.uber.org/mock/gomock"
+)
+
+type exportBundleRepoMock struct {
+	*mocks.MockExportBundleRepo
+	*mocks.MockReportRepo
+	*mocks.MockExpenseRepo
+	*mocks.MockDepositRepo
+	*mocks.MockTenantRepo
+	*mocks.MockUserRepo
+	*mocks.MockTaxiRepo
+	*mocks.MockDeviceTokenRepo
+	*mocks.MockInboxRepo
+}
+
+func newExportBundleServiceMock(t *testing.T) (*ExportBundleService, exportBundleRepoMock) {
+	ctrl := gomock.NewController(t)
+	repo := exportBundleRepoMock{
+		MockExportBundleRepo: mocks.NewMockExportBundleRepo(ctrl),
+		MockReportRepo:       mocks.NewMockReportRepo(ctrl),
+		MockExpenseRepo:      mocks.NewMockExpenseRepo(ctrl),
+		MockDepositRepo:      mocks.NewMockDepositRepo(ctrl),
+		MockTenantRepo:       mocks.NewMockTenantRepo(ctrl),
+		MockUserRepo:         mocks.NewMockUserRepo(ctrl),
+		MockTaxiRepo:         mocks.NewMockTaxiRepo(ctrl),
+		MockDeviceTokenRepo:  mocks.NewMockDeviceTokenRepo(ctrl),
+		MockInboxRepo:        mocks.NewMockInboxRepo(ctrl),
+	}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	notifications := NewNotificationService(repo, push.NoopSender{}, logger)
+	svc := NewExportBundleService(repo, NewBrandingService(repo), notifications, storage.NewLocal(t.TempDir()), time.Hour, time.Minute, logger)
+	return svc, repo
+}
+
+func TestExportBundleGenerateArchivesPeriod(t *testing.T) {
+	svc, repo := newExportBundleServiceMock(t)
+	ctx := context.Background()
+	if err := svc.files.Put(ctx, "1/cash.jpg", strings.NewReader("photo"), "image/jpeg"); err != nil {
+		t.Fatalf("failed to store test photo: %v", err)
+	}
+
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+	bundle := &repository.ExportBundle{ID: 5, TenantID: 1, RequestedByID: 9, PeriodStart: day(1), PeriodEnd: day(31), Status: repository.BundlePending}
+
+	repo.MockTenantRepo.EXPECT().GetTenantByID(gomock.Any(), uint(1)).Return(&repository.Tenant{ID: 1}, nil).AnyTimes()
+	repo.MockReportRepo.EXPECT().GetReportExportRows(gomock.Any(), uint(1), uint(0)).Return([]repository.ReportExportRow{
+		{ID: 1, WeekStartDate: day(2)},
+		{ID: 2, WeekStartDate: day(2).AddDate(0, 1, 0)},
+	}, nil)
+	repo.MockExpenseRepo.EXPECT().GetExpenseExportRows(gomock.Any(), uint(1)).Return([]repository.ExpenseExportRow{
+		{ID: 3, Date: day(31), ReceiptURL: "https://receipts.example.com/3.pdf"},
+		{ID: 4, Date: day(1).AddDate(0, 0, -1), ReceiptURL: "https://receipts.example.com/4.pdf"},
+	}, nil)
+	repo.MockDepositRepo.EXPECT().GetDepositsByTenant(gomock.Any(), uint(1)).Return([]repository.BankDeposit{{ID: 6, DepositDate: day(15)}}, nil)
+	repo.MockExportBundleRepo.EXPECT().GetPeriodReportAttachments(gomock.Any(), uint(1), day(1), day(31)).Return([]repository.ReportAttachment{
+		{ID: 7, ReportID: 1, FileName: "cash.jpg", StoragePath: "1/cash.jpg", ScanStatus: repository.ScanClean},
+		{ID: 8, ReportID: 1, FileName: "virus.jpg", StoragePath: "1/virus.jpg", ScanStatus: repository.ScanQuarantined},
+	}, nil)
+	repo.MockExportBundleRepo.EXPECT().UpdateExportBundle(gomock.Any(), bundle).Return(nil)
+	notified := make(chan *repository.Notification, 1)
+	repo.MockInboxRepo.EXPECT().CreateNotification(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, notification *repository.Notification) error {
+		notified <- notification
+		return nil
+	})
+	repo.MockDeviceTokenRepo.EXPECT().GetDeviceTokensByUser(gomock.Any(), uint(9)).Return(nil, nil)
+
+	if err := svc.Generate(ctx, bundle); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bundle.Status != repository.BundleReady || bundle.StoragePath != "1/bundles/5.zip" || bundle.Size == 0 {
+		t.Fatalf("expected the bundle to be ready, got %+v", bundle)
+	}
+
+	file, err := svc.files.Open(ctx, bundle.StoragePath)
+	if err != nil {
+		t.Fatalf("expected the archive in storage, got %v", err)
+	}
+	defer file.Close()
+	data, _ := io.ReadAll(file)
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("expected a ZIP archive, got %v", err)
+	}
+	entries := make(map[string]string)
+	for _, f := range archive.File {
+		r, _ := f.Open()
+		content, _ := io.ReadAll(r)
+		r.Close()
+		entries[f.Name] = string(content)
+	}
+
+	for _, name := range []string{"reports.csv", "reports.xlsx", "expenses.csv", "expenses.xlsx", "deposits.csv", "deposits.xlsx", "files.csv"} {
+		if _, ok := entries[name]; !ok {
+			t.Errorf("expected %s in the archive", name)
+		}
+	}
+	if entries["photos/1/7-cash.jpg"] != "photo" {
+		t.Errorf("expected the clean photo in the archive, got %q", entries["photos/1/7-cash.jpg"])
+	}
+	if strings.Count(entries["reports.csv"], "\n") != 2 || strings.Count(entries["expenses.csv"], "\n") != 2 {
+		t.Errorf("expected one report and one expense in the period, got %q and %q", entries["reports.csv"], entries["expenses.csv"])
+	}
+	files := entries["files.csv"]
+	if !strings.Contains(files, "receipts.example.com/3.pdf") || strings.Contains(files, "receipts.example.com/4.pdf") {
+		t.Errorf("expected only receipts of the period to be listed, got %q", files)
+	}
+	if !strings.Contains(files, ErrAttachmentQuarantined.Error()) {
+		t.Errorf("expected the quarantined photo to be listed as left out, got %q", files)
+	}
+
+	select {
+	case notification := <-notified:
+		if notification.UserID != 9 || notification.Data["download_url"] != "/api/v1/export/bundle/5/download" {
+			t.Fatalf("expected the requester to get the download link, got %+v", notification)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the requester to be notified")
+	}
+}
+
+func TestExportBundleOpenOnlyForRequester(t *testing.T) {
+	svc, repo := newExportBundleServiceMock(t)
+	ctx := context.Background()
+	if err := svc.files.Put(ctx, "1/bundles/5.zip", strings.NewReader("zip"), "application/zip"); err != nil {
+		t.Fatalf("failed to store test bundle: %v", err)
+	}
+	expiresAt := time.Now().Add(time.Hour)
+	bundle := &repository.ExportBundle{
+		ID: 5, TenantID: 1, RequestedByID: 9, Status: repository.BundlePending, StoragePath: "1/bundles/5.zip", ExpiresAt: &expiresAt,
+		PeriodStart: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), PeriodEnd: time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+	}
+	repo.MockExportBundleRepo.EXPECT().GetExportBundleByID(gomock.Any(), uint(5)).Return(bundle, nil).AnyTimes()
+
+	if _, err := svc.Open(ctx, 5, 1, 9); !errors.Is(err, ErrBundleNotReady) {
+		t.Fatalf("expected a pending bundle not to be downloadable, got %v", err)
+	}
+
+	bundle.Status = repository.BundleReady
+	if _, err := svc.Open(ctx, 5, 1, 10); !errors.Is(err, ErrExportNotFound) {
+		t.Fatalf("expected another user's bundle to be not found, got %v", err)
+	}
+	if _, err := svc.Open(ctx, 5, 2, 9); !errors.Is(err, ErrExportNotFound) {
+		t.Fatalf("expected another tenant's bundle to be not found, got %v", err)
+	}
+
+	download, err := svc.Open(ctx, 5, 1, 9)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer download.File.Close()
+	if download.FileName != "export-20260301-20260331.zip" || download.URL != "" {
+		t.Fatalf("expected the archive to be streamed from local storage, got %+v", download)
+	}
+
+	expired := time.Now().Add(-time.Minute)
+	bundle.ExpiresAt = &expired
+	if _, err := svc.Open(ctx, 5, 1, 9); !errors.Is(err, ErrExportNotFound) {
+		t.Fatalf("expected an expired bundle to be not found, got %v", err)
+	}
+}
+
+func TestExportBundleCleanupRemovesArchives(t *testing.T) {
+	svc, repo := newExportBundleServiceMock(t)
+	ctx := context.Background()
+	if err := svc.files.Put(ctx, "1/bundles/5.zip", strings.NewReader("zip"), "application/zip"); err != nil {
+		t.Fatalf("failed to store test bundle: %v", err)
+	}
+	repo.MockExportBundleRepo.EXPECT().GetExpiredExportBundles(gomock.Any(), gomock.Any()).Return([]repository.ExportBundle{
+		{ID: 5, TenantID: 1, StoragePath: "1/bundles/5.zip"},
+		{ID: 6, TenantID: 1}, // Never finished
+	}, nil)
+	repo.MockExportBundleRepo.EXPECT().DeleteExportBundle(gomock.Any(), uint(5)).Return(nil)
+	repo.MockExportBundleRepo.EXPECT().DeleteExportBundle(gomock.Any(), uint(6)).Return(nil)
+
+	removed, err := svc.Cleanup(ctx)
+	if err != nil || removed != 2 {
+		t.Fatalf("expected 2 bundles removed, got %d, %v", removed, err)
+	}
+	if _, err := svc.files.Open(ctx, "1/bundles/5.zip"); !errors.Is(err, storage.ErrNotExist) {
+		t.Fatalf("expected the archive to be deleted, got %v", err)
+	}
+}
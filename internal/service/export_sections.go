@@ -0,0 +1,99 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"taxifleet/backend/internal/export"
+	"taxifleet/backend/internal/repository"
+)
+
+// The sections below are the CSV and XLSX exports of reports, expenses and
+// deposits before the tenant's export template is applied. Both the export
+// endpoints and export bundles build them here so they stay identical.
+
+// ReportSection lays out report export rows
+func ReportSection(reports []repository.ReportExportRow) export.Section {
+	section := export.Section{
+		Keys:     []string{"id", "week_start", "taxi", "driver", "earnings", "expenses", "adjustments", "driver_share", "owner_share", "ledger_offset", "status", "notes", "signature", "created_at"},
+		Headers:  []string{"ID", "Week Start", "Taxi", "Driver", "Earnings", "Expenses", "Adjustments", "Driver Share", "Owner Share", "Ledger Offset", "Status", "Notes", "Signature", "Created At"},
+		Summable: true,
+	}
+	for _, report := range reports {
+		section.Rows = append(section.Rows, []interface{}{
+			report.ID,
+			exportDate(report.WeekStartDate),
+			report.LicensePlate,
+			report.DriverFirstName + " " + report.DriverLastName,
+			report.Earnings,
+			report.TotalExpenses,
+			report.TotalAdjustments,
+			optionalAmount(report.DriverShare),
+			optionalAmount(report.OwnerShare),
+			optionalAmount(report.LedgerOffset),
+			report.Status,
+			report.Notes,
+			report.SignatureMethod,
+			exportDate(report.CreatedAt),
+		})
+	}
+	return section
+}
+
+// ExpenseSection lays out expense export rows
+func ExpenseSection(expenses []repository.ExpenseExportRow) export.Section {
+	section := export.Section{
+		Keys:     []string{"id", "date", "category", "amount", "taxi", "reason", "created_at"},
+		Headers:  []string{"ID", "Date", "Category", "Amount", "Taxi", "Reason", "Created At"},
+		Summable: true,
+	}
+	for _, expense := range expenses {
+		section.Rows = append(section.Rows, []interface{}{
+			expense.ID,
+			exportDate(expense.Date),
+			expense.Category,
+			expense.Amount,
+			expense.LicensePlate,
+			expense.Reason,
+			exportDate(expense.CreatedAt),
+		})
+	}
+	return section
+}
+
+// DepositSection lays out deposits
+func DepositSection(deposits []repository.BankDeposit) export.Section {
+	section := export.Section{
+		Keys:     []string{"id", "deposit_date", "amount", "bank_account", "period_start", "period_end", "status", "notes", "created_at"},
+		Headers:  []string{"ID", "Deposit Date", "Amount", "Bank Account", "Period Start", "Period End", "Status", "Notes", "Created At"},
+		Summable: true,
+	}
+	for _, deposit := range deposits {
+		section.Rows = append(section.Rows, []interface{}{
+			deposit.ID,
+			exportDate(deposit.DepositDate),
+			deposit.Amount,
+			depositAccount(deposit),
+			exportDate(deposit.PeriodStart),
+			exportDate(deposit.PeriodEnd),
+			deposit.Status,
+			deposit.Notes,
+			exportDate(deposit.CreatedAt),
+		})
+	}
+	return section
+}
+
+// depositAccount names the account a deposit was made into, falling back to
+// the free text of deposits recorded before bank accounts
+func depositAccount(deposit repository.BankDeposit) string {
+	if deposit.Account != nil {
+		return deposit.Account.Label
+	}
+	return deposit.BankAccount
+}
+
+// exportDate formats a date as dd/mm/yyyy
+func exportDate(t time.Time) string {
+	return fmt.Sprintf("%02d/%02d/%d", t.Day(), t.Month(), t.Year())
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"taxifleet/backend/internal/export"
+	"taxifleet/backend/internal/validation"
+)
+
+// ExportTemplate picks, orders and labels the columns of a list export
+type ExportTemplate struct {
+	Columns  []ExportColumn `json:"columns"`  // Empty keeps every column in the default order
+	Language string         `json:"language"` // Language of the default header labels, defaults to en
+}
+
+// ExportColumn is a column of a templated export; Label overrides the header
+type ExportColumn struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+}
+
+// exportColumns are the column keys of each list export, in default order
+var exportColumns = map[string][]string{
+	"reports":  {"id", "week_start", "taxi", "driver", "earnings", "expenses", "adjustments", "driver_share", "owner_share", "ledger_offset", "status", "notes", "signature", "created_at"},
+	"expenses": {"id", "date", "category", "amount", "taxi", "reason", "created_at"},
+	"deposits": {"id", "deposit_date", "amount", "bank_account", "period_start", "period_end", "status", "notes", "created_at"},
+}
+
+// exportLabels are the header labels per language; English labels are the
+// exporters' own headers
+var exportLabels = map[string]map[string]string{
+	"de": {
+		"id": "ID", "week_start": "Wochenbeginn", "taxi": "Taxi", "driver": "Fahrer", "earnings": "Einnahmen",
+		"expenses": "Ausgaben", "adjustments": "Korrekturen", "driver_share": "Fahreranteil", "owner_share": "Halteranteil",
+		"ledger_offset": "Verrechnung", "status": "Status", "notes": "Notizen", "created_at": "Erstellt am",
+		"date": "Datum", "category": "Kategorie", "amount": "Betrag", "reason": "Grund", "deposit_date": "Einzahlungsdatum",
+		"bank_account": "Bankkonto", "period_start": "Zeitraum von", "period_end": "Zeitraum bis", "signature": "Unterschrift",
+	},
+	"fr": {
+		"id": "ID", "week_start": "Début de semaine", "taxi": "Taxi", "driver": "Chauffeur", "earnings": "Recettes",
+		"expenses": "Dépenses", "adjustments": "Ajustements", "driver_share": "Part chauffeur", "owner_share": "Part propriétaire",
+		"ledger_offset": "Compensation", "status": "Statut", "notes": "Notes", "created_at": "Créé le",
+		"date": "Date", "category": "Catégorie", "amount": "Montant", "reason": "Motif", "deposit_date": "Date de dépôt",
+		"bank_account": "Compte bancaire", "period_start": "Début de période", "period_end": "Fin de période", "signature": "Signature",
+	},
+	"es": {
+		"id": "ID", "week_start": "Inicio de semana", "taxi": "Taxi", "driver": "Conductor", "earnings": "Ingresos",
+		"expenses": "Gastos", "adjustments": "Ajustes", "driver_share": "Parte del conductor", "owner_share": "Parte del propietario",
+		"ledger_offset": "Compensación", "status": "Estado", "notes": "Notas", "created_at": "Creado el",
+		"date": "Fecha", "category": "Categoría", "amount": "Importe", "reason": "Motivo", "deposit_date": "Fecha de depósito",
+		"bank_account": "Cuenta bancaria", "period_start": "Inicio del periodo", "period_end": "Fin del periodo", "signature": "Firma",
+	},
+	"pt": {
+		"id": "ID", "week_start": "Início da semana", "taxi": "Táxi", "driver": "Motorista", "earnings": "Receitas",
+		"expenses": "Despesas", "adjustments": "Ajustes", "driver_share": "Parte do motorista", "owner_share": "Parte do proprietário",
+		"ledger_offset": "Compensação", "status": "Estado", "notes": "Notas", "created_at": "Criado em",
+		"date": "Data", "category": "Categoria", "amount": "Valor", "reason": "Motivo", "deposit_date": "Data do depósito",
+		"bank_account": "Conta bancária", "period_start": "Início do período", "period_end": "Fim do período", "signature": "Assinatura",
+	},
+}
+
+// checkExportTemplates validates the templates of the tenant settings
+func checkExportTemplates(templates map[string]ExportTemplate) error {
+	invalid := func(message string) error {
+		return &validation.FieldError{Field: "exports.templates", Rule: "export_template", Message: message}
+	}
+	for name, template := range templates {
+		keys, ok := exportColumns[name]
+		if !ok {
+			names := make([]string, 0, len(exportColumns))
+			for name := range exportColumns {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return invalid(fmt.Sprintf("exports.templates.%s is not an export; use %s", name, strings.Join(names, ", ")))
+		}
+		if _, ok := exportLabels[template.Language]; !ok && template.Language != "" && template.Language != "en" {
+			return invalid(fmt.Sprintf("exports.templates.%s.language must be en, de, fr, es or pt", name))
+		}
+		seen := make(map[string]bool, len(template.Columns))
+		for _, column := range template.Columns {
+			if !slices.Contains(keys, column.Key) {
+				return invalid(fmt.Sprintf("exports.templates.%s has no column %q; use %s", name, column.Key, strings.Join(keys, ", ")))
+			}
+			if seen[column.Key] {
+				return invalid(fmt.Sprintf("exports.templates.%s lists column %q twice", name, column.Key))
+			}
+			seen[column.Key] = true
+		}
+	}
+	return nil
+}
+
+// ApplyTemplate reshapes a list export's section to the tenant's template for
+// it, if any. The section's Keys name its columns.
+func (s *BrandingService) ApplyTemplate(ctx context.Context, tenantID uint, name string, section *export.Section) error {
+	tenant, err := s.repo.GetTenantByID(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	template, ok := parseTenantSettings(tenant.Settings).Exports.Templates[name]
+	if !ok {
+		return nil
+	}
+
+	columns := template.Columns
+	if len(columns) == 0 {
+		columns = make([]ExportColumn, len(section.Keys))
+		for i, key := range section.Keys {
+			columns[i] = ExportColumn{Key: key}
+		}
+	}
+
+	labels := exportLabels[template.Language]
+	indexes := make([]int, 0, len(columns))
+	headers := make([]string, 0, len(columns))
+	keys := make([]string, 0, len(columns))
+	for _, column := range columns {
+		index := slices.Index(section.Keys, column.Key)
+		if index < 0 {
+			continue
+		}
+		indexes = append(indexes, index)
+		keys = append(keys, column.Key)
+		switch {
+		case column.Label != "":
+			headers = append(headers, column.Label)
+		case labels[column.Key] != "":
+			headers = append(headers, labels[column.Key])
+		default:
+			headers = append(headers, section.Headers[index])
+		}
+	}
+
+	for i, values := range section.Rows {
+		row := make([]interface{}, len(indexes))
+		for j, index := range indexes {
+			if index < len(values) {
+				row[j] = values[index]
+			}
+		}
+		section.Rows[i] = row
+	}
+	section.Keys = keys
+	section.Headers = headers
+	return nil
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/validation"
+
+	"gorm.io/gorm"
+)
+
+// ErrFineCharged is returned when changing the amount or responsibility of a
+// fine, or deleting it, once it was charged to the driver
+var ErrFineCharged = errors.New("fine was already charged to the driver")
+
+// errNoDriverAtOffense is returned when a fine is made the driver's
+// responsibility but no driver was assigned to the taxi at the time
+var errNoDriverAtOffense = &validation.FieldError{
+	Field:   "responsibility",
+	Rule:    "responsibility",
+	Message: "no driver was assigned to the taxi at the time of the offense",
+}
+
+// FineRepository is the data access FineService depends on
+type FineRepository interface {
+	repository.Transactor
+	repository.FineRepo
+	repository.TaxiRepo
+	repository.AssignmentRepo
+	repository.DriverLedgerRepo
+}
+
+// FineService keeps track of traffic fines and charges those the driver is
+// responsible for to their ledger, so they are offset against their share of
+// approved reports
+type FineService struct {
+	repo FineRepository
+}
+
+func NewFineService(repo FineRepository) *FineService {
+	return &FineService{repo: repo}
+}
+
+type CreateFineRequest struct {
+	TaxiID      uint         `json:"taxi_id" binding:"required"`
+	OffenseAt   time.Time    `json:"offense_at" binding:"required"`
+	Reference   string       `json:"reference"`
+	Description string       `json:"description"`
+	Amount      money.Amount `json:"amount" binding:"required,amount"`
+	DueDate     string       `json:"due_date" binding:"required"` // YYYY-MM-DD
+
+	// Responsibility defaults to the driver when one was assigned at the time
+	Responsibility string `json:"responsibility" binding:"omitempty,oneof=driver owner"`
+}
+
+type UpdateFineRequest struct {
+	Reference      string       `json:"reference"`
+	Description    string       `json:"description"`
+	Amount         money.Amount `json:"amount" binding:"omitempty,amount"`
+	DueDate        string       `json:"due_date"`
+	Responsibility string       `json:"responsibility" binding:"omitempty,oneof=driver owner"`
+}
+
+// Create records a fine against the driver assigned to the taxi at the time
+// of the offense, charging it to them if they are responsible
+func (s *FineService) Create(ctx context.Context, tenantID uint, userID uint, req CreateFineRequest) (*repository.Fine, error) {
+	taxi, err := s.repo.GetTaxiByID(ctx, req.TaxiID)
+	if err != nil || taxi.TenantID != tenantID {
+		return nil, notFound("taxi not found")
+	}
+
+	dueDate, err := time.Parse("2006-01-02", req.DueDate)
+	if err != nil {
+		return nil, invalid("invalid due date format")
+	}
+
+	fine := &repository.Fine{
+		TenantID:       tenantID,
+		TaxiID:         taxi.ID,
+		OffenseAt:      req.OffenseAt,
+		Reference:      req.Reference,
+		Description:    req.Description,
+		Amount:         req.Amount,
+		DueDate:        dueDate,
+		Responsibility: req.Responsibility,
+		CreatedByID:    userID,
+	}
+
+	assignment, err := s.repo.GetAssignmentAt(ctx, taxi.ID, req.OffenseAt)
+	if err == nil {
+		fine.DriverID = &assignment.DriverID
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if fine.Responsibility == "" {
+		fine.Responsibility = "owner"
+		if fine.DriverID != nil {
+			fine.Responsibility = "driver"
+		}
+	}
+	if fine.Responsibility == "driver" && fine.DriverID == nil {
+		return nil, errNoDriverAtOffense
+	}
+
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateFine(ctx, fine); err != nil {
+			return err
+		}
+		if fine.Responsibility == "driver" {
+			return s.chargeDriver(ctx, fine, taxi, userID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetFineByID(ctx, fine.ID)
+}
+
+// chargeDriver adds the fine to its driver's ledger balance
+func (s *FineService) chargeDriver(ctx context.Context, fine *repository.Fine, taxi *repository.Taxi, userID uint) error {
+	fineID := fine.ID
+	return s.repo.CreateLedgerEntry(ctx, &repository.DriverLedgerEntry{
+		TenantID:    fine.TenantID,
+		DriverID:    *fine.DriverID,
+		Type:        "fine",
+		Amount:      fine.Amount,
+		FineID:      &fineID,
+		Notes:       "Traffic fine of " + fine.OffenseAt.Format("2006-01-02") + " for " + taxi.LicensePlate,
+		CreatedByID: userID,
+	})
+}
+
+func (s *FineService) GetByID(ctx context.Context, id uint, tenantID uint) (*repository.Fine, error) {
+	fine, err := s.repo.GetFineByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if fine.TenantID != tenantID {
+		return nil, notFound("fine not found")
+	}
+
+	return fine, nil
+}
+
+// List returns the tenant's fines, those due first. status paid or unpaid
+// keeps only those fines.
+func (s *FineService) List(ctx context.Context, tenantID uint, status string) ([]repository.Fine, error) {
+	fines, err := s.repo.GetFinesByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch status {
+	case "":
+		return fines, nil
+	case "paid", "unpaid":
+		return slices.DeleteFunc(fines, func(fine repository.Fine) bool {
+			return (fine.PaidAt != nil) != (status == "paid")
+		}), nil
+	default:
+		return nil, &validation.FieldError{Field: "status", Rule: "oneof", Message: "status must be paid or unpaid"}
+	}
+}
+
+// Update edits a fine. Making the driver responsible charges it to them; a
+// charged fine keeps its amount and responsibility.
+func (s *FineService) Update(ctx context.Context, id uint, tenantID uint, userID uint, req UpdateFineRequest) (*repository.Fine, error) {
+	fine, err := s.GetByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	charged := fine.Responsibility == "driver"
+	if charged && ((req.Amount != 0 && req.Amount != fine.Amount) || (req.Responsibility != "" && req.Responsibility != "driver")) {
+		return nil, ErrFineCharged
+	}
+
+	if req.Reference != "" {
+		fine.Reference = req.Reference
+	}
+	if req.Description != "" {
+		fine.Description = req.Description
+	}
+	if req.Amount != 0 {
+		fine.Amount = req.Amount
+	}
+	if req.DueDate != "" {
+		dueDate, err := time.Parse("2006-01-02", req.DueDate)
+		if err != nil {
+			return nil, invalid("invalid due date format")
+		}
+		fine.DueDate = dueDate
+	}
+	if req.Responsibility != "" {
+		fine.Responsibility = req.Responsibility
+	}
+	if fine.Responsibility == "driver" && fine.DriverID == nil {
+		return nil, errNoDriverAtOffense
+	}
+
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdateFine(ctx, fine); err != nil {
+			return err
+		}
+		if !charged && fine.Responsibility == "driver" {
+			return s.chargeDriver(ctx, fine, &fine.Taxi, userID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetFineByID(ctx, fine.ID)
+}
+
+// MarkPaid records that the fine was paid to the authority; paying it again
+// keeps the first payment time
+func (s *FineService) MarkPaid(ctx context.Context, id uint, tenantID uint) (*repository.Fine, error) {
+	fine, err := s.GetByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if fine.PaidAt != nil {
+		return fine, nil
+	}
+
+	now := time.Now()
+	fine.PaidAt = &now
+	if err := s.repo.UpdateFine(ctx, fine); err != nil {
+		return nil, err
+	}
+	return fine, nil
+}
+
+// Delete removes a fine that was not charged to the driver
+func (s *FineService) Delete(ctx context.Context, id uint, tenantID uint) error {
+	fine, err := s.GetByID(ctx, id, tenantID)
+	if err != nil {
+		return err
+	}
+	if fine.Responsibility == "driver" {
+		return ErrFineCharged
+	}
+	return s.repo.DeleteFine(ctx, id)
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+type fineRepoMock struct {
+	*mocks.MockTransactor
+	*mocks.MockFineRepo
+	*mocks.MockTaxiRepo
+	*mocks.MockAssignmentRepo
+	*mocks.MockDriverLedgerRepo
+}
+
+func newFineServiceMock(t *testing.T) (*FineService, fineRepoMock) {
+	ctrl := gomock.NewController(t)
+	repo := fineRepoMock{
+		MockTransactor:       mocks.NewMockTransactor(ctrl),
+		MockFineRepo:         mocks.NewMockFineRepo(ctrl),
+		MockTaxiRepo:         mocks.NewMockTaxiRepo(ctrl),
+		MockAssignmentRepo:   mocks.NewMockAssignmentRepo(ctrl),
+		MockDriverLedgerRepo: mocks.NewMockDriverLedgerRepo(ctrl),
+	}
+	repo.MockTransactor.EXPECT().InTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}).AnyTimes()
+	return NewFineService(repo), repo
+}
+
+func TestFineCreateChargesDriverAssignedAtOffense(t *testing.T) {
+	svc, repo := newFineServiceMock(t)
+	offenseAt := time.Date(2024, 3, 5, 14, 30, 0, 0, time.UTC)
+
+	repo.MockTaxiRepo.EXPECT().GetTaxiByID(gomock.Any(), uint(5)).Return(&repository.Taxi{ID: 5, TenantID: 1, LicensePlate: "AB-123"}, nil)
+	repo.MockAssignmentRepo.EXPECT().GetAssignmentAt(gomock.Any(), uint(5), offenseAt).Return(&repository.Assignment{TaxiID: 5, DriverID: 7}, nil)
+	repo.MockFineRepo.EXPECT().CreateFine(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, fine *repository.Fine) error {
+		if fine.DriverID == nil || *fine.DriverID != 7 || fine.Responsibility != "driver" {
+			t.Errorf("expected driver 7 to be responsible, got %+v", fine)
+		}
+		fine.ID = 3
+		return nil
+	})
+	repo.MockDriverLedgerRepo.EXPECT().CreateLedgerEntry(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, entry *repository.DriverLedgerEntry) error {
+		if entry.DriverID != 7 || entry.Type != "fine" || entry.Amount != 90_00 || entry.FineID == nil || *entry.FineID != 3 {
+			t.Errorf("unexpected ledger entry %+v", entry)
+		}
+		return nil
+	})
+	repo.MockFineRepo.EXPECT().GetFineByID(gomock.Any(), uint(3)).Return(&repository.Fine{ID: 3, TenantID: 1}, nil)
+
+	_, err := svc.Create(context.Background(), 1, 10, CreateFineRequest{TaxiID: 5, OffenseAt: offenseAt, Amount: 90_00, DueDate: "2024-04-05"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestFineCreateWithoutAssignedDriverIsOwners(t *testing.T) {
+	svc, repo := newFineServiceMock(t)
+	offenseAt := time.Date(2024, 3, 5, 14, 30, 0, 0, time.UTC)
+
+	repo.MockTaxiRepo.EXPECT().GetTaxiByID(gomock.Any(), uint(5)).Return(&repository.Taxi{ID: 5, TenantID: 1}, nil).Times(2)
+	repo.MockAssignmentRepo.EXPECT().GetAssignmentAt(gomock.Any(), uint(5), offenseAt).Return(nil, gorm.ErrRecordNotFound).Times(2)
+
+	_, err := svc.Create(context.Background(), 1, 10, CreateFineRequest{TaxiID: 5, OffenseAt: offenseAt, Amount: 90_00, DueDate: "2024-04-05", Responsibility: "driver"})
+	if !errors.Is(err, errNoDriverAtOffense) {
+		t.Fatalf("expected errNoDriverAtOffense, got %v", err)
+	}
+
+	repo.MockFineRepo.EXPECT().CreateFine(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, fine *repository.Fine) error {
+		if fine.DriverID != nil || fine.Responsibility != "owner" {
+			t.Errorf("expected an owner's fine without driver, got %+v", fine)
+		}
+		fine.ID = 3
+		return nil
+	})
+	repo.MockFineRepo.EXPECT().GetFineByID(gomock.Any(), uint(3)).Return(&repository.Fine{ID: 3, TenantID: 1}, nil)
+
+	if _, err := svc.Create(context.Background(), 1, 10, CreateFineRequest{TaxiID: 5, OffenseAt: offenseAt, Amount: 90_00, DueDate: "2024-04-05"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestFineUpdateKeepsChargedAmount(t *testing.T) {
+	svc, repo := newFineServiceMock(t)
+	driverID := uint(7)
+	repo.MockFineRepo.EXPECT().GetFineByID(gomock.Any(), uint(3)).Return(&repository.Fine{ID: 3, TenantID: 1, DriverID: &driverID, Amount: 90_00, Responsibility: "driver"}, nil)
+
+	_, err := svc.Update(context.Background(), 3, 1, 10, UpdateFineRequest{Amount: 45_00})
+	if !errors.Is(err, ErrFineCharged) {
+		t.Fatalf("expected ErrFineCharged, got %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"taxifleet/backend/internal/repository"
+)
+
+// FleetRepository is the data access FleetService depends on
+type FleetRepository interface {
+	repository.Transactor
+	repository.TaxiRepo
+	repository.TenantRepo
+	repository.DigestRepo
+	repository.MaintenanceRepo
+	repository.PositionRepo
+}
+
+// FleetService builds the at-a-glance status board of the fleet
+type FleetService struct {
+	repo FleetRepository
+}
+
+func NewFleetService(repo FleetRepository) *FleetService {
+	return &FleetService{repo: repo}
+}
+
+// FleetStatus is the status board: one row per taxi, by license plate
+type FleetStatus struct {
+	WeekStartDate time.Time        `json:"week_start_date"` // The current reporting week
+	Taxis         []FleetStatusRow `json:"taxis"`
+}
+
+type FleetStatusRow struct {
+	TaxiID       uint   `json:"taxi_id"`
+	LicensePlate string `json:"license_plate"`
+	Status       string `json:"status"` // The taxi's: active, maintenance, inactive
+	DriverID     *uint  `json:"driver_id"`
+	DriverName   string `json:"driver_name,omitempty"`
+	// LastSeenAt is when the taxi last reported its position; nil if never
+	LastSeenAt  *time.Time `json:"last_seen_at"`
+	OutsideZone bool       `json:"outside_zone"`
+	// ReportStatus is the status of the current week's report, "missing"
+	// while there is none
+	ReportStatus   string   `json:"report_status"`
+	MaintenanceDue []string `json:"maintenance_due"` // Tasks currently due
+}
+
+// ScopedStatus is Status in a short transaction scoped to the tenant by
+// row-level security, for the status stream: it runs outside a request
+// transaction, which would stay open as long as the client is connected
+func (s *FleetService) ScopedStatus(ctx context.Context, tenantID uint) (status *FleetStatus, err error) {
+	err = s.repo.InTransaction(repository.WithTenant(ctx, tenantID), func(ctx context.Context) error {
+		status, err = s.Status(ctx, tenantID)
+		return err
+	})
+	return status, err
+}
+
+// Status returns the current status of each of the tenant's taxis
+func (s *FleetService) Status(ctx context.Context, tenantID uint) (*FleetStatus, error) {
+	tenant, err := s.repo.GetTenantByID(ctx, tenantID)
+	if err != nil {
+		return nil, notFound("tenant not found")
+	}
+	now := time.Now()
+	weekStart := startOfWeek(now, parseTenantSettings(tenant.Settings).WeekStart())
+
+	taxis, err := s.repo.GetTaxisByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	reports, err := s.repo.GetReportsForWeek(ctx, tenantID, weekStart)
+	if err != nil {
+		return nil, err
+	}
+	schedules, err := s.repo.GetMaintenanceSchedulesByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	positions, err := s.repo.GetLatestPositions(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	lastPositions := make(map[uint]*repository.TaxiPosition, len(positions))
+	for i := range positions {
+		lastPositions[positions[i].TaxiID] = &positions[i]
+	}
+	due := make(map[uint][]string)
+	for _, schedule := range schedules {
+		if _, ok := checkDue(schedule, now); ok {
+			due[schedule.TaxiID] = append(due[schedule.TaxiID], schedule.Task)
+		}
+	}
+
+	rows := make([]FleetStatusRow, 0, len(taxis))
+	for _, taxi := range taxis {
+		row := FleetStatusRow{
+			TaxiID:         taxi.ID,
+			LicensePlate:   taxi.LicensePlate,
+			Status:         taxi.Status,
+			DriverID:       taxi.AssignedDriverID,
+			ReportStatus:   weekReportStatus(reports, &taxi),
+			MaintenanceDue: []string{},
+		}
+		if tasks, ok := due[taxi.ID]; ok {
+			row.MaintenanceDue = tasks
+		}
+		if taxi.AssignedDriver != nil {
+			row.DriverName = taxi.AssignedDriver.FirstName + " " + taxi.AssignedDriver.LastName
+		}
+		if position, ok := lastPositions[taxi.ID]; ok {
+			row.LastSeenAt = &position.RecordedAt
+			row.OutsideZone = position.OutsideZone
+		}
+		rows = append(rows, row)
+	}
+	slices.SortFunc(rows, func(a, b FleetStatusRow) int { return cmp.Compare(a.LicensePlate, b.LicensePlate) })
+
+	return &FleetStatus{WeekStartDate: weekStart, Taxis: rows}, nil
+}
+
+// weekReportStatus is the status of the taxi's report for the week, preferring
+// the one of its current driver
+func weekReportStatus(reports []repository.WeeklyReport, taxi *repository.Taxi) string {
+	status := "missing"
+	for _, report := range reports {
+		if report.TaxiID != taxi.ID {
+			continue
+		}
+		if taxi.AssignedDriverID != nil && report.DriverID == *taxi.AssignedDriverID {
+			return report.Status
+		}
+		status = report.Status
+	}
+	return status
+}
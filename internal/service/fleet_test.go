@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+
+	"go.uber.org/mock/gomock"
+)
+
+type fleetRepoMock struct {
+	*mocks.MockTransactor
+	*mocks.MockTaxiRepo
+	*mocks.MockTenantRepo
+	*mocks.MockDigestRepo
+	*mocks.MockMaintenanceRepo
+	*mocks.MockPositionRepo
+}
+
+func TestFleetStatusRows(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := fleetRepoMock{
+		MockTransactor:      mocks.NewMockTransactor(ctrl),
+		MockTaxiRepo:        mocks.NewMockTaxiRepo(ctrl),
+		MockTenantRepo:      mocks.NewMockTenantRepo(ctrl),
+		MockDigestRepo:      mocks.NewMockDigestRepo(ctrl),
+		MockMaintenanceRepo: mocks.NewMockMaintenanceRepo(ctrl),
+		MockPositionRepo:    mocks.NewMockPositionRepo(ctrl),
+	}
+	svc := NewFleetService(repo)
+	driverID := uint(7)
+	seenAt := time.Now().Add(-time.Minute)
+
+	repo.MockTenantRepo.EXPECT().GetTenantByID(gomock.Any(), uint(1)).Return(&repository.Tenant{ID: 1, Settings: "{}"}, nil)
+	repo.MockTaxiRepo.EXPECT().GetTaxisByTenant(gomock.Any(), uint(1)).Return([]repository.Taxi{
+		{ID: 6, TenantID: 1, LicensePlate: "ZZ-999", Status: "inactive"},
+		{ID: 5, TenantID: 1, LicensePlate: "AB-123", Status: "active", Mileage: 20000, AssignedDriverID: &driverID,
+			AssignedDriver: &repository.User{ID: 7, FirstName: "Jan", LastName: "Novak"}},
+	}, nil)
+	repo.MockDigestRepo.EXPECT().GetReportsForWeek(gomock.Any(), uint(1), gomock.Any()).Return([]repository.WeeklyReport{
+		{ID: 1, TaxiID: 5, DriverID: 8, Status: "approved"},
+		{ID: 2, TaxiID: 5, DriverID: 7, Status: "submitted"},
+	}, nil)
+	repo.MockMaintenanceRepo.EXPECT().GetMaintenanceSchedulesByTenant(gomock.Any(), uint(1)).Return([]repository.MaintenanceSchedule{
+		{ID: 1, TaxiID: 5, Task: "Oil change", IntervalKm: 10000, LastDoneKm: 5000, LastDoneAt: time.Now(), Taxi: repository.Taxi{Mileage: 20000}},
+		{ID: 2, TaxiID: 5, Task: "Tires", IntervalKm: 40000, LastDoneAt: time.Now(), Taxi: repository.Taxi{Mileage: 20000}},
+	}, nil)
+	repo.MockPositionRepo.EXPECT().GetLatestPositions(gomock.Any(), uint(1)).Return([]repository.TaxiPosition{
+		{TaxiID: 5, RecordedAt: seenAt, OutsideZone: true},
+	}, nil)
+
+	status, err := svc.Status(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(status.Taxis) != 2 || status.Taxis[0].LicensePlate != "AB-123" {
+		t.Fatalf("expected rows sorted by plate, got %+v", status.Taxis)
+	}
+
+	row := status.Taxis[0]
+	if row.DriverName != "Jan Novak" || row.ReportStatus != "submitted" || !row.OutsideZone || !row.LastSeenAt.Equal(seenAt) {
+		t.Errorf("unexpected row %+v", row)
+	}
+	if !slices.Equal(row.MaintenanceDue, []string{"Oil change"}) {
+		t.Errorf("expected the oil change due, got %v", row.MaintenanceDue)
+	}
+
+	idle := status.Taxis[1]
+	if idle.ReportStatus != "missing" || idle.LastSeenAt != nil || idle.MaintenanceDue == nil || len(idle.MaintenanceDue) != 0 {
+		t.Errorf("unexpected row %+v", idle)
+	}
+}
+
+func TestFleetScopedStatusRunsInTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := fleetRepoMock{
+		MockTransactor:      mocks.NewMockTransactor(ctrl),
+		MockTaxiRepo:        mocks.NewMockTaxiRepo(ctrl),
+		MockTenantRepo:      mocks.NewMockTenantRepo(ctrl),
+		MockDigestRepo:      mocks.NewMockDigestRepo(ctrl),
+		MockMaintenanceRepo: mocks.NewMockMaintenanceRepo(ctrl),
+		MockPositionRepo:    mocks.NewMockPositionRepo(ctrl),
+	}
+	svc := NewFleetService(repo)
+
+	inTx := false
+	repo.MockTransactor.EXPECT().InTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		inTx = true
+		defer func() { inTx = false }()
+		return fn(ctx)
+	})
+	repo.MockTenantRepo.EXPECT().GetTenantByID(gomock.Any(), uint(1)).DoAndReturn(func(ctx context.Context, id uint) (*repository.Tenant, error) {
+		if !inTx {
+			t.Fatal("expected the status to be read in the transaction")
+		}
+		return &repository.Tenant{ID: 1, Settings: "{}"}, nil
+	})
+	repo.MockTaxiRepo.EXPECT().GetTaxisByTenant(gomock.Any(), uint(1)).Return(nil, nil)
+	repo.MockDigestRepo.EXPECT().GetReportsForWeek(gomock.Any(), uint(1), gomock.Any()).Return(nil, nil)
+	repo.MockMaintenanceRepo.EXPECT().GetMaintenanceSchedulesByTenant(gomock.Any(), uint(1)).Return(nil, nil)
+	repo.MockPositionRepo.EXPECT().GetLatestPositions(gomock.Any(), uint(1)).Return(nil, nil)
+
+	if _, err := svc.ScopedStatus(context.Background(), 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,336 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"taxifleet/backend/internal/cache"
+	"taxifleet/backend/internal/fuelcard"
+	"taxifleet/backend/internal/logging"
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/repository"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// ErrFuelCardTaken is returned when adding a card number that is already
+// registered
+var ErrFuelCardTaken = errors.New("fuel card is already registered")
+
+// Reasons a synced fuel card transaction is flagged for the owner
+const (
+	flagCardUnassigned = "card is not assigned to a taxi"
+	flagNoDriver       = "no driver was assigned to the taxi at the time"
+	flagPeriodClosed   = "the month of the transaction is closed"
+)
+
+const (
+	// fuelCardSyncOverlap is how far before the latest stored transaction a
+	// sync starts, as providers may report transactions late
+	fuelCardSyncOverlap = 24 * time.Hour
+	// fuelCardInitialSync is how far back the first sync goes
+	fuelCardInitialSync = 30 * 24 * time.Hour
+)
+
+// FuelCardRepository is the data access FuelCardService depends on
+type FuelCardRepository interface {
+	repository.Transactor
+	repository.FuelCardRepo
+	repository.TaxiRepo
+	repository.AssignmentRepo
+	repository.ExpenseRepo
+	repository.PeriodRepo
+}
+
+// FuelCardService manages the fleet's fuel cards and turns the transactions
+// pulled from the fuel card provider into fuel expenses of the taxis
+type FuelCardService struct {
+	repo     FuelCardRepository
+	cache    cache.Cache
+	provider fuelcard.Provider
+	logger   *logrus.Logger
+}
+
+func NewFuelCardService(repo FuelCardRepository, cache cache.Cache, provider fuelcard.Provider, logger *logrus.Logger) *FuelCardService {
+	return &FuelCardService{repo: repo, cache: cache, provider: provider, logger: logger}
+}
+
+type CreateFuelCardRequest struct {
+	CardNumber string `json:"card_number" binding:"required"`
+	Label      string `json:"label"`
+	TaxiID     *uint  `json:"taxi_id"`
+}
+
+type UpdateFuelCardRequest struct {
+	Label string `json:"label"`
+	// TaxiID assigns the card to another taxi; 0 unassigns it
+	TaxiID *uint `json:"taxi_id"`
+}
+
+// FuelCardSyncResult counts what a sync did with the provider's transactions
+type FuelCardSyncResult struct {
+	Fetched int `json:"fetched"`
+	Created int `json:"created"` // Stored transactions, flagged ones included
+	Flagged int `json:"flagged"`
+	Unknown int `json:"unknown"` // Made with cards no tenant registered
+}
+
+// normalizeCardNumber strips the spaces providers and people put in card numbers
+func normalizeCardNumber(number string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(number), ""))
+}
+
+func (s *FuelCardService) Create(ctx context.Context, tenantID uint, userID uint, req CreateFuelCardRequest) (*repository.FuelCard, error) {
+	number := normalizeCardNumber(req.CardNumber)
+	existing, err := s.repo.GetFuelCardsByNumbers(ctx, []string{number})
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return nil, ErrFuelCardTaken
+	}
+
+	card := &repository.FuelCard{
+		TenantID:    tenantID,
+		CardNumber:  number,
+		Label:       req.Label,
+		CreatedByID: userID,
+	}
+	if req.TaxiID != nil {
+		if err := s.checkTaxi(ctx, tenantID, *req.TaxiID); err != nil {
+			return nil, err
+		}
+		card.TaxiID = req.TaxiID
+	}
+
+	if err := s.repo.CreateFuelCard(ctx, card); err != nil {
+		return nil, err
+	}
+	return s.repo.GetFuelCardByID(ctx, card.ID)
+}
+
+func (s *FuelCardService) checkTaxi(ctx context.Context, tenantID uint, taxiID uint) error {
+	taxi, err := s.repo.GetTaxiByID(ctx, taxiID)
+	if err != nil || taxi.TenantID != tenantID {
+		return notFound("taxi not found")
+	}
+	return nil
+}
+
+func (s *FuelCardService) GetByID(ctx context.Context, id uint, tenantID uint) (*repository.FuelCard, error) {
+	card, err := s.repo.GetFuelCardByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if card.TenantID != tenantID {
+		return nil, notFound("fuel card not found")
+	}
+
+	return card, nil
+}
+
+func (s *FuelCardService) List(ctx context.Context, tenantID uint) ([]repository.FuelCard, error) {
+	return s.repo.GetFuelCardsByTenant(ctx, tenantID)
+}
+
+// Update relabels the card or assigns it to another taxi. Transactions
+// already synced keep the taxi they were booked to.
+func (s *FuelCardService) Update(ctx context.Context, id uint, tenantID uint, req UpdateFuelCardRequest) (*repository.FuelCard, error) {
+	card, err := s.GetByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Label != "" {
+		card.Label = req.Label
+	}
+	if req.TaxiID != nil {
+		if *req.TaxiID == 0 {
+			card.TaxiID = nil
+		} else {
+			if err := s.checkTaxi(ctx, tenantID, *req.TaxiID); err != nil {
+				return nil, err
+			}
+			card.TaxiID = req.TaxiID
+		}
+	}
+
+	if err := s.repo.UpdateFuelCard(ctx, card); err != nil {
+		return nil, err
+	}
+	return s.repo.GetFuelCardByID(ctx, card.ID)
+}
+
+func (s *FuelCardService) Delete(ctx context.Context, id uint, tenantID uint) error {
+	if _, err := s.GetByID(ctx, id, tenantID); err != nil {
+		return err
+	}
+	return s.repo.DeleteFuelCard(ctx, id)
+}
+
+// Transactions returns the tenant's latest synced transactions, only the
+// flagged ones if asked
+func (s *FuelCardService) Transactions(ctx context.Context, tenantID uint, flagged bool, limit int) ([]repository.FuelCardTransaction, error) {
+	return s.repo.GetFuelCardTransactions(ctx, tenantID, flagged, limit)
+}
+
+// Sync pulls the transactions made since the last sync from the provider.
+// Each one made with a card assigned to a taxi becomes a fuel expense of the
+// taxi; those made with an unassigned card, or while no driver was assigned
+// to the taxi, are flagged. Those made in a closed month are flagged without
+// booking an expense.
+func (s *FuelCardService) Sync(ctx context.Context) (*FuelCardSyncResult, error) {
+	since := time.Now().Add(-fuelCardInitialSync)
+	latest, err := s.repo.GetLatestTransactionTime(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil {
+		since = latest.Add(-fuelCardSyncOverlap)
+	}
+
+	transactions, err := s.provider.Transactions(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	result := &FuelCardSyncResult{Fetched: len(transactions)}
+	if len(transactions) == 0 {
+		return result, nil
+	}
+
+	ids := make([]string, 0, len(transactions))
+	numbers := make([]string, 0, len(transactions))
+	for _, t := range transactions {
+		ids = append(ids, t.ID)
+		numbers = append(numbers, normalizeCardNumber(t.CardNumber))
+	}
+	synced, err := s.repo.GetSyncedTransactionIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(synced))
+	for _, id := range synced {
+		seen[id] = true
+	}
+	cardList, err := s.repo.GetFuelCardsByNumbers(ctx, numbers)
+	if err != nil {
+		return nil, err
+	}
+	cards := make(map[string]*repository.FuelCard, len(cardList))
+	for i := range cardList {
+		cards[cardList[i].CardNumber] = &cardList[i]
+	}
+
+	var errs []error
+	tenants := make(map[uint]bool)
+	for _, t := range transactions {
+		if seen[t.ID] {
+			continue
+		}
+		seen[t.ID] = true
+
+		card, ok := cards[normalizeCardNumber(t.CardNumber)]
+		if !ok {
+			result.Unknown++
+			continue
+		}
+
+		transaction, err := s.book(ctx, card, t)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("fuel card transaction %s: %w", t.ID, err))
+			continue
+		}
+		result.Created++
+		if transaction.FlagReason != "" {
+			result.Flagged++
+		}
+		tenants[card.TenantID] = true
+	}
+
+	for tenantID := range tenants {
+		s.cache.Invalidate(ctx, tenantID)
+	}
+	if result.Unknown > 0 {
+		logging.Entry(ctx, s.logger).WithField("count", result.Unknown).Warn("Fuel card transactions of unregistered cards skipped")
+	}
+	return result, errors.Join(errs...)
+}
+
+// book stores a transaction made with the card, creating the fuel expense of
+// the card's taxi unless its month is closed
+func (s *FuelCardService) book(ctx context.Context, card *repository.FuelCard, t fuelcard.Transaction) (*repository.FuelCardTransaction, error) {
+	cardID := card.ID
+	transaction := &repository.FuelCardTransaction{
+		TenantID:   card.TenantID,
+		FuelCardID: &cardID,
+		ExternalID: t.ID,
+		TaxiID:     card.TaxiID,
+		OccurredAt: t.Time,
+		Amount:     money.FromFloat(t.Amount),
+		Liters:     t.Liters,
+		Station:    t.Station,
+	}
+
+	if card.TaxiID == nil {
+		transaction.FlagReason = flagCardUnassigned
+		return transaction, s.repo.CreateFuelCardTransaction(ctx, transaction)
+	}
+
+	err := ensurePeriodOpen(ctx, s.repo, card.TenantID, t.Time)
+	if errors.Is(err, ErrPeriodClosed) {
+		transaction.FlagReason = flagPeriodClosed
+		return transaction, s.repo.CreateFuelCardTransaction(ctx, transaction)
+	} else if err != nil {
+		return nil, err
+	}
+
+	_, err = s.repo.GetAssignmentAt(ctx, *card.TaxiID, t.Time)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		transaction.FlagReason = flagNoDriver
+	} else if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		expense := &repository.Expense{
+			TenantID:    card.TenantID,
+			TaxiID:      card.TaxiID,
+			Category:    "fuel",
+			Amount:      transaction.Amount,
+			Reason:      fuelExpenseReason(card, t),
+			Date:        t.Time,
+			CreatedByID: card.CreatedByID,
+		}
+		if err := s.repo.CreateExpense(ctx, expense); err != nil {
+			return err
+		}
+		transaction.ExpenseID = &expense.ID
+		return s.repo.CreateFuelCardTransaction(ctx, transaction)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// fuelExpenseReason describes a fuel card transaction on its expense, e.g.
+// "Fuel card 1234 at Shell Main St (40.5 L)"
+func fuelExpenseReason(card *repository.FuelCard, t fuelcard.Transaction) string {
+	number := card.CardNumber
+	if len(number) > 4 {
+		number = number[len(number)-4:]
+	}
+	reason := "Fuel card " + number
+	if t.Station != "" {
+		reason += " at " + t.Station
+	}
+	if t.Liters > 0 {
+		reason += fmt.Sprintf(" (%.1f L)", t.Liters)
+	}
+	return reason
+}
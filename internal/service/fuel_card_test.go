@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"taxifleet/backend/internal/cache"
+	"taxifleet/backend/internal/fuelcard"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+
+	"github.com/sirupsen/logrus"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+type fuelCardRepoMock struct {
+	*mocks.MockTransactor
+	*mocks.MockFuelCardRepo
+	*mocks.MockTaxiRepo
+	*mocks.MockAssignmentRepo
+	*mocks.MockExpenseRepo
+	*mocks.MockPeriodRepo
+}
+
+type fuelCardProviderFunc func(ctx context.Context, since time.Time) ([]fuelcard.Transaction, error)
+
+func (f fuelCardProviderFunc) Transactions(ctx context.Context, since time.Time) ([]fuelcard.Transaction, error) {
+	return f(ctx, since)
+}
+
+func newFuelCardServiceMock(t *testing.T, provider fuelcard.Provider) (*FuelCardService, fuelCardRepoMock) {
+	ctrl := gomock.NewController(t)
+	repo := fuelCardRepoMock{
+		MockTransactor:     mocks.NewMockTransactor(ctrl),
+		MockFuelCardRepo:   mocks.NewMockFuelCardRepo(ctrl),
+		MockTaxiRepo:       mocks.NewMockTaxiRepo(ctrl),
+		MockAssignmentRepo: mocks.NewMockAssignmentRepo(ctrl),
+		MockExpenseRepo:    mocks.NewMockExpenseRepo(ctrl),
+		MockPeriodRepo:     mocks.NewMockPeriodRepo(ctrl),
+	}
+	repo.MockTransactor.EXPECT().InTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}).AnyTimes()
+	return NewFuelCardService(repo, cache.Noop{}, provider, logrus.New()), repo
+}
+
+func TestFuelCardSyncBooksAndFlagsTransactions(t *testing.T) {
+	day := time.Date(2024, 3, 5, 8, 0, 0, 0, time.UTC)
+	transactions := []fuelcard.Transaction{
+		{ID: "t1", CardNumber: "7001 0001", Time: day, Amount: 60.456, Liters: 40, Station: "Shell"},
+		{ID: "t2", CardNumber: "70010001", Time: day.Add(20 * time.Hour), Amount: 30},
+		{ID: "t3", CardNumber: "70010002", Time: day, Amount: 50},
+		{ID: "t4", CardNumber: "99999999", Time: day, Amount: 10},
+		{ID: "t5", CardNumber: "70010001", Time: day, Amount: 70},
+	}
+	svc, repo := newFuelCardServiceMock(t, fuelCardProviderFunc(func(ctx context.Context, since time.Time) ([]fuelcard.Transaction, error) {
+		return transactions, nil
+	}))
+	taxiID := uint(5)
+
+	repo.MockFuelCardRepo.EXPECT().GetLatestTransactionTime(gomock.Any()).Return(nil, nil)
+	repo.MockFuelCardRepo.EXPECT().GetSyncedTransactionIDs(gomock.Any(), gomock.Any()).Return([]string{"t5"}, nil)
+	repo.MockFuelCardRepo.EXPECT().GetFuelCardsByNumbers(gomock.Any(), gomock.Any()).Return([]repository.FuelCard{
+		{ID: 1, TenantID: 1, CardNumber: "70010001", TaxiID: &taxiID, CreatedByID: 10},
+		{ID: 2, TenantID: 1, CardNumber: "70010002", CreatedByID: 10},
+	}, nil)
+	repo.MockPeriodRepo.EXPECT().IsPeriodClosed(gomock.Any(), uint(1), gomock.Any()).Return(false, nil).Times(2)
+	repo.MockAssignmentRepo.EXPECT().GetAssignmentAt(gomock.Any(), uint(5), day).Return(&repository.Assignment{TaxiID: 5, DriverID: 7}, nil)
+	repo.MockAssignmentRepo.EXPECT().GetAssignmentAt(gomock.Any(), uint(5), day.Add(20*time.Hour)).Return(nil, gorm.ErrRecordNotFound)
+
+	var expenses []*repository.Expense
+	repo.MockExpenseRepo.EXPECT().CreateExpense(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, expense *repository.Expense) error {
+		expense.ID = uint(len(expenses) + 1)
+		expenses = append(expenses, expense)
+		return nil
+	}).Times(2)
+	stored := make(map[string]*repository.FuelCardTransaction)
+	repo.MockFuelCardRepo.EXPECT().CreateFuelCardTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, transaction *repository.FuelCardTransaction) error {
+		stored[transaction.ExternalID] = transaction
+		return nil
+	}).Times(3)
+
+	result, err := svc.Sync(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *result != (FuelCardSyncResult{Fetched: 5, Created: 3, Flagged: 2, Unknown: 1}) {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	if expenses[0].Category != "fuel" || expenses[0].Amount != 60_46 || *expenses[0].TaxiID != 5 || expenses[0].CreatedByID != 10 {
+		t.Errorf("unexpected expense %+v", expenses[0])
+	}
+	if expenses[0].Reason != "Fuel card 0001 at Shell (40.0 L)" {
+		t.Errorf("unexpected reason %q", expenses[0].Reason)
+	}
+	if stored["t1"].FlagReason != "" || stored["t1"].ExpenseID == nil || *stored["t1"].ExpenseID != 1 {
+		t.Errorf("expected t1 booked unflagged, got %+v", stored["t1"])
+	}
+	if stored["t2"].FlagReason != flagNoDriver || stored["t2"].ExpenseID == nil {
+		t.Errorf("expected t2 booked and flagged without driver, got %+v", stored["t2"])
+	}
+	if stored["t3"].FlagReason != flagCardUnassigned || stored["t3"].ExpenseID != nil {
+		t.Errorf("expected t3 flagged without expense, got %+v", stored["t3"])
+	}
+}
+
+func TestFuelCardSyncFlagsTransactionsOfClosedMonths(t *testing.T) {
+	day := time.Date(2024, 2, 28, 18, 0, 0, 0, time.UTC)
+	svc, repo := newFuelCardServiceMock(t, fuelCardProviderFunc(func(ctx context.Context, since time.Time) ([]fuelcard.Transaction, error) {
+		return []fuelcard.Transaction{{ID: "t1", CardNumber: "70010001", Time: day, Amount: 60}}, nil
+	}))
+	taxiID := uint(5)
+
+	repo.MockFuelCardRepo.EXPECT().GetLatestTransactionTime(gomock.Any()).Return(nil, nil)
+	repo.MockFuelCardRepo.EXPECT().GetSyncedTransactionIDs(gomock.Any(), gomock.Any()).Return(nil, nil)
+	repo.MockFuelCardRepo.EXPECT().GetFuelCardsByNumbers(gomock.Any(), gomock.Any()).Return([]repository.FuelCard{
+		{ID: 1, TenantID: 1, CardNumber: "70010001", TaxiID: &taxiID, CreatedByID: 10},
+	}, nil)
+	repo.MockPeriodRepo.EXPECT().IsPeriodClosed(gomock.Any(), uint(1), day).Return(true, nil)
+	var stored *repository.FuelCardTransaction
+	repo.MockFuelCardRepo.EXPECT().CreateFuelCardTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, transaction *repository.FuelCardTransaction) error {
+		stored = transaction
+		return nil
+	})
+
+	result, err := svc.Sync(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *result != (FuelCardSyncResult{Fetched: 1, Created: 1, Flagged: 1}) {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if stored.FlagReason != flagPeriodClosed || stored.ExpenseID != nil {
+		t.Errorf("expected the transaction flagged without expense, got %+v", stored)
+	}
+}
+
+func TestFuelCardSyncResumesBeforeLatestTransaction(t *testing.T) {
+	latest := time.Date(2024, 3, 5, 8, 0, 0, 0, time.UTC)
+	var since time.Time
+	provider := fuelCardProviderFunc(func(ctx context.Context, s time.Time) ([]fuelcard.Transaction, error) {
+		since = s
+		return nil, nil
+	})
+	svc, repo := newFuelCardServiceMock(t, provider)
+
+	repo.MockFuelCardRepo.EXPECT().GetLatestTransactionTime(gomock.Any()).Return(&latest, nil)
+
+	if _, err := svc.Sync(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !since.Equal(latest.Add(-fuelCardSyncOverlap)) {
+		t.Errorf("expected sync since %v, got %v", latest.Add(-fuelCardSyncOverlap), since)
+	}
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/validation"
+
+	"gorm.io/gorm"
+)
+
+// GeofenceRepository is the data access GeofenceService depends on
+type GeofenceRepository interface {
+	repository.GeofenceRepo
+	repository.PositionRepo
+	repository.TaxiRepo
+	repository.AssignmentRepo
+}
+
+// GeofenceService manages the tenant's operating zone and checks the positions
+// reported for taxis against it, alerting when a taxi leaves the zone or moves
+// while no driver is assigned to it
+type GeofenceService struct {
+	repo          GeofenceRepository
+	notifications *NotificationService
+}
+
+func NewGeofenceService(repo GeofenceRepository, notifications *NotificationService) *GeofenceService {
+	return &GeofenceService{repo: repo, notifications: notifications}
+}
+
+type CreateGeofenceRequest struct {
+	Name    string                `json:"name" binding:"required"`
+	Polygon []repository.GeoPoint `json:"polygon" binding:"required,min=3"`
+	Active  *bool                 `json:"active"` // Defaults to true
+}
+
+type UpdateGeofenceRequest struct {
+	Name    string                `json:"name"`
+	Polygon []repository.GeoPoint `json:"polygon" binding:"omitempty,min=3"`
+	Active  *bool                 `json:"active"`
+}
+
+type PositionInput struct {
+	Latitude   float64   `json:"latitude" binding:"min=-90,max=90"`
+	Longitude  float64   `json:"longitude" binding:"min=-180,max=180"`
+	RecordedAt time.Time `json:"recorded_at" binding:"required"`
+}
+
+// ReportPositionsRequest is a batch of positions of a taxi, e.g. buffered by
+// the driver app while offline
+type ReportPositionsRequest struct {
+	Positions []PositionInput `json:"positions" binding:"required,min=1,max=500,dive"`
+}
+
+// checkPolygon validates the vertices of a geofence
+func checkPolygon(polygon []repository.GeoPoint) error {
+	for i, point := range polygon {
+		if point.Latitude < -90 || point.Latitude > 90 || point.Longitude < -180 || point.Longitude > 180 {
+			return &validation.FieldError{Field: fmt.Sprintf("polygon[%d]", i), Rule: "coordinate", Message: "coordinate is out of range"}
+		}
+	}
+	return nil
+}
+
+func (s *GeofenceService) Create(ctx context.Context, tenantID uint, req CreateGeofenceRequest) (*repository.Geofence, error) {
+	if err := checkPolygon(req.Polygon); err != nil {
+		return nil, err
+	}
+
+	geofence := &repository.Geofence{
+		TenantID: tenantID,
+		Name:     req.Name,
+		Polygon:  req.Polygon,
+		Active:   req.Active == nil || *req.Active,
+	}
+	if err := s.repo.CreateGeofence(ctx, geofence); err != nil {
+		return nil, err
+	}
+	return geofence, nil
+}
+
+func (s *GeofenceService) GetByID(ctx context.Context, id uint, tenantID uint) (*repository.Geofence, error) {
+	geofence, err := s.repo.GetGeofenceByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if geofence.TenantID != tenantID {
+		return nil, notFound("geofence not found")
+	}
+
+	return geofence, nil
+}
+
+func (s *GeofenceService) List(ctx context.Context, tenantID uint) ([]repository.Geofence, error) {
+	return s.repo.GetGeofencesByTenant(ctx, tenantID)
+}
+
+func (s *GeofenceService) Update(ctx context.Context, id uint, tenantID uint, req UpdateGeofenceRequest) (*repository.Geofence, error) {
+	geofence, err := s.GetByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != "" {
+		geofence.Name = req.Name
+	}
+	if req.Polygon != nil {
+		if err := checkPolygon(req.Polygon); err != nil {
+			return nil, err
+		}
+		geofence.Polygon = req.Polygon
+	}
+	if req.Active != nil {
+		geofence.Active = *req.Active
+	}
+
+	if err := s.repo.UpdateGeofence(ctx, geofence); err != nil {
+		return nil, err
+	}
+	return geofence, nil
+}
+
+func (s *GeofenceService) Delete(ctx context.Context, id uint, tenantID uint) error {
+	if _, err := s.GetByID(ctx, id, tenantID); err != nil {
+		return err
+	}
+	return s.repo.DeleteGeofence(ctx, id)
+}
+
+// ReportPositions stores positions of the taxi and returns how many were
+// stored. Positions outside the operating zone, or recorded while no driver
+// was assigned, are marked; the users managing taxis are alerted when the
+// taxi enters either state rather than on every position.
+func (s *GeofenceService) ReportPositions(ctx context.Context, tenantID uint, taxiID uint, req ReportPositionsRequest) (int, error) {
+	taxi, err := s.repo.GetTaxiByID(ctx, taxiID)
+	if err != nil || taxi.TenantID != tenantID {
+		return 0, notFound("taxi not found")
+	}
+
+	geofences, err := s.repo.GetGeofencesByTenant(ctx, tenantID)
+	if err != nil {
+		return 0, err
+	}
+	zone := slices.DeleteFunc(geofences, func(g repository.Geofence) bool { return !g.Active })
+
+	previous, err := s.repo.GetLatestPosition(ctx, taxiID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		previous = nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	inputs := slices.Clone(req.Positions)
+	slices.SortFunc(inputs, func(a, b PositionInput) int { return a.RecordedAt.Compare(b.RecordedAt) })
+
+	positions := make([]repository.TaxiPosition, 0, len(inputs))
+	for _, input := range inputs {
+		position := repository.TaxiPosition{
+			TenantID:   tenantID,
+			TaxiID:     taxiID,
+			Latitude:   input.Latitude,
+			Longitude:  input.Longitude,
+			RecordedAt: input.RecordedAt,
+		}
+		assignment, err := s.repo.GetAssignmentAt(ctx, taxiID, input.RecordedAt)
+		if err == nil {
+			position.DriverID = &assignment.DriverID
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, err
+		}
+		position.Unscheduled = position.DriverID == nil
+		position.OutsideZone = len(zone) > 0 && !inZone(zone, repository.GeoPoint{Latitude: input.Latitude, Longitude: input.Longitude})
+		positions = append(positions, position)
+	}
+
+	if err := s.repo.CreatePositions(ctx, positions); err != nil {
+		return 0, err
+	}
+
+	for i := range positions {
+		position := &positions[i]
+		if position.OutsideZone && (previous == nil || !previous.OutsideZone) {
+			s.notifications.NotifyOutsideZone(ctx, taxi, position)
+		}
+		if position.Unscheduled && (previous == nil || !previous.Unscheduled) {
+			s.notifications.NotifyUnscheduledUse(ctx, taxi, position)
+		}
+		previous = position
+	}
+
+	return len(positions), nil
+}
+
+// inZone reports whether the point lies in any of the geofences
+func inZone(zone []repository.Geofence, point repository.GeoPoint) bool {
+	for _, geofence := range zone {
+		if polygonContains(geofence.Polygon, point) {
+			return true
+		}
+	}
+	return false
+}
+
+// polygonContains tests whether the point lies inside the polygon by casting a
+// ray east of it and counting the edges crossed. Coordinates are treated as
+// planar, which is accurate enough at city scale.
+func polygonContains(polygon []repository.GeoPoint, point repository.GeoPoint) bool {
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[i], polygon[j]
+		if (a.Latitude > point.Latitude) != (b.Latitude > point.Latitude) &&
+			point.Longitude < (b.Longitude-a.Longitude)*(point.Latitude-a.Latitude)/(b.Latitude-a.Latitude)+a.Longitude {
+			inside = !inside
+		}
+	}
+	return inside
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+type geofenceRepoMock struct {
+	*mocks.MockGeofenceRepo
+	*mocks.MockPositionRepo
+	*mocks.MockTaxiRepo
+	*mocks.MockAssignmentRepo
+}
+
+// square is a geofence of roughly 11 km around (50.05, 14.05)
+var square = []repository.GeoPoint{
+	{Latitude: 50.0, Longitude: 14.0},
+	{Latitude: 50.0, Longitude: 14.1},
+	{Latitude: 50.1, Longitude: 14.1},
+	{Latitude: 50.1, Longitude: 14.0},
+}
+
+func TestPolygonContains(t *testing.T) {
+	tests := []struct {
+		point repository.GeoPoint
+		want  bool
+	}{
+		{repository.GeoPoint{Latitude: 50.05, Longitude: 14.05}, true},
+		{repository.GeoPoint{Latitude: 50.05, Longitude: 14.2}, false},
+		{repository.GeoPoint{Latitude: 49.9, Longitude: 14.05}, false},
+	}
+	for _, tt := range tests {
+		if got := polygonContains(square, tt.point); got != tt.want {
+			t.Errorf("polygonContains(%v) = %v, want %v", tt.point, got, tt.want)
+		}
+	}
+}
+
+func TestReportPositionsAlertsOnceOnLeavingZone(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := geofenceRepoMock{
+		MockGeofenceRepo:   mocks.NewMockGeofenceRepo(ctrl),
+		MockPositionRepo:   mocks.NewMockPositionRepo(ctrl),
+		MockTaxiRepo:       mocks.NewMockTaxiRepo(ctrl),
+		MockAssignmentRepo: mocks.NewMockAssignmentRepo(ctrl),
+	}
+	notifications, notificationRepo := newNotificationServiceMock(t)
+	svc := NewGeofenceService(repo, notifications)
+	at := time.Date(2024, 3, 5, 14, 0, 0, 0, time.UTC)
+
+	repo.MockTaxiRepo.EXPECT().GetTaxiByID(gomock.Any(), uint(5)).Return(&repository.Taxi{ID: 5, TenantID: 1, LicensePlate: "AB-123"}, nil)
+	repo.MockGeofenceRepo.EXPECT().GetGeofencesByTenant(gomock.Any(), uint(1)).Return([]repository.Geofence{
+		{ID: 1, TenantID: 1, Polygon: square, Active: true},
+	}, nil)
+	repo.MockPositionRepo.EXPECT().GetLatestPosition(gomock.Any(), uint(5)).Return(nil, gorm.ErrRecordNotFound)
+	repo.MockAssignmentRepo.EXPECT().GetAssignmentAt(gomock.Any(), uint(5), gomock.Any()).Return(&repository.Assignment{TaxiID: 5, DriverID: 7}, nil).Times(3)
+	repo.MockPositionRepo.EXPECT().CreatePositions(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, positions []repository.TaxiPosition) error {
+		outside := []bool{false, true, true}
+		for i, position := range positions {
+			if position.OutsideZone != outside[i] || position.Unscheduled || *position.DriverID != 7 {
+				t.Errorf("unexpected position %d: %+v", i, position)
+			}
+		}
+		return nil
+	})
+	// The alert goes out once, when the taxi leaves the zone
+	notificationRepo.MockUserRepo.EXPECT().GetUsersByTenant(gomock.Any(), uint(1)).Return(nil, nil).Times(1)
+
+	count, err := svc.ReportPositions(context.Background(), 1, 5, ReportPositionsRequest{Positions: []PositionInput{
+		{Latitude: 50.2, Longitude: 14.05, RecordedAt: at.Add(2 * time.Minute)},
+		{Latitude: 50.05, Longitude: 14.05, RecordedAt: at},
+		{Latitude: 50.3, Longitude: 14.05, RecordedAt: at.Add(4 * time.Minute)},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("expected 3 positions stored, got %d", count)
+	}
+}
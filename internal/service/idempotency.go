@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"taxifleet/backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// idempotencyKeyTTL is how long a stored response can be replayed
+const idempotencyKeyTTL = 24 * time.Hour
+
+var (
+	// ErrIdempotencyKeyInFlight is returned while the original request of a key
+	// is still being processed
+	ErrIdempotencyKeyInFlight = errors.New("a request with this idempotency key is still in progress")
+	// ErrIdempotencyKeyReused is returned when a key is sent again with a
+	// different request
+	ErrIdempotencyKeyReused = errors.New("idempotency key was already used for a different request")
+)
+
+type IdempotencyService struct {
+	repo repository.IdempotencyRepo
+}
+
+func NewIdempotencyService(repo repository.IdempotencyRepo) *IdempotencyService {
+	return &IdempotencyService{repo: repo}
+}
+
+// Begin claims the key for a request. It returns the stored record with
+// replay set when the request was already completed, or a new in-flight
+// record the caller must Complete or Release.
+func (s *IdempotencyService) Begin(ctx context.Context, userID uint, key string, requestHash string) (record *repository.IdempotencyKey, replay bool, err error) {
+	existing, err := s.repo.GetIdempotencyKey(ctx, userID, key)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, err
+	}
+	if err == nil {
+		if time.Since(existing.CreatedAt) <= idempotencyKeyTTL {
+			if existing.RequestHash != requestHash {
+				return nil, false, ErrIdempotencyKeyReused
+			}
+			if existing.StatusCode == 0 {
+				return nil, false, ErrIdempotencyKeyInFlight
+			}
+			return existing, true, nil
+		}
+
+		// Expired but not cleaned up yet, so the key is free again
+		if err := s.repo.DeleteIdempotencyKey(ctx, existing.ID); err != nil {
+			return nil, false, err
+		}
+	}
+
+	record = &repository.IdempotencyKey{
+		UserID:      userID,
+		Key:         key,
+		RequestHash: requestHash,
+	}
+	if err := s.repo.CreateIdempotencyKey(ctx, record); err != nil {
+		// A concurrent retry claimed the key first
+		if repository.IsUniqueViolation(err) {
+			return nil, false, ErrIdempotencyKeyInFlight
+		}
+		return nil, false, err
+	}
+	return record, false, nil
+}
+
+// Complete stores the response so later retries replay it
+func (s *IdempotencyService) Complete(ctx context.Context, record *repository.IdempotencyKey, statusCode int, body []byte) error {
+	record.StatusCode = statusCode
+	record.ResponseBody = body
+	return s.repo.UpdateIdempotencyKey(ctx, record)
+}
+
+// Release frees the key after a failed request so it can be retried
+func (s *IdempotencyService) Release(ctx context.Context, record *repository.IdempotencyKey) error {
+	return s.repo.DeleteIdempotencyKey(ctx, record.ID)
+}
+
+// Cleanup removes expired keys and returns how many were removed
+func (s *IdempotencyService) Cleanup(ctx context.Context) (int64, error) {
+	return s.repo.DeleteIdempotencyKeysBefore(ctx, time.Now().Add(-idempotencyKeyTTL))
+}
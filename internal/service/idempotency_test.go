@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestIdempotencyBeginClaimsNewKey(t *testing.T) {
+	repo := mocks.NewMockIdempotencyRepo(gomock.NewController(t))
+	svc := NewIdempotencyService(repo)
+
+	repo.EXPECT().GetIdempotencyKey(gomock.Any(), uint(9), "abc").Return(nil, gorm.ErrRecordNotFound)
+	repo.EXPECT().CreateIdempotencyKey(gomock.Any(), gomock.Any()).Return(nil)
+
+	record, replay, err := svc.Begin(context.Background(), 9, "abc", "hash")
+	if err != nil || replay || record.Key != "abc" {
+		t.Fatalf("expected a new claim, got record=%+v replay=%v err=%v", record, replay, err)
+	}
+}
+
+func TestIdempotencyBeginReplaysCompletedRequest(t *testing.T) {
+	repo := mocks.NewMockIdempotencyRepo(gomock.NewController(t))
+	svc := NewIdempotencyService(repo)
+
+	stored := &repository.IdempotencyKey{ID: 1, UserID: 9, Key: "abc", RequestHash: "hash", StatusCode: 201, CreatedAt: time.Now()}
+	repo.EXPECT().GetIdempotencyKey(gomock.Any(), uint(9), "abc").Return(stored, nil)
+
+	record, replay, err := svc.Begin(context.Background(), 9, "abc", "hash")
+	if err != nil || !replay || record != stored {
+		t.Fatalf("expected a replay, got record=%+v replay=%v err=%v", record, replay, err)
+	}
+}
+
+func TestIdempotencyBeginRejectsConflicts(t *testing.T) {
+	tests := []struct {
+		name   string
+		stored repository.IdempotencyKey
+		want   error
+	}{
+		{"different request", repository.IdempotencyKey{RequestHash: "other", StatusCode: 201}, ErrIdempotencyKeyReused},
+		{"in flight", repository.IdempotencyKey{RequestHash: "hash"}, ErrIdempotencyKeyInFlight},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewMockIdempotencyRepo(gomock.NewController(t))
+			svc := NewIdempotencyService(repo)
+
+			stored := tt.stored
+			stored.CreatedAt = time.Now()
+			repo.EXPECT().GetIdempotencyKey(gomock.Any(), uint(9), "abc").Return(&stored, nil)
+
+			if _, _, err := svc.Begin(context.Background(), 9, "abc", "hash"); !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/repository"
+)
+
+// ErrDuplicateSKU is returned when another part of the tenant has the same SKU
+var ErrDuplicateSKU = errors.New("a part with this SKU already exists")
+
+// InventoryRepository is the data access InventoryService depends on
+type InventoryRepository interface {
+	repository.Transactor
+	repository.InventoryRepo
+	repository.MaintenanceRepo
+}
+
+type InventoryService struct {
+	repo          InventoryRepository
+	notifications *NotificationService
+}
+
+func NewInventoryService(repo InventoryRepository, notifications *NotificationService) *InventoryService {
+	return &InventoryService{repo: repo, notifications: notifications}
+}
+
+type CreatePartRequest struct {
+	Name            string       `json:"name" binding:"required"`
+	SKU             string       `json:"sku"`
+	Unit            string       `json:"unit"`
+	UnitCost        money.Amount `json:"unit_cost" binding:"min=0"`
+	ReorderLevel    float64      `json:"reorder_level" binding:"min=0"`
+	InitialQuantity float64      `json:"initial_quantity" binding:"min=0"`
+}
+
+type UpdatePartRequest struct {
+	Name         string        `json:"name"`
+	SKU          string        `json:"sku"`
+	Unit         string        `json:"unit"`
+	UnitCost     *money.Amount `json:"unit_cost" binding:"omitempty,min=0"`
+	ReorderLevel *float64      `json:"reorder_level" binding:"omitempty,min=0"`
+}
+
+type StockMovementRequest struct {
+	Type     string       `json:"type" binding:"required,oneof=in out adjustment"`
+	Quantity float64      `json:"quantity" binding:"required"` // Positive for in/out, signed for adjustment
+	UnitCost money.Amount `json:"unit_cost" binding:"min=0"`
+	Notes    string       `json:"notes"`
+}
+
+type ConsumePartsRequest struct {
+	MaintenanceLogID uint               `json:"maintenance_log_id" binding:"required"`
+	Items            []ConsumedPartItem `json:"items" binding:"required,min=1,dive"`
+}
+
+type ConsumedPartItem struct {
+	PartID   uint    `json:"part_id" binding:"required"`
+	Quantity float64 `json:"quantity" binding:"required,gt=0"`
+}
+
+func (s *InventoryService) CreatePart(ctx context.Context, tenantID uint, userID uint, req CreatePartRequest) (*repository.Part, error) {
+	part := &repository.Part{
+		TenantID:     tenantID,
+		Name:         req.Name,
+		SKU:          req.SKU,
+		Unit:         req.Unit,
+		UnitCost:     req.UnitCost,
+		ReorderLevel: req.ReorderLevel,
+	}
+	if part.Unit == "" {
+		part.Unit = "pcs"
+	}
+
+	if err := s.repo.CreatePart(ctx, part); err != nil {
+		if repository.IsUniqueViolation(err) {
+			return nil, ErrDuplicateSKU
+		}
+		return nil, err
+	}
+
+	// Opening stock is recorded as a movement so the history adds up
+	if req.InitialQuantity > 0 {
+		movement := repository.StockMovement{
+			TenantID:    tenantID,
+			PartID:      part.ID,
+			Type:        "in",
+			Quantity:    req.InitialQuantity,
+			UnitCost:    req.UnitCost,
+			Notes:       "Initial stock",
+			CreatedByID: userID,
+		}
+		if _, err := s.repo.CreateStockMovements(ctx, []repository.StockMovement{movement}); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.GetPartByID(ctx, part.ID)
+}
+
+func (s *InventoryService) GetPart(ctx context.Context, id uint, tenantID uint) (*repository.Part, error) {
+	part, err := s.repo.GetPartByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if part.TenantID != tenantID {
+		return nil, notFound("part not found")
+	}
+
+	return part, nil
+}
+
+func (s *InventoryService) ListParts(ctx context.Context, tenantID uint) ([]repository.Part, error) {
+	return s.repo.GetPartsByTenant(ctx, tenantID)
+}
+
+func (s *InventoryService) ListLowStock(ctx context.Context, tenantID uint) ([]repository.Part, error) {
+	return s.repo.GetLowStockParts(ctx, tenantID)
+}
+
+func (s *InventoryService) UpdatePart(ctx context.Context, id uint, tenantID uint, req UpdatePartRequest) (*repository.Part, error) {
+	part, err := s.GetPart(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != "" {
+		part.Name = req.Name
+	}
+	if req.SKU != "" {
+		part.SKU = req.SKU
+	}
+	if req.Unit != "" {
+		part.Unit = req.Unit
+	}
+	if req.UnitCost != nil {
+		part.UnitCost = *req.UnitCost
+	}
+	if req.ReorderLevel != nil {
+		part.ReorderLevel = *req.ReorderLevel
+	}
+
+	if err := s.repo.UpdatePart(ctx, part); err != nil {
+		if repository.IsUniqueViolation(err) {
+			return nil, ErrDuplicateSKU
+		}
+		return nil, err
+	}
+
+	return s.repo.GetPartByID(ctx, part.ID)
+}
+
+func (s *InventoryService) DeletePart(ctx context.Context, id uint, tenantID uint) error {
+	if _, err := s.GetPart(ctx, id, tenantID); err != nil {
+		return err
+	}
+
+	return s.repo.DeletePart(ctx, id)
+}
+
+func (s *InventoryService) ListMovements(ctx context.Context, partID uint, tenantID uint) ([]repository.StockMovement, error) {
+	if _, err := s.GetPart(ctx, partID, tenantID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetStockMovementsByPart(ctx, partID)
+}
+
+// RecordMovement receives, issues or corrects stock of a single part
+func (s *InventoryService) RecordMovement(ctx context.Context, partID uint, tenantID uint, userID uint, req StockMovementRequest) (*repository.Part, error) {
+	part, err := s.GetPart(ctx, partID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	quantity := req.Quantity
+	switch req.Type {
+	case "in":
+		if quantity <= 0 {
+			return nil, invalid("quantity must be positive")
+		}
+	case "out":
+		if quantity <= 0 {
+			return nil, invalid("quantity must be positive")
+		}
+		quantity = -quantity
+	}
+
+	unitCost := req.UnitCost
+	if unitCost == 0 {
+		unitCost = part.UnitCost
+	}
+
+	movement := repository.StockMovement{
+		TenantID:    tenantID,
+		PartID:      part.ID,
+		Type:        req.Type,
+		Quantity:    quantity,
+		UnitCost:    unitCost,
+		Notes:       req.Notes,
+		CreatedByID: userID,
+	}
+
+	parts, err := s.applyMovements(ctx, []repository.StockMovement{movement})
+	if err != nil {
+		return nil, err
+	}
+
+	return &parts[0], nil
+}
+
+// ConsumeParts issues parts against a maintenance log (work order) and adds
+// their cost to the work order
+func (s *InventoryService) ConsumeParts(ctx context.Context, tenantID uint, userID uint, req ConsumePartsRequest) ([]repository.StockMovement, error) {
+	log, err := s.repo.GetMaintenanceLogByID(ctx, req.MaintenanceLogID)
+	if err != nil || log.TenantID != tenantID {
+		return nil, notFound("maintenance log not found")
+	}
+
+	movements := make([]repository.StockMovement, 0, len(req.Items))
+	var totalCost money.Amount
+	for _, item := range req.Items {
+		part, err := s.GetPart(ctx, item.PartID, tenantID)
+		if err != nil {
+			return nil, err
+		}
+
+		movements = append(movements, repository.StockMovement{
+			TenantID:         tenantID,
+			PartID:           part.ID,
+			Type:             "out",
+			Quantity:         -item.Quantity,
+			UnitCost:         part.UnitCost,
+			MaintenanceLogID: &log.ID,
+			CreatedByID:      userID,
+		})
+		totalCost += part.UnitCost.Mul(item.Quantity)
+	}
+
+	// The stock and the work order's cost change together or not at all
+	var parts []repository.Part
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if parts, err = s.repo.CreateStockMovements(ctx, movements); err != nil {
+			return err
+		}
+		return s.repo.AddMaintenanceLogCost(ctx, log.ID, totalCost)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.alertLowStock(ctx, movements, parts)
+
+	return s.repo.GetStockMovementsByMaintenanceLog(ctx, log.ID)
+}
+
+// applyMovements writes the movements and alerts when a part crosses its
+// reorder level
+func (s *InventoryService) applyMovements(ctx context.Context, movements []repository.StockMovement) ([]repository.Part, error) {
+	parts, err := s.repo.CreateStockMovements(ctx, movements)
+	if err != nil {
+		return nil, err
+	}
+	s.alertLowStock(ctx, movements, parts)
+	return parts, nil
+}
+
+// alertLowStock alerts about the parts the movements took to their reorder
+// level or below
+func (s *InventoryService) alertLowStock(ctx context.Context, movements []repository.StockMovement, parts []repository.Part) {
+	for i := range parts {
+		part := &parts[i]
+		before := part.QuantityOnHand - movements[i].Quantity
+		if before > part.ReorderLevel && part.QuantityOnHand <= part.ReorderLevel {
+			s.notifications.NotifyLowStock(ctx, part)
+		}
+	}
+}
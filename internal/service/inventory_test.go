@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/push"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+
+	"github.com/sirupsen/logrus"
+	"go.uber.org/mock/gomock"
+)
+
+type inventoryRepoMock struct {
+	*mocks.MockTransactor
+	*mocks.MockInventoryRepo
+	*mocks.MockMaintenanceRepo
+	*mocks.MockDeviceTokenRepo
+	*mocks.MockInboxRepo
+	*mocks.MockUserRepo
+	*mocks.MockTaxiRepo
+}
+
+func newInventoryServiceMock(t *testing.T) (*InventoryService, inventoryRepoMock) {
+	ctrl := gomock.NewController(t)
+	repo := inventoryRepoMock{
+		MockTransactor:      mocks.NewMockTransactor(ctrl),
+		MockInventoryRepo:   mocks.NewMockInventoryRepo(ctrl),
+		MockMaintenanceRepo: mocks.NewMockMaintenanceRepo(ctrl),
+		MockDeviceTokenRepo: mocks.NewMockDeviceTokenRepo(ctrl),
+		MockInboxRepo:       mocks.NewMockInboxRepo(ctrl),
+		MockUserRepo:        mocks.NewMockUserRepo(ctrl),
+		MockTaxiRepo:        mocks.NewMockTaxiRepo(ctrl),
+	}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return NewInventoryService(repo, NewNotificationService(repo, push.NoopSender{}, logger)), repo
+}
+
+func TestConsumePartsAddsCostInTransaction(t *testing.T) {
+	svc, repo := newInventoryServiceMock(t)
+	ctx := context.Background()
+
+	repo.MockMaintenanceRepo.EXPECT().GetMaintenanceLogByID(gomock.Any(), uint(3)).Return(&repository.MaintenanceLog{ID: 3, TenantID: 1, Cost: 50_00}, nil)
+	repo.MockInventoryRepo.EXPECT().GetPartByID(gomock.Any(), uint(8)).Return(&repository.Part{ID: 8, TenantID: 1, UnitCost: 12_50, QuantityOnHand: 10, ReorderLevel: 2}, nil)
+	inTx := false
+	repo.MockTransactor.EXPECT().InTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		inTx = true
+		defer func() { inTx = false }()
+		return fn(ctx)
+	})
+	repo.MockInventoryRepo.EXPECT().CreateStockMovements(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, movements []repository.StockMovement) ([]repository.Part, error) {
+		if !inTx || len(movements) != 1 || movements[0].Quantity != -2 {
+			t.Fatalf("expected 2 parts taken out in the transaction, got %+v", movements)
+		}
+		return []repository.Part{{ID: 8, TenantID: 1, QuantityOnHand: 8, ReorderLevel: 2}}, nil
+	})
+	repo.MockMaintenanceRepo.EXPECT().AddMaintenanceLogCost(gomock.Any(), uint(3), money.Amount(25_00)).DoAndReturn(func(ctx context.Context, id uint, amount money.Amount) error {
+		if !inTx {
+			t.Fatal("expected the cost to be added in the transaction")
+		}
+		return nil
+	})
+	repo.MockInventoryRepo.EXPECT().GetStockMovementsByMaintenanceLog(gomock.Any(), uint(3)).Return([]repository.StockMovement{{ID: 4}}, nil)
+
+	movements, err := svc.ConsumeParts(ctx, 1, 9, ConsumePartsRequest{MaintenanceLogID: 3, Items: []ConsumedPartItem{{PartID: 8, Quantity: 2}}})
+	if err != nil || len(movements) != 1 {
+		t.Fatalf("expected the work order's movements, got %+v, %v", movements, err)
+	}
+}
+
+func TestConsumePartsDoesNotAlertWhenRolledBack(t *testing.T) {
+	svc, repo := newInventoryServiceMock(t)
+	failed := errors.New("connection reset")
+
+	repo.MockMaintenanceRepo.EXPECT().GetMaintenanceLogByID(gomock.Any(), uint(3)).Return(&repository.MaintenanceLog{ID: 3, TenantID: 1}, nil)
+	repo.MockInventoryRepo.EXPECT().GetPartByID(gomock.Any(), uint(8)).Return(&repository.Part{ID: 8, TenantID: 1, UnitCost: 12_50, QuantityOnHand: 3, ReorderLevel: 2}, nil)
+	repo.MockTransactor.EXPECT().InTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	})
+	// The part drops below its reorder level, but the transaction fails, so
+	// no user is looked up to be alerted
+	repo.MockInventoryRepo.EXPECT().CreateStockMovements(gomock.Any(), gomock.Any()).Return([]repository.Part{{ID: 8, TenantID: 1, QuantityOnHand: 1, ReorderLevel: 2}}, nil)
+	repo.MockMaintenanceRepo.EXPECT().AddMaintenanceLogCost(gomock.Any(), uint(3), money.Amount(25_00)).Return(failed)
+
+	if _, err := svc.ConsumeParts(context.Background(), 1, 9, ConsumePartsRequest{MaintenanceLogID: 3, Items: []ConsumedPartItem{{PartID: 8, Quantity: 2}}}); !errors.Is(err, failed) {
+		t.Fatalf("expected the failed cost update to fail the consumption, got %v", err)
+	}
+}
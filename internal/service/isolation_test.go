@@ -0,0 +1,162 @@
+//go:build integration
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"taxifleet/backend/internal/cache"
+	"taxifleet/backend/internal/config"
+	"taxifleet/backend/internal/ocr"
+	"taxifleet/backend/internal/permissions"
+	"taxifleet/backend/internal/push"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/storage"
+	"taxifleet/backend/internal/testdb"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// The isolation helpers and the row-level security check run against Postgres
+// in a container, connected as a regular role:
+//
+//	go test -tags integration -run Isolation ./internal/service/
+//
+// It needs Docker. The API's tenant isolation suite is in cmd/api.
+
+var testPostgres *testdb.Postgres
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+	pg, err := testdb.Start(ctx, "file://../../migrations")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	testPostgres = pg
+
+	code := m.Run()
+	if err := pg.Terminate(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	os.Exit(code)
+}
+
+// isolationTenant is a tenant with one of each record
+type isolationTenant struct {
+	tenant  *repository.Tenant
+	owner   *repository.User
+	taxi    *repository.Taxi
+	report  *repository.WeeklyReport
+	expense *repository.Expense
+	deposit *repository.BankDeposit
+}
+
+type isolationServices struct {
+	db       *sqlx.DB
+	repo     *repository.Repository
+	taxis    *TaxiService
+	reports  *ReportService
+	expenses *ExpenseService
+	deposits *DepositService
+}
+
+func openIsolationDB(t testing.TB) *isolationServices {
+	t.Helper()
+	cfg := testPostgres.Config()
+	db, err := sqlx.Connect("postgres", cfg.GetDSN())
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	repo := repository.New(db)
+	notifications := NewNotificationService(repo, push.NoopSender{}, logrus.New())
+	return &isolationServices{
+		db:       db,
+		repo:     repo,
+		taxis:    NewTaxiService(repo, cache.Noop{}),
+		reports:  NewReportService(repo, cache.Noop{}, notifications, NewPolicyService(repo, cache.Noop{}), storage.NewLocal(t.TempDir()), nil, config.AttachmentConfig{}),
+		expenses: NewExpenseService(repo, cache.Noop{}, ocr.DisabledProvider{}, NewBudgetService(repo, cache.Noop{}, notifications, logrus.New())),
+		deposits: NewDepositService(repo, cache.Noop{}),
+	}
+}
+
+// createIsolationTenant creates a tenant, its owner and one taxi, report,
+// expense and deposit
+func createIsolationTenant(t testing.TB, s *isolationServices, name string) *isolationTenant {
+	t.Helper()
+	ctx := context.Background()
+	unique := time.Now().UnixNano()
+
+	tenant := &repository.Tenant{Name: name, Subdomain: fmt.Sprintf("%s-%d", name, unique), Settings: "{}"}
+	if err := s.repo.CreateTenant(ctx, tenant); err != nil {
+		t.Fatalf("failed to create tenant: %v", err)
+	}
+	owner := &repository.User{
+		TenantID:     tenant.ID,
+		Email:        fmt.Sprintf("%s-%d@example.com", name, unique),
+		PasswordHash: "x",
+		Permission:   permissions.PermissionOwner,
+		FirstName:    "Owner",
+		LastName:     name,
+		Phone:        fmt.Sprintf("+1%d", unique%1_000_000_000_000),
+		Active:       true,
+	}
+	if err := s.repo.CreateUser(ctx, owner); err != nil {
+		t.Fatalf("failed to create owner: %v", err)
+	}
+
+	taxi, err := s.taxis.Create(ctx, tenant.ID, owner.ID, CreateTaxiRequest{LicensePlate: "AB-123-CD", Model: "Corolla"})
+	if err != nil {
+		t.Fatalf("failed to create taxi: %v", err)
+	}
+	report, err := s.reports.Create(ctx, tenant.ID, owner.ID, CreateReportRequest{TaxiID: taxi.ID, WeekStartDate: time.Now(), Earnings: 1000_00})
+	if err != nil {
+		t.Fatalf("failed to create report: %v", err)
+	}
+	expense, err := s.expenses.Create(ctx, tenant.ID, owner.ID, owner.Permission, CreateExpenseRequest{ReportID: &report.ID, Category: "fuel", Amount: 50_00, Date: "2024-05-13"})
+	if err != nil {
+		t.Fatalf("failed to create expense: %v", err)
+	}
+	deposit, err := s.deposits.Create(ctx, tenant.ID, CreateDepositRequest{Amount: 900_00, DepositDate: "2024-05-20", PeriodStart: "2024-05-13", PeriodEnd: "2024-05-19", TaxiID: &taxi.ID})
+	if err != nil {
+		t.Fatalf("failed to create deposit: %v", err)
+	}
+
+	return &isolationTenant{tenant: tenant, owner: owner, taxi: taxi, report: report, expense: expense, deposit: deposit}
+}
+
+// TestTenantIsolationRowLevelSecurity checks the database itself hides other
+// tenants' rows in a transaction scoped to a tenant, even from a query
+// without a tenant filter
+func TestTenantIsolationRowLevelSecurity(t *testing.T) {
+	s := openIsolationDB(t)
+	victim := createIsolationTenant(t, s, "victim")
+	attacker := createIsolationTenant(t, s, "attacker")
+
+	ctx := repository.WithTenant(context.Background(), attacker.tenant.ID)
+	err := s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.repo.GetTaxiByID(ctx, attacker.taxi.ID); err != nil {
+			return fmt.Errorf("own taxi: %w", err)
+		}
+		if _, err := s.repo.GetTaxiByID(ctx, victim.taxi.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("expected the victim's taxi hidden, got %v", err)
+		}
+		if _, err := s.repo.GetReportByID(ctx, victim.report.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("expected the victim's report hidden, got %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/tracing"
+)
+
+// JournalLine is one side of a journal entry; every entry has a debit and a
+// credit line of the same amount
+type JournalLine struct {
+	Date        string       `json:"date"`      // YYYY-MM-DD
+	Reference   string       `json:"reference"` // Source record, e.g. REP-12, EXP-5, DEP-3
+	Account     string       `json:"account"`
+	Description string       `json:"description"`
+	Debit       money.Amount `json:"debit"`
+	Credit      money.Amount `json:"credit"`
+}
+
+// Journal holds the journal entries of a period in date order
+type Journal struct {
+	Period AnalyticsPeriod `json:"period"`
+	Lines  []JournalLine   `json:"lines"`
+}
+
+// GetJournal books the approved earnings, expenses and deposits of the period
+// as double-entry journal lines using the tenant's account codes. Earnings
+// move cash into revenue, expenses are paid from cash and deposits move cash
+// to the bank.
+func (s *AnalyticsService) GetJournal(ctx context.Context, tenantID uint, from, to string) (_ *Journal, err error) {
+	ctx, span := tracing.Start(ctx, "AnalyticsService.GetJournal")
+	defer func() { tracing.End(span, err) }()
+
+	period, err := parsePeriod(from, to)
+	if err != nil {
+		return nil, err
+	}
+	end := period.To.AddDate(0, 0, 1)
+
+	tenant, err := s.repo.GetTenantByID(ctx, tenantID)
+	if err != nil {
+		return nil, notFound("tenant not found")
+	}
+	codes := parseTenantSettings(tenant.Settings).AccountCodes
+
+	reports, err := s.repo.GetApprovedReportsInPeriod(ctx, tenantID, period.From, end)
+	if err != nil {
+		return nil, err
+	}
+	expenses, err := s.repo.GetExpensesInPeriod(ctx, tenantID, period.From, end)
+	if err != nil {
+		return nil, err
+	}
+	deposits, err := s.repo.GetDepositsInPeriod(ctx, tenantID, period.From, end)
+	if err != nil {
+		return nil, err
+	}
+
+	journal := &Journal{Period: period, Lines: []JournalLine{}}
+	book := func(date, reference, description, debit, credit string, amount money.Amount) {
+		journal.Lines = append(journal.Lines,
+			JournalLine{Date: date, Reference: reference, Account: debit, Description: description, Debit: amount},
+			JournalLine{Date: date, Reference: reference, Account: credit, Description: description, Credit: amount},
+		)
+	}
+
+	for _, report := range reports {
+		if report.Earnings == 0 {
+			continue
+		}
+		description := fmt.Sprintf("Earnings %s week of %s", report.Taxi.LicensePlate, report.WeekStartDate.Format("2006-01-02"))
+		book(report.WeekStartDate.Format("2006-01-02"), fmt.Sprintf("REP-%d", report.ID), description,
+			codes.CashCode(), codes.RevenueCode(), report.Earnings)
+	}
+	for _, expense := range expenses {
+		description := expense.Category
+		if expense.Reason != "" {
+			description += ": " + expense.Reason
+		}
+		book(expense.Date.Format("2006-01-02"), fmt.Sprintf("EXP-%d", expense.ID), description,
+			codes.ExpenseCode(expense.Category), codes.CashCode(), expense.Amount)
+	}
+	for _, deposit := range deposits {
+		description := "Bank deposit"
+		if deposit.Account != nil {
+			description += " " + deposit.Account.Label
+		}
+		book(deposit.DepositDate.Format("2006-01-02"), fmt.Sprintf("DEP-%d", deposit.ID), description,
+			codes.BankCode(), codes.CashCode(), deposit.Amount)
+	}
+
+	// Dates are YYYY-MM-DD so they sort as strings; each entry's lines stay together
+	slices.SortStableFunc(journal.Lines, func(a, b JournalLine) int { return cmp.Compare(a.Date, b.Date) })
+
+	return journal, nil
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+
+	"go.uber.org/mock/gomock"
+)
+
+type analyticsRepoMock struct {
+	*mocks.MockAnalyticsRepo
+	*mocks.MockTenantRepo
+}
+
+func TestJournalUsesTenantAccountCodes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := analyticsRepoMock{MockAnalyticsRepo: mocks.NewMockAnalyticsRepo(ctrl), MockTenantRepo: mocks.NewMockTenantRepo(ctrl)}
+	svc := NewAnalyticsService(repo)
+
+	week := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	repo.MockTenantRepo.EXPECT().GetTenantByID(gomock.Any(), uint(1)).
+		Return(&repository.Tenant{ID: 1, Settings: `{"account_codes":{"cash":"530","expense":"625","expenses":{"fuel":"606"}}}`}, nil)
+	repo.MockAnalyticsRepo.EXPECT().GetApprovedReportsInPeriod(gomock.Any(), uint(1), gomock.Any(), gomock.Any()).
+		Return([]repository.WeeklyReport{{ID: 7, WeekStartDate: week, Earnings: 900_00}}, nil)
+	repo.MockAnalyticsRepo.EXPECT().GetExpensesInPeriod(gomock.Any(), uint(1), gomock.Any(), gomock.Any()).
+		Return([]repository.Expense{
+			{ID: 8, Date: week.AddDate(0, 0, 3), Category: "cleaning", Amount: 20_00},
+			{ID: 9, Date: week.AddDate(0, 0, 1), Category: "fuel", Amount: 60_00},
+		}, nil)
+	repo.MockAnalyticsRepo.EXPECT().GetDepositsInPeriod(gomock.Any(), uint(1), gomock.Any(), gomock.Any()).
+		Return(nil, nil)
+
+	journal, err := svc.GetJournal(context.Background(), 1, "2024-03-01", "2024-03-31")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []struct {
+		reference, account string
+		debit, credit      money.Amount
+	}{
+		{"REP-7", "530", 900_00, 0},
+		{"REP-7", "4000", 0, 900_00},
+		{"EXP-9", "606", 60_00, 0},
+		{"EXP-9", "530", 0, 60_00},
+		{"EXP-8", "625", 20_00, 0},
+		{"EXP-8", "530", 0, 20_00},
+	}
+	if len(journal.Lines) != len(want) {
+		t.Fatalf("expected %d lines, got %+v", len(want), journal.Lines)
+	}
+	for i, line := range journal.Lines {
+		if line.Reference != want[i].reference || line.Account != want[i].account || line.Debit != want[i].debit || line.Credit != want[i].credit {
+			t.Fatalf("line %d: expected %+v, got %+v", i, want[i], line)
+		}
+	}
+}
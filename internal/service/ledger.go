@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/permissions"
+	"taxifleet/backend/internal/repository"
+)
+
+// LedgerRepository is the data access LedgerService depends on
+type LedgerRepository interface {
+	repository.DriverLedgerRepo
+	repository.UserRepo
+}
+
+// LedgerService keeps track of the money owners lend drivers and how it is
+// paid back
+type LedgerService struct {
+	repo LedgerRepository
+}
+
+func NewLedgerService(repo LedgerRepository) *LedgerService {
+	return &LedgerService{repo: repo}
+}
+
+type RecordLedgerEntryRequest struct {
+	DriverID uint         `json:"driver_id" binding:"required"`
+	Type     string       `json:"type" binding:"required,oneof=advance repayment"`
+	Amount   money.Amount `json:"amount" binding:"required,amount"`
+	Notes    string       `json:"notes"`
+}
+
+// DriverLedger is a driver's balance with the entries it is made of, latest first
+type DriverLedger struct {
+	DriverID uint                           `json:"driver_id"`
+	Balance  money.Amount                   `json:"balance"` // What the driver owes; never negative
+	Entries  []repository.DriverLedgerEntry `json:"entries"`
+}
+
+// RecordEntry records an advance to a driver or a repayment made outside of
+// weekly settlements
+func (s *LedgerService) RecordEntry(ctx context.Context, tenantID uint, userID uint, req RecordLedgerEntryRequest) (*repository.DriverLedgerEntry, error) {
+	driver, err := s.repo.GetUserByID(ctx, req.DriverID)
+	if err != nil || driver.TenantID != tenantID {
+		return nil, notFound("driver not found")
+	}
+
+	if req.Type == "repayment" {
+		balance, err := s.repo.GetDriverBalance(ctx, req.DriverID)
+		if err != nil {
+			return nil, err
+		}
+		if req.Amount > balance {
+			return nil, fmt.Errorf("repayment exceeds the driver's balance of %s", balance)
+		}
+	}
+
+	entry := &repository.DriverLedgerEntry{
+		TenantID:    tenantID,
+		DriverID:    req.DriverID,
+		Type:        req.Type,
+		Amount:      req.Amount,
+		Notes:       req.Notes,
+		CreatedByID: userID,
+	}
+	if err := s.repo.CreateLedgerEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// GetDriverLedger returns a driver's ledger. Drivers may only see their own;
+// seeing others' takes the permission to view deposits.
+func (s *LedgerService) GetDriverLedger(ctx context.Context, tenantID uint, userID uint, permission int, driverID uint) (*DriverLedger, error) {
+	if driverID != userID && !permissions.HasPermission(permission, permissions.PermissionViewDeposits) {
+		return nil, forbidden("you can only see your own ledger")
+	}
+	driver, err := s.repo.GetUserByID(ctx, driverID)
+	if err != nil || driver.TenantID != tenantID {
+		return nil, notFound("driver not found")
+	}
+
+	balance, err := s.repo.GetDriverBalance(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.repo.GetLedgerEntries(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+	return &DriverLedger{DriverID: driverID, Balance: balance, Entries: entries}, nil
+}
+
+// ListBalances returns the balance of every driver with ledger entries
+func (s *LedgerService) ListBalances(ctx context.Context, tenantID uint) ([]repository.DriverBalance, error) {
+	return s.repo.GetDriverBalances(ctx, tenantID)
+}
+
+// offsetDriverDebt keeps as much of an approved report's driver share as the
+// driver owes, up to the whole share, and records it as an offset entry
+func offsetDriverDebt(ctx context.Context, repo repository.DriverLedgerRepo, report *repository.WeeklyReport, approvedByID uint) error {
+	if report.DriverShare == nil || *report.DriverShare <= 0 {
+		return nil
+	}
+	balance, err := repo.GetDriverBalance(ctx, report.DriverID)
+	if err != nil {
+		return err
+	}
+	offset := min(balance, *report.DriverShare)
+	if offset <= 0 {
+		return nil
+	}
+
+	reportID := report.ID
+	if err := repo.CreateLedgerEntry(ctx, &repository.DriverLedgerEntry{
+		TenantID:    report.TenantID,
+		DriverID:    report.DriverID,
+		Type:        "offset",
+		Amount:      offset,
+		ReportID:    &reportID,
+		Notes:       "Offset against the report for the week of " + report.WeekStartDate.Format("2006-01-02"),
+		CreatedByID: approvedByID,
+	}); err != nil {
+		return err
+	}
+	report.LedgerOffset = &offset
+	return nil
+}
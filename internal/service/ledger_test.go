@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+
+	"go.uber.org/mock/gomock"
+)
+
+type ledgerRepoMock struct {
+	*mocks.MockDriverLedgerRepo
+	*mocks.MockUserRepo
+}
+
+func newLedgerServiceMock(t *testing.T) (*LedgerService, ledgerRepoMock) {
+	ctrl := gomock.NewController(t)
+	repo := ledgerRepoMock{
+		MockDriverLedgerRepo: mocks.NewMockDriverLedgerRepo(ctrl),
+		MockUserRepo:         mocks.NewMockUserRepo(ctrl),
+	}
+	return NewLedgerService(repo), repo
+}
+
+func TestLedgerRepaymentCannotExceedBalance(t *testing.T) {
+	svc, repo := newLedgerServiceMock(t)
+	repo.MockUserRepo.EXPECT().GetUserByID(gomock.Any(), uint(9)).Return(&repository.User{ID: 9, TenantID: 1}, nil)
+	repo.MockDriverLedgerRepo.EXPECT().GetDriverBalance(gomock.Any(), uint(9)).Return(money.Amount(100_00), nil)
+
+	_, err := svc.RecordEntry(context.Background(), 1, 2, RecordLedgerEntryRequest{DriverID: 9, Type: "repayment", Amount: 150_00})
+	if err == nil {
+		t.Fatal("expected a repayment above the balance to be refused")
+	}
+}
+
+func TestOffsetDriverDebt(t *testing.T) {
+	tests := []struct {
+		name    string
+		balance money.Amount
+		share   money.Amount
+		offset  money.Amount // 0 when no offset is recorded
+	}{
+		{"debt below the share", 120_00, 500_00, 120_00},
+		{"debt above the share", 800_00, 500_00, 500_00},
+		{"no debt", 0, 500_00, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockDriverLedgerRepo(ctrl)
+			report := &repository.WeeklyReport{ID: 42, TenantID: 1, DriverID: 9, DriverShare: &tt.share}
+
+			repo.EXPECT().GetDriverBalance(gomock.Any(), uint(9)).Return(tt.balance, nil)
+			if tt.offset > 0 {
+				repo.EXPECT().CreateLedgerEntry(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, entry *repository.DriverLedgerEntry) error {
+					if entry.Type != "offset" || entry.Amount != tt.offset || entry.ReportID == nil || *entry.ReportID != 42 {
+						t.Fatalf("unexpected entry %+v", entry)
+					}
+					return nil
+				})
+			}
+
+			if err := offsetDriverDebt(context.Background(), repo, report, 2); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.offset == 0 && report.LedgerOffset != nil || tt.offset > 0 && (report.LedgerOffset == nil || *report.LedgerOffset != tt.offset) {
+				t.Fatalf("expected an offset of %v, got %v", tt.offset, report.LedgerOffset)
+			}
+		})
+	}
+}
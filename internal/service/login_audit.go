@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"taxifleet/backend/internal/push"
+	"taxifleet/backend/internal/repository"
+)
+
+const (
+	// loginMethodPassword is the audited method of password logins; OAuth
+	// logins are audited with their provider name
+	loginMethodPassword = "password"
+
+	// loginHistoryLimit is how many attempts the login history shows
+	loginHistoryLimit = 50
+)
+
+// LoginClient describes where a login attempt comes from
+type LoginClient struct {
+	IPAddress string
+	UserAgent string
+	DeviceID  string // Optional X-Device-ID header sent by the apps
+}
+
+// Fingerprint identifies the device by its user agent and, if sent, device ID
+func (c LoginClient) Fingerprint() string {
+	sum := sha256.Sum256([]byte(c.UserAgent + "\x00" + c.DeviceID))
+	return hex.EncodeToString(sum[:])
+}
+
+// recordFailedLogin audits a rejected attempt; userID is nil when no user matched
+func (s *AuthService) recordFailedLogin(ctx context.Context, userID *uint, identifier, method string, client LoginClient, reason string) error {
+	return s.repo.CreateLoginAudit(ctx, &repository.LoginAudit{
+		UserID:            userID,
+		Identifier:        identifier,
+		Method:            method,
+		FailureReason:     reason,
+		IPAddress:         client.IPAddress,
+		UserAgent:         client.UserAgent,
+		DeviceFingerprint: client.Fingerprint(),
+	})
+}
+
+// completeLogin audits a successful login, warns the user if it came from a
+// device they never signed in from, and starts the session
+func (s *AuthService) completeLogin(ctx context.Context, user *repository.User, identifier, method string, client LoginClient) (*AuthResponse, error) {
+	fingerprint := client.Fingerprint()
+	knownDevice, loggedInBefore, err := s.repo.HasLoginFromDevice(ctx, user.ID, fingerprint)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.CreateLoginAudit(ctx, &repository.LoginAudit{
+		UserID:            &user.ID,
+		Identifier:        identifier,
+		Method:            method,
+		Success:           true,
+		IPAddress:         client.IPAddress,
+		UserAgent:         client.UserAgent,
+		DeviceFingerprint: fingerprint,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The very first login has nothing to compare against
+	if loggedInBefore && !knownDevice && s.notifications != nil {
+		s.notifications.NotifyUser(ctx, user.ID, push.Message{
+			Title: "New sign-in to your account",
+			Body:  fmt.Sprintf("Your account was signed in from a new device (IP %s). If this wasn't you, change your password.", client.IPAddress),
+			Data:  map[string]string{"type": "new_device_login"},
+		})
+	}
+
+	return s.startSession(ctx, user)
+}
+
+// GetLoginHistory returns the user's most recent login attempts
+func (s *AuthService) GetLoginHistory(ctx context.Context, userID uint) ([]repository.LoginAudit, error) {
+	return s.repo.GetLoginAudits(ctx, userID, loginHistoryLimit)
+}
@@ -0,0 +1,366 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/repository"
+)
+
+// MaintenanceRepository is the data access MaintenanceService depends on
+type MaintenanceRepository interface {
+	repository.Transactor
+	repository.MaintenanceRepo
+	repository.TaxiRepo
+}
+
+type MaintenanceService struct {
+	repo          MaintenanceRepository
+	notifications *NotificationService
+}
+
+func NewMaintenanceService(repo MaintenanceRepository, notifications *NotificationService) *MaintenanceService {
+	return &MaintenanceService{repo: repo, notifications: notifications}
+}
+
+type CreateScheduleRequest struct {
+	TaxiID       uint   `json:"taxi_id" binding:"required"`
+	Task         string `json:"task" binding:"required"`
+	IntervalKm   int    `json:"interval_km" binding:"min=0"`
+	IntervalDays int    `json:"interval_days" binding:"min=0"`
+	LastDoneAt   string `json:"last_done_at"` // Defaults to today
+	LastDoneKm   *int   `json:"last_done_km"` // Defaults to the taxi's current mileage
+}
+
+type UpdateScheduleRequest struct {
+	Task         string `json:"task"`
+	IntervalKm   *int   `json:"interval_km"`
+	IntervalDays *int   `json:"interval_days"`
+}
+
+type CompleteScheduleRequest struct {
+	Date    string       `json:"date"`    // Defaults to today
+	Mileage int          `json:"mileage"` // Odometer at completion, defaults to the taxi's current mileage
+	Cost    money.Amount `json:"cost"`
+	Notes   string       `json:"notes"`
+}
+
+type CreateMaintenanceLogRequest struct {
+	TaxiID      uint         `json:"taxi_id" binding:"required"`
+	Description string       `json:"description" binding:"required"`
+	Cost        money.Amount `json:"cost"`
+	Date        string       `json:"date" binding:"required"`
+}
+
+// MaintenanceDue describes a schedule whose km or day interval has been reached
+type MaintenanceDue struct {
+	Schedule    repository.MaintenanceSchedule `json:"schedule"`
+	DueByKm     bool                           `json:"due_by_km"`
+	DueByDate   bool                           `json:"due_by_date"`
+	KmOverdue   int                            `json:"km_overdue"`
+	DaysOverdue int                            `json:"days_overdue"`
+}
+
+type MechanicDashboard struct {
+	TaxisInMaintenance int                         `json:"taxis_in_maintenance"`
+	MaintenanceDue     []MaintenanceDue            `json:"maintenance_due"`
+	RecentLogs         []repository.MaintenanceLog `json:"recent_logs"`
+}
+
+// checkDue evaluates a schedule against its taxi's mileage and the current date
+func checkDue(schedule repository.MaintenanceSchedule, now time.Time) (MaintenanceDue, bool) {
+	due := MaintenanceDue{Schedule: schedule}
+
+	if schedule.IntervalKm > 0 {
+		driven := schedule.Taxi.Mileage - schedule.LastDoneKm
+		if driven >= schedule.IntervalKm {
+			due.DueByKm = true
+			due.KmOverdue = driven - schedule.IntervalKm
+		}
+	}
+
+	if schedule.IntervalDays > 0 {
+		dueAt := schedule.LastDoneAt.AddDate(0, 0, schedule.IntervalDays)
+		if !now.Before(dueAt) {
+			due.DueByDate = true
+			due.DaysOverdue = int(now.Sub(dueAt).Hours() / 24)
+		}
+	}
+
+	return due, due.DueByKm || due.DueByDate
+}
+
+func parseOptionalDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Now(), nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, invalid("invalid date format")
+	}
+	return date, nil
+}
+
+func (s *MaintenanceService) getTenantTaxi(ctx context.Context, taxiID uint, tenantID uint) (*repository.Taxi, error) {
+	taxi, err := s.repo.GetTaxiByID(ctx, taxiID)
+	if err != nil || taxi.TenantID != tenantID {
+		return nil, notFound("taxi not found")
+	}
+	return taxi, nil
+}
+
+func (s *MaintenanceService) CreateSchedule(ctx context.Context, tenantID uint, req CreateScheduleRequest) (*repository.MaintenanceSchedule, error) {
+	if req.IntervalKm == 0 && req.IntervalDays == 0 {
+		return nil, invalid("interval_km or interval_days is required")
+	}
+
+	taxi, err := s.getTenantTaxi(ctx, req.TaxiID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	lastDoneAt, err := parseOptionalDate(req.LastDoneAt)
+	if err != nil {
+		return nil, err
+	}
+
+	lastDoneKm := taxi.Mileage
+	if req.LastDoneKm != nil {
+		lastDoneKm = *req.LastDoneKm
+	}
+
+	schedule := &repository.MaintenanceSchedule{
+		TenantID:     tenantID,
+		TaxiID:       taxi.ID,
+		Task:         req.Task,
+		IntervalKm:   req.IntervalKm,
+		IntervalDays: req.IntervalDays,
+		LastDoneAt:   lastDoneAt,
+		LastDoneKm:   lastDoneKm,
+	}
+
+	if err := s.repo.CreateMaintenanceSchedule(ctx, schedule); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetMaintenanceScheduleByID(ctx, schedule.ID)
+}
+
+func (s *MaintenanceService) GetSchedule(ctx context.Context, id uint, tenantID uint) (*repository.MaintenanceSchedule, error) {
+	schedule, err := s.repo.GetMaintenanceScheduleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if schedule.TenantID != tenantID {
+		return nil, notFound("schedule not found")
+	}
+
+	return schedule, nil
+}
+
+func (s *MaintenanceService) ListSchedules(ctx context.Context, tenantID uint) ([]repository.MaintenanceSchedule, error) {
+	return s.repo.GetMaintenanceSchedulesByTenant(ctx, tenantID)
+}
+
+func (s *MaintenanceService) UpdateSchedule(ctx context.Context, id uint, tenantID uint, req UpdateScheduleRequest) (*repository.MaintenanceSchedule, error) {
+	schedule, err := s.GetSchedule(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Task != "" {
+		schedule.Task = req.Task
+	}
+	if req.IntervalKm != nil {
+		schedule.IntervalKm = *req.IntervalKm
+	}
+	if req.IntervalDays != nil {
+		schedule.IntervalDays = *req.IntervalDays
+	}
+	if schedule.IntervalKm <= 0 && schedule.IntervalDays <= 0 {
+		return nil, invalid("interval_km or interval_days is required")
+	}
+
+	if err := s.repo.UpdateMaintenanceSchedule(ctx, schedule); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetMaintenanceScheduleByID(ctx, schedule.ID)
+}
+
+func (s *MaintenanceService) DeleteSchedule(ctx context.Context, id uint, tenantID uint) error {
+	if _, err := s.GetSchedule(ctx, id, tenantID); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteMaintenanceSchedule(ctx, id)
+}
+
+// CompleteSchedule records the work as a maintenance log and restarts the intervals
+func (s *MaintenanceService) CompleteSchedule(ctx context.Context, id uint, tenantID uint, mechanicID uint, req CompleteScheduleRequest) (*repository.MaintenanceLog, error) {
+	schedule, err := s.GetSchedule(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	date, err := parseOptionalDate(req.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	taxi := &schedule.Taxi
+	mileage := taxi.Mileage
+	if req.Mileage > 0 {
+		mileage = req.Mileage
+	}
+
+	description := schedule.Task
+	if req.Notes != "" {
+		description += ": " + req.Notes
+	}
+
+	log := &repository.MaintenanceLog{
+		TenantID:    tenantID,
+		TaxiID:      schedule.TaxiID,
+		Description: description,
+		Cost:        req.Cost,
+		Date:        date,
+		MechanicID:  &mechanicID,
+	}
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateMaintenanceLog(ctx, log); err != nil {
+			return err
+		}
+
+		// A reading taken at completion is the freshest odometer value we have
+		if mileage > taxi.Mileage {
+			taxi.Mileage = mileage
+			if err := s.repo.UpdateTaxi(ctx, taxi); err != nil {
+				return err
+			}
+		}
+
+		schedule.LastDoneAt = date
+		schedule.LastDoneKm = mileage
+		schedule.NotifiedAt = nil
+		return s.repo.UpdateMaintenanceSchedule(ctx, schedule)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetMaintenanceLogByID(ctx, log.ID)
+}
+
+func (s *MaintenanceService) ListLogs(ctx context.Context, tenantID uint) ([]repository.MaintenanceLog, error) {
+	return s.repo.GetMaintenanceLogsByTenant(ctx, tenantID)
+}
+
+func (s *MaintenanceService) CreateLog(ctx context.Context, tenantID uint, mechanicID uint, req CreateMaintenanceLogRequest) (*repository.MaintenanceLog, error) {
+	if _, err := s.getTenantTaxi(ctx, req.TaxiID, tenantID); err != nil {
+		return nil, err
+	}
+
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return nil, invalid("invalid date format")
+	}
+
+	log := &repository.MaintenanceLog{
+		TenantID:    tenantID,
+		TaxiID:      req.TaxiID,
+		Description: req.Description,
+		Cost:        req.Cost,
+		Date:        date,
+		MechanicID:  &mechanicID,
+	}
+	if err := s.repo.CreateMaintenanceLog(ctx, log); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetMaintenanceLogByID(ctx, log.ID)
+}
+
+// GetDue returns every schedule of the tenant that is currently due
+func (s *MaintenanceService) GetDue(ctx context.Context, tenantID uint) ([]MaintenanceDue, error) {
+	schedules, err := s.repo.GetMaintenanceSchedulesByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	due := []MaintenanceDue{}
+	for _, schedule := range schedules {
+		if item, ok := checkDue(schedule, now); ok {
+			due = append(due, item)
+		}
+	}
+
+	return due, nil
+}
+
+func (s *MaintenanceService) GetMechanicDashboard(ctx context.Context, tenantID uint) (*MechanicDashboard, error) {
+	taxis, err := s.repo.GetTaxisByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	inMaintenance := 0
+	for _, taxi := range taxis {
+		if taxi.Status == "maintenance" {
+			inMaintenance++
+		}
+	}
+
+	due, err := s.GetDue(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := s.repo.GetMaintenanceLogsByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if len(logs) > 10 {
+		logs = logs[:10]
+	}
+
+	return &MechanicDashboard{
+		TaxisInMaintenance: inMaintenance,
+		MaintenanceDue:     due,
+		RecentLogs:         logs,
+	}, nil
+}
+
+// NotifyDue sends a single alert per due schedule to the users of the tenant
+// who maintain taxis. Alerts are sent again only after the task is completed.
+func (s *MaintenanceService) NotifyDue(ctx context.Context) error {
+	schedules, err := s.repo.GetAllMaintenanceSchedules(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i := range schedules {
+		schedule := &schedules[i]
+		if schedule.NotifiedAt != nil {
+			continue
+		}
+		if _, ok := checkDue(*schedule, now); !ok {
+			continue
+		}
+
+		if err := s.notifications.NotifyMaintenanceDue(ctx, schedule); err != nil {
+			return err
+		}
+
+		schedule.NotifiedAt = &now
+		if err := s.repo.UpdateMaintenanceSchedule(ctx, schedule); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+
+	"go.uber.org/mock/gomock"
+)
+
+type maintenanceRepoMock struct {
+	*mocks.MockTransactor
+	*mocks.MockMaintenanceRepo
+	*mocks.MockTaxiRepo
+}
+
+func newMaintenanceServiceMock(t *testing.T) (*MaintenanceService, maintenanceRepoMock) {
+	ctrl := gomock.NewController(t)
+	repo := maintenanceRepoMock{
+		MockTransactor:      mocks.NewMockTransactor(ctrl),
+		MockMaintenanceRepo: mocks.NewMockMaintenanceRepo(ctrl),
+		MockTaxiRepo:        mocks.NewMockTaxiRepo(ctrl),
+	}
+	return NewMaintenanceService(repo, nil), repo
+}
+
+func TestCheckDue(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		schedule   repository.MaintenanceSchedule
+		wantDue    bool
+		wantKm     int
+		wantDays   int
+		wantByKm   bool
+		wantByDate bool
+	}{
+		{
+			name: "not due",
+			schedule: repository.MaintenanceSchedule{
+				IntervalKm: 10000, LastDoneKm: 50000, IntervalDays: 180, LastDoneAt: now.AddDate(0, -1, 0),
+				Taxi: repository.Taxi{Mileage: 55000},
+			},
+		},
+		{
+			name: "due by km",
+			schedule: repository.MaintenanceSchedule{
+				IntervalKm: 10000, LastDoneKm: 50000, LastDoneAt: now,
+				Taxi: repository.Taxi{Mileage: 61500},
+			},
+			wantDue: true, wantByKm: true, wantKm: 1500,
+		},
+		{
+			name: "due by date",
+			schedule: repository.MaintenanceSchedule{
+				IntervalDays: 30, LastDoneAt: now.AddDate(0, 0, -40),
+			},
+			wantDue: true, wantByDate: true, wantDays: 10,
+		},
+		{
+			name: "disabled intervals are ignored",
+			schedule: repository.MaintenanceSchedule{
+				LastDoneAt: now.AddDate(-5, 0, 0),
+				Taxi:       repository.Taxi{Mileage: 999999},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			due, ok := checkDue(tt.schedule, now)
+			if ok != tt.wantDue {
+				t.Fatalf("due = %v, want %v", ok, tt.wantDue)
+			}
+			if due.DueByKm != tt.wantByKm || due.DueByDate != tt.wantByDate {
+				t.Errorf("due by km/date = %v/%v, want %v/%v", due.DueByKm, due.DueByDate, tt.wantByKm, tt.wantByDate)
+			}
+			if due.KmOverdue != tt.wantKm || due.DaysOverdue != tt.wantDays {
+				t.Errorf("overdue km/days = %d/%d, want %d/%d", due.KmOverdue, due.DaysOverdue, tt.wantKm, tt.wantDays)
+			}
+		})
+	}
+}
+
+func TestCompleteScheduleWritesInTransaction(t *testing.T) {
+	svc, repo := newMaintenanceServiceMock(t)
+	ctx := context.Background()
+
+	repo.MockMaintenanceRepo.EXPECT().GetMaintenanceScheduleByID(ctx, uint(3)).Return(&repository.MaintenanceSchedule{
+		ID: 3, TenantID: 1, TaxiID: 5, Task: "Oil change", Taxi: repository.Taxi{ID: 5, TenantID: 1, Mileage: 50000},
+	}, nil)
+	inTx := false
+	repo.MockTransactor.EXPECT().InTransaction(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		inTx = true
+		defer func() { inTx = false }()
+		return fn(ctx)
+	})
+	repo.MockMaintenanceRepo.EXPECT().CreateMaintenanceLog(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, log *repository.MaintenanceLog) error {
+		if !inTx {
+			t.Fatal("expected the log created in the transaction")
+		}
+		log.ID = 9
+		return nil
+	})
+	repo.MockTaxiRepo.EXPECT().UpdateTaxi(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, taxi *repository.Taxi) error {
+		if !inTx || taxi.Mileage != 51200 {
+			t.Fatalf("expected the mileage updated in the transaction, got %d", taxi.Mileage)
+		}
+		return nil
+	})
+	repo.MockMaintenanceRepo.EXPECT().UpdateMaintenanceSchedule(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, schedule *repository.MaintenanceSchedule) error {
+		if !inTx || schedule.LastDoneKm != 51200 {
+			t.Fatalf("expected the schedule restarted in the transaction, got %+v", schedule)
+		}
+		return nil
+	})
+	repo.MockMaintenanceRepo.EXPECT().GetMaintenanceLogByID(ctx, uint(9)).Return(&repository.MaintenanceLog{ID: 9}, nil)
+
+	if _, err := svc.CompleteSchedule(ctx, 3, 1, 7, CompleteScheduleRequest{Mileage: 51200}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCompleteScheduleFailsWhenTaxiChanged(t *testing.T) {
+	svc, repo := newMaintenanceServiceMock(t)
+	ctx := context.Background()
+
+	repo.MockMaintenanceRepo.EXPECT().GetMaintenanceScheduleByID(ctx, uint(3)).Return(&repository.MaintenanceSchedule{
+		ID: 3, TenantID: 1, TaxiID: 5, Task: "Oil change", Taxi: repository.Taxi{ID: 5, TenantID: 1, Mileage: 50000},
+	}, nil)
+	repo.MockTransactor.EXPECT().InTransaction(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	})
+	repo.MockMaintenanceRepo.EXPECT().CreateMaintenanceLog(gomock.Any(), gomock.Any()).Return(nil)
+	repo.MockTaxiRepo.EXPECT().UpdateTaxi(gomock.Any(), gomock.Any()).Return(repository.ErrVersionConflict)
+
+	// The schedule is left as it was and the log rolled back with the transaction
+	if _, err := svc.CompleteSchedule(ctx, 3, 1, 7, CompleteScheduleRequest{Mileage: 51200}); !errors.Is(err, repository.ErrVersionConflict) {
+		t.Fatalf("expected the version conflict, got %v", err)
+	}
+}
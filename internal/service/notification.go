@@ -0,0 +1,415 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"taxifleet/backend/internal/logging"
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/permissions"
+	"taxifleet/backend/internal/push"
+	"taxifleet/backend/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// NotificationRepository is the data access NotificationService depends on
+type NotificationRepository interface {
+	repository.DeviceTokenRepo
+	repository.InboxRepo
+	repository.UserRepo
+	repository.TaxiRepo
+}
+
+// ErrNotificationNotFound is returned for notifications missing from the
+// user's inbox
+var ErrNotificationNotFound = errors.New("notification not found")
+
+type NotificationService struct {
+	repo   NotificationRepository
+	sender push.Sender
+	logger *logrus.Logger
+}
+
+func NewNotificationService(repo NotificationRepository, sender push.Sender, logger *logrus.Logger) *NotificationService {
+	return &NotificationService{repo: repo, sender: sender, logger: logger}
+}
+
+type RegisterDeviceRequest struct {
+	Token    string `json:"token" binding:"required" sanitize:"-"`
+	Platform string `json:"platform" binding:"required,oneof=android ios web"`
+}
+
+// AnnouncementRequest is a message from the platform admins to every user of
+// a tenant, or of all tenants
+type AnnouncementRequest struct {
+	TenantID *uint  `json:"tenant_id"` // Nil announces to every tenant
+	Title    string `json:"title" binding:"required,max=255"`
+	Body     string `json:"body" binding:"required"`
+}
+
+type ShiftReminderRequest struct {
+	DriverIDs []uint `json:"driver_ids"` // Empty means every driver with an assigned active taxi
+	Message   string `json:"message"`
+}
+
+func (s *NotificationService) RegisterDevice(ctx context.Context, tenantID uint, userID uint, req RegisterDeviceRequest) (*repository.DeviceToken, error) {
+	device := &repository.DeviceToken{
+		TenantID:   tenantID,
+		UserID:     userID,
+		Token:      req.Token,
+		Platform:   req.Platform,
+		LastSeenAt: time.Now(),
+	}
+
+	if err := s.repo.SaveDeviceToken(ctx, device); err != nil {
+		return nil, err
+	}
+
+	return device, nil
+}
+
+func (s *NotificationService) UnregisterDevice(ctx context.Context, userID uint, token string) error {
+	return s.repo.DeleteUserDeviceToken(ctx, userID, token)
+}
+
+// NotifyUser adds a message to the user's inbox and pushes it to every device
+// of the user in the background, so request handlers are never blocked by FCM
+// latency. Sending outlives the request, so it is detached from the request's
+// cancellation and transaction.
+func (s *NotificationService) NotifyUser(ctx context.Context, userID uint, msg push.Message) {
+	go s.send(repository.Detach(ctx), userID, msg)
+}
+
+func (s *NotificationService) send(ctx context.Context, userID uint, msg push.Message) {
+	notification := &repository.Notification{
+		UserID: userID,
+		Type:   cmp.Or(msg.Data["type"], "general"),
+		Title:  msg.Title,
+		Body:   msg.Body,
+		Data:   msg.Data,
+	}
+	if err := s.repo.CreateNotification(ctx, notification); err != nil {
+		logging.Entry(ctx, s.logger).WithError(err).WithField("user_id", userID).Error("Failed to store notification")
+	}
+
+	devices, err := s.repo.GetDeviceTokensByUser(ctx, userID)
+	if err != nil {
+		logging.Entry(ctx, s.logger).WithError(err).WithField("user_id", userID).Error("Failed to load device tokens")
+		return
+	}
+
+	for _, device := range devices {
+		sendCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		err := s.sender.Send(sendCtx, device.Token, msg)
+		cancel()
+
+		if errors.Is(err, push.ErrInvalidToken) {
+			if err := s.repo.DeleteDeviceToken(ctx, device.Token); err != nil {
+				logging.Entry(ctx, s.logger).WithError(err).Warn("Failed to remove invalid device token")
+			}
+			continue
+		}
+		if err != nil {
+			logging.Entry(ctx, s.logger).WithError(err).WithFields(logrus.Fields{
+				"user_id":  userID,
+				"platform": device.Platform,
+			}).Warn("Failed to send push notification")
+		}
+	}
+}
+
+// ListNotifications returns the user's latest notifications, newest first
+func (s *NotificationService) ListNotifications(ctx context.Context, userID uint, unreadOnly bool, limit int) ([]repository.Notification, error) {
+	return s.repo.GetNotifications(ctx, userID, unreadOnly, limit)
+}
+
+func (s *NotificationService) UnreadCount(ctx context.Context, userID uint) (int64, error) {
+	return s.repo.CountUnreadNotifications(ctx, userID)
+}
+
+func (s *NotificationService) MarkRead(ctx context.Context, userID, id uint) error {
+	found, err := s.repo.MarkNotificationRead(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+// MarkAllRead marks the user's whole inbox read and returns how many
+// notifications were unread
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID uint) (int64, error) {
+	return s.repo.MarkAllNotificationsRead(ctx, userID)
+}
+
+// Announce notifies every active user of the tenant, or of all tenants, and
+// returns the number of users notified
+func (s *NotificationService) Announce(ctx context.Context, req AnnouncementRequest) (int, error) {
+	var users []repository.User
+	var err error
+	if req.TenantID != nil {
+		users, err = s.repo.GetUsersByTenant(ctx, *req.TenantID)
+	} else {
+		users, err = s.repo.GetAllUsers(ctx)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, user := range users {
+		if !user.Active {
+			continue
+		}
+		s.NotifyUser(ctx, user.ID, push.Message{
+			Title: req.Title,
+			Body:  req.Body,
+			Data:  map[string]string{"type": "announcement"},
+		})
+		count++
+	}
+	return count, nil
+}
+
+// NotifyUsersWithPermission pushes a message to every active user of the tenant
+// holding the given permission
+func (s *NotificationService) NotifyUsersWithPermission(ctx context.Context, tenantID uint, permission int, msg push.Message) error {
+	users, err := s.repo.GetUsersByTenant(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		if user.Active && permissions.HasPermission(user.Permission, permission) {
+			s.NotifyUser(ctx, user.ID, msg)
+		}
+	}
+	return nil
+}
+
+// NotifyReportStatus tells the driver that their report changed status
+func (s *NotificationService) NotifyReportStatus(ctx context.Context, report *repository.WeeklyReport) {
+	week := report.WeekStartDate.Format("02/01/2006")
+
+	var title, body string
+	switch report.Status {
+	case "approved":
+		title = "Report approved"
+		body = fmt.Sprintf("Your weekly report for %s has been approved.", week)
+	case "rejected":
+		title = "Report rejected"
+		body = fmt.Sprintf("Your weekly report for %s has been rejected.", week)
+		if n := len(report.Rejections); n > 0 && report.Rejections[n-1].Reason != "" {
+			body += " Reason: " + report.Rejections[n-1].Reason
+		}
+	default:
+		return
+	}
+
+	s.NotifyUser(ctx, report.DriverID, push.Message{
+		Title: title,
+		Body:  body,
+		Data: map[string]string{
+			"type":      "report_status",
+			"report_id": strconv.FormatUint(uint64(report.ID), 10),
+			"status":    report.Status,
+		},
+	})
+}
+
+// NotifyReportSubmitted tells the users reviewing reports that a driver
+// submitted one. Failures are only logged since the submission succeeded.
+func (s *NotificationService) NotifyReportSubmitted(ctx context.Context, report *repository.WeeklyReport) {
+	err := s.NotifyUsersWithPermission(ctx, report.TenantID, permissions.PermissionEditReports, push.Message{
+		Title: "Report submitted",
+		Body: fmt.Sprintf("%s %s submitted the weekly report for %s.",
+			report.Driver.FirstName, report.Driver.LastName, report.WeekStartDate.Format("02/01/2006")),
+		Data: map[string]string{
+			"type":      "report_submitted",
+			"report_id": strconv.FormatUint(uint64(report.ID), 10),
+		},
+	})
+	if err != nil {
+		logging.Entry(ctx, s.logger).WithError(err).WithField("report_id", report.ID).Error("Failed to send report submitted notification")
+	}
+}
+
+// NotifyAttachmentQuarantined tells the uploader of a report photo that the
+// virus scan rejected it
+func (s *NotificationService) NotifyAttachmentQuarantined(ctx context.Context, attachment *repository.ReportAttachment) {
+	s.NotifyUser(ctx, attachment.UploadedByID, push.Message{
+		Title: "Upload rejected",
+		Body:  fmt.Sprintf("%s was rejected by the virus scan and cannot be downloaded.", attachment.FileName),
+		Data: map[string]string{
+			"type":          "attachment_quarantined",
+			"report_id":     strconv.FormatUint(uint64(attachment.ReportID), 10),
+			"attachment_id": strconv.FormatUint(uint64(attachment.ID), 10),
+		},
+	})
+}
+
+// NotifyExportBundle tells the user who requested an export bundle that it
+// is ready to download or could not be generated
+func (s *NotificationService) NotifyExportBundle(ctx context.Context, bundle *repository.ExportBundle) {
+	period := bundle.PeriodStart.Format("02/01/2006") + " - " + bundle.PeriodEnd.Format("02/01/2006")
+
+	var title, body string
+	switch bundle.Status {
+	case repository.BundleReady:
+		title = "Export ready"
+		body = fmt.Sprintf("Your export for %s is ready to download.", period)
+	case repository.BundleFailed:
+		title = "Export failed"
+		body = fmt.Sprintf("Your export for %s could not be generated. Please try again.", period)
+	default:
+		return
+	}
+
+	id := strconv.FormatUint(uint64(bundle.ID), 10)
+	data := map[string]string{"type": "export_bundle", "bundle_id": id, "status": bundle.Status}
+	if bundle.Status == repository.BundleReady {
+		data["download_url"] = bundleDownloadURL(bundle.ID)
+	}
+	s.NotifyUser(ctx, bundle.RequestedByID, push.Message{Title: title, Body: body, Data: data})
+}
+
+// SendShiftReminders notifies drivers of the tenant about their upcoming shift
+// and returns the number of drivers reminded
+func (s *NotificationService) SendShiftReminders(ctx context.Context, tenantID uint, req ShiftReminderRequest) (int, error) {
+	taxis, err := s.repo.GetTaxisByTenant(ctx, tenantID)
+	if err != nil {
+		return 0, err
+	}
+
+	// Map each assigned driver to their taxi plate for the message
+	plates := make(map[uint]string)
+	for _, taxi := range taxis {
+		if taxi.Status == "active" && taxi.AssignedDriverID != nil {
+			plates[*taxi.AssignedDriverID] = taxi.LicensePlate
+		}
+	}
+
+	driverIDs := req.DriverIDs
+	if len(driverIDs) == 0 {
+		for driverID := range plates {
+			driverIDs = append(driverIDs, driverID)
+		}
+	}
+
+	for _, driverID := range driverIDs {
+		driver, err := s.repo.GetUserByID(ctx, driverID)
+		if err != nil || driver.TenantID != tenantID {
+			return 0, notFound("driver not found")
+		}
+	}
+
+	for _, driverID := range driverIDs {
+		body := req.Message
+		if body == "" {
+			body = "Reminder: your shift is coming up."
+			if plate, ok := plates[driverID]; ok {
+				body = fmt.Sprintf("Reminder: your shift with taxi %s is coming up.", plate)
+			}
+		}
+
+		s.NotifyUser(ctx, driverID, push.Message{
+			Title: "Shift reminder",
+			Body:  body,
+			Data:  map[string]string{"type": "shift_reminder"},
+		})
+	}
+
+	return len(driverIDs), nil
+}
+
+// NotifyMaintenanceDue alerts the users maintaining taxis that a preventive
+// maintenance task is due
+func (s *NotificationService) NotifyMaintenanceDue(ctx context.Context, schedule *repository.MaintenanceSchedule) error {
+	return s.NotifyUsersWithPermission(ctx, schedule.TenantID, permissions.PermissionEditTaxis, push.Message{
+		Title: "Maintenance due",
+		Body:  fmt.Sprintf("%s is due for taxi %s.", schedule.Task, schedule.Taxi.LicensePlate),
+		Data: map[string]string{
+			"type":        "maintenance_due",
+			"schedule_id": strconv.FormatUint(uint64(schedule.ID), 10),
+			"taxi_id":     strconv.FormatUint(uint64(schedule.TaxiID), 10),
+		},
+	})
+}
+
+// NotifyLowStock alerts the users maintaining taxis that a part needs reordering.
+// Failures are only logged since the stock movement itself already succeeded.
+func (s *NotificationService) NotifyLowStock(ctx context.Context, part *repository.Part) {
+	err := s.NotifyUsersWithPermission(ctx, part.TenantID, permissions.PermissionEditTaxis, push.Message{
+		Title: "Low stock",
+		Body:  fmt.Sprintf("%s is running low (%.2f %s left).", part.Name, part.QuantityOnHand, part.Unit),
+		Data: map[string]string{
+			"type":    "low_stock",
+			"part_id": strconv.FormatUint(uint64(part.ID), 10),
+		},
+	})
+	if err != nil {
+		logging.Entry(ctx, s.logger).WithError(err).WithField("part_id", part.ID).Error("Failed to send low stock alert")
+	}
+}
+
+// NotifyBudgetThreshold alerts the users managing expenses that spending
+// reached a share of a budget in a month. Failures are only logged since the
+// expense itself was saved.
+func (s *NotificationService) NotifyBudgetThreshold(ctx context.Context, budget *repository.Budget, month time.Time, percent int, spent money.Amount) {
+	scope := "the fleet"
+	if budget.Taxi != nil {
+		scope = "taxi " + budget.Taxi.LicensePlate
+	}
+	err := s.NotifyUsersWithPermission(ctx, budget.TenantID, permissions.PermissionEditExpenses, push.Message{
+		Title: "Budget alert",
+		Body: fmt.Sprintf("Spending on %s for %s reached %d%% of its budget in %s (%s of %s).",
+			budget.Category, scope, percent, month.Format("01/2006"), spent, budget.Amount),
+		Data: map[string]string{
+			"type":      "budget_threshold",
+			"budget_id": strconv.FormatUint(uint64(budget.ID), 10),
+			"percent":   strconv.Itoa(percent),
+			"month":     month.Format("2006-01"),
+		},
+	})
+	if err != nil {
+		logging.Entry(ctx, s.logger).WithError(err).WithField("budget_id", budget.ID).Error("Failed to send budget alert")
+	}
+}
+
+// NotifyOutsideZone alerts the users managing taxis that a taxi reported a
+// position outside the operating zone. Failures are only logged since the
+// position was stored.
+func (s *NotificationService) NotifyOutsideZone(ctx context.Context, taxi *repository.Taxi, position *repository.TaxiPosition) {
+	s.notifyPosition(ctx, taxi, position, "outside_zone", "Taxi outside zone",
+		fmt.Sprintf("Taxi %s left the operating zone at %s.", taxi.LicensePlate, position.RecordedAt.Format("02/01/2006 15:04")))
+}
+
+// NotifyUnscheduledUse alerts the users managing taxis that a taxi reported a
+// position while no driver was assigned to it
+func (s *NotificationService) NotifyUnscheduledUse(ctx context.Context, taxi *repository.Taxi, position *repository.TaxiPosition) {
+	s.notifyPosition(ctx, taxi, position, "unscheduled_use", "Taxi used without driver",
+		fmt.Sprintf("Taxi %s reported a position at %s while no driver was assigned.", taxi.LicensePlate, position.RecordedAt.Format("02/01/2006 15:04")))
+}
+
+func (s *NotificationService) notifyPosition(ctx context.Context, taxi *repository.Taxi, position *repository.TaxiPosition, kind, title, body string) {
+	err := s.NotifyUsersWithPermission(ctx, taxi.TenantID, permissions.PermissionEditTaxis, push.Message{
+		Title: title,
+		Body:  body,
+		Data: map[string]string{
+			"type":      kind,
+			"taxi_id":   strconv.FormatUint(uint64(taxi.ID), 10),
+			"latitude":  strconv.FormatFloat(position.Latitude, 'f', 6, 64),
+			"longitude": strconv.FormatFloat(position.Longitude, 'f', 6, 64),
+		},
+	})
+	if err != nil {
+		logging.Entry(ctx, s.logger).WithError(err).WithField("taxi_id", taxi.ID).Error("Failed to send position alert")
+	}
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"taxifleet/backend/internal/push"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+
+	"github.com/sirupsen/logrus"
+	"go.uber.org/mock/gomock"
+)
+
+type notificationRepoMock struct {
+	*mocks.MockDeviceTokenRepo
+	*mocks.MockInboxRepo
+	*mocks.MockUserRepo
+	*mocks.MockTaxiRepo
+}
+
+func newNotificationServiceMock(t *testing.T) (*NotificationService, notificationRepoMock) {
+	ctrl := gomock.NewController(t)
+	repo := notificationRepoMock{
+		MockDeviceTokenRepo: mocks.NewMockDeviceTokenRepo(ctrl),
+		MockInboxRepo:       mocks.NewMockInboxRepo(ctrl),
+		MockUserRepo:        mocks.NewMockUserRepo(ctrl),
+		MockTaxiRepo:        mocks.NewMockTaxiRepo(ctrl),
+	}
+	return NewNotificationService(repo, push.NoopSender{}, logrus.New()), repo
+}
+
+func TestSendStoresNotificationInInbox(t *testing.T) {
+	svc, repo := newNotificationServiceMock(t)
+	msg := push.Message{Title: "Report approved", Body: "Approved.", Data: map[string]string{"type": "report_status", "report_id": "7"}}
+
+	repo.MockInboxRepo.EXPECT().CreateNotification(gomock.Any(), &repository.Notification{
+		UserID: 4,
+		Type:   "report_status",
+		Title:  "Report approved",
+		Body:   "Approved.",
+		Data:   msg.Data,
+	}).Return(nil)
+	repo.MockDeviceTokenRepo.EXPECT().GetDeviceTokensByUser(gomock.Any(), uint(4)).Return([]repository.DeviceToken{{Token: "t1"}}, nil)
+
+	svc.send(context.Background(), 4, msg)
+}
+
+func TestMarkReadOfAnotherUsersNotification(t *testing.T) {
+	svc, repo := newNotificationServiceMock(t)
+	repo.MockInboxRepo.EXPECT().MarkNotificationRead(gomock.Any(), uint(4), uint(9)).Return(false, nil)
+
+	if err := svc.MarkRead(context.Background(), 4, 9); !errors.Is(err, ErrNotificationNotFound) {
+		t.Fatalf("expected ErrNotificationNotFound, got %v", err)
+	}
+}
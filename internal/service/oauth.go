@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"taxifleet/backend/internal/config"
+	"taxifleet/backend/internal/repository"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"gorm.io/gorm"
+)
+
+const (
+	googleProvider    = "google"
+	googleUserInfoURL = "https://openidconnect.googleapis.com/v1/userinfo"
+)
+
+var (
+	// ErrOAuthEmailNotVerified is returned when the provider has not verified
+	// the email it reports, so it cannot be trusted to match a user
+	ErrOAuthEmailNotVerified = errors.New("email is not verified by the sign-in provider")
+	// ErrOAuthNoMatchingUser is returned when no user has the signed-in email.
+	// Sign-in never creates users; an admin has to invite them first.
+	ErrOAuthNoMatchingUser = errors.New("no user with this email, ask your administrator for an account")
+)
+
+// OAuthRepository is the data access OAuthService depends on
+type OAuthRepository interface {
+	repository.UserRepo
+	repository.OAuthIdentityRepo
+	repository.LoginAuditRepo
+}
+
+// OAuthService signs existing users in through an OpenID Connect provider.
+// Users keep their tenant and permission; the provider only proves who they are.
+type OAuthService struct {
+	repo        OAuthRepository
+	auth        *AuthService
+	google      *oauth2.Config
+	userInfoURL string
+}
+
+func NewOAuthService(repo OAuthRepository, auth *AuthService, cfg config.OAuthConfig) *OAuthService {
+	return &OAuthService{
+		repo: repo,
+		auth: auth,
+		google: &oauth2.Config{
+			ClientID:     cfg.GoogleClientID,
+			ClientSecret: cfg.GoogleClientSecret,
+			RedirectURL:  cfg.GoogleRedirectURL,
+			Endpoint:     google.Endpoint,
+			Scopes:       []string{"openid", "email", "profile"},
+		},
+		userInfoURL: googleUserInfoURL,
+	}
+}
+
+// OAuthProfile is the identity vouched for by the provider
+type OAuthProfile struct {
+	Subject       string `json:"sub"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+}
+
+// GoogleAuthURL returns the Google consent page to send the user to
+func (s *OAuthService) GoogleAuthURL(state string) string {
+	return s.google.AuthCodeURL(state, oauth2.SetAuthURLParam("prompt", "select_account"))
+}
+
+// GoogleLogin exchanges the authorization code returned by Google and signs
+// in the matching user
+func (s *OAuthService) GoogleLogin(ctx context.Context, code string, client LoginClient) (*AuthResponse, error) {
+	token, err := s.google.Exchange(ctx, code)
+	if err != nil {
+		return nil, errors.New("invalid authorization code")
+	}
+
+	profile, err := s.fetchProfile(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.login(ctx, googleProvider, profile, client)
+}
+
+func (s *OAuthService) fetchProfile(ctx context.Context, token *oauth2.Token) (*OAuthProfile, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.userInfoURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.google.Client(ctx, token).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch user info: unexpected status %d", resp.StatusCode)
+	}
+
+	var profile OAuthProfile
+	if err := json.NewDecoder(resp.Body).Decode(&profile); err != nil {
+		return nil, err
+	}
+	if profile.Subject == "" {
+		return nil, errors.New("fetch user info: missing subject")
+	}
+	return &profile, nil
+}
+
+// login signs in the user linked to the provider account. On the first
+// sign-in the account is linked to the user with the same verified email.
+// Every attempt is audited.
+func (s *OAuthService) login(ctx context.Context, provider string, profile *OAuthProfile, client LoginClient) (*AuthResponse, error) {
+	var user *repository.User
+
+	identity, err := s.repo.GetOAuthIdentity(ctx, provider, profile.Subject)
+	switch {
+	case err == nil:
+		user, err = s.repo.GetUserByID(ctx, identity.UserID)
+		if err != nil {
+			return nil, notFound("user not found")
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if !profile.EmailVerified {
+			if err := s.auth.recordFailedLogin(ctx, nil, profile.Email, provider, client, "email not verified"); err != nil {
+				return nil, err
+			}
+			return nil, ErrOAuthEmailNotVerified
+		}
+		user, err = s.repo.GetUserByEmail(ctx, profile.Email)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if err := s.auth.recordFailedLogin(ctx, nil, profile.Email, provider, client, "unknown user"); err != nil {
+				return nil, err
+			}
+			return nil, ErrOAuthNoMatchingUser
+		}
+		if err != nil {
+			return nil, err
+		}
+		identity = &repository.OAuthIdentity{
+			UserID:   user.ID,
+			Provider: provider,
+			Subject:  profile.Subject,
+			Email:    profile.Email,
+		}
+		if err := s.repo.CreateOAuthIdentity(ctx, identity); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	if !user.Active {
+		if err := s.auth.recordFailedLogin(ctx, &user.ID, profile.Email, provider, client, "inactive user"); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("user account is inactive")
+	}
+
+	return s.auth.completeLogin(ctx, user, profile.Email, provider, client)
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"taxifleet/backend/internal/config"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+	"taxifleet/backend/internal/tokens"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+type oauthRepoMock struct {
+	*mocks.MockUserRepo
+	*mocks.MockOAuthIdentityRepo
+	*mocks.MockTenantRepo
+	*mocks.MockSessionRepo
+	*mocks.MockLoginAuditRepo
+}
+
+func newOAuthServiceMock(t *testing.T) (*OAuthService, oauthRepoMock) {
+	ctrl := gomock.NewController(t)
+	repo := oauthRepoMock{
+		MockUserRepo:          mocks.NewMockUserRepo(ctrl),
+		MockOAuthIdentityRepo: mocks.NewMockOAuthIdentityRepo(ctrl),
+		MockTenantRepo:        mocks.NewMockTenantRepo(ctrl),
+		MockSessionRepo:       mocks.NewMockSessionRepo(ctrl),
+		MockLoginAuditRepo:    mocks.NewMockLoginAuditRepo(ctrl),
+	}
+	cfg := &config.Config{JWT: config.JWTConfig{Secret: "test-secret", Expiration: time.Hour, RefreshExpiration: time.Hour}}
+	keys, err := tokens.Load(cfg.JWT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := NewAuthService(repo, repo, nil, cfg, keys)
+	return NewOAuthService(repo, auth, cfg.OAuth), repo
+}
+
+func TestOAuthLoginLinksUserByVerifiedEmail(t *testing.T) {
+	svc, repo := newOAuthServiceMock(t)
+	user := &repository.User{ID: 4, TenantID: 2, Email: "driver@example.com", Active: true}
+
+	repo.MockOAuthIdentityRepo.EXPECT().GetOAuthIdentity(gomock.Any(), "google", "sub-1").Return(nil, gorm.ErrRecordNotFound)
+	repo.MockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), "driver@example.com").Return(user, nil)
+	repo.MockOAuthIdentityRepo.EXPECT().CreateOAuthIdentity(gomock.Any(), &repository.OAuthIdentity{UserID: 4, Provider: "google", Subject: "sub-1", Email: "driver@example.com"}).Return(nil)
+	repo.MockLoginAuditRepo.EXPECT().HasLoginFromDevice(gomock.Any(), uint(4), gomock.Any()).Return(false, false, nil)
+	repo.MockLoginAuditRepo.EXPECT().CreateLoginAudit(gomock.Any(), gomock.Any()).Return(nil)
+	repo.MockSessionRepo.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Return(nil)
+
+	response, err := svc.login(context.Background(), "google", &OAuthProfile{Subject: "sub-1", Email: "driver@example.com", EmailVerified: true}, LoginClient{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.User.ID != 4 || response.Token == "" {
+		t.Fatalf("expected user 4 to be signed in, got %+v", response)
+	}
+}
+
+func TestOAuthLoginRejectsUnverifiedEmail(t *testing.T) {
+	svc, repo := newOAuthServiceMock(t)
+	repo.MockOAuthIdentityRepo.EXPECT().GetOAuthIdentity(gomock.Any(), "google", "sub-1").Return(nil, gorm.ErrRecordNotFound)
+	repo.MockLoginAuditRepo.EXPECT().CreateLoginAudit(gomock.Any(), gomock.Any()).Return(nil)
+
+	_, err := svc.login(context.Background(), "google", &OAuthProfile{Subject: "sub-1", Email: "driver@example.com"}, LoginClient{})
+	if !errors.Is(err, ErrOAuthEmailNotVerified) {
+		t.Fatalf("expected ErrOAuthEmailNotVerified, got %v", err)
+	}
+}
+
+func TestOAuthLoginRejectsUnknownEmail(t *testing.T) {
+	svc, repo := newOAuthServiceMock(t)
+	repo.MockOAuthIdentityRepo.EXPECT().GetOAuthIdentity(gomock.Any(), "google", "sub-1").Return(nil, gorm.ErrRecordNotFound)
+	repo.MockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), "someone@example.com").Return(nil, gorm.ErrRecordNotFound)
+	repo.MockLoginAuditRepo.EXPECT().CreateLoginAudit(gomock.Any(), gomock.Any()).Return(nil)
+
+	_, err := svc.login(context.Background(), "google", &OAuthProfile{Subject: "sub-1", Email: "someone@example.com", EmailVerified: true}, LoginClient{})
+	if !errors.Is(err, ErrOAuthNoMatchingUser) {
+		t.Fatalf("expected ErrOAuthNoMatchingUser, got %v", err)
+	}
+}
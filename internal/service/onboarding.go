@@ -0,0 +1,250 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"taxifleet/backend/internal/config"
+	"taxifleet/backend/internal/mail"
+	"taxifleet/backend/internal/permissions"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/validation"
+
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidInviteCode is returned when onboarding requires an invite
+	// code and none or an unknown one was given
+	ErrInvalidInviteCode = errors.New("invalid invite code")
+	// ErrInvalidVerificationToken is returned for a missing, expired or
+	// already used email verification token
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
+	// ErrEmailNotVerified is returned when an owner who signed up signs in
+	// before verifying their email address
+	ErrEmailNotVerified = errors.New("email address not verified")
+)
+
+var subdomainPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{1,61}[a-z0-9])$`)
+
+// OnboardingRepository is the data access OnboardingService depends on
+type OnboardingRepository interface {
+	repository.Transactor
+	repository.UserRepo
+	repository.TenantRepo
+}
+
+// OnboardingService signs up new tenants: a tenant and its owner, who signs
+// in once they verified their email address
+type OnboardingService struct {
+	repo   OnboardingRepository
+	mailer mail.Sender
+	cfg    *config.Config
+}
+
+func NewOnboardingService(repo OnboardingRepository, mailer mail.Sender, cfg *config.Config) *OnboardingService {
+	return &OnboardingService{repo: repo, mailer: mailer, cfg: cfg}
+}
+
+type OnboardingRequest struct {
+	CompanyName  string `json:"company_name" binding:"required"`
+	Subdomain    string `json:"subdomain" binding:"required"`
+	Country      string `json:"country" binding:"omitempty,len=2"`
+	WeekStartDay string `json:"week_start_day" binding:"omitempty,oneof=monday tuesday wednesday thursday friday saturday sunday"`
+	InviteCode   string `json:"invite_code" sanitize:"-"`
+
+	// The owner
+	Email     string `json:"email" binding:"required,email"`
+	Password  string `json:"password" binding:"required,min=6" sanitize:"-"`
+	FirstName string `json:"first_name" binding:"required"`
+	LastName  string `json:"last_name" binding:"required"`
+	Phone     string `json:"phone" binding:"required,phone"`
+}
+
+// Onboarding is a new tenant and its owner, waiting for the verification
+type Onboarding struct {
+	Tenant *repository.Tenant `json:"tenant"`
+	User   *repository.User   `json:"user"`
+}
+
+// defaultTenantSettings are the settings new tenants start with
+func defaultTenantSettings(req OnboardingRequest) TenantSettings {
+	return TenantSettings{
+		WeekStartDay:    cmp.Or(req.WeekStartDay, "monday"),
+		FiscalYearStart: "01-01",
+		Country:         strings.ToUpper(req.Country),
+		Exports:         ExportSettings{Branding: true, Totals: true},
+	}
+}
+
+// Onboard creates the tenant with default settings and its owner in one
+// transaction and emails the owner a link to verify their address. Nothing is
+// created if the email cannot be sent.
+func (s *OnboardingService) Onboard(ctx context.Context, req OnboardingRequest) (*Onboarding, error) {
+	if !s.validInviteCode(req.InviteCode) {
+		return nil, ErrInvalidInviteCode
+	}
+
+	subdomain := strings.ToLower(strings.TrimSpace(req.Subdomain))
+	if !subdomainPattern.MatchString(subdomain) {
+		return nil, &validation.FieldError{Field: "subdomain", Rule: "subdomain", Message: "subdomain must be 3 to 63 lowercase letters, digits or hyphens"}
+	}
+	if _, err := s.repo.GetTenantBySubdomain(ctx, subdomain); err == nil {
+		return nil, conflict("subdomain already exists")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if _, err := s.repo.GetUserByEmail(ctx, req.Email); err == nil {
+		return nil, conflict("email already exists")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	req.Phone, _ = validation.NormalizePhone(req.Phone)
+	if _, err := s.repo.GetUserByPhone(ctx, req.Phone); err == nil {
+		return nil, conflict("phone number already exists")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	settings, err := json.Marshal(defaultTenantSettings(req))
+	if err != nil {
+		return nil, err
+	}
+	hashedPassword, err := hashPassword(req.Password)
+	if err != nil {
+		return nil, errors.New("failed to hash password")
+	}
+
+	tenant := &repository.Tenant{
+		Name:      strings.TrimSpace(req.CompanyName),
+		Subdomain: subdomain,
+		Settings:  string(settings),
+	}
+	if s.cfg.Onboarding.PlanID != 0 {
+		tenant.PlanID = &s.cfg.Onboarding.PlanID
+	}
+	user := &repository.User{
+		Email:        req.Email,
+		PasswordHash: hashedPassword,
+		Permission:   permissions.PermissionOwner,
+		FirstName:    req.FirstName,
+		LastName:     req.LastName,
+		Phone:        req.Phone,
+		Active:       true,
+		Unverified:   true,
+	}
+
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateTenant(ctx, tenant); err != nil {
+			return err
+		}
+		user.TenantID = tenant.ID
+		if err := s.repo.CreateUser(ctx, user); err != nil {
+			return err
+		}
+		return s.sendVerification(ctx, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Onboarding{Tenant: tenant, User: user}, nil
+}
+
+func (s *OnboardingService) validInviteCode(code string) bool {
+	if len(s.cfg.Onboarding.InviteCodes) == 0 {
+		return true
+	}
+	for _, valid := range s.cfg.Onboarding.InviteCodes {
+		if subtle.ConstantTimeCompare([]byte(strings.TrimSpace(valid)), []byte(code)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// ResendVerification emails a new verification link to an owner who has not
+// verified yet. Unknown and verified addresses are ignored so the response
+// does not reveal which addresses signed up.
+func (s *OnboardingService) ResendVerification(ctx context.Context, email string) error {
+	user, err := s.repo.GetUserByEmail(ctx, email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !user.Unverified {
+		return nil
+	}
+	return s.sendVerification(ctx, user)
+}
+
+// Verify marks the owner's email address verified so they can sign in
+func (s *OnboardingService) Verify(ctx context.Context, token string) (*repository.User, error) {
+	userID, email, ok := s.parseVerificationToken(token)
+	if !ok {
+		return nil, ErrInvalidVerificationToken
+	}
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil || !user.Unverified || user.Email != email {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	user.Unverified = false
+	if err := s.repo.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *OnboardingService) sendVerification(ctx context.Context, user *repository.User) error {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"purpose": "email_verification",
+		"user_id": user.ID,
+		"email":   user.Email,
+		"exp":     time.Now().Add(s.cfg.Onboarding.VerificationTTL).Unix(),
+	})
+	signed, err := token.SignedString([]byte(s.cfg.JWT.Secret))
+	if err != nil {
+		return err
+	}
+
+	link := s.cfg.Onboarding.VerifyURL + "?token=" + url.QueryEscape(signed)
+	return s.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Text: fmt.Sprintf("Hello %s,\n\nWelcome to TaxiFleet. Open this link to verify your email address and sign in:\n\n%s\n\nThe link expires in %s. If you did not sign up, ignore this email.\n",
+			user.FirstName, link, s.cfg.Onboarding.VerificationTTL),
+	})
+}
+
+func (s *OnboardingService) parseVerificationToken(tokenString string) (uint, string, bool) {
+	if tokenString == "" {
+		return 0, "", false
+	}
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		return []byte(s.cfg.JWT.Secret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil || !token.Valid {
+		return 0, "", false
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return 0, "", false
+	}
+	purpose, _ := claims["purpose"].(string)
+	userID, _ := claims["user_id"].(float64)
+	email, _ := claims["email"].(string)
+	return uint(userID), email, purpose == "email_verification" && userID > 0
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"taxifleet/backend/internal/config"
+	"taxifleet/backend/internal/mail"
+	"taxifleet/backend/internal/permissions"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+type onboardingRepoMock struct {
+	*mocks.MockTransactor
+	*mocks.MockUserRepo
+	*mocks.MockTenantRepo
+}
+
+// recordingSender keeps the messages it is asked to send
+type recordingSender struct {
+	sent []mail.Message
+	err  error
+}
+
+func (s *recordingSender) Send(ctx context.Context, msg mail.Message) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+func newOnboardingServiceMock(t *testing.T, inviteCodes ...string) (*OnboardingService, onboardingRepoMock, *recordingSender) {
+	ctrl := gomock.NewController(t)
+	repo := onboardingRepoMock{
+		MockTransactor: mocks.NewMockTransactor(ctrl),
+		MockUserRepo:   mocks.NewMockUserRepo(ctrl),
+		MockTenantRepo: mocks.NewMockTenantRepo(ctrl),
+	}
+	cfg := &config.Config{
+		JWT: config.JWTConfig{Secret: "test-secret"},
+		Onboarding: config.OnboardingConfig{
+			Enabled:         true,
+			InviteCodes:     inviteCodes,
+			VerifyURL:       "https://app.example.com/verify",
+			VerificationTTL: time.Hour,
+		},
+	}
+	sender := &recordingSender{}
+	return NewOnboardingService(repo, sender, cfg), repo, sender
+}
+
+func onboardingRequest() OnboardingRequest {
+	return OnboardingRequest{
+		CompanyName: "City Cabs",
+		Subdomain:   "City-Cabs",
+		Country:     "ci",
+		Email:       "owner@citycabs.example",
+		Password:    "secret123",
+		FirstName:   "Awa",
+		LastName:    "Kone",
+		Phone:       "+225 07 07 07 07 07",
+	}
+}
+
+func (r onboardingRepoMock) expectAvailable() {
+	r.MockTenantRepo.EXPECT().GetTenantBySubdomain(gomock.Any(), "city-cabs").Return(nil, gorm.ErrRecordNotFound)
+	r.MockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), "owner@citycabs.example").Return(nil, gorm.ErrRecordNotFound)
+	r.MockUserRepo.EXPECT().GetUserByPhone(gomock.Any(), "+2250707070707").Return(nil, gorm.ErrRecordNotFound)
+	r.MockTransactor.EXPECT().InTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	})
+}
+
+func TestOnboardCreatesUnverifiedOwner(t *testing.T) {
+	svc, repo, sender := newOnboardingServiceMock(t)
+	repo.expectAvailable()
+
+	var tenant *repository.Tenant
+	repo.MockTenantRepo.EXPECT().CreateTenant(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, created *repository.Tenant) error {
+		created.ID = 7
+		tenant = created
+		return nil
+	})
+	var owner *repository.User
+	repo.MockUserRepo.EXPECT().CreateUser(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, created *repository.User) error {
+		created.ID = 21
+		owner = created
+		return nil
+	})
+
+	if _, err := svc.Onboard(context.Background(), onboardingRequest()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if tenant.Subdomain != "city-cabs" {
+		t.Fatalf("expected the subdomain to be lowercased, got %q", tenant.Subdomain)
+	}
+	settings := parseTenantSettings(tenant.Settings)
+	if settings.WeekStartDay != "monday" || settings.Country != "CI" || !settings.Exports.Branding {
+		t.Fatalf("expected default settings, got %+v", settings)
+	}
+	if owner.TenantID != 7 || owner.Permission != permissions.PermissionOwner || !owner.Unverified {
+		t.Fatalf("expected an unverified owner of the tenant, got %+v", owner)
+	}
+	if len(sender.sent) != 1 || !strings.Contains(sender.sent[0].Text, "https://app.example.com/verify?token=") {
+		t.Fatalf("expected a verification email, got %+v", sender.sent)
+	}
+}
+
+func TestOnboardRollsBackWhenEmailFails(t *testing.T) {
+	svc, repo, sender := newOnboardingServiceMock(t)
+	sender.err = errors.New("smtp unavailable")
+	repo.expectAvailable()
+	repo.MockTenantRepo.EXPECT().CreateTenant(gomock.Any(), gomock.Any()).Return(nil)
+	repo.MockUserRepo.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(nil)
+
+	if _, err := svc.Onboard(context.Background(), onboardingRequest()); err == nil {
+		t.Fatal("expected the failed email to fail the onboarding")
+	}
+}
+
+func TestOnboardRequiresInviteCode(t *testing.T) {
+	svc, _, _ := newOnboardingServiceMock(t, "launch-2026")
+
+	req := onboardingRequest()
+	req.InviteCode = "guess"
+	if _, err := svc.Onboard(context.Background(), req); !errors.Is(err, ErrInvalidInviteCode) {
+		t.Fatalf("expected ErrInvalidInviteCode, got %v", err)
+	}
+}
+
+func TestVerifyEmail(t *testing.T) {
+	svc, repo, sender := newOnboardingServiceMock(t)
+	user := &repository.User{ID: 21, Email: "owner@citycabs.example", Unverified: true}
+	if err := svc.sendVerification(context.Background(), user); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	link, _ := url.Parse(strings.Fields(sender.sent[0].Text[strings.Index(sender.sent[0].Text, "https://"):])[0])
+	token := link.Query().Get("token")
+
+	repo.MockUserRepo.EXPECT().GetUserByID(gomock.Any(), uint(21)).Return(user, nil)
+	repo.MockUserRepo.EXPECT().UpdateUser(gomock.Any(), user).Return(nil)
+	if _, err := svc.Verify(context.Background(), token); err != nil || user.Unverified {
+		t.Fatalf("expected the email to be verified, got %v", err)
+	}
+
+	// The link works once
+	repo.MockUserRepo.EXPECT().GetUserByID(gomock.Any(), uint(21)).Return(user, nil)
+	if _, err := svc.Verify(context.Background(), token); !errors.Is(err, ErrInvalidVerificationToken) {
+		t.Fatalf("expected ErrInvalidVerificationToken, got %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/validation"
+)
+
+// encodeKeyset makes the opaque cursor of the page after the list item with
+// the given date and ID
+func encodeKeyset(date time.Time, id uint) string {
+	raw := date.Format("2006-01-02") + "/" + strconv.FormatUint(uint64(id), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeKeyset returns where the page before the cursor ended, or nil for
+// the first page
+func decodeKeyset(cursor string) (*repository.Keyset, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	invalid := &validation.FieldError{Field: "cursor", Rule: "cursor", Message: "cursor is invalid"}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, invalid
+	}
+	rawDate, rawID, ok := strings.Cut(string(raw), "/")
+	if !ok {
+		return nil, invalid
+	}
+	date, err := time.Parse("2006-01-02", rawDate)
+	if err != nil {
+		return nil, invalid
+	}
+	id, err := strconv.ParseUint(rawID, 10, 32)
+	if err != nil || id == 0 {
+		return nil, invalid
+	}
+	return &repository.Keyset{Date: date, ID: uint(id)}, nil
+}
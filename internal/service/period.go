@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/validation"
+)
+
+// ErrPeriodClosed is returned when a change touches a month closed for the
+// books, or when closing a month that already is
+var ErrPeriodClosed = errors.New("the period is closed")
+
+// PeriodRepository is the data access PeriodService depends on
+type PeriodRepository interface {
+	repository.Transactor
+	repository.PeriodRepo
+	repository.TenantRepo
+	repository.AuditRepo
+}
+
+// PeriodService closes months for the books. Reports, expenses and deposits
+// dated within a closed month can't be changed by anyone, owners included,
+// until a platform admin reopens it. Closing and reopening are audited.
+type PeriodService struct {
+	repo PeriodRepository
+}
+
+func NewPeriodService(repo PeriodRepository) *PeriodService {
+	return &PeriodService{repo: repo}
+}
+
+type ClosePeriodRequest struct {
+	Month string `json:"month" binding:"required"` // YYYY-MM
+}
+
+func (s *PeriodService) List(ctx context.Context, tenantID uint) ([]repository.ClosedPeriod, error) {
+	return s.repo.GetClosedPeriods(ctx, tenantID)
+}
+
+// Close closes a past month of the tenant; only owners close periods
+func (s *PeriodService) Close(ctx context.Context, tenantID, userID uint, permission int, req ClosePeriodRequest) (*repository.ClosedPeriod, error) {
+	if !hasOwnerRights(permission) {
+		return nil, forbidden("only owners can close periods")
+	}
+	month, err := parseMonth(req.Month)
+	if err != nil {
+		return nil, err
+	}
+	if !month.Before(startOfMonth(time.Now())) {
+		return nil, &validation.FieldError{Field: "month", Rule: "past", Message: "only past months can be closed"}
+	}
+
+	period := &repository.ClosedPeriod{TenantID: tenantID, Month: month, ClosedByID: userID, ClosedAt: time.Now()}
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateClosedPeriod(ctx, period); err != nil {
+			return err
+		}
+		return s.repo.CreateAuditEvent(ctx, auditEvent(tenantID, userID, ActionPeriodClosed, "period", period.ID, "Period "+month.Format("01/2006")+" closed"))
+	})
+	if repository.IsUniqueViolation(err) {
+		return nil, ErrPeriodClosed
+	}
+	if err != nil {
+		return nil, err
+	}
+	return period, nil
+}
+
+// Reopen reopens a closed month of a tenant for a platform admin
+func (s *PeriodService) Reopen(ctx context.Context, tenantID, adminID uint, rawMonth string) error {
+	if _, err := s.repo.GetTenantByID(ctx, tenantID); err != nil {
+		return err
+	}
+	month, err := parseMonth(rawMonth)
+	if err != nil {
+		return err
+	}
+	period, err := s.repo.GetClosedPeriod(ctx, tenantID, month)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteClosedPeriod(ctx, period.ID); err != nil {
+			return err
+		}
+		return s.repo.CreateAuditEvent(ctx, auditEvent(tenantID, adminID, ActionPeriodReopened, "period", period.ID, "Period "+month.Format("01/2006")+" reopened"))
+	})
+}
+
+// ensurePeriodOpen returns ErrPeriodClosed when one of the dates falls in a
+// closed month of the tenant
+func ensurePeriodOpen(ctx context.Context, repo repository.PeriodRepo, tenantID uint, dates ...time.Time) error {
+	for _, date := range dates {
+		closed, err := repo.IsPeriodClosed(ctx, tenantID, date)
+		if err != nil {
+			return err
+		}
+		if closed {
+			return ErrPeriodClosed
+		}
+	}
+	return nil
+}
+
+// parseMonth parses a YYYY-MM month into its first day
+func parseMonth(raw string) (time.Time, error) {
+	month, err := time.Parse("2006-01", raw)
+	if err != nil {
+		return time.Time{}, &validation.FieldError{Field: "month", Rule: "month", Message: "month must be a month (YYYY-MM)"}
+	}
+	return month, nil
+}
+
+func startOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"taxifleet/backend/internal/cache"
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/ocr"
+	"taxifleet/backend/internal/permissions"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+	"taxifleet/backend/internal/validation"
+
+	"github.com/sirupsen/logrus"
+	"go.uber.org/mock/gomock"
+)
+
+type periodRepoMock struct {
+	*mocks.MockTransactor
+	*mocks.MockPeriodRepo
+	*mocks.MockTenantRepo
+	*mocks.MockAuditRepo
+}
+
+func newPeriodServiceMock(t *testing.T) (*PeriodService, periodRepoMock) {
+	ctrl := gomock.NewController(t)
+	repo := periodRepoMock{
+		MockTransactor: mocks.NewMockTransactor(ctrl),
+		MockPeriodRepo: mocks.NewMockPeriodRepo(ctrl),
+		MockTenantRepo: mocks.NewMockTenantRepo(ctrl),
+		MockAuditRepo:  mocks.NewMockAuditRepo(ctrl),
+	}
+	repo.MockTransactor.EXPECT().InTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}).AnyTimes()
+	return NewPeriodService(repo), repo
+}
+
+func TestClosePeriod(t *testing.T) {
+	svc, repo := newPeriodServiceMock(t)
+
+	if _, err := svc.Close(context.Background(), 1, 5, permissions.PermissionManager, ClosePeriodRequest{Month: "2024-05"}); err == nil {
+		t.Fatal("expected a manager to be refused")
+	}
+	for _, month := range []string{"05/2024", time.Now().Format("2006-01")} {
+		var fieldErr *validation.FieldError
+		if _, err := svc.Close(context.Background(), 1, 9, permissions.PermissionOwner, ClosePeriodRequest{Month: month}); !errors.As(err, &fieldErr) {
+			t.Errorf("expected a validation error for %s, got %v", month, err)
+		}
+	}
+
+	repo.MockPeriodRepo.EXPECT().CreateClosedPeriod(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, period *repository.ClosedPeriod) error {
+		if period.Month.Format("2006-01-02") != "2024-05-01" || period.ClosedByID != 9 {
+			t.Errorf("unexpected period %+v", period)
+		}
+		return nil
+	})
+	repo.MockAuditRepo.EXPECT().CreateAuditEvent(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, event *repository.AuditEvent) error {
+		if event.Action != ActionPeriodClosed || event.Summary != "Period 05/2024 closed" {
+			t.Errorf("unexpected audit event %+v", event)
+		}
+		return nil
+	})
+	if _, err := svc.Close(context.Background(), 1, 9, permissions.PermissionOwner, ClosePeriodRequest{Month: "2024-05"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestClosedPeriodLocksExpenses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := expenseRepoMock{
+		MockExpenseRepo: mocks.NewMockExpenseRepo(ctrl),
+		MockReportRepo:  mocks.NewMockReportRepo(ctrl),
+		MockTaxiRepo:    mocks.NewMockTaxiRepo(ctrl),
+		MockTenantRepo:  mocks.NewMockTenantRepo(ctrl),
+		MockTransactor:  mocks.NewMockTransactor(ctrl),
+		MockPeriodRepo:  mocks.NewMockPeriodRepo(ctrl),
+		MockAuditRepo:   mocks.NewMockAuditRepo(ctrl),
+		MockBudgetRepo:  mocks.NewMockBudgetRepo(ctrl),
+	}
+	svc := NewExpenseService(repo, cache.Noop{}, ocr.DisabledProvider{}, NewBudgetService(repo, cache.Noop{}, nil, logrus.New()))
+	may := time.Date(2024, 5, 14, 0, 0, 0, 0, time.UTC)
+	repo.MockPeriodRepo.EXPECT().IsPeriodClosed(gomock.Any(), uint(1), may).Return(true, nil).Times(3)
+	repo.MockExpenseRepo.EXPECT().GetExpenseByID(gomock.Any(), uint(7)).Return(&repository.Expense{ID: 7, TenantID: 1, CreatedByID: 9, Date: may}, nil).Times(2)
+
+	if _, err := svc.Create(context.Background(), 1, 9, permissions.PermissionOwner, CreateExpenseRequest{Category: "fuel", Amount: 40_00, Date: "2024-05-14"}); !errors.Is(err, ErrPeriodClosed) {
+		t.Fatalf("expected ErrPeriodClosed on create, got %v", err)
+	}
+	// Owners can't change the books of a closed month either
+	amount := money.Amount(50_00)
+	if _, err := svc.Update(context.Background(), 7, 1, 9, permissions.PermissionOwner, UpdateExpenseRequest{Amount: &amount}); !errors.Is(err, ErrPeriodClosed) {
+		t.Fatalf("expected ErrPeriodClosed on update, got %v", err)
+	}
+	if err := svc.Delete(context.Background(), 7, 1, 9, permissions.PermissionOwner); !errors.Is(err, ErrPeriodClosed) {
+		t.Fatalf("expected ErrPeriodClosed on delete, got %v", err)
+	}
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/validation"
+)
+
+var (
+	// ErrPlanLimitReached is returned when creating something would exceed a
+	// limit of the tenant's plan
+	ErrPlanLimitReached = errors.New("plan limit reached")
+	// ErrPlanInUse is returned when deleting a plan a tenant is on
+	ErrPlanInUse = errors.New("tenants are on this plan")
+	// ErrPlanNameTaken is returned when a plan is given the name of another
+	ErrPlanNameTaken = errors.New("a plan with this name already exists")
+)
+
+// planLimit is a limit of a plan and what a tenant uses of it
+type planLimit struct {
+	what string // As in "the plan allows 5 taxis"
+	max  func(plan *repository.Plan) *int
+	used func(usage *repository.PlanUsage) int64
+}
+
+var (
+	taxiLimit = planLimit{
+		what: "taxis",
+		max:  func(plan *repository.Plan) *int { return plan.MaxTaxis },
+		used: func(usage *repository.PlanUsage) int64 { return usage.TaxiCount },
+	}
+	userLimit = planLimit{
+		what: "users",
+		max:  func(plan *repository.Plan) *int { return plan.MaxUsers },
+		used: func(usage *repository.PlanUsage) int64 { return usage.UserCount },
+	}
+	exportLimit = planLimit{
+		what: "exports a month",
+		max:  func(plan *repository.Plan) *int { return plan.ExportQuota },
+		used: func(usage *repository.PlanUsage) int64 { return usage.ExportCount },
+	}
+)
+
+// checkPlanLimit returns ErrPlanLimitReached if the tenant already uses all
+// its plan allows of the limit. Tenants without a plan have no limits.
+func checkPlanLimit(ctx context.Context, repo repository.PlanRepo, tenant *repository.Tenant, limit planLimit) error {
+	if tenant.PlanID == nil {
+		return nil
+	}
+	plan, err := repo.GetPlanByID(ctx, *tenant.PlanID)
+	if err != nil {
+		return err
+	}
+	allowed := limit.max(plan)
+	if allowed == nil {
+		return nil
+	}
+
+	usage, err := repo.GetPlanUsage(ctx, tenant.ID, exportMonth(time.Now()))
+	if err != nil {
+		return err
+	}
+	if limit.used(usage) >= int64(*allowed) {
+		return fmt.Errorf("%w: the %s plan allows %d %s", ErrPlanLimitReached, plan.Name, *allowed, limit.what)
+	}
+	return nil
+}
+
+// exportMonth is the month exports made at the given time count against
+func exportMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// PlanRepository is the data access PlanService depends on
+type PlanRepository interface {
+	repository.PlanRepo
+	repository.TenantRepo
+}
+
+// PlanService manages the subscription plans and enforces the export quota
+type PlanService struct {
+	repo     PlanRepository
+	settings *SystemSettingService
+}
+
+func NewPlanService(repo PlanRepository, settings *SystemSettingService) *PlanService {
+	return &PlanService{repo: repo, settings: settings}
+}
+
+// PlanRequest creates or replaces a plan; a limit left out is unlimited
+type PlanRequest struct {
+	Name                  string `json:"name" binding:"required"`
+	MaxTaxis              *int   `json:"max_taxis" binding:"omitempty,min=0"`
+	MaxUsers              *int   `json:"max_users" binding:"omitempty,min=0"`
+	ExportQuota           *int   `json:"export_quota" binding:"omitempty,min=0"`
+	APIKeyRequestsPerHour *int   `json:"api_key_requests_per_hour" binding:"omitempty,min=1"`
+}
+
+func (s *PlanService) Create(ctx context.Context, req PlanRequest) (*repository.Plan, error) {
+	plan := &repository.Plan{}
+	if err := applyPlanRequest(plan, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreatePlan(ctx, plan); err != nil {
+		if repository.IsUniqueViolation(err) {
+			return nil, ErrPlanNameTaken
+		}
+		return nil, err
+	}
+	return plan, nil
+}
+
+func (s *PlanService) List(ctx context.Context) ([]repository.Plan, error) {
+	return s.repo.GetPlans(ctx)
+}
+
+func (s *PlanService) GetByID(ctx context.Context, id uint) (*repository.Plan, error) {
+	return s.repo.GetPlanByID(ctx, id)
+}
+
+// Update replaces the plan. Tenants over a lowered limit keep what they have
+// but cannot add more.
+func (s *PlanService) Update(ctx context.Context, id uint, req PlanRequest) (*repository.Plan, error) {
+	plan, err := s.repo.GetPlanByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyPlanRequest(plan, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdatePlan(ctx, plan); err != nil {
+		if repository.IsUniqueViolation(err) {
+			return nil, ErrPlanNameTaken
+		}
+		return nil, err
+	}
+	return plan, nil
+}
+
+// Delete removes a plan no tenant is on
+func (s *PlanService) Delete(ctx context.Context, id uint) error {
+	if _, err := s.repo.GetPlanByID(ctx, id); err != nil {
+		return err
+	}
+
+	inUse, err := s.repo.PlanInUse(ctx, id)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return ErrPlanInUse
+	}
+
+	return s.repo.DeletePlan(ctx, id)
+}
+
+func applyPlanRequest(plan *repository.Plan, req PlanRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return &validation.FieldError{Field: "name", Rule: "required", Message: "name is required"}
+	}
+	plan.Name = name
+	plan.MaxTaxis = req.MaxTaxis
+	plan.MaxUsers = req.MaxUsers
+	plan.ExportQuota = req.ExportQuota
+	plan.APIKeyRequestsPerHour = req.APIKeyRequestsPerHour
+	return nil
+}
+
+// CheckExport returns ErrPlanLimitReached if the tenant used up this month's
+// export quota of its plan or, without a plan, the default quota of the
+// system settings
+func (s *PlanService) CheckExport(ctx context.Context, tenantID uint) error {
+	tenant, err := s.repo.GetTenantByID(ctx, tenantID)
+	if err != nil {
+		return notFound("tenant not found")
+	}
+	if tenant.PlanID != nil {
+		return checkPlanLimit(ctx, s.repo, tenant, exportLimit)
+	}
+
+	quota := s.settings.Current(ctx).ExportLimits.DefaultQuota
+	if quota == nil {
+		return nil
+	}
+	usage, err := s.repo.GetPlanUsage(ctx, tenant.ID, exportMonth(time.Now()))
+	if err != nil {
+		return err
+	}
+	if usage.ExportCount >= int64(*quota) {
+		return fmt.Errorf("%w: tenants without a plan can make %d exports a month", ErrPlanLimitReached, *quota)
+	}
+	return nil
+}
+
+// APIKeyRateLimit returns how many requests per hour each API key of the
+// tenant may make: its plan's limit, or the system settings' for tenants
+// without a plan. 0 is unlimited.
+func (s *PlanService) APIKeyRateLimit(ctx context.Context, tenantID uint) (int, error) {
+	tenant, err := s.repo.GetTenantByID(ctx, tenantID)
+	if err != nil {
+		return 0, notFound("tenant not found")
+	}
+	if tenant.PlanID == nil {
+		return s.settings.Current(ctx).RateLimit.APIKeyRequestsPerHour, nil
+	}
+
+	plan, err := s.repo.GetPlanByID(ctx, *tenant.PlanID)
+	if err != nil {
+		return 0, err
+	}
+	if plan.APIKeyRequestsPerHour == nil {
+		return 0, nil
+	}
+	return *plan.APIKeyRequestsPerHour, nil
+}
+
+// RecordExport counts an export against the tenant's quota
+func (s *PlanService) RecordExport(ctx context.Context, tenantID uint) error {
+	return s.repo.RecordExport(ctx, tenantID, exportMonth(time.Now()))
+}
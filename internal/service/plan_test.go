@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+
+	"go.uber.org/mock/gomock"
+)
+
+type planRepoMock struct {
+	*mocks.MockPlanRepo
+	*mocks.MockTenantRepo
+	settings systemSettingRepoMock
+}
+
+func newPlanServiceMock(t *testing.T) (*PlanService, planRepoMock) {
+	ctrl := gomock.NewController(t)
+	repo := planRepoMock{
+		MockPlanRepo:   mocks.NewMockPlanRepo(ctrl),
+		MockTenantRepo: mocks.NewMockTenantRepo(ctrl),
+	}
+	settings, settingsRepo := newSystemSettingServiceMock(t)
+	repo.settings = settingsRepo
+	return NewPlanService(repo, settings), repo
+}
+
+func TestCheckExportAgainstMonthlyQuota(t *testing.T) {
+	svc, repo := newPlanServiceMock(t)
+	planID, quota := uint(2), 3
+	month := exportMonth(time.Now())
+
+	repo.MockTenantRepo.EXPECT().GetTenantByID(gomock.Any(), uint(1)).Return(&repository.Tenant{ID: 1, PlanID: &planID}, nil).Times(2)
+	repo.MockPlanRepo.EXPECT().GetPlanByID(gomock.Any(), planID).Return(&repository.Plan{ID: 2, Name: "Starter", ExportQuota: &quota}, nil).Times(2)
+	gomock.InOrder(
+		repo.MockPlanRepo.EXPECT().GetPlanUsage(gomock.Any(), uint(1), month).Return(&repository.PlanUsage{ExportCount: 2}, nil),
+		repo.MockPlanRepo.EXPECT().GetPlanUsage(gomock.Any(), uint(1), month).Return(&repository.PlanUsage{ExportCount: 3}, nil),
+	)
+
+	if err := svc.CheckExport(context.Background(), 1); err != nil {
+		t.Fatalf("expected the export allowed, got %v", err)
+	}
+	if err := svc.CheckExport(context.Background(), 1); !errors.Is(err, ErrPlanLimitReached) {
+		t.Fatalf("expected ErrPlanLimitReached, got %v", err)
+	}
+}
+
+func TestCheckExportWithoutPlan(t *testing.T) {
+	svc, repo := newPlanServiceMock(t)
+	repo.MockTenantRepo.EXPECT().GetTenantByID(gomock.Any(), uint(1)).Return(&repository.Tenant{ID: 1}, nil)
+	repo.settings.MockSystemSettingRepo.EXPECT().GetSystemSettings(gomock.Any()).Return(nil, nil)
+
+	if err := svc.CheckExport(context.Background(), 1); err != nil {
+		t.Fatalf("expected no limits without a plan, got %v", err)
+	}
+}
+
+func TestCheckExportAgainstDefaultQuota(t *testing.T) {
+	svc, repo := newPlanServiceMock(t)
+	repo.MockTenantRepo.EXPECT().GetTenantByID(gomock.Any(), uint(1)).Return(&repository.Tenant{ID: 1}, nil)
+	repo.settings.MockSystemSettingRepo.EXPECT().GetSystemSettings(gomock.Any()).Return([]repository.SystemSetting{
+		{Key: SettingExportLimits, Value: `{"default_quota": 5}`},
+	}, nil)
+	repo.MockPlanRepo.EXPECT().GetPlanUsage(gomock.Any(), uint(1), exportMonth(time.Now())).Return(&repository.PlanUsage{ExportCount: 5}, nil)
+
+	if err := svc.CheckExport(context.Background(), 1); !errors.Is(err, ErrPlanLimitReached) {
+		t.Fatalf("expected ErrPlanLimitReached, got %v", err)
+	}
+}
+
+func TestDeletePlanInUse(t *testing.T) {
+	svc, repo := newPlanServiceMock(t)
+	repo.MockPlanRepo.EXPECT().GetPlanByID(gomock.Any(), uint(2)).Return(&repository.Plan{ID: 2}, nil)
+	repo.MockPlanRepo.EXPECT().PlanInUse(gomock.Any(), uint(2)).Return(true, nil)
+
+	if err := svc.Delete(context.Background(), 2); !errors.Is(err, ErrPlanInUse) {
+		t.Fatalf("expected ErrPlanInUse, got %v", err)
+	}
+}
+
+func TestAPIKeyRateLimitFromPlanOrSettings(t *testing.T) {
+	svc, repo := newPlanServiceMock(t)
+	planID, perHour := uint(2), 500
+	repo.MockTenantRepo.EXPECT().GetTenantByID(gomock.Any(), uint(1)).Return(&repository.Tenant{ID: 1, PlanID: &planID}, nil)
+	repo.MockPlanRepo.EXPECT().GetPlanByID(gomock.Any(), planID).Return(&repository.Plan{ID: 2, APIKeyRequestsPerHour: &perHour}, nil)
+	repo.MockTenantRepo.EXPECT().GetTenantByID(gomock.Any(), uint(3)).Return(&repository.Tenant{ID: 3}, nil)
+	repo.settings.MockSystemSettingRepo.EXPECT().GetSystemSettings(gomock.Any()).Return([]repository.SystemSetting{
+		{Key: SettingRateLimit, Value: `{"api_key_requests_per_hour": 100}`},
+	}, nil)
+
+	if limit, err := svc.APIKeyRateLimit(context.Background(), 1); err != nil || limit != 500 {
+		t.Fatalf("expected the plan's 500 requests per hour, got %d, %v", limit, err)
+	}
+	if limit, err := svc.APIKeyRateLimit(context.Background(), 3); err != nil || limit != 100 {
+		t.Fatalf("expected the default 100 requests per hour, got %d, %v", limit, err)
+	}
+}
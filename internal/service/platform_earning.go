@@ -0,0 +1,424 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/validation"
+)
+
+// platformStatement describes the CSV earnings statement a ride-hailing
+// platform exports: the accepted header names of each field and the date
+// format
+type platformStatement struct {
+	date   []string
+	driver []string // Driver's full name
+	phone  []string
+	amount []string // Net earnings paid to the driver
+	layout string
+}
+
+// ridePlatforms are the platforms whose statements can be imported
+var ridePlatforms = map[string]platformStatement{
+	"uber": {
+		date:   []string{"date", "trip date"},
+		driver: []string{"driver name", "driver"},
+		phone:  []string{"driver phone", "phone"},
+		amount: []string{"total earnings", "earnings"},
+		layout: "2006-01-02",
+	},
+	"bolt": {
+		date:   []string{"date"},
+		driver: []string{"driver", "driver name"},
+		phone:  []string{"driver's phone", "driver phone", "phone"},
+		amount: []string{"net earnings"},
+		layout: "02.01.2006",
+	},
+	"yango": {
+		date:   []string{"date"},
+		driver: []string{"driver", "driver name"},
+		phone:  []string{"phone", "driver phone"},
+		amount: []string{"net income", "income"},
+		layout: "02.01.2006",
+	},
+}
+
+// reconciliationTolerance is how much platform earnings may exceed reported
+// earnings before the week is flagged, absorbing rounding and platform fees
+const reconciliationTolerance money.Amount = 1_00
+
+// PlatformEarningRepository is the data access PlatformEarningService depends on
+type PlatformEarningRepository interface {
+	repository.PlatformEarningRepo
+	repository.UserRepo
+	repository.TenantRepo
+}
+
+// PlatformEarningService imports what drivers earn through ride-hailing
+// platforms and reconciles it with their weekly reports
+type PlatformEarningService struct {
+	repo PlatformEarningRepository
+}
+
+func NewPlatformEarningService(repo PlatformEarningRepository) *PlatformEarningService {
+	return &PlatformEarningService{repo: repo}
+}
+
+type ImportPlatformEarningsRequest struct {
+	Platform string `json:"platform"` // uber, bolt or yango
+	// Mapping names the CSV column of the date, driver, phone or amount when
+	// the statement's header differs from the platform's usual one
+	Mapping    map[string]string `json:"mapping"`
+	DateFormat string            `json:"date_format"` // One of importDateFormats, defaults to the platform's
+	DryRun     bool              `json:"dry_run"`
+}
+
+type PlatformEarningInput struct {
+	DriverID uint         `json:"driver_id" binding:"required"`
+	Date     string       `json:"date" binding:"required"` // YYYY-MM-DD
+	Amount   money.Amount `json:"amount" binding:"min=0"`
+}
+
+// PushPlatformEarningsRequest carries earnings fetched from a platform's API
+// by an integration
+type PushPlatformEarningsRequest struct {
+	Platform string                 `json:"platform" binding:"required,oneof=uber bolt yango"`
+	Earnings []PlatformEarningInput `json:"earnings" binding:"required,min=1,max=1000,dive"`
+}
+
+// PlatformImportReport tells which rows of a statement were accepted and
+// which of the imported weeks don't match the drivers' reports. In a dry run
+// nothing is saved.
+type PlatformImportReport struct {
+	Platform      string                   `json:"platform"`
+	DryRun        bool                     `json:"dry_run"`
+	Rows          int                      `json:"rows"`
+	Accepted      int                      `json:"accepted"`
+	Rejected      int                      `json:"rejected"`
+	Errors        []ImportRowError         `json:"errors"`
+	Days          int                      `json:"days"` // Driver days saved; rows of the same day are summed
+	Discrepancies []EarningsReconciliation `json:"discrepancies"`
+}
+
+// EarningsReconciliation compares what a driver earned through platforms in
+// a reporting week with the earnings of their reports for that week
+type EarningsReconciliation struct {
+	DriverID      uint                    `json:"driver_id"`
+	DriverName    string                  `json:"driver_name"`
+	WeekStartDate time.Time               `json:"week_start_date"`
+	ReportIDs     []uint                  `json:"report_ids"`
+	Reported      money.Amount            `json:"reported"`
+	Platforms     map[string]money.Amount `json:"platforms"`
+	PlatformTotal money.Amount            `json:"platform_total"`
+	// Difference is what the platforms paid beyond the reported earnings
+	Difference money.Amount `json:"difference"`
+	Flagged    bool         `json:"flagged"`
+}
+
+// PlatformReconciliation holds the reconciled weeks of a period
+type PlatformReconciliation struct {
+	Period  AnalyticsPeriod          `json:"period"`
+	Weeks   []EarningsReconciliation `json:"weeks"`
+	Flagged int                      `json:"flagged"`
+}
+
+// driverDay keys the earnings of a driver on one day, or in the week
+// starting on that day
+type driverDay struct {
+	driverID uint
+	date     time.Time
+}
+
+// Import reads a platform's CSV earnings statement. Rows are matched to
+// drivers by phone number, or by full name when the statement has no phone;
+// unmatched rows are reported and skipped.
+func (s *PlatformEarningService) Import(ctx context.Context, tenantID uint, userID uint, file io.Reader, req ImportPlatformEarningsRequest) (*PlatformImportReport, error) {
+	statement, ok := ridePlatforms[strings.ToLower(req.Platform)]
+	if !ok {
+		return nil, &validation.FieldError{Field: "platform", Rule: "oneof", Message: "platform must be uber, bolt or yango"}
+	}
+	layout := statement.layout
+	if req.DateFormat != "" {
+		if layout, ok = importDateFormats[strings.ToUpper(req.DateFormat)]; !ok {
+			return nil, &validation.FieldError{Field: "date_format", Rule: "oneof", Message: "date_format must be YYYY-MM-DD, DD/MM/YYYY, MM/DD/YYYY or DD.MM.YYYY"}
+		}
+	}
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, invalid("the file is not a CSV file with a header row")
+	}
+
+	index := headerIndex(header)
+	column := func(field string, names []string) int {
+		if mapped, ok := req.Mapping[field]; ok {
+			names = []string{mapped}
+		}
+		for _, name := range names {
+			if i, ok := index[strings.ToLower(strings.TrimSpace(name))]; ok {
+				return i
+			}
+		}
+		return -1
+	}
+	dateColumn, amountColumn := column("date", statement.date), column("amount", statement.amount)
+	driverColumn, phoneColumn := column("driver", statement.driver), column("phone", statement.phone)
+	if dateColumn < 0 || amountColumn < 0 || (driverColumn < 0 && phoneColumn < 0) {
+		return nil, &validation.FieldError{Field: "mapping", Rule: "column", Message: "the file needs a date, an amount and a driver or phone column"}
+	}
+
+	users, err := s.repo.GetUsersByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	match := newDriverMatcher(users)
+
+	platform := strings.ToLower(req.Platform)
+	report := &PlatformImportReport{Platform: platform, DryRun: req.DryRun, Errors: []ImportRowError{}, Discrepancies: []EarningsReconciliation{}}
+	days := make(map[driverDay]money.Amount)
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if report.Rows++; report.Rows > maxImportRows {
+			return nil, fmt.Errorf("an import holds at most %d rows", maxImportRows)
+		}
+		if err != nil {
+			report.Errors = append(report.Errors, ImportRowError{Row: row, Message: "malformed CSV row"})
+			continue
+		}
+
+		value := func(i int) string {
+			if i >= 0 && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		date, err := time.Parse(layout, value(dateColumn))
+		if err != nil {
+			report.Errors = append(report.Errors, ImportRowError{Row: row, Field: "date", Message: fmt.Sprintf("invalid date %q", value(dateColumn))})
+			continue
+		}
+		amount, err := parseImportAmount(value(amountColumn))
+		if err != nil {
+			report.Errors = append(report.Errors, ImportRowError{Row: row, Field: "amount", Message: fmt.Sprintf("invalid amount %q", value(amountColumn))})
+			continue
+		}
+		driverID, rowErr := match(value(phoneColumn), value(driverColumn))
+		if rowErr != nil {
+			rowErr.Row = row
+			report.Errors = append(report.Errors, *rowErr)
+			continue
+		}
+
+		days[driverDay{driverID, date}] += amount
+		report.Accepted++
+	}
+
+	report.Rejected = report.Rows - report.Accepted
+	report.Days = len(days)
+	if req.DryRun || len(days) == 0 {
+		return report, nil
+	}
+
+	earnings := make([]repository.PlatformEarning, 0, len(days))
+	from, to := time.Time{}, time.Time{}
+	for day, amount := range days {
+		earnings = append(earnings, s.newEarning(tenantID, userID, platform, day.driverID, day.date, amount))
+		if from.IsZero() || day.date.Before(from) {
+			from = day.date
+		}
+		if day.date.After(to) {
+			to = day.date
+		}
+	}
+	if err := s.repo.SavePlatformEarnings(ctx, earnings); err != nil {
+		return nil, err
+	}
+
+	reconciliation, err := s.reconcile(ctx, tenantID, AnalyticsPeriod{From: from, To: to})
+	if err != nil {
+		return nil, err
+	}
+	for _, week := range reconciliation.Weeks {
+		if week.Flagged {
+			report.Discrepancies = append(report.Discrepancies, week)
+		}
+	}
+	return report, nil
+}
+
+// Push stores earnings an integration fetched from a platform's API and
+// returns how many driver days were saved
+func (s *PlatformEarningService) Push(ctx context.Context, tenantID uint, userID uint, req PushPlatformEarningsRequest) (int, error) {
+	users, err := s.repo.GetUsersByTenant(ctx, tenantID)
+	if err != nil {
+		return 0, err
+	}
+	tenantUsers := make(map[uint]bool, len(users))
+	for _, user := range users {
+		tenantUsers[user.ID] = true
+	}
+
+	days := make(map[driverDay]money.Amount)
+	for i, input := range req.Earnings {
+		if !tenantUsers[input.DriverID] {
+			return 0, &validation.FieldError{Field: fmt.Sprintf("earnings[%d].driver_id", i), Rule: "driver", Message: "driver not found"}
+		}
+		date, err := time.Parse("2006-01-02", input.Date)
+		if err != nil {
+			return 0, &validation.FieldError{Field: fmt.Sprintf("earnings[%d].date", i), Rule: "date", Message: "date must be a YYYY-MM-DD date"}
+		}
+		days[driverDay{input.DriverID, date}] += input.Amount
+	}
+
+	earnings := make([]repository.PlatformEarning, 0, len(days))
+	for day, amount := range days {
+		earnings = append(earnings, s.newEarning(tenantID, userID, req.Platform, day.driverID, day.date, amount))
+	}
+	if err := s.repo.SavePlatformEarnings(ctx, earnings); err != nil {
+		return 0, err
+	}
+	return len(earnings), nil
+}
+
+func (s *PlatformEarningService) newEarning(tenantID, userID uint, platform string, driverID uint, date time.Time, amount money.Amount) repository.PlatformEarning {
+	earning := repository.PlatformEarning{
+		TenantID: tenantID,
+		DriverID: driverID,
+		Platform: platform,
+		Date:     date,
+		Amount:   amount,
+	}
+	// API keys act as user 0
+	if userID != 0 {
+		earning.CreatedByID = &userID
+	}
+	return earning
+}
+
+// newDriverMatcher returns a function finding the tenant user a statement
+// row is about, by phone number or else by full name
+func newDriverMatcher(users []repository.User) func(phone, name string) (uint, *ImportRowError) {
+	byPhone := make(map[string]uint, len(users))
+	byName := make(map[string][]uint, len(users))
+	for _, user := range users {
+		if normalized, ok := validation.NormalizePhone(user.Phone); ok {
+			byPhone[normalized] = user.ID
+		}
+		name := strings.ToLower(user.FirstName + " " + user.LastName)
+		byName[name] = append(byName[name], user.ID)
+	}
+
+	return func(phone, name string) (uint, *ImportRowError) {
+		if phone != "" {
+			if normalized, ok := validation.NormalizePhone(phone); ok {
+				if id, ok := byPhone[normalized]; ok {
+					return id, nil
+				}
+			}
+			if name == "" {
+				return 0, &ImportRowError{Field: "phone", Message: fmt.Sprintf("no driver with phone %q", phone)}
+			}
+		}
+		switch ids := byName[strings.ToLower(strings.Join(strings.Fields(name), " "))]; len(ids) {
+		case 1:
+			return ids[0], nil
+		case 0:
+			return 0, &ImportRowError{Field: "driver", Message: fmt.Sprintf("no driver named %q", name)}
+		default:
+			return 0, &ImportRowError{Field: "driver", Message: fmt.Sprintf("several drivers are named %q, add their phone numbers", name)}
+		}
+	}
+}
+
+// Reconcile compares the platform earnings of each driver and reporting week
+// between the from and to dates (by default the last 12 weeks) with the
+// earnings of their submitted and approved reports. Weeks where the
+// platforms paid more than was reported are flagged.
+func (s *PlatformEarningService) Reconcile(ctx context.Context, tenantID uint, from, to string) (*PlatformReconciliation, error) {
+	period, err := parsePeriod(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return s.reconcile(ctx, tenantID, period)
+}
+
+func (s *PlatformEarningService) reconcile(ctx context.Context, tenantID uint, period AnalyticsPeriod) (*PlatformReconciliation, error) {
+	tenant, err := s.repo.GetTenantByID(ctx, tenantID)
+	if err != nil {
+		return nil, notFound("tenant not found")
+	}
+	weekStart := parseTenantSettings(tenant.Settings).WeekStart()
+	from := startOfWeek(period.From, weekStart)
+	to := startOfWeek(period.To, weekStart).AddDate(0, 0, 7)
+
+	earnings, err := s.repo.GetPlatformEarnings(ctx, tenantID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	reports, err := s.repo.GetReportsInPeriod(ctx, tenantID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.repo.GetUsersByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uint]string, len(users))
+	for _, user := range users {
+		names[user.ID] = user.FirstName + " " + user.LastName
+	}
+
+	// Only weeks with platform earnings are reconciled
+	weeks := make(map[driverDay]*EarningsReconciliation)
+	for _, earning := range earnings {
+		key := driverDay{earning.DriverID, startOfWeek(earning.Date, weekStart)}
+		week, ok := weeks[key]
+		if !ok {
+			week = &EarningsReconciliation{
+				DriverID:      key.driverID,
+				DriverName:    names[key.driverID],
+				WeekStartDate: key.date,
+				ReportIDs:     []uint{},
+				Platforms:     make(map[string]money.Amount),
+			}
+			weeks[key] = week
+		}
+		week.Platforms[earning.Platform] += earning.Amount
+		week.PlatformTotal += earning.Amount
+	}
+	for _, report := range reports {
+		if week, ok := weeks[driverDay{report.DriverID, startOfWeek(report.WeekStartDate, weekStart)}]; ok {
+			week.ReportIDs = append(week.ReportIDs, report.ID)
+			week.Reported += report.Earnings
+		}
+	}
+
+	result := &PlatformReconciliation{Period: period, Weeks: make([]EarningsReconciliation, 0, len(weeks))}
+	for _, week := range weeks {
+		week.Difference = week.PlatformTotal - week.Reported
+		week.Flagged = week.Difference > reconciliationTolerance
+		if week.Flagged {
+			result.Flagged++
+		}
+		result.Weeks = append(result.Weeks, *week)
+	}
+	slices.SortFunc(result.Weeks, func(a, b EarningsReconciliation) int {
+		return cmp.Or(a.WeekStartDate.Compare(b.WeekStartDate), cmp.Compare(a.DriverName, b.DriverName), cmp.Compare(a.DriverID, b.DriverID))
+	})
+	return result, nil
+}
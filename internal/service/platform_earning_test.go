@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+
+	"go.uber.org/mock/gomock"
+)
+
+type platformEarningRepoMock struct {
+	*mocks.MockPlatformEarningRepo
+	*mocks.MockUserRepo
+	*mocks.MockTenantRepo
+}
+
+func newPlatformEarningServiceMock(t *testing.T) (*PlatformEarningService, platformEarningRepoMock) {
+	ctrl := gomock.NewController(t)
+	repo := platformEarningRepoMock{
+		MockPlatformEarningRepo: mocks.NewMockPlatformEarningRepo(ctrl),
+		MockUserRepo:            mocks.NewMockUserRepo(ctrl),
+		MockTenantRepo:          mocks.NewMockTenantRepo(ctrl),
+	}
+	return NewPlatformEarningService(repo), repo
+}
+
+var platformDrivers = []repository.User{
+	{ID: 7, FirstName: "Ama", LastName: "Mensah", Phone: "+233201234567"},
+	{ID: 8, FirstName: "Kofi", LastName: "Boateng"},
+}
+
+const boltStatement = "Driver,Driver's Phone,Date,Net earnings\n" +
+	"Ama Mensah,+233 20 123 4567,05.03.2024,\"40,50\"\n" +
+	"Ama Mensah,+233 20 123 4567,05.03.2024,20\n" +
+	"kofi  boateng,,06.03.2024,35\n" +
+	"Yaw Owusu,,06.03.2024,15\n"
+
+func TestPlatformImportDryRunMatchesDrivers(t *testing.T) {
+	svc, repo := newPlatformEarningServiceMock(t)
+	repo.MockUserRepo.EXPECT().GetUsersByTenant(gomock.Any(), uint(1)).Return(platformDrivers, nil)
+
+	report, err := svc.Import(context.Background(), 1, 10, strings.NewReader(boltStatement), ImportPlatformEarningsRequest{Platform: "Bolt", DryRun: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Accepted != 3 || report.Days != 2 {
+		t.Fatalf("expected 3 rows on 2 driver days, got %+v", report)
+	}
+	if len(report.Errors) != 1 || report.Errors[0].Row != 5 || report.Errors[0].Field != "driver" {
+		t.Fatalf("expected row 5 to name an unknown driver, got %+v", report.Errors)
+	}
+}
+
+func TestPlatformReconcileFlagsUnderReportedWeeks(t *testing.T) {
+	svc, repo := newPlatformEarningServiceMock(t)
+	monday := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	repo.MockTenantRepo.EXPECT().GetTenantByID(gomock.Any(), uint(1)).Return(&repository.Tenant{ID: 1, Settings: "{}"}, nil)
+	repo.MockPlatformEarningRepo.EXPECT().GetPlatformEarnings(gomock.Any(), uint(1), monday, monday.AddDate(0, 0, 7)).Return([]repository.PlatformEarning{
+		{DriverID: 7, Platform: "bolt", Date: monday.AddDate(0, 0, 1), Amount: 60_50},
+		{DriverID: 7, Platform: "uber", Date: monday.AddDate(0, 0, 2), Amount: 100_00},
+		{DriverID: 8, Platform: "bolt", Date: monday.AddDate(0, 0, 2), Amount: 35_00},
+	}, nil)
+	repo.MockPlatformEarningRepo.EXPECT().GetReportsInPeriod(gomock.Any(), uint(1), monday, monday.AddDate(0, 0, 7)).Return([]repository.WeeklyReport{
+		{ID: 20, DriverID: 7, WeekStartDate: monday, Earnings: 120_00},
+		{ID: 21, DriverID: 8, WeekStartDate: monday, Earnings: 300_00},
+	}, nil)
+	repo.MockUserRepo.EXPECT().GetUsersByTenant(gomock.Any(), uint(1)).Return(platformDrivers, nil)
+
+	result, err := svc.Reconcile(context.Background(), 1, "2024-03-04", "2024-03-10")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Weeks) != 2 || result.Flagged != 1 {
+		t.Fatalf("expected 2 weeks with 1 flagged, got %+v", result)
+	}
+	ama := result.Weeks[0]
+	if ama.DriverID != 7 || !ama.Flagged || ama.PlatformTotal != 160_50 || ama.Difference != 40_50 {
+		t.Fatalf("expected Ama's week to be flagged for 40.50, got %+v", ama)
+	}
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"taxifleet/backend/internal/cache"
+	"taxifleet/backend/internal/permissions"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/validation"
+)
+
+// ErrPolicyDenied is returned when a policy of the tenant denies an action
+var ErrPolicyDenied = forbidden("not allowed by the tenant's policies")
+
+// Actions policies can be written for
+const (
+	PolicyViewTaxis      = "taxis.view"
+	PolicyAddTaxis       = "taxis.add"
+	PolicyEditTaxis      = "taxis.edit"
+	PolicyDeleteTaxis    = "taxis.delete"
+	PolicyViewExpenses   = "expenses.view"
+	PolicyAddExpenses    = "expenses.add"
+	PolicyEditExpenses   = "expenses.edit"
+	PolicyDeleteExpenses = "expenses.delete"
+	PolicyViewDeposits   = "deposits.view"
+	PolicyAddDeposits    = "deposits.add"
+	PolicyEditDeposits   = "deposits.edit"
+	PolicyDeleteDeposits = "deposits.delete"
+	PolicyApproveReports = "reports.approve"
+)
+
+// policyAttributes are the attributes the conditions of each action may
+// compare. Actions checked by routes have none.
+var policyAttributes = map[string][]string{
+	PolicyViewTaxis:      nil,
+	PolicyAddTaxis:       nil,
+	PolicyEditTaxis:      nil,
+	PolicyDeleteTaxis:    nil,
+	PolicyViewExpenses:   nil,
+	PolicyAddExpenses:    nil,
+	PolicyEditExpenses:   nil,
+	PolicyDeleteExpenses: nil,
+	PolicyViewDeposits:   nil,
+	PolicyAddDeposits:    nil,
+	PolicyEditDeposits:   nil,
+	PolicyDeleteDeposits: nil,
+	PolicyApproveReports: {"amount"}, // The report's earnings
+}
+
+var policyRoles = []string{"owner", "manager", "mechanic", "driver", "custom"}
+
+// PolicyDecision is what the policies of a tenant decide about an action
+type PolicyDecision int
+
+const (
+	// PolicyUndecided means no policy is about the action and the user, so
+	// the permission bitmask decides
+	PolicyUndecided PolicyDecision = iota
+	PolicyAllow
+	PolicyDeny
+)
+
+// PolicyRepository is the data access PolicyService depends on
+type PolicyRepository interface {
+	repository.PolicyRepo
+	repository.TenantRepo
+}
+
+// PolicyService manages the tenants' policies and evaluates them. Policies
+// refine the permission bitmask: where none applies, the bitmask decides.
+type PolicyService struct {
+	repo  PolicyRepository
+	cache cache.Cache
+}
+
+func NewPolicyService(repo PolicyRepository, cache cache.Cache) *PolicyService {
+	return &PolicyService{repo: repo, cache: cache}
+}
+
+// PolicyRequest creates or replaces a policy
+type PolicyRequest struct {
+	Subject     string                       `json:"subject" binding:"required"`
+	Action      string                       `json:"action" binding:"required"`
+	Effect      string                       `json:"effect" binding:"required,oneof=allow deny"`
+	Conditions  []repository.PolicyCondition `json:"conditions"`
+	Description string                       `json:"description"`
+}
+
+// Decide evaluates the tenant's policies about the action for the user.
+// Among the policies whose subject and action match, a deny whose conditions
+// hold wins over an allow; if none holds the action is denied. Platform
+// admins are not subject to policies.
+func (s *PolicyService) Decide(ctx context.Context, tenantID, userID uint, permission int, action string, attributes map[string]float64) (PolicyDecision, error) {
+	if permissions.GetRoleName(permission) == "admin" {
+		return PolicyUndecided, nil
+	}
+
+	policies, err := cache.Remember(ctx, s.cache, tenantID, "policies", func() ([]repository.Policy, error) {
+		return s.repo.GetPolicies(ctx, tenantID)
+	})
+	if err != nil {
+		return PolicyUndecided, err
+	}
+
+	subjects := []string{"*", permissions.GetRoleName(permission), fmt.Sprintf("user:%d", userID)}
+	decision := PolicyUndecided
+	for _, policy := range policies {
+		if policy.Action != action || !slices.Contains(subjects, policy.Subject) {
+			continue
+		}
+		if decision == PolicyUndecided {
+			decision = PolicyDeny
+		}
+		if !conditionsHold(policy.Conditions, attributes) {
+			continue
+		}
+		if policy.Effect == "deny" {
+			return PolicyDeny, nil
+		}
+		decision = PolicyAllow
+	}
+	return decision, nil
+}
+
+// Authorize returns ErrPolicyDenied unless the policies allow the action or,
+// without a policy about it, the user has one of the fallback permissions
+func (s *PolicyService) Authorize(ctx context.Context, tenantID, userID uint, permission int, action string, attributes map[string]float64, fallback ...int) error {
+	decision, err := s.Decide(ctx, tenantID, userID, permission, action, attributes)
+	if err != nil {
+		return err
+	}
+	switch decision {
+	case PolicyAllow:
+		return nil
+	case PolicyDeny:
+		return ErrPolicyDenied
+	}
+	if !permissions.HasAnyPermission(permission, fallback...) {
+		return ErrPolicyDenied
+	}
+	return nil
+}
+
+// conditionsHold reports whether every condition holds; a condition on an
+// attribute that is not given does not
+func conditionsHold(conditions []repository.PolicyCondition, attributes map[string]float64) bool {
+	for _, condition := range conditions {
+		value, ok := attributes[condition.Attribute]
+		if !ok {
+			return false
+		}
+		var holds bool
+		switch condition.Operator {
+		case "lt":
+			holds = value < condition.Value
+		case "lte":
+			holds = value <= condition.Value
+		case "gt":
+			holds = value > condition.Value
+		case "gte":
+			holds = value >= condition.Value
+		case "eq":
+			holds = value == condition.Value
+		case "ne":
+			holds = value != condition.Value
+		}
+		if !holds {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *PolicyService) List(ctx context.Context, tenantID uint) ([]repository.Policy, error) {
+	if _, err := s.repo.GetTenantByID(ctx, tenantID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetPolicies(ctx, tenantID)
+}
+
+func (s *PolicyService) Create(ctx context.Context, tenantID uint, req PolicyRequest) (*repository.Policy, error) {
+	if _, err := s.repo.GetTenantByID(ctx, tenantID); err != nil {
+		return nil, err
+	}
+
+	policy := &repository.Policy{TenantID: tenantID}
+	if err := applyPolicyRequest(policy, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreatePolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+
+	s.cache.Invalidate(ctx, tenantID)
+	return policy, nil
+}
+
+// Update replaces a policy of the tenant
+func (s *PolicyService) Update(ctx context.Context, tenantID, id uint, req PolicyRequest) (*repository.Policy, error) {
+	policy, err := s.getPolicy(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyPolicyRequest(policy, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdatePolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+
+	s.cache.Invalidate(ctx, tenantID)
+	return policy, nil
+}
+
+func (s *PolicyService) Delete(ctx context.Context, tenantID, id uint) error {
+	if _, err := s.getPolicy(ctx, tenantID, id); err != nil {
+		return err
+	}
+	if err := s.repo.DeletePolicy(ctx, id); err != nil {
+		return err
+	}
+
+	s.cache.Invalidate(ctx, tenantID)
+	return nil
+}
+
+func (s *PolicyService) getPolicy(ctx context.Context, tenantID, id uint) (*repository.Policy, error) {
+	policy, err := s.repo.GetPolicyByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if policy.TenantID != tenantID {
+		return nil, notFound("policy not found")
+	}
+	return policy, nil
+}
+
+func applyPolicyRequest(policy *repository.Policy, req PolicyRequest) error {
+	subject := strings.TrimSpace(req.Subject)
+	if !validPolicySubject(subject) {
+		return &validation.FieldError{Field: "subject", Rule: "policy_subject", Message: "subject must be a role (" + strings.Join(policyRoles, ", ") + "), user:<id> or *"}
+	}
+	attributes, ok := policyAttributes[req.Action]
+	if !ok {
+		return &validation.FieldError{Field: "action", Rule: "policy_action", Message: fmt.Sprintf("unknown action %q", req.Action)}
+	}
+	for _, condition := range req.Conditions {
+		if !slices.Contains(attributes, condition.Attribute) {
+			return &validation.FieldError{Field: "conditions", Rule: "policy_attribute", Message: fmt.Sprintf("%s has no attribute %q", req.Action, condition.Attribute)}
+		}
+		if !slices.Contains([]string{"lt", "lte", "gt", "gte", "eq", "ne"}, condition.Operator) {
+			return &validation.FieldError{Field: "conditions", Rule: "policy_operator", Message: "operator must be lt, lte, gt, gte, eq or ne"}
+		}
+	}
+
+	policy.Subject = subject
+	policy.Action = req.Action
+	policy.Effect = req.Effect
+	policy.Conditions = req.Conditions
+	if policy.Conditions == nil {
+		policy.Conditions = []repository.PolicyCondition{}
+	}
+	policy.Description = req.Description
+	return nil
+}
+
+func validPolicySubject(subject string) bool {
+	if subject == "*" || slices.Contains(policyRoles, subject) {
+		return true
+	}
+	id, ok := strings.CutPrefix(subject, "user:")
+	if !ok {
+		return false
+	}
+	n, err := strconv.ParseUint(id, 10, 32)
+	return err == nil && n > 0
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"taxifleet/backend/internal/cache"
+	"taxifleet/backend/internal/permissions"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+	"taxifleet/backend/internal/validation"
+
+	"go.uber.org/mock/gomock"
+)
+
+type policyRepoMock struct {
+	*mocks.MockPolicyRepo
+	*mocks.MockTenantRepo
+}
+
+func newPolicyServiceMock(t *testing.T) (*PolicyService, policyRepoMock) {
+	ctrl := gomock.NewController(t)
+	repo := policyRepoMock{
+		MockPolicyRepo: mocks.NewMockPolicyRepo(ctrl),
+		MockTenantRepo: mocks.NewMockTenantRepo(ctrl),
+	}
+	return NewPolicyService(repo, cache.Noop{}), repo
+}
+
+func TestPolicyApprovalUnderAmount(t *testing.T) {
+	svc, repo := newPolicyServiceMock(t)
+	repo.MockPolicyRepo.EXPECT().GetPolicies(gomock.Any(), uint(1)).Return([]repository.Policy{{
+		TenantID: 1, Subject: "manager", Action: PolicyApproveReports, Effect: "allow",
+		Conditions: []repository.PolicyCondition{{Attribute: "amount", Operator: "lt", Value: 100000}},
+	}}, nil).AnyTimes()
+
+	tests := []struct {
+		permission int
+		amount     float64
+		want       PolicyDecision
+	}{
+		{permissions.PermissionManager, 50000, PolicyAllow},
+		{permissions.PermissionManager, 150000, PolicyDeny},
+		{permissions.PermissionOwner, 150000, PolicyUndecided},
+	}
+	for _, tt := range tests {
+		got, err := svc.Decide(context.Background(), 1, 3, tt.permission, PolicyApproveReports, map[string]float64{"amount": tt.amount})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("Decide(%s, %v) = %v, want %v", permissions.GetRoleName(tt.permission), tt.amount, got, tt.want)
+		}
+	}
+}
+
+func TestPolicyDenyWinsAndFallback(t *testing.T) {
+	svc, repo := newPolicyServiceMock(t)
+	repo.MockPolicyRepo.EXPECT().GetPolicies(gomock.Any(), uint(1)).Return([]repository.Policy{
+		{TenantID: 1, Subject: "*", Action: PolicyDeleteTaxis, Effect: "allow"},
+		{TenantID: 1, Subject: "user:4", Action: PolicyDeleteTaxis, Effect: "deny"},
+	}, nil).AnyTimes()
+
+	if err := svc.Authorize(context.Background(), 1, 4, permissions.PermissionOwner, PolicyDeleteTaxis, nil, permissions.PermissionDeleteTaxis); !errors.Is(err, ErrPolicyDenied) {
+		t.Fatalf("expected the user's deny to win, got %v", err)
+	}
+	if err := svc.Authorize(context.Background(), 1, 5, permissions.PermissionDriver, PolicyDeleteTaxis, nil, permissions.PermissionDeleteTaxis); err != nil {
+		t.Fatalf("expected the policy to allow, got %v", err)
+	}
+	// No policy about the action: the bitmask decides
+	if err := svc.Authorize(context.Background(), 1, 5, permissions.PermissionDriver, PolicyAddTaxis, nil, permissions.PermissionAddTaxis); !errors.Is(err, ErrPolicyDenied) {
+		t.Fatalf("expected the bitmask to deny, got %v", err)
+	}
+}
+
+func TestCreatePolicyValidates(t *testing.T) {
+	svc, repo := newPolicyServiceMock(t)
+	repo.MockTenantRepo.EXPECT().GetTenantByID(gomock.Any(), uint(1)).Return(&repository.Tenant{ID: 1}, nil).AnyTimes()
+
+	invalid := []PolicyRequest{
+		{Subject: "accountant", Action: PolicyApproveReports, Effect: "allow"},
+		{Subject: "manager", Action: "reports.publish", Effect: "allow"},
+		{Subject: "manager", Action: PolicyViewTaxis, Effect: "allow", Conditions: []repository.PolicyCondition{{Attribute: "amount", Operator: "lt", Value: 1}}},
+		{Subject: "manager", Action: PolicyApproveReports, Effect: "allow", Conditions: []repository.PolicyCondition{{Attribute: "amount", Operator: "under", Value: 1}}},
+	}
+	for _, req := range invalid {
+		var fieldErr *validation.FieldError
+		if _, err := svc.Create(context.Background(), 1, req); !errors.As(err, &fieldErr) {
+			t.Errorf("expected a validation error for %+v, got %v", req, err)
+		}
+	}
+
+	repo.MockPolicyRepo.EXPECT().CreatePolicy(gomock.Any(), gomock.Any()).Return(nil)
+	policy, err := svc.Create(context.Background(), 1, PolicyRequest{Subject: "user:7", Action: PolicyEditDeposits, Effect: "deny"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if policy.TenantID != 1 || policy.Conditions == nil {
+		t.Fatalf("unexpected policy %+v", policy)
+	}
+}
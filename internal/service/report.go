@@ -1,92 +1,370 @@
 package service
 
 import (
+	"cmp"
+	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"taxifleet/backend/internal/antivirus"
+	"taxifleet/backend/internal/cache"
+	"taxifleet/backend/internal/config"
+	"taxifleet/backend/internal/money"
 	"taxifleet/backend/internal/permissions"
 	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/storage"
+	"taxifleet/backend/internal/validation"
 	"time"
 )
 
+// ReportRepository is the data access ReportService depends on
+type ReportRepository interface {
+	repository.Transactor
+	repository.ReportRepo
+	repository.ReportAttachmentRepo
+	repository.ReportAdjustmentRepo
+	repository.ReportRejectionRepo
+	repository.ReportSignatureRepo
+	repository.UserRepo
+	repository.ExpenseRepo
+	repository.TaxiRepo
+	repository.TenantRepo
+	repository.DriverLedgerRepo
+	repository.DelegationRepo
+	repository.PeriodRepo
+	repository.AuditRepo
+}
+
 type ReportService struct {
-	repo *repository.Repository
+	repo          ReportRepository
+	cache         cache.Cache
+	notifications *NotificationService
+	policies      *PolicyService
+	files         storage.Store
+	scanner       antivirus.Scanner // nil when uploads are not scanned
+	attachments   config.AttachmentConfig
 }
 
-func NewReportService(repo *repository.Repository) *ReportService {
-	return &ReportService{repo: repo}
+func NewReportService(repo ReportRepository, cache cache.Cache, notifications *NotificationService, policies *PolicyService, files storage.Store, scanner antivirus.Scanner, attachments config.AttachmentConfig) *ReportService {
+	return &ReportService{repo: repo, cache: cache, notifications: notifications, policies: policies, files: files, scanner: scanner, attachments: attachments}
 }
 
 type CreateReportRequest struct {
-	TaxiID        uint      `json:"taxi_id" binding:"required"`
-	WeekStartDate time.Time `json:"week_start_date" binding:"required"`
-	Earnings      float64   `json:"earnings" binding:"required"`
-	Notes         string    `json:"notes"`
+	TaxiID        uint         `json:"taxi_id" binding:"required"`
+	WeekStartDate time.Time    `json:"week_start_date" binding:"required"`
+	Earnings      money.Amount `json:"earnings" binding:"required,amount"`
+	Notes         string       `json:"notes"`
+	ClientID      string       `json:"client_id" binding:"omitempty,uuid"` // Set by the driver app for offline creates
 }
 
+// UpdateReportRequest changes the fields that are set; earnings may be set
+// to 0 and empty notes clear them
 type UpdateReportRequest struct {
-	WeekStartDate time.Time `json:"week_start_date"`
-	Earnings      float64   `json:"earnings"`
-	Notes         string    `json:"notes"`
+	WeekStartDate time.Time     `json:"week_start_date"`
+	Earnings      *money.Amount `json:"earnings" binding:"omitempty,min=0"`
+	Notes         *string       `json:"notes"`
+	Version       int           `json:"version"` // Version the edit is based on; 0 skips the check
+}
+
+// WeekPeriod is an inclusive range of dates making up one reporting week
+type WeekPeriod struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+type WeekBoundaries struct {
+	WeekStartDay string     `json:"week_start_day"`
+	Current      WeekPeriod `json:"current"`
+	Previous     WeekPeriod `json:"previous"`
 }
 
-func (s *ReportService) Create(tenantID uint, driverID uint, req CreateReportRequest) (*repository.WeeklyReport, error) {
+// startOfWeek snaps a date to the first day of the week containing it,
+// dropping any time of day so reports always land on a calendar date
+func startOfWeek(date time.Time, weekStart time.Weekday) time.Time {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	offset := (int(day.Weekday()) - int(weekStart) + 7) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+func newWeekPeriod(start time.Time) WeekPeriod {
+	return WeekPeriod{
+		Start: start.Format("2006-01-02"),
+		End:   start.AddDate(0, 0, 6).Format("2006-01-02"),
+	}
+}
+
+// ErrDuplicateReport is returned when the driver already has a report for the taxi and week
+var ErrDuplicateReport = errors.New("a report already exists for this taxi and week")
+
+// ensureUniqueWeek rejects a second report for the same taxi, driver and week
+func (s *ReportService) ensureUniqueWeek(ctx context.Context, report *repository.WeeklyReport) error {
+	exists, err := s.repo.ReportExistsForWeek(ctx, report.TenantID, report.TaxiID, report.DriverID, report.WeekStartDate, report.ID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrDuplicateReport
+	}
+	return nil
+}
+
+// weekStartDay returns the tenant's configured first day of the reporting week
+func (s *ReportService) weekStartDay(ctx context.Context, tenantID uint) (time.Weekday, error) {
+	tenant, err := s.repo.GetTenantByID(ctx, tenantID)
+	if err != nil {
+		return time.Monday, notFound("tenant not found")
+	}
+	return parseTenantSettings(tenant.Settings).WeekStart(), nil
+}
+
+// GetWeekBoundaries returns the current and previous reporting weeks for the tenant
+func (s *ReportService) GetWeekBoundaries(ctx context.Context, tenantID uint) (*WeekBoundaries, error) {
+	weekStart, err := s.weekStartDay(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	current := startOfWeek(time.Now(), weekStart)
+
+	return &WeekBoundaries{
+		WeekStartDay: strings.ToLower(weekStart.String()),
+		Current:      newWeekPeriod(current),
+		Previous:     newWeekPeriod(current.AddDate(0, 0, -7)),
+	}, nil
+}
+
+func (s *ReportService) Create(ctx context.Context, tenantID uint, driverID uint, req CreateReportRequest) (*repository.WeeklyReport, error) {
 	// Verify taxi belongs to tenant
-	taxi, err := s.repo.GetTaxiByID(req.TaxiID)
+	taxi, err := s.repo.GetTaxiByID(ctx, req.TaxiID)
 	if err != nil {
-		return nil, errors.New("taxi not found")
+		return nil, notFound("taxi not found")
 	}
 	if taxi.TenantID != tenantID {
-		return nil, errors.New("taxi not found")
+		return nil, notFound("taxi not found")
+	}
+
+	weekStart, err := s.weekStartDay(ctx, tenantID)
+	if err != nil {
+		return nil, err
 	}
 
 	report := &repository.WeeklyReport{
 		TenantID:      tenantID,
 		TaxiID:        req.TaxiID,
 		DriverID:      driverID,
-		WeekStartDate: req.WeekStartDate,
+		WeekStartDate: startOfWeek(req.WeekStartDate, weekStart),
 		Earnings:      req.Earnings,
 		TotalExpenses: 0,
 		Status:        "draft",
 		Notes:         req.Notes,
 	}
+	if req.ClientID != "" {
+		report.ClientID = &req.ClientID
+	}
 
-	if err := s.repo.CreateReport(report); err != nil {
+	if err := ensurePeriodOpen(ctx, s.repo, tenantID, report.WeekStartDate); err != nil {
+		return nil, err
+	}
+	if err := s.ensureUniqueWeek(ctx, report); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateReport(ctx, report); err != nil {
+		// Concurrent creates can still race past the check; the index catches them
+		if repository.IsUniqueViolation(err) {
+			return nil, ErrDuplicateReport
+		}
 		return nil, err
 	}
 
-	return s.repo.GetReportByID(report.ID)
+	s.cache.Invalidate(ctx, tenantID)
+
+	return s.repo.GetReportByID(ctx, report.ID)
 }
 
-func (s *ReportService) GetByID(id uint, tenantID uint) (*repository.WeeklyReport, error) {
-	report, err := s.repo.GetReportByID(id)
+func (s *ReportService) GetByID(ctx context.Context, id uint, tenantID uint) (*repository.WeeklyReport, error) {
+	report, err := s.repo.GetReportByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	if report.TenantID != tenantID {
-		return nil, errors.New("report not found")
+		return nil, notFound("report not found")
 	}
 
 	return report, nil
 }
 
-func (s *ReportService) List(tenantID uint, userID uint, permission int) ([]repository.WeeklyReport, error) {
-	// Drivers can only see their own reports (only have view/add report permissions)
+// reportSortKeys are the fields the report list can be sorted by
+var reportSortKeys = sortKeys[repository.WeeklyReport]{
+	"id":              func(a, b repository.WeeklyReport) int { return cmp.Compare(a.ID, b.ID) },
+	"week_start_date": func(a, b repository.WeeklyReport) int { return a.WeekStartDate.Compare(b.WeekStartDate) },
+	"earnings":        func(a, b repository.WeeklyReport) int { return cmp.Compare(a.Earnings, b.Earnings) },
+	"total_expenses":  func(a, b repository.WeeklyReport) int { return cmp.Compare(a.TotalExpenses, b.TotalExpenses) },
+	"status":          func(a, b repository.WeeklyReport) int { return cmp.Compare(a.Status, b.Status) },
+	"taxi_id":         func(a, b repository.WeeklyReport) int { return cmp.Compare(a.TaxiID, b.TaxiID) },
+	"driver_id":       func(a, b repository.WeeklyReport) int { return cmp.Compare(a.DriverID, b.DriverID) },
+	"created_at":      func(a, b repository.WeeklyReport) int { return a.CreatedAt.Compare(b.CreatedAt) },
+}
+
+// List returns the reports visible to the user, those matching opts.Query if
+// set, latest week first unless opts.Sort says otherwise
+func (s *ReportService) List(ctx context.Context, tenantID uint, userID uint, permission int, opts ListOptions) ([]repository.WeeklyReport, error) {
+	var reports []repository.WeeklyReport
+	var err error
+	if opts.Query != "" {
+		driverID := uint(0)
+		if permission == permissions.PermissionDriver {
+			driverID = userID
+		}
+		reports, err = s.repo.SearchReports(ctx, tenantID, driverID, opts.Query, 0)
+	} else if permission == permissions.PermissionDriver {
+		// Drivers can only see their own reports (only have view/add report permissions)
+		reports, err = cache.Remember(ctx, s.cache, tenantID, fmt.Sprintf("reports:driver:%d", userID), func() ([]repository.WeeklyReport, error) {
+			return s.repo.GetReportsByDriver(ctx, tenantID, userID)
+		})
+	} else {
+		// Owners, managers, and others with view permissions see all tenant reports
+		reports, err = cache.Remember(ctx, s.cache, tenantID, "reports", func() ([]repository.WeeklyReport, error) {
+			return s.repo.GetReportsByTenant(ctx, tenantID)
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return sortList(reports, opts.Sort, reportSortKeys)
+}
+
+// ListArchived returns the archived reports the user can see, latest week
+// first. Drivers only get their own. Archived reports can be sorted but not
+// searched.
+func (s *ReportService) ListArchived(ctx context.Context, tenantID uint, userID uint, permission int, opts ListOptions) ([]repository.WeeklyReport, error) {
+	if opts.Query != "" {
+		return nil, &validation.FieldError{Field: "q", Rule: "archived", Message: "archived reports can't be searched"}
+	}
+	driverID := uint(0)
+	if permission == permissions.PermissionDriver {
+		driverID = userID
+	}
+	reports, err := s.repo.GetArchivedReports(ctx, tenantID, driverID)
+	if err != nil {
+		return nil, err
+	}
+	return sortList(reports, opts.Sort, reportSortKeys)
+}
+
+// ArchiveRequest archives the reviewed reports of the weeks that started
+// more than the given number of months ago
+type ArchiveRequest struct {
+	OlderThanMonths int `json:"older_than_months" binding:"required,min=1"`
+}
+
+// Archive archives the tenant's approved and rejected reports of the weeks
+// before the cutoff and returns how many it archived. Drafts and submitted
+// reports are still being worked on and stay in the lists.
+func (s *ReportService) Archive(ctx context.Context, tenantID uint, req ArchiveRequest) (int64, error) {
+	if req.OlderThanMonths < 1 {
+		return 0, &validation.FieldError{Field: "older_than_months", Rule: "min", Message: "older_than_months must be at least 1"}
+	}
+	now := time.Now().UTC()
+	cutoff := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, -req.OlderThanMonths, 0)
+
+	archived, err := s.repo.ArchiveReports(ctx, tenantID, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	if archived > 0 {
+		s.cache.Invalidate(ctx, tenantID)
+	}
+	return archived, nil
+}
+
+// Count counts the reports the user can list, only those with the status
+// when set
+func (s *ReportService) Count(ctx context.Context, tenantID uint, userID uint, permission int, status string) (int64, error) {
+	driverID := uint(0)
+	if permission == permissions.PermissionDriver {
+		driverID = userID
+	}
+	return s.repo.CountReports(ctx, tenantID, driverID, status)
+}
+
+// Summary counts the reports the user can list and sums their earnings per
+// status, taxi, driver or month
+func (s *ReportService) Summary(ctx context.Context, tenantID uint, userID uint, permission int, groupBy string) ([]repository.GroupTotal, error) {
+	if err := checkGroupBy(groupBy, reportSummaryGroups); err != nil {
+		return nil, err
+	}
+	driverID := uint(0)
+	if permission == permissions.PermissionDriver {
+		driverID = userID
+	}
+	return s.repo.SummarizeReports(ctx, tenantID, driverID, groupBy)
+}
+
+// ExportRows returns the reports to export, latest week first, without
+// loading their taxis and drivers one by one. Drivers only get their own.
+func (s *ReportService) ExportRows(ctx context.Context, tenantID uint, userID uint, permission int) ([]repository.ReportExportRow, error) {
+	driverID := uint(0)
 	if permission == permissions.PermissionDriver {
-		return s.repo.GetReportsByDriver(userID)
+		driverID = userID
 	}
+	return s.repo.GetReportExportRows(ctx, tenantID, driverID)
+}
 
-	// Owners, managers, and others with view permissions see all tenant reports
-	return s.repo.GetReportsByTenant(tenantID)
+// ReportPage is a page of the report list
+type ReportPage struct {
+	Reports    []repository.WeeklyReport
+	NextCursor string // Fetches the following page; empty on the last page
 }
 
-func (s *ReportService) Update(id uint, tenantID uint, driverID uint, permission int, req UpdateReportRequest) (*repository.WeeklyReport, error) {
-	report, err := s.repo.GetReportByID(id)
+// Page returns up to limit reports after the cursor, or the latest without
+// one, latest week first. Unlike List it reads one page from the database,
+// so it stays fast on tenants with years of reports. Drivers only get their
+// own reports.
+func (s *ReportService) Page(ctx context.Context, tenantID uint, userID uint, permission int, cursor string, limit int) (*ReportPage, error) {
+	after, err := decodeKeyset(cursor)
+	if err != nil {
+		return nil, err
+	}
+	driverID := uint(0)
+	if permission == permissions.PermissionDriver {
+		driverID = userID
+	}
+
+	// One extra report tells whether another page follows
+	reports, err := s.repo.GetReportsPage(ctx, tenantID, driverID, after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &ReportPage{Reports: reports}
+	if len(reports) > limit {
+		page.Reports = reports[:limit]
+		last := page.Reports[limit-1]
+		page.NextCursor = encodeKeyset(last.WeekStartDate, last.ID)
+	}
+	return page, nil
+}
+
+// ListVersion identifies the current state of the tenant's reports; a
+// driver's own list changes only when the tenant's does
+func (s *ReportService) ListVersion(ctx context.Context, tenantID uint) (*repository.ListVersion, error) {
+	return s.repo.GetReportListVersion(ctx, tenantID)
+}
+
+func (s *ReportService) Update(ctx context.Context, id uint, tenantID uint, driverID uint, permission int, req UpdateReportRequest) (*repository.WeeklyReport, error) {
+	report, err := s.repo.GetReportByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	if report.TenantID != tenantID {
-		return nil, errors.New("report not found")
+		return nil, notFound("report not found")
+	}
+
+	if err := ensurePeriodOpen(ctx, s.repo, tenantID, report.WeekStartDate); err != nil {
+		return nil, err
 	}
 
 	// Check if user has global edit permission (owner, manager, admin)
@@ -96,90 +374,167 @@ func (s *ReportService) Update(id uint, tenantID uint, driverID uint, permission
 	if !hasEditPermission {
 		// User must be the creator of the report
 		if report.DriverID != driverID {
-			return nil, errors.New("unauthorized")
+			return nil, forbidden("you can only edit your own reports")
 		}
 		// Can only edit draft reports
 		if report.Status != "draft" {
-			return nil, errors.New("can only edit draft reports")
+			return nil, conflict("can only edit draft reports")
 		}
 	} else {
 		// Users with edit permission can edit any report, but only if it's in draft or submitted status
 		if report.Status == "approved" {
-			return nil, errors.New("cannot edit approved reports")
+			return nil, conflict("cannot edit approved reports")
 		}
 	}
 
+	if req.Version != 0 && req.Version != report.Version {
+		return nil, repository.ErrVersionConflict
+	}
+
 	if !req.WeekStartDate.IsZero() {
-		report.WeekStartDate = req.WeekStartDate
+		weekStart, err := s.weekStartDay(ctx, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		report.WeekStartDate = startOfWeek(req.WeekStartDate, weekStart)
+		if err := ensurePeriodOpen(ctx, s.repo, tenantID, report.WeekStartDate); err != nil {
+			return nil, err
+		}
+		if err := s.ensureUniqueWeek(ctx, report); err != nil {
+			return nil, err
+		}
 	}
-	if req.Earnings != 0 {
-		report.Earnings = req.Earnings
+	if req.Earnings != nil {
+		report.Earnings = *req.Earnings
 	}
-	if req.Notes != "" {
-		report.Notes = req.Notes
+	if req.Notes != nil {
+		report.Notes = *req.Notes
 	}
 
 	// Recalculate total expenses
-	expenses, _ := s.repo.GetExpensesByReport(report.ID)
-	total := 0.0
+	expenses, _ := s.repo.GetExpensesByReport(ctx, report.ID)
+	var total money.Amount
 	for _, exp := range expenses {
 		total += exp.Amount
 	}
 	report.TotalExpenses = total
 
-	if err := s.repo.UpdateReport(report); err != nil {
+	if err := s.repo.UpdateReport(ctx, report); err != nil {
+		if repository.IsUniqueViolation(err) {
+			return nil, ErrDuplicateReport
+		}
 		return nil, err
 	}
 
-	return s.repo.GetReportByID(report.ID)
+	s.cache.Invalidate(ctx, tenantID)
+
+	return s.repo.GetReportByID(ctx, report.ID)
 }
 
-func (s *ReportService) Submit(id uint, tenantID uint, driverID uint) (*repository.WeeklyReport, error) {
-	report, err := s.repo.GetReportByID(id)
+func (s *ReportService) Submit(ctx context.Context, id uint, tenantID uint, driverID uint) (*repository.WeeklyReport, error) {
+	report, err := s.repo.GetReportByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	if report.TenantID != tenantID {
-		return nil, errors.New("report not found")
+		return nil, notFound("report not found")
+	}
+
+	if err := ensurePeriodOpen(ctx, s.repo, tenantID, report.WeekStartDate); err != nil {
+		return nil, err
 	}
 
 	if report.DriverID != driverID {
-		return nil, errors.New("unauthorized")
+		return nil, forbidden("you can only submit your own reports")
 	}
 
 	if report.Status != "draft" {
-		return nil, errors.New("report already submitted")
+		return nil, conflict("report already submitted")
 	}
 
 	now := time.Now()
 	report.Status = "submitted"
 	report.SubmittedAt = &now
 
-	if err := s.repo.UpdateReport(report); err != nil {
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdateReport(ctx, report); err != nil {
+			return err
+		}
+		status := "submitted"
+		if report.Resubmissions > 0 {
+			status = "resubmitted"
+		}
+		return s.repo.CreateAuditEvent(ctx, auditEvent(tenantID, driverID, ActionReportSubmitted, "report", report.ID, reportSummary(report, status)))
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return s.repo.GetReportByID(report.ID)
-}
+	s.cache.Invalidate(ctx, tenantID)
 
-func (s *ReportService) Approve(id uint, tenantID uint, approvedByID uint, permission int) (*repository.WeeklyReport, error) {
-	// Only owner or admin can approve reports
-	if !permissions.HasAnyPermission(permission, permissions.PermissionOwner, permissions.PermissionAdmin) {
-		return nil, errors.New("only owner or admin can approve reports")
+	submitted, err := s.repo.GetReportByID(ctx, report.ID)
+	if err != nil {
+		return nil, err
 	}
+	s.notifications.NotifyReportSubmitted(ctx, submitted)
+	return submitted, nil
+}
 
-	report, err := s.repo.GetReportByID(id)
+// Approve approves a submitted report, signed off as the request says
+func (s *ReportService) Approve(ctx context.Context, id uint, tenantID uint, approvedByID uint, permission int, req ApproveReportRequest) (*repository.WeeklyReport, error) {
+	report, err := s.repo.GetReportByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	if report.TenantID != tenantID {
-		return nil, errors.New("report not found")
+		return nil, notFound("report not found")
+	}
+
+	if err := ensurePeriodOpen(ctx, s.repo, tenantID, report.WeekStartDate); err != nil {
+		return nil, err
+	}
+
+	// The tenant's policies may say who approves which reports, e.g. managers
+	// up to an amount; without one only owner or admin can approve reports,
+	// or someone an owner delegated their approval rights to for today
+	decision, err := s.policies.Decide(ctx, tenantID, approvedByID, permission, PolicyApproveReports, map[string]float64{"amount": report.Earnings.Float()})
+	if err != nil {
+		return nil, err
+	}
+	delegated := false
+	switch {
+	case decision == PolicyDeny:
+		return nil, ErrPolicyDenied
+	case decision == PolicyUndecided && !hasOwnerRights(permission):
+		delegated, err = s.repo.HasActiveDelegation(ctx, tenantID, approvedByID, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		if !delegated {
+			return nil, forbidden("only owner or admin can approve reports")
+		}
 	}
 
 	if report.Status != "submitted" {
-		return nil, errors.New("report must be submitted before approval")
+		return nil, conflict("report must be submitted before approval")
+	}
+
+	tenant, err := s.repo.GetTenantByID(ctx, tenantID)
+	if err != nil {
+		return nil, notFound("tenant not found")
+	}
+	settings := parseTenantSettings(tenant.Settings)
+	signature, err := s.signOff(ctx, report, approvedByID, settings, req)
+	if err != nil {
+		return nil, err
+	}
+	// Shares are fixed on approval, so later rule changes don't rewrite them
+	if split := earningsSplitFor(&report.Taxi, settings); split != nil {
+		driverShare, ownerShare := splitEarnings(split, report.Earnings, report.TotalExpenses, report.TotalAdjustments)
+		report.DriverShare = &driverShare
+		report.OwnerShare = &ownerShare
 	}
 
 	now := time.Now()
@@ -187,61 +542,164 @@ func (s *ReportService) Approve(id uint, tenantID uint, approvedByID uint, permi
 	report.ApprovedAt = &now
 	report.ApprovedByID = &approvedByID
 
-	if err := s.repo.UpdateReport(report); err != nil {
+	// The offset and the approval are saved together so a conflicting edit
+	// doesn't leave an offset behind
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := offsetDriverDebt(ctx, s.repo, report, approvedByID); err != nil {
+			return err
+		}
+		if err := s.repo.UpdateReport(ctx, report); err != nil {
+			return err
+		}
+		if signature != nil {
+			if err := s.repo.CreateReportSignature(ctx, signature); err != nil {
+				return err
+			}
+		}
+		status := "approved"
+		if delegated {
+			status = "approved under delegation"
+		}
+		return s.repo.CreateAuditEvent(ctx, auditEvent(tenantID, approvedByID, ActionReportApproved, "report", report.ID, reportSummary(report, status)))
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return s.repo.GetReportByID(report.ID)
+	s.notifications.NotifyReportStatus(ctx, report)
+
+	s.cache.Invalidate(ctx, tenantID)
+
+	return s.repo.GetReportByID(ctx, report.ID)
 }
 
-func (s *ReportService) Reject(id uint, tenantID uint) (*repository.WeeklyReport, error) {
-	report, err := s.repo.GetReportByID(id)
+// RejectReportRequest gives the driver the reason their report was rejected
+type RejectReportRequest struct {
+	Reason string `json:"reason"`
+}
+
+// Reject rejects a submitted report and records the rejection in its history
+func (s *ReportService) Reject(ctx context.Context, id uint, tenantID uint, rejectedByID uint, req RejectReportRequest) (*repository.WeeklyReport, error) {
+	report, err := s.repo.GetReportByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	if report.TenantID != tenantID {
-		return nil, errors.New("report not found")
+		return nil, notFound("report not found")
+	}
+
+	if err := ensurePeriodOpen(ctx, s.repo, tenantID, report.WeekStartDate); err != nil {
+		return nil, err
 	}
 
 	if report.Status != "submitted" {
-		return nil, errors.New("report must be submitted before rejection")
+		return nil, conflict("report must be submitted before rejection")
 	}
 
 	report.Status = "rejected"
+	rejection := repository.ReportRejection{
+		TenantID:     tenantID,
+		ReportID:     report.ID,
+		RejectedByID: rejectedByID,
+		Reason:       strings.TrimSpace(req.Reason),
+		RejectedAt:   time.Now(),
+	}
+
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdateReport(ctx, report); err != nil {
+			return err
+		}
+		return s.repo.CreateReportRejection(ctx, &rejection)
+	})
+	if err != nil {
+		return nil, err
+	}
+	report.Rejections = append(report.Rejections, rejection)
+
+	s.notifications.NotifyReportStatus(ctx, report)
+
+	s.cache.Invalidate(ctx, tenantID)
+
+	return s.repo.GetReportByID(ctx, report.ID)
+}
+
+// Reopen puts a rejected report back to draft so it can be fixed and
+// submitted again. Drivers can only reopen their own reports. The rejections
+// stay in the report's history and its resubmission count goes up.
+func (s *ReportService) Reopen(ctx context.Context, id uint, tenantID uint, userID uint, permission int) (*repository.WeeklyReport, error) {
+	report, err := s.repo.GetReportByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if report.TenantID != tenantID {
+		return nil, notFound("report not found")
+	}
+
+	if err := ensurePeriodOpen(ctx, s.repo, tenantID, report.WeekStartDate); err != nil {
+		return nil, err
+	}
+
+	if !permissions.HasPermission(permission, permissions.PermissionEditReports) && report.DriverID != userID {
+		return nil, forbidden("you can only reopen your own reports")
+	}
+
+	if report.Status != "rejected" {
+		return nil, conflict("only rejected reports can be reopened")
+	}
+
+	report.Status = "draft"
+	report.SubmittedAt = nil
+	report.Resubmissions++
 
-	if err := s.repo.UpdateReport(report); err != nil {
+	if err := s.repo.UpdateReport(ctx, report); err != nil {
 		return nil, err
 	}
 
-	return s.repo.GetReportByID(report.ID)
+	s.cache.Invalidate(ctx, tenantID)
+
+	return s.repo.GetReportByID(ctx, report.ID)
 }
 
-func (s *ReportService) Delete(id uint, tenantID uint, driverID uint, permission int) error {
-	report, err := s.repo.GetReportByID(id)
+func (s *ReportService) deleteReport(ctx context.Context, id uint, tenantID uint) error {
+	if err := s.repo.DeleteReport(ctx, id); err != nil {
+		return err
+	}
+
+	s.cache.Invalidate(ctx, tenantID)
+	return nil
+}
+
+func (s *ReportService) Delete(ctx context.Context, id uint, tenantID uint, driverID uint, permission int) error {
+	report, err := s.repo.GetReportByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
 	if report.TenantID != tenantID {
-		return errors.New("report not found")
+		return notFound("report not found")
+	}
+
+	if err := ensurePeriodOpen(ctx, s.repo, tenantID, report.WeekStartDate); err != nil {
+		return err
 	}
 
 	// Owner/admin can delete reports in any status, including approved
 	if permissions.HasAnyPermission(permission, permissions.PermissionOwner, permissions.PermissionAdmin) {
-		return s.repo.DeleteReport(id)
+		return s.deleteReport(ctx, id, tenantID)
 	}
 
 	// Driver can only delete their own draft reports
 	if permission == permissions.PermissionDriver {
 		if report.DriverID != driverID {
-			return errors.New("unauthorized")
+			return forbidden("you can only delete your own reports")
 		}
 		if report.Status != "draft" {
-			return errors.New("can only delete draft reports")
+			return conflict("can only delete draft reports")
 		}
-		return s.repo.DeleteReport(id)
+		return s.deleteReport(ctx, id, tenantID)
 	}
 
-	return errors.New("unauthorized")
+	return forbidden("you are not allowed to delete reports")
 }
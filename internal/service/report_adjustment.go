@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/permissions"
+	"taxifleet/backend/internal/repository"
+)
+
+type AddAdjustmentRequest struct {
+	Type   string       `json:"type" binding:"required,oneof=bonus penalty"`
+	Amount money.Amount `json:"amount" binding:"required,amount"`
+	Reason string       `json:"reason" binding:"required"`
+}
+
+// ListAdjustments returns the bonuses and penalties on a report
+func (s *ReportService) ListAdjustments(ctx context.Context, reportID uint, tenantID uint) ([]repository.ReportAdjustment, error) {
+	report, err := s.GetByID(ctx, reportID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return report.Adjustments, nil
+}
+
+// AddAdjustment adds a bonus or penalty to a report that is not approved yet
+// and returns the report with its new total
+func (s *ReportService) AddAdjustment(ctx context.Context, reportID uint, tenantID uint, userID uint, permission int, req AddAdjustmentRequest) (*repository.WeeklyReport, error) {
+	if _, err := s.adjustableReport(ctx, reportID, tenantID, permission); err != nil {
+		return nil, err
+	}
+
+	err := s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateReportAdjustment(ctx, &repository.ReportAdjustment{
+			TenantID:    tenantID,
+			ReportID:    reportID,
+			Type:        req.Type,
+			Amount:      req.Amount,
+			Reason:      req.Reason,
+			CreatedByID: userID,
+		}); err != nil {
+			return err
+		}
+		return s.repo.RecalculateReportAdjustments(ctx, reportID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.Invalidate(ctx, tenantID)
+
+	return s.repo.GetReportByID(ctx, reportID)
+}
+
+// DeleteAdjustment removes a bonus or penalty from a report that is not
+// approved yet and returns the report with its new total
+func (s *ReportService) DeleteAdjustment(ctx context.Context, reportID uint, adjustmentID uint, tenantID uint, permission int) (*repository.WeeklyReport, error) {
+	if _, err := s.adjustableReport(ctx, reportID, tenantID, permission); err != nil {
+		return nil, err
+	}
+	adjustment, err := s.repo.GetReportAdjustmentByID(ctx, adjustmentID)
+	if err != nil {
+		return nil, err
+	}
+	if adjustment.TenantID != tenantID || adjustment.ReportID != reportID {
+		return nil, notFound("adjustment not found")
+	}
+
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteReportAdjustment(ctx, adjustmentID); err != nil {
+			return err
+		}
+		return s.repo.RecalculateReportAdjustments(ctx, reportID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.Invalidate(ctx, tenantID)
+
+	return s.repo.GetReportByID(ctx, reportID)
+}
+
+// adjustableReport loads a report whose adjustments the user may change.
+// Drivers can't adjust reports, not even their own.
+func (s *ReportService) adjustableReport(ctx context.Context, reportID uint, tenantID uint, permission int) (*repository.WeeklyReport, error) {
+	if !permissions.HasPermission(permission, permissions.PermissionEditReports) {
+		return nil, forbidden("you are not allowed to adjust reports")
+	}
+	report, err := s.GetByID(ctx, reportID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if report.Status == "approved" {
+		return nil, conflict("cannot change adjustments of approved reports")
+	}
+	if err := ensurePeriodOpen(ctx, s.repo, tenantID, report.WeekStartDate); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
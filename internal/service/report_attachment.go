@@ -0,0 +1,344 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"taxifleet/backend/internal/permissions"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/storage"
+	"taxifleet/backend/internal/thumbnail"
+)
+
+var (
+	// ErrAttachmentTooLarge is returned for files over the configured maximum size
+	ErrAttachmentTooLarge = errors.New("attachment is too large")
+	// ErrAttachmentType is returned for files that are not a supported image
+	ErrAttachmentType = errors.New("attachment must be a JPEG, PNG or WebP image")
+	// ErrAttachmentScanPending is returned for files not yet scanned for viruses
+	ErrAttachmentScanPending = errors.New("attachment is being scanned for viruses")
+	// ErrAttachmentQuarantined is returned for files the virus scan rejected
+	ErrAttachmentQuarantined = errors.New("attachment was quarantined by the virus scan")
+)
+
+// pendingScanBatch is how many attachments a run of the scan job retries
+const pendingScanBatch = 100
+
+// attachmentExtensions are the accepted content types, as detected from the
+// file itself, with the extension they are stored under
+var attachmentExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+// ListAttachments returns the photos attached to a report
+func (s *ReportService) ListAttachments(ctx context.Context, reportID uint, tenantID uint) ([]repository.ReportAttachment, error) {
+	if _, err := s.GetByID(ctx, reportID, tenantID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetReportAttachments(ctx, reportID)
+}
+
+// AddAttachment stores a photo and attaches it to the report. The report's
+// driver and users who can edit reports may attach photos until the report
+// is approved. With a virus scanner, the photo is scanned before it is
+// returned; a photo that could not be scanned stays pending for the scan job.
+func (s *ReportService) AddAttachment(ctx context.Context, reportID uint, tenantID uint, userID uint, permission int, fileName string, file io.Reader) (*repository.ReportAttachment, error) {
+	report, err := s.attachmentReport(ctx, reportID, tenantID, userID, permission)
+	if err != nil {
+		return nil, err
+	}
+
+	// Trust the file's content rather than the name or header the client sent
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	head = head[:n]
+	contentType := http.DetectContentType(head)
+	ext, ok := attachmentExtensions[contentType]
+	if !ok {
+		return nil, ErrAttachmentType
+	}
+
+	// Random names keep keys unguessable and free of the client's file name
+	name := make([]byte, 16)
+	if _, err := rand.Read(name); err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("%d/%s%s", tenantID, hex.EncodeToString(name), ext)
+	upload := &limitedUpload{r: io.MultiReader(bytes.NewReader(head), file), left: s.attachments.MaxSize}
+	if err := s.files.Put(ctx, key, upload, contentType); err != nil {
+		return nil, err
+	}
+
+	attachment := &repository.ReportAttachment{
+		TenantID:     tenantID,
+		ReportID:     report.ID,
+		UploadedByID: userID,
+		FileName:     filepath.Base(fileName),
+		ContentType:  contentType,
+		Size:         upload.size,
+		StoragePath:  key,
+		ScanStatus:   repository.ScanNotScanned,
+	}
+	if s.scanner != nil {
+		attachment.ScanStatus = repository.ScanPending
+	}
+	if err := s.repo.CreateReportAttachment(ctx, attachment); err != nil {
+		s.files.Delete(ctx, key)
+		return nil, err
+	}
+
+	if s.scanner != nil {
+		// The upload succeeded either way; ScanPendingAttachments retries
+		_ = s.scanAttachment(ctx, attachment)
+	}
+	return attachment, nil
+}
+
+// ScanPendingAttachments scans the attachments of every tenant whose scan
+// failed, e.g. while the scanner was unreachable, and returns how many it
+// scanned
+func (s *ReportService) ScanPendingAttachments(ctx context.Context) (int, error) {
+	if s.scanner == nil {
+		return 0, nil
+	}
+	attachments, err := s.repo.GetPendingReportAttachments(ctx, pendingScanBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	scanned := 0
+	var errs []error
+	for i := range attachments {
+		if err := s.scanAttachment(ctx, &attachments[i]); err != nil {
+			errs = append(errs, fmt.Errorf("attachment %d: %w", attachments[i].ID, err))
+			continue
+		}
+		scanned++
+	}
+	return scanned, errors.Join(errs...)
+}
+
+// scanAttachment scans the attachment's file and records the result. An
+// infected file is quarantined: it is kept but cannot be downloaded, and its
+// uploader is notified.
+func (s *ReportService) scanAttachment(ctx context.Context, attachment *repository.ReportAttachment) error {
+	file, err := s.files.Open(ctx, attachment.StoragePath)
+	if err != nil {
+		return err
+	}
+	verdict, err := s.scanner.Scan(ctx, file)
+	file.Close()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	attachment.ScanStatus = repository.ScanClean
+	attachment.ScanThreat = ""
+	attachment.ScannedAt = &now
+	if verdict.Infected {
+		attachment.ScanStatus = repository.ScanQuarantined
+		attachment.ScanThreat = verdict.Threat
+	}
+	if err := s.repo.UpdateReportAttachmentScan(ctx, attachment); err != nil {
+		return err
+	}
+
+	if verdict.Infected {
+		s.notifications.NotifyAttachmentQuarantined(ctx, attachment)
+	}
+	return nil
+}
+
+// limitedUpload reads an upload and fails with ErrAttachmentTooLarge once it
+// goes past the maximum size, which aborts storing it
+type limitedUpload struct {
+	r    io.Reader
+	left int64
+	size int64
+}
+
+func (u *limitedUpload) Read(p []byte) (int, error) {
+	// Read one byte past the limit to tell a file of exactly the maximum size
+	// from a larger one
+	if int64(len(p)) > u.left+1 {
+		p = p[:u.left+1]
+	}
+	n, err := u.r.Read(p)
+	u.size += int64(n)
+	u.left -= int64(n)
+	if u.left < 0 {
+		return n, ErrAttachmentTooLarge
+	}
+	return n, err
+}
+
+// OpenAttachment returns a report's attachment
+func (s *ReportService) OpenAttachment(ctx context.Context, reportID uint, attachmentID uint, tenantID uint) (*repository.ReportAttachment, error) {
+	attachment, err := s.GetAttachment(ctx, attachmentID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if attachment.ReportID != reportID {
+		return nil, notFound("attachment not found")
+	}
+	return attachment, nil
+}
+
+// GetAttachment returns an attachment of the tenant by its ID alone, for the
+// file routes
+func (s *ReportService) GetAttachment(ctx context.Context, attachmentID uint, tenantID uint) (*repository.ReportAttachment, error) {
+	attachment, err := s.repo.GetReportAttachmentByID(ctx, attachmentID)
+	if err != nil || attachment.TenantID != tenantID {
+		return nil, notFound("attachment not found")
+	}
+	return attachment, nil
+}
+
+// ReadAttachment opens the file of an attachment
+func (s *ReportService) ReadAttachment(ctx context.Context, attachment *repository.ReportAttachment) (io.ReadCloser, error) {
+	if err := downloadable(attachment); err != nil {
+		return nil, err
+	}
+	return s.files.Open(ctx, attachment.StoragePath)
+}
+
+// ReadThumbnail returns a JPEG thumbnail of an attachment, generated on the
+// first request and then kept in storage next to the file
+func (s *ReportService) ReadThumbnail(ctx context.Context, attachment *repository.ReportAttachment) (io.ReadCloser, error) {
+	if err := downloadable(attachment); err != nil {
+		return nil, err
+	}
+	key := thumbnailKey(attachment.StoragePath)
+	cached, err := s.files.Open(ctx, key)
+	if !errors.Is(err, storage.ErrNotExist) {
+		return cached, err
+	}
+
+	file, err := s.files.Open(ctx, attachment.StoragePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	thumb, err := thumbnail.Generate(file, s.attachments.ThumbnailSize)
+	if err != nil {
+		return nil, err
+	}
+	// A failed write only means the next request generates it again
+	_ = s.files.Put(ctx, key, bytes.NewReader(thumb), thumbnail.ContentType)
+	return io.NopCloser(bytes.NewReader(thumb)), nil
+}
+
+// thumbnailKey is where the thumbnail of the file under key is kept:
+// <tenant ID>/thumbs/<name>.jpg
+func thumbnailKey(key string) string {
+	dir, name := path.Split(key)
+	return dir + "thumbs/" + strings.TrimSuffix(name, path.Ext(name)) + ".jpg"
+}
+
+// downloadable refuses files that are waiting for or failed the virus scan
+func downloadable(attachment *repository.ReportAttachment) error {
+	switch attachment.ScanStatus {
+	case repository.ScanPending:
+		return ErrAttachmentScanPending
+	case repository.ScanQuarantined:
+		return ErrAttachmentQuarantined
+	}
+	return nil
+}
+
+// FileURL is where a file can be downloaded
+type FileURL struct {
+	URL       string     `json:"url"`
+	Presigned bool       `json:"presigned"`            // Downloaded straight from storage, without credentials
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // Of a presigned URL
+}
+
+// AttachmentURL returns a short-lived presigned URL of an attachment's file.
+// Stores that cannot presign get the API route streaming the file, which
+// needs the caller's credentials.
+func (s *ReportService) AttachmentURL(ctx context.Context, attachmentID uint, tenantID uint) (*FileURL, error) {
+	attachment, err := s.GetAttachment(ctx, attachmentID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := downloadable(attachment); err != nil {
+		return nil, err
+	}
+
+	presigner, ok := s.files.(storage.Presigner)
+	if !ok {
+		return &FileURL{URL: fmt.Sprintf("/api/v1/files/%d", attachment.ID)}, nil
+	}
+	expiresAt := time.Now().Add(s.attachments.URLTTL)
+	url, err := presigner.PresignGet(ctx, attachment.StoragePath, s.attachments.URLTTL, storage.Download{
+		FileName:    attachment.FileName,
+		ContentType: attachment.ContentType,
+		Inline:      true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &FileURL{URL: url, Presigned: true, ExpiresAt: &expiresAt}, nil
+}
+
+// DeleteAttachment removes an attachment and its file, with the same
+// permissions as adding one
+func (s *ReportService) DeleteAttachment(ctx context.Context, reportID uint, attachmentID uint, tenantID uint, userID uint, permission int) error {
+	if _, err := s.attachmentReport(ctx, reportID, tenantID, userID, permission); err != nil {
+		return err
+	}
+	attachment, err := s.OpenAttachment(ctx, reportID, attachmentID, tenantID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteReportAttachment(ctx, attachment.ID); err != nil {
+		return err
+	}
+	if err := s.files.Delete(ctx, attachment.StoragePath); err != nil {
+		return fmt.Errorf("attachment deleted but its file was not removed: %w", err)
+	}
+	if err := s.files.Delete(ctx, thumbnailKey(attachment.StoragePath)); err != nil {
+		return fmt.Errorf("attachment deleted but its thumbnail was not removed: %w", err)
+	}
+	return nil
+}
+
+// attachmentReport loads a report whose attachments the user may change
+func (s *ReportService) attachmentReport(ctx context.Context, reportID uint, tenantID uint, userID uint, permission int) (*repository.WeeklyReport, error) {
+	report, err := s.GetByID(ctx, reportID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if report.DriverID != userID && !permissions.HasPermission(permission, permissions.PermissionEditReports) {
+		return nil, forbidden("you can only change attachments of your own reports")
+	}
+	if report.Status == "approved" {
+		return nil, conflict("cannot change attachments of approved reports")
+	}
+	if err := ensurePeriodOpen(ctx, s.repo, tenantID, report.WeekStartDate); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// AttachmentMaxSize is the largest file AddAttachment accepts, in bytes
+func (s *ReportService) AttachmentMaxSize() int64 {
+	return s.attachments.MaxSize
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"taxifleet/backend/internal/permissions"
+	"taxifleet/backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// ErrReportNotApproved is returned for the receipt of a report that is not
+// approved yet
+var ErrReportNotApproved = errors.New("only approved reports have a receipt")
+
+// ReportReceipt is what the receipt of an approved report shows
+type ReportReceipt struct {
+	Report    *repository.WeeklyReport
+	Number    string                      // R-<year>-<report ID>
+	Signature *repository.ReportSignature // The approver's sign-off; nil when the approval wasn't signed off
+
+	// Reference is a checksum of the settled figures, so a receipt can be
+	// checked against the report; it is not a cryptographic signature
+	Reference string
+}
+
+// Receipt returns the receipt of an approved report, which drivers keep as
+// proof of settlement. Drivers only get the receipts of their own reports.
+func (s *ReportService) Receipt(ctx context.Context, id uint, tenantID uint, userID uint, permission int) (*ReportReceipt, error) {
+	report, err := s.GetByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if permission == permissions.PermissionDriver && report.DriverID != userID {
+		return nil, notFound("report not found")
+	}
+	if report.Status != "approved" || report.ApprovedAt == nil {
+		return nil, ErrReportNotApproved
+	}
+
+	receipt := &ReportReceipt{
+		Report:    report,
+		Number:    fmt.Sprintf("R-%d-%06d", report.WeekStartDate.Year(), report.ID),
+		Reference: receiptReference(report),
+	}
+	signature, err := s.repo.GetReportSignature(ctx, report.ID)
+	switch {
+	case err == nil:
+		receipt.Signature = signature
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+	return receipt, nil
+}
+
+// receiptReference checksums what the receipt settles, as groups of four hex
+// digits
+func receiptReference(report *repository.WeeklyReport) string {
+	settled := fmt.Sprintf("%d|%d|%d|%s|%s|%s|%s|%s", report.TenantID, report.ID, report.DriverID,
+		report.WeekStartDate.Format("2006-01-02"), report.Earnings, report.TotalExpenses, report.TotalAdjustments,
+		report.ApprovedAt.UTC().Format(time.RFC3339))
+	sum := sha256.Sum256([]byte(settled))
+	digits := strings.ToUpper(hex.EncodeToString(sum[:8]))
+	return digits[0:4] + "-" + digits[4:8] + "-" + digits[8:12] + "-" + digits[12:16]
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/validation"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// maxSignatureSize bounds a drawn signature image
+const maxSignatureSize = 256 << 10
+
+// signatureTypes are the image types a drawn signature may have, with the
+// extension PDF rendering needs
+var signatureTypes = map[string]string{
+	"image/png":  "png",
+	"image/jpeg": "jpg",
+}
+
+// ErrWrongPIN is returned when an approval is signed off with a wrong PIN
+var ErrWrongPIN = errors.New("wrong approval PIN")
+
+// ApproveReportRequest signs off an approval, with the approver's PIN or a
+// drawn signature. Both are optional unless the tenant requires a sign-off.
+type ApproveReportRequest struct {
+	PIN       string `json:"pin"`
+	Signature string `json:"signature" binding:"max=350000" sanitize:"-"` // data:image/png;base64,... or image/jpeg, of up to 256 KB
+}
+
+// signOff checks the sign-off of an approval and returns the signature to
+// store with the report, or nil when there is none
+func (s *ReportService) signOff(ctx context.Context, report *repository.WeeklyReport, approvedByID uint, settings TenantSettings, req ApproveReportRequest) (*repository.ReportSignature, error) {
+	signature := &repository.ReportSignature{TenantID: report.TenantID, ReportID: report.ID, SignedByID: approvedByID, SignedAt: time.Now()}
+	switch {
+	case req.PIN != "" && req.Signature != "":
+		return nil, &validation.FieldError{Field: "signature", Rule: "sign_off", Message: "sign off with either a PIN or a signature, not both"}
+	case req.PIN != "":
+		approver, err := s.repo.GetUserByID(ctx, approvedByID)
+		if err != nil {
+			return nil, err
+		}
+		if approver.PINHash == "" {
+			return nil, &validation.FieldError{Field: "pin", Rule: "pin_set", Message: "set an approval PIN in your profile first"}
+		}
+		if bcrypt.CompareHashAndPassword([]byte(approver.PINHash), []byte(req.PIN)) != nil {
+			return nil, ErrWrongPIN
+		}
+		signature.Method = "pin"
+	case req.Signature != "":
+		image, contentType, err := decodeSignature(req.Signature)
+		if err != nil {
+			return nil, err
+		}
+		signature.Method = "drawn"
+		signature.Image = image
+		signature.ContentType = contentType
+	case settings.RequireApprovalSignature:
+		return nil, &validation.FieldError{Field: "signature", Rule: "required", Message: "approvals must be signed off with a PIN or a signature"}
+	default:
+		return nil, nil
+	}
+	return signature, nil
+}
+
+// decodeSignature reads a drawn signature from a base64 data URI
+func decodeSignature(uri string) ([]byte, string, error) {
+	invalid := &validation.FieldError{Field: "signature", Rule: "signature", Message: "signature must be a base64 PNG or JPEG data URI of at most 256 KB"}
+	if !strings.HasPrefix(uri, "data:") {
+		return nil, "", invalid
+	}
+	_, encoded, ok := strings.Cut(uri, ";base64,")
+	if !ok || base64.StdEncoding.DecodedLen(len(encoded)) > maxSignatureSize+2 {
+		return nil, "", invalid
+	}
+	image, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(image) > maxSignatureSize {
+		return nil, "", invalid
+	}
+	contentType := http.DetectContentType(image)
+	if _, ok := signatureTypes[contentType]; !ok {
+		return nil, "", invalid
+	}
+	return image, contentType, nil
+}
+
+// SignatureImageType is the PDF image type of a drawn signature
+func SignatureImageType(contentType string) string {
+	return signatureTypes[contentType]
+}
+
+// checkApprovalPIN validates a new approval PIN: 4 to 8 digits
+func checkApprovalPIN(pin string) error {
+	if len(pin) < 4 || len(pin) > 8 || strings.Trim(pin, "0123456789") != "" {
+		return &validation.FieldError{Field: "approval_pin", Rule: "pin", Message: "approval PIN must be 4 to 8 digits"}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"taxifleet/backend/internal/validation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// bindJSON binds body into obj like a handler does, with the default text
+// length cap of the sanitizer
+func bindJSON(t *testing.T, body any, obj any) error {
+	t.Helper()
+	previous := binding.Validator
+	validation.SanitizeWithGin(validation.Sanitizer{MaxLength: 2000})
+	t.Cleanup(func() { binding.Validator = previous })
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("failed to encode the body: %v", err)
+	}
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(data))
+	c.Request.Header.Set("Content-Type", "application/json")
+	return c.ShouldBindJSON(obj)
+}
+
+// drawnSignature returns a noisy PNG of about 200 KB as a data URI, as large
+// as the signature pads of the apps send
+func drawnSignature(t *testing.T) string {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, 300, 170))
+	random := rand.New(rand.NewSource(1))
+	for y := 0; y < 170; y++ {
+		for x := 0; x < 300; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(random.Intn(256)), G: uint8(random.Intn(256)), B: uint8(random.Intn(256)), A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("failed to encode the signature: %v", err)
+	}
+	if buf.Len() > maxSignatureSize {
+		t.Fatalf("signature of %d bytes is over the limit", buf.Len())
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func TestApproveReportRequestBindsDrawnSignature(t *testing.T) {
+	signature := drawnSignature(t)
+
+	var req ApproveReportRequest
+	if err := bindJSON(t, map[string]string{"signature": signature}, &req); err != nil {
+		t.Fatalf("expected the signature of %d characters bound, got %v", len(signature), err)
+	}
+	if req.Signature != signature {
+		t.Fatal("expected the signature kept as sent")
+	}
+	if _, contentType, err := decodeSignature(req.Signature); err != nil || contentType != "image/png" {
+		t.Fatalf("expected the bound signature to decode, got %q, %v", contentType, err)
+	}
+
+	tooLong := "data:image/png;base64," + strings.Repeat("A", 350000)
+	if err := bindJSON(t, map[string]string{"signature": tooLong}, &req); err == nil {
+		t.Fatal("expected a signature over the limit refused")
+	}
+}
+
+func TestTenantRequestsBindInlineLogo(t *testing.T) {
+	// A data URI of a logo close to the 1 MB limit
+	logo := "data:image/png;base64," + base64.StdEncoding.EncodeToString(make([]byte, 1000<<10))
+
+	var create CreateTenantRequest
+	if err := bindJSON(t, map[string]string{"name": "Fleet", "subdomain": "fleet", "logo": logo}, &create); err != nil || create.Logo != logo {
+		t.Fatalf("expected the logo bound on create, got %v", err)
+	}
+	var update UpdateTenantRequest
+	if err := bindJSON(t, map[string]string{"logo": logo}, &update); err != nil || update.Logo != logo {
+		t.Fatalf("expected the logo bound on update, got %v", err)
+	}
+}
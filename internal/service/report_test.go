@@ -0,0 +1,567 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"taxifleet/backend/internal/antivirus"
+	"taxifleet/backend/internal/cache"
+	"taxifleet/backend/internal/config"
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/permissions"
+	"taxifleet/backend/internal/push"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+	"taxifleet/backend/internal/storage"
+	"taxifleet/backend/internal/validation"
+
+	"github.com/sirupsen/logrus"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+type reportRepoMock struct {
+	*mocks.MockTransactor
+	*mocks.MockReportRepo
+	*mocks.MockReportAttachmentRepo
+	*mocks.MockReportAdjustmentRepo
+	*mocks.MockReportRejectionRepo
+	*mocks.MockReportSignatureRepo
+	*mocks.MockUserRepo
+	*mocks.MockExpenseRepo
+	*mocks.MockTaxiRepo
+	*mocks.MockTenantRepo
+	*mocks.MockDriverLedgerRepo
+	*mocks.MockDelegationRepo
+	*mocks.MockPeriodRepo
+	*mocks.MockAuditRepo
+	*mocks.MockPolicyRepo
+}
+
+func newReportServiceMock(t *testing.T) (*ReportService, reportRepoMock) {
+	ctrl := gomock.NewController(t)
+	repo := reportRepoMock{
+		MockTransactor:           mocks.NewMockTransactor(ctrl),
+		MockReportRepo:           mocks.NewMockReportRepo(ctrl),
+		MockReportAttachmentRepo: mocks.NewMockReportAttachmentRepo(ctrl),
+		MockReportAdjustmentRepo: mocks.NewMockReportAdjustmentRepo(ctrl),
+		MockReportRejectionRepo:  mocks.NewMockReportRejectionRepo(ctrl),
+		MockReportSignatureRepo:  mocks.NewMockReportSignatureRepo(ctrl),
+		MockUserRepo:             mocks.NewMockUserRepo(ctrl),
+		MockExpenseRepo:          mocks.NewMockExpenseRepo(ctrl),
+		MockTaxiRepo:             mocks.NewMockTaxiRepo(ctrl),
+		MockTenantRepo:           mocks.NewMockTenantRepo(ctrl),
+		MockDriverLedgerRepo:     mocks.NewMockDriverLedgerRepo(ctrl),
+		MockDelegationRepo:       mocks.NewMockDelegationRepo(ctrl),
+		MockPeriodRepo:           mocks.NewMockPeriodRepo(ctrl),
+		MockAuditRepo:            mocks.NewMockAuditRepo(ctrl),
+		MockPolicyRepo:           mocks.NewMockPolicyRepo(ctrl),
+	}
+	repo.MockPeriodRepo.EXPECT().IsPeriodClosed(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+	attachments := config.AttachmentConfig{MaxSize: 1 << 10, ThumbnailSize: 320}
+	return NewReportService(repo, cache.Noop{}, nil, NewPolicyService(repo, cache.Noop{}), storage.NewLocal(t.TempDir()), nil, attachments), repo
+}
+
+func TestStartOfWeek(t *testing.T) {
+	wednesday := time.Date(2024, 5, 15, 13, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		weekStart time.Weekday
+		want      string
+	}{
+		{time.Monday, "2024-05-13"},
+		{time.Sunday, "2024-05-12"},
+		{time.Wednesday, "2024-05-15"},
+		{time.Thursday, "2024-05-09"},
+	}
+	for _, tt := range tests {
+		if got := startOfWeek(wednesday, tt.weekStart).Format("2006-01-02"); got != tt.want {
+			t.Errorf("startOfWeek(%s) = %s, want %s", tt.weekStart, got, tt.want)
+		}
+	}
+}
+
+func TestReportCreateSnapsToTenantWeekStart(t *testing.T) {
+	svc, repo := newReportServiceMock(t)
+
+	repo.MockTaxiRepo.EXPECT().GetTaxiByID(gomock.Any(), uint(3)).Return(&repository.Taxi{ID: 3, TenantID: 1}, nil)
+	repo.MockTenantRepo.EXPECT().GetTenantByID(gomock.Any(), uint(1)).Return(&repository.Tenant{ID: 1, Settings: `{"week_start_day":"sunday"}`}, nil)
+	repo.MockReportRepo.EXPECT().ReportExistsForWeek(gomock.Any(), uint(1), uint(3), uint(9), gomock.Any(), uint(0)).Return(false, nil)
+	repo.MockReportRepo.EXPECT().CreateReport(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, report *repository.WeeklyReport) error {
+		if got := report.WeekStartDate.Format("2006-01-02"); got != "2024-05-12" {
+			t.Errorf("expected week start 2024-05-12, got %s", got)
+		}
+		if report.Status != "draft" {
+			t.Errorf("expected draft status, got %q", report.Status)
+		}
+		report.ID = 42
+		return nil
+	})
+	repo.MockReportRepo.EXPECT().GetReportByID(gomock.Any(), uint(42)).Return(&repository.WeeklyReport{ID: 42}, nil)
+
+	_, err := svc.Create(context.Background(), 1, 9, CreateReportRequest{
+		TaxiID:        3,
+		WeekStartDate: time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC),
+		Earnings:      500,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestReportCreateRejectsDuplicateWeek(t *testing.T) {
+	svc, repo := newReportServiceMock(t)
+
+	repo.MockTaxiRepo.EXPECT().GetTaxiByID(gomock.Any(), uint(3)).Return(&repository.Taxi{ID: 3, TenantID: 1}, nil)
+	repo.MockTenantRepo.EXPECT().GetTenantByID(gomock.Any(), uint(1)).Return(&repository.Tenant{ID: 1, Settings: "{}"}, nil)
+	repo.MockReportRepo.EXPECT().ReportExistsForWeek(gomock.Any(), uint(1), uint(3), uint(9), gomock.Any(), uint(0)).Return(true, nil)
+
+	_, err := svc.Create(context.Background(), 1, 9, CreateReportRequest{TaxiID: 3, WeekStartDate: time.Now(), Earnings: 500_00})
+	if err != ErrDuplicateReport {
+		t.Fatalf("expected ErrDuplicateReport, got %v", err)
+	}
+}
+
+func TestReportUpdateSetsZeroAndKeepsAbsentFields(t *testing.T) {
+	svc, repo := newReportServiceMock(t)
+	week := time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC)
+	report := &repository.WeeklyReport{ID: 42, TenantID: 1, DriverID: 9, Status: "draft", WeekStartDate: week, Earnings: 500_00, Notes: "Rainy week"}
+	repo.MockReportRepo.EXPECT().GetReportByID(gomock.Any(), uint(42)).Return(report, nil).Times(4)
+	repo.MockExpenseRepo.EXPECT().GetExpensesByReport(gomock.Any(), uint(42)).Return(nil, nil).Times(2)
+	repo.MockReportRepo.EXPECT().UpdateReport(gomock.Any(), report).Return(nil).Times(2)
+
+	notes := "Two days off"
+	if _, err := svc.Update(context.Background(), 42, 1, 9, permissions.PermissionDriver, UpdateReportRequest{Notes: &notes}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Earnings != 500_00 || report.Notes != "Two days off" {
+		t.Fatalf("expected only the notes to change, got %+v", report)
+	}
+
+	earnings, cleared := money.Amount(0), ""
+	if _, err := svc.Update(context.Background(), 42, 1, 9, permissions.PermissionDriver, UpdateReportRequest{Earnings: &earnings, Notes: &cleared}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Earnings != 0 || report.Notes != "" {
+		t.Fatalf("expected zero earnings and no notes, got %+v", report)
+	}
+}
+
+func TestReportSubmitOnlyByItsDriver(t *testing.T) {
+	svc, repo := newReportServiceMock(t)
+	repo.MockReportRepo.EXPECT().GetReportByID(gomock.Any(), uint(42)).Return(&repository.WeeklyReport{ID: 42, TenantID: 1, DriverID: 9, Status: "draft"}, nil)
+
+	if _, err := svc.Submit(context.Background(), 42, 1, 10); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected another driver to be forbidden, got %v", err)
+	}
+}
+
+func TestReportRejectRequiresSubmitted(t *testing.T) {
+	svc, repo := newReportServiceMock(t)
+	repo.MockReportRepo.EXPECT().GetReportByID(gomock.Any(), uint(42)).Return(&repository.WeeklyReport{ID: 42, TenantID: 1, Status: "draft"}, nil)
+
+	if _, err := svc.Reject(context.Background(), 42, 1, 3, RejectReportRequest{}); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected a draft report not to be rejectable, got %v", err)
+	}
+}
+
+// pngHeader is enough of a PNG file for its content type to be detected
+var pngHeader = []byte("\x89PNG\r\n\x1a\n")
+
+func TestReportAddAttachmentStoresImage(t *testing.T) {
+	svc, repo := newReportServiceMock(t)
+	repo.MockReportRepo.EXPECT().GetReportByID(gomock.Any(), uint(42)).Return(&repository.WeeklyReport{ID: 42, TenantID: 1, DriverID: 9, Status: "draft"}, nil)
+	repo.MockReportAttachmentRepo.EXPECT().CreateReportAttachment(gomock.Any(), gomock.Any()).Return(nil)
+
+	attachment, err := svc.AddAttachment(context.Background(), 42, 1, 9, 0, "cash.png", bytes.NewReader(append(pngHeader, "rest"...)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attachment.ContentType != "image/png" || attachment.Size != int64(len(pngHeader)+4) || !strings.HasSuffix(attachment.StoragePath, ".png") {
+		t.Fatalf("unexpected attachment %+v", attachment)
+	}
+}
+
+func TestReportAddAttachmentRejectsInvalidFiles(t *testing.T) {
+	tests := []struct {
+		name string
+		file []byte
+		want error
+	}{
+		{"not an image", []byte("%PDF-1.7"), ErrAttachmentType},
+		{"too large", append(pngHeader, make([]byte, 1<<10)...), ErrAttachmentTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo := newReportServiceMock(t)
+			repo.MockReportRepo.EXPECT().GetReportByID(gomock.Any(), uint(42)).Return(&repository.WeeklyReport{ID: 42, TenantID: 1, DriverID: 9, Status: "draft"}, nil)
+
+			if _, err := svc.AddAttachment(context.Background(), 42, 1, 9, 0, "file", bytes.NewReader(tt.file)); !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+// fakeScanner returns the same verdict or error for every file
+type fakeScanner struct {
+	verdict antivirus.Verdict
+	err     error
+}
+
+func (f fakeScanner) Scan(ctx context.Context, file io.Reader) (antivirus.Verdict, error) {
+	return f.verdict, f.err
+}
+
+func TestReportAddAttachmentQuarantinesInfectedFiles(t *testing.T) {
+	svc, repo := newReportServiceMock(t)
+	svc.scanner = fakeScanner{verdict: antivirus.Verdict{Infected: true, Threat: "Eicar-Test-Signature"}}
+	ctrl := gomock.NewController(t)
+	notificationRepo := notificationRepoMock{MockDeviceTokenRepo: mocks.NewMockDeviceTokenRepo(ctrl), MockInboxRepo: mocks.NewMockInboxRepo(ctrl)}
+	svc.notifications = NewNotificationService(notificationRepo, push.NoopSender{}, logrus.New())
+
+	repo.MockReportRepo.EXPECT().GetReportByID(gomock.Any(), uint(42)).Return(&repository.WeeklyReport{ID: 42, TenantID: 1, DriverID: 9, Status: "draft"}, nil)
+	repo.MockReportAttachmentRepo.EXPECT().CreateReportAttachment(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, attachment *repository.ReportAttachment) error {
+		if attachment.ScanStatus != repository.ScanPending {
+			t.Errorf("expected the attachment to be stored pending, got %s", attachment.ScanStatus)
+		}
+		return nil
+	})
+	repo.MockReportAttachmentRepo.EXPECT().UpdateReportAttachmentScan(gomock.Any(), gomock.Any()).Return(nil)
+	notified := make(chan *repository.Notification, 1)
+	notificationRepo.MockInboxRepo.EXPECT().CreateNotification(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, notification *repository.Notification) error {
+		notified <- notification
+		return nil
+	})
+	notificationRepo.MockDeviceTokenRepo.EXPECT().GetDeviceTokensByUser(gomock.Any(), uint(9)).Return(nil, nil)
+
+	attachment, err := svc.AddAttachment(context.Background(), 42, 1, 9, 0, "cash.png", bytes.NewReader(pngHeader))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attachment.ScanStatus != repository.ScanQuarantined || attachment.ScanThreat != "Eicar-Test-Signature" {
+		t.Fatalf("expected the attachment to be quarantined, got %+v", attachment)
+	}
+	if _, err := svc.ReadAttachment(context.Background(), attachment); !errors.Is(err, ErrAttachmentQuarantined) {
+		t.Fatalf("expected a quarantined file not to be downloadable, got %v", err)
+	}
+
+	select {
+	case notification := <-notified:
+		if notification.UserID != 9 || notification.Type != "attachment_quarantined" {
+			t.Fatalf("expected the uploader to be notified, got %+v", notification)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the uploader to be notified")
+	}
+}
+
+func TestReportAddAttachmentStaysPendingWhenScanFails(t *testing.T) {
+	svc, repo := newReportServiceMock(t)
+	svc.scanner = fakeScanner{err: errors.New("connection refused")}
+	repo.MockReportRepo.EXPECT().GetReportByID(gomock.Any(), uint(42)).Return(&repository.WeeklyReport{ID: 42, TenantID: 1, DriverID: 9, Status: "draft"}, nil)
+	repo.MockReportAttachmentRepo.EXPECT().CreateReportAttachment(gomock.Any(), gomock.Any()).Return(nil)
+
+	attachment, err := svc.AddAttachment(context.Background(), 42, 1, 9, 0, "cash.png", bytes.NewReader(pngHeader))
+	if err != nil {
+		t.Fatalf("expected the upload to succeed, got %v", err)
+	}
+	if _, err := svc.ReadAttachment(context.Background(), attachment); !errors.Is(err, ErrAttachmentScanPending) {
+		t.Fatalf("expected an unscanned file not to be downloadable, got %v", err)
+	}
+}
+
+func TestReportReadThumbnailCachesIt(t *testing.T) {
+	svc, _ := newReportServiceMock(t)
+	var photo bytes.Buffer
+	if err := png.Encode(&photo, image.NewNRGBA(image.Rect(0, 0, 1200, 900))); err != nil {
+		t.Fatalf("failed to encode test image: %v", err)
+	}
+	if err := svc.files.Put(context.Background(), "1/photo.png", &photo, "image/png"); err != nil {
+		t.Fatalf("failed to store test image: %v", err)
+	}
+	attachment := &repository.ReportAttachment{ID: 7, TenantID: 1, StoragePath: "1/photo.png", ScanStatus: repository.ScanNotScanned}
+
+	thumb, err := svc.ReadThumbnail(context.Background(), attachment)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer thumb.Close()
+	config, err := jpeg.DecodeConfig(thumb)
+	if err != nil || config.Width != 320 || config.Height != 240 {
+		t.Fatalf("expected a 320x240 JPEG, got %+v, %v", config, err)
+	}
+
+	cached, err := svc.files.Open(context.Background(), "1/thumbs/photo.jpg")
+	if err != nil {
+		t.Fatalf("expected the thumbnail to be kept in storage, got %v", err)
+	}
+	cached.Close()
+}
+
+func TestReportAttachmentURLFallsBackToStreaming(t *testing.T) {
+	svc, repo := newReportServiceMock(t)
+	repo.MockReportAttachmentRepo.EXPECT().GetReportAttachmentByID(gomock.Any(), uint(7)).Return(&repository.ReportAttachment{ID: 7, TenantID: 1, StoragePath: "1/photo.png"}, nil).Times(2)
+
+	url, err := svc.AttachmentURL(context.Background(), 7, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if url.URL != "/api/v1/files/7" || url.Presigned || url.ExpiresAt != nil {
+		t.Fatalf("expected the streaming route for local storage, got %+v", url)
+	}
+
+	if _, err := svc.AttachmentURL(context.Background(), 7, 2); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected another tenant's file not to be found, got %v", err)
+	}
+}
+
+func TestSplitEarnings(t *testing.T) {
+	tests := []struct {
+		name        string
+		split       repository.EarningsSplit
+		earnings    money.Amount
+		expenses    money.Amount
+		adjustments money.Amount
+		driver      money.Amount
+		owner       money.Amount
+	}{
+		{"percentage of net earnings", repository.EarningsSplit{Type: "percentage", DriverPercent: 60}, 1000_00, 100_00, 0, 540_00, 360_00},
+		{"rounded to cents", repository.EarningsSplit{Type: "percentage", DriverPercent: 33.333}, 100_00, 0, 0, 33_33, 66_67},
+		{"daily rental for the week", repository.EarningsSplit{Type: "daily_rental", DailyRental: 50_00}, 1000_00, 100_00, 0, 550_00, 350_00},
+		{"rental above net earnings", repository.EarningsSplit{Type: "daily_rental", DailyRental: 50_00}, 300_00, 0, 0, -50_00, 350_00},
+		{"bonus paid by the owner", repository.EarningsSplit{Type: "percentage", DriverPercent: 50}, 1000_00, 0, 25_00, 525_00, 475_00},
+		{"penalty paid by the driver", repository.EarningsSplit{Type: "percentage", DriverPercent: 50}, 1000_00, 0, -40_00, 460_00, 540_00},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			driver, owner := splitEarnings(&tt.split, tt.earnings, tt.expenses, tt.adjustments)
+			if driver != tt.driver || owner != tt.owner {
+				t.Fatalf("expected driver %v and owner %v, got %v and %v", tt.driver, tt.owner, driver, owner)
+			}
+		})
+	}
+}
+
+func TestEarningsSplitForPrefersTaxiRule(t *testing.T) {
+	tenantRule := &repository.EarningsSplit{Type: "percentage", DriverPercent: 50}
+	taxiRule := &repository.EarningsSplit{Type: "daily_rental", DailyRental: 40_00}
+	settings := TenantSettings{EarningsSplit: tenantRule}
+
+	if got := earningsSplitFor(&repository.Taxi{EarningsSplit: taxiRule}, settings); got != taxiRule {
+		t.Fatalf("expected the taxi's rule, got %+v", got)
+	}
+	if got := earningsSplitFor(&repository.Taxi{}, settings); got != tenantRule {
+		t.Fatalf("expected the tenant's rule, got %+v", got)
+	}
+}
+
+func TestReportAddAdjustmentRefusedOnceApproved(t *testing.T) {
+	svc, repo := newReportServiceMock(t)
+	repo.MockReportRepo.EXPECT().GetReportByID(gomock.Any(), uint(42)).Return(&repository.WeeklyReport{ID: 42, TenantID: 1, Status: "approved"}, nil)
+
+	req := AddAdjustmentRequest{Type: "bonus", Amount: 20_00, Reason: "Clean record"}
+	if _, err := svc.AddAdjustment(context.Background(), 42, 1, 2, permissions.PermissionOwner, req); err == nil {
+		t.Fatal("expected an approved report not to be adjustable")
+	}
+	if _, err := svc.AddAdjustment(context.Background(), 42, 1, 9, permissions.PermissionDriver, req); err == nil {
+		t.Fatal("expected a driver not to be allowed to adjust reports")
+	}
+}
+
+func TestReportPagesWithKeyset(t *testing.T) {
+	svc, repo := newReportServiceMock(t)
+	week := time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC)
+
+	repo.MockReportRepo.EXPECT().GetReportsPage(gomock.Any(), uint(1), uint(0), nil, 3).Return([]repository.WeeklyReport{
+		{ID: 9, WeekStartDate: week}, {ID: 8, WeekStartDate: week}, {ID: 12, WeekStartDate: week.AddDate(0, 0, -7)},
+	}, nil)
+	page, err := svc.Page(context.Background(), 1, 2, permissions.PermissionOwner, "", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Reports) != 2 || page.NextCursor == "" {
+		t.Fatalf("expected 2 reports and a next cursor, got %+v", page)
+	}
+
+	// Drivers page through their own reports only
+	repo.MockReportRepo.EXPECT().GetReportsPage(gomock.Any(), uint(1), uint(2), &repository.Keyset{Date: week, ID: 8}, 3).Return([]repository.WeeklyReport{
+		{ID: 12, WeekStartDate: week.AddDate(0, 0, -7)},
+	}, nil)
+	page, err = svc.Page(context.Background(), 1, 2, permissions.PermissionDriver, page.NextCursor, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Reports) != 1 || page.NextCursor != "" {
+		t.Fatalf("expected the last page, got %+v", page)
+	}
+}
+
+func TestReportCountAndSummaryScopeDrivers(t *testing.T) {
+	svc, repo := newReportServiceMock(t)
+
+	repo.MockReportRepo.EXPECT().CountReports(gomock.Any(), uint(1), uint(4), "submitted").Return(int64(2), nil)
+	if count, err := svc.Count(context.Background(), 1, 4, permissions.PermissionDriver, "submitted"); err != nil || count != 2 {
+		t.Fatalf("expected the driver's 2 reports, got %d, %v", count, err)
+	}
+
+	repo.MockReportRepo.EXPECT().SummarizeReports(gomock.Any(), uint(1), uint(0), "month").Return([]repository.GroupTotal{{Key: "2024-05", Count: 3, Total: 4500_00}}, nil)
+	if _, err := svc.Summary(context.Background(), 1, 4, permissions.PermissionOwner, "month"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var fieldErr *validation.FieldError
+	if _, err := svc.Summary(context.Background(), 1, 4, permissions.PermissionOwner, "earnings"); !errors.As(err, &fieldErr) || fieldErr.Field != "group_by" {
+		t.Fatalf("expected a group_by field error, got %v", err)
+	}
+}
+
+func TestReportArchiveOlderThanMonths(t *testing.T) {
+	svc, repo := newReportServiceMock(t)
+
+	var fieldErr *validation.FieldError
+	if _, err := svc.Archive(context.Background(), 1, ArchiveRequest{}); !errors.As(err, &fieldErr) || fieldErr.Field != "older_than_months" {
+		t.Fatalf("expected an older_than_months field error, got %v", err)
+	}
+
+	var cutoff time.Time
+	repo.MockReportRepo.EXPECT().ArchiveReports(gomock.Any(), uint(1), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uint, before time.Time) (int64, error) {
+			cutoff = before
+			return 7, nil
+		})
+	archived, err := svc.Archive(context.Background(), 1, ArchiveRequest{OlderThanMonths: 6})
+	if err != nil || archived != 7 {
+		t.Fatalf("expected 7 archived reports, got %d, %v", archived, err)
+	}
+	if want := time.Now().UTC().AddDate(0, -6, 0); cutoff.After(want) || want.Sub(cutoff) > 24*time.Hour {
+		t.Fatalf("expected a cutoff six months back, got %s", cutoff)
+	}
+
+	if _, err := svc.ListArchived(context.Background(), 1, 4, permissions.PermissionDriver, ListOptions{Query: "fuel"}); !errors.As(err, &fieldErr) || fieldErr.Field != "q" {
+		t.Fatalf("expected a q field error, got %v", err)
+	}
+	repo.MockReportRepo.EXPECT().GetArchivedReports(gomock.Any(), uint(1), uint(4)).Return([]repository.WeeklyReport{{ID: 2}}, nil)
+	if reports, err := svc.ListArchived(context.Background(), 1, 4, permissions.PermissionDriver, ListOptions{}); err != nil || len(reports) != 1 {
+		t.Fatalf("expected the driver's archived report, got %v, %v", reports, err)
+	}
+}
+
+func TestReportReceiptOnlyForApprovedReports(t *testing.T) {
+	svc, repo := newReportServiceMock(t)
+	approvedAt := time.Date(2024, 5, 21, 9, 30, 0, 0, time.UTC)
+	repo.MockReportRepo.EXPECT().GetReportByID(gomock.Any(), uint(2)).Return(&repository.WeeklyReport{ID: 2, TenantID: 1, DriverID: 4, Status: "submitted"}, nil)
+	repo.MockReportRepo.EXPECT().GetReportByID(gomock.Any(), uint(3)).Return(&repository.WeeklyReport{
+		ID: 3, TenantID: 1, DriverID: 4, Status: "approved", ApprovedAt: &approvedAt,
+		WeekStartDate: time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC), Earnings: 1500_00,
+	}, nil).Times(2)
+	repo.MockReportSignatureRepo.EXPECT().GetReportSignature(gomock.Any(), uint(3)).Return(nil, gorm.ErrRecordNotFound)
+
+	if _, err := svc.Receipt(context.Background(), 2, 1, 4, permissions.PermissionDriver); !errors.Is(err, ErrReportNotApproved) {
+		t.Fatalf("expected ErrReportNotApproved, got %v", err)
+	}
+	if _, err := svc.Receipt(context.Background(), 3, 1, 5, permissions.PermissionDriver); err == nil {
+		t.Fatal("expected another driver's receipt to be refused")
+	}
+	receipt, err := svc.Receipt(context.Background(), 3, 1, 9, permissions.PermissionOwner)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if receipt.Number != "R-2024-000003" || len(receipt.Reference) != 19 || receipt.Signature != nil {
+		t.Fatalf("unexpected receipt %s / %s", receipt.Number, receipt.Reference)
+	}
+}
+
+func TestReportSignOff(t *testing.T) {
+	svc, repo := newReportServiceMock(t)
+	report := &repository.WeeklyReport{ID: 3, TenantID: 1}
+	pinHash, _ := bcrypt.GenerateFromPassword([]byte("4821"), bcrypt.MinCost)
+	repo.MockUserRepo.EXPECT().GetUserByID(gomock.Any(), uint(9)).Return(&repository.User{ID: 9, PINHash: string(pinHash)}, nil).Times(2)
+
+	var fieldErr *validation.FieldError
+	if _, err := svc.signOff(context.Background(), report, 9, TenantSettings{RequireApprovalSignature: true}, ApproveReportRequest{}); !errors.As(err, &fieldErr) {
+		t.Fatalf("expected the tenant to require a sign-off, got %v", err)
+	}
+	if signature, err := svc.signOff(context.Background(), report, 9, TenantSettings{}, ApproveReportRequest{}); err != nil || signature != nil {
+		t.Fatalf("expected no signature, got %v, %v", signature, err)
+	}
+
+	if _, err := svc.signOff(context.Background(), report, 9, TenantSettings{}, ApproveReportRequest{PIN: "1111"}); !errors.Is(err, ErrWrongPIN) {
+		t.Fatalf("expected ErrWrongPIN, got %v", err)
+	}
+	signature, err := svc.signOff(context.Background(), report, 9, TenantSettings{}, ApproveReportRequest{PIN: "4821"})
+	if err != nil || signature.Method != "pin" || signature.SignedByID != 9 {
+		t.Fatalf("expected a PIN signature, got %+v, %v", signature, err)
+	}
+
+	drawn := "data:image/png;base64," + base64.StdEncoding.EncodeToString([]byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"))
+	signature, err = svc.signOff(context.Background(), report, 9, TenantSettings{}, ApproveReportRequest{Signature: drawn})
+	if err != nil || signature.Method != "drawn" || signature.ContentType != "image/png" {
+		t.Fatalf("expected a drawn signature, got %+v, %v", signature, err)
+	}
+	if _, err := svc.signOff(context.Background(), report, 9, TenantSettings{}, ApproveReportRequest{Signature: "data:text/plain;base64,aGVsbG8="}); !errors.As(err, &fieldErr) {
+		t.Fatalf("expected a signature field error, got %v", err)
+	}
+}
+
+func TestReportApproveUnderDelegation(t *testing.T) {
+	svc, repo := newReportServiceMock(t)
+	repo.MockReportRepo.EXPECT().GetReportByID(gomock.Any(), uint(3)).Return(&repository.WeeklyReport{ID: 3, TenantID: 1, Status: "approved"}, nil).AnyTimes()
+	repo.MockPolicyRepo.EXPECT().GetPolicies(gomock.Any(), uint(1)).Return(nil, nil).AnyTimes()
+	repo.MockDelegationRepo.EXPECT().HasActiveDelegation(gomock.Any(), uint(1), uint(5), gomock.Any()).Return(false, nil)
+	repo.MockDelegationRepo.EXPECT().HasActiveDelegation(gomock.Any(), uint(1), uint(6), gomock.Any()).Return(true, nil)
+
+	if _, err := svc.Approve(context.Background(), 3, 1, 5, permissions.PermissionManager, ApproveReportRequest{}); err == nil || err.Error() != "only owner or admin can approve reports" {
+		t.Fatalf("expected a manager without a delegation to be refused, got %v", err)
+	}
+	// Past the permission check the already approved report is refused
+	if _, err := svc.Approve(context.Background(), 3, 1, 6, permissions.PermissionManager, ApproveReportRequest{}); err == nil || err.Error() != "report must be submitted before approval" {
+		t.Fatalf("expected the delegate to pass the permission check, got %v", err)
+	}
+	if _, err := svc.Approve(context.Background(), 3, 1, 9, permissions.PermissionOwner, ApproveReportRequest{}); err == nil || err.Error() != "report must be submitted before approval" {
+		t.Fatalf("expected the owner to pass the permission check, got %v", err)
+	}
+}
+
+func TestReportReopenRejected(t *testing.T) {
+	svc, repo := newReportServiceMock(t)
+	submittedAt := time.Now()
+	repo.MockReportRepo.EXPECT().GetReportByID(gomock.Any(), uint(2)).Return(&repository.WeeklyReport{ID: 2, TenantID: 1, DriverID: 4, Status: "submitted"}, nil)
+	repo.MockReportRepo.EXPECT().GetReportByID(gomock.Any(), uint(3)).DoAndReturn(func(context.Context, uint) (*repository.WeeklyReport, error) {
+		return &repository.WeeklyReport{
+			ID: 3, TenantID: 1, DriverID: 4, Status: "rejected", SubmittedAt: &submittedAt, Resubmissions: 1,
+			Rejections: []repository.ReportRejection{{ReportID: 3, Reason: "Missing fuel receipts"}},
+		}, nil
+	}).Times(3)
+
+	if _, err := svc.Reopen(context.Background(), 2, 1, 4, permissions.PermissionDriver); err == nil {
+		t.Fatal("expected a submitted report to be refused")
+	}
+	if _, err := svc.Reopen(context.Background(), 3, 1, 5, permissions.PermissionDriver); err == nil {
+		t.Fatal("expected another driver's report to be refused")
+	}
+
+	repo.MockReportRepo.EXPECT().UpdateReport(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, report *repository.WeeklyReport) error {
+		if report.Status != "draft" || report.SubmittedAt != nil || report.Resubmissions != 2 {
+			t.Errorf("unexpected reopened report %+v", report)
+		}
+		return nil
+	})
+	report, err := svc.Reopen(context.Background(), 3, 1, 4, permissions.PermissionDriver)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Rejections) != 1 {
+		t.Fatalf("expected the rejection history to be kept, got %+v", report.Rejections)
+	}
+}
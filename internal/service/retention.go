@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"taxifleet/backend/internal/logging"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/validation"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RetentionRepository is the data access RetentionService depends on
+type RetentionRepository interface {
+	repository.RetentionRepo
+	repository.TenantRepo
+}
+
+// RetentionService removes the data the tenants' retention settings no
+// longer keep: taxi positions after some days, and deleted records for good
+// some days after their deletion
+type RetentionService struct {
+	repo   RetentionRepository
+	logger *logrus.Logger
+}
+
+func NewRetentionService(repo RetentionRepository, logger *logrus.Logger) *RetentionService {
+	return &RetentionService{repo: repo, logger: logger}
+}
+
+// RetentionReport is what retention removes from a tenant's data
+type RetentionReport struct {
+	TenantID        uint             `json:"tenant_id"`
+	PositionsBefore *time.Time       `json:"positions_before"` // Nil when positions are kept
+	DeletedBefore   *time.Time       `json:"deleted_before"`   // Nil when deleted records are kept
+	Rows            map[string]int64 `json:"rows"`             // Per table
+}
+
+// Preview reports what the next purge would remove from the tenant's data,
+// without removing anything
+func (s *RetentionService) Preview(ctx context.Context, tenantID uint) (*RetentionReport, error) {
+	tenant, err := s.repo.GetTenantByID(ctx, tenantID)
+	if err != nil {
+		return nil, notFound("tenant not found")
+	}
+
+	cutoffs := retentionCutoffs(parseTenantSettings(tenant.Settings).Retention, time.Now())
+	rows, err := s.repo.CountExpiredData(ctx, tenant.ID, cutoffs)
+	if err != nil {
+		return nil, err
+	}
+	return &RetentionReport{TenantID: tenant.ID, PositionsBefore: cutoffs.Positions, DeletedBefore: cutoffs.Deleted, Rows: rows}, nil
+}
+
+// Purge removes the expired data of every tenant with retention settings.
+// A tenant that fails is skipped and reported in the returned error.
+func (s *RetentionService) Purge(ctx context.Context) error {
+	tenants, err := s.repo.GetAllTenants(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var errs []error
+	for _, tenant := range tenants {
+		cutoffs := retentionCutoffs(parseTenantSettings(tenant.Settings).Retention, now)
+		if cutoffs.Positions == nil && cutoffs.Deleted == nil {
+			continue
+		}
+		deleted, err := s.repo.PurgeExpiredData(ctx, tenant.ID, cutoffs)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("retention of tenant %d: %w", tenant.ID, err))
+			continue
+		}
+		logging.Entry(ctx, s.logger).WithFields(logrus.Fields{
+			"tenant_id": tenant.ID,
+			"deleted":   deleted,
+		}).Info("Purged expired data")
+	}
+	return errors.Join(errs...)
+}
+
+func retentionCutoffs(settings RetentionSettings, now time.Time) repository.RetentionCutoffs {
+	var cutoffs repository.RetentionCutoffs
+	if settings.PositionsDays > 0 {
+		before := now.AddDate(0, 0, -settings.PositionsDays)
+		cutoffs.Positions = &before
+	}
+	if settings.DeletedDays > 0 {
+		before := now.AddDate(0, 0, -settings.DeletedDays)
+		cutoffs.Deleted = &before
+	}
+	return cutoffs
+}
+
+func checkRetention(settings RetentionSettings) error {
+	if settings.PositionsDays < 0 {
+		return &validation.FieldError{Field: "retention.positions_days", Rule: "min", Message: "positions_days can't be negative"}
+	}
+	if settings.DeletedDays < 0 {
+		return &validation.FieldError{Field: "retention.deleted_days", Rule: "min", Message: "deleted_days can't be negative"}
+	}
+	return nil
+}
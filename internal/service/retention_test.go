@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+	"taxifleet/backend/internal/validation"
+
+	"github.com/sirupsen/logrus"
+	"go.uber.org/mock/gomock"
+)
+
+type retentionRepoMock struct {
+	*mocks.MockRetentionRepo
+	*mocks.MockTenantRepo
+}
+
+func newRetentionServiceMock(t *testing.T) (*RetentionService, retentionRepoMock) {
+	ctrl := gomock.NewController(t)
+	repo := retentionRepoMock{
+		MockRetentionRepo: mocks.NewMockRetentionRepo(ctrl),
+		MockTenantRepo:    mocks.NewMockTenantRepo(ctrl),
+	}
+	return NewRetentionService(repo, logrus.New()), repo
+}
+
+func TestRetentionPreviewCountsWithoutDeleting(t *testing.T) {
+	svc, repo := newRetentionServiceMock(t)
+	repo.MockTenantRepo.EXPECT().GetTenantByID(gomock.Any(), uint(1)).
+		Return(&repository.Tenant{ID: 1, Settings: `{"retention": {"positions_days": 90}}`}, nil)
+	repo.MockRetentionRepo.EXPECT().CountExpiredData(gomock.Any(), uint(1), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uint, cutoffs repository.RetentionCutoffs) (map[string]int64, error) {
+			if cutoffs.Positions == nil || cutoffs.Deleted != nil {
+				t.Fatalf("expected only a positions cutoff, got %+v", cutoffs)
+			}
+			return map[string]int64{"taxi_positions": 1200}, nil
+		})
+
+	report, err := svc.Preview(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := time.Now().AddDate(0, 0, -90); report.PositionsBefore.Sub(want).Abs() > time.Minute {
+		t.Errorf("expected positions before %s, got %s", want, report.PositionsBefore)
+	}
+	if report.Rows["taxi_positions"] != 1200 {
+		t.Errorf("unexpected rows %v", report.Rows)
+	}
+}
+
+func TestRetentionPurgeSkipsTenantsWithoutSettings(t *testing.T) {
+	svc, repo := newRetentionServiceMock(t)
+	repo.MockTenantRepo.EXPECT().GetAllTenants(gomock.Any()).Return([]repository.Tenant{
+		{ID: 1},
+		{ID: 2, Settings: `{"retention": {"deleted_days": 365}}`},
+		{ID: 3, Settings: `{"retention": {"positions_days": 30}}`},
+	}, nil)
+	repo.MockRetentionRepo.EXPECT().PurgeExpiredData(gomock.Any(), uint(2), gomock.Any()).Return(map[string]int64{"expenses": 3}, nil)
+	repo.MockRetentionRepo.EXPECT().PurgeExpiredData(gomock.Any(), uint(3), gomock.Any()).Return(nil, errors.New("connection reset"))
+
+	if err := svc.Purge(context.Background()); err == nil {
+		t.Fatal("expected the failed tenant to be reported")
+	}
+}
+
+func TestRetentionSettingsValidated(t *testing.T) {
+	var fieldErr *validation.FieldError
+	if err := checkTenantSettings(`{"retention": {"deleted_days": -1}}`); !errors.As(err, &fieldErr) || fieldErr.Field != "retention.deleted_days" {
+		t.Fatalf("expected a retention.deleted_days field error, got %v", err)
+	}
+	if err := checkTenantSettings(`{"retention": {"positions_days": 90, "deleted_days": 365}}`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
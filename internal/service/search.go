@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"taxifleet/backend/internal/permissions"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/validation"
+)
+
+// searchLimit is how many matches of each kind the combined search returns
+const searchLimit = 20
+
+// SearchRepository is the data access SearchService depends on
+type SearchRepository interface {
+	repository.TaxiRepo
+	repository.ReportRepo
+	repository.ExpenseRepo
+}
+
+// SearchService searches taxis, reports and expenses at once
+type SearchService struct {
+	repo SearchRepository
+}
+
+func NewSearchService(repo SearchRepository) *SearchService {
+	return &SearchService{repo: repo}
+}
+
+// SearchResults holds the best matches of each kind
+type SearchResults struct {
+	Taxis    []repository.Taxi         `json:"taxis"`
+	Reports  []repository.WeeklyReport `json:"reports"`
+	Expenses []repository.Expense      `json:"expenses"`
+}
+
+// Search finds what the user could see in the taxi, report and expense
+// lists; drivers only find their own reports
+func (s *SearchService) Search(ctx context.Context, tenantID, userID uint, permission int, query string) (*SearchResults, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, &validation.FieldError{Field: "q", Rule: "required", Message: "q is required"}
+	}
+
+	taxis, err := s.repo.SearchTaxis(ctx, tenantID, query, searchLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	driverID := uint(0)
+	if permission == permissions.PermissionDriver {
+		driverID = userID
+	}
+	reports, err := s.repo.SearchReports(ctx, tenantID, driverID, query, searchLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	expenses, err := s.repo.SearchExpenses(ctx, tenantID, query, searchLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SearchResults{Taxis: taxis, Reports: reports, Expenses: expenses}, nil
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"cmp"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/validation"
+)
+
+// TenantSettings is the typed view of the Tenant.Settings JSON document.
+// Unknown keys are ignored and missing keys fall back to defaults.
+type TenantSettings struct {
+	WeekStartDay    string `json:"week_start_day"`    // monday..sunday, defaults to monday
+	FiscalYearStart string `json:"fiscal_year_start"` // MM-DD, defaults to 01-01
+	Country         string `json:"country"`           // ISO 3166 code, selects the license plate format
+
+	// EarningsSplit shares approved reports' earnings between owner and
+	// driver, unless the taxi has its own rule
+	EarningsSplit *repository.EarningsSplit `json:"earnings_split"`
+
+	// AccountCodes are the ledger accounts of the accounting journal export
+	AccountCodes AccountCodes `json:"account_codes"`
+
+	// Exports controls the branding of generated XLSX and PDF exports
+	Exports ExportSettings `json:"exports"`
+
+	// Retention removes the tenant's old data, see RetentionService
+	Retention RetentionSettings `json:"retention"`
+
+	// RequireApprovalSignature makes approvers sign off each approval with
+	// their PIN or a drawn signature
+	RequireApprovalSignature bool `json:"require_approval_signature"`
+
+	// ExpenseApprovalThreshold is the amount above which expenses recorded
+	// by anyone but an owner wait for an owner's approval before they count;
+	// zero disables approval
+	ExpenseApprovalThreshold money.Amount `json:"expense_approval_threshold"`
+}
+
+// RetentionSettings are how many days data is kept; zero keeps it forever
+type RetentionSettings struct {
+	PositionsDays int `json:"positions_days"` // Taxi positions, after they were recorded
+	DeletedDays   int `json:"deleted_days"`   // Deleted records, after their deletion
+}
+
+// ExportSettings brand generated documents with the tenant's logo and name
+// and shape the columns of list exports
+type ExportSettings struct {
+	Branding bool   `json:"branding"` // Logo and name header, generation footer
+	Totals   bool   `json:"totals"`   // Totals row below list sections
+	Footer   string `json:"footer"`   // Extra footer text, e.g. a company registration number
+
+	// Templates per list export: reports, expenses or deposits
+	Templates map[string]ExportTemplate `json:"templates"`
+}
+
+// AccountCodes maps the journal's accounts to the tenant's chart of accounts.
+// Empty codes fall back to defaults.
+type AccountCodes struct {
+	Cash     string            `json:"cash"`     // Cash collected by drivers, defaults to 1000
+	Bank     string            `json:"bank"`     // Defaults to 1200
+	Revenue  string            `json:"revenue"`  // Fare revenue, defaults to 4000
+	Expense  string            `json:"expense"`  // Expenses of categories without a code of their own
+	Expenses map[string]string `json:"expenses"` // Code per expense category
+}
+
+// defaultExpenseCodes are used for the categories the tenant did not map,
+// unless it set a catch-all expense code
+var defaultExpenseCodes = map[string]string{
+	"fuel":        "6100",
+	"maintenance": "6200",
+	"insurance":   "6300",
+	"repair":      "6400",
+	"cleaning":    "6500",
+}
+
+func (a AccountCodes) CashCode() string    { return cmp.Or(a.Cash, "1000") }
+func (a AccountCodes) BankCode() string    { return cmp.Or(a.Bank, "1200") }
+func (a AccountCodes) RevenueCode() string { return cmp.Or(a.Revenue, "4000") }
+
+// ExpenseCode returns the account expenses of the category are booked to
+func (a AccountCodes) ExpenseCode(category string) string {
+	return cmp.Or(a.Expenses[category], a.Expense, defaultExpenseCodes[category], "6000")
+}
+
+func parseTenantSettings(raw string) TenantSettings {
+	var settings TenantSettings
+	if raw != "" {
+		_ = json.Unmarshal([]byte(raw), &settings)
+	}
+	return settings
+}
+
+// checkTenantSettings validates the settings against their schema and the
+// rules a schema cannot express
+func checkTenantSettings(raw string) error {
+	if err := checkSettingsSchema(raw); err != nil {
+		return err
+	}
+	settings := parseTenantSettings(raw)
+	if err := checkEarningsSplit(settings.EarningsSplit); err != nil {
+		return err
+	}
+	if err := checkRetention(settings.Retention); err != nil {
+		return err
+	}
+	if settings.ExpenseApprovalThreshold < 0 {
+		return &validation.FieldError{Field: "expense_approval_threshold", Rule: "min", Message: "expense_approval_threshold can't be negative"}
+	}
+	return checkExportTemplates(settings.Exports.Templates)
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// WeekStart returns the configured first day of the reporting week
+func (t TenantSettings) WeekStart() time.Weekday {
+	if day, ok := weekdays[strings.ToLower(t.WeekStartDay)]; ok {
+		return day
+	}
+	return time.Monday
+}
+
+// FiscalYear returns the [start, end) boundaries of the fiscal year starting in
+// the given calendar year
+func (t TenantSettings) FiscalYear(year int) (time.Time, time.Time) {
+	month, day := time.January, 1
+	if parsed, err := time.Parse("01-02", t.FiscalYearStart); err == nil {
+		month, day = parsed.Month(), parsed.Day()
+	}
+
+	start := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(1, 0, 0)
+}
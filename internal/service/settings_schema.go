@@ -0,0 +1,272 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"taxifleet/backend/internal/validation"
+)
+
+// jsonSchema is the part of JSON Schema (draft 2020-12) that describes the
+// tenant settings. Type is a type name or a list of them.
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 any                    `json:"type,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	AdditionalProperties any                    `json:"additionalProperties,omitempty"` // false or a schema
+	Required             []string               `json:"required,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	MaxLength            *int                   `json:"maxLength,omitempty"`
+	Minimum              *float64               `json:"minimum,omitempty"`
+	Maximum              *float64               `json:"maximum,omitempty"`
+	MultipleOf           *float64               `json:"multipleOf,omitempty"`
+}
+
+func bound(n float64) *float64 { return &n }
+func length(n int) *int        { return &n }
+
+// amountSchema is a sum of money in major units with at most two decimals
+func amountSchema(description string) *jsonSchema {
+	return &jsonSchema{Type: "number", Description: description, Minimum: bound(0), MultipleOf: bound(0.01)}
+}
+
+// exportTemplateSchema is the template of the export with the given columns
+func exportTemplateSchema(columns []string) *jsonSchema {
+	return &jsonSchema{
+		Type: "object",
+		Properties: map[string]*jsonSchema{
+			"columns": {
+				Type:        "array",
+				Description: "Columns in order; empty keeps every column in the default order",
+				Items: &jsonSchema{
+					Type: "object",
+					Properties: map[string]*jsonSchema{
+						"key":   {Type: "string", Enum: columns},
+						"label": {Type: "string", Description: "Header label, overriding the default", MaxLength: length(100)},
+					},
+					Required:             []string{"key"},
+					AdditionalProperties: false,
+				},
+			},
+			"language": {Type: "string", Description: "Language of the default header labels", Enum: []string{"", "en", "de", "fr", "es", "pt"}},
+		},
+		AdditionalProperties: false,
+	}
+}
+
+// tenantSettingsSchema describes TenantSettings; keys it does not know are
+// refused
+var tenantSettingsSchema = func() *jsonSchema {
+	templates := make(map[string]*jsonSchema, len(exportColumns))
+	for name, columns := range exportColumns {
+		templates[name] = exportTemplateSchema(columns)
+	}
+	accountCode := &jsonSchema{Type: "string", MaxLength: length(20)}
+
+	return &jsonSchema{
+		Schema: "https://json-schema.org/draft/2020-12/schema",
+		Title:  "Tenant settings",
+		Type:   "object",
+		Properties: map[string]*jsonSchema{
+			"week_start_day":    {Type: "string", Description: "First day of the reporting week, defaults to monday", Enum: []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}},
+			"fiscal_year_start": {Type: "string", Description: "MM-DD, defaults to 01-01", Pattern: `^(0[1-9]|1[0-2])-(0[1-9]|[12][0-9]|3[01])$`},
+			"country":           {Type: "string", Description: "ISO 3166 code, selects the license plate format", Pattern: `^[A-Za-z]{2}$`},
+			"earnings_split": {
+				Type:        []string{"object", "null"},
+				Description: "Shares approved reports' earnings between owner and driver, unless the taxi has its own rule",
+				Properties: map[string]*jsonSchema{
+					"type":           {Type: "string", Enum: []string{splitPercentage, splitDailyRental}},
+					"driver_percent": {Type: "number", Description: "percentage: the driver's share of net earnings", Minimum: bound(0), Maximum: bound(100)},
+					"daily_rental":   amountSchema("daily_rental: what the driver owes the owner per day"),
+				},
+				Required:             []string{"type"},
+				AdditionalProperties: false,
+			},
+			"account_codes": {
+				Type:        "object",
+				Description: "Ledger accounts of the accounting journal export",
+				Properties: map[string]*jsonSchema{
+					"cash":     accountCode,
+					"bank":     accountCode,
+					"revenue":  accountCode,
+					"expense":  accountCode,
+					"expenses": {Type: "object", Description: "Code per expense category", AdditionalProperties: accountCode},
+				},
+				AdditionalProperties: false,
+			},
+			"exports": {
+				Type:        "object",
+				Description: "Branding and columns of generated exports",
+				Properties: map[string]*jsonSchema{
+					"branding":  {Type: "boolean"},
+					"totals":    {Type: "boolean"},
+					"footer":    {Type: "string", MaxLength: length(500)},
+					"templates": {Type: "object", Properties: templates, AdditionalProperties: false},
+				},
+				AdditionalProperties: false,
+			},
+			"retention": {
+				Type:        "object",
+				Description: "Days data is kept; 0 keeps it forever",
+				Properties: map[string]*jsonSchema{
+					"positions_days": {Type: "integer", Minimum: bound(0)},
+					"deleted_days":   {Type: "integer", Minimum: bound(0)},
+				},
+				AdditionalProperties: false,
+			},
+			"require_approval_signature": {Type: "boolean"},
+			"expense_approval_threshold": amountSchema("Amount above which expenses wait for an owner's approval; 0 disables approval"),
+		},
+		AdditionalProperties: false,
+	}
+}()
+
+// TenantSettingsSchemaJSON is the JSON schema of the tenant settings
+var TenantSettingsSchemaJSON = func() json.RawMessage {
+	data, err := json.Marshal(tenantSettingsSchema)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}()
+
+// checkSettingsSchema returns a validation.FieldErrors naming every key of
+// the settings document that does not match the schema
+func checkSettingsSchema(raw string) error {
+	var document any
+	if err := json.Unmarshal([]byte(raw), &document); err != nil {
+		return &validation.FieldError{Field: "settings", Rule: "json", Message: "settings must be a JSON object"}
+	}
+
+	var errs validation.FieldErrors
+	tenantSettingsSchema.check(document, "", &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// check adds an error for each part of value at path that breaks the schema
+func (s *jsonSchema) check(value any, path string, errs *validation.FieldErrors) {
+	fail := func(field, rule, format string, args ...any) {
+		name := field
+		if name == "" {
+			name = "settings"
+		}
+		*errs = append(*errs, &validation.FieldError{Field: name, Rule: rule, Message: name + " " + fmt.Sprintf(format, args...)})
+	}
+
+	if !s.allows(value) {
+		fail(path, "type", "must be %s", s.typeNames())
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			child := joinPath(path, key)
+			if property, ok := s.Properties[key]; ok {
+				property.check(v[key], child, errs)
+			} else if additional, ok := s.AdditionalProperties.(*jsonSchema); ok {
+				additional.check(v[key], child, errs)
+			} else if s.AdditionalProperties == false {
+				fail(child, "additionalProperties", "is not a known setting")
+			}
+		}
+		for _, key := range s.Required {
+			if _, ok := v[key]; !ok {
+				fail(joinPath(path, key), "required", "is required")
+			}
+		}
+	case []any:
+		if s.Items != nil {
+			for i, item := range v {
+				s.Items.check(item, fmt.Sprintf("%s[%d]", path, i), errs)
+			}
+		}
+	case string:
+		if len(s.Enum) > 0 && !slices.Contains(s.Enum, v) {
+			fail(path, "enum", "must be one of %s", strings.Join(s.Enum, ", "))
+		}
+		if s.Pattern != "" && !regexp.MustCompile(s.Pattern).MatchString(v) {
+			fail(path, "pattern", "must match %s", s.Pattern)
+		}
+		if s.MaxLength != nil && utf8.RuneCountInString(v) > *s.MaxLength {
+			fail(path, "maxLength", "must be at most %d characters", *s.MaxLength)
+		}
+	case float64:
+		if s.Minimum != nil && v < *s.Minimum {
+			fail(path, "minimum", "must be at least %v", *s.Minimum)
+		}
+		if s.Maximum != nil && v > *s.Maximum {
+			fail(path, "maximum", "must be at most %v", *s.Maximum)
+		}
+		if s.MultipleOf != nil {
+			if steps := v / *s.MultipleOf; math.Abs(steps-math.Round(steps)) > 1e-6 {
+				fail(path, "multipleOf", "must be a multiple of %v", *s.MultipleOf)
+			}
+		}
+	}
+}
+
+// allows reports whether value has one of the schema's types
+func (s *jsonSchema) allows(value any) bool {
+	types, ok := s.Type.([]string)
+	if !ok {
+		name, _ := s.Type.(string)
+		types = []string{name}
+	}
+	for _, name := range types {
+		switch v := value.(type) {
+		case map[string]any:
+			ok = name == "object"
+		case []any:
+			ok = name == "array"
+		case string:
+			ok = name == "string"
+		case bool:
+			ok = name == "boolean"
+		case float64:
+			ok = name == "number" || name == "integer" && v == math.Trunc(v)
+		case nil:
+			ok = name == "null"
+		}
+		if ok || name == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *jsonSchema) typeNames() string {
+	if types, ok := s.Type.([]string); ok {
+		return strings.Join(types, " or ")
+	}
+	name, _ := s.Type.(string)
+	if name == "object" || name == "array" || name == "integer" {
+		return "an " + name
+	}
+	return "a " + name
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"taxifleet/backend/internal/validation"
+)
+
+func TestCheckTenantSettingsReportsEveryOffendingKey(t *testing.T) {
+	err := checkTenantSettings(`{"week_start_day": "funday", "retention": {"deleted_days": 1.5},
+		"earnings_split": {"driver_percent": 60}, "expense_approval_threshold": 10.005, "theme": "dark"}`)
+
+	var fieldErrs validation.FieldErrors
+	if !errors.As(err, &fieldErrs) {
+		t.Fatalf("expected field errors, got %v", err)
+	}
+	got := make(map[string]string, len(fieldErrs))
+	for _, fieldErr := range fieldErrs {
+		got[fieldErr.Field] = fieldErr.Rule
+	}
+	want := map[string]string{
+		"week_start_day":             "enum",
+		"retention.deleted_days":     "type",
+		"earnings_split.type":        "required",
+		"expense_approval_threshold": "multipleOf",
+		"theme":                      "additionalProperties",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for field, rule := range want {
+		if got[field] != rule {
+			t.Errorf("expected %s to break %s, got %q", field, rule, got[field])
+		}
+	}
+}
+
+func TestCheckTenantSettingsAcceptsValidSettings(t *testing.T) {
+	valid := []string{
+		`{}`,
+		`{"week_start_day": "sunday", "fiscal_year_start": "07-01", "country": "CI", "earnings_split": null}`,
+		`{"earnings_split": {"type": "daily_rental", "daily_rental": 25.5}, "expense_approval_threshold": 300}`,
+		`{"account_codes": {"cash": "530", "expenses": {"fuel": "606"}}, "require_approval_signature": true}`,
+		`{"exports": {"branding": true, "templates": {"expenses": {"language": "de", "columns": [{"key": "amount", "label": "Summe"}]}}}}`,
+	}
+	for _, raw := range valid {
+		if err := checkTenantSettings(raw); err != nil {
+			t.Errorf("expected %s to be valid, got %v", raw, err)
+		}
+	}
+
+	if err := checkTenantSettings(`[]`); err == nil {
+		t.Error("expected a settings array to be refused")
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(TenantSettingsSchemaJSON, &schema); err != nil || schema["type"] != "object" {
+		t.Fatalf("expected the schema to be a JSON object schema, got %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"taxifleet/backend/internal/validation"
+)
+
+// ListOptions are the query parameters of searchable list endpoints
+type ListOptions struct {
+	Query string // Full-text search; matches are listed best first
+	Sort  string // See sortList
+}
+
+// sortKeys compares two list items by the field named by the key
+type sortKeys[T any] map[string]func(a, b T) int
+
+// sortList orders items by a sort parameter such as "-week_start_date,id":
+// field names separated by commas, each descending with a leading "-". Items
+// that compare equal keep their default order, and an empty parameter leaves
+// the list as it is. The items are copied, so cached lists are never reordered.
+func sortList[T any](items []T, param string, keys sortKeys[T]) ([]T, error) {
+	if param == "" {
+		return items, nil
+	}
+
+	var compares []func(a, b T) int
+	for _, field := range strings.Split(param, ",") {
+		field = strings.TrimSpace(field)
+		name := strings.TrimPrefix(field, "-")
+		compare, ok := keys[name]
+		if !ok {
+			return nil, &validation.FieldError{
+				Field:   "sort",
+				Rule:    "sort",
+				Message: fmt.Sprintf("cannot sort by %q, use one of: %s", name, strings.Join(sortKeyNames(keys), ", ")),
+			}
+		}
+		if name != field {
+			ascending := compare
+			compare = func(a, b T) int { return -ascending(a, b) }
+		}
+		compares = append(compares, compare)
+	}
+
+	sorted := slices.Clone(items)
+	slices.SortStableFunc(sorted, func(a, b T) int {
+		for _, compare := range compares {
+			if c := compare(a, b); c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+	return sorted, nil
+}
+
+func sortKeyNames[T any](keys sortKeys[T]) []string {
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
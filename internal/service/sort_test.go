@@ -0,0 +1,38 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/validation"
+)
+
+func TestSortListByMultipleFields(t *testing.T) {
+	taxis := []repository.Taxi{
+		{ID: 1, Status: "active", Mileage: 100},
+		{ID: 2, Status: "maintenance", Mileage: 300},
+		{ID: 3, Status: "active", Mileage: 200},
+	}
+
+	sorted, err := sortList(taxis, "status,-mileage", taxiSortKeys)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, want := range []uint{3, 1, 2} {
+		if sorted[i].ID != want {
+			t.Fatalf("expected order 3, 1, 2, got %d at %d", sorted[i].ID, i)
+		}
+	}
+	if taxis[0].ID != 1 {
+		t.Error("sorting must not reorder the original list")
+	}
+}
+
+func TestSortListRejectsUnknownField(t *testing.T) {
+	_, err := sortList([]repository.Taxi{{ID: 1}}, "vin", taxiSortKeys)
+	var fieldErr *validation.FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Field != "sort" {
+		t.Fatalf("expected sort field error, got %v", err)
+	}
+}
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"slices"
+	"strings"
+
+	"taxifleet/backend/internal/validation"
+)
+
+// Groups the report and expense summaries can be made of
+var (
+	reportSummaryGroups  = []string{"status", "taxi", "driver", "month"}
+	expenseSummaryGroups = []string{"category", "taxi", "month"}
+)
+
+// checkGroupBy fails unless groupBy is one of the groups
+func checkGroupBy(groupBy string, groups []string) error {
+	if !slices.Contains(groups, groupBy) {
+		return &validation.FieldError{Field: "group_by", Rule: "oneof", Message: "group_by must be one of " + strings.Join(groups, ", ")}
+	}
+	return nil
+}
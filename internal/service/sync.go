@@ -0,0 +1,331 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"time"
+
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/validation"
+
+	"gorm.io/gorm"
+)
+
+// syncOverlap is how far before the cursor changes are looked for, covering
+// clock differences between the API and the database; a record may come twice
+const syncOverlap = time.Minute
+
+// Outcomes of a synced item
+const (
+	SyncCreated  = "created"
+	SyncExists   = "exists" // Created by an earlier sync
+	SyncUpdated  = "updated"
+	SyncConflict = "conflict" // Changed on the server since the copy the edit is based on
+	SyncFailed   = "failed"
+)
+
+// SyncService applies what the driver app created offline and returns what
+// changed on the server since the app last synced
+type SyncService struct {
+	repo     repository.SyncRepo
+	reports  *ReportService
+	expenses *ExpenseService
+}
+
+func NewSyncService(repo repository.SyncRepo, reports *ReportService, expenses *ExpenseService) *SyncService {
+	return &SyncService{repo: repo, reports: reports, expenses: expenses}
+}
+
+// SyncExpenseRequest is an expense created offline
+type SyncExpenseRequest struct {
+	CreateExpenseRequest
+	// ReportClientID attaches the expense to a report created offline
+	ReportClientID string `json:"report_client_id" binding:"omitempty,uuid"`
+}
+
+// SyncReportUpdate is an edit made offline to a report
+type SyncReportUpdate struct {
+	ID uint `json:"id" binding:"required"`
+	// ClientUpdatedAt is the updated_at of the copy the edit is based on
+	ClientUpdatedAt time.Time `json:"client_updated_at" binding:"required"`
+	UpdateReportRequest
+}
+
+// SyncExpenseUpdate is an edit made offline to an expense
+type SyncExpenseUpdate struct {
+	ID uint `json:"id" binding:"required"`
+	// ClientUpdatedAt is the updated_at of the copy the edit is based on
+	ClientUpdatedAt time.Time `json:"client_updated_at" binding:"required"`
+	UpdateExpenseRequest
+}
+
+// SyncRequest is a batch of reports and expenses created offline, each with
+// a client_id, and of edits made offline. Reports are applied first so
+// expenses can refer to them, and creates before edits.
+type SyncRequest struct {
+	Cursor         string                `json:"cursor"` // From the previous sync; empty on the first one
+	Reports        []CreateReportRequest `json:"reports" binding:"max=100,dive"`
+	Expenses       []SyncExpenseRequest  `json:"expenses" binding:"max=500,dive"`
+	ReportUpdates  []SyncReportUpdate    `json:"report_updates" binding:"max=100,dive"`
+	ExpenseUpdates []SyncExpenseUpdate   `json:"expense_updates" binding:"max=500,dive"`
+}
+
+// SyncItemResult is the outcome of one synced item
+type SyncItemResult struct {
+	ClientID string `json:"client_id"`
+	Status   string `json:"status"`       // created, exists, failed
+	ID       uint   `json:"id,omitempty"` // The server ID unless failed
+	Error    string `json:"error,omitempty"`
+}
+
+// SyncUpdateResult is the outcome of one offline edit
+type SyncUpdateResult struct {
+	ID     uint   `json:"id"`
+	Status string `json:"status"` // updated, conflict, failed
+	Error  string `json:"error,omitempty"`
+	// Server is the server copy on conflict, for the driver to choose between
+	// it and their edit; the edit is not applied
+	Server any `json:"server,omitempty"`
+}
+
+// SyncChanges are the user's reports and expenses changed on the server
+// since the cursor, e.g. reviewed reports
+type SyncChanges struct {
+	Reports           []repository.WeeklyReport `json:"reports"`
+	Expenses          []repository.Expense      `json:"expenses"`
+	DeletedReportIDs  []uint                    `json:"deleted_report_ids"`
+	DeletedExpenseIDs []uint                    `json:"deleted_expense_ids"`
+}
+
+type SyncResponse struct {
+	Reports        []SyncItemResult   `json:"reports"`
+	Expenses       []SyncItemResult   `json:"expenses"`
+	ReportUpdates  []SyncUpdateResult `json:"report_updates"`
+	ExpenseUpdates []SyncUpdateResult `json:"expense_updates"`
+	Changes        SyncChanges        `json:"changes"`
+	// Cursor is sent with the next sync
+	Cursor string `json:"cursor"`
+}
+
+// Sync creates the items not synced before, applies the edits that don't
+// conflict and returns the outcome of each. An item that fails does not stop
+// the others.
+func (s *SyncService) Sync(ctx context.Context, tenantID uint, userID uint, permission int, req SyncRequest) (*SyncResponse, error) {
+	since, err := decodeSyncCursor(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+
+	resp := &SyncResponse{
+		Reports:        make([]SyncItemResult, 0, len(req.Reports)),
+		Expenses:       make([]SyncItemResult, 0, len(req.Expenses)),
+		ReportUpdates:  make([]SyncUpdateResult, 0, len(req.ReportUpdates)),
+		ExpenseUpdates: make([]SyncUpdateResult, 0, len(req.ExpenseUpdates)),
+	}
+	for _, item := range req.Reports {
+		resp.Reports = append(resp.Reports, s.syncReport(ctx, tenantID, userID, item))
+	}
+	for _, item := range req.Expenses {
+		resp.Expenses = append(resp.Expenses, s.syncExpense(ctx, tenantID, userID, permission, item))
+	}
+	for _, item := range req.ReportUpdates {
+		resp.ReportUpdates = append(resp.ReportUpdates, s.syncReportUpdate(ctx, tenantID, userID, permission, item))
+	}
+	for _, item := range req.ExpenseUpdates {
+		resp.ExpenseUpdates = append(resp.ExpenseUpdates, s.syncExpenseUpdate(ctx, tenantID, userID, permission, item))
+	}
+
+	changes, err := s.changes(ctx, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	resp.Changes = *changes
+	resp.Cursor = encodeSyncCursor(now)
+	return resp, nil
+}
+
+func (s *SyncService) syncReport(ctx context.Context, tenantID uint, userID uint, item CreateReportRequest) SyncItemResult {
+	result := SyncItemResult{ClientID: item.ClientID}
+	if item.ClientID == "" {
+		return failedSync(result, invalid("client_id is required"))
+	}
+
+	existing, err := s.repo.GetReportByClientID(ctx, tenantID, item.ClientID)
+	if err == nil {
+		result.Status, result.ID = SyncExists, existing.ID
+		return result
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return failedSync(result, err)
+	}
+
+	report, err := s.reports.Create(ctx, tenantID, userID, item)
+	if err != nil {
+		return failedSync(result, err)
+	}
+	result.Status, result.ID = SyncCreated, report.ID
+	return result
+}
+
+func (s *SyncService) syncExpense(ctx context.Context, tenantID uint, userID uint, permission int, item SyncExpenseRequest) SyncItemResult {
+	result := SyncItemResult{ClientID: item.ClientID}
+	if item.ClientID == "" {
+		return failedSync(result, invalid("client_id is required"))
+	}
+
+	existing, err := s.repo.GetExpenseByClientID(ctx, tenantID, item.ClientID)
+	if err == nil {
+		result.Status, result.ID = SyncExists, existing.ID
+		return result
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return failedSync(result, err)
+	}
+
+	if item.ReportClientID != "" {
+		report, err := s.repo.GetReportByClientID(ctx, tenantID, item.ReportClientID)
+		if err != nil {
+			return failedSync(result, notFound("report not found"))
+		}
+		item.ReportID = &report.ID
+	}
+
+	expense, err := s.expenses.Create(ctx, tenantID, userID, permission, item.CreateExpenseRequest)
+	if err != nil {
+		return failedSync(result, err)
+	}
+	result.Status, result.ID = SyncCreated, expense.ID
+	return result
+}
+
+func (s *SyncService) syncReportUpdate(ctx context.Context, tenantID uint, userID uint, permission int, item SyncReportUpdate) SyncUpdateResult {
+	result := SyncUpdateResult{ID: item.ID}
+	report, err := s.reports.GetByID(ctx, item.ID, tenantID)
+	if err != nil {
+		return failedUpdate(result, err)
+	}
+	if changedSince(report.UpdatedAt, item.ClientUpdatedAt) {
+		result.Status, result.Server = SyncConflict, report
+		return result
+	}
+
+	// The version guards against an edit between the check and the update
+	item.Version = report.Version
+	updated, err := s.reports.Update(ctx, item.ID, tenantID, userID, permission, item.UpdateReportRequest)
+	if errors.Is(err, repository.ErrVersionConflict) {
+		if report, err = s.reports.GetByID(ctx, item.ID, tenantID); err == nil {
+			result.Status, result.Server = SyncConflict, report
+			return result
+		}
+	}
+	if err != nil {
+		return failedUpdate(result, err)
+	}
+	result.Status = SyncUpdated
+	result.Server = updated
+	return result
+}
+
+func (s *SyncService) syncExpenseUpdate(ctx context.Context, tenantID uint, userID uint, permission int, item SyncExpenseUpdate) SyncUpdateResult {
+	result := SyncUpdateResult{ID: item.ID}
+	expense, err := s.expenses.GetByID(ctx, item.ID, tenantID)
+	if err != nil {
+		return failedUpdate(result, err)
+	}
+	if expense.CreatedByID != userID {
+		return failedUpdate(result, notFound("expense not found"))
+	}
+	if changedSince(expense.UpdatedAt, item.ClientUpdatedAt) {
+		result.Status, result.Server = SyncConflict, expense
+		return result
+	}
+
+	updated, err := s.expenses.Update(ctx, item.ID, tenantID, userID, permission, item.UpdateExpenseRequest)
+	if err != nil {
+		return failedUpdate(result, err)
+	}
+	result.Status = SyncUpdated
+	result.Server = updated
+	return result
+}
+
+// changedSince reports whether the server copy was updated after the copy
+// the client edited, comparing at the database's microsecond precision
+func changedSince(serverUpdatedAt, clientUpdatedAt time.Time) bool {
+	return serverUpdatedAt.Truncate(time.Microsecond).After(clientUpdatedAt.Truncate(time.Microsecond))
+}
+
+func failedUpdate(result SyncUpdateResult, err error) SyncUpdateResult {
+	result.Status = SyncFailed
+	result.Error = err.Error()
+	return result
+}
+
+func failedSync(result SyncItemResult, err error) SyncItemResult {
+	result.Status = SyncFailed
+	result.Error = err.Error()
+	return result
+}
+
+func (s *SyncService) changes(ctx context.Context, userID uint, since time.Time) (*SyncChanges, error) {
+	if !since.IsZero() {
+		since = since.Add(-syncOverlap)
+	}
+
+	reports, err := s.repo.GetReportsChangedSince(ctx, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	expenses, err := s.repo.GetExpensesChangedSince(ctx, userID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := &SyncChanges{
+		Reports:           []repository.WeeklyReport{},
+		Expenses:          []repository.Expense{},
+		DeletedReportIDs:  []uint{},
+		DeletedExpenseIDs: []uint{},
+	}
+	for _, report := range reports {
+		if report.DeletedAt.Valid {
+			changes.DeletedReportIDs = append(changes.DeletedReportIDs, report.ID)
+		} else {
+			changes.Reports = append(changes.Reports, report)
+		}
+	}
+	for _, expense := range expenses {
+		if expense.DeletedAt.Valid {
+			changes.DeletedExpenseIDs = append(changes.DeletedExpenseIDs, expense.ID)
+		} else {
+			changes.Expenses = append(changes.Expenses, expense)
+		}
+	}
+	return changes, nil
+}
+
+// encodeSyncCursor makes the opaque cursor of a sync made at the given time
+func encodeSyncCursor(at time.Time) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(at.UnixNano(), 10)))
+}
+
+// decodeSyncCursor returns the time of the sync the cursor was made at, or
+// the zero time without one
+func decodeSyncCursor(cursor string) (time.Time, error) {
+	if cursor == "" {
+		return time.Time{}, nil
+	}
+	invalid := &validation.FieldError{Field: "cursor", Rule: "cursor", Message: "cursor is invalid"}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, invalid
+	}
+	nanos, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil || nanos <= 0 {
+		return time.Time{}, invalid
+	}
+	return time.Unix(0, nanos).UTC(), nil
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/permissions"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestSyncSkipsKnownItemsAndLinksOfflineReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	syncRepo := mocks.NewMockSyncRepo(ctrl)
+	reports, _ := newReportServiceMock(t)
+	expenses, expenseRepo := newExpenseServiceMock(t)
+	svc := NewSyncService(syncRepo, reports, expenses)
+
+	const reportClientID = "0b8f6c1e-3f7a-4d2b-9c55-1d2e3f4a5b6c"
+	const expenseClientID = "5a4b3c2d-1e0f-4a9b-8c7d-6e5f4a3b2c1d"
+
+	// The report was created by an earlier sync whose response got lost
+	syncRepo.EXPECT().GetReportByClientID(gomock.Any(), uint(1), reportClientID).Return(&repository.WeeklyReport{ID: 40, TenantID: 1}, nil).Times(2)
+	syncRepo.EXPECT().GetExpenseByClientID(gomock.Any(), uint(1), expenseClientID).Return(nil, gorm.ErrRecordNotFound)
+
+	expenseRepo.MockTransactor.EXPECT().InTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	})
+	expenseRepo.MockReportRepo.EXPECT().GetReportByID(gomock.Any(), uint(40)).Return(&repository.WeeklyReport{ID: 40, TenantID: 1}, nil)
+	expenseRepo.MockTenantRepo.EXPECT().GetTenantByID(gomock.Any(), uint(1)).Return(&repository.Tenant{ID: 1}, nil)
+	expenseRepo.MockExpenseRepo.EXPECT().CreateExpense(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, expense *repository.Expense) error {
+		if expense.ReportID == nil || *expense.ReportID != 40 || expense.ClientID == nil || *expense.ClientID != expenseClientID {
+			t.Errorf("unexpected expense %+v", expense)
+		}
+		expense.ID = 90
+		return nil
+	})
+	expenseRepo.MockReportRepo.EXPECT().RecalculateReportExpenses(gomock.Any(), uint(40)).Return(nil)
+	expenseRepo.MockExpenseRepo.EXPECT().GetExpenseByID(gomock.Any(), uint(90)).Return(&repository.Expense{ID: 90, TenantID: 1}, nil)
+
+	syncedAt := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+	syncRepo.EXPECT().GetReportsChangedSince(gomock.Any(), uint(7), syncedAt.Add(-syncOverlap)).Return([]repository.WeeklyReport{
+		{ID: 40, Status: "approved"},
+		{ID: 41, DeletedAt: gorm.DeletedAt{Time: syncedAt, Valid: true}},
+	}, nil)
+	syncRepo.EXPECT().GetExpensesChangedSince(gomock.Any(), uint(7), syncedAt.Add(-syncOverlap)).Return(nil, nil)
+
+	resp, err := svc.Sync(context.Background(), 1, 7, permissions.PermissionDriver, SyncRequest{
+		Cursor:   encodeSyncCursor(syncedAt),
+		Reports:  []CreateReportRequest{{ClientID: reportClientID, TaxiID: 5, Earnings: 900_00}},
+		Expenses: []SyncExpenseRequest{{CreateExpenseRequest: CreateExpenseRequest{ClientID: expenseClientID, Category: "fuel", Amount: 50_00, Date: "2024-03-06"}, ReportClientID: reportClientID}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.Reports[0] != (SyncItemResult{ClientID: reportClientID, Status: SyncExists, ID: 40}) {
+		t.Errorf("unexpected report result %+v", resp.Reports[0])
+	}
+	if resp.Expenses[0] != (SyncItemResult{ClientID: expenseClientID, Status: SyncCreated, ID: 90}) {
+		t.Errorf("unexpected expense result %+v", resp.Expenses[0])
+	}
+	if len(resp.Changes.Reports) != 1 || len(resp.Changes.DeletedReportIDs) != 1 || resp.Changes.DeletedReportIDs[0] != 41 {
+		t.Errorf("unexpected changes %+v", resp.Changes)
+	}
+	if resp.Cursor == "" {
+		t.Error("expected a cursor for the next sync")
+	}
+}
+
+func TestSyncReturnsServerCopyOnConflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	syncRepo := mocks.NewMockSyncRepo(ctrl)
+	reports, reportRepo := newReportServiceMock(t)
+	expenses, expenseRepo := newExpenseServiceMock(t)
+	svc := NewSyncService(syncRepo, reports, expenses)
+
+	editedAt := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+
+	// A manager changed the report after the driver's copy
+	reportRepo.MockReportRepo.EXPECT().GetReportByID(gomock.Any(), uint(40)).Return(&repository.WeeklyReport{
+		ID: 40, TenantID: 1, DriverID: 7, Status: "draft", Earnings: 950_00, UpdatedAt: editedAt.Add(time.Hour),
+	}, nil)
+
+	// The expense is unchanged since the driver's copy, up to the database's precision
+	expense := &repository.Expense{ID: 90, TenantID: 1, CreatedByID: 7, Category: "fuel", Amount: 50_00, UpdatedAt: editedAt.Add(400 * time.Nanosecond)}
+	expenseRepo.MockExpenseRepo.EXPECT().GetExpenseByID(gomock.Any(), uint(90)).Return(expense, nil).AnyTimes()
+	expenseRepo.MockTenantRepo.EXPECT().GetTenantByID(gomock.Any(), uint(1)).Return(&repository.Tenant{ID: 1}, nil)
+	expenseRepo.MockTransactor.EXPECT().InTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	})
+	expenseRepo.MockExpenseRepo.EXPECT().UpdateExpense(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, expense *repository.Expense) error {
+		if expense.Amount != 65_00 {
+			t.Errorf("expected the offline amount, got %v", expense.Amount)
+		}
+		return nil
+	})
+
+	syncRepo.EXPECT().GetReportsChangedSince(gomock.Any(), uint(7), time.Time{}).Return(nil, nil)
+	syncRepo.EXPECT().GetExpensesChangedSince(gomock.Any(), uint(7), time.Time{}).Return(nil, nil)
+
+	earnings, amount := money.Amount(900_00), money.Amount(65_00)
+	resp, err := svc.Sync(context.Background(), 1, 7, permissions.PermissionDriver, SyncRequest{
+		ReportUpdates:  []SyncReportUpdate{{ID: 40, ClientUpdatedAt: editedAt, UpdateReportRequest: UpdateReportRequest{Earnings: &earnings}}},
+		ExpenseUpdates: []SyncExpenseUpdate{{ID: 90, ClientUpdatedAt: editedAt, UpdateExpenseRequest: UpdateExpenseRequest{Amount: &amount}}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	conflict := resp.ReportUpdates[0]
+	if server, ok := conflict.Server.(*repository.WeeklyReport); conflict.Status != SyncConflict || !ok || server.Earnings != 950_00 {
+		t.Errorf("expected a conflict with the server copy, got %+v", conflict)
+	}
+	if resp.ExpenseUpdates[0].Status != SyncUpdated {
+		t.Errorf("expected the expense edit applied, got %+v", resp.ExpenseUpdates[0])
+	}
+}
+
+func TestSyncRejectsInvalidCursor(t *testing.T) {
+	svc := NewSyncService(mocks.NewMockSyncRepo(gomock.NewController(t)), nil, nil)
+
+	if _, err := svc.Sync(context.Background(), 1, 7, permissions.PermissionDriver, SyncRequest{Cursor: "not a cursor"}); err == nil {
+		t.Fatal("expected an invalid cursor error")
+	}
+}
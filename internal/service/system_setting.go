@@ -0,0 +1,354 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"taxifleet/backend/internal/config"
+	"taxifleet/backend/internal/logging"
+	"taxifleet/backend/internal/permissions"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/validation"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrSettingNotFound is returned for a key that is not a system setting
+var ErrSettingNotFound = errors.New("setting not found")
+
+// systemSettingsTTL is how long an instance uses its copy of the settings
+// before reading them again, so changes made through another instance apply
+// within it
+const systemSettingsTTL = time.Minute
+
+// Keys of the system settings
+const (
+	SettingPermissionMasks = "permission_masks"
+	SettingRateLimit       = "rate_limit"
+	SettingExportLimits    = "export_limits"
+)
+
+// PermissionMasks are the permissions users get by role when they are
+// created. The admin mask is not tunable: it must keep every bit.
+type PermissionMasks struct {
+	Owner    int `json:"owner"`
+	Manager  int `json:"manager"`
+	Mechanic int `json:"mechanic"`
+	Driver   int `json:"driver"`
+}
+
+// RateLimit is how many requests per second each client IP may make on
+// average and in a burst, and how many exports each user or API key and how
+// many requests each API key of a tenant without a plan may make per hour; 0
+// turns a limit off
+type RateLimit struct {
+	RPS                   int `json:"rps"`
+	Burst                 int `json:"burst"`
+	ExportsPerHour        int `json:"exports_per_hour"`
+	APIKeyRequestsPerHour int `json:"api_key_requests_per_hour"`
+}
+
+// ExportLimits cap the exports of tenants without a plan; plans have their
+// own quota
+type ExportLimits struct {
+	DefaultQuota *int `json:"default_quota"` // Exports per calendar month; nil is unlimited
+}
+
+// SystemSettings are the platform-wide settings in effect: the defaults
+// from the environment, overridden by what admins set
+type SystemSettings struct {
+	PermissionMasks PermissionMasks `json:"permission_masks"`
+	RateLimit       RateLimit       `json:"rate_limit"`
+	ExportLimits    ExportLimits    `json:"export_limits"`
+}
+
+// systemSetting is a tunable part of SystemSettings
+type systemSetting struct {
+	value func(settings *SystemSettings) any // Pointer to the part
+	check func(settings *SystemSettings) error
+}
+
+var systemSettings = map[string]systemSetting{
+	SettingPermissionMasks: {
+		value: func(s *SystemSettings) any { return &s.PermissionMasks },
+		check: func(s *SystemSettings) error {
+			masks := s.PermissionMasks
+			if masks.Owner <= 0 || masks.Manager <= 0 || masks.Mechanic <= 0 || masks.Driver <= 0 {
+				return &validation.FieldError{Field: "value", Rule: "permission_mask", Message: "permission masks must be positive"}
+			}
+			return nil
+		},
+	},
+	SettingRateLimit: {
+		value: func(s *SystemSettings) any { return &s.RateLimit },
+		check: func(s *SystemSettings) error {
+			if s.RateLimit.RPS < 0 || s.RateLimit.Burst < s.RateLimit.RPS {
+				return &validation.FieldError{Field: "value", Rule: "rate_limit", Message: "rps must not be negative and burst must be at least rps"}
+			}
+			if s.RateLimit.ExportsPerHour < 0 || s.RateLimit.APIKeyRequestsPerHour < 0 {
+				return &validation.FieldError{Field: "value", Rule: "min", Message: "hourly limits must not be negative"}
+			}
+			return nil
+		},
+	},
+	SettingExportLimits: {
+		value: func(s *SystemSettings) any { return &s.ExportLimits },
+		check: func(s *SystemSettings) error {
+			if quota := s.ExportLimits.DefaultQuota; quota != nil && *quota < 0 {
+				return &validation.FieldError{Field: "value", Rule: "min", Message: "default_quota must not be negative"}
+			}
+			return nil
+		},
+	},
+}
+
+// SystemSettingRepository is the data access SystemSettingService depends on
+type SystemSettingRepository interface {
+	repository.Transactor
+	repository.SystemSettingRepo
+}
+
+// SystemSettingService lets platform admins tune settings at runtime. Each
+// instance keeps the settings in memory and reads them again after
+// systemSettingsTTL; every change is recorded.
+type SystemSettingService struct {
+	repo   SystemSettingRepository
+	cfg    *config.Config
+	logger *logrus.Logger
+
+	mu       sync.Mutex
+	current  SystemSettings
+	stored   map[string]repository.SystemSetting
+	loadedAt time.Time
+}
+
+func NewSystemSettingService(repo SystemSettingRepository, cfg *config.Config, logger *logrus.Logger) *SystemSettingService {
+	s := &SystemSettingService{repo: repo, cfg: cfg, logger: logger}
+	s.current = s.defaults()
+	return s
+}
+
+// defaults are the settings from the environment
+func (s *SystemSettingService) defaults() SystemSettings {
+	return SystemSettings{
+		PermissionMasks: PermissionMasks{
+			Owner:    s.cfg.Permissions.Owner,
+			Manager:  s.cfg.Permissions.Manager,
+			Mechanic: s.cfg.Permissions.Mechanic,
+			Driver:   s.cfg.Permissions.Driver,
+		},
+		RateLimit: RateLimit{
+			RPS:                   s.cfg.Security.RateLimitRPS,
+			Burst:                 s.cfg.Security.RateLimitBurst,
+			ExportsPerHour:        s.cfg.Security.RateLimitExportsPerHour,
+			APIKeyRequestsPerHour: s.cfg.Security.RateLimitAPIKeyPerHour,
+		},
+	}
+}
+
+// Load reads the settings and applies the permission masks
+func (s *SystemSettingService) Load(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load(ctx)
+}
+
+func (s *SystemSettingService) load(ctx context.Context) error {
+	rows, err := s.repo.GetSystemSettings(ctx)
+	if err != nil {
+		return err
+	}
+
+	settings := s.defaults()
+	stored := make(map[string]repository.SystemSetting, len(rows))
+	for _, row := range rows {
+		setting, ok := systemSettings[row.Key]
+		if !ok {
+			continue
+		}
+		// A value that no longer decodes keeps the default
+		if err := json.Unmarshal([]byte(row.Value), setting.value(&settings)); err != nil {
+			logging.Entry(ctx, s.logger).WithError(err).WithField("key", row.Key).Error("Invalid system setting")
+			continue
+		}
+		stored[row.Key] = row
+	}
+
+	if settings.PermissionMasks != s.current.PermissionMasks || s.loadedAt.IsZero() {
+		masks := settings.PermissionMasks
+		permissions.SetPermissionMasks(s.cfg.Permissions.Admin, masks.Owner, masks.Manager, masks.Mechanic, masks.Driver)
+	}
+	s.current, s.stored, s.loadedAt = settings, stored, time.Now()
+	return nil
+}
+
+// Current returns the settings in effect. It reads them again once they are
+// older than systemSettingsTTL; if that fails the known settings stay.
+func (s *SystemSettingService) Current(ctx context.Context) SystemSettings {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if time.Since(s.loadedAt) >= systemSettingsTTL {
+		if err := s.load(ctx); err != nil {
+			logging.Entry(ctx, s.logger).WithError(err).Error("Failed to load system settings")
+			// Try again after the TTL rather than on every request
+			s.loadedAt = time.Now()
+		}
+	}
+	return s.current
+}
+
+// SystemSettingView is a setting as admins see it
+type SystemSettingView struct {
+	Key         string          `json:"key"`
+	Value       json.RawMessage `json:"value"`   // In effect
+	Default     json.RawMessage `json:"default"` // From the environment
+	Overridden  bool            `json:"overridden"`
+	UpdatedByID *uint           `json:"updated_by_id,omitempty"`
+	UpdatedAt   *time.Time      `json:"updated_at,omitempty"`
+}
+
+// List returns every setting, freshly read
+func (s *SystemSettingService) List(ctx context.Context) ([]SystemSettingView, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.load(ctx); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(systemSettings))
+	for key := range systemSettings {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	views := make([]SystemSettingView, 0, len(keys))
+	for _, key := range keys {
+		view, err := s.view(key)
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, *view)
+	}
+	return views, nil
+}
+
+func (s *SystemSettingService) view(key string) (*SystemSettingView, error) {
+	setting := systemSettings[key]
+	defaults := s.defaults()
+	value, err := json.Marshal(setting.value(&s.current))
+	if err != nil {
+		return nil, err
+	}
+	defaultValue, err := json.Marshal(setting.value(&defaults))
+	if err != nil {
+		return nil, err
+	}
+
+	view := &SystemSettingView{Key: key, Value: value, Default: defaultValue}
+	if row, ok := s.stored[key]; ok {
+		view.Overridden = true
+		view.UpdatedByID = row.UpdatedByID
+		view.UpdatedAt = &row.UpdatedAt
+	}
+	return view, nil
+}
+
+// Set overrides a setting. Fields left out of the value keep their default.
+func (s *SystemSettingService) Set(ctx context.Context, key string, value json.RawMessage, userID uint) (*SystemSettingView, error) {
+	setting, ok := systemSettings[key]
+	if !ok {
+		return nil, ErrSettingNotFound
+	}
+
+	settings := s.defaults()
+	decoder := json.NewDecoder(bytes.NewReader(value))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(setting.value(&settings)); err != nil {
+		return nil, &validation.FieldError{Field: "value", Rule: "json", Message: fmt.Sprintf("invalid %s: %v", key, err)}
+	}
+	if err := setting.check(&settings); err != nil {
+		return nil, err
+	}
+	normalized, err := json.Marshal(setting.value(&settings))
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.load(ctx); err != nil {
+		return nil, err
+	}
+	err = s.repo.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.SaveSystemSetting(ctx, &repository.SystemSetting{Key: key, Value: string(normalized), UpdatedByID: &userID}); err != nil {
+			return err
+		}
+		newValue := string(normalized)
+		return s.repo.CreateSystemSettingChange(ctx, &repository.SystemSettingChange{
+			Key: key, OldValue: s.storedValue(key), NewValue: &newValue, ChangedByID: &userID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.load(ctx); err != nil {
+		return nil, err
+	}
+	return s.view(key)
+}
+
+// Reset removes the override of a setting so its default applies again
+func (s *SystemSettingService) Reset(ctx context.Context, key string, userID uint) (*SystemSettingView, error) {
+	if _, ok := systemSettings[key]; !ok {
+		return nil, ErrSettingNotFound
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.load(ctx); err != nil {
+		return nil, err
+	}
+	if _, ok := s.stored[key]; ok {
+		err := s.repo.InTransaction(ctx, func(ctx context.Context) error {
+			if err := s.repo.DeleteSystemSetting(ctx, key); err != nil {
+				return err
+			}
+			return s.repo.CreateSystemSettingChange(ctx, &repository.SystemSettingChange{
+				Key: key, OldValue: s.storedValue(key), ChangedByID: &userID,
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := s.load(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return s.view(key)
+}
+
+func (s *SystemSettingService) storedValue(key string) *string {
+	if row, ok := s.stored[key]; ok {
+		return &row.Value
+	}
+	return nil
+}
+
+// Changes returns the latest changes, of one setting unless key is empty
+func (s *SystemSettingService) Changes(ctx context.Context, key string, limit int) ([]repository.SystemSettingChange, error) {
+	if key != "" {
+		if _, ok := systemSettings[key]; !ok {
+			return nil, ErrSettingNotFound
+		}
+	}
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	return s.repo.GetSystemSettingChanges(ctx, key, limit)
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"taxifleet/backend/internal/config"
+	"taxifleet/backend/internal/permissions"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+	"taxifleet/backend/internal/validation"
+
+	"go.uber.org/mock/gomock"
+)
+
+type systemSettingRepoMock struct {
+	*mocks.MockTransactor
+	*mocks.MockSystemSettingRepo
+}
+
+func newSystemSettingServiceMock(t *testing.T) (*SystemSettingService, systemSettingRepoMock) {
+	ctrl := gomock.NewController(t)
+	repo := systemSettingRepoMock{
+		MockTransactor:        mocks.NewMockTransactor(ctrl),
+		MockSystemSettingRepo: mocks.NewMockSystemSettingRepo(ctrl),
+	}
+	cfg := &config.Config{
+		Permissions: config.PermissionsConfig{Admin: 0xFFFFFFFF, Owner: 0xFFFF, Manager: 0x7, Mechanic: 0x11, Driver: 0x3},
+		Security:    config.SecurityConfig{RateLimitRPS: 10, RateLimitBurst: 20},
+	}
+	admin, owner, manager, mechanic, driver := permissions.PermissionAdmin, permissions.PermissionOwner, permissions.PermissionManager, permissions.PermissionMechanic, permissions.PermissionDriver
+	t.Cleanup(func() { permissions.SetPermissionMasks(admin, owner, manager, mechanic, driver) })
+	return NewSystemSettingService(repo, cfg, nil), repo
+}
+
+func TestSetSystemSettingRecordsChange(t *testing.T) {
+	svc, repo := newSystemSettingServiceMock(t)
+	stored := []repository.SystemSetting{{Key: SettingRateLimit, Value: `{"rps":10,"burst":20}`}}
+	gomock.InOrder(
+		repo.MockSystemSettingRepo.EXPECT().GetSystemSettings(gomock.Any()).Return(stored, nil),
+		repo.MockSystemSettingRepo.EXPECT().GetSystemSettings(gomock.Any()).Return([]repository.SystemSetting{{Key: SettingRateLimit, Value: `{"rps":5,"burst":20}`}}, nil),
+	)
+	repo.MockTransactor.EXPECT().InTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	})
+	repo.MockSystemSettingRepo.EXPECT().SaveSystemSetting(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, setting *repository.SystemSetting) error {
+		if setting.Value != `{"rps":5,"burst":20,"exports_per_hour":0,"api_key_requests_per_hour":0}` || *setting.UpdatedByID != 1 {
+			t.Fatalf("expected the partial value merged into the default, got %+v", setting)
+		}
+		return nil
+	})
+	repo.MockSystemSettingRepo.EXPECT().CreateSystemSettingChange(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, change *repository.SystemSettingChange) error {
+		if *change.OldValue != `{"rps":10,"burst":20}` || *change.NewValue != `{"rps":5,"burst":20,"exports_per_hour":0,"api_key_requests_per_hour":0}` {
+			t.Fatalf("unexpected change %+v", change)
+		}
+		return nil
+	})
+
+	view, err := svc.Set(context.Background(), SettingRateLimit, json.RawMessage(`{"rps": 5}`), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !view.Overridden || svc.Current(context.Background()).RateLimit.RPS != 5 {
+		t.Fatalf("expected the new rate limit in effect, got %s", view.Value)
+	}
+}
+
+func TestSetSystemSettingValidates(t *testing.T) {
+	svc, _ := newSystemSettingServiceMock(t)
+
+	var fieldErr *validation.FieldError
+	if _, err := svc.Set(context.Background(), SettingRateLimit, json.RawMessage(`{"rps": 50}`), 1); !errors.As(err, &fieldErr) {
+		t.Fatalf("expected a burst below rps refused, got %v", err)
+	}
+	if _, err := svc.Set(context.Background(), SettingRateLimit, json.RawMessage(`{"rpm": 50}`), 1); !errors.As(err, &fieldErr) {
+		t.Fatalf("expected an unknown field refused, got %v", err)
+	}
+	if _, err := svc.Set(context.Background(), "bcrypt_cost", json.RawMessage(`4`), 1); !errors.Is(err, ErrSettingNotFound) {
+		t.Fatalf("expected ErrSettingNotFound, got %v", err)
+	}
+}
+
+func TestLoadAppliesPermissionMasks(t *testing.T) {
+	svc, repo := newSystemSettingServiceMock(t)
+	repo.MockSystemSettingRepo.EXPECT().GetSystemSettings(gomock.Any()).Return([]repository.SystemSetting{
+		{Key: SettingPermissionMasks, Value: `{"owner":65535,"manager":7,"mechanic":17,"driver":259}`},
+	}, nil)
+
+	if err := svc.Load(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if permissions.PermissionDriver != 259 || permissions.PermissionOwner != 0xFFFF {
+		t.Fatalf("expected the stored driver mask applied, got %d", permissions.PermissionDriver)
+	}
+}
@@ -1,127 +1,366 @@
 package service
 
 import (
+	"cmp"
+	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"taxifleet/backend/internal/cache"
 	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/validation"
 )
 
+// ErrLicensePlateTaken is returned when another taxi of the tenant has the same plate
+var ErrLicensePlateTaken = errors.New("a taxi with this license plate already exists")
+
+// TaxiRepository is the data access TaxiService depends on
+type TaxiRepository interface {
+	repository.Transactor
+	repository.TaxiRepo
+	repository.UserRepo
+	repository.AssignmentRepo
+	repository.TenantRepo
+	repository.PlanRepo
+	repository.TaxiLedgerRepo
+	repository.AuditRepo
+}
+
 type TaxiService struct {
-	repo *repository.Repository
+	repo  TaxiRepository
+	cache cache.Cache
 }
 
-func NewTaxiService(repo *repository.Repository) *TaxiService {
-	return &TaxiService{repo: repo}
+func NewTaxiService(repo TaxiRepository, cache cache.Cache) *TaxiService {
+	return &TaxiService{repo: repo, cache: cache}
 }
 
 type CreateTaxiRequest struct {
-	LicensePlate    string `json:"license_plate" binding:"required"`
-	Model           string `json:"model"`
-	Year            int    `json:"year"`
-	Color           string `json:"color"`
-	VIN             string `json:"vin"`
-	Status          string `json:"status"`
+	LicensePlate     string `json:"license_plate" binding:"required,license_plate"`
+	Model            string `json:"model"`
+	Year             int    `json:"year"`
+	Color            string `json:"color"`
+	VIN              string `json:"vin" binding:"omitempty,vin"`
+	Status           string `json:"status"`
+	Mileage          int    `json:"mileage" binding:"min=0"`
 	AssignedDriverID *uint  `json:"assigned_driver_id"`
+
+	EarningsSplit *repository.EarningsSplit `json:"earnings_split"`
 }
 
+// UpdateTaxiRequest changes the fields that are set; an empty model, color or
+// VIN clears it
 type UpdateTaxiRequest struct {
-	LicensePlate    string `json:"license_plate"`
-	Model           string `json:"model"`
-	Year            int    `json:"year"`
-	Color           string `json:"color"`
-	VIN             string `json:"vin"`
-	Status          string `json:"status"`
-	AssignedDriverID *uint  `json:"assigned_driver_id"`
+	LicensePlate string  `json:"license_plate" binding:"omitempty,license_plate"`
+	Model        *string `json:"model"`
+	Year         *int    `json:"year"`
+	Color        *string `json:"color"`
+	VIN          *string `json:"vin"`
+	Status       string  `json:"status"`
+	Mileage      *int    `json:"mileage" binding:"omitempty,min=0"`
+	Version      int     `json:"version"` // Version the edit is based on; 0 skips the check
+
+	// AssignedDriverID assigns another driver; 0 unassigns the taxi
+	AssignedDriverID *uint `json:"assigned_driver_id"`
+
+	// EarningsSplit replaces the taxi's rule; one with an empty type removes
+	// it so the tenant's rule applies
+	EarningsSplit *repository.EarningsSplit `json:"earnings_split"`
+}
+
+// normalizePlate returns the plate in its stored form after checking it
+// against the format of the tenant's country
+func normalizePlate(tenant *repository.Tenant, licensePlate string) (string, error) {
+	if err := validation.CheckLicensePlate(parseTenantSettings(tenant.Settings).Country, licensePlate); err != nil {
+		return "", err
+	}
+	return validation.NormalizeLicensePlate(licensePlate), nil
+}
+
+func (s *TaxiService) ensureUniquePlate(ctx context.Context, tenantID uint, licensePlate string, excludeID uint) error {
+	exists, err := s.repo.LicensePlateExists(ctx, tenantID, licensePlate, excludeID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrLicensePlateTaken
+	}
+	return nil
 }
 
-func (s *TaxiService) Create(tenantID uint, req CreateTaxiRequest) (*repository.Taxi, error) {
+// ensureTenantDriver verifies the driver to assign belongs to the tenant
+func (s *TaxiService) ensureTenantDriver(ctx context.Context, tenantID uint, driverID *uint) error {
+	if driverID == nil {
+		return nil
+	}
+	driver, err := s.repo.GetUserByID(ctx, *driverID)
+	if err != nil || driver.TenantID != tenantID {
+		return notFound("driver not found")
+	}
+	return nil
+}
+
+func sameDriver(a, b *uint) bool {
+	return a == nil && b == nil || a != nil && b != nil && *a == *b
+}
+
+func (s *TaxiService) Create(ctx context.Context, tenantID uint, userID uint, req CreateTaxiRequest) (*repository.Taxi, error) {
+	tenant, err := s.repo.GetTenantByID(ctx, tenantID)
+	if err != nil {
+		return nil, notFound("tenant not found")
+	}
+	if err := checkPlanLimit(ctx, s.repo, tenant, taxiLimit); err != nil {
+		return nil, err
+	}
+
+	plate, err := normalizePlate(tenant, req.LicensePlate)
+	if err != nil {
+		return nil, err
+	}
+	req.LicensePlate = plate
+
+	if err := s.ensureUniquePlate(ctx, tenantID, req.LicensePlate, 0); err != nil {
+		return nil, err
+	}
+	if err := s.ensureTenantDriver(ctx, tenantID, req.AssignedDriverID); err != nil {
+		return nil, err
+	}
+	if err := checkEarningsSplit(req.EarningsSplit); err != nil {
+		return nil, err
+	}
+
 	taxi := &repository.Taxi{
-		TenantID:        tenantID,
-		LicensePlate:    req.LicensePlate,
-		Model:           req.Model,
-		Year:            req.Year,
-		Color:           req.Color,
-		VIN:             req.VIN,
-		Status:          req.Status,
+		TenantID:         tenantID,
+		LicensePlate:     req.LicensePlate,
+		Model:            req.Model,
+		Year:             req.Year,
+		Color:            req.Color,
+		VIN:              strings.ToUpper(req.VIN),
+		Status:           req.Status,
+		Mileage:          req.Mileage,
 		AssignedDriverID: req.AssignedDriverID,
+		EarningsSplit:    req.EarningsSplit,
 	}
 
 	if taxi.Status == "" {
 		taxi.Status = "active"
 	}
 
-	if err := s.repo.CreateTaxi(taxi); err != nil {
+	if err := s.repo.CreateTaxi(ctx, taxi); err != nil {
+		// Concurrent creates can still race past the check; the index catches them
+		if repository.IsUniqueViolation(err) {
+			return nil, ErrLicensePlateTaken
+		}
 		return nil, err
 	}
 
-	return s.repo.GetTaxiByID(taxi.ID)
+	if taxi.AssignedDriverID != nil {
+		if err := s.repo.RecordAssignment(ctx, tenantID, taxi.ID, taxi.AssignedDriverID, &userID, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.CreateAuditEvent(ctx, auditEvent(tenantID, userID, ActionTaxiCreated, "taxi", taxi.ID, fmt.Sprintf("Taxi %s added", taxi.LicensePlate))); err != nil {
+		return nil, err
+	}
+
+	s.cache.Invalidate(ctx, tenantID)
+
+	return s.repo.GetTaxiByID(ctx, taxi.ID)
 }
 
-func (s *TaxiService) GetByID(id uint, tenantID uint) (*repository.Taxi, error) {
-	taxi, err := s.repo.GetTaxiByID(id)
+func (s *TaxiService) GetByID(ctx context.Context, id uint, tenantID uint) (*repository.Taxi, error) {
+	taxi, err := s.repo.GetTaxiByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	if taxi.TenantID != tenantID {
-		return nil, errors.New("taxi not found")
+		return nil, notFound("taxi not found")
 	}
 
 	return taxi, nil
 }
 
-func (s *TaxiService) List(tenantID uint) ([]repository.Taxi, error) {
-	return s.repo.GetTaxisByTenant(tenantID)
+// taxiSortKeys are the fields the taxi list can be sorted by
+var taxiSortKeys = sortKeys[repository.Taxi]{
+	"id":            func(a, b repository.Taxi) int { return cmp.Compare(a.ID, b.ID) },
+	"license_plate": func(a, b repository.Taxi) int { return cmp.Compare(a.LicensePlate, b.LicensePlate) },
+	"model":         func(a, b repository.Taxi) int { return cmp.Compare(a.Model, b.Model) },
+	"year":          func(a, b repository.Taxi) int { return cmp.Compare(a.Year, b.Year) },
+	"status":        func(a, b repository.Taxi) int { return cmp.Compare(a.Status, b.Status) },
+	"mileage":       func(a, b repository.Taxi) int { return cmp.Compare(a.Mileage, b.Mileage) },
+	"created_at":    func(a, b repository.Taxi) int { return a.CreatedAt.Compare(b.CreatedAt) },
 }
 
-func (s *TaxiService) Update(id uint, tenantID uint, req UpdateTaxiRequest) (*repository.Taxi, error) {
-	taxi, err := s.repo.GetTaxiByID(id)
+// List returns the tenant's taxis, those matching opts.Query if set, in the
+// order of opts.Sort
+func (s *TaxiService) List(ctx context.Context, tenantID uint, opts ListOptions) ([]repository.Taxi, error) {
+	var taxis []repository.Taxi
+	var err error
+	if opts.Query != "" {
+		taxis, err = s.repo.SearchTaxis(ctx, tenantID, opts.Query, 0)
+	} else {
+		taxis, err = s.repo.GetTaxisByTenant(ctx, tenantID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return sortList(taxis, opts.Sort, taxiSortKeys)
+}
+
+// ListVersion identifies the current state of the tenant's taxi list
+func (s *TaxiService) ListVersion(ctx context.Context, tenantID uint) (*repository.ListVersion, error) {
+	return s.repo.GetTaxiListVersion(ctx, tenantID)
+}
+
+// Count counts the tenant's taxis, only those with the status when set
+func (s *TaxiService) Count(ctx context.Context, tenantID uint, status string) (int64, error) {
+	return s.repo.CountTaxis(ctx, tenantID, status)
+}
+
+func (s *TaxiService) Update(ctx context.Context, id uint, tenantID uint, userID uint, req UpdateTaxiRequest) (*repository.Taxi, error) {
+	taxi, err := s.repo.GetTaxiByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	if taxi.TenantID != tenantID {
-		return nil, errors.New("taxi not found")
+		return nil, notFound("taxi not found")
+	}
+
+	if req.Version != 0 && req.Version != taxi.Version {
+		return nil, repository.ErrVersionConflict
 	}
 
 	if req.LicensePlate != "" {
+		tenant, err := s.repo.GetTenantByID(ctx, tenantID)
+		if err != nil {
+			return nil, notFound("tenant not found")
+		}
+		if req.LicensePlate, err = normalizePlate(tenant, req.LicensePlate); err != nil {
+			return nil, err
+		}
+	}
+	if req.LicensePlate != "" && req.LicensePlate != taxi.LicensePlate {
+		if err := s.ensureUniquePlate(ctx, tenantID, req.LicensePlate, taxi.ID); err != nil {
+			return nil, err
+		}
 		taxi.LicensePlate = req.LicensePlate
 	}
-	if req.Model != "" {
-		taxi.Model = req.Model
+	if req.Model != nil {
+		taxi.Model = *req.Model
 	}
-	if req.Year != 0 {
-		taxi.Year = req.Year
+	if req.Year != nil {
+		taxi.Year = *req.Year
 	}
-	if req.Color != "" {
-		taxi.Color = req.Color
+	if req.Color != nil {
+		taxi.Color = *req.Color
 	}
-	if req.VIN != "" {
-		taxi.VIN = req.VIN
+	if req.VIN != nil {
+		if *req.VIN != "" && !validation.IsVIN(*req.VIN) {
+			return nil, &validation.FieldError{Field: "vin", Rule: "vin", Message: fmt.Sprintf("%s is not a valid VIN", *req.VIN)}
+		}
+		taxi.VIN = strings.ToUpper(*req.VIN)
 	}
 	if req.Status != "" {
 		taxi.Status = req.Status
 	}
+	if req.EarningsSplit != nil {
+		if req.EarningsSplit.Type == "" {
+			taxi.EarningsSplit = nil
+		} else if err := checkEarningsSplit(req.EarningsSplit); err != nil {
+			return nil, err
+		} else {
+			taxi.EarningsSplit = req.EarningsSplit
+		}
+	}
+	if req.Mileage != nil {
+		if *req.Mileage < taxi.Mileage {
+			return nil, invalid("mileage cannot decrease")
+		}
+		taxi.Mileage = *req.Mileage
+	}
+	driverChanged := false
 	if req.AssignedDriverID != nil {
-		taxi.AssignedDriverID = req.AssignedDriverID
+		driverID := req.AssignedDriverID
+		if *driverID == 0 {
+			driverID = nil
+		}
+		if !sameDriver(taxi.AssignedDriverID, driverID) {
+			if err := s.ensureTenantDriver(ctx, tenantID, driverID); err != nil {
+				return nil, err
+			}
+			taxi.AssignedDriverID = driverID
+			taxi.AssignedDriver = nil // Otherwise saving the stale association restores the old ID
+			driverChanged = true
+		}
 	}
 
-	if err := s.repo.UpdateTaxi(taxi); err != nil {
+	if err := s.repo.UpdateTaxi(ctx, taxi); err != nil {
+		if repository.IsUniqueViolation(err) {
+			return nil, ErrLicensePlateTaken
+		}
 		return nil, err
 	}
 
-	return s.repo.GetTaxiByID(taxi.ID)
+	if driverChanged {
+		if err := s.repo.RecordAssignment(ctx, tenantID, taxi.ID, taxi.AssignedDriverID, &userID, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	s.cache.Invalidate(ctx, tenantID)
+
+	return s.repo.GetTaxiByID(ctx, taxi.ID)
 }
 
-func (s *TaxiService) Delete(id uint, tenantID uint) error {
-	taxi, err := s.repo.GetTaxiByID(id)
+func (s *TaxiService) Delete(ctx context.Context, id uint, tenantID uint) error {
+	taxi, err := s.repo.GetTaxiByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
 	if taxi.TenantID != tenantID {
-		return errors.New("taxi not found")
+		return notFound("taxi not found")
 	}
 
-	return s.repo.DeleteTaxi(id)
+	if err := s.repo.DeleteTaxi(ctx, id); err != nil {
+		return err
+	}
+
+	s.cache.Invalidate(ctx, tenantID)
+	return nil
 }
 
+// taxiStatuses are the statuses a taxi can be set to in bulk
+var taxiStatuses = []string{"active", "maintenance", "inactive"}
+
+// BulkStatusRequest sets the status of several taxis
+type BulkStatusRequest struct {
+	IDs    []uint `json:"ids" binding:"required"`
+	Status string `json:"status" binding:"required"`
+}
+
+// BulkStatus sets the status of the taxis, all or none
+func (s *TaxiService) BulkStatus(ctx context.Context, ids []uint, status string, tenantID uint, userID uint) (*BulkResult, error) {
+	if !slices.Contains(taxiStatuses, status) {
+		return nil, &validation.FieldError{Field: "status", Rule: "oneof", Message: "status must be one of " + strings.Join(taxiStatuses, ", ")}
+	}
+	return runBulk(ctx, s.repo, ids, func(ctx context.Context, id uint) error {
+		_, err := s.Update(ctx, id, tenantID, userID, UpdateTaxiRequest{Status: status})
+		return err
+	})
+}
+
+// GetAssignments returns the driver assignment timeline of the taxi, newest first
+func (s *TaxiService) GetAssignments(ctx context.Context, id uint, tenantID uint) ([]repository.Assignment, error) {
+	if _, err := s.GetByID(ctx, id, tenantID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetAssignmentsByTaxi(ctx, id)
+}
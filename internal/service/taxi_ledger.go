@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"taxifleet/backend/internal/money"
+)
+
+// TaxiLedgerEntry is a movement of a taxi's cash. Amount is positive for cash
+// coming in and negative for cash going out.
+type TaxiLedgerEntry struct {
+	Date        time.Time    `json:"date"`
+	Type        string       `json:"type"` // earnings, expense, adjustment, deposit
+	Description string       `json:"description"`
+	Amount      money.Amount `json:"amount"`
+	Balance     money.Amount `json:"balance"` // Cash in hand after this entry
+	SourceID    uint         `json:"source_id"`
+}
+
+// TaxiLedger is the cash a taxi has collected and not yet banked, with the
+// entries it is made of, oldest first
+type TaxiLedger struct {
+	TaxiID     uint              `json:"taxi_id"`
+	CashInHand money.Amount      `json:"cash_in_hand"`
+	Entries    []TaxiLedgerEntry `json:"entries"`
+}
+
+// Ledger chains the approved earnings of a taxi with the expenses paid and
+// adjustments made from them and the deposits of its cash into a running
+// balance
+func (s *TaxiService) Ledger(ctx context.Context, id uint, tenantID uint) (*TaxiLedger, error) {
+	if _, err := s.GetByID(ctx, id, tenantID); err != nil {
+		return nil, err
+	}
+
+	reports, err := s.repo.GetApprovedReportsByTaxi(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	expenses, err := s.repo.GetTaxiCashExpenses(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	deposits, err := s.repo.GetDepositsByTaxi(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []TaxiLedgerEntry{}
+	for _, report := range reports {
+		week := report.WeekStartDate.Format("2006-01-02")
+		entries = append(entries, TaxiLedgerEntry{
+			Date:        report.WeekStartDate,
+			Type:        "earnings",
+			Description: fmt.Sprintf("Earnings for the week of %s", week),
+			Amount:      report.Earnings,
+			SourceID:    report.ID,
+		})
+		if report.TotalAdjustments != 0 {
+			entries = append(entries, TaxiLedgerEntry{
+				Date:        report.WeekStartDate,
+				Type:        "adjustment",
+				Description: fmt.Sprintf("Adjustments for the week of %s", week),
+				Amount:      -report.TotalAdjustments,
+				SourceID:    report.ID,
+			})
+		}
+	}
+	for _, expense := range expenses {
+		entries = append(entries, TaxiLedgerEntry{
+			Date:        expense.Date,
+			Type:        "expense",
+			Description: expense.Category,
+			Amount:      -expense.Amount,
+			SourceID:    expense.ID,
+		})
+	}
+	for _, deposit := range deposits {
+		entries = append(entries, TaxiLedgerEntry{
+			Date:        deposit.DepositDate,
+			Type:        "deposit",
+			Description: "Bank deposit",
+			Amount:      -deposit.Amount,
+			SourceID:    deposit.ID,
+		})
+	}
+
+	// Entries dated alike keep the order above: cash comes in before it is
+	// spent or banked
+	slices.SortStableFunc(entries, func(a, b TaxiLedgerEntry) int { return a.Date.Compare(b.Date) })
+
+	ledger := &TaxiLedger{TaxiID: id, Entries: entries}
+	for i := range entries {
+		ledger.CashInHand += entries[i].Amount
+		entries[i].Balance = ledger.CashInHand
+	}
+	return ledger, nil
+}
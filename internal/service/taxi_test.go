@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"taxifleet/backend/internal/cache"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+	"taxifleet/backend/internal/validation"
+
+	"go.uber.org/mock/gomock"
+)
+
+type taxiRepoMock struct {
+	*mocks.MockTransactor
+	*mocks.MockTaxiRepo
+	*mocks.MockUserRepo
+	*mocks.MockAssignmentRepo
+	*mocks.MockTenantRepo
+	*mocks.MockPlanRepo
+	*mocks.MockTaxiLedgerRepo
+	*mocks.MockAuditRepo
+}
+
+func newTaxiServiceMock(t *testing.T) (*TaxiService, taxiRepoMock) {
+	ctrl := gomock.NewController(t)
+	repo := taxiRepoMock{
+		MockTransactor:     mocks.NewMockTransactor(ctrl),
+		MockTaxiRepo:       mocks.NewMockTaxiRepo(ctrl),
+		MockUserRepo:       mocks.NewMockUserRepo(ctrl),
+		MockAssignmentRepo: mocks.NewMockAssignmentRepo(ctrl),
+		MockTenantRepo:     mocks.NewMockTenantRepo(ctrl),
+		MockPlanRepo:       mocks.NewMockPlanRepo(ctrl),
+		MockTaxiLedgerRepo: mocks.NewMockTaxiLedgerRepo(ctrl),
+		MockAuditRepo:      mocks.NewMockAuditRepo(ctrl),
+	}
+	return NewTaxiService(repo, cache.Noop{}), repo
+}
+
+// expectTenant stubs the tenant lookup used for the plate format check
+func (r taxiRepoMock) expectTenant(settings string) {
+	r.MockTenantRepo.EXPECT().GetTenantByID(gomock.Any(), uint(1)).Return(&repository.Tenant{ID: 1, Settings: settings}, nil)
+}
+
+func TestTaxiCreateRejectsDuplicatePlate(t *testing.T) {
+	svc, repo := newTaxiServiceMock(t)
+	repo.expectTenant("{}")
+	repo.MockTaxiRepo.EXPECT().LicensePlateExists(gomock.Any(), uint(1), "AB-123", uint(0)).Return(true, nil)
+
+	_, err := svc.Create(context.Background(), 1, 10, CreateTaxiRequest{LicensePlate: "AB-123"})
+	if !errors.Is(err, ErrLicensePlateTaken) {
+		t.Fatalf("expected ErrLicensePlateTaken, got %v", err)
+	}
+}
+
+func TestTaxiCreateEnforcesPlanLimit(t *testing.T) {
+	svc, repo := newTaxiServiceMock(t)
+	planID, maxTaxis := uint(2), 5
+	repo.MockTenantRepo.EXPECT().GetTenantByID(gomock.Any(), uint(1)).Return(&repository.Tenant{ID: 1, Settings: "{}", PlanID: &planID}, nil)
+	repo.MockPlanRepo.EXPECT().GetPlanByID(gomock.Any(), planID).Return(&repository.Plan{ID: 2, Name: "Starter", MaxTaxis: &maxTaxis}, nil)
+	repo.MockPlanRepo.EXPECT().GetPlanUsage(gomock.Any(), uint(1), gomock.Any()).Return(&repository.PlanUsage{TaxiCount: 5}, nil)
+
+	_, err := svc.Create(context.Background(), 1, 10, CreateTaxiRequest{LicensePlate: "AB-123"})
+	if !errors.Is(err, ErrPlanLimitReached) {
+		t.Fatalf("expected ErrPlanLimitReached, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "Starter plan allows 5 taxis") {
+		t.Errorf("expected the limit in the message, got %q", err)
+	}
+}
+
+func TestTaxiCreateRecordsAssignment(t *testing.T) {
+	svc, repo := newTaxiServiceMock(t)
+	driverID := uint(7)
+
+	repo.expectTenant("{}")
+	repo.MockTaxiRepo.EXPECT().LicensePlateExists(gomock.Any(), uint(1), "AB-123", uint(0)).Return(false, nil)
+	repo.MockUserRepo.EXPECT().GetUserByID(gomock.Any(), driverID).Return(&repository.User{ID: driverID, TenantID: 1}, nil)
+	repo.MockTaxiRepo.EXPECT().CreateTaxi(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, taxi *repository.Taxi) error {
+		if taxi.Status != "active" {
+			t.Errorf("expected default status active, got %q", taxi.Status)
+		}
+		taxi.ID = 5
+		return nil
+	})
+	repo.MockAssignmentRepo.EXPECT().RecordAssignment(gomock.Any(), uint(1), uint(5), &driverID, gomock.Any(), gomock.Any()).Return(nil)
+	repo.MockAuditRepo.EXPECT().CreateAuditEvent(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, event *repository.AuditEvent) error {
+		if event.Action != ActionTaxiCreated || event.EntityID != 5 || *event.ActorID != 10 {
+			t.Errorf("unexpected audit event %+v", event)
+		}
+		return nil
+	})
+	repo.MockTaxiRepo.EXPECT().GetTaxiByID(gomock.Any(), uint(5)).Return(&repository.Taxi{ID: 5, TenantID: 1}, nil)
+
+	taxi, err := svc.Create(context.Background(), 1, 10, CreateTaxiRequest{LicensePlate: "AB-123", AssignedDriverID: &driverID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if taxi.ID != 5 {
+		t.Fatalf("expected taxi 5, got %d", taxi.ID)
+	}
+}
+
+func TestTaxiCreateRejectsDriverOfAnotherTenant(t *testing.T) {
+	svc, repo := newTaxiServiceMock(t)
+	driverID := uint(7)
+
+	repo.expectTenant("{}")
+	repo.MockTaxiRepo.EXPECT().LicensePlateExists(gomock.Any(), uint(1), "AB-123", uint(0)).Return(false, nil)
+	repo.MockUserRepo.EXPECT().GetUserByID(gomock.Any(), driverID).Return(&repository.User{ID: driverID, TenantID: 2}, nil)
+
+	if _, err := svc.Create(context.Background(), 1, 10, CreateTaxiRequest{LicensePlate: "AB-123", AssignedDriverID: &driverID}); err == nil {
+		t.Fatal("expected an error for a driver of another tenant")
+	}
+}
+
+func TestTaxiUpdateRejectsDecreasingMileage(t *testing.T) {
+	svc, repo := newTaxiServiceMock(t)
+	repo.MockTaxiRepo.EXPECT().GetTaxiByID(gomock.Any(), uint(5)).Return(&repository.Taxi{ID: 5, TenantID: 1, Mileage: 1000}, nil)
+
+	mileage := 900
+	if _, err := svc.Update(context.Background(), 5, 1, 10, UpdateTaxiRequest{Mileage: &mileage}); err == nil {
+		t.Fatal("expected an error when mileage decreases")
+	}
+}
+
+func TestTaxiUpdateUnassignsDriverAndClearsFields(t *testing.T) {
+	svc, repo := newTaxiServiceMock(t)
+	driverID := uint(7)
+	taxi := &repository.Taxi{ID: 5, TenantID: 1, Year: 2019, Color: "red", AssignedDriverID: &driverID}
+	repo.MockTaxiRepo.EXPECT().GetTaxiByID(gomock.Any(), uint(5)).Return(taxi, nil).Times(2)
+	repo.MockTaxiRepo.EXPECT().UpdateTaxi(gomock.Any(), taxi).Return(nil)
+	repo.MockAssignmentRepo.EXPECT().RecordAssignment(gomock.Any(), uint(1), uint(5), gomock.Nil(), gomock.Any(), gomock.Any()).Return(nil)
+
+	color, unassigned := "", uint(0)
+	if _, err := svc.Update(context.Background(), 5, 1, 10, UpdateTaxiRequest{Color: &color, AssignedDriverID: &unassigned}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if taxi.Color != "" || taxi.Year != 2019 || taxi.AssignedDriverID != nil {
+		t.Fatalf("expected the color cleared and the driver unassigned, got %+v", taxi)
+	}
+}
+
+func TestTaxiGetByIDHidesOtherTenants(t *testing.T) {
+	svc, repo := newTaxiServiceMock(t)
+	repo.MockTaxiRepo.EXPECT().GetTaxiByID(gomock.Any(), uint(5)).Return(&repository.Taxi{ID: 5, TenantID: 2}, nil)
+
+	if _, err := svc.GetByID(context.Background(), 5, 1); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected taxi of another tenant to be hidden, got %v", err)
+	}
+}
+
+func TestTaxiUpdateRejectsStaleVersion(t *testing.T) {
+	svc, repo := newTaxiServiceMock(t)
+	repo.MockTaxiRepo.EXPECT().GetTaxiByID(gomock.Any(), uint(5)).Return(&repository.Taxi{ID: 5, TenantID: 1, Version: 3}, nil)
+
+	model := "Corolla"
+	_, err := svc.Update(context.Background(), 5, 1, 10, UpdateTaxiRequest{Model: &model, Version: 2})
+	if !errors.Is(err, repository.ErrVersionConflict) {
+		t.Fatalf("expected version conflict, got %v", err)
+	}
+}
+
+func TestTaxiCreateChecksTenantPlateFormat(t *testing.T) {
+	svc, repo := newTaxiServiceMock(t)
+	repo.expectTenant(`{"country":"FR"}`)
+
+	_, err := svc.Create(context.Background(), 1, 10, CreateTaxiRequest{LicensePlate: "1234 AB 01"})
+	var fieldErr *validation.FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Field != "license_plate" {
+		t.Fatalf("expected a license_plate field error, got %v", err)
+	}
+}
+
+func TestTaxiCreateNormalizesPlate(t *testing.T) {
+	svc, repo := newTaxiServiceMock(t)
+	repo.expectTenant(`{"country":"FR"}`)
+	repo.MockTaxiRepo.EXPECT().LicensePlateExists(gomock.Any(), uint(1), "AB-123-CD", uint(0)).Return(true, nil)
+
+	_, err := svc.Create(context.Background(), 1, 10, CreateTaxiRequest{LicensePlate: " ab-123-cd"})
+	if !errors.Is(err, ErrLicensePlateTaken) {
+		t.Fatalf("expected ErrLicensePlateTaken, got %v", err)
+	}
+}
+
+func TestTaxiLedgerRunningBalance(t *testing.T) {
+	svc, repo := newTaxiServiceMock(t)
+	week := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	repo.MockTaxiRepo.EXPECT().GetTaxiByID(gomock.Any(), uint(5)).Return(&repository.Taxi{ID: 5, TenantID: 1}, nil)
+	repo.MockTaxiLedgerRepo.EXPECT().GetApprovedReportsByTaxi(gomock.Any(), uint(5)).Return([]repository.WeeklyReport{
+		{ID: 1, WeekStartDate: week, Earnings: 800_00, TotalAdjustments: 50_00},
+	}, nil)
+	repo.MockTaxiLedgerRepo.EXPECT().GetTaxiCashExpenses(gomock.Any(), uint(5)).Return([]repository.Expense{
+		{ID: 2, Date: week.AddDate(0, 0, 2), Category: "fuel", Amount: 60_50},
+	}, nil)
+	repo.MockTaxiLedgerRepo.EXPECT().GetDepositsByTaxi(gomock.Any(), uint(5)).Return([]repository.BankDeposit{
+		{ID: 3, DepositDate: week.AddDate(0, 0, 1), Amount: 500_00},
+	}, nil)
+
+	ledger, err := svc.Ledger(context.Background(), 5, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var types []string
+	for _, entry := range ledger.Entries {
+		types = append(types, entry.Type)
+	}
+	if strings.Join(types, ",") != "earnings,adjustment,deposit,expense" {
+		t.Fatalf("expected entries in date order, got %v", types)
+	}
+	if ledger.Entries[2].Balance != 250_00 || ledger.CashInHand != 189_50 {
+		t.Fatalf("expected balance 250 after the deposit and 189.5 in hand, got %+v", ledger)
+	}
+}
+
+func TestTaxiBulkStatusValidatesStatus(t *testing.T) {
+	svc, _ := newTaxiServiceMock(t)
+
+	var fieldErr *validation.FieldError
+	if _, err := svc.BulkStatus(context.Background(), []uint{1, 2}, "scrapped", 1, 3); !errors.As(err, &fieldErr) || fieldErr.Field != "status" {
+		t.Fatalf("expected a status field error, got %v", err)
+	}
+}
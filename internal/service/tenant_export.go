@@ -0,0 +1,381 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+
+	"taxifleet/backend/internal/config"
+	"taxifleet/backend/internal/export"
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/storage"
+)
+
+// ErrExportNotFound is returned for an unknown or expired export token
+var ErrExportNotFound = errors.New("export not found or expired")
+
+var exportTokenPattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// TenantExportRepository is the data access TenantExportService depends on
+type TenantExportRepository interface {
+	repository.TenantRepo
+	repository.UserRepo
+	repository.TaxiRepo
+	repository.ReportRepo
+	repository.ReportAttachmentRepo
+	repository.ExpenseRepo
+	repository.DepositRepo
+	repository.MaintenanceRepo
+}
+
+// TenantExportService assembles all data of a tenant into a ZIP archive that
+// can be downloaded for a limited time, for data portability and offboarding
+type TenantExportService struct {
+	repo  TenantExportRepository
+	files storage.Store
+	dir   string
+	ttl   time.Duration
+}
+
+func NewTenantExportService(repo TenantExportRepository, files storage.Store, cfg config.DataExportConfig) *TenantExportService {
+	return &TenantExportService{repo: repo, files: files, dir: cfg.Dir, ttl: cfg.TTL}
+}
+
+// TenantExport points to a generated archive
+type TenantExport struct {
+	TenantID    uint      `json:"tenant_id"`
+	Token       string    `json:"token"`
+	DownloadURL string    `json:"download_url"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// exportTable is one entity of the archive, written as <name>.json and <name>.csv
+type exportTable struct {
+	name    string
+	records interface{}
+	csv     export.Section
+}
+
+// exportFile is a stored file copied into the archive
+type exportFile struct {
+	name string // Path in the archive
+	key  string // Key in storage
+}
+
+// Export writes the tenant's users, taxis, reports, expenses, deposits,
+// maintenance logs, report attachments and uploaded file references to a new
+// archive
+func (s *TenantExportService) Export(ctx context.Context, tenantID uint) (*TenantExport, error) {
+	tenant, err := s.repo.GetTenantByID(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	tables, files, err := s.collect(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := newExportToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.writeArchive(ctx, tenantID, token, tables, files); err != nil {
+		return nil, err
+	}
+
+	return &TenantExport{
+		TenantID:    tenantID,
+		Token:       token,
+		DownloadURL: "/api/v1/admin/exports/" + token,
+		ExpiresAt:   time.Now().Add(s.ttl),
+	}, nil
+}
+
+// Open returns the path of a downloadable archive
+func (s *TenantExportService) Open(token string) (string, error) {
+	if !exportTokenPattern.MatchString(token) {
+		return "", ErrExportNotFound
+	}
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*-"+token+".zip"))
+	if err != nil || len(paths) != 1 {
+		return "", ErrExportNotFound
+	}
+	info, err := os.Stat(paths[0])
+	if err != nil || time.Since(info.ModTime()) > s.ttl {
+		return "", ErrExportNotFound
+	}
+	return paths[0], nil
+}
+
+// PurgeTenant removes all archives of a tenant, including unexpired ones
+func (s *TenantExportService) PurgeTenant(tenantID uint) error {
+	paths, err := filepath.Glob(filepath.Join(s.dir, fmt.Sprintf("%d-*", tenantID)))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Cleanup removes expired archives and returns how many were removed
+func (s *TenantExportService) Cleanup(ctx context.Context) (int, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return removed, ctx.Err()
+		}
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() || time.Since(info.ModTime()) <= s.ttl {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.dir, entry.Name())); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+func (s *TenantExportService) collect(ctx context.Context, tenant *repository.Tenant) ([]exportTable, []exportFile, error) {
+	users, err := s.repo.GetUsersByTenant(ctx, tenant.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	taxis, err := s.repo.GetTaxisByTenant(ctx, tenant.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	reports, err := s.repo.GetReportsByTenant(ctx, tenant.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	archived, err := s.repo.GetArchivedReports(ctx, tenant.ID, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	reports = append(reports, archived...)
+	attachments, err := s.repo.GetReportAttachmentsByTenant(ctx, tenant.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	expenses, err := s.repo.GetExpensesByTenant(ctx, tenant.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	deposits, err := s.repo.GetDepositsByTenant(ctx, tenant.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	logs, err := s.repo.GetMaintenanceLogsByTenant(ctx, tenant.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tenantTable := exportTable{name: "tenant", records: tenant, csv: export.Section{
+		Headers: []string{"id", "name", "subdomain", "settings", "created_at"},
+		Rows:    [][]interface{}{{tenant.ID, tenant.Name, tenant.Subdomain, tenant.Settings, formatTime(tenant.CreatedAt)}},
+	}}
+
+	userTable := exportTable{name: "users", records: users, csv: export.Section{
+		Headers: []string{"id", "email", "first_name", "last_name", "phone", "permission", "active", "created_at"},
+	}}
+	for _, u := range users {
+		userTable.csv.Rows = append(userTable.csv.Rows, []interface{}{u.ID, u.Email, u.FirstName, u.LastName, u.Phone, u.Permission, u.Active, formatTime(u.CreatedAt)})
+	}
+
+	taxiTable := exportTable{name: "taxis", records: taxis, csv: export.Section{
+		Headers: []string{"id", "license_plate", "model", "year", "color", "vin", "status", "mileage", "assigned_driver_id", "created_at"},
+	}}
+	for _, t := range taxis {
+		taxiTable.csv.Rows = append(taxiTable.csv.Rows, []interface{}{t.ID, t.LicensePlate, t.Model, t.Year, t.Color, t.VIN, t.Status, t.Mileage, optionalID(t.AssignedDriverID), formatTime(t.CreatedAt)})
+	}
+
+	reportTable := exportTable{name: "reports", records: reports, csv: export.Section{
+		Headers: []string{"id", "taxi_id", "driver_id", "week_start_date", "earnings", "total_expenses", "total_adjustments", "driver_share", "owner_share", "status", "notes", "submitted_at", "approved_at", "approved_by_id"},
+	}}
+	for _, r := range reports {
+		reportTable.csv.Rows = append(reportTable.csv.Rows, []interface{}{r.ID, r.TaxiID, r.DriverID, r.WeekStartDate.Format("2006-01-02"), r.Earnings, r.TotalExpenses, r.TotalAdjustments, optionalAmount(r.DriverShare), optionalAmount(r.OwnerShare), r.Status, r.Notes, formatOptionalTime(r.SubmittedAt), formatOptionalTime(r.ApprovedAt), optionalID(r.ApprovedByID)})
+	}
+
+	// Report attachments are copied into the archive. Receipts and deposit
+	// proofs are stored by other services, so the archive lists their URLs.
+	uploadTable := exportTable{name: "uploads", csv: export.Section{
+		Headers: []string{"record", "record_id", "file", "url", "note"},
+	}}
+	var uploads []map[string]interface{}
+	addUpload := func(record string, id uint, file, url, note string) {
+		uploads = append(uploads, map[string]interface{}{"record": record, "record_id": id, "file": file, "url": url, "note": note})
+		uploadTable.csv.Rows = append(uploadTable.csv.Rows, []interface{}{record, id, file, url, note})
+	}
+
+	var files []exportFile
+	for i := range attachments {
+		attachment := &attachments[i]
+		if err := downloadable(attachment); err != nil {
+			addUpload("report", attachment.ReportID, "", "", err.Error())
+			continue
+		}
+		name := path.Join("attachments", strconv.FormatUint(uint64(attachment.ReportID), 10), fmt.Sprintf("%d-%s", attachment.ID, attachment.FileName))
+		files = append(files, exportFile{name: name, key: attachment.StoragePath})
+		addUpload("report", attachment.ReportID, name, "", "")
+	}
+
+	expenseTable := exportTable{name: "expenses", records: expenses, csv: export.Section{
+		Headers: []string{"id", "report_id", "taxi_id", "category", "amount", "reason", "receipt_url", "date", "created_by_id"},
+	}}
+	for _, e := range expenses {
+		expenseTable.csv.Rows = append(expenseTable.csv.Rows, []interface{}{e.ID, optionalID(e.ReportID), optionalID(e.TaxiID), e.Category, e.Amount, e.Reason, e.ReceiptURL, e.Date.Format("2006-01-02"), e.CreatedByID})
+		if e.ReceiptURL != "" {
+			addUpload("expense", e.ID, "", e.ReceiptURL, "")
+		}
+	}
+
+	depositTable := exportTable{name: "deposits", records: deposits, csv: export.Section{
+		Headers: []string{"id", "taxi_id", "amount", "deposit_date", "period_start", "period_end", "bank_account_id", "bank_account", "proof_url", "status", "notes"},
+	}}
+	for _, d := range deposits {
+		depositTable.csv.Rows = append(depositTable.csv.Rows, []interface{}{d.ID, optionalID(d.TaxiID), d.Amount, d.DepositDate.Format("2006-01-02"), d.PeriodStart.Format("2006-01-02"), d.PeriodEnd.Format("2006-01-02"), optionalID(d.BankAccountID), d.BankAccount, d.ProofURL, d.Status, d.Notes})
+		if d.ProofURL != "" {
+			addUpload("deposit", d.ID, "", d.ProofURL, "")
+		}
+	}
+
+	logTable := exportTable{name: "maintenance_logs", records: logs, csv: export.Section{
+		Headers: []string{"id", "taxi_id", "description", "cost", "date", "mechanic_id"},
+	}}
+	for _, l := range logs {
+		logTable.csv.Rows = append(logTable.csv.Rows, []interface{}{l.ID, l.TaxiID, l.Description, l.Cost, l.Date.Format("2006-01-02"), optionalID(l.MechanicID)})
+	}
+
+	uploadTable.records = uploads
+	return []exportTable{tenantTable, userTable, taxiTable, reportTable, expenseTable, depositTable, logTable, uploadTable}, files, nil
+}
+
+// writeArchive writes the tables and files to <tenant ID>-<token>.zip,
+// renaming it into place once complete so a download never sees a partial
+// archive
+func (s *TenantExportService) writeArchive(ctx context.Context, tenantID uint, token string, tables []exportTable, files []exportFile) (err error) {
+	if err := os.MkdirAll(s.dir, 0o750); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%d-%s", tenantID, token)
+	tmp, err := os.CreateTemp(s.dir, name+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	zw := zip.NewWriter(tmp)
+	for _, table := range tables {
+		w, err := zw.Create(table.name + ".json")
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(table.records); err != nil {
+			return fmt.Errorf("encode %s: %w", table.name, err)
+		}
+
+		w, err = zw.Create(table.name + ".csv")
+		if err != nil {
+			return err
+		}
+		if err := export.WriteCSV(w, table.csv); err != nil {
+			return fmt.Errorf("write %s: %w", table.name, err)
+		}
+	}
+	for _, file := range files {
+		if err := s.addFile(ctx, zw, file); err != nil {
+			return fmt.Errorf("%s: %w", file.name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(s.dir, name+".zip"))
+}
+
+// addFile copies a stored file into the archive
+func (s *TenantExportService) addFile(ctx context.Context, zw *zip.Writer, file exportFile) error {
+	r, err := s.files.Open(ctx, file.key)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	// Photos and PDFs are already compressed
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: file.name, Method: zip.Store, Modified: time.Now()})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, r)
+	return err
+}
+
+func newExportToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func optionalID(id *uint) interface{} {
+	if id == nil {
+		return nil
+	}
+	return *id
+}
+
+func optionalAmount(amount *money.Amount) interface{} {
+	if amount == nil {
+		return nil
+	}
+	return *amount
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return formatTime(*t)
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"taxifleet/backend/internal/config"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+	"taxifleet/backend/internal/storage"
+
+	"go.uber.org/mock/gomock"
+)
+
+type tenantExportRepoMock struct {
+	*mocks.MockTenantRepo
+	*mocks.MockUserRepo
+	*mocks.MockTaxiRepo
+	*mocks.MockReportRepo
+	*mocks.MockReportAttachmentRepo
+	*mocks.MockExpenseRepo
+	*mocks.MockDepositRepo
+	*mocks.MockMaintenanceRepo
+}
+
+func TestTenantExportCopiesReportAttachments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := tenantExportRepoMock{
+		MockTenantRepo:           mocks.NewMockTenantRepo(ctrl),
+		MockUserRepo:             mocks.NewMockUserRepo(ctrl),
+		MockTaxiRepo:             mocks.NewMockTaxiRepo(ctrl),
+		MockReportRepo:           mocks.NewMockReportRepo(ctrl),
+		MockReportAttachmentRepo: mocks.NewMockReportAttachmentRepo(ctrl),
+		MockExpenseRepo:          mocks.NewMockExpenseRepo(ctrl),
+		MockDepositRepo:          mocks.NewMockDepositRepo(ctrl),
+		MockMaintenanceRepo:      mocks.NewMockMaintenanceRepo(ctrl),
+	}
+	files := storage.NewLocal(t.TempDir())
+	dir := t.TempDir()
+	svc := NewTenantExportService(repo, files, config.DataExportConfig{Dir: dir, TTL: time.Hour})
+	ctx := context.Background()
+
+	if err := files.Put(ctx, "reports/4/photo.jpg", strings.NewReader("jpeg bytes"), "image/jpeg"); err != nil {
+		t.Fatalf("failed to store the photo: %v", err)
+	}
+	repo.MockTenantRepo.EXPECT().GetTenantByID(ctx, uint(1)).Return(&repository.Tenant{ID: 1, Name: "Fleet"}, nil)
+	repo.MockUserRepo.EXPECT().GetUsersByTenant(ctx, uint(1)).Return(nil, nil)
+	repo.MockTaxiRepo.EXPECT().GetTaxisByTenant(ctx, uint(1)).Return(nil, nil)
+	repo.MockReportRepo.EXPECT().GetReportsByTenant(ctx, uint(1)).Return([]repository.WeeklyReport{{ID: 4, TenantID: 1}}, nil)
+	repo.MockReportRepo.EXPECT().GetArchivedReports(ctx, uint(1), uint(0)).Return(nil, nil)
+	repo.MockReportAttachmentRepo.EXPECT().GetReportAttachmentsByTenant(ctx, uint(1)).Return([]repository.ReportAttachment{
+		{ID: 7, TenantID: 1, ReportID: 4, FileName: "photo.jpg", StoragePath: "reports/4/photo.jpg", ScanStatus: repository.ScanClean},
+		{ID: 8, TenantID: 1, ReportID: 4, FileName: "virus.pdf", StoragePath: "reports/4/virus.pdf", ScanStatus: repository.ScanQuarantined},
+	}, nil)
+	repo.MockExpenseRepo.EXPECT().GetExpensesByTenant(ctx, uint(1)).Return([]repository.Expense{{ID: 2, ReceiptURL: "https://receipts.example.com/2"}}, nil)
+	repo.MockDepositRepo.EXPECT().GetDepositsByTenant(ctx, uint(1)).Return(nil, nil)
+	repo.MockMaintenanceRepo.EXPECT().GetMaintenanceLogsByTenant(ctx, uint(1)).Return(nil, nil)
+
+	exported, err := svc.Export(ctx, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	path, err := svc.Open(exported.Token)
+	if err != nil {
+		t.Fatalf("failed to open the archive: %v", err)
+	}
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatalf("failed to read the archive: %v", err)
+	}
+	defer archive.Close()
+	entries := make(map[string]string)
+	for _, f := range archive.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", f.Name, err)
+		}
+		data, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", f.Name, err)
+		}
+		entries[f.Name] = string(data)
+	}
+
+	if entries["attachments/4/7-photo.jpg"] != "jpeg bytes" {
+		t.Errorf("expected the photo copied into the archive, got entries %v", entries)
+	}
+	rows, err := csv.NewReader(strings.NewReader(entries["uploads.csv"])).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to parse uploads.csv: %v", err)
+	}
+	want := [][]string{
+		{"record", "record_id", "file", "url", "note"},
+		{"report", "4", "attachments/4/7-photo.jpg", "", ""},
+		{"report", "4", "", "", ErrAttachmentQuarantined.Error()},
+		{"expense", "2", "", "https://receipts.example.com/2", ""},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("expected %d rows in uploads.csv, got %q", len(want), rows)
+	}
+	for i := range want {
+		if strings.Join(rows[i], ",") != strings.Join(want[i], ",") {
+			t.Errorf("row %d: expected %q, got %q", i, want[i], rows[i])
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"taxifleet/backend/internal/money"
+	"taxifleet/backend/internal/permissions"
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/validation"
+)
+
+// TripRepository is the data access TripService depends on
+type TripRepository interface {
+	repository.TripRepo
+	repository.TaxiRepo
+	repository.TenantRepo
+}
+
+// TripService stores the rides logged by the driver app and rolls them up
+// into the earnings of weekly reports
+type TripService struct {
+	repo TripRepository
+}
+
+func NewTripService(repo TripRepository) *TripService {
+	return &TripService{repo: repo}
+}
+
+type TripInput struct {
+	TaxiID     uint         `json:"taxi_id" binding:"required"`
+	StartedAt  time.Time    `json:"started_at" binding:"required"`
+	EndedAt    time.Time    `json:"ended_at" binding:"required"`
+	Fare       money.Amount `json:"fare" binding:"min=0"`
+	DistanceKm float64      `json:"distance_km" binding:"min=0"`
+}
+
+// LogTripsRequest is a batch of trips the driver app uploads at once, e.g.
+// after being offline
+type LogTripsRequest struct {
+	Trips []TripInput `json:"trips" binding:"required,min=1,max=500,dive"`
+}
+
+// EarningsSuggestion is what a driver's trips with a taxi add up to over a
+// reporting week, to prefill the report's earnings
+type EarningsSuggestion struct {
+	TaxiID        uint      `json:"taxi_id"`
+	DriverID      uint      `json:"driver_id"`
+	WeekStartDate time.Time `json:"week_start_date"`
+	repository.TripTotals
+	Earnings money.Amount `json:"earnings"`
+}
+
+// LogTrips stores a batch of the driver's trips and returns how many were
+// stored. The batch is refused as a whole if any trip is invalid.
+func (s *TripService) LogTrips(ctx context.Context, tenantID uint, driverID uint, req LogTripsRequest) (int, error) {
+	checked := make(map[uint]bool)
+	trips := make([]repository.Trip, 0, len(req.Trips))
+	for i, input := range req.Trips {
+		if !checked[input.TaxiID] {
+			taxi, err := s.repo.GetTaxiByID(ctx, input.TaxiID)
+			if err != nil || taxi.TenantID != tenantID {
+				return 0, &validation.FieldError{Field: fmt.Sprintf("trips[%d].taxi_id", i), Rule: "taxi", Message: "taxi not found"}
+			}
+			checked[input.TaxiID] = true
+		}
+		if input.EndedAt.Before(input.StartedAt) {
+			return 0, &validation.FieldError{Field: fmt.Sprintf("trips[%d].ended_at", i), Rule: "gtefield", Message: "ended_at must not be before started_at"}
+		}
+
+		trips = append(trips, repository.Trip{
+			TenantID:   tenantID,
+			TaxiID:     input.TaxiID,
+			DriverID:   driverID,
+			StartedAt:  input.StartedAt,
+			EndedAt:    input.EndedAt,
+			Fare:       input.Fare,
+			DistanceKm: input.DistanceKm,
+		})
+	}
+
+	if err := s.repo.CreateTrips(ctx, trips); err != nil {
+		return 0, err
+	}
+	return len(trips), nil
+}
+
+// List returns the trips started between the from and to dates (inclusive,
+// by default the last 12 weeks). Drivers only see their own.
+func (s *TripService) List(ctx context.Context, tenantID uint, userID uint, permission int, from, to string) ([]repository.Trip, error) {
+	period, err := parsePeriod(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	driverID := uint(0)
+	if permission == permissions.PermissionDriver {
+		driverID = userID
+	}
+	return s.repo.GetTrips(ctx, tenantID, driverID, period.From, period.To.AddDate(0, 0, 1))
+}
+
+// SuggestEarnings totals the driver's trips with the taxi over the reporting
+// week containing date
+func (s *TripService) SuggestEarnings(ctx context.Context, tenantID uint, driverID uint, taxiID uint, date time.Time) (*EarningsSuggestion, error) {
+	taxi, err := s.repo.GetTaxiByID(ctx, taxiID)
+	if err != nil || taxi.TenantID != tenantID {
+		return nil, notFound("taxi not found")
+	}
+	tenant, err := s.repo.GetTenantByID(ctx, tenantID)
+	if err != nil {
+		return nil, notFound("tenant not found")
+	}
+
+	weekStart := startOfWeek(date, parseTenantSettings(tenant.Settings).WeekStart())
+	totals, err := s.repo.SumTrips(ctx, taxiID, driverID, weekStart, weekStart.AddDate(0, 0, 7))
+	if err != nil {
+		return nil, err
+	}
+
+	return &EarningsSuggestion{
+		TaxiID:        taxiID,
+		DriverID:      driverID,
+		WeekStartDate: weekStart,
+		TripTotals:    *totals,
+		Earnings:      totals.Fare,
+	}, nil
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"taxifleet/backend/internal/repository"
+	"taxifleet/backend/internal/repository/mocks"
+	"taxifleet/backend/internal/validation"
+
+	"go.uber.org/mock/gomock"
+)
+
+type tripRepoMock struct {
+	*mocks.MockTripRepo
+	*mocks.MockTaxiRepo
+	*mocks.MockTenantRepo
+}
+
+func newTripServiceMock(t *testing.T) (*TripService, tripRepoMock) {
+	ctrl := gomock.NewController(t)
+	repo := tripRepoMock{
+		MockTripRepo:   mocks.NewMockTripRepo(ctrl),
+		MockTaxiRepo:   mocks.NewMockTaxiRepo(ctrl),
+		MockTenantRepo: mocks.NewMockTenantRepo(ctrl),
+	}
+	return NewTripService(repo), repo
+}
+
+func TestLogTripsRejectsBatchWithForeignTaxi(t *testing.T) {
+	svc, repo := newTripServiceMock(t)
+	start := time.Date(2024, 3, 5, 8, 0, 0, 0, time.UTC)
+	repo.MockTaxiRepo.EXPECT().GetTaxiByID(gomock.Any(), uint(5)).Return(&repository.Taxi{ID: 5, TenantID: 1}, nil)
+	repo.MockTaxiRepo.EXPECT().GetTaxiByID(gomock.Any(), uint(6)).Return(&repository.Taxi{ID: 6, TenantID: 2}, nil)
+
+	_, err := svc.LogTrips(context.Background(), 1, 7, LogTripsRequest{Trips: []TripInput{
+		{TaxiID: 5, StartedAt: start, EndedAt: start.Add(20 * time.Minute), Fare: 18_50},
+		{TaxiID: 5, StartedAt: start.Add(time.Hour), EndedAt: start.Add(80 * time.Minute), Fare: 12_00},
+		{TaxiID: 6, StartedAt: start.Add(2 * time.Hour), EndedAt: start.Add(150 * time.Minute), Fare: 30_00},
+	}})
+	var fieldErr *validation.FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Field != "trips[2].taxi_id" {
+		t.Fatalf("expected a trips[2].taxi_id field error, got %v", err)
+	}
+}
+
+func TestSuggestEarningsTotalsTenantWeek(t *testing.T) {
+	svc, repo := newTripServiceMock(t)
+	sunday := time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC)
+
+	repo.MockTaxiRepo.EXPECT().GetTaxiByID(gomock.Any(), uint(5)).Return(&repository.Taxi{ID: 5, TenantID: 1}, nil)
+	repo.MockTenantRepo.EXPECT().GetTenantByID(gomock.Any(), uint(1)).Return(&repository.Tenant{ID: 1, Settings: `{"week_start_day":"sunday"}`}, nil)
+	repo.MockTripRepo.EXPECT().SumTrips(gomock.Any(), uint(5), uint(7), sunday, sunday.AddDate(0, 0, 7)).
+		Return(&repository.TripTotals{Trips: 3, Fare: 60_50, DistanceKm: 42}, nil)
+
+	suggestion, err := svc.SuggestEarnings(context.Background(), 1, 7, 5, time.Date(2024, 3, 6, 15, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !suggestion.WeekStartDate.Equal(sunday) || suggestion.Earnings != 60_50 || suggestion.Trips != 3 {
+		t.Fatalf("unexpected suggestion %+v", suggestion)
+	}
+}
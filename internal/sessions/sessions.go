@@ -0,0 +1,121 @@
+// Package sessions provides a Redis-backed store for refresh token sessions,
+// shared by all API instances. The Postgres store is the repository itself;
+// both implement repository.SessionRepo and are selected with SESSION_STORE.
+package sessions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"taxifleet/backend/internal/repository"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// Redis stores each session under its token and expires it with the session.
+// A per-user set of tokens allows signing a user out everywhere.
+type Redis struct {
+	client *redis.Client
+}
+
+var _ repository.SessionRepo = (*Redis)(nil)
+
+// NewRedis returns a store on the client, which stays owned by the caller
+func NewRedis(client *redis.Client) *Redis {
+	return &Redis{client: client}
+}
+
+func sessionKey(token string) string {
+	return "session:" + token
+}
+
+func userSessionsKey(userID uint) string {
+	return fmt.Sprintf("user:%d:sessions", userID)
+}
+
+func (r *Redis) CreateSession(ctx context.Context, session *repository.Session) error {
+	ttl := time.Until(session.ExpiresAt)
+	if ttl <= 0 {
+		return fmt.Errorf("session already expired")
+	}
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = time.Now()
+	}
+
+	stored := *session
+	stored.User = repository.User{}
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+
+	userKey := userSessionsKey(session.UserID)
+	pipe := r.client.TxPipeline()
+	pipe.Set(ctx, sessionKey(session.Token), data, ttl)
+	pipe.SAdd(ctx, userKey, session.Token)
+	// The index lives as long as the user's newest session
+	pipe.ExpireGT(ctx, userKey, ttl)
+	pipe.ExpireNX(ctx, userKey, ttl)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// GetSessionByToken returns gorm.ErrRecordNotFound for unknown or expired
+// tokens, like the Postgres store
+func (r *Redis) GetSessionByToken(ctx context.Context, token string) (*repository.Session, error) {
+	data, err := r.client.Get(ctx, sessionKey(token)).Bytes()
+	if err == redis.Nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var session repository.Session
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, err
+	}
+	if !session.ExpiresAt.After(time.Now()) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &session, nil
+}
+
+func (r *Redis) DeleteSession(ctx context.Context, token string) error {
+	session, err := r.GetSessionByToken(ctx, token)
+	if err == gorm.ErrRecordNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	pipe := r.client.TxPipeline()
+	pipe.Del(ctx, sessionKey(token))
+	pipe.SRem(ctx, userSessionsKey(session.UserID), token)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (r *Redis) DeleteUserSessions(ctx context.Context, userID uint) error {
+	userKey := userSessionsKey(userID)
+	tokens, err := r.client.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return err
+	}
+
+	keys := []string{userKey}
+	for _, token := range tokens {
+		keys = append(keys, sessionKey(token))
+	}
+	return r.client.Del(ctx, keys...).Err()
+}
+
+// DeleteStaleSessions removes nothing: Redis expires sessions by itself, and
+// logged out sessions are deleted right away
+func (r *Redis) DeleteStaleSessions(ctx context.Context, now time.Time) (int64, error) {
+	return 0, nil
+}
@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// tempSuffix ends the names of files still being written
+const tempSuffix = ".tmp"
+
+// Local keeps files in a directory on disk. It cannot presign URLs; its files
+// are streamed by the API.
+type Local struct {
+	dir string
+}
+
+var _ Store = (*Local)(nil)
+
+func NewLocal(dir string) *Local {
+	return &Local{dir: dir}
+}
+
+// path returns where the key's file lives, refusing keys that leave the
+// directory
+func (l *Local) path(key string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(key))
+	if clean == "." || filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", errors.New("invalid storage key " + key)
+	}
+	return filepath.Join(l.dir, clean), nil
+}
+
+// Put writes the file to a temporary file next to its place and renames it
+// into place once complete
+func (l *Local) Put(ctx context.Context, key string, file io.Reader, contentType string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "upload-*"+tempSuffix)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, file)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (l *Local) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotExist
+	}
+	return file, err
+}
+
+func (l *Local) Delete(ctx context.Context, key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// DeletePrefix removes the directory the prefix names, such as a tenant's
+func (l *Local) DeletePrefix(ctx context.Context, prefix string) error {
+	path, err := l.path(prefix)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(path)
+}
+
+// Walk calls fn for the files under the directory the prefix names, skipping
+// those still being written
+func (l *Local) Walk(ctx context.Context, prefix string, fn func(key string) error) error {
+	path, err := l.path(prefix)
+	if err != nil {
+		return err
+	}
+	err = filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || strings.HasSuffix(file, tempSuffix) {
+			return ctx.Err()
+		}
+		key, err := filepath.Rel(l.dir, file)
+		if err != nil {
+			return err
+		}
+		return fn(filepath.ToSlash(key))
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
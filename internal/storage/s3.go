@@ -0,0 +1,311 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"taxifleet/backend/internal/config"
+)
+
+// S3 keeps files in Amazon S3 or a compatible service such as MinIO, talking
+// to its REST API with Signature Version 4. A key <tenant ID>/<name> is the
+// object <prefix><tenant ID>/<name> of the bucket, or with tenant buckets the
+// object <prefix><name> of the bucket <bucket>-<tenant ID>.
+type S3 struct {
+	endpoint      *url.URL
+	region        string
+	bucket        string
+	prefix        string
+	accessKey     string
+	secretKey     string
+	pathStyle     bool
+	tenantBuckets bool
+	client        *http.Client
+	now           func() time.Time
+}
+
+var (
+	_ Store     = (*S3)(nil)
+	_ Presigner = (*S3)(nil)
+)
+
+// NewS3 creates an S3 store; without an endpoint it uses AWS in the region
+func NewS3(cfg config.StorageConfig) (*S3, error) {
+	endpoint := cfg.S3Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", cfg.S3Region)
+	}
+	parsed, err := url.Parse(strings.TrimSuffix(endpoint, "/"))
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", endpoint)
+	}
+
+	return &S3{
+		endpoint:      parsed,
+		region:        cfg.S3Region,
+		bucket:        cfg.S3Bucket,
+		prefix:        cfg.S3Prefix,
+		accessKey:     cfg.S3AccessKey,
+		secretKey:     cfg.S3SecretKey,
+		pathStyle:     cfg.S3PathStyle,
+		tenantBuckets: cfg.S3TenantBuckets,
+		client:        &http.Client{Timeout: 60 * time.Second},
+		now:           time.Now,
+	}, nil
+}
+
+// object returns the bucket and object key of a key
+func (s *S3) object(key string) (bucket, objectKey string) {
+	if !s.tenantBuckets {
+		return s.bucket, s.prefix + key
+	}
+	tenant, name, _ := strings.Cut(key, "/")
+	return s.bucket + "-" + tenant, s.prefix + name
+}
+
+// url returns the URL of an object, or of the bucket for an empty object key
+func (s *S3) url(bucket, objectKey string, query url.Values) *url.URL {
+	u := *s.endpoint
+	path := "/" + objectKey
+	if s.pathStyle {
+		path = "/" + bucket + path
+	} else {
+		u.Host = bucket + "." + u.Host
+	}
+	u.Path = path
+	u.RawPath = uriEncode(path, false)
+	u.RawQuery = canonicalQuery(query)
+	return &u
+}
+
+// do sends a signed request and returns its response, turning error
+// statuses into *s3Error
+func (s *S3) do(ctx context.Context, method string, u *url.URL, body []byte, header http.Header) (*http.Response, error) {
+	var reader io.Reader
+	if len(body) > 0 {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reader)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	s.sign(req, body)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3 request failed: %w", err)
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		defer resp.Body.Close()
+		return nil, readS3Error(resp)
+	}
+	return resp, nil
+}
+
+// s3Error is an error response of S3
+type s3Error struct {
+	Status  int    `xml:"-"`
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+func (e *s3Error) Error() string {
+	return fmt.Sprintf("s3 returned %d %s: %s", e.Status, e.Code, e.Message)
+}
+
+func readS3Error(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	s3Err := &s3Error{Status: resp.StatusCode}
+	if xml.Unmarshal(body, s3Err) != nil || s3Err.Code == "" {
+		s3Err.Code = http.StatusText(resp.StatusCode)
+	}
+	return s3Err
+}
+
+func isS3Error(err error, code string) bool {
+	var s3Err *s3Error
+	return errors.As(err, &s3Err) && s3Err.Code == code
+}
+
+// Put uploads the file once it has been read in full, creating a tenant's
+// bucket on its first file
+func (s *S3) Put(ctx context.Context, key string, file io.Reader, contentType string) error {
+	body, err := io.ReadAll(file)
+	if err != nil {
+		return err
+	}
+
+	bucket, objectKey := s.object(key)
+	header := http.Header{"Content-Type": {contentType}}
+	resp, err := s.do(ctx, http.MethodPut, s.url(bucket, objectKey, nil), body, header)
+	if isS3Error(err, "NoSuchBucket") && s.tenantBuckets {
+		if err := s.createBucket(ctx, bucket); err != nil {
+			return err
+		}
+		resp, err = s.do(ctx, http.MethodPut, s.url(bucket, objectKey, nil), body, header)
+	}
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (s *S3) createBucket(ctx context.Context, bucket string) error {
+	var body []byte
+	// us-east-1 is the default location, which must not be named
+	if s.region != "" && s.region != "us-east-1" {
+		body = []byte(`<CreateBucketConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><LocationConstraint>` + s.region + `</LocationConstraint></CreateBucketConfiguration>`)
+	}
+	resp, err := s.do(ctx, http.MethodPut, s.url(bucket, "", nil), body, nil)
+	if isS3Error(err, "BucketAlreadyOwnedByYou") {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (s *S3) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	bucket, objectKey := s.object(key)
+	resp, err := s.do(ctx, http.MethodGet, s.url(bucket, objectKey, nil), nil, nil)
+	if isS3Error(err, "NoSuchKey") || isS3Error(err, "NoSuchBucket") {
+		return nil, ErrNotExist
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+	bucket, objectKey := s.object(key)
+	resp, err := s.do(ctx, http.MethodDelete, s.url(bucket, objectKey, nil), nil, nil)
+	if isS3Error(err, "NoSuchBucket") {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// DeletePrefix removes the objects one by one. With tenant buckets, removing
+// all of a tenant's files removes its bucket too.
+func (s *S3) DeletePrefix(ctx context.Context, prefix string) error {
+	err := s.Walk(ctx, prefix, func(key string) error {
+		return s.Delete(ctx, key)
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, name, _ := strings.Cut(prefix, "/"); !s.tenantBuckets || name != "" {
+		return nil
+	}
+	bucket, _ := s.object(prefix)
+	resp, err := s.do(ctx, http.MethodDelete, s.url(bucket, "", nil), nil, nil)
+	// A bucket also holding other objects under another prefix is kept
+	if isS3Error(err, "NoSuchBucket") || isS3Error(err, "BucketNotEmpty") {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// listBucketResult is a page of ListObjectsV2
+type listBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// Walk lists the objects page by page
+func (s *S3) Walk(ctx context.Context, prefix string, fn func(key string) error) error {
+	bucket, objectPrefix := s.object(prefix)
+	// A tenant bucket's object keys leave out the tenant
+	keyPrefix := ""
+	if s.tenantBuckets {
+		tenant, _, _ := strings.Cut(prefix, "/")
+		keyPrefix = tenant + "/"
+	}
+
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {objectPrefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		resp, err := s.do(ctx, http.MethodGet, s.url(bucket, "", query), nil, nil)
+		if isS3Error(err, "NoSuchBucket") {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var page listBucketResult
+		err = xml.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to decode s3 object list: %w", err)
+		}
+
+		for _, object := range page.Contents {
+			if err := fn(keyPrefix + strings.TrimPrefix(object.Key, s.prefix)); err != nil {
+				return err
+			}
+		}
+		if !page.IsTruncated {
+			return nil
+		}
+		token = page.NextContinuationToken
+	}
+}
+
+// PresignGet returns a URL downloading the object without credentials until
+// the TTL passes, served with the download's name and content type
+func (s *S3) PresignGet(ctx context.Context, key string, ttl time.Duration, download Download) (string, error) {
+	now := s.now().UTC()
+	query := url.Values{
+		"X-Amz-Algorithm":     {signingAlgorithm},
+		"X-Amz-Credential":    {s.accessKey + "/" + s.scope(now)},
+		"X-Amz-Date":          {now.Format(amzDateFormat)},
+		"X-Amz-Expires":       {strconv.Itoa(int(ttl.Seconds()))},
+		"X-Amz-SignedHeaders": {"host"},
+	}
+	if download.ContentType != "" {
+		query.Set("response-content-type", download.ContentType)
+	}
+	if download.FileName != "" {
+		disposition := "attachment"
+		if download.Inline {
+			disposition = "inline"
+		}
+		query.Set("response-content-disposition", mime.FormatMediaType(disposition, map[string]string{"filename": download.FileName}))
+	}
+
+	bucket, objectKey := s.object(key)
+	u := s.url(bucket, objectKey, query)
+	header := http.Header{"Host": {u.Host}}
+	request := canonicalRequest(http.MethodGet, u, header, []string{"host"}, unsignedPayload)
+	query.Set("X-Amz-Signature", s.signature(now, request))
+	u.RawQuery = canonicalQuery(query)
+	return u.String(), nil
+}
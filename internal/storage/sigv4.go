@@ -0,0 +1,124 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	signingAlgorithm = "AWS4-HMAC-SHA256"
+	amzDateFormat    = "20060102T150405Z"
+	unsignedPayload  = "UNSIGNED-PAYLOAD"
+)
+
+// sign adds the Signature Version 4 authorization of the request and its body
+func (s *S3) sign(req *http.Request, body []byte) {
+	now := s.now().UTC()
+	payloadHash := sha256Hex(body)
+	req.Header.Set("X-Amz-Date", now.Format(amzDateFormat))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	header := req.Header.Clone()
+	header.Set("Host", req.URL.Host)
+	signed := make([]string, 0, len(header))
+	for name := range header {
+		signed = append(signed, strings.ToLower(name))
+	}
+	sort.Strings(signed)
+
+	request := canonicalRequest(req.Method, req.URL, header, signed, payloadHash)
+	req.Header.Set("Authorization", signingAlgorithm+
+		" Credential="+s.accessKey+"/"+s.scope(now)+
+		", SignedHeaders="+strings.Join(signed, ";")+
+		", Signature="+s.signature(now, request))
+}
+
+// scope is the date, region and service the signature is valid for
+func (s *S3) scope(now time.Time) string {
+	return now.Format("20060102") + "/" + s.region + "/s3/aws4_request"
+}
+
+// signature signs the canonical request with a key derived from the secret
+// key for the scope
+func (s *S3) signature(now time.Time, canonicalRequest string) string {
+	stringToSign := signingAlgorithm + "\n" +
+		now.Format(amzDateFormat) + "\n" +
+		s.scope(now) + "\n" +
+		sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), now.Format("20060102"))
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// canonicalRequest is the form of a request that is signed, covering the
+// signed headers, named in lower case and sorted
+func canonicalRequest(method string, u *url.URL, header http.Header, signed []string, payloadHash string) string {
+	var headers strings.Builder
+	for _, name := range signed {
+		headers.WriteString(name + ":" + strings.TrimSpace(strings.Join(header.Values(name), ",")) + "\n")
+	}
+	return method + "\n" +
+		uriEncode(u.Path, false) + "\n" +
+		u.RawQuery + "\n" +
+		headers.String() + "\n" +
+		strings.Join(signed, ";") + "\n" +
+		payloadHash
+}
+
+// canonicalQuery encodes the query sorted by name, as signatures need
+func canonicalQuery(query url.Values) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var parts []string
+	for _, name := range names {
+		for _, value := range query[name] {
+			parts = append(parts, uriEncode(name, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode percent-encodes all but the unreserved characters, and slashes
+// unless encodeSlash
+func uriEncode(s string, encodeSlash bool) string {
+	const hexDigits = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			b.WriteByte('%')
+			b.WriteByte(hexDigits[c>>4])
+			b.WriteByte(hexDigits[c&15])
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
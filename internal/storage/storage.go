@@ -0,0 +1,58 @@
+// Package storage keeps uploaded files, such as report attachments, under
+// keys of the form <tenant ID>/<name>. Stores that can hand out short-lived
+// URLs let clients download files without going through the API.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"taxifleet/backend/internal/config"
+)
+
+// ErrNotExist is returned for a key without a file
+var ErrNotExist = errors.New("file does not exist")
+
+// Store keeps files under keys
+type Store interface {
+	// Put writes the file under the key. A failed write leaves nothing
+	// behind, so the reader may end with an error to abort the upload.
+	Put(ctx context.Context, key string, file io.Reader, contentType string) error
+	// Open returns the file under the key, or ErrNotExist
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the file under the key; a missing file is no error
+	Delete(ctx context.Context, key string) error
+	// DeletePrefix removes every file whose key starts with the prefix
+	DeletePrefix(ctx context.Context, prefix string) error
+	// Walk calls fn with the key of every file whose key starts with the
+	// prefix, stopping at the first error
+	Walk(ctx context.Context, prefix string, fn func(key string) error) error
+}
+
+// New returns the store of the driver, "local" or "s3"
+func New(driver string, cfg config.StorageConfig) (Store, error) {
+	switch driver {
+	case "local":
+		return NewLocal(cfg.Dir), nil
+	case "s3":
+		return NewS3(cfg)
+	default:
+		return nil, fmt.Errorf("unsupported storage driver %q", driver)
+	}
+}
+
+// Download is how a presigned URL serves its file
+type Download struct {
+	FileName    string
+	ContentType string
+	Inline      bool // Shown in the browser rather than saved
+}
+
+// Presigner is a Store whose files can be downloaded directly with a signed
+// URL for a while
+type Presigner interface {
+	PresignGet(ctx context.Context, key string, ttl time.Duration, download Download) (string, error)
+}
@@ -0,0 +1,123 @@
+//go:build integration
+
+// Package testdb runs the Postgres the integration tests work against in a
+// container, so they need Docker but no database set up by hand.
+package testdb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"taxifleet/backend/internal/config"
+	"taxifleet/backend/internal/database"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const (
+	image     = "postgres:16"
+	name      = "taxifleet"
+	superuser = "postgres"
+	// appRole is the role the API connects as. Row-level security does not
+	// apply to superusers, so the tests must not run as one.
+	appRole = "taxifleet_app"
+)
+
+// Postgres is a migrated database in a container
+type Postgres struct {
+	container *postgres.PostgresContainer
+	host      string
+	port      int
+}
+
+// Start runs Postgres, applies the migrations at migrationPath, such as
+// "file://../../migrations", and creates the API's role
+func Start(ctx context.Context, migrationPath string) (*Postgres, error) {
+	container, err := postgres.Run(ctx, image,
+		postgres.WithDatabase(name),
+		postgres.WithUsername(superuser),
+		postgres.WithPassword(superuser),
+		// The server restarts once after the init scripts
+		testcontainers.WithWaitStrategy(wait.ForLog("database system is ready to accept connections").
+			WithOccurrence(2).
+			WithStartupTimeout(time.Minute)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start postgres: %w", err)
+	}
+	p := &Postgres{container: container}
+	if p.host, err = container.Host(ctx); err != nil {
+		return nil, fail(ctx, p, err)
+	}
+	port, err := container.MappedPort(ctx, "5432/tcp")
+	if err != nil {
+		return nil, fail(ctx, p, err)
+	}
+	p.port = port.Int()
+
+	cfg := p.config(superuser)
+	cfg.MigrationPath = migrationPath
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	db, err := database.New(&cfg, logger)
+	if err != nil {
+		return nil, fail(ctx, p, err)
+	}
+	defer db.Close()
+	if err := db.Migrate(); err != nil {
+		return nil, fail(ctx, p, err)
+	}
+	_, err = db.ExecContext(ctx, fmt.Sprintf(`
+		CREATE ROLE %[1]s LOGIN PASSWORD '%[1]s';
+		GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA public TO %[1]s;
+		GRANT USAGE, SELECT ON ALL SEQUENCES IN SCHEMA public TO %[1]s`, appRole))
+	if err != nil {
+		return nil, fail(ctx, p, fmt.Errorf("failed to create the API's role: %w", err))
+	}
+	return p, nil
+}
+
+// Config returns the connection settings of the API's role
+func (p *Postgres) Config() config.DatabaseConfig {
+	return p.config(appRole)
+}
+
+// Superuser connects as the superuser, for setup the API's role may not do,
+// such as creating triggers
+func (p *Postgres) Superuser() (*sqlx.DB, error) {
+	cfg := p.config(superuser)
+	return sqlx.Connect("postgres", cfg.GetDSN())
+}
+
+// Terminate removes the container
+func (p *Postgres) Terminate(ctx context.Context) error {
+	return p.container.Terminate(ctx)
+}
+
+func (p *Postgres) config(user string) config.DatabaseConfig {
+	// Each role's password is its name
+	return config.DatabaseConfig{
+		Host:            p.host,
+		Port:            p.port,
+		User:            user,
+		Password:        user,
+		Name:            name,
+		SSLMode:         "disable",
+		MaxOpenConns:    10,
+		MaxIdleConns:    5,
+		ConnMaxLifetime: 5 * time.Minute,
+		ConnMaxIdleTime: 5 * time.Minute,
+	}
+}
+
+// fail removes the container of a failed start
+func fail(ctx context.Context, p *Postgres, err error) error {
+	_ = p.Terminate(ctx)
+	return err
+}
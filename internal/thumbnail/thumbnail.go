@@ -0,0 +1,72 @@
+// Package thumbnail scales uploaded images down to small JPEGs for list views.
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png" // Registers the PNG decoder
+	"io"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // Registers the WebP decoder
+)
+
+// ContentType is the type of every thumbnail
+const ContentType = "image/jpeg"
+
+// maxPixels bounds the images decoded, so a small file declaring huge
+// dimensions cannot exhaust memory
+const maxPixels = 50_000_000
+
+// ErrTooLarge is returned for images with more than maxPixels pixels
+var ErrTooLarge = errors.New("image dimensions are too large")
+
+// Generate decodes a JPEG, PNG or WebP image and returns it as a JPEG whose
+// longer side is at most size pixels. Smaller images keep their size.
+// Transparent areas become white.
+func Generate(file io.Reader, size int) ([]byte, error) {
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+	if config.Width*config.Height > maxPixels {
+		return nil, ErrTooLarge
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	bounds := src.Bounds()
+	width, height := fit(bounds.Dx(), bounds.Dy(), size)
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// fit returns the dimensions of a width x height image scaled to fit in a
+// size x size square, keeping its aspect ratio and at least one pixel
+func fit(width, height, size int) (int, int) {
+	if width <= size && height <= size {
+		return width, height
+	}
+	if width >= height {
+		return size, max(1, height*size/width)
+	}
+	return max(1, width*size/height), size
+}
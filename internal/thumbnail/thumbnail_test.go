@@ -0,0 +1,50 @@
+package thumbnail
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		wantW, wantH  int
+	}{
+		{"landscape", 1600, 1200, 320, 240},
+		{"portrait", 600, 3000, 64, 320},
+		{"smaller than the thumbnail", 100, 50, 100, 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := image.NewNRGBA(image.Rect(0, 0, tt.width, tt.height))
+			src.Set(0, 0, color.NRGBA{R: 255, A: 255})
+			var file bytes.Buffer
+			if err := png.Encode(&file, src); err != nil {
+				t.Fatalf("failed to encode test image: %v", err)
+			}
+
+			thumb, err := Generate(&file, 320)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got, err := jpeg.Decode(bytes.NewReader(thumb))
+			if err != nil {
+				t.Fatalf("expected a JPEG thumbnail, got %v", err)
+			}
+			if size := got.Bounds().Size(); size.X != tt.wantW || size.Y != tt.wantH {
+				t.Fatalf("expected %dx%d, got %dx%d", tt.wantW, tt.wantH, size.X, size.Y)
+			}
+		})
+	}
+}
+
+func TestGenerateRejectsNonImages(t *testing.T) {
+	if _, err := Generate(bytes.NewReader([]byte("%PDF-1.7")), 320); err == nil {
+		t.Fatal("expected a PDF to be refused")
+	}
+}
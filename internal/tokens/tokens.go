@@ -0,0 +1,217 @@
+// Package tokens signs and verifies JWTs with the configured key: the shared
+// HS256 secret, or an RS256/EdDSA private key whose public half is published
+// as a JWKS so other services can verify tokens without the secret.
+//
+// During a key rotation the previous public key keeps validating tokens until
+// its grace period ends.
+package tokens
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"taxifleet/backend/internal/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// verificationKey is a public key (or the HS256 secret) accepted for tokens
+// carrying its kid
+type verificationKey struct {
+	method   jwt.SigningMethod
+	key      interface{}
+	public   crypto.PublicKey // nil for HS256, which is never published
+	notAfter time.Time        // Zero for the current key
+}
+
+// KeySet signs tokens with the current key and verifies them with the
+// current and, during its grace period, the previous key
+type KeySet struct {
+	method     jwt.SigningMethod
+	signingKey interface{}
+	keyID      string
+	keys       map[string]verificationKey
+}
+
+// Load builds the key set described by the JWT configuration
+func Load(cfg config.JWTConfig) (*KeySet, error) {
+	if cfg.Algorithm == "" || cfg.Algorithm == "HS256" {
+		secret := []byte(cfg.Secret)
+		return &KeySet{
+			method:     jwt.SigningMethodHS256,
+			signingKey: secret,
+			keys:       map[string]verificationKey{"": {method: jwt.SigningMethodHS256, key: secret}},
+		}, nil
+	}
+
+	private, err := readPrivateKey(cfg.PrivateKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	method, public, err := methodFor(private.Public())
+	if err != nil {
+		return nil, err
+	}
+	if method.Alg() != cfg.Algorithm {
+		return nil, fmt.Errorf("JWT private key is for %s, not %s", method.Alg(), cfg.Algorithm)
+	}
+
+	ks := &KeySet{
+		method:     method,
+		signingKey: private,
+		keyID:      cfg.KeyID,
+		keys:       map[string]verificationKey{cfg.KeyID: {method: method, key: public, public: public}},
+	}
+
+	if cfg.PreviousPublicKeyFile != "" {
+		previous, err := readPublicKey(cfg.PreviousPublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		previousMethod, previousKey, err := methodFor(previous)
+		if err != nil {
+			return nil, err
+		}
+		ks.keys[cfg.PreviousKeyID] = verificationKey{
+			method:   previousMethod,
+			key:      previousKey,
+			public:   previousKey,
+			notAfter: cfg.PreviousKeyUntil,
+		}
+	}
+	return ks, nil
+}
+
+// Sign returns a token for the claims, signed with the current key
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.method, claims)
+	if ks.keyID != "" {
+		token.Header["kid"] = ks.keyID
+	}
+	return token.SignedString(ks.signingKey)
+}
+
+// Parse verifies a token against the key named by its kid
+func (ks *KeySet) Parse(tokenString string) (*jwt.Token, error) {
+	return jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := ks.keys[kid]
+		if !ok {
+			return nil, errors.New("unknown signing key")
+		}
+		if !key.notAfter.IsZero() && time.Now().After(key.notAfter) {
+			return nil, errors.New("signing key was retired")
+		}
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, errors.New("unexpected signing method")
+		}
+		return key.key, nil
+	})
+}
+
+// JWK is a public key in JSON Web Key format (RFC 7517)
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	N         string `json:"n,omitempty"`   // RSA modulus
+	E         string `json:"e,omitempty"`   // RSA exponent
+	Curve     string `json:"crv,omitempty"` // OKP curve
+	X         string `json:"x,omitempty"`   // OKP public key
+}
+
+// JWKS is a JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys tokens may currently be signed with. It is
+// empty with HS256, whose secret must never be published.
+func (ks *KeySet) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{}}
+	for kid, key := range ks.keys {
+		if key.public == nil || (!key.notAfter.IsZero() && time.Now().After(key.notAfter)) {
+			continue
+		}
+		jwk := JWK{Use: "sig", Algorithm: key.method.Alg(), KeyID: kid}
+		switch public := key.public.(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+func methodFor(public crypto.PublicKey) (jwt.SigningMethod, crypto.PublicKey, error) {
+	switch key := public.(type) {
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256, key, nil
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, key, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported JWT key type %T", public)
+	}
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read JWT key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("read JWT key: %s is not PEM encoded", path)
+	}
+	return block, nil
+}
+
+func readPrivateKey(path string) (crypto.Signer, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse JWT private key: %w", err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported JWT private key type %T", key)
+	}
+	return signer, nil
+}
+
+func readPublicKey(path string) (crypto.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse JWT public key: %w", err)
+	}
+	return key, nil
+}
@@ -0,0 +1,104 @@
+package tokens
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"taxifleet/backend/internal/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func writeKeyPair(t *testing.T, dir, name string) (privateFile, publicFile string) {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateFile = filepath.Join(dir, name+".pem")
+	publicFile = filepath.Join(dir, name+".pub.pem")
+	if err := os.WriteFile(privateFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(publicFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return privateFile, publicFile
+}
+
+func TestKeyRotation(t *testing.T) {
+	dir := t.TempDir()
+	oldPrivate, oldPublic := writeKeyPair(t, dir, "old")
+	newPrivate, _ := writeKeyPair(t, dir, "new")
+
+	oldKeys, err := Load(config.JWTConfig{Algorithm: "EdDSA", PrivateKeyFile: oldPrivate, KeyID: "old"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := oldKeys.Sign(jwt.MapClaims{"user_id": 1, "exp": time.Now().Add(time.Hour).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotated := config.JWTConfig{
+		Algorithm:             "EdDSA",
+		PrivateKeyFile:        newPrivate,
+		KeyID:                 "new",
+		PreviousPublicKeyFile: oldPublic,
+		PreviousKeyID:         "old",
+		PreviousKeyUntil:      time.Now().Add(time.Hour),
+	}
+	keys, err := Load(rotated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := keys.Parse(token); err != nil {
+		t.Errorf("token signed with the previous key rejected during grace period: %v", err)
+	}
+	if got := len(keys.JWKS().Keys); got != 2 {
+		t.Errorf("expected both keys published during grace period, got %d", got)
+	}
+
+	rotated.PreviousKeyUntil = time.Now().Add(-time.Minute)
+	keys, err = Load(rotated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := keys.Parse(token); err == nil {
+		t.Error("expected token signed with the previous key to be rejected after grace period")
+	}
+	if jwks := keys.JWKS(); len(jwks.Keys) != 1 || jwks.Keys[0].KeyID != "new" {
+		t.Errorf("expected only the new key published, got %+v", jwks.Keys)
+	}
+}
+
+func TestHS256PublishesNoKeys(t *testing.T) {
+	keys, err := Load(config.JWTConfig{Algorithm: "HS256", Secret: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := keys.Sign(jwt.MapClaims{"user_id": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := keys.Parse(token); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got := len(keys.JWKS().Keys); got != 0 {
+		t.Errorf("expected no published keys, got %d", got)
+	}
+}
@@ -0,0 +1,66 @@
+package tracing
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const gormSpanKey = "tracing:span"
+
+// InstrumentGORM wraps every database call in a span that is a child of the
+// span in the statement's context (see gorm's WithContext)
+func InstrumentGORM(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("tracing:before_create", startGORMSpan("create")),
+		cb.Create().After("gorm:create").Register("tracing:after_create", endGORMSpan),
+		cb.Query().Before("gorm:query").Register("tracing:before_query", startGORMSpan("query")),
+		cb.Query().After("gorm:query").Register("tracing:after_query", endGORMSpan),
+		cb.Update().Before("gorm:update").Register("tracing:before_update", startGORMSpan("update")),
+		cb.Update().After("gorm:update").Register("tracing:after_update", endGORMSpan),
+		cb.Delete().Before("gorm:delete").Register("tracing:before_delete", startGORMSpan("delete")),
+		cb.Delete().After("gorm:delete").Register("tracing:after_delete", endGORMSpan),
+		cb.Row().Before("gorm:row").Register("tracing:before_row", startGORMSpan("row")),
+		cb.Row().After("gorm:row").Register("tracing:after_row", endGORMSpan),
+		cb.Raw().Before("gorm:raw").Register("tracing:before_raw", startGORMSpan("raw")),
+		cb.Raw().After("gorm:raw").Register("tracing:after_raw", endGORMSpan),
+	)
+}
+
+func startGORMSpan(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Statement.Context == nil {
+			return
+		}
+		ctx, span := Start(db.Statement.Context, "db."+operation,
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.operation", operation),
+			attribute.String("db.sql.table", db.Statement.Table),
+		)
+		db.Statement.Context = ctx
+		db.InstanceSet(gormSpanKey, span)
+	}
+}
+
+func endGORMSpan(db *gorm.DB) {
+	value, ok := db.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span := value.(trace.Span)
+
+	// Only the SQL with placeholders is recorded, never the bound values
+	span.SetAttributes(
+		attribute.String("db.statement", db.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	)
+
+	err := db.Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = nil // An expected outcome of lookups, not a failure
+	}
+	End(span, err)
+}
@@ -0,0 +1,162 @@
+package validation
+
+import (
+	"fmt"
+	"html"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+// Sanitizer cleans the text fields of bound requests before they are
+// validated: it drops invalid UTF-8 and control characters other than
+// newlines and tabs, and refuses text longer than MaxLength characters unless
+// the field has its own max rule. With EscapeHTML, valid requests also get <,
+// >, &, ' and " escaped, for frontends that render text as HTML.
+//
+// Fields tagged sanitize:"-", such as passwords, tokens, URLs and JSON, are
+// kept as sent; only the length is checked.
+type Sanitizer struct {
+	MaxLength  int
+	EscapeHTML bool
+}
+
+// Text returns text without invalid UTF-8 and control characters, with
+// Windows line endings turned into newlines
+func (s Sanitizer) Text(text string) string {
+	text = strings.ToValidUTF8(text, "")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' {
+			return r
+		}
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, text)
+}
+
+// Struct sanitizes the string fields of obj, a pointer to a request, and the
+// structs, pointers and slices it holds. It returns a *FieldError for the
+// first text that is too long.
+func (s Sanitizer) Struct(obj any) error {
+	return walkText(reflect.ValueOf(obj), "", true, 0, func(v reflect.Value, name string, clean bool, maxLength int) error {
+		text := v.String()
+		if clean {
+			text = s.Text(text)
+			v.SetString(text)
+		}
+		if maxLength == 0 && s.MaxLength > 0 && utf8.RuneCountInString(text) > s.MaxLength {
+			return &FieldError{
+				Field:   name,
+				Rule:    "max",
+				Message: fmt.Sprintf("%s must be at most %d characters", name, s.MaxLength),
+			}
+		}
+		return nil
+	})
+}
+
+// Escape HTML-escapes the string fields Struct cleans. It runs after
+// validation, so that lengths and formats are checked on the text as sent.
+func (s Sanitizer) Escape(obj any) {
+	walkText(reflect.ValueOf(obj), "", true, 0, func(v reflect.Value, name string, clean bool, maxLength int) error {
+		if clean {
+			v.SetString(html.EscapeString(v.String()))
+		}
+		return nil
+	})
+}
+
+// walkText calls visit for each settable string in v with the name of its
+// field, whether it may be cleaned and the limit of its own max rule
+func walkText(v reflect.Value, name string, clean bool, maxLength int, visit func(v reflect.Value, name string, clean bool, maxLength int) error) error {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return walkText(v.Elem(), name, clean, maxLength, visit)
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if err := walkText(v.Field(i), fieldName(field), field.Tag.Get("sanitize") != "-", maxRule(field), visit); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return nil // Raw bytes, e.g. json.RawMessage
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := walkText(v.Index(i), name, clean, maxLength, visit); err != nil {
+				return err
+			}
+		}
+	case reflect.String:
+		if v.CanSet() {
+			return visit(v, name, clean, maxLength)
+		}
+	}
+	return nil
+}
+
+// fieldName is the field's name in request bodies
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		if name, _, _ := strings.Cut(field.Tag.Get(tag), ","); name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// maxRule returns the limit of a text field's own max binding rule, 0 without
+func maxRule(field reflect.StructField) int {
+	t := field.Type
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.String {
+		return 0
+	}
+	for _, rule := range strings.Split(field.Tag.Get("binding"), ",") {
+		var limit int
+		if _, err := fmt.Sscanf(rule, "max=%d", &limit); err == nil {
+			return limit
+		}
+	}
+	return 0
+}
+
+// sanitizingValidator sanitizes requests before gin's validator checks them
+type sanitizingValidator struct {
+	binding.StructValidator
+	sanitizer Sanitizer
+}
+
+func (v *sanitizingValidator) ValidateStruct(obj any) error {
+	if err := v.sanitizer.Struct(obj); err != nil {
+		return err
+	}
+	if err := v.StructValidator.ValidateStruct(obj); err != nil {
+		return err
+	}
+	if v.sanitizer.EscapeHTML {
+		v.sanitizer.Escape(obj)
+	}
+	return nil
+}
+
+// SanitizeWithGin makes gin sanitize every request it binds
+func SanitizeWithGin(sanitizer Sanitizer) {
+	binding.Validator = &sanitizingValidator{StructValidator: binding.Validator, sanitizer: sanitizer}
+}
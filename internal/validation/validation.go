@@ -0,0 +1,201 @@
+// Package validation holds the domain rules for request fields and registers
+// them as validator tags, so they can be used in binding tags:
+//
+//	license_plate  plate made of letters, digits, spaces and dashes
+//	vin            17 character VIN with a valid check digit
+//	phone          phone number that normalizes to E.164
+//	amount         positive amount; money.Amount fields in minor units, float
+//	               fields with at most two decimals
+package validation
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError reports a field that breaks a domain rule checked outside of
+// request binding, e.g. a plate format that depends on the tenant's country
+type FieldError struct {
+	Field   string
+	Rule    string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return e.Message
+}
+
+// FieldErrors reports every field of a document that breaks a rule, e.g. the
+// keys of tenant settings that do not match their schema
+type FieldErrors []*FieldError
+
+func (e FieldErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap lets errors.As find the first *FieldError
+func (e FieldErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Register adds the domain rules to v
+func Register(v *validator.Validate) error {
+	rules := map[string]validator.Func{
+		"license_plate": func(fl validator.FieldLevel) bool {
+			return IsLicensePlate(fl.Field().String())
+		},
+		"vin": func(fl validator.FieldLevel) bool {
+			return IsVIN(fl.Field().String())
+		},
+		"phone": func(fl validator.FieldLevel) bool {
+			_, ok := NormalizePhone(fl.Field().String())
+			return ok
+		},
+		"amount": func(fl validator.FieldLevel) bool {
+			if fl.Field().CanInt() {
+				return fl.Field().Int() > 0
+			}
+			return IsAmount(fl.Field().Float())
+		},
+	}
+	for tag, rule := range rules {
+		if err := v.RegisterValidation(tag, rule); err != nil {
+			return fmt.Errorf("register %s rule: %w", tag, err)
+		}
+	}
+	return nil
+}
+
+// RegisterWithGin adds the domain rules to gin's binding validator
+func RegisterWithGin() error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return fmt.Errorf("unexpected validator engine %T", binding.Validator.Engine())
+	}
+	return Register(v)
+}
+
+var (
+	genericPlatePattern = regexp.MustCompile(`^[A-Z0-9]([A-Z0-9 -]{0,10})[A-Z0-9]$`)
+	whitespace          = regexp.MustCompile(`\s+`)
+)
+
+// platePatterns are the national formats checked when a tenant sets its
+// country; other countries only get the generic check
+var platePatterns = map[string]*regexp.Regexp{
+	"CI": regexp.MustCompile(`^\d{1,4} ?[A-Z]{1,2} ?\d{2}$`),              // 1234 AB 01
+	"SN": regexp.MustCompile(`^[A-Z]{2}-?\d{4}-?[A-Z]{1,2}$`),             // DK-1234-AB
+	"NG": regexp.MustCompile(`^[A-Z]{3}-?\d{3}[A-Z]{2}$`),                 // ABC-123DE
+	"FR": regexp.MustCompile(`^[A-Z]{2}-?\d{3}-?[A-Z]{2}$`),               // AB-123-CD
+	"DE": regexp.MustCompile(`^[A-Z]{1,3}[- ]?[A-Z]{1,2} ?\d{1,4}[EH]?$`), // B-AB 1234
+	"GB": regexp.MustCompile(`^[A-Z]{2}\d{2} ?[A-Z]{3}$`),                 // AB12 CDE
+}
+
+// NormalizeLicensePlate upper-cases the plate and collapses its whitespace
+func NormalizeLicensePlate(plate string) string {
+	return whitespace.ReplaceAllString(strings.ToUpper(strings.TrimSpace(plate)), " ")
+}
+
+// IsLicensePlate reports whether plate looks like a plate of any country
+func IsLicensePlate(plate string) bool {
+	return genericPlatePattern.MatchString(NormalizeLicensePlate(plate))
+}
+
+// CheckLicensePlate validates plate against the format of the given ISO 3166
+// country code. Countries without a known format only get the generic check.
+func CheckLicensePlate(country, plate string) error {
+	plate = NormalizeLicensePlate(plate)
+	pattern, ok := platePatterns[strings.ToUpper(country)]
+	if !ok {
+		pattern = genericPlatePattern
+	}
+	if !pattern.MatchString(plate) {
+		return &FieldError{
+			Field:   "license_plate",
+			Rule:    "license_plate",
+			Message: fmt.Sprintf("%s is not a valid license plate", plate),
+		}
+	}
+	return nil
+}
+
+// vinValues are the transliterated values of VIN characters; I, O and Q are
+// not allowed
+var vinValues = map[rune]int{
+	'0': 0, '1': 1, '2': 2, '3': 3, '4': 4, '5': 5, '6': 6, '7': 7, '8': 8, '9': 9,
+	'A': 1, 'B': 2, 'C': 3, 'D': 4, 'E': 5, 'F': 6, 'G': 7, 'H': 8,
+	'J': 1, 'K': 2, 'L': 3, 'M': 4, 'N': 5, 'P': 7, 'R': 9,
+	'S': 2, 'T': 3, 'U': 4, 'V': 5, 'W': 6, 'X': 7, 'Y': 8, 'Z': 9,
+}
+
+var vinWeights = [17]int{8, 7, 6, 5, 4, 3, 2, 10, 0, 9, 8, 7, 6, 5, 4, 3, 2}
+
+// IsVIN reports whether vin has 17 valid characters and a correct check digit
+// (ISO 3779, position 9)
+func IsVIN(vin string) bool {
+	vin = strings.ToUpper(vin)
+	if len(vin) != 17 {
+		return false
+	}
+
+	sum := 0
+	for i, r := range vin {
+		value, ok := vinValues[r]
+		if !ok {
+			return false
+		}
+		sum += value * vinWeights[i]
+	}
+
+	check := byte('0' + sum%11)
+	if sum%11 == 10 {
+		check = 'X'
+	}
+	return vin[8] == check
+}
+
+var e164Pattern = regexp.MustCompile(`^\+[1-9]\d{7,14}$`)
+
+// NormalizePhone strips formatting from an international phone number and
+// returns it in E.164 form (+2250707070707). Numbers must carry their
+// country code, written as + or 00.
+func NormalizePhone(phone string) (string, bool) {
+	normalized := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')', '\t':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(phone))
+
+	if strings.HasPrefix(normalized, "00") {
+		normalized = "+" + normalized[2:]
+	}
+	if !e164Pattern.MatchString(normalized) {
+		return phone, false
+	}
+	return normalized, true
+}
+
+// IsAmount reports whether amount is a positive sum of money with at most two
+// decimals
+func IsAmount(amount float64) bool {
+	if amount <= 0 || math.IsInf(amount, 0) || math.IsNaN(amount) {
+		return false
+	}
+	cents := amount * 100
+	return math.Abs(cents-math.Round(cents)) < 1e-6
+}
@@ -0,0 +1,118 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestIsVIN(t *testing.T) {
+	tests := map[string]bool{
+		"1HGCM82633A004352": true,
+		"1M8GDM9AXKP042788": true,
+		"1hgcm82633a004352": true,
+		"1HGCM82643A004352": false, // Wrong check digit
+		"1HGCM82633A00435":  false,
+		"1HGCM82633AO04352": false, // O is not allowed
+	}
+	for vin, want := range tests {
+		if got := IsVIN(vin); got != want {
+			t.Errorf("IsVIN(%q) = %v, want %v", vin, got, want)
+		}
+	}
+}
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		phone string
+		want  string
+		ok    bool
+	}{
+		{"+225 07 07 07 07 07", "+2250707070707", true},
+		{"00225-0707-070707", "+2250707070707", true},
+		{"+1 (415) 555-0100", "+14155550100", true},
+		{"0707070707", "0707070707", false},
+		{"+0123456789", "+0123456789", false},
+		{"+225abc", "+225abc", false},
+	}
+	for _, tt := range tests {
+		got, ok := NormalizePhone(tt.phone)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("NormalizePhone(%q) = %q, %v, want %q, %v", tt.phone, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestCheckLicensePlate(t *testing.T) {
+	tests := []struct {
+		country string
+		plate   string
+		valid   bool
+	}{
+		{"CI", "1234 AB 01", true},
+		{"FR", "ab-123-cd", true},
+		{"FR", "1234 AB 01", false},
+		{"GB", "AB12 CDE", true},
+		{"", "TAXI-42", true},
+		{"", "!!", false},
+		{"XX", "TAXI 42", true},
+	}
+	for _, tt := range tests {
+		if err := CheckLicensePlate(tt.country, tt.plate); (err == nil) != tt.valid {
+			t.Errorf("CheckLicensePlate(%q, %q) = %v, want valid %v", tt.country, tt.plate, err, tt.valid)
+		}
+	}
+}
+
+func TestIsAmount(t *testing.T) {
+	tests := map[float64]bool{
+		12.5:   true,
+		0.01:   true,
+		19.99:  true,
+		0:      false,
+		-5:     false,
+		1.005:  false,
+		100000: true,
+	}
+	for amount, want := range tests {
+		if got := IsAmount(amount); got != want {
+			t.Errorf("IsAmount(%v) = %v, want %v", amount, got, want)
+		}
+	}
+}
+
+func TestSanitizerStruct(t *testing.T) {
+	type item struct {
+		Note string `json:"note"`
+	}
+	req := struct {
+		Reason   *string `json:"reason"`
+		Title    string  `json:"title" binding:"max=5000"`
+		Password string  `json:"password" sanitize:"-"`
+		Items    []item  `json:"items"`
+	}{
+		Title:    strings.Repeat("a", 30),
+		Password: "p\x00ss",
+		Items:    []item{{Note: "<b>flat\r\ntyre</b>\x1b[31m"}},
+	}
+	reason := "late\u0007 deposit\ttoday"
+	req.Reason = &reason
+
+	sanitizer := Sanitizer{MaxLength: 20, EscapeHTML: true}
+	if err := sanitizer.Struct(&req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *req.Reason != "late deposit\ttoday" || req.Password != "p\x00ss" || req.Items[0].Note != "<b>flat\ntyre</b>[31m" {
+		t.Fatalf("unexpected sanitized request %+v, reason %q", req, *req.Reason)
+	}
+	sanitizer.Escape(&req)
+	if req.Items[0].Note != "&lt;b&gt;flat\ntyre&lt;/b&gt;[31m" || req.Password != "p\x00ss" {
+		t.Fatalf("unexpected escaped request %+v", req)
+	}
+
+	req.Items[0].Note = strings.Repeat("é", 21)
+	var fieldErr *FieldError
+	if err := sanitizer.Struct(&req); !errors.As(err, &fieldErr) || fieldErr.Field != "note" || fieldErr.Rule != "max" {
+		t.Fatalf("expected the long note refused, got %v", err)
+	}
+}
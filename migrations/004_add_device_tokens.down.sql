@@ -0,0 +1,4 @@
+-- Rollback device tokens
+
+DROP TRIGGER IF EXISTS trigger_device_tokens_updated_at ON device_tokens;
+DROP TABLE IF EXISTS device_tokens;
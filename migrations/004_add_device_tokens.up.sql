@@ -0,0 +1,21 @@
+-- Device tokens for push notifications (FCM)
+
+CREATE TABLE device_tokens (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token TEXT NOT NULL UNIQUE,
+    platform VARCHAR(20) NOT NULL,
+    last_seen_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_device_tokens_tenant_id ON device_tokens(tenant_id);
+CREATE INDEX idx_device_tokens_user_id ON device_tokens(user_id);
+CREATE INDEX idx_device_tokens_deleted_at ON device_tokens(deleted_at);
+
+CREATE TRIGGER trigger_device_tokens_updated_at
+    BEFORE UPDATE ON device_tokens
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,2 @@
+-- Remove unique weekly report index
+DROP INDEX IF EXISTS idx_weekly_reports_unique_week;
@@ -0,0 +1,27 @@
+-- Prevent duplicate weekly reports per taxi/driver/week
+-- Soft-deleted reports are excluded so a deleted draft can be recreated
+
+-- First, soft-delete existing duplicates, keeping the most advanced report
+-- (approved > submitted > draft > rejected), then the oldest one
+WITH duplicates AS (
+  SELECT id, ROW_NUMBER() OVER (
+    PARTITION BY tenant_id, taxi_id, driver_id, week_start_date
+    ORDER BY CASE status
+      WHEN 'approved' THEN 0
+      WHEN 'submitted' THEN 1
+      WHEN 'draft' THEN 2
+      ELSE 3
+    END, id
+  ) AS rn
+  FROM weekly_reports
+  WHERE deleted_at IS NULL
+)
+UPDATE weekly_reports
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE id IN (
+  SELECT id FROM duplicates WHERE rn > 1
+);
+
+CREATE UNIQUE INDEX idx_weekly_reports_unique_week
+    ON weekly_reports(tenant_id, taxi_id, driver_id, week_start_date)
+    WHERE deleted_at IS NULL;
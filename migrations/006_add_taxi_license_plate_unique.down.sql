@@ -0,0 +1,2 @@
+-- Remove unique license plate index
+DROP INDEX IF EXISTS idx_taxis_tenant_license_plate_unique;
@@ -0,0 +1,18 @@
+-- Enforce unique license plates per tenant (case-insensitive)
+-- Soft-deleted taxis are excluded so a plate can be reused after deletion
+
+-- First, rename existing duplicates (keep the first one) so they can be fixed by hand
+WITH duplicates AS (
+  SELECT id, ROW_NUMBER() OVER (PARTITION BY tenant_id, UPPER(license_plate) ORDER BY id) AS rn
+  FROM taxis
+  WHERE deleted_at IS NULL
+)
+UPDATE taxis
+SET license_plate = license_plate || '-DUP-' || id
+WHERE id IN (
+  SELECT id FROM duplicates WHERE rn > 1
+);
+
+CREATE UNIQUE INDEX idx_taxis_tenant_license_plate_unique
+    ON taxis(tenant_id, UPPER(license_plate))
+    WHERE deleted_at IS NULL;
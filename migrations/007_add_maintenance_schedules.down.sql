@@ -0,0 +1,6 @@
+-- Rollback preventive maintenance schedules
+
+DROP TRIGGER IF EXISTS trigger_maintenance_schedules_updated_at ON maintenance_schedules;
+DROP TABLE IF EXISTS maintenance_schedules;
+
+ALTER TABLE taxis DROP COLUMN IF EXISTS mileage;
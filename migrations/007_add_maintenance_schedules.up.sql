@@ -0,0 +1,28 @@
+-- Preventive maintenance schedules
+-- Adds an odometer reading to taxis so schedules can use km intervals
+
+ALTER TABLE taxis ADD COLUMN mileage INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE maintenance_schedules (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    taxi_id INTEGER NOT NULL REFERENCES taxis(id) ON DELETE CASCADE,
+    task VARCHAR(255) NOT NULL,
+    interval_km INTEGER NOT NULL DEFAULT 0,
+    interval_days INTEGER NOT NULL DEFAULT 0,
+    last_done_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_done_km INTEGER NOT NULL DEFAULT 0,
+    notified_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT check_maintenance_schedule_interval CHECK (interval_km > 0 OR interval_days > 0)
+);
+
+CREATE INDEX idx_maintenance_schedules_tenant_id ON maintenance_schedules(tenant_id);
+CREATE INDEX idx_maintenance_schedules_taxi_id ON maintenance_schedules(taxi_id);
+CREATE INDEX idx_maintenance_schedules_deleted_at ON maintenance_schedules(deleted_at);
+
+CREATE TRIGGER trigger_maintenance_schedules_updated_at
+    BEFORE UPDATE ON maintenance_schedules
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,5 @@
+-- Rollback spare parts inventory
+
+DROP TRIGGER IF EXISTS trigger_parts_updated_at ON parts;
+DROP TABLE IF EXISTS stock_movements;
+DROP TABLE IF EXISTS parts;
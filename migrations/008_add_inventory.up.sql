@@ -0,0 +1,41 @@
+-- Spare parts inventory
+
+CREATE TABLE parts (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    sku VARCHAR(100),
+    unit VARCHAR(20) NOT NULL DEFAULT 'pcs',
+    unit_cost DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    quantity_on_hand DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    reorder_level DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT check_parts_quantity_non_negative CHECK (quantity_on_hand >= 0)
+);
+
+CREATE TABLE stock_movements (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    part_id INTEGER NOT NULL REFERENCES parts(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL,
+    quantity DECIMAL(10, 2) NOT NULL,
+    unit_cost DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    maintenance_log_id INTEGER REFERENCES maintenance_logs(id) ON DELETE SET NULL,
+    notes TEXT,
+    created_by_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_parts_tenant_id ON parts(tenant_id);
+CREATE INDEX idx_parts_deleted_at ON parts(deleted_at);
+CREATE UNIQUE INDEX idx_parts_tenant_sku_unique ON parts(tenant_id, sku) WHERE sku IS NOT NULL AND sku != '' AND deleted_at IS NULL;
+
+CREATE INDEX idx_stock_movements_tenant_id ON stock_movements(tenant_id);
+CREATE INDEX idx_stock_movements_part_id ON stock_movements(part_id);
+CREATE INDEX idx_stock_movements_maintenance_log_id ON stock_movements(maintenance_log_id);
+
+CREATE TRIGGER trigger_parts_updated_at
+    BEFORE UPDATE ON parts
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,2 @@
+-- Rollback taxi-driver assignment history
+DROP TABLE IF EXISTS assignments;
@@ -0,0 +1,26 @@
+-- Taxi-driver assignment history
+
+CREATE TABLE assignments (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    taxi_id INTEGER NOT NULL REFERENCES taxis(id) ON DELETE CASCADE,
+    driver_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ended_at TIMESTAMP WITH TIME ZONE,
+    assigned_by_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_assignments_tenant_id ON assignments(tenant_id);
+CREATE INDEX idx_assignments_taxi_id_started_at ON assignments(taxi_id, started_at);
+CREATE INDEX idx_assignments_driver_id ON assignments(driver_id);
+
+-- Only one open assignment per taxi
+CREATE UNIQUE INDEX idx_assignments_taxi_open ON assignments(taxi_id) WHERE ended_at IS NULL;
+
+-- Backfill the current assignments; their true start is unknown, so the
+-- taxi's creation date is the best available approximation
+INSERT INTO assignments (tenant_id, taxi_id, driver_id, started_at)
+SELECT tenant_id, id, assigned_driver_id, created_at
+FROM taxis
+WHERE assigned_driver_id IS NOT NULL AND deleted_at IS NULL;
@@ -0,0 +1,2 @@
+-- Rollback background job run history
+DROP TABLE IF EXISTS job_runs;
@@ -0,0 +1,12 @@
+-- Background job run history
+
+CREATE TABLE job_runs (
+    id SERIAL PRIMARY KEY,
+    job_name VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'running', -- running, succeeded, failed
+    error TEXT,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_job_runs_job_name_started_at ON job_runs(job_name, started_at DESC);
@@ -0,0 +1,3 @@
+-- Rollback optimistic locking
+ALTER TABLE taxis DROP COLUMN IF EXISTS version;
+ALTER TABLE weekly_reports DROP COLUMN IF EXISTS version;
@@ -0,0 +1,4 @@
+-- Row versions for optimistic locking of concurrently edited records
+
+ALTER TABLE weekly_reports ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE taxis ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,2 @@
+-- Rollback idempotency keys
+DROP TABLE IF EXISTS idempotency_keys;
@@ -0,0 +1,15 @@
+-- Stored responses of create requests, replayed when a client retries with
+-- the same Idempotency-Key
+
+CREATE TABLE idempotency_keys (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    key VARCHAR(255) NOT NULL,
+    request_hash VARCHAR(64) NOT NULL,
+    status_code INTEGER NOT NULL DEFAULT 0, -- 0 while the original request is in flight
+    response_body BYTEA,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_idempotency_keys_user_id_key ON idempotency_keys(user_id, key);
+CREATE INDEX idx_idempotency_keys_created_at ON idempotency_keys(created_at);
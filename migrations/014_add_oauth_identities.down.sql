@@ -0,0 +1,2 @@
+-- Rollback OAuth identities
+DROP TABLE IF EXISTS oauth_identities;
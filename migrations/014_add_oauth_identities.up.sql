@@ -0,0 +1,13 @@
+-- External sign-in identities (e.g. Google) linked to users
+
+CREATE TABLE oauth_identities (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL, -- The provider's stable user ID
+    email VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_oauth_identities_provider_subject ON oauth_identities(provider, subject);
+CREATE INDEX idx_oauth_identities_user_id ON oauth_identities(user_id);
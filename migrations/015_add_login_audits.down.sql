@@ -0,0 +1,2 @@
+-- Rollback login audits
+DROP TABLE IF EXISTS login_audits;
@@ -0,0 +1,17 @@
+-- Successful and failed login attempts, for users' login history and security review
+
+CREATE TABLE login_audits (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE, -- NULL when no user matched
+    identifier VARCHAR(255) NOT NULL, -- The email or phone the attempt was made with
+    method VARCHAR(50) NOT NULL, -- password, google
+    success BOOLEAN NOT NULL,
+    failure_reason VARCHAR(255),
+    ip_address VARCHAR(45),
+    user_agent TEXT,
+    device_fingerprint VARCHAR(64),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_login_audits_user_id_created_at ON login_audits(user_id, created_at DESC);
+CREATE INDEX idx_login_audits_created_at ON login_audits(created_at);
@@ -0,0 +1,5 @@
+-- Rollback full-text search
+ALTER TABLE expenses DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE weekly_reports DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE users DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE taxis DROP COLUMN IF EXISTS search_vector;
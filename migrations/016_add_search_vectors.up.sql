@@ -0,0 +1,27 @@
+-- Full-text search over taxis, reports, expenses and driver names.
+-- The 'simple' configuration does no stemming, so French and English text and
+-- license plates are matched alike. Plates are also indexed without separators.
+
+ALTER TABLE taxis ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    to_tsvector('simple',
+        coalesce(license_plate, '') || ' ' ||
+        regexp_replace(coalesce(license_plate, ''), '[^[:alnum:]]', '', 'g') || ' ' ||
+        coalesce(model, ''))
+) STORED;
+
+ALTER TABLE users ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    to_tsvector('simple', coalesce(first_name, '') || ' ' || coalesce(last_name, ''))
+) STORED;
+
+ALTER TABLE weekly_reports ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    to_tsvector('simple', coalesce(notes, ''))
+) STORED;
+
+ALTER TABLE expenses ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    to_tsvector('simple', coalesce(reason, '') || ' ' || coalesce(category, ''))
+) STORED;
+
+CREATE INDEX idx_taxis_search_vector ON taxis USING GIN (search_vector);
+CREATE INDEX idx_users_search_vector ON users USING GIN (search_vector);
+CREATE INDEX idx_weekly_reports_search_vector ON weekly_reports USING GIN (search_vector);
+CREATE INDEX idx_expenses_search_vector ON expenses USING GIN (search_vector);
@@ -0,0 +1,2 @@
+-- Rollback report attachments
+DROP TABLE IF EXISTS report_attachments;
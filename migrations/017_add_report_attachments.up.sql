@@ -0,0 +1,17 @@
+-- Photos drivers attach to weekly reports, e.g. of the cash count or logbook page.
+-- The files live under ATTACHMENT_DIR; rows point to them by storage path.
+
+CREATE TABLE report_attachments (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    report_id INTEGER NOT NULL REFERENCES weekly_reports(id) ON DELETE CASCADE,
+    uploaded_by_id INTEGER NOT NULL REFERENCES users(id),
+    file_name VARCHAR(255) NOT NULL, -- As uploaded, for downloads
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    storage_path VARCHAR(255) NOT NULL, -- Relative to ATTACHMENT_DIR
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_report_attachments_report_id ON report_attachments(report_id);
+CREATE INDEX idx_report_attachments_tenant_id ON report_attachments(tenant_id);
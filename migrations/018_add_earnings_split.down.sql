@@ -0,0 +1,4 @@
+-- Rollback earnings split
+ALTER TABLE weekly_reports DROP COLUMN IF EXISTS owner_share;
+ALTER TABLE weekly_reports DROP COLUMN IF EXISTS driver_share;
+ALTER TABLE taxis DROP COLUMN IF EXISTS earnings_split;
@@ -0,0 +1,7 @@
+-- Earnings split between owner and driver. A taxi's rule overrides the
+-- tenant's earnings_split setting; shares are fixed when a report is approved.
+
+ALTER TABLE taxis ADD COLUMN earnings_split JSONB; -- NULL uses the tenant's rule
+
+ALTER TABLE weekly_reports ADD COLUMN driver_share DECIMAL(10, 2);
+ALTER TABLE weekly_reports ADD COLUMN owner_share DECIMAL(10, 2);
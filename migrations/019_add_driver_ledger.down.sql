@@ -0,0 +1,3 @@
+-- Rollback driver ledger
+ALTER TABLE weekly_reports DROP COLUMN IF EXISTS ledger_offset;
+DROP TABLE IF EXISTS driver_ledger_entries;
@@ -0,0 +1,19 @@
+-- Money owners lend drivers and what the drivers paid back, either directly or
+-- offset against their share of an approved weekly report
+
+CREATE TABLE driver_ledger_entries (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    driver_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL, -- advance, repayment, offset
+    amount DECIMAL(10, 2) NOT NULL CHECK (amount > 0),
+    report_id INTEGER REFERENCES weekly_reports(id) ON DELETE SET NULL, -- Report an offset was taken from
+    notes TEXT,
+    created_by_id INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_driver_ledger_entries_driver_id ON driver_ledger_entries(driver_id, created_at);
+CREATE INDEX idx_driver_ledger_entries_tenant_id ON driver_ledger_entries(tenant_id);
+
+ALTER TABLE weekly_reports ADD COLUMN ledger_offset DECIMAL(10, 2);
@@ -0,0 +1,3 @@
+-- Rollback report adjustments
+ALTER TABLE weekly_reports DROP COLUMN IF EXISTS total_adjustments;
+DROP TABLE IF EXISTS report_adjustments;
@@ -0,0 +1,18 @@
+-- Bonuses and penalties added to a weekly report before approval. A report's
+-- total_adjustments is its bonuses less its penalties.
+
+CREATE TABLE report_adjustments (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    report_id INTEGER NOT NULL REFERENCES weekly_reports(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL, -- bonus, penalty
+    amount DECIMAL(10, 2) NOT NULL CHECK (amount > 0),
+    reason TEXT NOT NULL,
+    created_by_id INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_report_adjustments_report_id ON report_adjustments(report_id);
+CREATE INDEX idx_report_adjustments_tenant_id ON report_adjustments(tenant_id);
+
+ALTER TABLE weekly_reports ADD COLUMN total_adjustments DECIMAL(10, 2) NOT NULL DEFAULT 0;
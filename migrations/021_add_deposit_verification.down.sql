@@ -0,0 +1,5 @@
+-- Rollback deposit verification
+DROP INDEX IF EXISTS idx_bank_deposits_tenant_id_status;
+ALTER TABLE bank_deposits DROP COLUMN IF EXISTS verified_by_id;
+ALTER TABLE bank_deposits DROP COLUMN IF EXISTS verified_at;
+ALTER TABLE bank_deposits DROP COLUMN IF EXISTS status;
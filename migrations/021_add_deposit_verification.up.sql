@@ -0,0 +1,7 @@
+-- Deposits stay unverified until an owner has checked them against their proof
+
+ALTER TABLE bank_deposits ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'unverified'; -- unverified, verified
+ALTER TABLE bank_deposits ADD COLUMN verified_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE bank_deposits ADD COLUMN verified_by_id INTEGER REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_bank_deposits_tenant_id_status ON bank_deposits(tenant_id, status);
@@ -0,0 +1,3 @@
+-- Rollback deposit taxi
+DROP INDEX IF EXISTS idx_bank_deposits_taxi_id;
+ALTER TABLE bank_deposits DROP COLUMN IF EXISTS taxi_id;
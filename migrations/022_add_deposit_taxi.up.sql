@@ -0,0 +1,5 @@
+-- Deposits can name the taxi whose cash they bank, for the per-taxi cash ledger
+
+ALTER TABLE bank_deposits ADD COLUMN taxi_id INTEGER REFERENCES taxis(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_bank_deposits_taxi_id ON bank_deposits(taxi_id);
@@ -0,0 +1,6 @@
+-- Rollback bank accounts
+DROP INDEX IF EXISTS idx_bank_deposits_bank_account_id;
+ALTER TABLE bank_deposits DROP COLUMN IF EXISTS bank_account_id;
+
+DROP TRIGGER IF EXISTS trigger_bank_accounts_updated_at ON bank_accounts;
+DROP TABLE IF EXISTS bank_accounts;
@@ -0,0 +1,35 @@
+-- Bank accounts deposits are made into, replacing the free-text bank_account
+-- of deposits
+
+CREATE TABLE bank_accounts (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    label VARCHAR(255) NOT NULL,
+    bank_name VARCHAR(255),
+    account_number VARCHAR(64), -- IBAN or local account number
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_bank_accounts_tenant_id ON bank_accounts(tenant_id);
+CREATE INDEX idx_bank_accounts_deleted_at ON bank_accounts(deleted_at);
+
+CREATE TRIGGER trigger_bank_accounts_updated_at
+    BEFORE UPDATE ON bank_accounts
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE bank_deposits ADD COLUMN bank_account_id INTEGER REFERENCES bank_accounts(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_bank_deposits_bank_account_id ON bank_deposits(bank_account_id);
+
+-- Each distinct free-text account of a tenant becomes an account of its own
+INSERT INTO bank_accounts (tenant_id, label)
+SELECT DISTINCT tenant_id, TRIM(bank_account)
+FROM bank_deposits
+WHERE TRIM(COALESCE(bank_account, '')) != '';
+
+UPDATE bank_deposits d
+SET bank_account_id = a.id
+FROM bank_accounts a
+WHERE a.tenant_id = d.tenant_id AND a.label = TRIM(d.bank_account);
@@ -0,0 +1,2 @@
+-- Rollback weekly digest opt-out
+ALTER TABLE users DROP COLUMN IF EXISTS weekly_digest;
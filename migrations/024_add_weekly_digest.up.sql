@@ -0,0 +1,3 @@
+-- Owners receive the weekly email digest unless they opt out
+
+ALTER TABLE users ADD COLUMN weekly_digest BOOLEAN NOT NULL DEFAULT TRUE;
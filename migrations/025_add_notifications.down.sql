@@ -0,0 +1,2 @@
+-- Rollback notifications
+DROP TABLE IF EXISTS notifications;
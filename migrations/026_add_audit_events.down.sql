@@ -0,0 +1,2 @@
+-- Rollback audit events
+DROP TABLE IF EXISTS audit_events;
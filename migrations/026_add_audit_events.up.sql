@@ -0,0 +1,14 @@
+-- Audit log of notable tenant events, shown as the tenant's activity feed
+
+CREATE TABLE audit_events (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    actor_id INTEGER REFERENCES users(id) ON DELETE SET NULL, -- NULL for API keys and deleted users
+    action VARCHAR(50) NOT NULL, -- report.submitted, report.approved, taxi.created, expense.created
+    entity_type VARCHAR(50) NOT NULL, -- report, taxi, expense
+    entity_id INTEGER NOT NULL,
+    summary TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_events_tenant_id_id ON audit_events(tenant_id, id DESC);
@@ -0,0 +1,7 @@
+-- Rollback fines
+DELETE FROM driver_ledger_entries WHERE type = 'fine';
+DROP INDEX IF EXISTS idx_driver_ledger_entries_fine_id;
+ALTER TABLE driver_ledger_entries DROP COLUMN IF EXISTS fine_id;
+
+DROP TRIGGER IF EXISTS trigger_fines_updated_at ON fines;
+DROP TABLE IF EXISTS fines;
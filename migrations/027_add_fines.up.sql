@@ -0,0 +1,33 @@
+-- Traffic fines received for the tenant's taxis. A fine the driver is
+-- responsible for is charged to their ledger and offset against their share
+-- of approved reports like an advance.
+
+CREATE TABLE fines (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    taxi_id INTEGER NOT NULL REFERENCES taxis(id) ON DELETE CASCADE,
+    driver_id INTEGER REFERENCES users(id) ON DELETE SET NULL, -- Assigned driver at the time of the offense
+    offense_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    reference VARCHAR(100), -- Number of the notice
+    description TEXT,
+    amount DECIMAL(10, 2) NOT NULL CHECK (amount > 0),
+    due_date DATE NOT NULL,
+    responsibility VARCHAR(20) NOT NULL DEFAULT 'owner', -- driver, owner
+    paid_at TIMESTAMP WITH TIME ZONE,
+    created_by_id INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_fines_tenant_id_due_date ON fines(tenant_id, due_date);
+CREATE INDEX idx_fines_taxi_id ON fines(taxi_id);
+CREATE INDEX idx_fines_driver_id ON fines(driver_id);
+
+CREATE TRIGGER trigger_fines_updated_at
+    BEFORE UPDATE ON fines
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Fines charged to a driver add to their balance like advances
+ALTER TABLE driver_ledger_entries ADD COLUMN fine_id INTEGER REFERENCES fines(id) ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX idx_driver_ledger_entries_fine_id ON driver_ledger_entries(fine_id);
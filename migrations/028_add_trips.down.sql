@@ -0,0 +1,2 @@
+-- Rollback trips
+DROP TABLE IF EXISTS trips;
@@ -0,0 +1,19 @@
+-- Individual rides logged by the driver app, for tenants wanting more than
+-- weekly totals. They are rolled up into earnings suggestions for reports.
+
+CREATE TABLE trips (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    taxi_id INTEGER NOT NULL REFERENCES taxis(id) ON DELETE CASCADE,
+    driver_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ended_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    fare DECIMAL(10, 2) NOT NULL CHECK (fare >= 0),
+    distance_km DECIMAL(10, 2) NOT NULL DEFAULT 0 CHECK (distance_km >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ended_at >= started_at)
+);
+
+CREATE INDEX idx_trips_tenant_id_started_at ON trips(tenant_id, started_at);
+CREATE INDEX idx_trips_driver_id_started_at ON trips(driver_id, started_at);
+CREATE INDEX idx_trips_taxi_id_started_at ON trips(taxi_id, started_at);
@@ -0,0 +1,3 @@
+-- Rollback platform earnings
+DROP TRIGGER IF EXISTS trigger_platform_earnings_updated_at ON platform_earnings;
+DROP TABLE IF EXISTS platform_earnings;
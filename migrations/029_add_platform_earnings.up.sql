@@ -0,0 +1,22 @@
+-- Daily earnings of drivers on ride-hailing platforms (Uber, Bolt, Yango),
+-- imported from the platforms' statements and reconciled with weekly reports
+
+CREATE TABLE platform_earnings (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    driver_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    platform VARCHAR(20) NOT NULL, -- uber, bolt, yango
+    date DATE NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL,
+    created_by_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Importing a statement again replaces the day's amount
+CREATE UNIQUE INDEX idx_platform_earnings_driver_platform_date ON platform_earnings(driver_id, platform, date);
+CREATE INDEX idx_platform_earnings_tenant_id_date ON platform_earnings(tenant_id, date);
+
+CREATE TRIGGER trigger_platform_earnings_updated_at
+    BEFORE UPDATE ON platform_earnings
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,5 @@
+-- Rollback fuel cards
+DROP TABLE IF EXISTS fuel_card_transactions;
+
+DROP TRIGGER IF EXISTS trigger_fuel_cards_updated_at ON fuel_cards;
+DROP TABLE IF EXISTS fuel_cards;
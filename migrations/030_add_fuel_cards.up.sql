@@ -0,0 +1,38 @@
+-- Fuel cards assigned to taxis, and the transactions pulled from the fuel card
+-- provider. A transaction on an assigned card becomes a fuel expense of the
+-- taxi.
+
+CREATE TABLE fuel_cards (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    card_number VARCHAR(64) NOT NULL UNIQUE, -- As the provider reports it, without spaces
+    label VARCHAR(255),
+    taxi_id INTEGER REFERENCES taxis(id) ON DELETE SET NULL,
+    created_by_id INTEGER NOT NULL REFERENCES users(id), -- Also the creator of the card's expenses
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_fuel_cards_tenant_id ON fuel_cards(tenant_id);
+
+CREATE TRIGGER trigger_fuel_cards_updated_at
+    BEFORE UPDATE ON fuel_cards
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE fuel_card_transactions (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    fuel_card_id INTEGER REFERENCES fuel_cards(id) ON DELETE SET NULL,
+    external_id VARCHAR(100) NOT NULL UNIQUE, -- The provider's transaction ID
+    taxi_id INTEGER REFERENCES taxis(id) ON DELETE SET NULL,
+    expense_id INTEGER REFERENCES expenses(id) ON DELETE SET NULL,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL,
+    liters DECIMAL(10, 2),
+    station VARCHAR(255),
+    flag_reason VARCHAR(255), -- Set when the transaction needs the owner's attention
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_fuel_card_transactions_tenant_id_occurred_at ON fuel_card_transactions(tenant_id, occurred_at DESC);
+CREATE INDEX idx_fuel_card_transactions_occurred_at ON fuel_card_transactions(occurred_at);
@@ -0,0 +1,5 @@
+-- Rollback geofences
+DROP TABLE IF EXISTS taxi_positions;
+
+DROP TRIGGER IF EXISTS trigger_geofences_updated_at ON geofences;
+DROP TABLE IF EXISTS geofences;
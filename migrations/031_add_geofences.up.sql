@@ -0,0 +1,34 @@
+-- Taxi positions reported by the driver app or a telematics box, and the
+-- geofences defining the tenant's operating zone
+
+CREATE TABLE geofences (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    polygon JSONB NOT NULL, -- [{"latitude": .., "longitude": ..}, ...]
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_geofences_tenant_id ON geofences(tenant_id);
+
+CREATE TRIGGER trigger_geofences_updated_at
+    BEFORE UPDATE ON geofences
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE taxi_positions (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    taxi_id INTEGER NOT NULL REFERENCES taxis(id) ON DELETE CASCADE,
+    driver_id INTEGER REFERENCES users(id) ON DELETE SET NULL, -- Assigned at recorded_at
+    latitude DOUBLE PRECISION NOT NULL,
+    longitude DOUBLE PRECISION NOT NULL,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    outside_zone BOOLEAN NOT NULL DEFAULT false, -- Outside every active geofence of the tenant
+    unscheduled BOOLEAN NOT NULL DEFAULT false, -- No driver was assigned to the taxi
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_taxi_positions_taxi_id_recorded_at ON taxi_positions(taxi_id, recorded_at DESC);
+CREATE INDEX idx_taxi_positions_tenant_id ON taxi_positions(tenant_id);
@@ -0,0 +1,6 @@
+-- Rollback client IDs
+DROP INDEX IF EXISTS idx_expenses_tenant_id_client_id;
+DROP INDEX IF EXISTS idx_weekly_reports_tenant_id_client_id;
+
+ALTER TABLE expenses DROP COLUMN IF EXISTS client_id;
+ALTER TABLE weekly_reports DROP COLUMN IF EXISTS client_id;
@@ -0,0 +1,8 @@
+-- UUIDs the driver app generates for reports and expenses created offline, so
+-- syncing them again does not create duplicates
+
+ALTER TABLE weekly_reports ADD COLUMN client_id UUID;
+ALTER TABLE expenses ADD COLUMN client_id UUID;
+
+CREATE UNIQUE INDEX idx_weekly_reports_tenant_id_client_id ON weekly_reports(tenant_id, client_id) WHERE client_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_expenses_tenant_id_client_id ON expenses(tenant_id, client_id) WHERE client_id IS NOT NULL;
@@ -0,0 +1,7 @@
+-- Rollback plans
+DROP TABLE IF EXISTS export_counts;
+
+ALTER TABLE tenants DROP COLUMN IF EXISTS plan_id;
+
+DROP TRIGGER IF EXISTS trigger_plans_updated_at ON plans;
+DROP TABLE IF EXISTS plans;
@@ -0,0 +1,26 @@
+-- Subscription plans limiting what a tenant can use. A NULL limit is
+-- unlimited, as is a tenant without a plan.
+
+CREATE TABLE plans (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    max_taxis INTEGER,
+    max_users INTEGER,
+    export_quota INTEGER, -- Exports per calendar month
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER trigger_plans_updated_at
+    BEFORE UPDATE ON plans
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE tenants ADD COLUMN plan_id INTEGER REFERENCES plans(id);
+
+-- Exports made by each tenant per calendar month, counted against the quota
+CREATE TABLE export_counts (
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    month DATE NOT NULL, -- First day of the month
+    count INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (tenant_id, month)
+);
@@ -0,0 +1,2 @@
+-- Rollback user verification
+ALTER TABLE users DROP COLUMN IF EXISTS unverified;
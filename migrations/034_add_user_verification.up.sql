@@ -0,0 +1,3 @@
+-- Owners signing up through onboarding cannot sign in until they verified
+-- their email address
+ALTER TABLE users ADD COLUMN unverified BOOLEAN NOT NULL DEFAULT FALSE;
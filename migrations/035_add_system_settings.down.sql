@@ -0,0 +1,3 @@
+-- Rollback system settings
+DROP TABLE IF EXISTS system_setting_changes;
+DROP TABLE IF EXISTS system_settings;
@@ -0,0 +1,20 @@
+-- Platform-wide settings admins change at runtime, overriding the defaults
+-- from the environment, and the history of their changes
+
+CREATE TABLE system_settings (
+    key VARCHAR(100) PRIMARY KEY,
+    value JSONB NOT NULL,
+    updated_by_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE system_setting_changes (
+    id SERIAL PRIMARY KEY,
+    key VARCHAR(100) NOT NULL,
+    old_value JSONB, -- NULL while the default applied
+    new_value JSONB, -- NULL when reset to the default
+    changed_by_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_system_setting_changes_key ON system_setting_changes(key, id);
@@ -0,0 +1,2 @@
+-- Rollback policies
+DROP TABLE IF EXISTS policies;
@@ -0,0 +1,15 @@
+-- Per-tenant authorization policies, evaluated before the permission bitmask
+
+CREATE TABLE policies (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    subject VARCHAR(100) NOT NULL, -- Role name, user:<id> or *
+    action VARCHAR(100) NOT NULL,
+    effect VARCHAR(10) NOT NULL CHECK (effect IN ('allow', 'deny')),
+    conditions JSONB NOT NULL DEFAULT '[]',
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_policies_tenant_id ON policies(tenant_id);
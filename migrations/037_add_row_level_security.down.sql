@@ -0,0 +1,18 @@
+-- Rollback row-level security
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY[
+        'api_keys', 'assignments', 'audit_events', 'bank_accounts', 'bank_deposits',
+        'device_tokens', 'driver_ledger_entries', 'expenses', 'export_counts', 'fines',
+        'fuel_card_transactions', 'fuel_cards', 'geofences', 'maintenance_logs',
+        'maintenance_schedules', 'parts', 'platform_earnings', 'policies',
+        'report_adjustments', 'report_attachments', 'stock_movements', 'taxi_positions',
+        'taxis', 'trips', 'users', 'weekly_reports'
+    ] LOOP
+        EXECUTE format('DROP POLICY IF EXISTS tenant_isolation ON %I', t);
+        EXECUTE format('ALTER TABLE %I NO FORCE ROW LEVEL SECURITY', t);
+        EXECUTE format('ALTER TABLE %I DISABLE ROW LEVEL SECURITY', t);
+    END LOOP;
+END $$;
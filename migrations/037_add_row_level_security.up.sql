@@ -0,0 +1,26 @@
+-- Row-level security on the tenant tables: while app.tenant_id is set (per
+-- transaction, see repository.WithTenant) only the tenant's rows are visible
+-- and writable. Without it, as for admins and background jobs, nothing is
+-- filtered. FORCE applies the policies to the table owner too; superusers
+-- still bypass them.
+
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY[
+        'api_keys', 'assignments', 'audit_events', 'bank_accounts', 'bank_deposits',
+        'device_tokens', 'driver_ledger_entries', 'expenses', 'export_counts', 'fines',
+        'fuel_card_transactions', 'fuel_cards', 'geofences', 'maintenance_logs',
+        'maintenance_schedules', 'parts', 'platform_earnings', 'policies',
+        'report_adjustments', 'report_attachments', 'stock_movements', 'taxi_positions',
+        'taxis', 'trips', 'users', 'weekly_reports'
+    ] LOOP
+        EXECUTE format('ALTER TABLE %I ENABLE ROW LEVEL SECURITY', t);
+        EXECUTE format('ALTER TABLE %I FORCE ROW LEVEL SECURITY', t);
+        EXECUTE format(
+            'CREATE POLICY tenant_isolation ON %I USING ('
+            'NULLIF(current_setting(''app.tenant_id'', true), '''') IS NULL '
+            'OR tenant_id = current_setting(''app.tenant_id'', true)::integer)', t);
+    END LOOP;
+END $$;
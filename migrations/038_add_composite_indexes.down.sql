@@ -0,0 +1,6 @@
+-- Rollback composite indexes
+DROP INDEX IF EXISTS idx_sessions_user_id_expires_at;
+DROP INDEX IF EXISTS idx_expenses_tenant_id_date;
+DROP INDEX IF EXISTS idx_weekly_reports_driver_id_week_start_date;
+DROP INDEX IF EXISTS idx_weekly_reports_tenant_id_status;
+DROP INDEX IF EXISTS idx_weekly_reports_tenant_id_week_start_date;
@@ -0,0 +1,11 @@
+-- Composite indexes for the hottest queries. The report and expense lists
+-- filter by tenant and order by date; the trailing id serves their keyset
+-- pages, which order by (date, id).
+
+CREATE INDEX idx_weekly_reports_tenant_id_week_start_date ON weekly_reports(tenant_id, week_start_date, id);
+CREATE INDEX idx_weekly_reports_tenant_id_status ON weekly_reports(tenant_id, status);
+CREATE INDEX idx_weekly_reports_driver_id_week_start_date ON weekly_reports(driver_id, week_start_date);
+CREATE INDEX idx_expenses_tenant_id_date ON expenses(tenant_id, date, id);
+
+-- A user's sessions by expiry, e.g. the ones still active
+CREATE INDEX idx_sessions_user_id_expires_at ON sessions(user_id, expires_at);
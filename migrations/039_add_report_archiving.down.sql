@@ -0,0 +1,3 @@
+-- Rollback report archiving
+DROP INDEX IF EXISTS idx_weekly_reports_tenant_id_unarchived;
+ALTER TABLE weekly_reports DROP COLUMN IF EXISTS archived_at;
@@ -0,0 +1,6 @@
+-- Reports of closed periods can be archived. Archived reports are left out of
+-- the default lists, which the partial index serves.
+
+ALTER TABLE weekly_reports ADD COLUMN archived_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_weekly_reports_tenant_id_unarchived ON weekly_reports(tenant_id, week_start_date, id) WHERE archived_at IS NULL;
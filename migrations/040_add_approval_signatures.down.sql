@@ -0,0 +1,3 @@
+-- Rollback approval signatures
+DROP TABLE IF EXISTS report_signatures;
+ALTER TABLE users DROP COLUMN IF EXISTS pin_hash;
@@ -0,0 +1,23 @@
+-- Sign-off captured when a report is approved, kept for dispute resolution:
+-- either the approver's PIN was checked or they drew their signature.
+
+ALTER TABLE users ADD COLUMN pin_hash VARCHAR(255) NOT NULL DEFAULT ''; -- bcrypt; empty until the user sets an approval PIN
+
+CREATE TABLE report_signatures (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    report_id INTEGER NOT NULL UNIQUE REFERENCES weekly_reports(id) ON DELETE CASCADE,
+    signed_by_id INTEGER NOT NULL REFERENCES users(id),
+    method VARCHAR(10) NOT NULL, -- pin or drawn
+    image BYTEA, -- Drawn signature
+    content_type VARCHAR(100) NOT NULL DEFAULT '',
+    signed_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_report_signatures_tenant_id ON report_signatures(tenant_id);
+
+ALTER TABLE report_signatures ENABLE ROW LEVEL SECURITY;
+ALTER TABLE report_signatures FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON report_signatures USING (
+    NULLIF(current_setting('app.tenant_id', true), '') IS NULL
+    OR tenant_id = current_setting('app.tenant_id', true)::integer);
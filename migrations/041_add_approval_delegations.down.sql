@@ -0,0 +1,2 @@
+-- Rollback approval delegations
+DROP TABLE IF EXISTS approval_delegations;
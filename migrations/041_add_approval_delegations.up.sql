@@ -0,0 +1,24 @@
+-- Owners delegate their approval rights for a date range, e.g. to a manager
+-- while they travel. Revoked delegations are kept for the audit trail.
+
+CREATE TABLE approval_delegations (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    delegator_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    delegate_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    starts_on DATE NOT NULL,
+    ends_on DATE NOT NULL, -- Inclusive
+    reason TEXT NOT NULL DEFAULT '',
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ends_on >= starts_on)
+);
+
+CREATE INDEX idx_approval_delegations_tenant_id ON approval_delegations(tenant_id);
+CREATE INDEX idx_approval_delegations_delegate_id ON approval_delegations(delegate_id, starts_on, ends_on);
+
+ALTER TABLE approval_delegations ENABLE ROW LEVEL SECURITY;
+ALTER TABLE approval_delegations FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON approval_delegations USING (
+    NULLIF(current_setting('app.tenant_id', true), '') IS NULL
+    OR tenant_id = current_setting('app.tenant_id', true)::integer);
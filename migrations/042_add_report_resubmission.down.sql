@@ -0,0 +1,3 @@
+-- Rollback report resubmission
+DROP TABLE IF EXISTS report_rejections;
+ALTER TABLE weekly_reports DROP COLUMN IF EXISTS resubmissions;
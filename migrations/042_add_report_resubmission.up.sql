@@ -0,0 +1,22 @@
+-- Rejected reports go back to draft for their driver to fix and resubmit.
+-- Every rejection is kept, with who rejected the report and why.
+
+ALTER TABLE weekly_reports ADD COLUMN resubmissions INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE report_rejections (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    report_id INTEGER NOT NULL REFERENCES weekly_reports(id) ON DELETE CASCADE,
+    rejected_by_id INTEGER NOT NULL REFERENCES users(id),
+    reason TEXT NOT NULL DEFAULT '',
+    rejected_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_report_rejections_tenant_id ON report_rejections(tenant_id);
+CREATE INDEX idx_report_rejections_report_id ON report_rejections(report_id);
+
+ALTER TABLE report_rejections ENABLE ROW LEVEL SECURITY;
+ALTER TABLE report_rejections FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON report_rejections USING (
+    NULLIF(current_setting('app.tenant_id', true), '') IS NULL
+    OR tenant_id = current_setting('app.tenant_id', true)::integer);
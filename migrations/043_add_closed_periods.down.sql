@@ -0,0 +1,2 @@
+-- Rollback closed periods
+DROP TABLE IF EXISTS closed_periods;
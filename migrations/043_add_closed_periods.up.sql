@@ -0,0 +1,17 @@
+-- Months closed for the books: reports, expenses and deposits dated within
+-- a closed month can no longer be changed until an admin reopens it.
+
+CREATE TABLE closed_periods (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    month DATE NOT NULL, -- First day of the month
+    closed_by_id INTEGER NOT NULL REFERENCES users(id),
+    closed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    UNIQUE (tenant_id, month)
+);
+
+ALTER TABLE closed_periods ENABLE ROW LEVEL SECURITY;
+ALTER TABLE closed_periods FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON closed_periods USING (
+    NULLIF(current_setting('app.tenant_id', true), '') IS NULL
+    OR tenant_id = current_setting('app.tenant_id', true)::integer);
@@ -0,0 +1,2 @@
+-- Rollback budgets
+DROP TABLE IF EXISTS budgets;
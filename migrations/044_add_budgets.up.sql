@@ -0,0 +1,20 @@
+-- Monthly budgets per expense category, fleet-wide or for one taxi
+
+CREATE TABLE budgets (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    category VARCHAR(50) NOT NULL,
+    taxi_id INTEGER REFERENCES taxis(id) ON DELETE CASCADE, -- NULL: the whole fleet
+    amount DECIMAL(10, 2) NOT NULL CHECK (amount > 0), -- Per month
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- One budget per category and taxi, and one fleet-wide budget per category
+CREATE UNIQUE INDEX idx_budgets_scope ON budgets(tenant_id, category, COALESCE(taxi_id, 0));
+
+ALTER TABLE budgets ENABLE ROW LEVEL SECURITY;
+ALTER TABLE budgets FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON budgets USING (
+    NULLIF(current_setting('app.tenant_id', true), '') IS NULL
+    OR tenant_id = current_setting('app.tenant_id', true)::integer);
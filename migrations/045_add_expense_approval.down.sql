@@ -0,0 +1,5 @@
+-- Rollback expense approval
+DROP INDEX IF EXISTS idx_expenses_pending;
+ALTER TABLE expenses DROP COLUMN IF EXISTS approved_by_id;
+ALTER TABLE expenses DROP COLUMN IF EXISTS approved_at;
+ALTER TABLE expenses DROP COLUMN IF EXISTS status;
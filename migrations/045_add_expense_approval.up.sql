@@ -0,0 +1,8 @@
+-- Expenses above the tenant's approval threshold wait for an owner's
+-- approval before they count towards totals.
+
+ALTER TABLE expenses ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'approved';
+ALTER TABLE expenses ADD COLUMN approved_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE expenses ADD COLUMN approved_by_id INTEGER REFERENCES users(id);
+
+CREATE INDEX idx_expenses_pending ON expenses(tenant_id) WHERE status = 'pending_approval';
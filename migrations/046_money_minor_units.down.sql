@@ -0,0 +1,32 @@
+-- Rollback money in minor units
+ALTER TABLE weekly_reports
+    ALTER COLUMN earnings TYPE DECIMAL(10, 2) USING earnings / 100.0,
+    ALTER COLUMN total_expenses TYPE DECIMAL(10, 2) USING total_expenses / 100.0,
+    ALTER COLUMN total_adjustments TYPE DECIMAL(10, 2) USING total_adjustments / 100.0,
+    ALTER COLUMN driver_share TYPE DECIMAL(10, 2) USING driver_share / 100.0,
+    ALTER COLUMN owner_share TYPE DECIMAL(10, 2) USING owner_share / 100.0,
+    ALTER COLUMN ledger_offset TYPE DECIMAL(10, 2) USING ledger_offset / 100.0;
+ALTER TABLE expenses
+    ALTER COLUMN amount TYPE DECIMAL(10, 2) USING amount / 100.0;
+ALTER TABLE bank_deposits
+    ALTER COLUMN amount TYPE DECIMAL(10, 2) USING amount / 100.0;
+ALTER TABLE maintenance_logs
+    ALTER COLUMN cost TYPE DECIMAL(10, 2) USING cost / 100.0;
+ALTER TABLE parts
+    ALTER COLUMN unit_cost TYPE DECIMAL(10, 2) USING unit_cost / 100.0;
+ALTER TABLE stock_movements
+    ALTER COLUMN unit_cost TYPE DECIMAL(10, 2) USING unit_cost / 100.0;
+ALTER TABLE driver_ledger_entries
+    ALTER COLUMN amount TYPE DECIMAL(10, 2) USING amount / 100.0;
+ALTER TABLE report_adjustments
+    ALTER COLUMN amount TYPE DECIMAL(10, 2) USING amount / 100.0;
+ALTER TABLE fines
+    ALTER COLUMN amount TYPE DECIMAL(10, 2) USING amount / 100.0;
+ALTER TABLE trips
+    ALTER COLUMN fare TYPE DECIMAL(10, 2) USING fare / 100.0;
+ALTER TABLE platform_earnings
+    ALTER COLUMN amount TYPE DECIMAL(10, 2) USING amount / 100.0;
+ALTER TABLE fuel_card_transactions
+    ALTER COLUMN amount TYPE DECIMAL(10, 2) USING amount / 100.0;
+ALTER TABLE budgets
+    ALTER COLUMN amount TYPE DECIMAL(10, 2) USING amount / 100.0;
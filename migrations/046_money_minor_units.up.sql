@@ -0,0 +1,46 @@
+-- Money is stored as integer minor units (cents) instead of DECIMAL, so the
+-- application sums and splits it exactly. Existing amounts are converted.
+
+ALTER TABLE weekly_reports
+    ALTER COLUMN earnings TYPE BIGINT USING ROUND(earnings * 100),
+    ALTER COLUMN total_expenses TYPE BIGINT USING ROUND(total_expenses * 100),
+    ALTER COLUMN total_adjustments TYPE BIGINT USING ROUND(total_adjustments * 100),
+    ALTER COLUMN driver_share TYPE BIGINT USING ROUND(driver_share * 100),
+    ALTER COLUMN owner_share TYPE BIGINT USING ROUND(owner_share * 100),
+    ALTER COLUMN ledger_offset TYPE BIGINT USING ROUND(ledger_offset * 100);
+
+ALTER TABLE expenses
+    ALTER COLUMN amount TYPE BIGINT USING ROUND(amount * 100);
+
+ALTER TABLE bank_deposits
+    ALTER COLUMN amount TYPE BIGINT USING ROUND(amount * 100);
+
+ALTER TABLE maintenance_logs
+    ALTER COLUMN cost TYPE BIGINT USING ROUND(cost * 100);
+
+ALTER TABLE parts
+    ALTER COLUMN unit_cost TYPE BIGINT USING ROUND(unit_cost * 100);
+
+ALTER TABLE stock_movements
+    ALTER COLUMN unit_cost TYPE BIGINT USING ROUND(unit_cost * 100);
+
+ALTER TABLE driver_ledger_entries
+    ALTER COLUMN amount TYPE BIGINT USING ROUND(amount * 100);
+
+ALTER TABLE report_adjustments
+    ALTER COLUMN amount TYPE BIGINT USING ROUND(amount * 100);
+
+ALTER TABLE fines
+    ALTER COLUMN amount TYPE BIGINT USING ROUND(amount * 100);
+
+ALTER TABLE trips
+    ALTER COLUMN fare TYPE BIGINT USING ROUND(fare * 100);
+
+ALTER TABLE platform_earnings
+    ALTER COLUMN amount TYPE BIGINT USING ROUND(amount * 100);
+
+ALTER TABLE fuel_card_transactions
+    ALTER COLUMN amount TYPE BIGINT USING ROUND(amount * 100);
+
+ALTER TABLE budgets
+    ALTER COLUMN amount TYPE BIGINT USING ROUND(amount * 100);
@@ -0,0 +1,2 @@
+-- Rollback the API key rate limit of plans
+ALTER TABLE plans DROP COLUMN IF EXISTS api_key_requests_per_hour;
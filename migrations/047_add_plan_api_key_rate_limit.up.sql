@@ -0,0 +1,3 @@
+-- Requests per hour each API key of a plan's tenants may make; NULL is
+-- unlimited
+ALTER TABLE plans ADD COLUMN api_key_requests_per_hour INTEGER;
@@ -0,0 +1,5 @@
+-- Rollback the antivirus scan status of report attachments
+DROP INDEX IF EXISTS idx_report_attachments_scan_pending;
+ALTER TABLE report_attachments DROP COLUMN IF EXISTS scanned_at;
+ALTER TABLE report_attachments DROP COLUMN IF EXISTS scan_threat;
+ALTER TABLE report_attachments DROP COLUMN IF EXISTS scan_status;
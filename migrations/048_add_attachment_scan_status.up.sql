@@ -0,0 +1,7 @@
+-- Antivirus scan status of report attachments; files uploaded before
+-- scanning existed are not_scanned
+ALTER TABLE report_attachments ADD COLUMN scan_status VARCHAR(20) NOT NULL DEFAULT 'not_scanned';
+ALTER TABLE report_attachments ADD COLUMN scan_threat VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE report_attachments ADD COLUMN scanned_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_report_attachments_scan_pending ON report_attachments (created_at) WHERE scan_status = 'pending';
@@ -0,0 +1,2 @@
+-- Rollback export bundles
+DROP TABLE IF EXISTS export_bundles;
@@ -0,0 +1,25 @@
+-- ZIP bundles of a period's exports and files, generated in the background
+
+CREATE TABLE export_bundles (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    requested_by_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, ready or failed
+    error TEXT,
+    storage_path VARCHAR(255), -- Key of the archive in storage once ready
+    size BIGINT NOT NULL DEFAULT 0,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_export_bundles_tenant_id ON export_bundles(tenant_id);
+CREATE INDEX idx_export_bundles_expires_at ON export_bundles(expires_at);
+
+ALTER TABLE export_bundles ENABLE ROW LEVEL SECURITY;
+ALTER TABLE export_bundles FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON export_bundles USING (
+    NULLIF(current_setting('app.tenant_id', true), '') IS NULL
+    OR tenant_id = current_setting('app.tenant_id', true)::integer);